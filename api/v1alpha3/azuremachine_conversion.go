@@ -52,8 +52,13 @@ func (src *AzureMachine) ConvertTo(dstRaw conversion.Hub) error { // nolint
 	}
 
 	dst.Spec.SubnetName = restored.Spec.SubnetName
+	dst.Spec.VMExtensions = restored.Spec.VMExtensions
+	dst.Spec.NodeDiagnostics = restored.Spec.NodeDiagnostics
+	dst.Spec.Diagnostics = restored.Spec.Diagnostics
 
 	dst.Status.LongRunningOperationStates = restored.Status.LongRunningOperationStates
+	dst.Status.VMExtensions = restored.Status.VMExtensions
+	dst.Status.NodeDiagnosticsRef = restored.Status.NodeDiagnosticsRef
 
 	return nil
 }
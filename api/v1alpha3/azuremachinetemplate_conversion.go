@@ -52,6 +52,9 @@ func (src *AzureMachineTemplate) ConvertTo(dstRaw conversion.Hub) error { // nol
 	}
 
 	dst.Spec.Template.Spec.SubnetName = restored.Spec.Template.Spec.SubnetName
+	dst.Spec.Template.Spec.VMExtensions = restored.Spec.Template.Spec.VMExtensions
+	dst.Spec.Template.Spec.NodeDiagnostics = restored.Spec.Template.Spec.NodeDiagnostics
+	dst.Spec.Template.Spec.Diagnostics = restored.Spec.Template.Spec.Diagnostics
 	dst.Spec.Template.ObjectMeta = restored.Spec.Template.ObjectMeta
 
 	return nil
@@ -38,6 +38,9 @@ func (src *AzureMachineTemplate) ConvertTo(dstRaw conversion.Hub) error { // nol
 	}
 
 	dst.Spec.Template.ObjectMeta = restored.Spec.Template.ObjectMeta
+	dst.Spec.Template.Spec.VMExtensions = restored.Spec.Template.Spec.VMExtensions
+	dst.Spec.Template.Spec.NodeDiagnostics = restored.Spec.Template.Spec.NodeDiagnostics
+	dst.Spec.Template.Spec.Diagnostics = restored.Spec.Template.Spec.Diagnostics
 
 	return nil
 }
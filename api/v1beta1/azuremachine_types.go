@@ -28,8 +28,21 @@ const (
 	// MachineFinalizer allows ReconcileAzureMachine to clean up Azure resources associated with AzureMachine before
 	// removing it from the apiserver.
 	MachineFinalizer = "azuremachine.infrastructure.cluster.x-k8s.io"
+
+	// InPlaceUpdateAnnotation opts an AzureMachine in to updating the VM size, data disks and identities of its VM in
+	// place when they change, instead of treating these fields as immutable. The only supported value is "true".
+	InPlaceUpdateAnnotation = "azuremachine.infrastructure.cluster.x-k8s.io/in-place-update"
+
+	// DeallocatedForResizeAnnotation is set on an AzureMachine while its VM is deallocated to be resized to a size that
+	// is not available on its current hardware cluster.
+	DeallocatedForResizeAnnotation = "azuremachine.infrastructure.cluster.x-k8s.io/deallocated-for-resize"
 )
 
+// InPlaceUpdateEnabled returns true if the AzureMachine opted in to in-place updates of its VM.
+func (m *AzureMachine) InPlaceUpdateEnabled() bool {
+	return m.Annotations[InPlaceUpdateAnnotation] == "true"
+}
+
 // AzureMachineSpec defines the desired state of AzureMachine.
 type AzureMachineSpec struct {
 	// ProviderID is the unique identifier as specified by the cloud provider.
@@ -114,6 +127,20 @@ type AzureMachineSpec struct {
 	// SubnetName selects the Subnet where the VM will be placed
 	// +optional
 	SubnetName string `json:"subnetName,omitempty"`
+
+	// VMExtensions specifies a list of extensions to be added to the virtual machine, in addition to the
+	// bootstrapping extension managed by CAPZ.
+	// +optional
+	VMExtensions []VMExtension `json:"vmExtensions,omitempty"`
+
+	// NodeDiagnostics enables the collection of node diagnostics when the virtual machine fails to bootstrap.
+	// +optional
+	NodeDiagnostics *NodeDiagnostics `json:"nodeDiagnostics,omitempty"`
+
+	// Diagnostics specifies the diagnostics settings for a virtual machine.
+	// If not specified then Boot diagnostics (Managed) will be enabled.
+	// +optional
+	Diagnostics *Diagnostics `json:"diagnostics,omitempty"`
 }
 
 // SpotVMOptions defines the options relevant to running the Machine on Spot VMs.
@@ -179,6 +206,15 @@ type AzureMachineStatus struct {
 	// +optional
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
 
+	// VMExtensions reports the provisioning state of each extension installed on the virtual machine.
+	// +optional
+	VMExtensions []VMExtensionStatus `json:"vmExtensions,omitempty"`
+
+	// NodeDiagnosticsRef is a reference to the Secret containing the node diagnostics collected after a bootstrap
+	// failure. It is only set when the node diagnostics output is Secret.
+	// +optional
+	NodeDiagnosticsRef *corev1.LocalObjectReference `json:"nodeDiagnosticsRef,omitempty"`
+
 	// LongRunningOperationStates saves the states for Azure long-running operations so they can be continued on the
 	// next reconciliation loop.
 	// +optional
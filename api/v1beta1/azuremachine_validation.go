@@ -19,6 +19,7 @@ package v1beta1
 import (
 	"encoding/base64"
 	"fmt"
+	"reflect"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
 	"github.com/google/uuid"
@@ -54,6 +55,14 @@ func ValidateAzureMachineSpec(spec AzureMachineSpec) field.ErrorList {
 		allErrs = append(allErrs, errs...)
 	}
 
+	if errs := ValidateVMExtensions(spec.VMExtensions, field.NewPath("vmExtensions")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := ValidateDiagnostics(spec.Diagnostics, field.NewPath("diagnostics")); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
 	return allErrs
 }
 
@@ -148,6 +157,67 @@ func ValidateDataDisks(dataDisks []DataDisk, fieldPath *field.Path) field.ErrorL
 	return allErrs
 }
 
+// ValidateVMExtensions validates a list of VM extensions.
+func ValidateVMExtensions(extensions []VMExtension, fieldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	nameSet := make(map[string]struct{})
+	for i, extension := range extensions {
+		idxPath := fieldPath.Index(i)
+		if extension.Name == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), "the extension name cannot be empty"))
+		} else if _, ok := nameSet[extension.Name]; ok {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), extension.Name))
+		} else {
+			nameSet[extension.Name] = struct{}{}
+		}
+
+		if extension.Publisher == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("publisher"), "the extension publisher cannot be empty"))
+		}
+		if extension.Type == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("type"), "the extension type cannot be empty"))
+		}
+		if extension.Version == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("version"), "the extension version cannot be empty"))
+		}
+		if extension.ProtectedSettingsRef != nil && extension.ProtectedSettingsRef.Name == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("protectedSettingsRef", "name"), "the protected settings secret name cannot be empty"))
+		}
+	}
+	return allErrs
+}
+
+// ValidateDiagnostics validates the Diagnostic spec.
+func ValidateDiagnostics(diagnostics *Diagnostics, fieldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if diagnostics == nil || diagnostics.Boot == nil {
+		return allErrs
+	}
+
+	bootPath := fieldPath.Child("boot")
+	switch diagnostics.Boot.StorageAccountType {
+	case UserManagedDiagnosticsStorage:
+		if diagnostics.Boot.UserManaged == nil {
+			allErrs = append(allErrs, field.Required(bootPath.Child("userManaged"),
+				fmt.Sprintf("userManaged must be specified when storageAccountType is '%s'", UserManagedDiagnosticsStorage)))
+		} else if diagnostics.Boot.UserManaged.StorageAccountURI == "" {
+			allErrs = append(allErrs, field.Required(bootPath.Child("userManaged", "storageAccountURI"),
+				fmt.Sprintf("storageAccountURI cannot be empty when storageAccountType is '%s'", UserManagedDiagnosticsStorage)))
+		}
+	case ManagedDiagnosticsStorage, DisabledDiagnosticsStorage:
+		if diagnostics.Boot.UserManaged != nil {
+			allErrs = append(allErrs, field.Invalid(bootPath.Child("userManaged"), diagnostics.Boot.UserManaged,
+				fmt.Sprintf("userManaged must not be specified when storageAccountType is '%s'", diagnostics.Boot.StorageAccountType)))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(bootPath.Child("storageAccountType"), diagnostics.Boot.StorageAccountType,
+			[]string{string(ManagedDiagnosticsStorage), string(UserManagedDiagnosticsStorage), string(DisabledDiagnosticsStorage)}))
+	}
+
+	return allErrs
+}
+
 // ValidateOSDisk validates the OSDisk spec.
 func ValidateOSDisk(osDisk OSDisk, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
@@ -235,6 +305,29 @@ func ValidateDataDisksUpdate(oldDataDisks, newDataDisks []DataDisk, fieldPath *f
 	return allErrs
 }
 
+// ValidateDataDisksInPlaceUpdate validates in-place updates to data disks, which only allow adding new data disks.
+func ValidateDataDisksInPlaceUpdate(oldDataDisks, newDataDisks []DataDisk, fieldPath *field.Path) field.ErrorList {
+	allErrs := ValidateDataDisks(newDataDisks, fieldPath)
+
+	newDisks := make(map[string]DataDisk)
+	for _, disk := range newDataDisks {
+		newDisks[disk.NameSuffix] = disk
+	}
+
+	for _, oldDisk := range oldDataDisks {
+		newDisk, ok := newDisks[oldDisk.NameSuffix]
+		if !ok {
+			allErrs = append(allErrs, field.Invalid(fieldPath, newDataDisks, fmt.Sprintf("removing data disk %s after machine creation is not allowed", oldDisk.NameSuffix)))
+			continue
+		}
+		if !reflect.DeepEqual(oldDisk, newDisk) {
+			allErrs = append(allErrs, field.Invalid(fieldPath, newDataDisks, fmt.Sprintf("modifying data disk %s after machine creation is not allowed", oldDisk.NameSuffix)))
+		}
+	}
+
+	return allErrs
+}
+
 func validateManagedDisksUpdate(old, new *ManagedDiskParameters, fieldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	fieldErrMsg := "changing managed disk options after machine creation is not allowed"
@@ -28,6 +28,7 @@ import (
 	"github.com/google/uuid"
 	. "github.com/onsi/gomega"
 	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
@@ -399,6 +400,169 @@ func TestAzureMachine_ValidateDataDisks(t *testing.T) {
 	}
 }
 
+func TestAzureMachine_ValidateVMExtensions(t *testing.T) {
+	g := NewWithT(t)
+
+	testcases := []struct {
+		name       string
+		extensions []VMExtension
+		wantErr    bool
+	}{
+		{
+			name:       "valid nil extensions",
+			extensions: nil,
+			wantErr:    false,
+		},
+		{
+			name: "valid extensions",
+			extensions: []VMExtension{
+				{
+					Name:      "monitoring-agent",
+					Publisher: "Microsoft.Azure.Monitor",
+					Type:      "AzureMonitorLinuxAgent",
+					Version:   "1.0",
+					Settings:  map[string]string{"workspaceId": "1234"},
+				},
+				{
+					Name:                 "security-agent",
+					Publisher:            "Microsoft.Azure.Security",
+					Type:                 "AzureSecurityLinuxAgent",
+					Version:              "2.0",
+					ProtectedSettingsRef: &corev1.LocalObjectReference{Name: "security-agent-settings"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "duplicate names",
+			extensions: []VMExtension{
+				{
+					Name:      "agent",
+					Publisher: "Microsoft.Azure.Monitor",
+					Type:      "AzureMonitorLinuxAgent",
+					Version:   "1.0",
+				},
+				{
+					Name:      "agent",
+					Publisher: "Microsoft.Azure.Security",
+					Type:      "AzureSecurityLinuxAgent",
+					Version:   "2.0",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing publisher, type and version",
+			extensions: []VMExtension{
+				{
+					Name: "agent",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty protected settings secret name",
+			extensions: []VMExtension{
+				{
+					Name:                 "agent",
+					Publisher:            "Microsoft.Azure.Monitor",
+					Type:                 "AzureMonitorLinuxAgent",
+					Version:              "1.0",
+					ProtectedSettingsRef: &corev1.LocalObjectReference{},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateVMExtensions(test.extensions, field.NewPath("vmExtensions"))
+			if test.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
+func TestAzureMachine_ValidateDiagnostics(t *testing.T) {
+	g := NewWithT(t)
+
+	testcases := []struct {
+		name        string
+		diagnostics *Diagnostics
+		wantErr     bool
+	}{
+		{
+			name:        "nil diagnostics",
+			diagnostics: nil,
+			wantErr:     false,
+		},
+		{
+			name:        "nil boot diagnostics",
+			diagnostics: &Diagnostics{},
+			wantErr:     false,
+		},
+		{
+			name:        "managed boot diagnostics",
+			diagnostics: &Diagnostics{Boot: &BootDiagnostics{StorageAccountType: ManagedDiagnosticsStorage}},
+			wantErr:     false,
+		},
+		{
+			name:        "disabled boot diagnostics",
+			diagnostics: &Diagnostics{Boot: &BootDiagnostics{StorageAccountType: DisabledDiagnosticsStorage}},
+			wantErr:     false,
+		},
+		{
+			name: "user managed boot diagnostics with storage account URI",
+			diagnostics: &Diagnostics{Boot: &BootDiagnostics{
+				StorageAccountType: UserManagedDiagnosticsStorage,
+				UserManaged:        &UserManagedBootDiagnostics{StorageAccountURI: "https://fake.blob.core.windows.net/"},
+			}},
+			wantErr: false,
+		},
+		{
+			name:        "user managed boot diagnostics without user managed settings",
+			diagnostics: &Diagnostics{Boot: &BootDiagnostics{StorageAccountType: UserManagedDiagnosticsStorage}},
+			wantErr:     true,
+		},
+		{
+			name: "user managed boot diagnostics with empty storage account URI",
+			diagnostics: &Diagnostics{Boot: &BootDiagnostics{
+				StorageAccountType: UserManagedDiagnosticsStorage,
+				UserManaged:        &UserManagedBootDiagnostics{},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "managed boot diagnostics with user managed settings",
+			diagnostics: &Diagnostics{Boot: &BootDiagnostics{
+				StorageAccountType: ManagedDiagnosticsStorage,
+				UserManaged:        &UserManagedBootDiagnostics{StorageAccountURI: "https://fake.blob.core.windows.net/"},
+			}},
+			wantErr: true,
+		},
+		{
+			name:        "unsupported storage account type",
+			diagnostics: &Diagnostics{Boot: &BootDiagnostics{StorageAccountType: "Unknown"}},
+			wantErr:     true,
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateDiagnostics(test.diagnostics, field.NewPath("diagnostics"))
+			if test.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
+
 func TestAzureMachine_ValidateSystemAssignedIdentity(t *testing.T) {
 	g := NewWithT(t)
 
@@ -649,3 +813,65 @@ func TestAzureMachine_ValidateDataDisksUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestAzureMachine_ValidateDataDisksInPlaceUpdate(t *testing.T) {
+	g := NewWithT(t)
+
+	disk := func(nameSuffix string, lun int32, size int32) DataDisk {
+		return DataDisk{
+			NameSuffix:  nameSuffix,
+			DiskSizeGB:  size,
+			Lun:         to.Int32Ptr(lun),
+			CachingType: string(compute.PossibleCachingTypesValues()[0]),
+		}
+	}
+
+	tests := []struct {
+		name     string
+		disks    []DataDisk
+		oldDisks []DataDisk
+		wantErr  bool
+	}{
+		{
+			name:     "valid unchanged data disks",
+			disks:    []DataDisk{disk("my_disk", 0, 64)},
+			oldDisks: []DataDisk{disk("my_disk", 0, 64)},
+			wantErr:  false,
+		},
+		{
+			name:     "valid added data disk",
+			disks:    []DataDisk{disk("my_disk", 0, 64), disk("my_other_disk", 1, 128)},
+			oldDisks: []DataDisk{disk("my_disk", 0, 64)},
+			wantErr:  false,
+		},
+		{
+			name:     "invalid added data disk with a LUN in use",
+			disks:    []DataDisk{disk("my_disk", 0, 64), disk("my_other_disk", 0, 128)},
+			oldDisks: []DataDisk{disk("my_disk", 0, 64)},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid removed data disk",
+			disks:    []DataDisk{},
+			oldDisks: []DataDisk{disk("my_disk", 0, 64)},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid modified data disk",
+			disks:    []DataDisk{disk("my_disk", 0, 128)},
+			oldDisks: []DataDisk{disk("my_disk", 0, 64)},
+			wantErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateDataDisksInPlaceUpdate(test.oldDisks, test.disks, field.NewPath("dataDisks"))
+			if test.wantErr {
+				g.Expect(err).NotTo(HaveLen(0))
+			} else {
+				g.Expect(err).To(HaveLen(0))
+			}
+		})
+	}
+}
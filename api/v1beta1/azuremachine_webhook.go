@@ -59,25 +59,31 @@ func (m *AzureMachine) ValidateUpdate(oldRaw runtime.Object) error {
 		)
 	}
 
-	if !reflect.DeepEqual(m.Spec.Identity, old.Spec.Identity) {
-		allErrs = append(allErrs,
-			field.Invalid(field.NewPath("spec", "identity"),
-				m.Spec.Identity, "field is immutable"),
-		)
-	}
-
-	if !reflect.DeepEqual(m.Spec.UserAssignedIdentities, old.Spec.UserAssignedIdentities) {
-		allErrs = append(allErrs,
-			field.Invalid(field.NewPath("spec", "userAssignedIdentities"),
-				m.Spec.UserAssignedIdentities, "field is immutable"),
-		)
-	}
-
-	if !reflect.DeepEqual(m.Spec.RoleAssignmentName, old.Spec.RoleAssignmentName) {
-		allErrs = append(allErrs,
-			field.Invalid(field.NewPath("spec", "roleAssignmentName"),
-				m.Spec.RoleAssignmentName, "field is immutable"),
-		)
+	if m.InPlaceUpdateEnabled() {
+		// Identities can be updated in place, but the role assignment name cannot change once set.
+		allErrs = append(allErrs, ValidateSystemAssignedIdentity(m.Spec.Identity, old.Spec.RoleAssignmentName, m.Spec.RoleAssignmentName, field.NewPath("spec", "roleAssignmentName"))...)
+		allErrs = append(allErrs, ValidateUserAssignedIdentity(m.Spec.Identity, m.Spec.UserAssignedIdentities, field.NewPath("spec", "userAssignedIdentities"))...)
+	} else {
+		if !reflect.DeepEqual(m.Spec.Identity, old.Spec.Identity) {
+			allErrs = append(allErrs,
+				field.Invalid(field.NewPath("spec", "identity"),
+					m.Spec.Identity, "field is immutable"),
+			)
+		}
+
+		if !reflect.DeepEqual(m.Spec.UserAssignedIdentities, old.Spec.UserAssignedIdentities) {
+			allErrs = append(allErrs,
+				field.Invalid(field.NewPath("spec", "userAssignedIdentities"),
+					m.Spec.UserAssignedIdentities, "field is immutable"),
+			)
+		}
+
+		if !reflect.DeepEqual(m.Spec.RoleAssignmentName, old.Spec.RoleAssignmentName) {
+			allErrs = append(allErrs,
+				field.Invalid(field.NewPath("spec", "roleAssignmentName"),
+					m.Spec.RoleAssignmentName, "field is immutable"),
+			)
+		}
 	}
 
 	if !reflect.DeepEqual(m.Spec.OSDisk, old.Spec.OSDisk) {
@@ -87,7 +93,10 @@ func (m *AzureMachine) ValidateUpdate(oldRaw runtime.Object) error {
 		)
 	}
 
-	if !reflect.DeepEqual(m.Spec.DataDisks, old.Spec.DataDisks) {
+	if m.InPlaceUpdateEnabled() {
+		// Data disks can be added in place, but existing data disks cannot be changed or removed.
+		allErrs = append(allErrs, ValidateDataDisksInPlaceUpdate(old.Spec.DataDisks, m.Spec.DataDisks, field.NewPath("spec", "dataDisks"))...)
+	} else if !reflect.DeepEqual(m.Spec.DataDisks, old.Spec.DataDisks) {
 		allErrs = append(allErrs,
 			field.Invalid(field.NewPath("spec", "dataDisks"),
 				m.Spec.DataDisks, "field is immutable"),
@@ -136,6 +145,20 @@ func (m *AzureMachine) ValidateUpdate(oldRaw runtime.Object) error {
 		)
 	}
 
+	if !reflect.DeepEqual(m.Spec.VMExtensions, old.Spec.VMExtensions) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "vmExtensions"),
+				m.Spec.VMExtensions, "field is immutable"),
+		)
+	}
+
+	if !reflect.DeepEqual(m.Spec.Diagnostics, old.Spec.Diagnostics) {
+		allErrs = append(allErrs,
+			field.Invalid(field.NewPath("spec", "diagnostics"),
+				m.Spec.Diagnostics, "field is immutable"),
+		)
+	}
+
 	if len(allErrs) == 0 {
 		return nil
 	}
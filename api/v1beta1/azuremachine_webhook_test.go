@@ -22,6 +22,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 )
 
@@ -509,6 +510,115 @@ func TestAzureMachine_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "invalidTest: azuremachine.spec.VMExtensions is immutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					VMExtensions: []VMExtension{{Name: "agent", Publisher: "publisher", Type: "type", Version: "1.0"}},
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					VMExtensions: []VMExtension{{Name: "agent", Publisher: "publisher", Type: "type", Version: "2.0"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalidTest: azuremachine.spec.Diagnostics is immutable",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					Diagnostics: &Diagnostics{Boot: &BootDiagnostics{StorageAccountType: ManagedDiagnosticsStorage}},
+				},
+			},
+			newMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					Diagnostics: &Diagnostics{Boot: &BootDiagnostics{StorageAccountType: DisabledDiagnosticsStorage}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "validTest: azuremachine.spec.Identity can be updated in place",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					Identity: VMIdentityNone,
+				},
+			},
+			newMachine: &AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{InPlaceUpdateAnnotation: "true"},
+				},
+				Spec: AzureMachineSpec{
+					Identity: VMIdentityUserAssigned,
+					UserAssignedIdentities: []UserAssignedIdentity{
+						{ProviderID: "azure:///subscriptions/123/resourcegroups/456/providers/Microsoft.ManagedIdentity/userAssignedIdentities/id1"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalidTest: azuremachine.spec.UserAssignedIdentities is required when updating identity in place",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					Identity: VMIdentityNone,
+				},
+			},
+			newMachine: &AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{InPlaceUpdateAnnotation: "true"},
+				},
+				Spec: AzureMachineSpec{
+					Identity: VMIdentityUserAssigned,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "validTest: azuremachine.spec.DataDisks can be added in place",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					DataDisks: []DataDisk{
+						{NameSuffix: "disk1", DiskSizeGB: 128, Lun: pointer.Int32(0), CachingType: "None"},
+					},
+				},
+			},
+			newMachine: &AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{InPlaceUpdateAnnotation: "true"},
+				},
+				Spec: AzureMachineSpec{
+					DataDisks: []DataDisk{
+						{NameSuffix: "disk1", DiskSizeGB: 128, Lun: pointer.Int32(0), CachingType: "None"},
+						{NameSuffix: "disk2", DiskSizeGB: 64, Lun: pointer.Int32(1), CachingType: "None"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalidTest: azuremachine.spec.DataDisks cannot be removed in place",
+			oldMachine: &AzureMachine{
+				Spec: AzureMachineSpec{
+					DataDisks: []DataDisk{
+						{NameSuffix: "disk1", DiskSizeGB: 128, Lun: pointer.Int32(0), CachingType: "None"},
+						{NameSuffix: "disk2", DiskSizeGB: 64, Lun: pointer.Int32(1), CachingType: "None"},
+					},
+				},
+			},
+			newMachine: &AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{InPlaceUpdateAnnotation: "true"},
+				},
+				Spec: AzureMachineSpec{
+					DataDisks: []DataDisk{
+						{NameSuffix: "disk1", DiskSizeGB: 128, Lun: pointer.Int32(0), CachingType: "None"},
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
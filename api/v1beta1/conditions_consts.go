@@ -48,6 +48,25 @@ const (
 	BootstrapInProgressReason = "BootstrapInProgress"
 	// BootstrapFailedReason is used to indicate the bootstrap process ran into an error.
 	BootstrapFailedReason = "BootstrapFailed"
+	// NodeDiagnosticsCollectedCondition reports whether node diagnostics were collected after a bootstrap failure.
+	NodeDiagnosticsCollectedCondition clusterv1.ConditionType = "NodeDiagnosticsCollected"
+	// NodeDiagnosticsCollectionFailedReason is used when node diagnostics could not be collected from the machine.
+	NodeDiagnosticsCollectionFailedReason = "NodeDiagnosticsCollectionFailed"
+	// SerialConsoleLogRecordedCondition reports whether the serial console log of a failed machine was recorded in an event.
+	SerialConsoleLogRecordedCondition clusterv1.ConditionType = "SerialConsoleLogRecorded"
+	// SerialConsoleLogUnavailableReason is used when the serial console log of a failed machine could not be retrieved.
+	SerialConsoleLogUnavailableReason = "SerialConsoleLogUnavailable"
+)
+
+// AzureMachinePoolMachine Conditions and Reasons.
+const (
+	// ScheduledEventsHandledCondition reports whether the node of the machine has been drained ahead of the Terminate,
+	// Preempt and Reboot scheduled events of its VM, and the events approved.
+	ScheduledEventsHandledCondition clusterv1.ConditionType = "ScheduledEventsHandled"
+	// ScheduledEventDrainingReason is used while the node is drained ahead of a scheduled event.
+	ScheduledEventDrainingReason = "ScheduledEventDraining"
+	// ScheduledEventDrainFailedReason is used when the node could not be drained ahead of a scheduled event.
+	ScheduledEventDrainFailedReason = "ScheduledEventDrainFailed"
 )
 
 // AzureMachinePool Conditions and Reasons.
@@ -82,6 +101,32 @@ const (
 	ManagedClusterRunningCondition clusterv1.ConditionType = "ManagedClusterRunning"
 	// AgentPoolsReadyCondition means the AKS agent pools exist and are ready to be used.
 	AgentPoolsReadyCondition clusterv1.ConditionType = "AgentPoolsReady"
+
+	// ManagedClusterUpgradedCondition means the AKS control plane runs the desired Kubernetes version.
+	ManagedClusterUpgradedCondition clusterv1.ConditionType = "ManagedClusterUpgraded"
+	// ManagedClusterUpgradingReason is used while the AKS control plane is upgraded to the desired Kubernetes version.
+	ManagedClusterUpgradingReason = "ManagedClusterUpgrading"
+	// ManagedClusterUpgradeUnavailableReason is used when AKS does not offer the desired Kubernetes version as an
+	// upgrade of the current version of the control plane.
+	ManagedClusterUpgradeUnavailableReason = "ManagedClusterUpgradeUnavailable"
+
+	// ManagedClusterAdoptedCondition means an existing AKS cluster that was not created by CAPZ matches its
+	// AzureManagedControlPlane and is managed by CAPZ.
+	ManagedClusterAdoptedCondition clusterv1.ConditionType = "ManagedClusterAdopted"
+	// ManagedClusterAdoptionPendingReason is used while the adoption of an existing AKS cluster waits for the user's
+	// confirmation.
+	ManagedClusterAdoptionPendingReason = "ManagedClusterAdoptionPending"
+	// ManagedClusterAdoptionMismatchReason is used when an existing AKS cluster does not match its
+	// AzureManagedControlPlane or AzureManagedMachinePools in a way that CAPZ cannot reconcile.
+	ManagedClusterAdoptionMismatchReason = "ManagedClusterAdoptionMismatch"
+
+	// AgentPoolUpgradedCondition means the AKS agent pool runs the desired Kubernetes version.
+	AgentPoolUpgradedCondition clusterv1.ConditionType = "AgentPoolUpgraded"
+	// AgentPoolUpgradingReason is used while the nodes of the AKS agent pool are upgraded to the desired Kubernetes version.
+	AgentPoolUpgradingReason = "AgentPoolUpgrading"
+	// WaitingForControlPlaneUpgradeReason is used while the AKS agent pool waits for the control plane to be upgraded
+	// to its desired Kubernetes version.
+	WaitingForControlPlaneUpgradeReason = "WaitingForControlPlaneUpgrade"
 )
 
 // Azure Services Conditions and Reasons.
@@ -18,7 +18,9 @@ package v1beta1
 
 import (
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
@@ -40,6 +42,8 @@ const (
 	PutFuture string = "PUT"
 	// DeleteFuture is a future that was derived from a DELETE request.
 	DeleteFuture string = "DELETE"
+	// PostFuture is a future that was derived from a POST request, such as running a command on a VM.
+	PostFuture string = "POST"
 )
 
 // Future contains the data needed for an Azure long-running operation to continue across reconcile loops.
@@ -412,6 +416,153 @@ const (
 	VMIdentityUserAssigned VMIdentity = "UserAssigned"
 )
 
+// OrchestrationModeType represents the orchestration mode for a Virtual Machine Scale Set backing an AzureMachinePool.
+// +kubebuilder:validation:Enum=Flexible;Uniform
+type OrchestrationModeType string
+
+const (
+	// FlexibleOrchestrationMode treats VMs as individual resources accessible by standard VM APIs.
+	FlexibleOrchestrationMode OrchestrationModeType = "Flexible"
+	// UniformOrchestrationMode treats VMs as identical instances accessible by the VMSS VM API.
+	UniformOrchestrationMode OrchestrationModeType = "Uniform"
+)
+
+// UpgradeMode specifies how changes to the model of a Virtual Machine Scale Set are applied to its instances.
+// +kubebuilder:validation:Enum=Manual;Rolling;Automatic
+type UpgradeMode string
+
+const (
+	// UpgradeModeManual leaves the rollout of model changes to the AzureMachinePool deployment strategy.
+	UpgradeModeManual UpgradeMode = "Manual"
+	// UpgradeModeRolling lets Azure upgrade the instances in batches, with an optional pause between batches.
+	UpgradeModeRolling UpgradeMode = "Rolling"
+	// UpgradeModeAutomatic lets Azure upgrade all the instances at the same time.
+	UpgradeModeAutomatic UpgradeMode = "Automatic"
+)
+
+// ModelDifference names a part of the model of a Virtual Machine Scale Set an instance differs in.
+// +kubebuilder:validation:Enum=Image;VMSize;Extensions;Tags;BootstrapData;Unknown
+type ModelDifference string
+
+const (
+	// ModelDifferenceImage means the instance runs a different image than the scale set model.
+	ModelDifferenceImage ModelDifference = "Image"
+	// ModelDifferenceVMSize means the instance has a different VM size than the scale set model.
+	ModelDifferenceVMSize ModelDifference = "VMSize"
+	// ModelDifferenceExtensions means the instance has different extensions, or extension versions, than the scale
+	// set model.
+	ModelDifferenceExtensions ModelDifference = "Extensions"
+	// ModelDifferenceTags means the instance has different tags than the scale set model.
+	ModelDifferenceTags ModelDifference = "Tags"
+	// ModelDifferenceBootstrapData means the instance was created from other bootstrap data than the one of the scale
+	// set model.
+	ModelDifferenceBootstrapData ModelDifference = "BootstrapData"
+	// ModelDifferenceUnknown means Azure reports the instance is not running the latest model while none of the
+	// compared parts differ, for example because of a part Azure does not return, such as the custom data.
+	ModelDifferenceUnknown ModelDifference = "Unknown"
+)
+
+// UpgradePolicy describes how Azure upgrades the instances of a Virtual Machine Scale Set.
+type UpgradePolicy struct {
+	// Mode specifies how changes to the scale set model are applied to the instances. With Manual, CAPZ rolls out
+	// changes using the AzureMachinePool deployment strategy. With Rolling or Automatic, the rollout is delegated to
+	// Azure and CAPZ does not replace instances running an older model.
+	// +kubebuilder:default=Manual
+	// +optional
+	Mode UpgradeMode `json:"mode,omitempty"`
+
+	// RollingUpgradePolicy configures the batches of a Rolling upgrade or of automatic OS image upgrades.
+	// +optional
+	RollingUpgradePolicy *RollingUpgradePolicy `json:"rollingUpgradePolicy,omitempty"`
+
+	// AutomaticOSUpgradePolicy configures automatic OS image upgrades of the instances.
+	// +optional
+	AutomaticOSUpgradePolicy *AutomaticOSUpgradePolicy `json:"automaticOSUpgradePolicy,omitempty"`
+}
+
+// RollingUpgradePolicy describes the batches of a rolling upgrade.
+type RollingUpgradePolicy struct {
+	// MaxBatchInstancePercent is the maximum percentage of instances upgraded simultaneously in one batch.
+	// Defaults to 20.
+	// +kubebuilder:validation:Minimum=5
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxBatchInstancePercent *int32 `json:"maxBatchInstancePercent,omitempty"`
+
+	// MaxUnhealthyInstancePercent is the maximum percentage of instances that can be unhealthy at the same time before
+	// the rolling upgrade aborts. Defaults to 20.
+	// +kubebuilder:validation:Minimum=5
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxUnhealthyInstancePercent *int32 `json:"maxUnhealthyInstancePercent,omitempty"`
+
+	// MaxUnhealthyUpgradedInstancePercent is the maximum percentage of upgraded instances that can be unhealthy before
+	// the rolling upgrade aborts. Defaults to 20.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxUnhealthyUpgradedInstancePercent *int32 `json:"maxUnhealthyUpgradedInstancePercent,omitempty"`
+
+	// PauseTimeBetweenBatches is the time to wait between upgrading two batches of instances. Defaults to 0.
+	// +optional
+	PauseTimeBetweenBatches *metav1.Duration `json:"pauseTimeBetweenBatches,omitempty"`
+
+	// PrioritizeUnhealthyInstances upgrades all unhealthy instances before any healthy instance.
+	// +optional
+	PrioritizeUnhealthyInstances *bool `json:"prioritizeUnhealthyInstances,omitempty"`
+}
+
+// AutomaticOSUpgradePolicy describes automatic OS image upgrades of the instances of a Virtual Machine Scale Set.
+type AutomaticOSUpgradePolicy struct {
+	// EnableAutomaticOSUpgrade rolls out newer versions of the OS image to the instances as they become available.
+	// This requires a Marketplace image with version "latest".
+	EnableAutomaticOSUpgrade bool `json:"enableAutomaticOSUpgrade"`
+
+	// DisableAutomaticRollback disables the rollback of the OS image when an upgrade fails.
+	// +optional
+	DisableAutomaticRollback *bool `json:"disableAutomaticRollback,omitempty"`
+}
+
+// AutomaticRepairsPolicy describes the automatic repair of unhealthy instances of a Virtual Machine Scale Set.
+type AutomaticRepairsPolicy struct {
+	// Enabled turns on the automatic repair of instances reported unhealthy by the application health extension.
+	Enabled bool `json:"enabled"`
+
+	// GracePeriod is the amount of time for which automatic repairs are suspended after a state change of an instance.
+	// It must be a whole number of minutes between 30m and 90m. Defaults to 30m.
+	// +optional
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+}
+
+// ApplicationHealthProtocol is the protocol used by the application health extension to probe an instance.
+// +kubebuilder:validation:Enum=http;https;tcp
+type ApplicationHealthProtocol string
+
+const (
+	// ApplicationHealthProtocolHTTP probes an HTTP endpoint; a 200 response marks the instance healthy.
+	ApplicationHealthProtocolHTTP ApplicationHealthProtocol = "http"
+	// ApplicationHealthProtocolHTTPS probes an HTTPS endpoint; a 200 response marks the instance healthy.
+	ApplicationHealthProtocolHTTPS ApplicationHealthProtocol = "https"
+	// ApplicationHealthProtocolTCP probes a TCP port; an established connection marks the instance healthy.
+	ApplicationHealthProtocolTCP ApplicationHealthProtocol = "tcp"
+)
+
+// ApplicationHealthProbe configures the application health extension, which reports the health of an instance from
+// a probe run inside the instance against localhost.
+type ApplicationHealthProbe struct {
+	// Protocol is the protocol used to probe the instance.
+	Protocol ApplicationHealthProtocol `json:"protocol"`
+
+	// Port is the port probed on localhost.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+
+	// RequestPath is the path of the HTTP or HTTPS request. It must not be set with the tcp protocol.
+	// +optional
+	RequestPath string `json:"requestPath,omitempty"`
+}
+
 // UserAssignedIdentity defines the user-assigned identities provided
 // by the user to be assigned to Azure resources.
 type UserAssignedIdentity struct {
@@ -428,7 +579,7 @@ const (
 )
 
 // IdentityType represents different types of identities.
-// +kubebuilder:validation:Enum=ServicePrincipal;ManualServicePrincipal;UserAssignedMSI
+// +kubebuilder:validation:Enum=ServicePrincipal;ManualServicePrincipal;UserAssignedMSI;WorkloadIdentity
 type IdentityType string
 
 const (
@@ -440,6 +591,9 @@ const (
 
 	// ManualServicePrincipal represents a manual service principal.
 	ManualServicePrincipal IdentityType = "ManualServicePrincipal"
+
+	// WorkloadIdentity represents an application whose federated credential trusts the service account of CAPZ.
+	WorkloadIdentity IdentityType = "WorkloadIdentity"
 )
 
 // OSDisk defines the operating system disk for a VM.
@@ -595,6 +749,108 @@ type SecurityProfile struct {
 	EncryptionAtHost *bool `json:"encryptionAtHost,omitempty"`
 }
 
+// VMExtension specifies the parameters for a custom VM extension.
+type VMExtension struct {
+	// Name is the name of the extension.
+	Name string `json:"name"`
+
+	// Publisher is the name of the extension handler publisher.
+	Publisher string `json:"publisher"`
+
+	// Type is the type of the extension handler, e.g. "CustomScript".
+	Type string `json:"type"`
+
+	// Version specifies the version of the extension handler.
+	Version string `json:"version"`
+
+	// Settings is the public configuration passed to the extension handler.
+	// +optional
+	Settings map[string]string `json:"settings,omitempty"`
+
+	// ProtectedSettingsRef is a reference to a Secret in the same namespace whose data is passed
+	// to the extension handler as protected settings. Protected settings are encrypted by Azure
+	// and are never returned by the API.
+	// +optional
+	ProtectedSettingsRef *corev1.LocalObjectReference `json:"protectedSettingsRef,omitempty"`
+}
+
+// VMExtensionStatus describes the observed state of a VM extension.
+type VMExtensionStatus struct {
+	// Name is the name of the extension.
+	Name string `json:"name"`
+
+	// ProvisioningState is the provisioning state of the extension.
+	// +optional
+	ProvisioningState ProvisioningState `json:"provisioningState,omitempty"`
+}
+
+// BootDiagnosticsStorageAccountType defines the list of valid storage account types for the boot diagnostics.
+type BootDiagnosticsStorageAccountType string
+
+const (
+	// DisabledDiagnosticsStorage is used to determine that the diagnostics storage account is disabled.
+	DisabledDiagnosticsStorage BootDiagnosticsStorageAccountType = "Disabled"
+	// ManagedDiagnosticsStorage is used to determine that the diagnostics storage account is provisioned by Azure.
+	ManagedDiagnosticsStorage BootDiagnosticsStorageAccountType = "Managed"
+	// UserManagedDiagnosticsStorage is used to determine that the diagnostics storage account is provided by the user.
+	UserManagedDiagnosticsStorage BootDiagnosticsStorageAccountType = "UserManaged"
+)
+
+// Diagnostics is used to configure the diagnostic settings of the virtual machine.
+type Diagnostics struct {
+	// Boot configures the boot diagnostics settings for the virtual machine.
+	// This allows to configure capturing serial output from the virtual machine on boot.
+	// This is useful for debugging software based launch issues.
+	// If not specified then Boot diagnostics (Managed) will be enabled.
+	// +optional
+	Boot *BootDiagnostics `json:"boot,omitempty"`
+}
+
+// BootDiagnostics configures the boot diagnostics settings for the virtual machine.
+type BootDiagnostics struct {
+	// StorageAccountType determines if the storage account for storing the diagnostics data
+	// should be disabled (Disabled), provisioned by Azure (Managed) or by the user (UserManaged).
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=Managed;UserManaged;Disabled
+	StorageAccountType BootDiagnosticsStorageAccountType `json:"storageAccountType"`
+
+	// UserManaged provides a reference to the user-managed storage account.
+	// +optional
+	UserManaged *UserManagedBootDiagnostics `json:"userManaged,omitempty"`
+}
+
+// UserManagedBootDiagnostics provides a reference to a user-managed storage account.
+type UserManagedBootDiagnostics struct {
+	// StorageAccountURI is the URI of the user-managed storage account.
+	// The URI typically will be `https://<mystorageaccountname>.blob.core.windows.net/`
+	// but may differ if you are using Azure DNS zone endpoints.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^https://`
+	// +kubebuilder:validation:MaxLength=1024
+	StorageAccountURI string `json:"storageAccountURI"`
+}
+
+// NodeDiagnosticsOutput defines where collected node diagnostics are stored.
+type NodeDiagnosticsOutput string
+
+const (
+	// NodeDiagnosticsOutputEvent stores a truncated excerpt of the collected node diagnostics in a Kubernetes event.
+	NodeDiagnosticsOutputEvent NodeDiagnosticsOutput = "Event"
+	// NodeDiagnosticsOutputSecret stores the collected node diagnostics in a Secret referenced from the object status.
+	NodeDiagnosticsOutputSecret NodeDiagnosticsOutput = "Secret"
+)
+
+// NodeDiagnostics configures the collection of node diagnostics from machines that fail to bootstrap.
+// Diagnostics are collected once using Azure Run Command and include the cloud-init and kubelet logs,
+// as well as the boot diagnostics serial console output.
+type NodeDiagnostics struct {
+	// Output specifies where the collected diagnostics are stored.
+	// +kubebuilder:validation:Enum=Event;Secret
+	// +kubebuilder:default=Event
+	// +optional
+	Output NodeDiagnosticsOutput `json:"output,omitempty"`
+}
+
 // AddressRecord specifies a DNS record mapping a hostname to an IPV4 or IPv6 address.
 type AddressRecord struct {
 	Hostname string
@@ -69,6 +69,61 @@ func (in *AllowedNamespaces) DeepCopy() *AllowedNamespaces {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationHealthProbe) DeepCopyInto(out *ApplicationHealthProbe) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationHealthProbe.
+func (in *ApplicationHealthProbe) DeepCopy() *ApplicationHealthProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationHealthProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutomaticOSUpgradePolicy) DeepCopyInto(out *AutomaticOSUpgradePolicy) {
+	*out = *in
+	if in.DisableAutomaticRollback != nil {
+		in, out := &in.DisableAutomaticRollback, &out.DisableAutomaticRollback
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutomaticOSUpgradePolicy.
+func (in *AutomaticOSUpgradePolicy) DeepCopy() *AutomaticOSUpgradePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AutomaticOSUpgradePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutomaticRepairsPolicy) DeepCopyInto(out *AutomaticRepairsPolicy) {
+	*out = *in
+	if in.GracePeriod != nil {
+		in, out := &in.GracePeriod, &out.GracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutomaticRepairsPolicy.
+func (in *AutomaticRepairsPolicy) DeepCopy() *AutomaticRepairsPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AutomaticRepairsPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureBastion) DeepCopyInto(out *AzureBastion) {
 	*out = *in
@@ -428,6 +483,23 @@ func (in *AzureMachineSpec) DeepCopyInto(out *AzureMachineSpec) {
 		*out = new(SecurityProfile)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.VMExtensions != nil {
+		in, out := &in.VMExtensions, &out.VMExtensions
+		*out = make([]VMExtension, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeDiagnostics != nil {
+		in, out := &in.NodeDiagnostics, &out.NodeDiagnostics
+		*out = new(NodeDiagnostics)
+		**out = **in
+	}
+	if in.Diagnostics != nil {
+		in, out := &in.Diagnostics, &out.Diagnostics
+		*out = new(Diagnostics)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachineSpec.
@@ -470,6 +542,16 @@ func (in *AzureMachineStatus) DeepCopyInto(out *AzureMachineStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.VMExtensions != nil {
+		in, out := &in.VMExtensions, &out.VMExtensions
+		*out = make([]VMExtensionStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeDiagnosticsRef != nil {
+		in, out := &in.NodeDiagnosticsRef, &out.NodeDiagnosticsRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
 	if in.LongRunningOperationStates != nil {
 		in, out := &in.LongRunningOperationStates, &out.LongRunningOperationStates
 		*out = make(Futures, len(*in))
@@ -668,6 +750,26 @@ func (in *BastionSpec) DeepCopy() *BastionSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootDiagnostics) DeepCopyInto(out *BootDiagnostics) {
+	*out = *in
+	if in.UserManaged != nil {
+		in, out := &in.UserManaged, &out.UserManaged
+		*out = new(UserManagedBootDiagnostics)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootDiagnostics.
+func (in *BootDiagnostics) DeepCopy() *BootDiagnostics {
+	if in == nil {
+		return nil
+	}
+	out := new(BootDiagnostics)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BuildParams) DeepCopyInto(out *BuildParams) {
 	*out = *in
@@ -748,6 +850,26 @@ func (in *DataDisk) DeepCopy() *DataDisk {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Diagnostics) DeepCopyInto(out *Diagnostics) {
+	*out = *in
+	if in.Boot != nil {
+		in, out := &in.Boot, &out.Boot
+		*out = new(BootDiagnostics)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Diagnostics.
+func (in *Diagnostics) DeepCopy() *Diagnostics {
+	if in == nil {
+		return nil
+	}
+	out := new(Diagnostics)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DiffDiskSettings) DeepCopyInto(out *DiffDiskSettings) {
 	*out = *in
@@ -964,6 +1086,21 @@ func (in *NetworkSpec) DeepCopy() *NetworkSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeDiagnostics) DeepCopyInto(out *NodeDiagnostics) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeDiagnostics.
+func (in *NodeDiagnostics) DeepCopy() *NodeDiagnostics {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeDiagnostics)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OSDisk) DeepCopyInto(out *OSDisk) {
 	*out = *in
@@ -1050,6 +1187,46 @@ func (in *RateLimitSpec) DeepCopy() *RateLimitSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollingUpgradePolicy) DeepCopyInto(out *RollingUpgradePolicy) {
+	*out = *in
+	if in.MaxBatchInstancePercent != nil {
+		in, out := &in.MaxBatchInstancePercent, &out.MaxBatchInstancePercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxUnhealthyInstancePercent != nil {
+		in, out := &in.MaxUnhealthyInstancePercent, &out.MaxUnhealthyInstancePercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxUnhealthyUpgradedInstancePercent != nil {
+		in, out := &in.MaxUnhealthyUpgradedInstancePercent, &out.MaxUnhealthyUpgradedInstancePercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PauseTimeBetweenBatches != nil {
+		in, out := &in.PauseTimeBetweenBatches, &out.PauseTimeBetweenBatches
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.PrioritizeUnhealthyInstances != nil {
+		in, out := &in.PrioritizeUnhealthyInstances, &out.PrioritizeUnhealthyInstances
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollingUpgradePolicy.
+func (in *RollingUpgradePolicy) DeepCopy() *RollingUpgradePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RollingUpgradePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RouteTable) DeepCopyInto(out *RouteTable) {
 	*out = *in
@@ -1255,6 +1432,31 @@ func (in Tags) DeepCopy() Tags {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradePolicy) DeepCopyInto(out *UpgradePolicy) {
+	*out = *in
+	if in.RollingUpgradePolicy != nil {
+		in, out := &in.RollingUpgradePolicy, &out.RollingUpgradePolicy
+		*out = new(RollingUpgradePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AutomaticOSUpgradePolicy != nil {
+		in, out := &in.AutomaticOSUpgradePolicy, &out.AutomaticOSUpgradePolicy
+		*out = new(AutomaticOSUpgradePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradePolicy.
+func (in *UpgradePolicy) DeepCopy() *UpgradePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UserAssignedIdentity) DeepCopyInto(out *UserAssignedIdentity) {
 	*out = *in
@@ -1270,6 +1472,63 @@ func (in *UserAssignedIdentity) DeepCopy() *UserAssignedIdentity {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserManagedBootDiagnostics) DeepCopyInto(out *UserManagedBootDiagnostics) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserManagedBootDiagnostics.
+func (in *UserManagedBootDiagnostics) DeepCopy() *UserManagedBootDiagnostics {
+	if in == nil {
+		return nil
+	}
+	out := new(UserManagedBootDiagnostics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMExtension) DeepCopyInto(out *VMExtension) {
+	*out = *in
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ProtectedSettingsRef != nil {
+		in, out := &in.ProtectedSettingsRef, &out.ProtectedSettingsRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMExtension.
+func (in *VMExtension) DeepCopy() *VMExtension {
+	if in == nil {
+		return nil
+	}
+	out := new(VMExtension)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMExtensionStatus) DeepCopyInto(out *VMExtensionStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMExtensionStatus.
+func (in *VMExtensionStatus) DeepCopy() *VMExtensionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VMExtensionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VnetPeeringSpec) DeepCopyInto(out *VnetPeeringSpec) {
 	*out = *in
@@ -0,0 +1,149 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-08-01/containerservice"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+)
+
+// NodeTaintsToSDK converts agent pool node taints to the SDK representation.
+// An empty, non-nil list is returned when there are no taints so that existing taints are removed on update.
+func NodeTaintsToSDK(taints []string) *[]string {
+	sdkTaints := make([]string, len(taints))
+	copy(sdkTaints, taints)
+	return &sdkTaints
+}
+
+// SDKToNodeTaints converts the node taints of an agent pool, of the form key=value:effect, to taints.
+func SDKToNodeTaints(sdkTaints *[]string) (infrav1exp.Taints, error) {
+	if sdkTaints == nil || len(*sdkTaints) == 0 {
+		return nil, nil
+	}
+
+	taints := make(infrav1exp.Taints, 0, len(*sdkTaints))
+	for _, sdkTaint := range *sdkTaints {
+		taint, err := SDKToNodeTaint(sdkTaint)
+		if err != nil {
+			return nil, err
+		}
+		taints = append(taints, taint)
+	}
+	return taints, nil
+}
+
+// SDKToNodeTaint converts an agent pool node taint of the form key=value:effect to a taint.
+func SDKToNodeTaint(sdkTaint string) (infrav1exp.Taint, error) {
+	keyValue, effect := sdkTaint, ""
+	if i := strings.LastIndex(sdkTaint, ":"); i >= 0 {
+		keyValue, effect = sdkTaint[:i], sdkTaint[i+1:]
+	}
+	key, value := keyValue, ""
+	if i := strings.Index(keyValue, "="); i >= 0 {
+		key, value = keyValue[:i], keyValue[i+1:]
+	}
+
+	switch infrav1exp.TaintEffect(effect) {
+	case infrav1exp.TaintEffectNoSchedule, infrav1exp.TaintEffectPreferNoSchedule, infrav1exp.TaintEffectNoExecute:
+	default:
+		return infrav1exp.Taint{}, errors.Errorf("invalid taint %s", sdkTaint)
+	}
+
+	return infrav1exp.Taint{
+		Key:    key,
+		Value:  value,
+		Effect: infrav1exp.TaintEffect(effect),
+	}, nil
+}
+
+// KubeletConfigToSDK converts an agent pool kubelet configuration to the SDK representation.
+func KubeletConfigToSDK(config *azure.KubeletConfig) *containerservice.KubeletConfig {
+	if config == nil {
+		return nil
+	}
+
+	sdkConfig := &containerservice.KubeletConfig{
+		CPUManagerPolicy:      config.CPUManagerPolicy,
+		CPUCfsQuota:           config.CPUCfsQuota,
+		CPUCfsQuotaPeriod:     config.CPUCfsQuotaPeriod,
+		ImageGcHighThreshold:  config.ImageGcHighThreshold,
+		ImageGcLowThreshold:   config.ImageGcLowThreshold,
+		TopologyManagerPolicy: config.TopologyManagerPolicy,
+		FailSwapOn:            config.FailSwapOn,
+		ContainerLogMaxSizeMB: config.ContainerLogMaxSizeMB,
+		ContainerLogMaxFiles:  config.ContainerLogMaxFiles,
+		PodMaxPids:            config.PodMaxPids,
+	}
+	if len(config.AllowedUnsafeSysctls) > 0 {
+		sysctls := make([]string, len(config.AllowedUnsafeSysctls))
+		copy(sysctls, config.AllowedUnsafeSysctls)
+		sdkConfig.AllowedUnsafeSysctls = &sysctls
+	}
+
+	return sdkConfig
+}
+
+// LinuxOSConfigToSDK converts an agent pool Linux OS configuration to the SDK representation.
+func LinuxOSConfigToSDK(config *azure.LinuxOSConfig) *containerservice.LinuxOSConfig {
+	if config == nil {
+		return nil
+	}
+
+	sdkConfig := &containerservice.LinuxOSConfig{
+		SwapFileSizeMB:             config.SwapFileSizeMB,
+		TransparentHugePageDefrag:  config.TransparentHugePageDefrag,
+		TransparentHugePageEnabled: config.TransparentHugePageEnabled,
+	}
+	if config.Sysctls != nil {
+		sdkConfig.Sysctls = &containerservice.SysctlConfig{
+			NetCoreSomaxconn:               config.Sysctls.NetCoreSomaxconn,
+			NetCoreNetdevMaxBacklog:        config.Sysctls.NetCoreNetdevMaxBacklog,
+			NetCoreRmemDefault:             config.Sysctls.NetCoreRmemDefault,
+			NetCoreRmemMax:                 config.Sysctls.NetCoreRmemMax,
+			NetCoreWmemDefault:             config.Sysctls.NetCoreWmemDefault,
+			NetCoreWmemMax:                 config.Sysctls.NetCoreWmemMax,
+			NetCoreOptmemMax:               config.Sysctls.NetCoreOptmemMax,
+			NetIpv4TCPMaxSynBacklog:        config.Sysctls.NetIpv4TCPMaxSynBacklog,
+			NetIpv4TCPMaxTwBuckets:         config.Sysctls.NetIpv4TCPMaxTwBuckets,
+			NetIpv4TCPFinTimeout:           config.Sysctls.NetIpv4TCPFinTimeout,
+			NetIpv4TCPKeepaliveTime:        config.Sysctls.NetIpv4TCPKeepaliveTime,
+			NetIpv4TCPKeepaliveProbes:      config.Sysctls.NetIpv4TCPKeepaliveProbes,
+			NetIpv4TcpkeepaliveIntvl:       config.Sysctls.NetIpv4TCPKeepaliveIntvl,
+			NetIpv4TCPTwReuse:              config.Sysctls.NetIpv4TCPTwReuse,
+			NetIpv4IPLocalPortRange:        config.Sysctls.NetIpv4IPLocalPortRange,
+			NetIpv4NeighDefaultGcThresh1:   config.Sysctls.NetIpv4NeighDefaultGcThresh1,
+			NetIpv4NeighDefaultGcThresh2:   config.Sysctls.NetIpv4NeighDefaultGcThresh2,
+			NetIpv4NeighDefaultGcThresh3:   config.Sysctls.NetIpv4NeighDefaultGcThresh3,
+			NetNetfilterNfConntrackMax:     config.Sysctls.NetNetfilterNfConntrackMax,
+			NetNetfilterNfConntrackBuckets: config.Sysctls.NetNetfilterNfConntrackBuckets,
+			FsInotifyMaxUserWatches:        config.Sysctls.FsInotifyMaxUserWatches,
+			FsFileMax:                      config.Sysctls.FsFileMax,
+			FsAioMaxNr:                     config.Sysctls.FsAioMaxNr,
+			FsNrOpen:                       config.Sysctls.FsNrOpen,
+			KernelThreadsMax:               config.Sysctls.KernelThreadsMax,
+			VMMaxMapCount:                  config.Sysctls.VMMaxMapCount,
+			VMSwappiness:                   config.Sysctls.VMSwappiness,
+			VMVfsCachePressure:             config.Sysctls.VMVfsCachePressure,
+		}
+	}
+
+	return sdkConfig
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-08-01/containerservice"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+)
+
+func Test_NodeTaintsToSDK(t *testing.T) {
+	cases := []struct {
+		name   string
+		taints []string
+		expect *[]string
+	}{
+		{
+			name:   "Should return an empty list for no taints",
+			taints: nil,
+			expect: &[]string{},
+		},
+		{
+			name:   "Should return the taints",
+			taints: []string{"CriticalAddonsOnly=true:NoSchedule"},
+			expect: &[]string{"CriticalAddonsOnly=true:NoSchedule"},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(NodeTaintsToSDK(c.taints)).To(Equal(c.expect))
+		})
+	}
+}
+
+func Test_KubeletConfigToSDK(t *testing.T) {
+	cases := []struct {
+		name   string
+		config *azure.KubeletConfig
+		expect *containerservice.KubeletConfig
+	}{
+		{
+			name:   "Should return nil for no kubelet configuration",
+			config: nil,
+			expect: nil,
+		},
+		{
+			name: "Should convert the kubelet configuration",
+			config: &azure.KubeletConfig{
+				CPUManagerPolicy:     to.StringPtr("static"),
+				ImageGcHighThreshold: to.Int32Ptr(85),
+				ImageGcLowThreshold:  to.Int32Ptr(80),
+				AllowedUnsafeSysctls: []string{"kernel.msg*", "net.ipv4.route.min_pmtu"},
+				FailSwapOn:           to.BoolPtr(false),
+			},
+			expect: &containerservice.KubeletConfig{
+				CPUManagerPolicy:     to.StringPtr("static"),
+				ImageGcHighThreshold: to.Int32Ptr(85),
+				ImageGcLowThreshold:  to.Int32Ptr(80),
+				AllowedUnsafeSysctls: &[]string{"kernel.msg*", "net.ipv4.route.min_pmtu"},
+				FailSwapOn:           to.BoolPtr(false),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(KubeletConfigToSDK(c.config)).To(Equal(c.expect))
+		})
+	}
+}
+
+func Test_LinuxOSConfigToSDK(t *testing.T) {
+	cases := []struct {
+		name   string
+		config *azure.LinuxOSConfig
+		expect *containerservice.LinuxOSConfig
+	}{
+		{
+			name:   "Should return nil for no Linux OS configuration",
+			config: nil,
+			expect: nil,
+		},
+		{
+			name: "Should convert the Linux OS configuration without sysctls",
+			config: &azure.LinuxOSConfig{
+				SwapFileSizeMB:             to.Int32Ptr(1500),
+				TransparentHugePageEnabled: to.StringPtr("madvise"),
+			},
+			expect: &containerservice.LinuxOSConfig{
+				SwapFileSizeMB:             to.Int32Ptr(1500),
+				TransparentHugePageEnabled: to.StringPtr("madvise"),
+			},
+		},
+		{
+			name: "Should convert the Linux OS configuration with sysctls",
+			config: &azure.LinuxOSConfig{
+				TransparentHugePageDefrag: to.StringPtr("defer+madvise"),
+				Sysctls: &azure.SysctlConfig{
+					NetCoreSomaxconn:         to.Int32Ptr(16384),
+					NetIpv4TCPKeepaliveIntvl: to.Int32Ptr(30),
+					NetIpv4TCPTwReuse:        to.BoolPtr(true),
+					NetIpv4IPLocalPortRange:  to.StringPtr("32000 60000"),
+					VMMaxMapCount:            to.Int32Ptr(262144),
+				},
+			},
+			expect: &containerservice.LinuxOSConfig{
+				TransparentHugePageDefrag: to.StringPtr("defer+madvise"),
+				Sysctls: &containerservice.SysctlConfig{
+					NetCoreSomaxconn:         to.Int32Ptr(16384),
+					NetIpv4TcpkeepaliveIntvl: to.Int32Ptr(30),
+					NetIpv4TCPTwReuse:        to.BoolPtr(true),
+					NetIpv4IPLocalPortRange:  to.StringPtr("32000 60000"),
+					VMMaxMapCount:            to.Int32Ptr(262144),
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(LinuxOSConfigToSDK(c.config)).To(Equal(c.expect))
+		})
+	}
+}
+
+func Test_SDKToNodeTaint(t *testing.T) {
+	tests := []struct {
+		taint   string
+		want    infrav1exp.Taint
+		wantErr bool
+	}{
+		{
+			taint: "dedicated=batch:NoSchedule",
+			want:  infrav1exp.Taint{Key: "dedicated", Value: "batch", Effect: infrav1exp.TaintEffectNoSchedule},
+		},
+		{
+			taint: "CriticalAddonsOnly:PreferNoSchedule",
+			want:  infrav1exp.Taint{Key: "CriticalAddonsOnly", Effect: infrav1exp.TaintEffectPreferNoSchedule},
+		},
+		{
+			taint:   "dedicated=batch",
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.taint, func(t *testing.T) {
+			g := NewWithT(t)
+			taint, err := SDKToNodeTaint(tc.taint)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(taint).To(Equal(tc.want))
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestGetDiagnosticsProfile(t *testing.T) {
+	tests := []struct {
+		name        string
+		diagnostics *infrav1.Diagnostics
+		want        *compute.DiagnosticsProfile
+	}{
+		{
+			name:        "managed boot diagnostics when no diagnostics are specified",
+			diagnostics: nil,
+			want: &compute.DiagnosticsProfile{
+				BootDiagnostics: &compute.BootDiagnostics{Enabled: to.BoolPtr(true)},
+			},
+		},
+		{
+			name:        "managed boot diagnostics",
+			diagnostics: &infrav1.Diagnostics{Boot: &infrav1.BootDiagnostics{StorageAccountType: infrav1.ManagedDiagnosticsStorage}},
+			want: &compute.DiagnosticsProfile{
+				BootDiagnostics: &compute.BootDiagnostics{Enabled: to.BoolPtr(true)},
+			},
+		},
+		{
+			name:        "disabled boot diagnostics",
+			diagnostics: &infrav1.Diagnostics{Boot: &infrav1.BootDiagnostics{StorageAccountType: infrav1.DisabledDiagnosticsStorage}},
+			want: &compute.DiagnosticsProfile{
+				BootDiagnostics: &compute.BootDiagnostics{Enabled: to.BoolPtr(false)},
+			},
+		},
+		{
+			name: "user managed boot diagnostics",
+			diagnostics: &infrav1.Diagnostics{Boot: &infrav1.BootDiagnostics{
+				StorageAccountType: infrav1.UserManagedDiagnosticsStorage,
+				UserManaged:        &infrav1.UserManagedBootDiagnostics{StorageAccountURI: "https://fake.blob.core.windows.net/"},
+			}},
+			want: &compute.DiagnosticsProfile{
+				BootDiagnostics: &compute.BootDiagnostics{
+					Enabled:    to.BoolPtr(true),
+					StorageURI: to.StringPtr("https://fake.blob.core.windows.net/"),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(GetDiagnosticsProfile(tt.diagnostics)).To(Equal(tt.want))
+		})
+	}
+}
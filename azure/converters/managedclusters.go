@@ -0,0 +1,61 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-08-01/containerservice"
+	"github.com/Azure/go-autorest/autorest/to"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+// AutoScalerProfileToSDK converts a managed cluster autoscaler profile to the SDK representation.
+func AutoScalerProfileToSDK(profile *azure.AutoScalerProfile) *containerservice.ManagedClusterPropertiesAutoScalerProfile {
+	if profile == nil {
+		return nil
+	}
+
+	return &containerservice.ManagedClusterPropertiesAutoScalerProfile{
+		BalanceSimilarNodeGroups:      profile.BalanceSimilarNodeGroups,
+		Expander:                      containerservice.Expander(to.String(profile.Expander)),
+		MaxEmptyBulkDelete:            profile.MaxEmptyBulkDelete,
+		MaxGracefulTerminationSec:     profile.MaxGracefulTerminationSec,
+		MaxNodeProvisionTime:          profile.MaxNodeProvisionTime,
+		MaxTotalUnreadyPercentage:     profile.MaxTotalUnreadyPercentage,
+		NewPodScaleUpDelay:            profile.NewPodScaleUpDelay,
+		OkTotalUnreadyCount:           profile.OkTotalUnreadyCount,
+		ScanInterval:                  profile.ScanInterval,
+		ScaleDownDelayAfterAdd:        profile.ScaleDownDelayAfterAdd,
+		ScaleDownDelayAfterDelete:     profile.ScaleDownDelayAfterDelete,
+		ScaleDownDelayAfterFailure:    profile.ScaleDownDelayAfterFailure,
+		ScaleDownUnneededTime:         profile.ScaleDownUnneededTime,
+		ScaleDownUnreadyTime:          profile.ScaleDownUnreadyTime,
+		ScaleDownUtilizationThreshold: profile.ScaleDownUtilizationThreshold,
+		SkipNodesWithLocalStorage:     profile.SkipNodesWithLocalStorage,
+		SkipNodesWithSystemPods:       profile.SkipNodesWithSystemPods,
+	}
+}
+
+// AutoUpgradeProfileToSDK converts a managed cluster auto-upgrade channel to the SDK representation.
+func AutoUpgradeProfileToSDK(upgradeChannel *string) *containerservice.ManagedClusterAutoUpgradeProfile {
+	if upgradeChannel == nil {
+		return nil
+	}
+
+	return &containerservice.ManagedClusterAutoUpgradeProfile{
+		UpgradeChannel: containerservice.UpgradeChannel(*upgradeChannel),
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-08-01/containerservice"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+)
+
+func Test_AutoScalerProfileToSDK(t *testing.T) {
+	cases := []struct {
+		name    string
+		profile *azure.AutoScalerProfile
+		expect  *containerservice.ManagedClusterPropertiesAutoScalerProfile
+	}{
+		{
+			name:    "Should return nil for no autoscaler profile",
+			profile: nil,
+			expect:  nil,
+		},
+		{
+			name: "Should convert the autoscaler profile",
+			profile: &azure.AutoScalerProfile{
+				BalanceSimilarNodeGroups: to.StringPtr("true"),
+				Expander:                 to.StringPtr("least-waste"),
+				ScanInterval:             to.StringPtr("20s"),
+			},
+			expect: &containerservice.ManagedClusterPropertiesAutoScalerProfile{
+				BalanceSimilarNodeGroups: to.StringPtr("true"),
+				Expander:                 containerservice.ExpanderLeastWaste,
+				ScanInterval:             to.StringPtr("20s"),
+			},
+		},
+		{
+			name: "Should leave the expander empty when it is not set",
+			profile: &azure.AutoScalerProfile{
+				MaxEmptyBulkDelete: to.StringPtr("5"),
+			},
+			expect: &containerservice.ManagedClusterPropertiesAutoScalerProfile{
+				MaxEmptyBulkDelete: to.StringPtr("5"),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(AutoScalerProfileToSDK(c.profile)).To(Equal(c.expect))
+		})
+	}
+}
+
+func Test_AutoUpgradeProfileToSDK(t *testing.T) {
+	cases := []struct {
+		name           string
+		upgradeChannel *string
+		expect         *containerservice.ManagedClusterAutoUpgradeProfile
+	}{
+		{
+			name:           "Should return nil for no upgrade channel",
+			upgradeChannel: nil,
+			expect:         nil,
+		},
+		{
+			name:           "Should convert the upgrade channel",
+			upgradeChannel: to.StringPtr("node-image"),
+			expect: &containerservice.ManagedClusterAutoUpgradeProfile{
+				UpgradeChannel: containerservice.UpgradeChannelNodeImage,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(AutoUpgradeProfileToSDK(c.upgradeChannel)).To(Equal(c.expect))
+		})
+	}
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package converters
 
 import (
+	"strings"
+
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
 	"github.com/Azure/go-autorest/autorest/to"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
@@ -40,6 +42,10 @@ func SDKToVMSS(sdkvmss compute.VirtualMachineScaleSet, sdkinstances []compute.Vi
 		vmss.Zones = to.StringSlice(sdkvmss.Zones)
 	}
 
+	if sdkvmss.VirtualMachineScaleSetProperties != nil && sdkvmss.OrchestrationMode != "" {
+		vmss.OrchestrationMode = infrav1.OrchestrationModeType(sdkvmss.OrchestrationMode)
+	}
+
 	if len(sdkvmss.Tags) > 0 {
 		vmss.Tags = MapToTags(sdkvmss.Tags)
 	}
@@ -58,6 +64,19 @@ func SDKToVMSS(sdkvmss compute.VirtualMachineScaleSet, sdkinstances []compute.Vi
 		vmss.Image = SDKImageToImage(imageRef, sdkvmss.Plan != nil)
 	}
 
+	if sdkvmss.VirtualMachineProfile != nil &&
+		sdkvmss.VirtualMachineProfile.ExtensionProfile != nil &&
+		sdkvmss.VirtualMachineProfile.ExtensionProfile.Extensions != nil {
+		vmss.Extensions = make(map[string]string, len(*sdkvmss.VirtualMachineProfile.ExtensionProfile.Extensions))
+		for _, extension := range *sdkvmss.VirtualMachineProfile.ExtensionProfile.Extensions {
+			var version string
+			if extension.VirtualMachineScaleSetExtensionProperties != nil {
+				version = to.String(extension.TypeHandlerVersion)
+			}
+			vmss.Extensions[to.String(extension.Name)] = version
+		}
+	}
+
 	return vmss
 }
 
@@ -91,9 +110,128 @@ func SDKToVMSSVM(sdkInstance compute.VirtualMachineScaleSetVM) *azure.VMSSVM {
 		instance.AvailabilityZone = to.StringSlice(sdkInstance.Zones)[0]
 	}
 
+	if sdkInstance.InstanceView != nil {
+		instance.BootstrapState = sdkBootstrapExtensionState(sdkInstance.InstanceView.Extensions)
+	}
+
+	if sdkInstance.ProtectionPolicy != nil {
+		instance.ProtectionPolicy = azure.VMSSVMProtectionPolicy{
+			ProtectFromScaleIn:         to.Bool(sdkInstance.ProtectionPolicy.ProtectFromScaleIn),
+			ProtectFromScaleSetActions: to.Bool(sdkInstance.ProtectionPolicy.ProtectFromScaleSetActions),
+		}
+	}
+
+	if sdkInstance.Sku != nil {
+		instance.VMSize = to.String(sdkInstance.Sku.Name)
+	}
+
+	if len(sdkInstance.Tags) > 0 {
+		instance.Tags = MapToTags(sdkInstance.Tags)
+	}
+
+	instance.Extensions = sdkExtensionVersions(sdkInstance.Resources)
+	instance.ModelOutdated = sdkInstance.LatestModelApplied != nil && !*sdkInstance.LatestModelApplied
+
 	return &instance
 }
 
+// SDKVMToVMSSVM converts an Azure SDK VirtualMachine of a scale set in Flexible orchestration mode into an
+// azure.VMSSVM. Flexible scale set instances are addressed by their VM name, which is used as the instance ID.
+func SDKVMToVMSSVM(sdkInstance compute.VirtualMachine) *azure.VMSSVM {
+	instance := azure.VMSSVM{
+		ID:         to.String(sdkInstance.ID),
+		InstanceID: to.String(sdkInstance.Name),
+	}
+
+	if sdkInstance.VirtualMachineProperties == nil {
+		return &instance
+	}
+
+	instance.State = infrav1.Creating
+	if sdkInstance.ProvisioningState != nil {
+		instance.State = infrav1.ProvisioningState(to.String(sdkInstance.ProvisioningState))
+	}
+
+	if sdkInstance.OsProfile != nil && sdkInstance.OsProfile.ComputerName != nil {
+		instance.Name = *sdkInstance.OsProfile.ComputerName
+	}
+
+	if sdkInstance.StorageProfile != nil && sdkInstance.StorageProfile.ImageReference != nil {
+		imageRef := sdkInstance.StorageProfile.ImageReference
+		instance.Image = SDKImageToImage(imageRef, sdkInstance.Plan != nil)
+	}
+
+	if sdkInstance.Zones != nil && len(*sdkInstance.Zones) > 0 {
+		// an instance should only have 1 zone, so we select the first item of the slice
+		instance.AvailabilityZone = to.StringSlice(sdkInstance.Zones)[0]
+	}
+
+	if sdkInstance.InstanceView != nil {
+		instance.BootstrapState = sdkBootstrapExtensionState(sdkInstance.InstanceView.Extensions)
+	}
+
+	if sdkInstance.HardwareProfile != nil {
+		instance.VMSize = string(sdkInstance.HardwareProfile.VMSize)
+	}
+
+	if len(sdkInstance.Tags) > 0 {
+		instance.Tags = MapToTags(sdkInstance.Tags)
+	}
+
+	instance.Extensions = sdkExtensionVersions(sdkInstance.Resources)
+
+	return &instance
+}
+
+// sdkExtensionVersions returns the type handler version of the extensions of a VM by extension name, or nil if the
+// extensions are not returned.
+func sdkExtensionVersions(extensions *[]compute.VirtualMachineExtension) map[string]string {
+	if extensions == nil {
+		return nil
+	}
+
+	versions := make(map[string]string, len(*extensions))
+	for _, extension := range *extensions {
+		var version string
+		if extension.VirtualMachineExtensionProperties != nil {
+			version = to.String(extension.TypeHandlerVersion)
+		}
+		versions[to.String(extension.Name)] = version
+	}
+
+	return versions
+}
+
+// sdkBootstrapExtensionState returns the provisioning state of the bootstrapping extension from the instance view
+// of a scale set VM, or an empty state if the extension is not reported.
+func sdkBootstrapExtensionState(extensions *[]compute.VirtualMachineExtensionInstanceView) infrav1.ProvisioningState {
+	if extensions == nil {
+		return ""
+	}
+
+	for _, extension := range *extensions {
+		if !azure.IsBootstrappingVMExtension(to.String(extension.Name)) || extension.Statuses == nil {
+			continue
+		}
+		for _, status := range *extension.Statuses {
+			// status codes are of the form "ProvisioningState/<state>[/<substatus>]"
+			parts := strings.Split(to.String(status.Code), "/")
+			if len(parts) < 2 || parts[0] != "ProvisioningState" {
+				continue
+			}
+			switch strings.ToLower(parts[1]) {
+			case "succeeded":
+				return infrav1.Succeeded
+			case "failed":
+				return infrav1.Failed
+			default:
+				return infrav1.Creating
+			}
+		}
+	}
+	return ""
+}
+
 // SDKImageToImage converts a SDK image reference to infrav1.Image.
 func SDKImageToImage(sdkImageRef *compute.ImageReference, isThirdPartyImage bool) infrav1.Image {
 	return infrav1.Image{
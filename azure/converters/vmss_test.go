@@ -23,6 +23,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
 )
@@ -122,3 +123,185 @@ func Test_SDKToVMSS(t *testing.T) {
 		})
 	}
 }
+
+func Test_SDKToVMSSVM_BootstrapState(t *testing.T) {
+	cases := []struct {
+		Name       string
+		Extensions *[]compute.VirtualMachineExtensionInstanceView
+		Expected   infrav1.ProvisioningState
+	}{
+		{
+			Name:     "no extensions reported",
+			Expected: "",
+		},
+		{
+			Name: "bootstrapping extension failed",
+			Extensions: &[]compute.VirtualMachineExtensionInstanceView{
+				{
+					Name:     to.StringPtr("my-extension"),
+					Statuses: &[]compute.InstanceViewStatus{{Code: to.StringPtr("ProvisioningState/succeeded")}},
+				},
+				{
+					Name:     to.StringPtr(azure.BootstrappingExtensionLinux),
+					Statuses: &[]compute.InstanceViewStatus{{Code: to.StringPtr("ProvisioningState/failed/1")}},
+				},
+			},
+			Expected: infrav1.Failed,
+		},
+		{
+			Name: "bootstrapping extension succeeded",
+			Extensions: &[]compute.VirtualMachineExtensionInstanceView{
+				{
+					Name:     to.StringPtr(azure.BootstrappingExtensionWindows),
+					Statuses: &[]compute.InstanceViewStatus{{Code: to.StringPtr("ProvisioningState/succeeded")}},
+				},
+			},
+			Expected: infrav1.Succeeded,
+		},
+		{
+			Name: "bootstrapping extension transitioning",
+			Extensions: &[]compute.VirtualMachineExtensionInstanceView{
+				{
+					Name:     to.StringPtr(azure.BootstrappingExtensionLinux),
+					Statuses: &[]compute.InstanceViewStatus{{Code: to.StringPtr("ProvisioningState/transitioning")}},
+				},
+			},
+			Expected: infrav1.Creating,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+			g := gomega.NewGomegaWithT(t)
+			subject := converters.SDKToVMSSVM(compute.VirtualMachineScaleSetVM{
+				VirtualMachineScaleSetVMProperties: &compute.VirtualMachineScaleSetVMProperties{
+					ProvisioningState: to.StringPtr("Succeeded"),
+					InstanceView: &compute.VirtualMachineScaleSetVMInstanceView{
+						Extensions: c.Extensions,
+					},
+				},
+			})
+			g.Expect(subject.BootstrapState).To(gomega.Equal(c.Expected))
+		})
+	}
+}
+
+func Test_SDKToVMSSVM_ProtectionPolicy(t *testing.T) {
+	cases := []struct {
+		Name             string
+		ProtectionPolicy *compute.VirtualMachineScaleSetVMProtectionPolicy
+		Expected         azure.VMSSVMProtectionPolicy
+	}{
+		{
+			Name:     "no protection policy reported",
+			Expected: azure.VMSSVMProtectionPolicy{},
+		},
+		{
+			Name: "protected from scale-in",
+			ProtectionPolicy: &compute.VirtualMachineScaleSetVMProtectionPolicy{
+				ProtectFromScaleIn: to.BoolPtr(true),
+			},
+			Expected: azure.VMSSVMProtectionPolicy{ProtectFromScaleIn: true},
+		},
+		{
+			Name: "protected from scale-in and scale set actions",
+			ProtectionPolicy: &compute.VirtualMachineScaleSetVMProtectionPolicy{
+				ProtectFromScaleIn:         to.BoolPtr(true),
+				ProtectFromScaleSetActions: to.BoolPtr(true),
+			},
+			Expected: azure.VMSSVMProtectionPolicy{ProtectFromScaleIn: true, ProtectFromScaleSetActions: true},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+			g := gomega.NewGomegaWithT(t)
+			subject := converters.SDKToVMSSVM(compute.VirtualMachineScaleSetVM{
+				VirtualMachineScaleSetVMProperties: &compute.VirtualMachineScaleSetVMProperties{
+					ProvisioningState: to.StringPtr("Succeeded"),
+					ProtectionPolicy:  c.ProtectionPolicy,
+				},
+			})
+			g.Expect(subject.ProtectionPolicy).To(gomega.Equal(c.Expected))
+		})
+	}
+}
+
+func Test_SDKToVMSSVM_Model(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	subject := converters.SDKToVMSSVM(compute.VirtualMachineScaleSetVM{
+		Sku:  &compute.Sku{Name: to.StringPtr("Standard_D2s_v3")},
+		Tags: map[string]*string{"foo": to.StringPtr("bar")},
+		Resources: &[]compute.VirtualMachineExtension{
+			{
+				Name: to.StringPtr("CAPZ.Linux.Bootstrapping"),
+				VirtualMachineExtensionProperties: &compute.VirtualMachineExtensionProperties{
+					TypeHandlerVersion: to.StringPtr("1.0"),
+				},
+			},
+		},
+		VirtualMachineScaleSetVMProperties: &compute.VirtualMachineScaleSetVMProperties{
+			ProvisioningState:  to.StringPtr("Succeeded"),
+			LatestModelApplied: to.BoolPtr(false),
+		},
+	})
+
+	g.Expect(subject.VMSize).To(gomega.Equal("Standard_D2s_v3"))
+	g.Expect(subject.Tags).To(gomega.Equal(infrav1.Tags{"foo": "bar"}))
+	g.Expect(subject.Extensions).To(gomega.Equal(map[string]string{"CAPZ.Linux.Bootstrapping": "1.0"}))
+	g.Expect(subject.ModelOutdated).To(gomega.BeTrue())
+}
+
+func Test_SDKVMToVMSSVM(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	subject := converters.SDKVMToVMSSVM(compute.VirtualMachine{
+		ID:    to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/vmss_1a2b3c4d"),
+		Name:  to.StringPtr("vmss_1a2b3c4d"),
+		Zones: &[]string{"2"},
+		VirtualMachineProperties: &compute.VirtualMachineProperties{
+			ProvisioningState: to.StringPtr("Succeeded"),
+			OsProfile: &compute.OSProfile{
+				ComputerName: to.StringPtr("vmss1a2b3c"),
+			},
+			StorageProfile: &compute.StorageProfile{
+				ImageReference: &compute.ImageReference{
+					Publisher: to.StringPtr("cncf-upstream"),
+					Offer:     to.StringPtr("capi"),
+					Sku:       to.StringPtr("k8s-1dot22dot1-ubuntu-2004"),
+					Version:   to.StringPtr("latest"),
+				},
+			},
+			InstanceView: &compute.VirtualMachineInstanceView{
+				Extensions: &[]compute.VirtualMachineExtensionInstanceView{
+					{
+						Name:     to.StringPtr(azure.BootstrappingExtensionLinux),
+						Statuses: &[]compute.InstanceViewStatus{{Code: to.StringPtr("ProvisioningState/succeeded")}},
+					},
+				},
+			},
+		},
+	})
+
+	g.Expect(subject).To(gomega.Equal(&azure.VMSSVM{
+		ID:               "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachines/vmss_1a2b3c4d",
+		InstanceID:       "vmss_1a2b3c4d",
+		Name:             "vmss1a2b3c",
+		AvailabilityZone: "2",
+		State:            infrav1.Succeeded,
+		BootstrapState:   infrav1.Succeeded,
+		Image: infrav1.Image{
+			Marketplace: &infrav1.AzureMarketplaceImage{
+				Publisher: "cncf-upstream",
+				Offer:     "capi",
+				SKU:       "k8s-1dot22dot1-ubuntu-2004",
+				Version:   "latest",
+			},
+		},
+	}))
+}
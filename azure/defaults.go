@@ -19,6 +19,7 @@ package azure
 import (
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/azure"
@@ -67,6 +68,24 @@ const (
 	ControlPlaneNodeGroup = "control-plane"
 )
 
+const (
+	// BootstrappingExtensionLinux is the name of the Linux CAPZ bootstrapping VM extension.
+	BootstrappingExtensionLinux = "CAPZ.Linux.Bootstrapping"
+	// BootstrappingExtensionWindows is the name of the Windows CAPZ bootstrapping VM extension.
+	BootstrappingExtensionWindows = "CAPZ.Windows.Bootstrapping"
+)
+
+const (
+	// ApplicationHealthExtensionName is the name of the application health extension of a VM scale set.
+	ApplicationHealthExtensionName = "HealthExtension"
+	// ApplicationHealthExtensionPublisher is the publisher of the application health extension.
+	ApplicationHealthExtensionPublisher = "Microsoft.ManagedServices"
+	// ApplicationHealthExtensionLinux is the type of the Linux application health extension.
+	ApplicationHealthExtensionLinux = "ApplicationHealthLinux"
+	// ApplicationHealthExtensionWindows is the type of the Windows application health extension.
+	ApplicationHealthExtensionWindows = "ApplicationHealthWindows"
+)
+
 const (
 	// bootstrapExtensionRetries is the number of retries in the BootstrapExtensionCommand.
 	// NOTE: the overall timeout will be number of retries * retry sleep, in this case 60 * 5s = 300s.
@@ -78,6 +97,11 @@ const (
 	bootstrapSentinelFile = "/run/cluster-api/bootstrap-success.complete"
 )
 
+const (
+	// KubeletIdentityKey is the key of the kubelet identity in the identity profile of an AKS managed cluster.
+	KubeletIdentityKey = "kubeletidentity"
+)
+
 const (
 	// ProviderIDPrefix will be appended to the beginning of Azure resource IDs to form the Kubernetes Provider ID.
 	// NOTE: this format matches the 2 slashes format used in cloud-provider and cluster-autoscaler.
@@ -167,6 +191,22 @@ func GenerateDataDiskName(machineName, nameSuffix string) string {
 	return fmt.Sprintf("%s_%s", machineName, nameSuffix)
 }
 
+// GenerateMixedInstancesScaleSetName generates the name of one of the scale sets backing a machine pool with a mixed
+// instances policy, based on the name of the machine pool, the VM size and the priority of the scale set.
+func GenerateMixedInstancesScaleSetName(poolName, vmSize string, spot bool) string {
+	size := strings.ToLower(strings.ReplaceAll(strings.TrimPrefix(vmSize, "Standard_"), "_", "-"))
+	if spot {
+		return fmt.Sprintf("%s-%s-spot", poolName, size)
+	}
+	return fmt.Sprintf("%s-%s", poolName, size)
+}
+
+// GenerateBlueGreenScaleSetName generates the name of the scale set replacing the scale set of a machine pool with the
+// BlueGreen deployment strategy, based on the name of the machine pool and the revision of its model.
+func GenerateBlueGreenScaleSetName(poolName, revision string) string {
+	return fmt.Sprintf("%s-%s", poolName, revision)
+}
+
 // GenerateVnetPeeringName generates the name for a peering between two vnets.
 func GenerateVnetPeeringName(sourceVnetName string, remoteVnetName string) string {
 	return fmt.Sprintf("%s-To-%s", sourceVnetName, remoteVnetName)
@@ -342,9 +382,10 @@ func GetBootstrappingVMExtension(osType string, cloud string, vmName string) *Ex
 	if osType == LinuxOS && cloud == azure.PublicCloud.Name {
 		// The command checks for the existence of the bootstrapSentinelFile on the machine, with retries and sleep between retries.
 		return &ExtensionSpec{
-			Name:      "CAPZ.Linux.Bootstrapping",
+			Name:      BootstrappingExtensionLinux,
 			VMName:    vmName,
 			Publisher: "Microsoft.Azure.ContainerUpstream",
+			Type:      BootstrappingExtensionLinux,
 			Version:   "1.0",
 			ProtectedSettings: map[string]string{
 				"commandToExecute": LinuxBootstrapExtensionCommand,
@@ -354,9 +395,10 @@ func GetBootstrappingVMExtension(osType string, cloud string, vmName string) *Ex
 		// This command for the existence of the bootstrapSentinelFile on the machine, with retries and sleep between reties.
 		// If the file is not present after the retries are exhausted the extension fails with return code '-2' - ERROR_FILE_NOT_FOUND.
 		return &ExtensionSpec{
-			Name:      "CAPZ.Windows.Bootstrapping",
+			Name:      BootstrappingExtensionWindows,
 			VMName:    vmName,
 			Publisher: "Microsoft.Azure.ContainerUpstream",
+			Type:      BootstrappingExtensionWindows,
 			Version:   "1.0",
 			ProtectedSettings: map[string]string{
 				"commandToExecute": WindowsBootstrapExtensionCommand,
@@ -367,6 +409,11 @@ func GetBootstrappingVMExtension(osType string, cloud string, vmName string) *Ex
 	return nil
 }
 
+// IsBootstrappingVMExtension returns true if the extension name is one of the CAPZ Bootstrapping VM extensions.
+func IsBootstrappingVMExtension(name string) bool {
+	return name == BootstrappingExtensionLinux || name == BootstrappingExtensionWindows
+}
+
 // UserAgent specifies a string to append to the agent identifier.
 func UserAgent() string {
 	return fmt.Sprintf("cluster-api-provider-azure/%s", version.Get().String())
@@ -50,6 +50,43 @@ func ResourceConflict(err error) bool {
 	return errors.As(err, &derr) && derr.StatusCode == 409
 }
 
+// capacityErrorCodes are the error codes returned by Azure when a VM size cannot be provisioned because of SKU
+// restrictions or a lack of capacity in the requested location or zone.
+var capacityErrorCodes = map[string]struct{}{
+	"SkuNotAvailable":                       {},
+	"AllocationFailed":                      {},
+	"ZonalAllocationFailed":                 {},
+	"OverconstrainedAllocationRequest":      {},
+	"OverconstrainedZonalAllocationRequest": {},
+}
+
+// CapacityUnavailable parses the error to check if a VM size could not be provisioned because it is restricted or out
+// of capacity.
+func CapacityUnavailable(err error) bool {
+	var serr *azure.ServiceError
+	rerr := &azure.RequestError{}
+	switch {
+	case errors.As(err, &rerr) && rerr.ServiceError != nil:
+		serr = rerr.ServiceError
+	case !errors.As(err, &serr):
+		return false
+	}
+
+	if _, ok := capacityErrorCodes[serr.Code]; ok {
+		return true
+	}
+
+	for _, detail := range serr.Details {
+		if code, ok := detail["code"].(string); ok {
+			if _, ok := capacityErrorCodes[code]; ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 // VMDeletedError is returned when a virtual machine is deleted outside of capz.
 type VMDeletedError struct {
 	ProviderID string
@@ -0,0 +1,79 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	. "github.com/onsi/gomega"
+	pkgerrors "github.com/pkg/errors"
+)
+
+func TestCapacityUnavailable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "nil error",
+			err:  nil,
+			want: false,
+		},
+		{
+			name: "generic error",
+			err:  errors.New("foo"),
+			want: false,
+		},
+		{
+			name: "request error with SkuNotAvailable",
+			err: autorest.NewErrorWithError(&azure.RequestError{
+				ServiceError: &azure.ServiceError{Code: "SkuNotAvailable"},
+			}, "compute.VirtualMachineScaleSetsClient", "CreateOrUpdate", nil, "Failure sending request"),
+			want: true,
+		},
+		{
+			name: "wrapped long running operation error with AllocationFailed",
+			err: pkgerrors.Wrap(autorest.NewErrorWithError(&azure.ServiceError{Code: "AllocationFailed"},
+				"compute.VirtualMachineScaleSetsCreateOrUpdateFuture", "Result", nil, "Polling failure"), "failed to get result from future"),
+			want: true,
+		},
+		{
+			name: "long running operation error with ZonalAllocationFailed in details",
+			err: autorest.NewErrorWithError(&azure.ServiceError{
+				Code:    "Conflict",
+				Details: []map[string]interface{}{{"code": "ZonalAllocationFailed"}},
+			}, "compute.VirtualMachineScaleSetsUpdateFuture", "Result", nil, "Polling failure"),
+			want: true,
+		},
+		{
+			name: "service error with another code",
+			err:  autorest.NewErrorWithError(&azure.ServiceError{Code: "InvalidParameter"}, "compute.VirtualMachineScaleSetsUpdateFuture", "Result", nil, "Polling failure"),
+			want: false,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(CapacityUnavailable(tc.err)).To(Equal(tc.want))
+		})
+	}
+}
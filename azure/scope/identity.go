@@ -19,7 +19,10 @@ package scope
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"os"
 	"reflect"
+	"strings"
 
 	aadpodv1 "github.com/Azure/aad-pod-identity/pkg/apis/aadpodidentity/v1"
 	"github.com/Azure/go-autorest/autorest"
@@ -38,7 +41,16 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-const azureSecretKey = "clientSecret"
+const (
+	azureSecretKey = "clientSecret"
+
+	// federatedTokenFileEnvVar is the environment variable set by the Azure Workload Identity webhook with the path of
+	// the projected service account token to exchange for an AAD token.
+	federatedTokenFileEnvVar = "AZURE_FEDERATED_TOKEN_FILE"
+
+	// defaultFederatedTokenFile is the path of the service account token projected in the CAPZ controller manager.
+	defaultFederatedTokenFile = "/var/run/secrets/azure/tokens/azure-identity-token"
+)
 
 // CredentialsProvider defines the behavior for azure identity based credential providers.
 type CredentialsProvider interface {
@@ -87,8 +99,8 @@ func NewAzureClusterCredentialsProvider(ctx context.Context, kubeClient client.C
 		return nil, errors.Errorf("failed to retrieve AzureClusterIdentity external object %q/%q: %v", key.Namespace, key.Name, err)
 	}
 
-	if identity.Spec.Type != infrav1.ServicePrincipal {
-		return nil, errors.New("AzureClusterIdentity is not of type Service Principal")
+	if identity.Spec.Type != infrav1.ServicePrincipal && identity.Spec.Type != infrav1.WorkloadIdentity {
+		return nil, errors.New("AzureClusterIdentity is not of type Service Principal or Workload Identity")
 	}
 
 	return &AzureClusterCredentialsProvider{
@@ -123,8 +135,8 @@ func NewManagedControlPlaneCredentialsProvider(ctx context.Context, kubeClient c
 		return nil, errors.Errorf("failed to retrieve AzureClusterIdentity external object %q/%q: %v", key.Namespace, key.Name, err)
 	}
 
-	if identity.Spec.Type != infrav1.ServicePrincipal {
-		return nil, errors.New("AzureClusterIdentity is not of type Service Principal")
+	if identity.Spec.Type != infrav1.ServicePrincipal && identity.Spec.Type != infrav1.WorkloadIdentity {
+		return nil, errors.New("AzureClusterIdentity is not of type Service Principal or Workload Identity")
 	}
 
 	return &ManagedControlPlaneCredentialsProvider{
@@ -176,6 +188,19 @@ func (p *AzureCredentialsProvider) GetAuthorizer(ctx context.Context, resourceMa
 			return nil, errors.Errorf("failed to get token from service principal identity: %v", err)
 		}
 
+	case infrav1.WorkloadIdentity:
+		oauthConfig, err := adal.NewOAuthConfig(activeDirectoryEndpoint, p.GetTenantID())
+		if err != nil {
+			return nil, err
+		}
+
+		spt, err = adal.NewServicePrincipalTokenWithSecret(*oauthConfig, p.Identity.Spec.ClientID, resourceManagerEndpoint, &federatedTokenSecret{
+			tokenFile: federatedTokenFile(),
+		})
+		if err != nil {
+			return nil, errors.Errorf("failed to get token from workload identity: %v", err)
+		}
+
 	default:
 		return nil, errors.Errorf("identity type %s not supported", p.Identity.Spec.Type)
 	}
@@ -210,6 +235,34 @@ func (p *AzureCredentialsProvider) GetTenantID() string {
 	return p.Identity.Spec.TenantID
 }
 
+// federatedTokenSecret authenticates to AAD with a projected service account token, which a federated credential of
+// the application exchanges for an AAD token.
+type federatedTokenSecret struct {
+	tokenFile string
+}
+
+var _ adal.ServicePrincipalSecret = (*federatedTokenSecret)(nil)
+
+// SetAuthenticationValues sets the service account token as the client assertion of the token request. The token is
+// read on every request, as the kubelet rotates it before it expires.
+func (s *federatedTokenSecret) SetAuthenticationValues(_ *adal.ServicePrincipalToken, v *url.Values) error {
+	token, err := os.ReadFile(s.tokenFile)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read service account token %s", s.tokenFile)
+	}
+	v.Set("client_assertion", strings.TrimSpace(string(token)))
+	v.Set("client_assertion_type", "urn:ietf:params:oauth:client-assertion-type:jwt-bearer")
+	return nil
+}
+
+// federatedTokenFile returns the path of the service account token to exchange for an AAD token.
+func federatedTokenFile() string {
+	if tokenFile := os.Getenv(federatedTokenFileEnvVar); tokenFile != "" {
+		return tokenFile
+	}
+	return defaultFederatedTokenFile
+}
+
 func createAzureIdentityWithBindings(ctx context.Context, azureIdentity *infrav1.AzureClusterIdentity, resourceManagerEndpoint, activeDirectoryEndpoint string, clusterMeta metav1.ObjectMeta,
 	kubeClient client.Client) error {
 	azureIdentityType, err := getAzureIdentityType(azureIdentity)
@@ -18,9 +18,17 @@ package scope
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	aadpodv1 "github.com/Azure/aad-pod-identity/pkg/apis/aadpodidentity/v1"
+	"github.com/Azure/go-autorest/autorest"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -263,3 +271,205 @@ func TestCreateAzureIdentityWithBindings(t *testing.T) {
 		})
 	}
 }
+
+func TestGetAuthorizerWorkloadIdentity(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = infrav1.AddToScheme(scheme)
+	_ = aadpodv1.AddToScheme(scheme)
+
+	tests := []struct {
+		name                    string
+		identity                *infrav1.AzureClusterIdentity
+		activeDirectoryEndpoint string
+		expectedErr             bool
+	}{
+		{
+			name: "workload identity",
+			identity: &infrav1.AzureClusterIdentity{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-identity",
+					Namespace: "default",
+				},
+				Spec: infrav1.AzureClusterIdentitySpec{
+					Type:     infrav1.WorkloadIdentity,
+					ClientID: "my-client-id",
+					TenantID: "my-tenant-id",
+				},
+			},
+			activeDirectoryEndpoint: "https://login.microsoftonline.com/",
+		},
+		{
+			name: "workload identity with an invalid active directory endpoint",
+			identity: &infrav1.AzureClusterIdentity{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-identity",
+					Namespace: "default",
+				},
+				Spec: infrav1.AzureClusterIdentitySpec{
+					Type:     infrav1.WorkloadIdentity,
+					ClientID: "my-client-id",
+					TenantID: "my-tenant-id",
+				},
+			},
+			activeDirectoryEndpoint: "https://login.microsoftonline.com/%zz",
+			expectedErr:             true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(tc.identity).Build()
+			provider := &AzureCredentialsProvider{
+				Client:   fakeClient,
+				Identity: tc.identity,
+			}
+
+			authorizer, err := provider.GetAuthorizer(context.TODO(), "https://management.azure.com/", tc.activeDirectoryEndpoint, metav1.ObjectMeta{Name: "cluster-name", Namespace: "default"})
+			if !tc.expectedErr {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(authorizer).NotTo(BeNil())
+
+				// Workload identities don't need aad-pod-identity.
+				azureIdentities := &aadpodv1.AzureIdentityList{}
+				g.Expect(fakeClient.List(context.TODO(), azureIdentities)).To(Succeed())
+				g.Expect(azureIdentities.Items).To(BeEmpty())
+			} else {
+				g.Expect(err).To(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestGetAuthorizerWorkloadIdentityTokenExchange(t *testing.T) {
+	g := NewWithT(t)
+
+	tokenFile := filepath.Join(t.TempDir(), "azure-identity-token")
+	g.Expect(os.WriteFile(tokenFile, []byte("service-account-token\n"), 0600)).To(Succeed())
+	t.Setenv(federatedTokenFileEnvVar, tokenFile)
+
+	var tokenRequest url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/my-tenant-id/oauth2/token" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		tokenRequest = r.PostForm
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token":"aad-token","token_type":"Bearer","expires_in":"3600","expires_on":"%d","resource":"https://management.azure.com/"}`,
+			time.Now().Add(time.Hour).Unix())
+	}))
+	defer server.Close()
+
+	identity := &infrav1.AzureClusterIdentity{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-identity",
+			Namespace: "default",
+		},
+		Spec: infrav1.AzureClusterIdentitySpec{
+			Type:     infrav1.WorkloadIdentity,
+			ClientID: "my-client-id",
+			TenantID: "my-tenant-id",
+		},
+	}
+	provider := &AzureCredentialsProvider{
+		Identity: identity,
+	}
+
+	authorizer, err := provider.GetAuthorizer(context.TODO(), "https://management.azure.com/", server.URL+"/", metav1.ObjectMeta{Name: "cluster-name", Namespace: "default"})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	req, err := autorest.Prepare(httptest.NewRequest(http.MethodGet, "https://management.azure.com/subscriptions", nil), authorizer.WithAuthorization())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(req.Header.Get("Authorization")).To(Equal("Bearer aad-token"))
+
+	// The service account token is exchanged for the AAD token of the application.
+	g.Expect(tokenRequest.Get("grant_type")).To(Equal("client_credentials"))
+	g.Expect(tokenRequest.Get("client_id")).To(Equal("my-client-id"))
+	g.Expect(tokenRequest.Get("resource")).To(Equal("https://management.azure.com/"))
+	g.Expect(tokenRequest.Get("client_assertion")).To(Equal("service-account-token"))
+	g.Expect(tokenRequest.Get("client_assertion_type")).To(Equal("urn:ietf:params:oauth:client-assertion-type:jwt-bearer"))
+}
+
+func TestFederatedTokenSecret(t *testing.T) {
+	tests := []struct {
+		name               string
+		token              string
+		rotatedToken       string
+		missing            bool
+		expectedAssertions []string
+		expectedErr        bool
+	}{
+		{
+			name:               "service account token",
+			token:              "service-account-token\n",
+			expectedAssertions: []string{"service-account-token"},
+		},
+		{
+			name:               "service account token read again after its rotation",
+			token:              "service-account-token",
+			rotatedToken:       "rotated-service-account-token",
+			expectedAssertions: []string{"service-account-token", "rotated-service-account-token"},
+		},
+		{
+			name:        "missing service account token",
+			missing:     true,
+			expectedErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			tokenFile := filepath.Join(t.TempDir(), "azure-identity-token")
+			if !tc.missing {
+				g.Expect(os.WriteFile(tokenFile, []byte(tc.token), 0600)).To(Succeed())
+			}
+			secret := &federatedTokenSecret{tokenFile: tokenFile}
+
+			values := url.Values{}
+			err := secret.SetAuthenticationValues(nil, &values)
+			if tc.expectedErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(values.Get("client_assertion")).To(Equal(tc.expectedAssertions[0]))
+			g.Expect(values.Get("client_assertion_type")).To(Equal("urn:ietf:params:oauth:client-assertion-type:jwt-bearer"))
+
+			if tc.rotatedToken != "" {
+				g.Expect(os.WriteFile(tokenFile, []byte(tc.rotatedToken), 0600)).To(Succeed())
+				g.Expect(secret.SetAuthenticationValues(nil, &values)).To(Succeed())
+				g.Expect(values.Get("client_assertion")).To(Equal(tc.expectedAssertions[1]))
+			}
+		})
+	}
+}
+
+func TestFederatedTokenFile(t *testing.T) {
+	tests := []struct {
+		name              string
+		tokenFileEnvVar   string
+		expectedTokenFile string
+	}{
+		{
+			name:              "token file of the environment",
+			tokenFileEnvVar:   "/var/run/secrets/other/token",
+			expectedTokenFile: "/var/run/secrets/other/token",
+		},
+		{
+			name:              "default token file",
+			tokenFileEnvVar:   "",
+			expectedTokenFile: defaultFederatedTokenFile,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Setenv(federatedTokenFileEnvVar, tc.tokenFileEnvVar)
+			g.Expect(federatedTokenFile()).To(Equal(tc.expectedTokenFile))
+		})
+	}
+}
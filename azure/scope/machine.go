@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"strings"
 	"time"
 
@@ -27,6 +28,7 @@ import (
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
@@ -34,6 +36,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/disks"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/inboundnatrules"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkinterfaces"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/nodediagnostics"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachines"
 	"sigs.k8s.io/cluster-api-provider-azure/util/futures"
@@ -45,8 +48,12 @@ import (
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
+// NodeDiagnosticsSecretKey is the key of the node diagnostics Secret holding the collected diagnostics.
+const NodeDiagnosticsSecretKey = "diagnostics"
+
 // MachineScopeParams defines the input parameters used to create a new MachineScope.
 type MachineScopeParams struct {
 	Client       client.Client
@@ -93,6 +100,9 @@ type MachineScope struct {
 	Machine      *clusterv1.Machine
 	AzureMachine *infrav1.AzureMachine
 	cache        *MachineCache
+
+	// serialConsoleLogGetter is only used for testing purposes and provides a way for mocking requests to retrieve the serial console log
+	serialConsoleLogGetter nodediagnostics.SerialConsoleLogGetter
 }
 
 // MachineCache stores common machine information so we don't have to hit the API multiple times within the same reconcile loop.
@@ -160,8 +170,11 @@ func (m *MachineScope) VMSpec() azure.ResourceSpecGetter {
 		UserAssignedIdentities: m.AzureMachine.Spec.UserAssignedIdentities,
 		SpotVMOptions:          m.AzureMachine.Spec.SpotVMOptions,
 		SecurityProfile:        m.AzureMachine.Spec.SecurityProfile,
+		DiagnosticsProfile:     m.AzureMachine.Spec.Diagnostics,
 		AdditionalTags:         m.AdditionalTags(),
 		ProviderID:             m.ProviderID(),
+		InPlaceUpdate:          m.AzureMachine.InPlaceUpdateEnabled(),
+		DeallocatedForResize:   m.AzureMachine.Annotations[infrav1.DeallocatedForResizeAnnotation] == "true",
 	}
 	if m.cache != nil {
 		spec.SKU = m.cache.VMSKU
@@ -302,7 +315,10 @@ func (m *MachineScope) RoleAssignmentSpecs() []azure.RoleAssignmentSpec {
 }
 
 // VMExtensionSpecs returns the vm extension specs.
-func (m *MachineScope) VMExtensionSpecs() []azure.ExtensionSpec {
+func (m *MachineScope) VMExtensionSpecs(ctx context.Context) ([]azure.ExtensionSpec, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.MachineScope.VMExtensionSpecs")
+	defer done()
+
 	var extensionSpecs = []azure.ExtensionSpec{}
 	extensionSpec := azure.GetBootstrappingVMExtension(m.AzureMachine.Spec.OSDisk.OSType, m.CloudEnvironment(), m.Name())
 
@@ -310,7 +326,152 @@ func (m *MachineScope) VMExtensionSpecs() []azure.ExtensionSpec {
 		extensionSpecs = append(extensionSpecs, *extensionSpec)
 	}
 
-	return extensionSpecs
+	for _, extension := range m.AzureMachine.Spec.VMExtensions {
+		protectedSettings, err := getVMExtensionProtectedSettings(ctx, m.client, m.Namespace(), extension)
+		if err != nil {
+			return nil, err
+		}
+		extensionSpecs = append(extensionSpecs, azure.ExtensionSpec{
+			Name:              extension.Name,
+			VMName:            m.Name(),
+			Publisher:         extension.Publisher,
+			Type:              extension.Type,
+			Version:           extension.Version,
+			Settings:          extension.Settings,
+			ProtectedSettings: protectedSettings,
+		})
+	}
+
+	return extensionSpecs, nil
+}
+
+// SetVMExtensionStatus records the provisioning state of a VM extension in the AzureMachine status.
+func (m *MachineScope) SetVMExtensionStatus(extensionName string, state infrav1.ProvisioningState) {
+	m.AzureMachine.Status.VMExtensions = setVMExtensionStatus(m.AzureMachine.Status.VMExtensions, extensionName, state)
+}
+
+// NodeDiagnosticsSpec returns the specification for collecting node diagnostics from the VM, or nil if node diagnostics
+// are not enabled, the VM did not fail to bootstrap, or diagnostics were already collected.
+func (m *MachineScope) NodeDiagnosticsSpec() *azure.NodeDiagnosticsSpec {
+	if m.AzureMachine.Spec.NodeDiagnostics == nil || !shouldCollectNodeDiagnostics(m.AzureMachine) {
+		return nil
+	}
+
+	return &azure.NodeDiagnosticsSpec{
+		VMName: m.Name(),
+		OSType: m.AzureMachine.Spec.OSDisk.OSType,
+	}
+}
+
+// NodeDiagnosticsOutput returns where the collected node diagnostics should be stored.
+func (m *MachineScope) NodeDiagnosticsOutput() infrav1.NodeDiagnosticsOutput {
+	if m.AzureMachine.Spec.NodeDiagnostics == nil || m.AzureMachine.Spec.NodeDiagnostics.Output == "" {
+		return infrav1.NodeDiagnosticsOutputEvent
+	}
+	return m.AzureMachine.Spec.NodeDiagnostics.Output
+}
+
+// SetNodeDiagnostics stores the collected node diagnostics in a Secret referenced from the AzureMachine status when
+// the output is Secret, and marks the node diagnostics as collected.
+func (m *MachineScope) SetNodeDiagnostics(ctx context.Context, output string) error {
+	if m.NodeDiagnosticsOutput() == infrav1.NodeDiagnosticsOutputSecret {
+		owner := metav1.OwnerReference{
+			APIVersion: infrav1.GroupVersion.String(),
+			Kind:       "AzureMachine",
+			Name:       m.AzureMachine.Name,
+			UID:        m.AzureMachine.UID,
+		}
+		ref, err := reconcileNodeDiagnosticsSecret(ctx, m.client, owner, m.Namespace(), m.ClusterName(), output)
+		if err != nil {
+			return err
+		}
+		m.AzureMachine.Status.NodeDiagnosticsRef = ref
+	}
+	conditions.MarkTrue(m.AzureMachine, infrav1.NodeDiagnosticsCollectedCondition)
+	return nil
+}
+
+// SetNodeDiagnosticsFailed marks the node diagnostics collection as failed so that it is not attempted again.
+func (m *MachineScope) SetNodeDiagnosticsFailed(err error) {
+	conditions.MarkFalse(m.AzureMachine, infrav1.NodeDiagnosticsCollectedCondition, infrav1.NodeDiagnosticsCollectionFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+}
+
+// BootDiagnosticsEnabled returns true if boot diagnostics are enabled on the VM.
+func (m *MachineScope) BootDiagnosticsEnabled() bool {
+	return bootDiagnosticsEnabled(m.AzureMachine.Spec.Diagnostics)
+}
+
+// GetSerialConsoleLog retrieves the boot diagnostics serial console log of the VM.
+func (m *MachineScope) GetSerialConsoleLog(ctx context.Context) (string, error) {
+	if !m.BootDiagnosticsEnabled() {
+		return "", errors.New("boot diagnostics are disabled")
+	}
+	if m.serialConsoleLogGetter == nil {
+		m.serialConsoleLogGetter = nodediagnostics.NewSerialConsoleLogGetter(m)
+	}
+	return m.serialConsoleLogGetter.GetSerialConsoleLog(ctx, m.ResourceGroup(), m.Name())
+}
+
+// bootDiagnosticsEnabled returns true unless boot diagnostics are explicitly disabled.
+func bootDiagnosticsEnabled(diagnostics *infrav1.Diagnostics) bool {
+	return diagnostics == nil || diagnostics.Boot == nil || diagnostics.Boot.StorageAccountType != infrav1.DisabledDiagnosticsStorage
+}
+
+// shouldCollectNodeDiagnostics returns true if the object failed to bootstrap and node diagnostics were not yet collected.
+func shouldCollectNodeDiagnostics(obj conditions.Getter) bool {
+	return conditions.GetReason(obj, infrav1.BootstrapSucceededCondition) == infrav1.BootstrapFailedReason &&
+		!conditions.Has(obj, infrav1.NodeDiagnosticsCollectedCondition)
+}
+
+// reconcileNodeDiagnosticsSecret creates or updates the Secret holding the node diagnostics collected from a machine.
+func reconcileNodeDiagnosticsSecret(ctx context.Context, c client.Client, owner metav1.OwnerReference, namespace, clusterName, output string) (*corev1.LocalObjectReference, error) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-node-diagnostics", owner.Name),
+			Namespace: namespace,
+		},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, c, secret, func() error {
+		secret.Labels = map[string]string{clusterv1.ClusterLabelName: clusterName}
+		secret.OwnerReferences = []metav1.OwnerReference{owner}
+		secret.Data = map[string][]byte{
+			NodeDiagnosticsSecretKey: []byte(nodediagnostics.Truncate(output, nodediagnostics.MaxSecretOutputLength)),
+		}
+		return nil
+	}); err != nil {
+		return nil, errors.Wrapf(err, "failed to reconcile node diagnostics secret %s/%s", namespace, secret.Name)
+	}
+	return &corev1.LocalObjectReference{Name: secret.Name}, nil
+}
+
+// getVMExtensionProtectedSettings returns the protected settings of a VM extension from the referenced secret, if any.
+func getVMExtensionProtectedSettings(ctx context.Context, c client.Client, namespace string, extension infrav1.VMExtension) (map[string]string, error) {
+	if extension.ProtectedSettingsRef == nil {
+		return nil, nil
+	}
+
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: namespace, Name: extension.ProtectedSettingsRef.Name}
+	if err := c.Get(ctx, key, secret); err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve protected settings secret %s/%s for VM extension %s", namespace, extension.ProtectedSettingsRef.Name, extension.Name)
+	}
+
+	protectedSettings := make(map[string]string, len(secret.Data))
+	for k, v := range secret.Data {
+		protectedSettings[k] = string(v)
+	}
+	return protectedSettings, nil
+}
+
+// setVMExtensionStatus adds or updates the status of the named VM extension.
+func setVMExtensionStatus(statuses []infrav1.VMExtensionStatus, extensionName string, state infrav1.ProvisioningState) []infrav1.VMExtensionStatus {
+	for i := range statuses {
+		if statuses[i].Name == extensionName {
+			statuses[i].ProvisioningState = state
+			return statuses
+		}
+	}
+	return append(statuses, infrav1.VMExtensionStatus{Name: extensionName, ProvisioningState: state})
 }
 
 // Subnet returns the machine's subnet.
@@ -487,6 +648,10 @@ func (m *MachineScope) SetBootstrapConditions(ctx context.Context, provisioningS
 	_, log, done := tele.StartSpanWithLogger(ctx, "scope.MachineScope.SetBootstrapConditions")
 	defer done()
 
+	if !azure.IsBootstrappingVMExtension(extensionName) {
+		return nil
+	}
+
 	switch infrav1.ProvisioningState(provisioningState) {
 	case infrav1.Succeeded:
 		log.V(4).Info("extension provisioning state is succeeded", "vm extension", extensionName, "virtual machine", m.Name())
@@ -513,6 +678,15 @@ func (m *MachineScope) SetAnnotation(key, value string) {
 	m.AzureMachine.Annotations[key] = value
 }
 
+// SetDeallocatedForResize records whether the VM was deallocated to be resized.
+func (m *MachineScope) SetDeallocatedForResize(deallocated bool) {
+	if deallocated {
+		m.SetAnnotation(infrav1.DeallocatedForResizeAnnotation, "true")
+		return
+	}
+	delete(m.AzureMachine.Annotations, infrav1.DeallocatedForResizeAnnotation)
+}
+
 // AnnotationJSON returns a map[string]interface from a JSON annotation.
 func (m *MachineScope) AnnotationJSON(annotation string) (map[string]interface{}, error) {
 	out := map[string]interface{}{}
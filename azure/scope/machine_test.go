@@ -26,16 +26,24 @@ import (
 	autorestazure "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/azure/auth"
 	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/disks"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/inboundnatrules"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/networkinterfaces"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/nodediagnostics/mock_nodediagnostics"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func specArrayToString(specs []azure.ResourceSpecGetter) string {
@@ -460,6 +468,7 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 					Name:      "CAPZ.Linux.Bootstrapping",
 					VMName:    "machine-name",
 					Publisher: "Microsoft.Azure.ContainerUpstream",
+					Type:      "CAPZ.Linux.Bootstrapping",
 					Version:   "1.0",
 					ProtectedSettings: map[string]string{
 						"commandToExecute": azure.LinuxBootstrapExtensionCommand,
@@ -522,6 +531,7 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 					Name:      "CAPZ.Windows.Bootstrapping",
 					VMName:    "machine-name",
 					Publisher: "Microsoft.Azure.ContainerUpstream",
+					Type:      "CAPZ.Windows.Bootstrapping",
 					Version:   "1.0",
 					ProtectedSettings: map[string]string{
 						"commandToExecute": azure.WindowsBootstrapExtensionCommand,
@@ -610,13 +620,356 @@ func TestMachineScope_VMExtensionSpecs(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := tt.machineScope.VMExtensionSpecs(); !reflect.DeepEqual(got, tt.want) {
+			got, err := tt.machineScope.VMExtensionSpecs(context.Background())
+			if err != nil {
+				t.Fatalf("VMExtensionSpecs() returned error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("VMExtensionSpecs() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
+func TestMachineScope_VMExtensionSpecsWithUserExtensions(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-script-settings",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"storageAccountKey": []byte("secret"),
+		},
+	}
+
+	tests := []struct {
+		name       string
+		extensions []infrav1.VMExtension
+		want       []azure.ExtensionSpec
+		wantErr    bool
+	}{
+		{
+			name: "returns user extensions with settings and protected settings from the referenced secret",
+			extensions: []infrav1.VMExtension{
+				{
+					Name:      "my-script",
+					Publisher: "Microsoft.Azure.Extensions",
+					Type:      "CustomScript",
+					Version:   "2.1",
+					Settings: map[string]string{
+						"commandToExecute": "echo hello",
+					},
+					ProtectedSettingsRef: &corev1.LocalObjectReference{Name: "my-script-settings"},
+				},
+				{
+					Name:      "my-monitor",
+					Publisher: "Microsoft.Azure.Monitor",
+					Type:      "AzureMonitorLinuxAgent",
+					Version:   "1.0",
+				},
+			},
+			want: []azure.ExtensionSpec{
+				{
+					Name:      "my-script",
+					VMName:    "machine-name",
+					Publisher: "Microsoft.Azure.Extensions",
+					Type:      "CustomScript",
+					Version:   "2.1",
+					Settings: map[string]string{
+						"commandToExecute": "echo hello",
+					},
+					ProtectedSettings: map[string]string{
+						"storageAccountKey": "secret",
+					},
+				},
+				{
+					Name:      "my-monitor",
+					VMName:    "machine-name",
+					Publisher: "Microsoft.Azure.Monitor",
+					Type:      "AzureMonitorLinuxAgent",
+					Version:   "1.0",
+				},
+			},
+		},
+		{
+			name: "returns an error if the protected settings secret does not exist",
+			extensions: []infrav1.VMExtension{
+				{
+					Name:                 "my-script",
+					Publisher:            "Microsoft.Azure.Extensions",
+					Type:                 "CustomScript",
+					Version:              "2.1",
+					ProtectedSettingsRef: &corev1.LocalObjectReference{Name: "does-not-exist"},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machineScope := MachineScope{
+				client:  fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build(),
+				Machine: &clusterv1.Machine{},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "machine-name",
+						Namespace: "default",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						OSDisk: infrav1.OSDisk{
+							OSType: "Linux",
+						},
+						VMExtensions: tt.extensions,
+					},
+				},
+				ClusterScoper: &ClusterScope{
+					AzureClients: AzureClients{
+						EnvironmentSettings: auth.EnvironmentSettings{
+							Environment: autorestazure.Environment{
+								Name: autorestazure.USGovernmentCloud.Name,
+							},
+						},
+					},
+				},
+			}
+			got, err := machineScope.VMExtensionSpecs(context.Background())
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestMachineScope_SetVMExtensionStatus(t *testing.T) {
+	g := NewWithT(t)
+	machineScope := MachineScope{
+		AzureMachine: &infrav1.AzureMachine{},
+	}
+
+	machineScope.SetVMExtensionStatus("my-script", infrav1.Creating)
+	machineScope.SetVMExtensionStatus("my-monitor", infrav1.Succeeded)
+	machineScope.SetVMExtensionStatus("my-script", infrav1.Failed)
+
+	g.Expect(machineScope.AzureMachine.Status.VMExtensions).To(Equal([]infrav1.VMExtensionStatus{
+		{Name: "my-script", ProvisioningState: infrav1.Failed},
+		{Name: "my-monitor", ProvisioningState: infrav1.Succeeded},
+	}))
+}
+
+func TestMachineScope_NodeDiagnosticsSpec(t *testing.T) {
+	bootstrapFailed := func(m *infrav1.AzureMachine) {
+		conditions.MarkFalse(m, infrav1.BootstrapSucceededCondition, infrav1.BootstrapFailedReason, clusterv1.ConditionSeverityError, "")
+	}
+
+	tests := []struct {
+		name  string
+		setup func(m *infrav1.AzureMachine)
+		want  *azure.NodeDiagnosticsSpec
+	}{
+		{
+			name: "returns nil if node diagnostics are not enabled",
+			setup: func(m *infrav1.AzureMachine) {
+				m.Spec.NodeDiagnostics = nil
+				bootstrapFailed(m)
+			},
+			want: nil,
+		},
+		{
+			name: "returns nil if bootstrap did not fail",
+			setup: func(m *infrav1.AzureMachine) {
+				conditions.MarkFalse(m, infrav1.BootstrapSucceededCondition, infrav1.BootstrapInProgressReason, clusterv1.ConditionSeverityInfo, "")
+			},
+			want: nil,
+		},
+		{
+			name: "returns nil if node diagnostics were already collected",
+			setup: func(m *infrav1.AzureMachine) {
+				bootstrapFailed(m)
+				conditions.MarkTrue(m, infrav1.NodeDiagnosticsCollectedCondition)
+			},
+			want: nil,
+		},
+		{
+			name:  "returns the spec if bootstrap failed",
+			setup: bootstrapFailed,
+			want: &azure.NodeDiagnosticsSpec{
+				VMName: "machine-name",
+				OSType: "Linux",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			azureMachine := &infrav1.AzureMachine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "machine-name",
+				},
+				Spec: infrav1.AzureMachineSpec{
+					OSDisk: infrav1.OSDisk{
+						OSType: "Linux",
+					},
+					NodeDiagnostics: &infrav1.NodeDiagnostics{},
+				},
+			}
+			tt.setup(azureMachine)
+			machineScope := MachineScope{AzureMachine: azureMachine}
+			g.Expect(machineScope.NodeDiagnosticsSpec()).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestMachineScope_GetSerialConsoleLog(t *testing.T) {
+	tests := []struct {
+		name        string
+		diagnostics *infrav1.Diagnostics
+		expect      func(m *mock_nodediagnostics.MockSerialConsoleLogGetterMockRecorder)
+		want        string
+		wantErr     bool
+	}{
+		{
+			name:        "retrieves the serial console log with managed boot diagnostics",
+			diagnostics: nil,
+			expect: func(m *mock_nodediagnostics.MockSerialConsoleLogGetterMockRecorder) {
+				m.GetSerialConsoleLog(gomockinternal.AContext(), "my-rg", "machine-name").Return("serial log", nil)
+			},
+			want: "serial log",
+		},
+		{
+			name: "retrieves the serial console log with user managed boot diagnostics",
+			diagnostics: &infrav1.Diagnostics{
+				Boot: &infrav1.BootDiagnostics{
+					StorageAccountType: infrav1.UserManagedDiagnosticsStorage,
+					UserManaged: &infrav1.UserManagedBootDiagnostics{
+						StorageAccountURI: "https://fake.blob.core.windows.net/",
+					},
+				},
+			},
+			expect: func(m *mock_nodediagnostics.MockSerialConsoleLogGetterMockRecorder) {
+				m.GetSerialConsoleLog(gomockinternal.AContext(), "my-rg", "machine-name").Return("serial log", nil)
+			},
+			want: "serial log",
+		},
+		{
+			name: "returns an error if boot diagnostics are disabled",
+			diagnostics: &infrav1.Diagnostics{
+				Boot: &infrav1.BootDiagnostics{
+					StorageAccountType: infrav1.DisabledDiagnosticsStorage,
+				},
+			},
+			expect:  func(m *mock_nodediagnostics.MockSerialConsoleLogGetterMockRecorder) {},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			getterMock := mock_nodediagnostics.NewMockSerialConsoleLogGetter(mockCtrl)
+			tt.expect(getterMock.EXPECT())
+
+			machineScope := MachineScope{
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "machine-name",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						Diagnostics: tt.diagnostics,
+					},
+				},
+				ClusterScoper: &ClusterScope{
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							ResourceGroup: "my-rg",
+						},
+					},
+				},
+				serialConsoleLogGetter: getterMock,
+			}
+			got, err := machineScope.GetSerialConsoleLog(context.Background())
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestMachineScope_SetNodeDiagnostics(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	tests := []struct {
+		name       string
+		output     infrav1.NodeDiagnosticsOutput
+		wantSecret bool
+	}{
+		{
+			name:       "does not create a secret when the output is Event",
+			output:     infrav1.NodeDiagnosticsOutputEvent,
+			wantSecret: false,
+		},
+		{
+			name:       "creates a secret referenced from the status when the output is Secret",
+			output:     infrav1.NodeDiagnosticsOutputSecret,
+			wantSecret: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+			machineScope := MachineScope{
+				client:  fakeClient,
+				Machine: &clusterv1.Machine{},
+				AzureMachine: &infrav1.AzureMachine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "machine-name",
+						Namespace: "default",
+					},
+					Spec: infrav1.AzureMachineSpec{
+						NodeDiagnostics: &infrav1.NodeDiagnostics{Output: tt.output},
+					},
+				},
+				ClusterScoper: &ClusterScope{
+					Cluster: &clusterv1.Cluster{
+						ObjectMeta: metav1.ObjectMeta{Name: "my-cluster"},
+					},
+				},
+			}
+
+			g.Expect(machineScope.SetNodeDiagnostics(context.Background(), "kubelet failed to start")).To(Succeed())
+			g.Expect(conditions.IsTrue(machineScope.AzureMachine, infrav1.NodeDiagnosticsCollectedCondition)).To(BeTrue())
+
+			secret := &corev1.Secret{}
+			err := fakeClient.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "machine-name-node-diagnostics"}, secret)
+			if !tt.wantSecret {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(machineScope.AzureMachine.Status.NodeDiagnosticsRef).To(BeNil())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(secret.Data).To(HaveKeyWithValue(NodeDiagnosticsSecretKey, []byte("kubelet failed to start")))
+			g.Expect(secret.Labels).To(HaveKeyWithValue(clusterv1.ClusterLabelName, "my-cluster"))
+			g.Expect(machineScope.AzureMachine.Status.NodeDiagnosticsRef).To(Equal(&corev1.LocalObjectReference{Name: "machine-name-node-diagnostics"}))
+		})
+	}
+}
+
 func TestMachineScope_Subnet(t *testing.T) {
 	tests := []struct {
 		name         string
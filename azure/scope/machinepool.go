@@ -18,19 +18,30 @@ package scope
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	machinepool "sigs.k8s.io/cluster-api-provider-azure/azure/scope/strategies/machinepool_deployments"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/util/futures"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -49,6 +60,37 @@ import (
 // added here to avoid a circular dependency.
 const ScalesetsServiceName = "scalesets"
 
+const (
+	// autoscalerMinSizeAnnotation and autoscalerMaxSizeAnnotation are the size of the node group of a MachinePool for
+	// the Cluster API cluster autoscaler provider.
+	autoscalerMinSizeAnnotation = "cluster.x-k8s.io/cluster-api-autoscaler-node-group-min-size"
+	autoscalerMaxSizeAnnotation = "cluster.x-k8s.io/cluster-api-autoscaler-node-group-max-size"
+
+	// the capacity annotations describe the nodes of a MachinePool to the cluster autoscaler when the pool is scaled to
+	// zero, in which case there is no node to build the template node of the node group from.
+	autoscalerCPUAnnotation      = "capacity.cluster-autoscaler.kubernetes.io/cpu"
+	autoscalerMemoryAnnotation   = "capacity.cluster-autoscaler.kubernetes.io/memory"
+	autoscalerGPUCountAnnotation = "capacity.cluster-autoscaler.kubernetes.io/gpu-count"
+	autoscalerGPUTypeAnnotation  = "capacity.cluster-autoscaler.kubernetes.io/gpu-type"
+	autoscalerLabelsAnnotation   = "capacity.cluster-autoscaler.kubernetes.io/labels"
+	autoscalerTaintsAnnotation   = "capacity.cluster-autoscaler.kubernetes.io/taints"
+
+	// autoscalerGPUType is the extended resource the GPUs of a VM size are advertised as.
+	autoscalerGPUType = "nvidia.com/gpu"
+)
+
+// mixedInstancesRetryInterval is the time after which a VM size found restricted or out of capacity is tried again for
+// scale ups of a machine pool with a mixed instances policy.
+const mixedInstancesRetryInterval = 30 * time.Minute
+
+// defaultBlueGreenReadinessTimeout is the time the replacement scale set of a machine pool with the BlueGreen deployment
+// strategy has to get all of its machines ready, unless the strategy specifies it.
+const defaultBlueGreenReadinessTimeout = 30 * time.Minute
+
+// defaultBlueGreenMaxUnavailable is the share of the desired machines of a machine pool with the BlueGreen deployment
+// strategy that are drained and deleted at once from the scale sets it retired, unless the strategy specifies it.
+var defaultBlueGreenMaxUnavailable = intstr.FromString("25%")
+
 type (
 	// MachinePoolScopeParams defines the input parameters used to create a new MachinePoolScope.
 	MachinePoolScopeParams struct {
@@ -66,6 +108,7 @@ type (
 		client           client.Client
 		patchHelper      *patch.Helper
 		vmssState        *azure.VMSS
+		modelDifferences map[string][]infrav1.ModelDifference
 	}
 
 	// NodeStatus represents the status of a Kubernetes node.
@@ -122,10 +165,415 @@ func (m *MachinePoolScope) ScaleSetSpec() azure.ScaleSetSpec {
 		Identity:                     m.AzureMachinePool.Spec.Identity,
 		UserAssignedIdentities:       m.AzureMachinePool.Spec.UserAssignedIdentities,
 		SecurityProfile:              m.AzureMachinePool.Spec.Template.SecurityProfile,
+		DiagnosticsProfile:           m.AzureMachinePool.Spec.Template.Diagnostics,
 		SpotVMOptions:                m.AzureMachinePool.Spec.Template.SpotVMOptions,
 		FailureDomains:               m.MachinePool.Spec.FailureDomains,
 		TerminateNotificationTimeout: m.AzureMachinePool.Spec.Template.TerminateNotificationTimeout,
+		OrchestrationMode:            m.AzureMachinePool.Spec.OrchestrationMode,
+		PlatformFaultDomainCount:     m.AzureMachinePool.Spec.PlatformFaultDomainCount,
+		UpgradePolicy:                m.AzureMachinePool.Spec.UpgradePolicy,
+		AutomaticRepairsPolicy:       m.AzureMachinePool.Spec.AutomaticRepairsPolicy,
+		ApplicationHealth:            m.AzureMachinePool.Spec.Template.ApplicationHealth,
+	}
+}
+
+// ScaleSetSpecs returns the specs of the scale sets backing a machine pool with a mixed instances policy, one for each
+// VM size and priority. It returns nil if the machine pool has no mixed instances policy. The replicas missing from a
+// priority are added to its first VM size that is not unavailable, replicas are never moved between existing scale sets.
+func (m *MachinePoolScope) ScaleSetSpecs() []azure.ScaleSetSpec {
+	policy := m.AzureMachinePool.Spec.MixedInstancesPolicy
+	if policy == nil || m.isBlueGreen() {
+		return nil
+	}
+
+	onDemand, spot := mixedInstancesReplicas(m.DesiredReplicas(), policy)
+	desired := map[infrav1exp.ScaleSetPriority]int32{
+		infrav1exp.RegularScaleSetPriority: onDemand,
+		infrav1exp.SpotScaleSetPriority:    spot,
+	}
+
+	var (
+		specs     []azure.ScaleSetSpec
+		scaleSets = m.mixedInstancesScaleSets()
+	)
+	for _, priority := range []infrav1exp.ScaleSetPriority{infrav1exp.RegularScaleSetPriority, infrav1exp.SpotScaleSetPriority} {
+		var (
+			first   = len(specs)
+			target  = -1
+			current int32
+		)
+		for _, scaleSet := range scaleSets {
+			if scaleSet.Priority != priority {
+				continue
+			}
+
+			spec := m.ScaleSetSpec()
+			spec.Name = scaleSet.Name
+			spec.Size = scaleSet.VMSize
+			spec.Capacity = int64(scaleSet.Replicas)
+			spec.SpotVMOptions = nil
+			if priority == infrav1exp.SpotScaleSetPriority {
+				spec.SpotVMOptions = &infrav1.SpotVMOptions{}
+				if m.AzureMachinePool.Spec.Template.SpotVMOptions != nil {
+					spec.SpotVMOptions = m.AzureMachinePool.Spec.Template.SpotVMOptions
+				}
+			}
+
+			if target < 0 && !isScaleSetUnavailable(scaleSet) {
+				target = len(specs)
+			}
+			current += scaleSet.Replicas
+			specs = append(specs, spec)
+		}
+
+		if target < 0 {
+			// all the VM sizes are unavailable, keep trying the preferred one
+			target = first
+		}
+
+		if missing := desired[priority] - current; missing > 0 {
+			specs[target].Capacity += int64(missing)
+		}
+	}
+
+	return specs
+}
+
+// SetScaleSetUnavailable records that the VM size of a scale set backing a machine pool with a mixed instances policy
+// is restricted or out of capacity.
+func (m *MachinePoolScope) SetScaleSetUnavailable(name string) {
+	now := metav1.Now()
+	scaleSets := m.mixedInstancesScaleSets()
+	for i := range scaleSets {
+		if scaleSets[i].Name == name {
+			scaleSets[i].UnavailableSince = &now
+		}
+	}
+
+	m.setScaleSetsStatus(scaleSets)
+}
+
+// SetScaleSetDeleted records that a scale set backing a machine pool with the BlueGreen deployment strategy no longer
+// exists. The scale sets of a mixed instances policy are tracked through their replicas instead.
+func (m *MachinePoolScope) SetScaleSetDeleted(name string) {
+	status := m.AzureMachinePool.Status.BlueGreen
+	if status == nil {
+		return
+	}
+
+	status.RetiredScaleSets = withoutScaleSet(status.RetiredScaleSets, name)
+}
+
+// withoutScaleSet returns the names of the scale sets other than the given one.
+func withoutScaleSet(names []string, name string) []string {
+	var remaining []string
+	for _, scaleSet := range names {
+		if scaleSet != name {
+			remaining = append(remaining, scaleSet)
+		}
+	}
+	return remaining
+}
+
+// SetScaleSetStates updates the machine pool scope with the current state of the scale sets backing a machine pool
+// with a mixed instances policy. The scale sets are presented as a single VMSS holding the instances of all of them.
+func (m *MachinePoolScope) SetScaleSetStates(states []*azure.VMSS) {
+	statesByName := make(map[string]*azure.VMSS, len(states))
+	for _, state := range states {
+		statesByName[state.Name] = state
+	}
+
+	scaleSets := m.mixedInstancesScaleSets()
+	for i := range scaleSets {
+		scaleSets[i].Replicas = 0
+		if state, ok := statesByName[scaleSets[i].Name]; ok {
+			for _, instance := range state.Instances {
+				// failed instances are replaced by instances of the next VM size, and removed by the delete strategy
+				if instance.State != infrav1.Failed {
+					scaleSets[i].Replicas++
+				}
+			}
+		}
+
+		if scaleSets[i].UnavailableSince != nil && !isScaleSetUnavailable(scaleSets[i]) {
+			scaleSets[i].UnavailableSince = nil
+		}
+	}
+
+	m.setScaleSetsStatus(scaleSets)
+
+	m.modelDifferences = instanceModelDifferences(states...)
+	if len(states) == 0 {
+		m.vmssState = nil
+		return
+	}
+
+	merged := *states[0]
+	merged.Capacity = 0
+	merged.Instances = nil
+	for _, state := range states {
+		merged.Capacity += state.Capacity
+		merged.Instances = append(merged.Instances, state.Instances...)
+		if merged.State == infrav1.Succeeded {
+			merged.State = state.State
+		}
+	}
+
+	m.vmssState = &merged
+}
+
+// mixedInstancesScaleSets returns the last observed status of each of the scale sets backing a machine pool with a
+// mixed instances policy, ordered by priority and by preference of VM size.
+func (m *MachinePoolScope) mixedInstancesScaleSets() []infrav1exp.AzureMachinePoolScaleSetStatus {
+	policy := m.AzureMachinePool.Spec.MixedInstancesPolicy
+	if policy == nil {
+		return nil
+	}
+
+	observed := make(map[string]infrav1exp.AzureMachinePoolScaleSetStatus, len(m.AzureMachinePool.Status.ScaleSets))
+	for _, scaleSet := range m.AzureMachinePool.Status.ScaleSets {
+		observed[scaleSet.Name] = scaleSet
+	}
+
+	vmSizes := append([]string{m.AzureMachinePool.Spec.Template.VMSize}, policy.FallbackVMSizes...)
+	scaleSets := make([]infrav1exp.AzureMachinePoolScaleSetStatus, 0, 2*len(vmSizes))
+	for _, priority := range []infrav1exp.ScaleSetPriority{infrav1exp.RegularScaleSetPriority, infrav1exp.SpotScaleSetPriority} {
+		for _, vmSize := range vmSizes {
+			name := azure.GenerateMixedInstancesScaleSetName(m.Name(), vmSize, priority == infrav1exp.SpotScaleSetPriority)
+			scaleSet, ok := observed[name]
+			if !ok {
+				scaleSet = infrav1exp.AzureMachinePoolScaleSetStatus{
+					Name:     name,
+					VMSize:   vmSize,
+					Priority: priority,
+				}
+			}
+			scaleSets = append(scaleSets, scaleSet)
+		}
+	}
+
+	return scaleSets
+}
+
+// setScaleSetsStatus records the scale sets which have replicas or are unavailable in the AzureMachinePool status.
+func (m *MachinePoolScope) setScaleSetsStatus(scaleSets []infrav1exp.AzureMachinePoolScaleSetStatus) {
+	var status []infrav1exp.AzureMachinePoolScaleSetStatus
+	for _, scaleSet := range scaleSets {
+		if scaleSet.Replicas > 0 || scaleSet.UnavailableSince != nil {
+			status = append(status, scaleSet)
+		}
+	}
+
+	m.AzureMachinePool.Status.ScaleSets = status
+}
+
+// scaleSetNames returns the names of the scale sets backing the machine pool.
+func (m *MachinePoolScope) scaleSetNames() []string {
+	if m.isBlueGreen() {
+		status := m.blueGreenStatus()
+		names := []string{status.ActiveScaleSet}
+		if status.ReplacementScaleSet != "" {
+			names = append(names, status.ReplacementScaleSet)
+		}
+		return append(names, status.RetiredScaleSets...)
+	}
+
+	if m.AzureMachinePool.Spec.MixedInstancesPolicy == nil {
+		return []string{m.Name()}
+	}
+
+	scaleSets := m.mixedInstancesScaleSets()
+	names := make([]string, len(scaleSets))
+	for i, scaleSet := range scaleSets {
+		names[i] = scaleSet.Name
+	}
+	return names
+}
+
+// isScaleSetUnavailable returns true if the VM size of the scale set was found restricted or out of capacity within the
+// retry interval.
+func isScaleSetUnavailable(scaleSet infrav1exp.AzureMachinePoolScaleSetStatus) bool {
+	return scaleSet.UnavailableSince != nil && time.Since(scaleSet.UnavailableSince.Time) < mixedInstancesRetryInterval
+}
+
+// mixedInstancesReplicas splits the replicas of a machine pool between regular and Spot VMs according to its mixed
+// instances policy.
+func mixedInstancesReplicas(replicas int32, policy *infrav1exp.MixedInstancesPolicy) (onDemand, spot int32) {
+	base := to.Int32(policy.OnDemandBaseCapacity)
+	if base > replicas {
+		base = replicas
+	}
+
+	spot = (replicas - base) * to.Int32(policy.SpotPercentageAboveBaseCapacity) / 100
+	return replicas - spot, spot
+}
+
+// hasMultipleScaleSets returns true if the machine pool may be backed by several scale sets, whose instance IDs are not
+// unique within the machine pool.
+func (m *MachinePoolScope) hasMultipleScaleSets() bool {
+	return m.AzureMachinePool.Spec.MixedInstancesPolicy != nil || m.isBlueGreen()
+}
+
+// isBlueGreen returns true if the machine pool uses the BlueGreen deployment strategy.
+func (m *MachinePoolScope) isBlueGreen() bool {
+	return m.AzureMachinePool.Spec.Strategy.Type == infrav1exp.BlueGreenAzureMachinePoolDeploymentStrategyType
+}
+
+// blueGreenStatus returns a copy of the status of the blue/green deployment of the machine pool, or the status of a
+// deployment starting from the current scale set of the machine pool if it has not been recorded yet. The status is
+// only recorded by reconcileBlueGreen.
+func (m *MachinePoolScope) blueGreenStatus() *infrav1exp.AzureMachinePoolBlueGreenStatus {
+	if m.AzureMachinePool.Status.BlueGreen == nil {
+		return &infrav1exp.AzureMachinePoolBlueGreenStatus{
+			ActiveScaleSet: m.Name(),
+			ActiveRevision: m.modelRevision(),
+		}
+	}
+
+	return m.AzureMachinePool.Status.BlueGreen.DeepCopy()
+}
+
+// modelRevision returns a hash of the inputs of the scale set model. A machine pool with the BlueGreen deployment
+// strategy rolls out a new scale set whenever the revision changes.
+func (m *MachinePoolScope) modelRevision() string {
+	h := fnv.New32a()
+	inputs := struct {
+		Template       infrav1exp.AzureMachinePoolMachineTemplate
+		Version        *string
+		DataSecretName *string
+	}{
+		Template:       m.AzureMachinePool.Spec.Template,
+		Version:        m.MachinePool.Spec.Template.Spec.Version,
+		DataSecretName: m.MachinePool.Spec.Template.Spec.Bootstrap.DataSecretName,
+	}
+	if err := json.NewEncoder(h).Encode(inputs); err != nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%08x", h.Sum32())
+}
+
+// BlueGreenScaleSetSpecs returns the specs of the scale sets backing a machine pool with the BlueGreen deployment
+// strategy, one for each model being rolled out or retired. It returns nil if the machine pool uses another deployment
+// strategy. Only the scale set matching the latest model is created or updated, the others are observed until their
+// machines are drained and deleted.
+func (m *MachinePoolScope) BlueGreenScaleSetSpecs() []azure.ScaleSetSpec {
+	if !m.isBlueGreen() {
+		return nil
+	}
+
+	var (
+		status   = m.blueGreenStatus()
+		revision = m.modelRevision()
+	)
+
+	active := m.ScaleSetSpec()
+	active.Name = status.ActiveScaleSet
+	active.ObserveOnly = status.ActiveRevision != revision
+	specs := []azure.ScaleSetSpec{active}
+
+	if status.ReplacementScaleSet != "" {
+		replacement := m.ScaleSetSpec()
+		replacement.Name = status.ReplacementScaleSet
+		replacement.ObserveOnly = status.ReplacementRevision != revision
+		specs = append(specs, replacement)
+	}
+
+	for _, name := range status.RetiredScaleSets {
+		retired := m.ScaleSetSpec()
+		retired.Name = name
+		retired.Capacity = 0
+		retired.ObserveOnly = true
+		specs = append(specs, retired)
 	}
+
+	return specs
+}
+
+// reconcileBlueGreen moves the blue/green deployment of the machine pool forward. A replacement scale set is rolled out
+// when the model changes, it is promoted once all of its machines stayed ready for the soak duration, and it is rolled
+// back if its machines are not ready within the readiness timeout. The active scale set is retired when replaced.
+func (m *MachinePoolScope) reconcileBlueGreen(ctx context.Context, machinesByProviderID map[string]infrav1exp.AzureMachinePoolMachine) {
+	_, log, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.reconcileBlueGreen")
+	defer done()
+
+	var (
+		status           = m.blueGreenStatus()
+		revision         = m.modelRevision()
+		now              = metav1.Now()
+		soakDuration     time.Duration
+		readinessTimeout = defaultBlueGreenReadinessTimeout
+	)
+	if blueGreen := m.AzureMachinePool.Spec.Strategy.BlueGreen; blueGreen != nil {
+		if blueGreen.SoakDuration != nil {
+			soakDuration = blueGreen.SoakDuration.Duration
+		}
+		if blueGreen.ReadinessTimeout != nil {
+			readinessTimeout = blueGreen.ReadinessTimeout.Duration
+		}
+	}
+
+	defer func() {
+		m.AzureMachinePool.Status.BlueGreen = status
+	}()
+
+	if status.ReplacementScaleSet != "" && status.ReplacementRevision != revision {
+		log.Info("retiring the replacement scale set of an outdated model", "scale set", status.ReplacementScaleSet, "revision", status.ReplacementRevision)
+		retireBlueGreenReplacement(status)
+	}
+
+	if status.ReplacementScaleSet == "" && revision != status.ActiveRevision && revision != status.FailedRevision {
+		name := azure.GenerateBlueGreenScaleSetName(m.Name(), revision)
+		log.Info("rolling out a replacement scale set", "scale set", name, "revision", revision)
+		// a model retired shortly before is rolled out again on its own scale set
+		status.RetiredScaleSets = withoutScaleSet(status.RetiredScaleSets, name)
+		status.ReplacementScaleSet = name
+		status.ReplacementRevision = revision
+		status.ReplacementStartTime = &now
+		status.ReplacementReadyTime = nil
+	}
+
+	if status.ReplacementScaleSet == "" {
+		return
+	}
+
+	var ready int32
+	for _, machine := range machinesByProviderID {
+		if strings.EqualFold(scaleSetNameFromProviderID(machine.Spec.ProviderID), status.ReplacementScaleSet) &&
+			machine.Status.Ready && machine.Status.ProvisioningState != nil && *machine.Status.ProvisioningState == infrav1.Succeeded {
+			ready++
+		}
+	}
+
+	switch {
+	case ready >= m.DesiredReplicas():
+		if status.ReplacementReadyTime == nil {
+			status.ReplacementReadyTime = &now
+		}
+
+		if now.Sub(status.ReplacementReadyTime.Time) >= soakDuration {
+			log.Info("promoting the replacement scale set", "scale set", status.ReplacementScaleSet, "replaced", status.ActiveScaleSet)
+			status.RetiredScaleSets = append(status.RetiredScaleSets, status.ActiveScaleSet)
+			status.ActiveScaleSet = status.ReplacementScaleSet
+			status.ActiveRevision = status.ReplacementRevision
+			status.ReplacementScaleSet, status.ReplacementRevision = "", ""
+			status.ReplacementStartTime, status.ReplacementReadyTime = nil, nil
+		}
+	case now.Sub(status.ReplacementStartTime.Time) >= readinessTimeout:
+		log.Info("rolling back the replacement scale set, its machines are not ready", "scale set", status.ReplacementScaleSet, "readyMachines", ready, "readinessTimeout", readinessTimeout)
+		status.FailedRevision = status.ReplacementRevision
+		retireBlueGreenReplacement(status)
+	default:
+		// the soak period starts over once all the machines are ready again
+		status.ReplacementReadyTime = nil
+	}
+}
+
+// retireBlueGreenReplacement retires the replacement scale set of a blue/green deployment.
+func retireBlueGreenReplacement(status *infrav1exp.AzureMachinePoolBlueGreenStatus) {
+	status.RetiredScaleSets = append(status.RetiredScaleSets, status.ReplacementScaleSet)
+	status.ReplacementScaleSet, status.ReplacementRevision = "", ""
+	status.ReplacementStartTime, status.ReplacementReadyTime = nil, nil
 }
 
 // Name returns the Azure Machine Pool Name.
@@ -162,6 +610,86 @@ func (m *MachinePoolScope) ProvisioningState() infrav1.ProvisioningState {
 // SetVMSSState updates the machine pool scope with the current state of the VMSS.
 func (m *MachinePoolScope) SetVMSSState(vmssState *azure.VMSS) {
 	m.vmssState = vmssState
+	m.modelDifferences = instanceModelDifferences(vmssState)
+}
+
+// instanceModelDifferences returns the parts of the model of their scale set the instances differ in, by provider ID.
+// Each instance is compared with the model of its own scale set, as the scale sets backing a machine pool may differ.
+func instanceModelDifferences(states ...*azure.VMSS) map[string][]infrav1.ModelDifference {
+	differences := make(map[string][]infrav1.ModelDifference)
+	for _, state := range states {
+		if state == nil {
+			continue
+		}
+		for _, instance := range state.Instances {
+			if d := state.ModelDifferences(instance); len(d) > 0 {
+				differences[instance.ProviderID()] = d
+			}
+		}
+	}
+
+	return differences
+}
+
+// modelDifferencesSummary returns a summary of the parts of the scale set model the instances of the machine pool
+// differ in, or an empty string if all the instances run the latest model.
+func (m *MachinePoolScope) modelDifferencesSummary() string {
+	if m.vmssState == nil || len(m.modelDifferences) == 0 {
+		return ""
+	}
+
+	counts := make(map[infrav1.ModelDifference]int)
+	for _, differences := range m.modelDifferences {
+		for _, difference := range differences {
+			counts[difference]++
+		}
+	}
+
+	var parts []string
+	for _, difference := range []infrav1.ModelDifference{
+		infrav1.ModelDifferenceImage,
+		infrav1.ModelDifferenceVMSize,
+		infrav1.ModelDifferenceExtensions,
+		infrav1.ModelDifferenceTags,
+		infrav1.ModelDifferenceBootstrapData,
+		infrav1.ModelDifferenceUnknown,
+	} {
+		if counts[difference] > 0 {
+			parts = append(parts, fmt.Sprintf("%s (%d)", difference, counts[difference]))
+		}
+	}
+
+	return fmt.Sprintf("%d of %d instances differ from the scale set model in %s",
+		len(m.modelDifferences), len(m.vmssState.Instances), strings.Join(parts, ", "))
+}
+
+// updateMachineModelDifferences records on the AzureMachinePoolMachine the parts of the scale set model its instance
+// differs in. Only the AzureMachinePool controller observes the model of the VMSS, so it owns this part of the status
+// of the AzureMachinePoolMachines.
+func (m *MachinePoolScope) updateMachineModelDifferences(ctx context.Context, machine *infrav1exp.AzureMachinePoolMachine) error {
+	differences := m.modelDifferences[machine.Spec.ProviderID]
+	if isBootstrapDataOutdated(m.AzureMachinePool, machine) {
+		// the bootstrap data explains the difference Azure reports when none of the compared parts differ
+		explained := make([]infrav1.ModelDifference, 0, len(differences)+1)
+		for _, difference := range differences {
+			if difference != infrav1.ModelDifferenceUnknown {
+				explained = append(explained, difference)
+			}
+		}
+		differences = append(explained, infrav1.ModelDifferenceBootstrapData)
+		if m.modelDifferences == nil {
+			m.modelDifferences = make(map[string][]infrav1.ModelDifference)
+		}
+		m.modelDifferences[machine.Spec.ProviderID] = differences
+	}
+
+	if reflect.DeepEqual(machine.Status.ModelDifferences, differences) {
+		return nil
+	}
+
+	before := machine.DeepCopy()
+	machine.Status.ModelDifferences = differences
+	return m.client.Status().Patch(ctx, machine, client.MergeFrom(before))
 }
 
 // NeedsRequeue return true if any machines are not on the latest model or the VMSS is not in a terminal provisioning
@@ -263,6 +791,10 @@ func (m *MachinePoolScope) applyAzureMachinePoolMachines(ctx context.Context) er
 		existingMachinesByProviderID[machine.Spec.ProviderID] = machine
 	}
 
+	if m.isBlueGreen() {
+		m.reconcileBlueGreen(ctx, existingMachinesByProviderID)
+	}
+
 	// determine which machines need to be created to reflect the current state in Azure
 	azureMachinesByProviderID := m.vmssState.InstancesByProviderID()
 	for key, val := range azureMachinesByProviderID {
@@ -289,16 +821,43 @@ func (m *MachinePoolScope) applyAzureMachinePoolMachines(ctx context.Context) er
 		}
 	}
 
+	for key, machine := range existingMachinesByProviderID {
+		machine := machine
+		if err := m.updateMachineModelDifferences(ctx, &machine); err != nil {
+			return errors.Wrap(err, "failed to update the model differences of AzureMachinePoolMachine")
+		}
+		existingMachinesByProviderID[key] = machine
+	}
+
 	if deleted {
 		log.V(4).Info("exiting early due to finding AzureMachinePoolMachine(s) that were deleted because they no longer exist in the VMSS")
 		// exit early to be less greedy about delete
 		return nil
 	}
 
-	if futures.Has(m.AzureMachinePool, m.Name(), ScalesetsServiceName) {
-		log.V(4).Info("exiting early due an in-progress long running operation on the ScaleSet")
-		// exit early to be less greedy about delete
-		return nil
+	for _, name := range m.scaleSetNames() {
+		if futures.Has(m.AzureMachinePool, name, ScalesetsServiceName) {
+			log.V(4).Info("exiting early due an in-progress long running operation on the ScaleSet", "scale set", name)
+			// exit early to be less greedy about delete
+			return nil
+		}
+	}
+
+	// the failure domain is only observed by the AzureMachinePoolMachine controller, fill it in for machines which
+	// have not been reconciled yet so the delete selection can balance them across zones
+	for key, machine := range existingMachinesByProviderID {
+		if machine.Status.FailureDomain == "" {
+			machine.Status.FailureDomain = azureMachinesByProviderID[key].AvailabilityZone
+			existingMachinesByProviderID[key] = machine
+		}
+	}
+
+	if m.isBlueGreen() {
+		// the machines of the retired scale sets are drained and deleted within the budget of the strategy, the delete
+		// strategy only applies to the machines of the active scale set
+		if err := m.retireBlueGreenMachines(ctx, existingMachinesByProviderID); err != nil {
+			return err
+		}
 	}
 
 	deleteSelector := m.getDeploymentStrategy()
@@ -308,7 +867,7 @@ func (m *MachinePoolScope) applyAzureMachinePoolMachines(ctx context.Context) er
 	}
 
 	// select machines to delete to lower the replica count
-	toDelete, err := deleteSelector.SelectMachinesToDelete(ctx, m.DesiredReplicas(), existingMachinesByProviderID)
+	toDelete, err := m.selectMachinesToDelete(ctx, deleteSelector, existingMachinesByProviderID)
 	if err != nil {
 		return errors.Wrap(err, "failed selecting AzureMachinePoolMachine(s) to delete")
 	}
@@ -325,6 +884,148 @@ func (m *MachinePoolScope) applyAzureMachinePoolMachines(ctx context.Context) er
 	return nil
 }
 
+// selectMachinesToDelete selects the machines to delete to lower the replica count. The machines of a pool with a mixed
+// instances policy are selected separately for regular and Spot VMs, against the replicas of each priority, so that
+// scaling down restores the split between regular and Spot VMs of the policy.
+func (m *MachinePoolScope) selectMachinesToDelete(ctx context.Context, deleteSelector machinepool.DeleteSelector, machinesByProviderID map[string]infrav1exp.AzureMachinePoolMachine) ([]infrav1exp.AzureMachinePoolMachine, error) {
+	policy := m.AzureMachinePool.Spec.MixedInstancesPolicy
+	if policy == nil || m.isBlueGreen() {
+		return deleteSelector.SelectMachinesToDelete(ctx, m.DesiredReplicas(), machinesByProviderID)
+	}
+
+	priorities := make(map[string]infrav1exp.ScaleSetPriority)
+	for _, scaleSet := range m.mixedInstancesScaleSets() {
+		priorities[strings.ToLower(scaleSet.Name)] = scaleSet.Priority
+	}
+
+	machinesByPriority := map[infrav1exp.ScaleSetPriority]map[string]infrav1exp.AzureMachinePoolMachine{
+		infrav1exp.RegularScaleSetPriority: {},
+		infrav1exp.SpotScaleSetPriority:    {},
+	}
+	for key, machine := range machinesByProviderID {
+		priority, ok := priorities[strings.ToLower(scaleSetNameFromProviderID(machine.Spec.ProviderID))]
+		if !ok {
+			// the scale set of the machine no longer matches the policy, count it as a regular VM
+			priority = infrav1exp.RegularScaleSetPriority
+		}
+		machinesByPriority[priority][key] = machine
+	}
+
+	onDemand, spot := mixedInstancesReplicas(m.DesiredReplicas(), policy)
+	desired := map[infrav1exp.ScaleSetPriority]int32{
+		infrav1exp.RegularScaleSetPriority: onDemand,
+		infrav1exp.SpotScaleSetPriority:    spot,
+	}
+
+	var toDelete []infrav1exp.AzureMachinePoolMachine
+	for _, priority := range []infrav1exp.ScaleSetPriority{infrav1exp.RegularScaleSetPriority, infrav1exp.SpotScaleSetPriority} {
+		selected, err := deleteSelector.SelectMachinesToDelete(ctx, desired[priority], machinesByPriority[priority])
+		if err != nil {
+			return nil, err
+		}
+		toDelete = append(toDelete, selected...)
+	}
+
+	return toDelete, nil
+}
+
+// retireBlueGreenMachines deletes the machines of the scale sets retired from the blue/green deployment of the machine
+// pool, oldest first, without exceeding the number of machines the strategy allows to be drained and deleted at once.
+// All the machines which are not part of the active scale set are removed from machinesByProviderID.
+func (m *MachinePoolScope) retireBlueGreenMachines(ctx context.Context, machinesByProviderID map[string]infrav1exp.AzureMachinePoolMachine) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.retireBlueGreenMachines")
+	defer done()
+
+	budget, err := m.blueGreenMaxUnavailable()
+	if err != nil {
+		return err
+	}
+
+	var (
+		status   = m.blueGreenStatus()
+		retiring []infrav1exp.AzureMachinePoolMachine
+	)
+	for key, machine := range machinesByProviderID {
+		scaleSetName := scaleSetNameFromProviderID(machine.Spec.ProviderID)
+		if strings.EqualFold(scaleSetName, status.ActiveScaleSet) {
+			continue
+		}
+
+		delete(machinesByProviderID, key)
+		switch {
+		case !isRetiredScaleSet(status, scaleSetName):
+			continue
+		case !machine.DeletionTimestamp.IsZero():
+			budget--
+		case machinepool.IsProtected(machine):
+			// the retired scale set is kept until the protection of its remaining machines is removed
+			log.V(4).Info("not deleting AzureMachinePoolMachine of a retired scale set protected from scale-in", "providerID", key, "scale set", scaleSetName)
+		default:
+			retiring = append(retiring, machine)
+		}
+	}
+
+	sort.Slice(retiring, func(i, j int) bool {
+		if !retiring[i].CreationTimestamp.Equal(&retiring[j].CreationTimestamp) {
+			return retiring[i].CreationTimestamp.Before(&retiring[j].CreationTimestamp)
+		}
+		return retiring[i].Name < retiring[j].Name
+	})
+
+	for i, machine := range retiring {
+		machine := machine
+		if budget <= 0 {
+			log.V(4).Info("waiting for the machines of the retired scale sets being deleted before deleting more", "remaining", len(retiring)-i)
+			return nil
+		}
+
+		log.Info("deleting AzureMachinePoolMachine of a retired scale set", "providerID", machine.Spec.ProviderID, "scale set", scaleSetNameFromProviderID(machine.Spec.ProviderID))
+		if err := m.client.Delete(ctx, &machine); err != nil {
+			return errors.Wrap(err, "failed deleting AzureMachinePoolMachine of a retired scale set")
+		}
+		budget--
+	}
+
+	return nil
+}
+
+// blueGreenMaxUnavailable returns the number of machines of the scale sets retired from the blue/green deployment of
+// the machine pool that may be drained and deleted at once. It is at least 1, so that the retired scale sets are
+// deleted even if the machine pool is scaled to zero.
+func (m *MachinePoolScope) blueGreenMaxUnavailable() (int, error) {
+	maxUnavailable := &defaultBlueGreenMaxUnavailable
+	if blueGreen := m.AzureMachinePool.Spec.Strategy.BlueGreen; blueGreen != nil && blueGreen.MaxUnavailable != nil {
+		maxUnavailable = blueGreen.MaxUnavailable
+	}
+
+	val, err := intstr.GetScaledValueFromIntOrPercent(maxUnavailable, int(m.DesiredReplicas()), true)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get scaled value or int from the blue/green maxUnavailable")
+	}
+
+	if val < 1 {
+		return 1, nil
+	}
+	return val, nil
+}
+
+// isRetiredScaleSet returns true if the scale set is retired from the blue/green deployment of the machine pool.
+func isRetiredScaleSet(status *infrav1exp.AzureMachinePoolBlueGreenStatus, name string) bool {
+	for _, retired := range status.RetiredScaleSets {
+		if strings.EqualFold(retired, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// azureMachinePoolMachineName returns the name of the AzureMachinePoolMachine for an instance of the scale set. Instances
+// of a Flexible scale set are identified by their VM name, which may contain underscores and upper case characters that
+// are not allowed in Kubernetes object names.
+func azureMachinePoolMachineName(poolName, instanceID string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.Join([]string{poolName, instanceID}, "-"), "_", "-"))
+}
+
 func (m *MachinePoolScope) createMachine(ctx context.Context, machine azure.VMSSVM) error {
 	if machine.InstanceID == "" {
 		return errors.New("machine.InstanceID must not be empty")
@@ -334,9 +1035,16 @@ func (m *MachinePoolScope) createMachine(ctx context.Context, machine azure.VMSS
 		return errors.New("machine.Name must not be empty")
 	}
 
+	// instance IDs are only unique within a scale set, the instances of a pool backed by several scale sets are
+	// identified by their computer name instead
+	instanceName := machine.InstanceID
+	if m.hasMultipleScaleSets() {
+		instanceName = machine.Name
+	}
+
 	ampm := infrav1exp.AzureMachinePoolMachine{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      strings.Join([]string{m.AzureMachinePool.Name, machine.InstanceID}, "-"),
+			Name:      azureMachinePoolMachineName(m.AzureMachinePool.Name, instanceName),
 			Namespace: m.AzureMachinePool.Namespace,
 			OwnerReferences: []metav1.OwnerReference{
 				{
@@ -359,6 +1067,11 @@ func (m *MachinePoolScope) createMachine(ctx context.Context, machine azure.VMSS
 		},
 	}
 
+	// the instance is created from the model of the scale set, which holds the bootstrap data last applied to it
+	if hash, ok := m.AzureMachinePool.Annotations[infrav1exp.BootstrapDataHashAnnotation]; ok {
+		ampm.Annotations = map[string]string{infrav1exp.BootstrapDataHashAnnotation: hash}
+	}
+
 	controllerutil.AddFinalizer(&ampm, infrav1exp.AzureMachinePoolMachineFinalizer)
 	conditions.MarkFalse(&ampm, infrav1.VMRunningCondition, string(infrav1.Creating), clusterv1.ConditionSeverityInfo, "")
 	if err := m.client.Create(ctx, &ampm); err != nil {
@@ -417,6 +1130,10 @@ func (m *MachinePoolScope) setProvisioningStateAndConditions(v infrav1.Provision
 		conditions.MarkFalse(m.AzureMachinePool, infrav1.ScaleSetRunningCondition, string(v), clusterv1.ConditionSeverityInfo, "")
 		m.SetNotReady()
 	}
+
+	if summary := m.modelDifferencesSummary(); summary != "" {
+		conditions.MarkFalse(m.AzureMachinePool, infrav1.ScaleSetModelUpdatedCondition, infrav1.ScaleSetModelOutOfDateReason, clusterv1.ConditionSeverityInfo, "%s", summary)
+	}
 }
 
 // SetReady sets the AzureMachinePool Ready Status to true.
@@ -444,6 +1161,10 @@ func (m *MachinePoolScope) SetBootstrapConditions(ctx context.Context, provision
 	_, log, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.SetBootstrapConditions")
 	defer done()
 
+	if !azure.IsBootstrappingVMExtension(extensionName) {
+		return nil
+	}
+
 	switch infrav1.ProvisioningState(provisioningState) {
 	case infrav1.Succeeded:
 		log.V(4).Info("extension provisioning state is succeeded", "vm extension", extensionName, "scale set", m.Name())
@@ -534,6 +1255,52 @@ func (m *MachinePoolScope) GetBootstrapData(ctx context.Context) (string, error)
 	return base64.StdEncoding.EncodeToString(value), nil
 }
 
+// HasBootstrapDataChanges returns true if the bootstrap data differs from the one last applied to the model of the scale
+// sets of the machine pool.
+func (m *MachinePoolScope) HasBootstrapDataChanges(ctx context.Context) (bool, error) {
+	hash, err := m.bootstrapDataHash(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return m.AzureMachinePool.Annotations[infrav1exp.BootstrapDataHashAnnotation] != hash, nil
+}
+
+// SaveBootstrapDataHash records the hash of the bootstrap data once it is applied to the model of the scale sets of the
+// machine pool.
+func (m *MachinePoolScope) SaveBootstrapDataHash(ctx context.Context) error {
+	hash, err := m.bootstrapDataHash(ctx)
+	if err != nil {
+		return err
+	}
+
+	m.SetAnnotation(infrav1exp.BootstrapDataHashAnnotation, hash)
+	return nil
+}
+
+// bootstrapDataHash returns the SHA-256 hash of the bootstrap data of the machine pool.
+func (m *MachinePoolScope) bootstrapDataHash(ctx context.Context) (string, error) {
+	bootstrapData, err := m.GetBootstrapData(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(bootstrapData))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// isBootstrapDataOutdated returns true if the AzureMachinePoolMachine was created from other bootstrap data than the one
+// last applied to the model of the scale sets of its AzureMachinePool. The machines created before the hash of the
+// bootstrap data was recorded are not outdated.
+func isBootstrapDataOutdated(amp *infrav1exp.AzureMachinePool, ampm *infrav1exp.AzureMachinePoolMachine) bool {
+	if amp == nil || ampm == nil {
+		return false
+	}
+
+	hash, ok := ampm.Annotations[infrav1exp.BootstrapDataHashAnnotation]
+	return ok && hash != amp.Annotations[infrav1exp.BootstrapDataHashAnnotation]
+}
+
 // GetVMImage picks an image from the machine configuration, or uses a default one.
 func (m *MachinePoolScope) GetVMImage(ctx context.Context) (*infrav1.Image, error) {
 	_, log, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.GetVMImage")
@@ -582,16 +1349,58 @@ func (m *MachinePoolScope) RoleAssignmentSpecs() []azure.RoleAssignmentSpec {
 	return []azure.RoleAssignmentSpec{}
 }
 
-// VMSSExtensionSpecs returns the vmss extension specs.
-func (m *MachinePoolScope) VMSSExtensionSpecs() []azure.ExtensionSpec {
+// VMSSExtensionSpecs returns the vmss extension specs, for each of the scale sets backing the machine pool.
+func (m *MachinePoolScope) VMSSExtensionSpecs(ctx context.Context) ([]azure.ExtensionSpec, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.VMSSExtensionSpecs")
+	defer done()
+
 	var extensionSpecs = []azure.ExtensionSpec{}
-	extensionSpec := azure.GetBootstrappingVMExtension(m.AzureMachinePool.Spec.Template.OSDisk.OSType, m.CloudEnvironment(), m.Name())
+	for _, vmssName := range m.extensionScaleSetNames() {
+		extensionSpec := azure.GetBootstrappingVMExtension(m.AzureMachinePool.Spec.Template.OSDisk.OSType, m.CloudEnvironment(), vmssName)
+
+		if extensionSpec != nil {
+			extensionSpecs = append(extensionSpecs, *extensionSpec)
+		}
 
-	if extensionSpec != nil {
-		extensionSpecs = append(extensionSpecs, *extensionSpec)
+		for _, extension := range m.AzureMachinePool.Spec.Template.VMExtensions {
+			protectedSettings, err := getVMExtensionProtectedSettings(ctx, m.client, m.AzureMachinePool.Namespace, extension)
+			if err != nil {
+				return nil, err
+			}
+			extensionSpecs = append(extensionSpecs, azure.ExtensionSpec{
+				Name:              extension.Name,
+				VMName:            vmssName,
+				Publisher:         extension.Publisher,
+				Type:              extension.Type,
+				Version:           extension.Version,
+				Settings:          extension.Settings,
+				ProtectedSettings: protectedSettings,
+			})
+		}
 	}
 
-	return extensionSpecs
+	return extensionSpecs, nil
+}
+
+// extensionScaleSetNames returns the names of the scale sets whose model holds the extensions of the machine pool: the
+// active and replacement scale sets of a blue/green deployment, or each of the scale sets of a mixed instances policy.
+// The scale sets retired by a blue/green deployment keep their model.
+func (m *MachinePoolScope) extensionScaleSetNames() []string {
+	if !m.isBlueGreen() {
+		return m.scaleSetNames()
+	}
+
+	status := m.blueGreenStatus()
+	names := []string{status.ActiveScaleSet}
+	if status.ReplacementScaleSet != "" {
+		names = append(names, status.ReplacementScaleSet)
+	}
+	return names
+}
+
+// SetVMExtensionStatus records the provisioning state of a scale set extension in the AzureMachinePool status.
+func (m *MachinePoolScope) SetVMExtensionStatus(extensionName string, state infrav1.ProvisioningState) {
+	m.AzureMachinePool.Status.VMExtensions = setVMExtensionStatus(m.AzureMachinePool.Status.VMExtensions, extensionName, state)
 }
 
 func (m *MachinePoolScope) getDeploymentStrategy() machinepool.TypedDeleteSelector {
@@ -599,7 +1408,166 @@ func (m *MachinePoolScope) getDeploymentStrategy() machinepool.TypedDeleteSelect
 		return nil
 	}
 
-	return machinepool.NewMachinePoolDeploymentStrategy(m.AzureMachinePool.Spec.Strategy)
+	strategy := m.AzureMachinePool.Spec.Strategy
+	if m.hasAzureManagedUpgrades() {
+		// Azure rolls out model changes itself, so CAPZ must neither surge nor replace instances running an old model.
+		// Failed, deleting and surplus instances are still removed according to the delete policy.
+		var deletePolicy infrav1exp.AzureMachinePoolDeletePolicyType
+		if strategy.RollingUpdate != nil {
+			deletePolicy = strategy.RollingUpdate.DeletePolicy
+		}
+		return machinepool.NewAzureManagedUpgradeStrategy(deletePolicy)
+	}
+
+	return machinepool.NewMachinePoolDeploymentStrategy(strategy)
+}
+
+// hasAzureManagedUpgrades returns true if the scale set rolls out model changes to its instances through a Rolling or
+// Automatic upgrade policy instead of CAPZ.
+func (m *MachinePoolScope) hasAzureManagedUpgrades() bool {
+	policy := m.AzureMachinePool.Spec.UpgradePolicy
+	return policy != nil && (policy.Mode == infrav1.UpgradeModeRolling || policy.Mode == infrav1.UpgradeModeAutomatic)
+}
+
+// ReconcileAutoscalerAnnotations sets the annotations of the MachinePool read by the Cluster API cluster autoscaler
+// provider: the size of the node group, and the capacity of the nodes of the pool computed from its VM size, which lets
+// the cluster autoscaler scale the pool up from zero. The capacity of a pool with a mixed instances policy is the one
+// of its smallest VM size, so that the cluster autoscaler never expects more of a node than it may get.
+func (m *MachinePoolScope) ReconcileAutoscalerAnnotations(ctx context.Context, skuCache *resourceskus.Cache) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scope.MachinePoolScope.ReconcileAutoscalerAnnotations")
+	defer done()
+
+	if m.AzureMachinePool.Spec.Scaling == nil {
+		return nil
+	}
+
+	vmSizes := []string{m.AzureMachinePool.Spec.Template.VMSize}
+	if policy := m.AzureMachinePool.Spec.MixedInstancesPolicy; policy != nil {
+		vmSizes = append(vmSizes, policy.FallbackVMSizes...)
+	}
+
+	var sku resourceskus.SKU
+	for i, vmSize := range vmSizes {
+		candidate, err := skuCache.Get(ctx, vmSize, resourceskus.VirtualMachines)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the SKU of VM size %s", vmSize)
+		}
+
+		if i == 0 || isSmallerVMSize(candidate, sku) {
+			sku = candidate
+		}
+	}
+
+	annotations, err := m.autoscalerAnnotations(sku)
+	if err != nil {
+		return err
+	}
+
+	before := m.MachinePool.DeepCopy()
+	if m.MachinePool.Annotations == nil {
+		m.MachinePool.Annotations = map[string]string{}
+	}
+	for _, key := range []string{autoscalerGPUCountAnnotation, autoscalerGPUTypeAnnotation, autoscalerLabelsAnnotation, autoscalerTaintsAnnotation} {
+		delete(m.MachinePool.Annotations, key)
+	}
+	for key, value := range annotations {
+		m.MachinePool.Annotations[key] = value
+	}
+
+	if reflect.DeepEqual(before.Annotations, m.MachinePool.Annotations) {
+		return nil
+	}
+
+	log.V(4).Info("updating the cluster autoscaler annotations of the machine pool", "annotations", annotations)
+	if err := m.client.Patch(ctx, m.MachinePool, client.MergeFrom(before)); err != nil {
+		return errors.Wrap(err, "failed to patch the cluster autoscaler annotations of the machine pool")
+	}
+
+	return nil
+}
+
+// autoscalerAnnotations returns the cluster autoscaler annotations of the MachinePool for the given VM size SKU.
+func (m *MachinePoolScope) autoscalerAnnotations(sku resourceskus.SKU) (map[string]string, error) {
+	scaling := m.AzureMachinePool.Spec.Scaling
+	annotations := map[string]string{
+		autoscalerMinSizeAnnotation: strconv.Itoa(int(scaling.MinSize)),
+		autoscalerMaxSizeAnnotation: strconv.Itoa(int(scaling.MaxSize)),
+	}
+
+	vCPUs, ok := sku.GetCapability(resourceskus.VCPUs)
+	if !ok {
+		return nil, errors.Errorf("VM size %s has no %s capability", to.String(sku.Name), resourceskus.VCPUs)
+	}
+	annotations[autoscalerCPUAnnotation] = vCPUs
+
+	memoryGB, ok := sku.GetCapability(resourceskus.MemoryGB)
+	if !ok {
+		return nil, errors.Errorf("VM size %s has no %s capability", to.String(sku.Name), resourceskus.MemoryGB)
+	}
+	memory, err := resource.ParseQuantity(memoryGB + "Gi")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the memory of VM size %s", to.String(sku.Name))
+	}
+	annotations[autoscalerMemoryAnnotation] = memory.String()
+
+	if gpus, ok := sku.GetCapability(resourceskus.GPUs); ok && gpus != "0" {
+		annotations[autoscalerGPUCountAnnotation] = gpus
+		annotations[autoscalerGPUTypeAnnotation] = autoscalerGPUType
+	}
+
+	// the well-known labels of the nodes are advertised along with the labels the nodes register with
+	arch := "amd64"
+	if cpuArchitecture, ok := sku.GetCapability(resourceskus.CPUArchitectureType); ok && strings.EqualFold(cpuArchitecture, "Arm64") {
+		arch = "arm64"
+	}
+	osType := strings.ToLower(azure.LinuxOS)
+	if m.AzureMachinePool.Spec.Template.OSDisk.OSType == azure.WindowsOS {
+		osType = strings.ToLower(azure.WindowsOS)
+	}
+	nodeLabels := map[string]string{
+		corev1.LabelArchStable:         arch,
+		corev1.LabelOSStable:           osType,
+		corev1.LabelInstanceTypeStable: to.String(sku.Name),
+		corev1.LabelTopologyRegion:     m.Location(),
+	}
+	for key, value := range scaling.NodeLabels {
+		nodeLabels[key] = value
+	}
+	labels := make([]string, 0, len(nodeLabels))
+	for key, value := range nodeLabels {
+		labels = append(labels, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(labels)
+	annotations[autoscalerLabelsAnnotation] = strings.Join(labels, ",")
+
+	if len(scaling.NodeTaints) > 0 {
+		taints := make([]string, 0, len(scaling.NodeTaints))
+		for _, taint := range scaling.NodeTaints {
+			if taint.Value == "" {
+				taints = append(taints, fmt.Sprintf("%s:%s", taint.Key, taint.Effect))
+				continue
+			}
+			taints = append(taints, fmt.Sprintf("%s=%s:%s", taint.Key, taint.Value, taint.Effect))
+		}
+		annotations[autoscalerTaintsAnnotation] = strings.Join(taints, ",")
+	}
+
+	return annotations, nil
+}
+
+// isSmallerVMSize returns true if the VM size SKU has fewer vCPUs than the other one, or as many vCPUs and less memory.
+// A capability which cannot be parsed counts as zero.
+func isSmallerVMSize(sku, other resourceskus.SKU) bool {
+	capability := func(sku resourceskus.SKU, name string) float64 {
+		value, _ := sku.GetCapability(name)
+		parsed, _ := strconv.ParseFloat(value, 64)
+		return parsed
+	}
+
+	if vCPUs, otherVCPUs := capability(sku, resourceskus.VCPUs), capability(other, resourceskus.VCPUs); vCPUs != otherVCPUs {
+		return vCPUs < otherVCPUs
+	}
+	return capability(sku, resourceskus.MemoryGB) < capability(other, resourceskus.MemoryGB)
 }
 
 // SetSubnetName defaults the AzureMachinePool subnet name to the name of the subnet with role 'node' when there is only one of them.
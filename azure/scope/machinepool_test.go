@@ -20,23 +20,30 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
 	autorestazure "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/azure/auth"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	machinepool "sigs.k8s.io/cluster-api-provider-azure/azure/scope/strategies/machinepool_deployments"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/resourceskus"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	clusterv1exp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -96,6 +103,32 @@ func TestMachinePoolScope_Name(t *testing.T) {
 	}
 }
 
+func Test_azureMachinePoolMachineName(t *testing.T) {
+	tests := []struct {
+		name       string
+		instanceID string
+		want       string
+	}{
+		{
+			name:       "uniform instance ID",
+			instanceID: "3",
+			want:       "pool-3",
+		},
+		{
+			name:       "flexible instance VM name",
+			instanceID: "Pool_1A2b3c4D",
+			want:       "pool-pool-1a2b3c4d",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(azureMachinePoolMachineName("pool", tt.instanceID)).To(Equal(tt.want))
+		})
+	}
+}
+
 func TestMachinePoolScope_SetBootstrapConditions(t *testing.T) {
 	cases := []struct {
 		Name   string
@@ -105,7 +138,7 @@ func TestMachinePoolScope_SetBootstrapConditions(t *testing.T) {
 		{
 			Name: "should set bootstrap succeeded condition if provisioning state succeeded",
 			Setup: func() (provisioningState string, extensionName string) {
-				return string(infrav1.Succeeded), "foo"
+				return string(infrav1.Succeeded), azure.BootstrappingExtensionLinux
 			},
 			Verify: func(g *WithT, amp *infrav1exp.AzureMachinePool, err error) {
 				g.Expect(err).NotTo(HaveOccurred())
@@ -115,7 +148,7 @@ func TestMachinePoolScope_SetBootstrapConditions(t *testing.T) {
 		{
 			Name: "should set bootstrap succeeded false condition with reason if provisioning state creating",
 			Setup: func() (provisioningState string, extensionName string) {
-				return string(infrav1.Creating), "bazz"
+				return string(infrav1.Creating), azure.BootstrappingExtensionLinux
 			},
 			Verify: func(g *WithT, amp *infrav1exp.AzureMachinePool, err error) {
 				g.Expect(err).To(MatchError("extension is still in provisioning state. This likely means that bootstrapping has not yet completed on the VM. Object will be requeued after 30s"))
@@ -129,7 +162,7 @@ func TestMachinePoolScope_SetBootstrapConditions(t *testing.T) {
 		{
 			Name: "should set bootstrap succeeded false condition with reason if provisioning state failed",
 			Setup: func() (provisioningState string, extensionName string) {
-				return string(infrav1.Failed), "buzz"
+				return string(infrav1.Failed), azure.BootstrappingExtensionWindows
 			},
 			Verify: func(g *WithT, amp *infrav1exp.AzureMachinePool, err error) {
 				g.Expect(err).To(MatchError("reconcile error that cannot be recovered occurred: extension state failed. This likely means the Kubernetes node bootstrapping process failed or timed out. Check VM boot diagnostics logs to learn more. Object will not be requeued"))
@@ -140,6 +173,16 @@ func TestMachinePoolScope_SetBootstrapConditions(t *testing.T) {
 				g.Expect(*severity).To(Equal(clusterv1.ConditionSeverityError))
 			},
 		},
+		{
+			Name: "should not set bootstrap conditions for extensions other than the bootstrapping extension",
+			Setup: func() (provisioningState string, extensionName string) {
+				return string(infrav1.Failed), "my-extension"
+			},
+			Verify: func(g *WithT, amp *infrav1exp.AzureMachinePool, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(conditions.Has(amp, infrav1.BootstrapSucceededCondition)).To(BeFalse())
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -217,6 +260,24 @@ func TestMachinePoolScope_MaxSurge(t *testing.T) {
 				g.Expect(err).NotTo(HaveOccurred())
 			},
 		},
+		{
+			Name: "surge should be 0 when Azure rolls out model changes",
+			Setup: func(mp *clusterv1exp.MachinePool, amp *infrav1exp.AzureMachinePool) {
+				mp.Spec.Replicas = to.Int32Ptr(4)
+				two := intstr.FromInt(2)
+				amp.Spec.Strategy = infrav1exp.AzureMachinePoolDeploymentStrategy{
+					Type: infrav1exp.RollingUpdateAzureMachinePoolDeploymentStrategyType,
+					RollingUpdate: &infrav1exp.MachineRollingUpdateDeployment{
+						MaxSurge: &two,
+					},
+				}
+				amp.Spec.UpgradePolicy = &infrav1.UpgradePolicy{Mode: infrav1.UpgradeModeRolling}
+			},
+			Verify: func(g *WithT, surge int, err error) {
+				g.Expect(surge).To(Equal(0))
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -514,6 +575,704 @@ func TestMachinePoolScope_NeedsRequeue(t *testing.T) {
 	}
 }
 
+func TestMachinePoolScope_ScaleSetSpecs(t *testing.T) {
+	type capacities map[string]int64
+
+	cases := []struct {
+		Name   string
+		Setup  func(mp *clusterv1exp.MachinePool, amp *infrav1exp.AzureMachinePool)
+		Verify func(g *WithT, specs []azure.ScaleSetSpec)
+	}{
+		{
+			Name: "should return no specs without a mixed instances policy",
+			Setup: func(mp *clusterv1exp.MachinePool, amp *infrav1exp.AzureMachinePool) {
+				amp.Spec.MixedInstancesPolicy = nil
+			},
+			Verify: func(g *WithT, specs []azure.ScaleSetSpec) {
+				g.Expect(specs).To(BeNil())
+			},
+		},
+		{
+			Name: "should split replicas between regular and Spot VMs of the preferred VM size",
+			Setup: func(mp *clusterv1exp.MachinePool, amp *infrav1exp.AzureMachinePool) {
+				mp.Spec.Replicas = to.Int32Ptr(6)
+			},
+			Verify: func(g *WithT, specs []azure.ScaleSetSpec) {
+				g.Expect(specs).To(HaveLen(4))
+				got := capacities{}
+				for _, spec := range specs {
+					got[spec.Name] = spec.Capacity
+					if strings.HasSuffix(spec.Name, "-spot") {
+						g.Expect(spec.SpotVMOptions).NotTo(BeNil())
+					} else {
+						g.Expect(spec.SpotVMOptions).To(BeNil())
+					}
+				}
+				g.Expect(got).To(Equal(capacities{"pool-d2s-v3": 4, "pool-d2as-v4": 0, "pool-d2s-v3-spot": 2, "pool-d2as-v4-spot": 0}))
+			},
+		},
+		{
+			Name: "should keep the on-demand base capacity when scaled below it",
+			Setup: func(mp *clusterv1exp.MachinePool, amp *infrav1exp.AzureMachinePool) {
+				mp.Spec.Replicas = to.Int32Ptr(1)
+			},
+			Verify: func(g *WithT, specs []azure.ScaleSetSpec) {
+				g.Expect(specs[0].Capacity).To(Equal(int64(1)))
+				g.Expect(specs[2].Capacity).To(Equal(int64(0)))
+			},
+		},
+		{
+			Name: "should add missing replicas to the next VM size when the preferred one is unavailable",
+			Setup: func(mp *clusterv1exp.MachinePool, amp *infrav1exp.AzureMachinePool) {
+				mp.Spec.Replicas = to.Int32Ptr(6)
+				now := metav1.Now()
+				amp.Status.ScaleSets = []infrav1exp.AzureMachinePoolScaleSetStatus{
+					{Name: "pool-d2s-v3", VMSize: "Standard_D2s_v3", Priority: infrav1exp.RegularScaleSetPriority, Replicas: 3, UnavailableSince: &now},
+				}
+			},
+			Verify: func(g *WithT, specs []azure.ScaleSetSpec) {
+				g.Expect(specs[0].Capacity).To(Equal(int64(3)))
+				g.Expect(specs[1].Capacity).To(Equal(int64(1)))
+			},
+		},
+		{
+			Name: "should retry the preferred VM size after the retry interval",
+			Setup: func(mp *clusterv1exp.MachinePool, amp *infrav1exp.AzureMachinePool) {
+				mp.Spec.Replicas = to.Int32Ptr(6)
+				since := metav1.NewTime(time.Now().Add(-time.Hour))
+				amp.Status.ScaleSets = []infrav1exp.AzureMachinePoolScaleSetStatus{
+					{Name: "pool-d2s-v3", VMSize: "Standard_D2s_v3", Priority: infrav1exp.RegularScaleSetPriority, Replicas: 1, UnavailableSince: &since},
+					{Name: "pool-d2as-v4", VMSize: "Standard_D2as_v4", Priority: infrav1exp.RegularScaleSetPriority, Replicas: 2},
+				}
+			},
+			Verify: func(g *WithT, specs []azure.ScaleSetSpec) {
+				g.Expect(specs[0].Capacity).To(Equal(int64(2)))
+				g.Expect(specs[1].Capacity).To(Equal(int64(2)))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			g := NewWithT(t)
+			mp := &clusterv1exp.MachinePool{}
+			amp := &infrav1exp.AzureMachinePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "pool",
+				},
+				Spec: infrav1exp.AzureMachinePoolSpec{
+					Template: infrav1exp.AzureMachinePoolMachineTemplate{
+						VMSize: "Standard_D2s_v3",
+					},
+					MixedInstancesPolicy: &infrav1exp.MixedInstancesPolicy{
+						FallbackVMSizes:                 []string{"Standard_D2as_v4"},
+						OnDemandBaseCapacity:            to.Int32Ptr(2),
+						SpotPercentageAboveBaseCapacity: to.Int32Ptr(50),
+					},
+				},
+			}
+			c.Setup(mp, amp)
+			s := &MachinePoolScope{
+				ClusterScoper: &ClusterScope{
+					AzureCluster: &infrav1.AzureCluster{},
+				},
+				MachinePool:      mp,
+				AzureMachinePool: amp,
+			}
+			c.Verify(g, s.ScaleSetSpecs())
+		})
+	}
+}
+
+func TestMachinePoolScope_BlueGreenScaleSetSpecs(t *testing.T) {
+	g := NewWithT(t)
+	amp := &infrav1exp.AzureMachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "pool",
+		},
+		Spec: infrav1exp.AzureMachinePoolSpec{
+			Template: infrav1exp.AzureMachinePoolMachineTemplate{
+				VMSize: "Standard_D2s_v3",
+			},
+			Strategy: infrav1exp.AzureMachinePoolDeploymentStrategy{
+				Type: infrav1exp.BlueGreenAzureMachinePoolDeploymentStrategyType,
+			},
+		},
+	}
+	s := &MachinePoolScope{
+		ClusterScoper: &ClusterScope{
+			AzureCluster: &infrav1.AzureCluster{},
+		},
+		MachinePool:      &clusterv1exp.MachinePool{Spec: clusterv1exp.MachinePoolSpec{Replicas: to.Int32Ptr(3)}},
+		AzureMachinePool: amp,
+	}
+	revision := s.modelRevision()
+
+	// the status of the deployment is only recorded once it is reconciled
+	specs := s.BlueGreenScaleSetSpecs()
+	g.Expect(specs).To(HaveLen(1))
+	g.Expect(specs[0].Name).To(Equal("pool"))
+	g.Expect(specs[0].ObserveOnly).To(BeFalse())
+	g.Expect(amp.Status.BlueGreen).To(BeNil())
+	g.Expect(s.ScaleSetSpecs()).To(BeNil())
+
+	amp.Status.BlueGreen = &infrav1exp.AzureMachinePoolBlueGreenStatus{
+		ActiveScaleSet:      "pool",
+		ActiveRevision:      "0a1b2c3d",
+		ReplacementScaleSet: "pool-" + revision,
+		ReplacementRevision: revision,
+		RetiredScaleSets:    []string{"pool-4e5f6a7b"},
+	}
+
+	specs = s.BlueGreenScaleSetSpecs()
+	g.Expect(specs).To(HaveLen(3))
+	g.Expect(specs[0].Name).To(Equal("pool"))
+	g.Expect(specs[0].ObserveOnly).To(BeTrue())
+	g.Expect(specs[0].Capacity).To(Equal(int64(3)))
+	g.Expect(specs[1].Name).To(Equal("pool-" + revision))
+	g.Expect(specs[1].ObserveOnly).To(BeFalse())
+	g.Expect(specs[1].Capacity).To(Equal(int64(3)))
+	g.Expect(specs[2].Name).To(Equal("pool-4e5f6a7b"))
+	g.Expect(specs[2].ObserveOnly).To(BeTrue())
+	g.Expect(specs[2].Capacity).To(Equal(int64(0)))
+	g.Expect(s.scaleSetNames()).To(Equal([]string{"pool", "pool-" + revision, "pool-4e5f6a7b"}))
+
+	s.SetScaleSetDeleted("pool-4e5f6a7b")
+	g.Expect(amp.Status.BlueGreen.RetiredScaleSets).To(BeEmpty())
+}
+
+func TestMachinePoolScope_reconcileBlueGreen(t *testing.T) {
+	var (
+		succeeded  = infrav1.Succeeded
+		hourAgo    = metav1.NewTime(time.Now().Add(-time.Hour))
+		minuteAgo  = metav1.NewTime(time.Now().Add(-time.Minute))
+		readyAMPMs = func(scaleSet string, count int) map[string]infrav1exp.AzureMachinePoolMachine {
+			machines := make(map[string]infrav1exp.AzureMachinePoolMachine, count)
+			for i := 0; i < count; i++ {
+				providerID := fmt.Sprintf("azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachineScaleSets/%s/virtualMachines/%d", scaleSet, i)
+				machines[providerID] = infrav1exp.AzureMachinePoolMachine{
+					Spec:   infrav1exp.AzureMachinePoolMachineSpec{ProviderID: providerID},
+					Status: infrav1exp.AzureMachinePoolMachineStatus{Ready: true, ProvisioningState: &succeeded},
+				}
+			}
+			return machines
+		}
+	)
+
+	cases := []struct {
+		Name   string
+		Setup  func(amp *infrav1exp.AzureMachinePool, revision string) map[string]infrav1exp.AzureMachinePoolMachine
+		Verify func(g *WithT, status *infrav1exp.AzureMachinePoolBlueGreenStatus, revision string)
+	}{
+		{
+			Name: "should start from the scale set of the machine pool",
+			Setup: func(amp *infrav1exp.AzureMachinePool, revision string) map[string]infrav1exp.AzureMachinePoolMachine {
+				return readyAMPMs("pool", 2)
+			},
+			Verify: func(g *WithT, status *infrav1exp.AzureMachinePoolBlueGreenStatus, revision string) {
+				g.Expect(status.ActiveScaleSet).To(Equal("pool"))
+				g.Expect(status.ActiveRevision).To(Equal(revision))
+				g.Expect(status.ReplacementScaleSet).To(BeEmpty())
+			},
+		},
+		{
+			Name: "should roll out a replacement scale set when the model changes",
+			Setup: func(amp *infrav1exp.AzureMachinePool, revision string) map[string]infrav1exp.AzureMachinePoolMachine {
+				amp.Status.BlueGreen = &infrav1exp.AzureMachinePoolBlueGreenStatus{ActiveScaleSet: "pool", ActiveRevision: "0a1b2c3d"}
+				return readyAMPMs("pool", 2)
+			},
+			Verify: func(g *WithT, status *infrav1exp.AzureMachinePoolBlueGreenStatus, revision string) {
+				g.Expect(status.ActiveScaleSet).To(Equal("pool"))
+				g.Expect(status.ReplacementScaleSet).To(Equal("pool-" + revision))
+				g.Expect(status.ReplacementRevision).To(Equal(revision))
+				g.Expect(status.ReplacementStartTime).NotTo(BeNil())
+				g.Expect(status.ReplacementReadyTime).To(BeNil())
+			},
+		},
+		{
+			Name: "should soak a ready replacement scale set before promoting it",
+			Setup: func(amp *infrav1exp.AzureMachinePool, revision string) map[string]infrav1exp.AzureMachinePoolMachine {
+				amp.Spec.Strategy.BlueGreen = &infrav1exp.MachineBlueGreenDeployment{SoakDuration: &metav1.Duration{Duration: 10 * time.Minute}}
+				amp.Status.BlueGreen = &infrav1exp.AzureMachinePoolBlueGreenStatus{
+					ActiveScaleSet:       "pool",
+					ActiveRevision:       "0a1b2c3d",
+					ReplacementScaleSet:  "pool-" + revision,
+					ReplacementRevision:  revision,
+					ReplacementStartTime: &minuteAgo,
+				}
+				return readyAMPMs("pool-"+revision, 2)
+			},
+			Verify: func(g *WithT, status *infrav1exp.AzureMachinePoolBlueGreenStatus, revision string) {
+				g.Expect(status.ActiveScaleSet).To(Equal("pool"))
+				g.Expect(status.ReplacementScaleSet).To(Equal("pool-" + revision))
+				g.Expect(status.ReplacementReadyTime).NotTo(BeNil())
+			},
+		},
+		{
+			Name: "should promote the replacement scale set after the soak duration",
+			Setup: func(amp *infrav1exp.AzureMachinePool, revision string) map[string]infrav1exp.AzureMachinePoolMachine {
+				amp.Spec.Strategy.BlueGreen = &infrav1exp.MachineBlueGreenDeployment{SoakDuration: &metav1.Duration{Duration: 10 * time.Minute}}
+				amp.Status.BlueGreen = &infrav1exp.AzureMachinePoolBlueGreenStatus{
+					ActiveScaleSet:       "pool",
+					ActiveRevision:       "0a1b2c3d",
+					ReplacementScaleSet:  "pool-" + revision,
+					ReplacementRevision:  revision,
+					ReplacementStartTime: &hourAgo,
+					ReplacementReadyTime: &hourAgo,
+				}
+				return readyAMPMs("pool-"+revision, 2)
+			},
+			Verify: func(g *WithT, status *infrav1exp.AzureMachinePoolBlueGreenStatus, revision string) {
+				g.Expect(status.ActiveScaleSet).To(Equal("pool-" + revision))
+				g.Expect(status.ActiveRevision).To(Equal(revision))
+				g.Expect(status.ReplacementScaleSet).To(BeEmpty())
+				g.Expect(status.RetiredScaleSets).To(Equal([]string{"pool"}))
+			},
+		},
+		{
+			Name: "should restart the soak period when a machine of the replacement scale set is no longer ready",
+			Setup: func(amp *infrav1exp.AzureMachinePool, revision string) map[string]infrav1exp.AzureMachinePoolMachine {
+				amp.Status.BlueGreen = &infrav1exp.AzureMachinePoolBlueGreenStatus{
+					ActiveScaleSet:       "pool",
+					ActiveRevision:       "0a1b2c3d",
+					ReplacementScaleSet:  "pool-" + revision,
+					ReplacementRevision:  revision,
+					ReplacementStartTime: &minuteAgo,
+					ReplacementReadyTime: &minuteAgo,
+				}
+				return readyAMPMs("pool-"+revision, 1)
+			},
+			Verify: func(g *WithT, status *infrav1exp.AzureMachinePoolBlueGreenStatus, revision string) {
+				g.Expect(status.ReplacementScaleSet).To(Equal("pool-" + revision))
+				g.Expect(status.ReplacementReadyTime).To(BeNil())
+			},
+		},
+		{
+			Name: "should roll back the replacement scale set after the readiness timeout",
+			Setup: func(amp *infrav1exp.AzureMachinePool, revision string) map[string]infrav1exp.AzureMachinePoolMachine {
+				amp.Status.BlueGreen = &infrav1exp.AzureMachinePoolBlueGreenStatus{
+					ActiveScaleSet:       "pool",
+					ActiveRevision:       "0a1b2c3d",
+					ReplacementScaleSet:  "pool-" + revision,
+					ReplacementRevision:  revision,
+					ReplacementStartTime: &hourAgo,
+				}
+				return readyAMPMs("pool-"+revision, 1)
+			},
+			Verify: func(g *WithT, status *infrav1exp.AzureMachinePoolBlueGreenStatus, revision string) {
+				g.Expect(status.ActiveScaleSet).To(Equal("pool"))
+				g.Expect(status.FailedRevision).To(Equal(revision))
+				g.Expect(status.ReplacementScaleSet).To(BeEmpty())
+				g.Expect(status.RetiredScaleSets).To(Equal([]string{"pool-" + revision}))
+			},
+		},
+		{
+			Name: "should not roll out a failed model again",
+			Setup: func(amp *infrav1exp.AzureMachinePool, revision string) map[string]infrav1exp.AzureMachinePoolMachine {
+				amp.Status.BlueGreen = &infrav1exp.AzureMachinePoolBlueGreenStatus{
+					ActiveScaleSet: "pool",
+					ActiveRevision: "0a1b2c3d",
+					FailedRevision: revision,
+				}
+				return readyAMPMs("pool", 2)
+			},
+			Verify: func(g *WithT, status *infrav1exp.AzureMachinePoolBlueGreenStatus, revision string) {
+				g.Expect(status.ActiveScaleSet).To(Equal("pool"))
+				g.Expect(status.ReplacementScaleSet).To(BeEmpty())
+			},
+		},
+		{
+			Name: "should retire the replacement scale set of an outdated model",
+			Setup: func(amp *infrav1exp.AzureMachinePool, revision string) map[string]infrav1exp.AzureMachinePoolMachine {
+				amp.Status.BlueGreen = &infrav1exp.AzureMachinePoolBlueGreenStatus{
+					ActiveScaleSet:       "pool",
+					ActiveRevision:       "0a1b2c3d",
+					ReplacementScaleSet:  "pool-4e5f6a7b",
+					ReplacementRevision:  "4e5f6a7b",
+					ReplacementStartTime: &minuteAgo,
+				}
+				return readyAMPMs("pool-4e5f6a7b", 2)
+			},
+			Verify: func(g *WithT, status *infrav1exp.AzureMachinePoolBlueGreenStatus, revision string) {
+				g.Expect(status.ActiveScaleSet).To(Equal("pool"))
+				g.Expect(status.ReplacementScaleSet).To(Equal("pool-" + revision))
+				g.Expect(status.RetiredScaleSets).To(Equal([]string{"pool-4e5f6a7b"}))
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			g := NewWithT(t)
+			amp := &infrav1exp.AzureMachinePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "pool",
+				},
+				Spec: infrav1exp.AzureMachinePoolSpec{
+					Template: infrav1exp.AzureMachinePoolMachineTemplate{
+						VMSize: "Standard_D2s_v3",
+					},
+					Strategy: infrav1exp.AzureMachinePoolDeploymentStrategy{
+						Type: infrav1exp.BlueGreenAzureMachinePoolDeploymentStrategyType,
+					},
+				},
+			}
+			s := &MachinePoolScope{
+				MachinePool:      &clusterv1exp.MachinePool{Spec: clusterv1exp.MachinePoolSpec{Replicas: to.Int32Ptr(2)}},
+				AzureMachinePool: amp,
+			}
+			revision := s.modelRevision()
+			machines := c.Setup(amp, revision)
+			s.reconcileBlueGreen(context.TODO(), machines)
+			c.Verify(g, amp.Status.BlueGreen, revision)
+		})
+	}
+}
+
+func TestMachinePoolScope_retireBlueGreenMachines(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = infrav1exp.AddToScheme(scheme)
+
+	var (
+		twoMachines = intstr.FromInt(2)
+		hourAgo     = metav1.NewTime(time.Now().Add(-time.Hour))
+		minuteAgo   = metav1.NewTime(time.Now().Add(-time.Minute))
+		now         = metav1.Now()
+	)
+
+	cases := []struct {
+		Name           string
+		MaxUnavailable *intstr.IntOrString
+		Setup          func(ampms []infrav1exp.AzureMachinePoolMachine)
+		Deleted        []string
+	}{
+		{
+			Name:    "should delete the oldest machines of the retired scale sets within the default budget",
+			Deleted: []string{"ampm1"},
+		},
+		{
+			Name:           "should delete the oldest machines of the retired scale sets within the budget of the strategy",
+			MaxUnavailable: &twoMachines,
+			Deleted:        []string{"ampm1", "ampm2"},
+		},
+		{
+			Name:           "should count the machines of the retired scale sets being deleted against the budget",
+			MaxUnavailable: &twoMachines,
+			Setup: func(ampms []infrav1exp.AzureMachinePoolMachine) {
+				ampms[2].DeletionTimestamp = &now
+				ampms[2].Finalizers = []string{"test"}
+			},
+			Deleted: []string{"ampm1"},
+		},
+		{
+			Name:           "should not delete the machines of the retired scale sets protected from scale-in",
+			MaxUnavailable: &twoMachines,
+			Setup: func(ampms []infrav1exp.AzureMachinePoolMachine) {
+				ampms[1].Spec.ProtectionPolicy = &infrav1exp.InstanceProtectionPolicy{ProtectFromScaleIn: true}
+			},
+			Deleted: []string{"ampm2", "ampm3"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			g := NewWithT(t)
+			ampms := getReadyAzureMachinePoolMachines(5)
+			for i, scaleSet := range []string{"pool-0a1b2c3d", "pool", "pool", "pool", "pool-4e5f6a7b"} {
+				ampms[i].Spec.ProviderID = fmt.Sprintf("azure:///subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Compute/virtualMachineScaleSets/%s/virtualMachines/%d", scaleSet, i)
+			}
+			ampms[1].CreationTimestamp = hourAgo
+			ampms[2].CreationTimestamp = hourAgo
+			ampms[3].CreationTimestamp = minuteAgo
+			if c.Setup != nil {
+				c.Setup(ampms)
+			}
+
+			var (
+				cb                   = fake.NewClientBuilder().WithScheme(scheme)
+				machinesByProviderID = make(map[string]infrav1exp.AzureMachinePoolMachine, len(ampms))
+			)
+			for i := range ampms {
+				cb.WithObjects(&ampms[i])
+				machinesByProviderID[ampms[i].Spec.ProviderID] = ampms[i]
+			}
+			kubeClient := cb.Build()
+
+			s := &MachinePoolScope{
+				client: kubeClient,
+				MachinePool: &clusterv1exp.MachinePool{
+					Spec: clusterv1exp.MachinePoolSpec{
+						Replicas: to.Int32Ptr(3),
+					},
+				},
+				AzureMachinePool: &infrav1exp.AzureMachinePool{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "pool",
+					},
+					Spec: infrav1exp.AzureMachinePoolSpec{
+						Strategy: infrav1exp.AzureMachinePoolDeploymentStrategy{
+							Type:      infrav1exp.BlueGreenAzureMachinePoolDeploymentStrategyType,
+							BlueGreen: &infrav1exp.MachineBlueGreenDeployment{MaxUnavailable: c.MaxUnavailable},
+						},
+					},
+					Status: infrav1exp.AzureMachinePoolStatus{
+						BlueGreen: &infrav1exp.AzureMachinePoolBlueGreenStatus{
+							ActiveScaleSet:      "pool-0a1b2c3d",
+							ActiveRevision:      "0a1b2c3d",
+							ReplacementScaleSet: "pool-4e5f6a7b",
+							ReplacementRevision: "4e5f6a7b",
+							RetiredScaleSets:    []string{"pool"},
+						},
+					},
+				},
+			}
+
+			g.Expect(s.retireBlueGreenMachines(context.TODO(), machinesByProviderID)).To(Succeed())
+			g.Expect(machinesByProviderID).To(HaveLen(1))
+			g.Expect(machinesByProviderID).To(HaveKey(ampms[0].Spec.ProviderID))
+
+			var deleted []string
+			for i := range ampms {
+				err := kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(&ampms[i]), &infrav1exp.AzureMachinePoolMachine{})
+				if apierrors.IsNotFound(err) {
+					deleted = append(deleted, ampms[i].Name)
+					continue
+				}
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+			g.Expect(deleted).To(Equal(c.Deleted))
+		})
+	}
+}
+
+func TestMachinePoolScope_SetScaleSetStates(t *testing.T) {
+	g := NewWithT(t)
+	since := metav1.NewTime(time.Now().Add(-time.Hour))
+	amp := &infrav1exp.AzureMachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "pool",
+		},
+		Spec: infrav1exp.AzureMachinePoolSpec{
+			Template: infrav1exp.AzureMachinePoolMachineTemplate{
+				VMSize: "Standard_D2s_v3",
+			},
+			MixedInstancesPolicy: &infrav1exp.MixedInstancesPolicy{
+				FallbackVMSizes: []string{"Standard_D2as_v4"},
+			},
+		},
+		Status: infrav1exp.AzureMachinePoolStatus{
+			ScaleSets: []infrav1exp.AzureMachinePoolScaleSetStatus{
+				{Name: "pool-d2as-v4", VMSize: "Standard_D2as_v4", Priority: infrav1exp.RegularScaleSetPriority, Replicas: 1, UnavailableSince: &since},
+			},
+		},
+	}
+	s := &MachinePoolScope{
+		MachinePool:      &clusterv1exp.MachinePool{},
+		AzureMachinePool: amp,
+	}
+
+	s.SetScaleSetUnavailable("pool-d2s-v3")
+	s.SetScaleSetStates([]*azure.VMSS{
+		{
+			ID:       "vmss-1",
+			Name:     "pool-d2s-v3",
+			Capacity: 2,
+			State:    infrav1.Succeeded,
+			Instances: []azure.VMSSVM{
+				{InstanceID: "0", State: infrav1.Succeeded},
+				{InstanceID: "1", State: infrav1.Failed},
+			},
+		},
+		{
+			ID:        "vmss-2",
+			Name:      "pool-d2as-v4",
+			Capacity:  1,
+			State:     infrav1.Updating,
+			Instances: []azure.VMSSVM{{InstanceID: "0", State: infrav1.Succeeded}},
+		},
+	})
+
+	g.Expect(amp.Status.ScaleSets).To(HaveLen(2))
+	g.Expect(amp.Status.ScaleSets[0].Name).To(Equal("pool-d2s-v3"))
+	g.Expect(amp.Status.ScaleSets[0].Replicas).To(Equal(int32(1)))
+	g.Expect(amp.Status.ScaleSets[0].UnavailableSince).NotTo(BeNil())
+	g.Expect(amp.Status.ScaleSets[1].Replicas).To(Equal(int32(1)))
+	g.Expect(amp.Status.ScaleSets[1].UnavailableSince).To(BeNil())
+	g.Expect(s.vmssState.Capacity).To(Equal(int64(3)))
+	g.Expect(s.vmssState.Instances).To(HaveLen(3))
+	g.Expect(s.vmssState.State).To(Equal(infrav1.Updating))
+}
+
+func TestMachinePoolScope_ModelDifferences(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	_ = infrav1exp.AddToScheme(scheme)
+
+	image := infrav1.Image{
+		Marketplace: &infrav1.AzureMarketplaceImage{
+			Version: "2",
+		},
+	}
+	oldImage := infrav1.Image{
+		Marketplace: &infrav1.AzureMarketplaceImage{
+			Version: "1",
+		},
+	}
+	amp := &infrav1exp.AzureMachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pool",
+			Namespace: "default",
+		},
+		Spec: infrav1exp.AzureMachinePoolSpec{
+			Template: infrav1exp.AzureMachinePoolMachineTemplate{
+				VMSize: "Standard_D2s_v3",
+			},
+			MixedInstancesPolicy: &infrav1exp.MixedInstancesPolicy{
+				FallbackVMSizes: []string{"Standard_D2as_v4"},
+			},
+		},
+	}
+	ampms := getReadyAzureMachinePoolMachines(2)
+	ampms[0].Spec.ProviderID = "azure:///vmss-1/virtualMachines/0"
+	ampms[1].Spec.ProviderID = "azure:///vmss-2/virtualMachines/0"
+	ampms[1].Status.ModelDifferences = []infrav1.ModelDifference{infrav1.ModelDifferenceImage}
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&ampms[0], &ampms[1]).Build()
+
+	s := &MachinePoolScope{
+		client: kubeClient,
+		MachinePool: &clusterv1exp.MachinePool{
+			Spec: clusterv1exp.MachinePoolSpec{
+				Replicas: to.Int32Ptr(3),
+			},
+		},
+		AzureMachinePool: amp,
+	}
+	s.SetScaleSetStates([]*azure.VMSS{
+		{
+			ID:    "/vmss-1",
+			Name:  "pool-d2s-v3",
+			Sku:   "Standard_D2s_v3",
+			Image: image,
+			State: infrav1.Succeeded,
+			Instances: []azure.VMSSVM{
+				{ID: "/vmss-1/virtualMachines/0", Image: oldImage, VMSize: "Standard_D2s_v3"},
+				{ID: "/vmss-1/virtualMachines/1", Image: image, VMSize: "Standard_D2s_v3", ModelOutdated: true},
+			},
+		},
+		{
+			ID:        "/vmss-2",
+			Name:      "pool-d2as-v4",
+			Sku:       "Standard_D2as_v4",
+			Image:     image,
+			State:     infrav1.Succeeded,
+			Instances: []azure.VMSSVM{{ID: "/vmss-2/virtualMachines/0", Image: image, VMSize: "Standard_D2as_v4"}},
+		},
+	})
+
+	amp.Status.Replicas = 3
+	s.setProvisioningStateAndConditions(infrav1.Succeeded)
+	condition := conditions.Get(amp, infrav1.ScaleSetModelUpdatedCondition)
+	g.Expect(condition).NotTo(BeNil())
+	g.Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+	g.Expect(condition.Reason).To(Equal(infrav1.ScaleSetModelOutOfDateReason))
+	g.Expect(condition.Message).To(Equal("2 of 3 instances differ from the scale set model in Image (1), Unknown (1)"))
+
+	for i := range ampms {
+		g.Expect(s.updateMachineModelDifferences(context.TODO(), &ampms[i])).To(Succeed())
+	}
+
+	actual := &infrav1exp.AzureMachinePoolMachine{}
+	g.Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(&ampms[0]), actual)).To(Succeed())
+	g.Expect(actual.Status.ModelDifferences).To(Equal([]infrav1.ModelDifference{infrav1.ModelDifferenceImage}))
+	g.Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(&ampms[1]), actual)).To(Succeed())
+	g.Expect(actual.Status.ModelDifferences).To(BeEmpty())
+}
+
+func TestMachinePoolScope_BootstrapDataHash(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = infrav1exp.AddToScheme(scheme)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "bootstrap-data",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{"value": []byte("#cloud-config")},
+	}
+	amp := &infrav1exp.AzureMachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "amp1",
+			Namespace: "default",
+		},
+	}
+	ampms := getReadyAzureMachinePoolMachines(1)
+	ampms[0].Annotations = map[string]string{infrav1exp.BootstrapDataHashAnnotation: "previous"}
+	kubeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret, &ampms[0]).Build()
+
+	s := &MachinePoolScope{
+		client: kubeClient,
+		ClusterScoper: &ClusterScope{
+			Cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster1",
+				},
+			},
+		},
+		MachinePool: &clusterv1exp.MachinePool{
+			Spec: clusterv1exp.MachinePoolSpec{
+				Template: clusterv1.MachineTemplateSpec{
+					Spec: clusterv1.MachineSpec{
+						Bootstrap: clusterv1.Bootstrap{
+							DataSecretName: to.StringPtr("bootstrap-data"),
+						},
+					},
+				},
+			},
+		},
+		AzureMachinePool: amp,
+	}
+
+	// the bootstrap data of a pool whose hash was never recorded is applied to the scale set model
+	changed, err := s.HasBootstrapDataChanges(context.TODO())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(changed).To(BeTrue())
+
+	g.Expect(s.SaveBootstrapDataHash(context.TODO())).To(Succeed())
+	hash := amp.Annotations[infrav1exp.BootstrapDataHashAnnotation]
+	g.Expect(hash).To(HaveLen(64))
+	changed, err = s.HasBootstrapDataChanges(context.TODO())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(changed).To(BeFalse())
+
+	// the machines are created with the hash of the bootstrap data of the scale set model
+	g.Expect(s.createMachine(context.TODO(), azure.VMSSVM{ID: "/vmss/virtualMachines/1", InstanceID: "1", Name: "vm1"})).To(Succeed())
+	created := &infrav1exp.AzureMachinePoolMachine{}
+	g.Expect(kubeClient.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "amp1-1"}, created)).To(Succeed())
+	g.Expect(created.Annotations).To(HaveKeyWithValue(infrav1exp.BootstrapDataHashAnnotation, hash))
+	g.Expect(isBootstrapDataOutdated(amp, created)).To(BeFalse())
+
+	// the machine created from older bootstrap data differs from the scale set model in its bootstrap data
+	g.Expect(isBootstrapDataOutdated(amp, &ampms[0])).To(BeTrue())
+	s.modelDifferences = map[string][]infrav1.ModelDifference{
+		ampms[0].Spec.ProviderID: {infrav1.ModelDifferenceUnknown},
+	}
+	g.Expect(s.updateMachineModelDifferences(context.TODO(), &ampms[0])).To(Succeed())
+	actual := &infrav1exp.AzureMachinePoolMachine{}
+	g.Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(&ampms[0]), actual)).To(Succeed())
+	g.Expect(actual.Status.ModelDifferences).To(Equal([]infrav1.ModelDifference{infrav1.ModelDifferenceBootstrapData}))
+
+	// the machines created before the hash of the bootstrap data was recorded are not rolled out
+	g.Expect(isBootstrapDataOutdated(amp, &infrav1exp.AzureMachinePoolMachine{})).To(BeFalse())
+
+	secret.Data["value"] = []byte("#cloud-config\nruncmd: []")
+	g.Expect(kubeClient.Update(context.TODO(), secret)).To(Succeed())
+	changed, err = s.HasBootstrapDataChanges(context.TODO())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(changed).To(BeTrue())
+}
+
 func TestMachinePoolScope_updateReplicasAndProviderIDs(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = clusterv1.AddToScheme(scheme)
@@ -657,6 +1416,7 @@ func TestMachinePoolScope_VMSSExtensionSpecs(t *testing.T) {
 					Name:      "CAPZ.Linux.Bootstrapping",
 					VMName:    "machinepool-name",
 					Publisher: "Microsoft.Azure.ContainerUpstream",
+					Type:      "CAPZ.Linux.Bootstrapping",
 					Version:   "1.0",
 					ProtectedSettings: map[string]string{
 						"commandToExecute": azure.LinuxBootstrapExtensionCommand,
@@ -725,6 +1485,7 @@ func TestMachinePoolScope_VMSSExtensionSpecs(t *testing.T) {
 					// Note: machine pool names longer than 9 characters get truncated. See MachinePoolScope::Name() for more details.
 					VMName:    "winpool",
 					Publisher: "Microsoft.Azure.ContainerUpstream",
+					Type:      "CAPZ.Windows.Bootstrapping",
 					Version:   "1.0",
 					ProtectedSettings: map[string]string{
 						"commandToExecute": azure.WindowsBootstrapExtensionCommand,
@@ -819,13 +1580,100 @@ func TestMachinePoolScope_VMSSExtensionSpecs(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := tt.machinePoolScope.VMSSExtensionSpecs(); !reflect.DeepEqual(got, tt.want) {
+			got, err := tt.machinePoolScope.VMSSExtensionSpecs(context.Background())
+			if err != nil {
+				t.Fatalf("VMSSExtensionSpecs() returned error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("VMSSExtensionSpecs() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
+func TestMachinePoolScope_VMSSExtensionSpecsWithMixedInstancesPolicy(t *testing.T) {
+	g := NewWithT(t)
+	s := &MachinePoolScope{
+		MachinePool: &clusterv1exp.MachinePool{},
+		AzureMachinePool: &infrav1exp.AzureMachinePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pool",
+			},
+			Spec: infrav1exp.AzureMachinePoolSpec{
+				Template: infrav1exp.AzureMachinePoolMachineTemplate{
+					VMSize: "Standard_D2s_v3",
+					OSDisk: infrav1.OSDisk{
+						OSType: "Linux",
+					},
+				},
+				MixedInstancesPolicy: &infrav1exp.MixedInstancesPolicy{},
+			},
+		},
+		ClusterScoper: &ClusterScope{
+			AzureClients: AzureClients{
+				EnvironmentSettings: auth.EnvironmentSettings{
+					Environment: autorestazure.Environment{
+						Name: autorestazure.PublicCloud.Name,
+					},
+				},
+			},
+		},
+	}
+
+	got, err := s.VMSSExtensionSpecs(context.Background())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got).To(HaveLen(2))
+	g.Expect(got[0].VMName).To(Equal("pool-d2s-v3"))
+	g.Expect(got[1].VMName).To(Equal("pool-d2s-v3-spot"))
+}
+
+func TestMachinePoolScope_selectMachinesToDelete(t *testing.T) {
+	g := NewWithT(t)
+	amp := &infrav1exp.AzureMachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "pool",
+		},
+		Spec: infrav1exp.AzureMachinePoolSpec{
+			Template: infrav1exp.AzureMachinePoolMachineTemplate{
+				VMSize: "Standard_D2s_v3",
+			},
+			MixedInstancesPolicy: &infrav1exp.MixedInstancesPolicy{
+				OnDemandBaseCapacity:            to.Int32Ptr(1),
+				SpotPercentageAboveBaseCapacity: to.Int32Ptr(50),
+			},
+		},
+	}
+	s := &MachinePoolScope{
+		MachinePool: &clusterv1exp.MachinePool{
+			Spec: clusterv1exp.MachinePoolSpec{
+				Replicas: to.Int32Ptr(3),
+			},
+		},
+		AzureMachinePool: amp,
+	}
+
+	// the pool was scaled down from 5 replicas, 3 regular and 2 Spot VMs, to 3 replicas, 2 regular and 1 Spot VMs
+	succeeded := infrav1.Succeeded
+	machines := getReadyAzureMachinePoolMachines(5)
+	machinesByProviderID := make(map[string]infrav1exp.AzureMachinePoolMachine, len(machines))
+	for i, machine := range machines {
+		scaleSet := "pool-d2s-v3"
+		if i >= 3 {
+			scaleSet = "pool-d2s-v3-spot"
+		}
+		machine.Spec.ProviderID = fmt.Sprintf("azure:///subscriptions/123/resourceGroups/rg/providers/Microsoft.Compute/virtualMachineScaleSets/%s/virtualMachines/%d", scaleSet, i)
+		machine.Status.ProvisioningState = &succeeded
+		machine.Status.LatestModelApplied = true
+		machinesByProviderID[machine.Spec.ProviderID] = machine
+	}
+
+	toDelete, err := s.selectMachinesToDelete(context.TODO(), machinepool.NewMachinePoolDeploymentStrategy(amp.Spec.Strategy), machinesByProviderID)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(toDelete).To(HaveLen(2))
+	g.Expect(scaleSetNameFromProviderID(toDelete[0].Spec.ProviderID)).To(Equal("pool-d2s-v3"))
+	g.Expect(scaleSetNameFromProviderID(toDelete[1].Spec.ProviderID)).To(Equal("pool-d2s-v3-spot"))
+}
+
 func getReadyAzureMachinePoolMachines(count int32) []infrav1exp.AzureMachinePoolMachine {
 	machines := make([]infrav1exp.AzureMachinePoolMachine, count)
 	for i := 0; i < int(count); i++ {
@@ -856,3 +1704,196 @@ func getReadyAzureMachinePoolMachines(count int32) []infrav1exp.AzureMachinePool
 
 	return machines
 }
+
+func TestMachinePoolScope_ReconcileAutoscalerAnnotations(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clusterv1exp.AddToScheme(scheme)
+	_ = infrav1exp.AddToScheme(scheme)
+
+	skuCache := resourceskus.NewStaticCache([]compute.ResourceSku{
+		{
+			Name:         to.StringPtr("Standard_D4s_v3"),
+			ResourceType: to.StringPtr(string(resourceskus.VirtualMachines)),
+			Locations:    &[]string{"westus2"},
+			Capabilities: &[]compute.ResourceSkuCapabilities{
+				{Name: to.StringPtr(resourceskus.VCPUs), Value: to.StringPtr("4")},
+				{Name: to.StringPtr(resourceskus.MemoryGB), Value: to.StringPtr("16")},
+			},
+		},
+		{
+			Name:         to.StringPtr("Standard_NC6s_v3"),
+			ResourceType: to.StringPtr(string(resourceskus.VirtualMachines)),
+			Locations:    &[]string{"westus2"},
+			Capabilities: &[]compute.ResourceSkuCapabilities{
+				{Name: to.StringPtr(resourceskus.VCPUs), Value: to.StringPtr("6")},
+				{Name: to.StringPtr(resourceskus.MemoryGB), Value: to.StringPtr("112")},
+				{Name: to.StringPtr(resourceskus.GPUs), Value: to.StringPtr("1")},
+			},
+		},
+		{
+			Name:         to.StringPtr("Standard_D2ps_v5"),
+			ResourceType: to.StringPtr(string(resourceskus.VirtualMachines)),
+			Locations:    &[]string{"westus2"},
+			Capabilities: &[]compute.ResourceSkuCapabilities{
+				{Name: to.StringPtr(resourceskus.VCPUs), Value: to.StringPtr("2")},
+				{Name: to.StringPtr(resourceskus.MemoryGB), Value: to.StringPtr("0.75")},
+				{Name: to.StringPtr(resourceskus.CPUArchitectureType), Value: to.StringPtr("Arm64")},
+			},
+		},
+	}, "westus2")
+
+	cases := []struct {
+		Name                 string
+		VMSize               string
+		MixedInstancesPolicy *infrav1exp.MixedInstancesPolicy
+		Scaling              *infrav1exp.MachinePoolScaling
+		Annotations          map[string]string
+		Want                 map[string]string
+		Err                  string
+	}{
+		{
+			Name:        "should leave the annotations unchanged without scaling",
+			VMSize:      "Standard_D4s_v3",
+			Annotations: map[string]string{autoscalerMinSizeAnnotation: "1"},
+			Want:        map[string]string{autoscalerMinSizeAnnotation: "1"},
+		},
+		{
+			Name:   "should set the node group size and the capacity of a GPU VM size",
+			VMSize: "Standard_NC6s_v3",
+			Scaling: &infrav1exp.MachinePoolScaling{
+				MinSize:    0,
+				MaxSize:    5,
+				NodeLabels: map[string]string{"accelerator": "v100"},
+				NodeTaints: []corev1.Taint{{Key: "nvidia.com/gpu", Value: "present", Effect: corev1.TaintEffectNoSchedule}},
+			},
+			Want: map[string]string{
+				autoscalerMinSizeAnnotation:  "0",
+				autoscalerMaxSizeAnnotation:  "5",
+				autoscalerCPUAnnotation:      "6",
+				autoscalerMemoryAnnotation:   "112Gi",
+				autoscalerGPUCountAnnotation: "1",
+				autoscalerGPUTypeAnnotation:  "nvidia.com/gpu",
+				autoscalerLabelsAnnotation:   "accelerator=v100,kubernetes.io/arch=amd64,kubernetes.io/os=linux,node.kubernetes.io/instance-type=Standard_NC6s_v3,topology.kubernetes.io/region=westus2",
+				autoscalerTaintsAnnotation:   "nvidia.com/gpu=present:NoSchedule",
+			},
+		},
+		{
+			Name:    "should remove the stale GPU and taints annotations of the MachinePool",
+			VMSize:  "Standard_D2ps_v5",
+			Scaling: &infrav1exp.MachinePoolScaling{MinSize: 1, MaxSize: 3},
+			Annotations: map[string]string{
+				"foo":                        "bar",
+				autoscalerGPUCountAnnotation: "1",
+				autoscalerGPUTypeAnnotation:  "nvidia.com/gpu",
+				autoscalerTaintsAnnotation:   "nvidia.com/gpu=present:NoSchedule",
+			},
+			Want: map[string]string{
+				"foo":                       "bar",
+				autoscalerMinSizeAnnotation: "1",
+				autoscalerMaxSizeAnnotation: "3",
+				autoscalerCPUAnnotation:     "2",
+				autoscalerMemoryAnnotation:  "768Mi",
+				autoscalerLabelsAnnotation:  "kubernetes.io/arch=arm64,kubernetes.io/os=linux,node.kubernetes.io/instance-type=Standard_D2ps_v5,topology.kubernetes.io/region=westus2",
+			},
+		},
+		{
+			Name:   "should render a taint without a value as key:Effect",
+			VMSize: "Standard_D4s_v3",
+			Scaling: &infrav1exp.MachinePoolScaling{
+				MinSize:    0,
+				MaxSize:    3,
+				NodeTaints: []corev1.Taint{{Key: "dedicated", Effect: corev1.TaintEffectNoExecute}},
+			},
+			Want: map[string]string{
+				autoscalerMinSizeAnnotation: "0",
+				autoscalerMaxSizeAnnotation: "3",
+				autoscalerCPUAnnotation:     "4",
+				autoscalerMemoryAnnotation:  "16Gi",
+				autoscalerLabelsAnnotation:  "kubernetes.io/arch=amd64,kubernetes.io/os=linux,node.kubernetes.io/instance-type=Standard_D4s_v3,topology.kubernetes.io/region=westus2",
+				autoscalerTaintsAnnotation:  "dedicated:NoExecute",
+			},
+		},
+		{
+			Name:   "should use the capacity of the smallest VM size of a mixed instances policy",
+			VMSize: "Standard_NC6s_v3",
+			MixedInstancesPolicy: &infrav1exp.MixedInstancesPolicy{
+				FallbackVMSizes: []string{"Standard_D4s_v3"},
+			},
+			Scaling: &infrav1exp.MachinePoolScaling{MinSize: 0, MaxSize: 3},
+			Want: map[string]string{
+				autoscalerMinSizeAnnotation: "0",
+				autoscalerMaxSizeAnnotation: "3",
+				autoscalerCPUAnnotation:     "4",
+				autoscalerMemoryAnnotation:  "16Gi",
+				autoscalerLabelsAnnotation:  "kubernetes.io/arch=amd64,kubernetes.io/os=linux,node.kubernetes.io/instance-type=Standard_D4s_v3,topology.kubernetes.io/region=westus2",
+			},
+		},
+		{
+			Name:   "should fail if a fallback VM size of a mixed instances policy is not available",
+			VMSize: "Standard_D4s_v3",
+			MixedInstancesPolicy: &infrav1exp.MixedInstancesPolicy{
+				FallbackVMSizes: []string{"Standard_Missing"},
+			},
+			Scaling: &infrav1exp.MachinePoolScaling{MinSize: 1, MaxSize: 3},
+			Err:     "failed to get the SKU of VM size Standard_Missing",
+		},
+		{
+			Name:    "should fail if the VM size is not available",
+			VMSize:  "Standard_Missing",
+			Scaling: &infrav1exp.MachinePoolScaling{MinSize: 1, MaxSize: 3},
+			Err:     "failed to get the SKU of VM size Standard_Missing",
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			g := NewWithT(t)
+			mp := &clusterv1exp.MachinePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "mp1",
+					Namespace:   "default",
+					Annotations: c.Annotations,
+				},
+			}
+			amp := &infrav1exp.AzureMachinePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "amp1",
+					Namespace: "default",
+				},
+				Spec: infrav1exp.AzureMachinePoolSpec{
+					Template: infrav1exp.AzureMachinePoolMachineTemplate{
+						VMSize: c.VMSize,
+					},
+					MixedInstancesPolicy: c.MixedInstancesPolicy,
+					Scaling:              c.Scaling,
+				},
+			}
+			kubeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(mp).Build()
+			s := &MachinePoolScope{
+				client: kubeClient,
+				ClusterScoper: &ClusterScope{
+					AzureCluster: &infrav1.AzureCluster{
+						Spec: infrav1.AzureClusterSpec{
+							Location: "westus2",
+						},
+					},
+				},
+				MachinePool:      mp,
+				AzureMachinePool: amp,
+			}
+
+			err := s.ReconcileAutoscalerAnnotations(context.TODO(), skuCache)
+			if c.Err != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(c.Err))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			actual := &clusterv1exp.MachinePool{}
+			g.Expect(kubeClient.Get(context.TODO(), client.ObjectKeyFromObject(mp), actual)).To(Succeed())
+			g.Expect(actual.Annotations).To(Equal(c.Want))
+		})
+	}
+}
@@ -18,19 +18,29 @@ package scope
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	kubedrain "k8s.io/kubectl/pkg/drain"
 	"k8s.io/utils/pointer"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/nodediagnostics"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/util/futures"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -67,9 +77,14 @@ type (
 		Client                  client.Client
 		ClusterScope            azure.ClusterScoper
 		MachinePool             *capiv1exp.MachinePool
+		// Recorder records the events of the machine pool machine, such as the pod evictions blocked while draining
+		// its node. Events are not recorded if it is nil.
+		Recorder record.EventRecorder
 
 		// workloadNodeGetter is only used for testing purposes and provides a way for mocking requests to the workload cluster
 		workloadNodeGetter nodeGetter
+		// serialConsoleLogGetter is only used for testing purposes and provides a way for mocking requests to retrieve the serial console log
+		serialConsoleLogGetter nodediagnostics.SerialConsoleLogGetter
 	}
 
 	// MachinePoolMachineScope defines a scope defined around a machine pool machine.
@@ -82,9 +97,36 @@ type (
 		client                  client.Client
 		patchHelper             *patch.Helper
 		instance                *azure.VMSSVM
+		recorder                record.EventRecorder
 
 		// workloadNodeGetter is only used for testing purposes and provides a way for mocking requests to the workload cluster
 		workloadNodeGetter nodeGetter
+		// serialConsoleLogGetter is only used for testing purposes and provides a way for mocking requests to retrieve the serial console log
+		serialConsoleLogGetter nodediagnostics.SerialConsoleLogGetter
+		// workloadClientset is only used for testing purposes and provides a way for mocking requests to the workload cluster
+		workloadClientset kubernetes.Interface
+		// pendingScheduledEvents is true if the VM had scheduled events pending when they were last handled
+		pendingScheduledEvents bool
+		// node is the node of the machine fetched by UpdateStatus, nil if the machine has no node
+		node *corev1.Node
+		// nodeFetched is true once UpdateStatus has fetched the node of the machine
+		nodeFetched bool
+	}
+
+	// scheduledEvent is a scheduled event of a VM, as returned by the Azure Instance Metadata Service.
+	scheduledEvent struct {
+		EventID     string `json:"EventId"`
+		EventType   string `json:"EventType"`
+		EventStatus string `json:"EventStatus,omitempty"`
+		NotBefore   string `json:"NotBefore,omitempty"`
+	}
+
+	// EvictionBlock describes a pod whose eviction is blocked by a PodDisruptionBudget which allows no disruption.
+	EvictionBlock struct {
+		// Pod is the namespaced name of the pod.
+		Pod string
+		// PodDisruptionBudget is the namespaced name of the PodDisruptionBudget.
+		PodDisruptionBudget string
 	}
 )
 
@@ -144,6 +186,7 @@ func NewMachinePoolMachineScope(params MachinePoolMachineScopeParams) (*MachineP
 		MachinePoolScope:        mpScope,
 		client:                  params.Client,
 		patchHelper:             helper,
+		recorder:                params.Recorder,
 		workloadNodeGetter:      params.workloadNodeGetter,
 	}, nil
 }
@@ -160,9 +203,114 @@ func (s *MachinePoolMachineScope) InstanceID() string {
 
 // ScaleSetName is the name of the VMSS.
 func (s *MachinePoolMachineScope) ScaleSetName() string {
+	if s.MachinePoolScope.hasMultipleScaleSets() {
+		// a pool with a mixed instances policy or a blue/green deployment is backed by several scale sets, the instance
+		// belongs to the one in its provider ID
+		if name := scaleSetNameFromProviderID(s.ProviderID()); name != "" {
+			return name
+		}
+	}
 	return s.MachinePoolScope.Name()
 }
 
+// OrchestrationMode is the orchestration mode of the VMSS.
+func (s *MachinePoolMachineScope) OrchestrationMode() infrav1.OrchestrationModeType {
+	return s.AzureMachinePool.Spec.OrchestrationMode
+}
+
+// ProtectionPolicy returns the instance protection to apply to the scale set instance, or nil if the instance protection
+// is not managed by the AzureMachinePoolMachine.
+func (s *MachinePoolMachineScope) ProtectionPolicy() *azure.VMSSVMProtectionPolicy {
+	policy := s.AzureMachinePoolMachine.Spec.ProtectionPolicy
+	if policy == nil {
+		return nil
+	}
+
+	return &azure.VMSSVMProtectionPolicy{
+		ProtectFromScaleIn:         policy.ProtectFromScaleIn,
+		ProtectFromScaleSetActions: policy.ProtectFromScaleSetActions,
+	}
+}
+
+// NodeDiagnosticsSpec returns the specification for collecting node diagnostics from the scale set instance, or nil if
+// node diagnostics are not enabled, the instance did not fail to bootstrap, or diagnostics were already collected.
+func (s *MachinePoolMachineScope) NodeDiagnosticsSpec() *azure.NodeDiagnosticsSpec {
+	if s.AzureMachinePool.Spec.Template.NodeDiagnostics == nil || s.instance == nil || s.instance.BootstrapState != infrav1.Failed {
+		return nil
+	}
+
+	if conditions.Has(s.AzureMachinePoolMachine, infrav1.NodeDiagnosticsCollectedCondition) {
+		return nil
+	}
+
+	if s.OrchestrationMode() == infrav1.FlexibleOrchestrationMode {
+		// instances of a Flexible scale set are regular VMs identified by their VM name
+		return &azure.NodeDiagnosticsSpec{
+			VMName: s.InstanceID(),
+			OSType: s.AzureMachinePool.Spec.Template.OSDisk.OSType,
+		}
+	}
+
+	return &azure.NodeDiagnosticsSpec{
+		ScaleSetName: s.ScaleSetName(),
+		InstanceID:   s.InstanceID(),
+		OSType:       s.AzureMachinePool.Spec.Template.OSDisk.OSType,
+	}
+}
+
+// NodeDiagnosticsOutput returns where the collected node diagnostics should be stored.
+func (s *MachinePoolMachineScope) NodeDiagnosticsOutput() infrav1.NodeDiagnosticsOutput {
+	nodeDiagnostics := s.AzureMachinePool.Spec.Template.NodeDiagnostics
+	if nodeDiagnostics == nil || nodeDiagnostics.Output == "" {
+		return infrav1.NodeDiagnosticsOutputEvent
+	}
+	return nodeDiagnostics.Output
+}
+
+// SetNodeDiagnostics stores the collected node diagnostics in a Secret referenced from the AzureMachinePoolMachine
+// status when the output is Secret, and marks the node diagnostics as collected.
+func (s *MachinePoolMachineScope) SetNodeDiagnostics(ctx context.Context, output string) error {
+	if s.NodeDiagnosticsOutput() == infrav1.NodeDiagnosticsOutputSecret {
+		owner := metav1.OwnerReference{
+			APIVersion: infrav1exp.GroupVersion.String(),
+			Kind:       "AzureMachinePoolMachine",
+			Name:       s.AzureMachinePoolMachine.Name,
+			UID:        s.AzureMachinePoolMachine.UID,
+		}
+		ref, err := reconcileNodeDiagnosticsSecret(ctx, s.client, owner, s.AzureMachinePoolMachine.Namespace, s.ClusterName(), output)
+		if err != nil {
+			return err
+		}
+		s.AzureMachinePoolMachine.Status.NodeDiagnosticsRef = ref
+	}
+	conditions.MarkTrue(s.AzureMachinePoolMachine, infrav1.NodeDiagnosticsCollectedCondition)
+	return nil
+}
+
+// SetNodeDiagnosticsFailed marks the node diagnostics collection as failed so that it is not attempted again.
+func (s *MachinePoolMachineScope) SetNodeDiagnosticsFailed(err error) {
+	conditions.MarkFalse(s.AzureMachinePoolMachine, infrav1.NodeDiagnosticsCollectedCondition, infrav1.NodeDiagnosticsCollectionFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+}
+
+// BootDiagnosticsEnabled returns true if boot diagnostics are enabled on the scale set instance.
+func (s *MachinePoolMachineScope) BootDiagnosticsEnabled() bool {
+	return bootDiagnosticsEnabled(s.AzureMachinePool.Spec.Template.Diagnostics)
+}
+
+// GetSerialConsoleLog retrieves the boot diagnostics serial console log of the scale set instance.
+func (s *MachinePoolMachineScope) GetSerialConsoleLog(ctx context.Context) (string, error) {
+	if !s.BootDiagnosticsEnabled() {
+		return "", errors.New("boot diagnostics are disabled")
+	}
+	if s.serialConsoleLogGetter == nil {
+		s.serialConsoleLogGetter = nodediagnostics.NewSerialConsoleLogGetter(s)
+	}
+	if s.OrchestrationMode() == infrav1.FlexibleOrchestrationMode {
+		return s.serialConsoleLogGetter.GetSerialConsoleLog(ctx, s.ResourceGroup(), s.InstanceID())
+	}
+	return s.serialConsoleLogGetter.GetScaleSetVMSerialConsoleLog(ctx, s.ResourceGroup(), s.ScaleSetName(), s.InstanceID())
+}
+
 // SetLongRunningOperationState will set the future on the AzureMachinePoolMachine status to allow the resource to continue
 // in the next reconciliation.
 func (s *MachinePoolMachineScope) SetLongRunningOperationState(future *infrav1.Future) {
@@ -289,6 +437,11 @@ func (s *MachinePoolMachineScope) UpdateStatus(ctx context.Context) error {
 	if err != nil && !apierrors.IsNotFound(err) {
 		return errors.Wrap(err, "failed to to get node by providerID or object reference")
 	}
+	if err != nil {
+		// the node referenced by the machine no longer exists
+		node = nil
+	}
+	s.node, s.nodeFetched = node, true
 
 	if node != nil {
 		s.AzureMachinePoolMachine.Status.NodeRef = &corev1.ObjectReference{
@@ -311,6 +464,14 @@ func (s *MachinePoolMachineScope) UpdateStatus(ctx context.Context) error {
 
 		s.AzureMachinePoolMachine.Status.LatestModelApplied = hasLatestModel
 		s.AzureMachinePoolMachine.Status.ProvisioningState = &s.instance.State
+		s.AzureMachinePoolMachine.Status.FailureDomain = s.instance.AvailabilityZone
+		s.AzureMachinePoolMachine.Status.ProtectionPolicy = nil
+		if s.instance.ProtectionPolicy != (azure.VMSSVMProtectionPolicy{}) {
+			s.AzureMachinePoolMachine.Status.ProtectionPolicy = &infrav1exp.InstanceProtectionPolicy{
+				ProtectFromScaleIn:         s.instance.ProtectionPolicy.ProtectFromScaleIn,
+				ProtectFromScaleSetActions: s.instance.ProtectionPolicy.ProtectFromScaleSetActions,
+			}
+		}
 	}
 
 	return nil
@@ -324,27 +485,9 @@ func (s *MachinePoolMachineScope) CordonAndDrain(ctx context.Context) error {
 	)
 	defer done()
 
-	var (
-		nodeRef = s.AzureMachinePoolMachine.Status.NodeRef
-		node    *corev1.Node
-		err     error
-	)
-	if nodeRef == nil || nodeRef.Name == "" {
-		node, err = s.workloadNodeGetter.GetNodeByProviderID(ctx, s.ProviderID())
-	} else {
-		node, err = s.workloadNodeGetter.GetNodeByObjectReference(ctx, *nodeRef)
-	}
-
-	switch {
-	case err != nil && !apierrors.IsNotFound(err):
-		// failed due to an unexpected error
-		return errors.Wrap(err, "failed to find node")
-	case err != nil && apierrors.IsNotFound(err):
-		// node was not found due to 404 when finding by ObjectReference
-		return nil
-	case node == nil:
-		// node was not found due to not finding a nodes with the ProviderID
-		return nil
+	node, err := s.getNode(ctx)
+	if err != nil || node == nil {
+		return err
 	}
 
 	// Drain node before deletion and issue a patch in order to make this operation visible to the users.
@@ -366,7 +509,13 @@ func (s *MachinePoolMachineScope) CordonAndDrain(ctx context.Context) error {
 			return errors.Wrap(err, "failed to patch AzureMachinePoolMachine")
 		}
 
-		if err := s.drainNode(ctx, node); err != nil {
+		kubeClient, err := s.getWorkloadClientset(ctx)
+		if err != nil {
+			log.Error(err, "Error creating a remote client while deleting Machine, won't retry")
+			return nil
+		}
+
+		if err := s.drainNode(ctx, kubeClient, node); err != nil {
 			// Check for condition existence. If the condition exists, it may have a different severity or message, which
 			// would cause the last transition time to be updated. The last transition time is used to determine how
 			// long to wait to timeout the node drain operation. If we were to keep updating the last transition time,
@@ -383,28 +532,55 @@ func (s *MachinePoolMachineScope) CordonAndDrain(ctx context.Context) error {
 	return nil
 }
 
-func (s *MachinePoolMachineScope) drainNode(ctx context.Context, node *corev1.Node) error {
-	ctx, log, done := tele.StartSpanWithLogger(
-		ctx,
-		"scope.MachinePoolMachineScope.drainNode",
+// getNode returns the Kubernetes node associated with this AzureMachinePoolMachine, or nil if the node does not exist.
+func (s *MachinePoolMachineScope) getNode(ctx context.Context) (*corev1.Node, error) {
+	var (
+		nodeRef = s.AzureMachinePoolMachine.Status.NodeRef
+		node    *corev1.Node
+		err     error
 	)
-	defer done()
+	if nodeRef == nil || nodeRef.Name == "" {
+		node, err = s.workloadNodeGetter.GetNodeByProviderID(ctx, s.ProviderID())
+	} else {
+		node, err = s.workloadNodeGetter.GetNodeByObjectReference(ctx, *nodeRef)
+	}
+
+	switch {
+	case err != nil && !apierrors.IsNotFound(err):
+		// failed due to an unexpected error
+		return nil, errors.Wrap(err, "failed to find node")
+	case err != nil && apierrors.IsNotFound(err):
+		// node was not found due to 404 when finding by ObjectReference
+		return nil, nil
+	}
+
+	// node is nil if no node has the ProviderID
+	return node, nil
+}
+
+// getWorkloadClientset returns a clientset for the workload cluster.
+func (s *MachinePoolMachineScope) getWorkloadClientset(ctx context.Context) (kubernetes.Interface, error) {
+	if s.workloadClientset != nil {
+		return s.workloadClientset, nil
+	}
 
 	restConfig, err := remote.RESTConfig(ctx, MachinePoolMachineScopeName, s.client, client.ObjectKey{
 		Name:      s.ClusterName(),
 		Namespace: s.AzureMachinePoolMachine.Namespace,
 	})
-
 	if err != nil {
-		log.Error(err, "Error creating a remote client while deleting Machine, won't retry")
-		return nil
+		return nil, err
 	}
 
-	kubeClient, err := kubernetes.NewForConfig(restConfig)
-	if err != nil {
-		log.Error(err, "Error creating a remote client while deleting Machine, won't retry")
-		return nil
-	}
+	return kubernetes.NewForConfig(restConfig)
+}
+
+func (s *MachinePoolMachineScope) drainNode(ctx context.Context, kubeClient kubernetes.Interface, node *corev1.Node) error {
+	ctx, log, done := tele.StartSpanWithLogger(
+		ctx,
+		"scope.MachinePoolMachineScope.drainNode",
+	)
+	defer done()
 
 	drainer := &kubedrain.Helper{
 		Client:              kubeClient,
@@ -428,10 +604,7 @@ func (s *MachinePoolMachineScope) drainNode(ctx context.Context, node *corev1.No
 		ErrOut: writer{klog.Error},
 	}
 
-	if noderefutil.IsNodeUnreachable(node) {
-		// When the node is unreachable and some pods are not evicted for as long as this timeout, we ignore them.
-		drainer.SkipWaitForDeleteTimeoutSeconds = 60 * 5 // 5 minutes
-	}
+	s.applyNodeDrainOptions(drainer, node)
 
 	if err := kubedrain.RunCordonOrUncordon(drainer, node, true); err != nil {
 		// Machine will be re-reconciled after a cordon failure.
@@ -439,6 +612,12 @@ func (s *MachinePoolMachineScope) drainNode(ctx context.Context, node *corev1.No
 	}
 
 	if err := kubedrain.RunNodeDrain(drainer, node.Name); err != nil {
+		blocks, blocksErr := findEvictionBlocks(ctx, drainer, node.Name)
+		if blocksErr != nil {
+			log.V(4).Info("unable to find the PodDisruptionBudgets blocking eviction", "error", blocksErr.Error())
+		}
+		s.recordEvictionBlocks(blocks)
+
 		// Machine will be re-reconciled after a drain failure.
 		return azure.WithTransientError(errors.Wrap(err, "Drain failed, retry in 20s"), 20*time.Second)
 	}
@@ -447,6 +626,271 @@ func (s *MachinePoolMachineScope) drainNode(ctx context.Context, node *corev1.No
 	return nil
 }
 
+// HandleScheduledEvents cordons and drains the node of this AzureMachinePoolMachine ahead of the pending Terminate,
+// Preempt and Reboot scheduled events of its VM, then approves the events so that they start without waiting for
+// their deadline. Once the events are over, a node which was drained for a reboot is uncordoned.
+func (s *MachinePoolMachineScope) HandleScheduledEvents(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(
+		ctx,
+		"scope.MachinePoolMachineScope.HandleScheduledEvents",
+	)
+	defer done()
+
+	s.pendingScheduledEvents = false
+	node := s.node
+	if !s.nodeFetched {
+		var err error
+		if node, err = s.getNode(ctx); err != nil {
+			return err
+		}
+	}
+	if node == nil {
+		return nil
+	}
+
+	events := pendingScheduledEvents(node)
+	if len(events) == 0 {
+		if _, ok := node.Annotations[infrav1exp.ApprovedScheduledEventsAnnotation]; ok {
+			log.V(2).Info("scheduled events are over, uncordoning node", "node", node.Name)
+			if err := s.uncordonAfterScheduledEvents(ctx, node); err != nil {
+				return azure.WithTransientError(errors.Wrap(err, "failed to uncordon node after scheduled events"), 20*time.Second)
+			}
+		}
+
+		if conditions.Has(s.AzureMachinePoolMachine, infrav1.ScheduledEventsHandledCondition) {
+			conditions.MarkTrue(s.AzureMachinePoolMachine, infrav1.ScheduledEventsHandledCondition)
+		}
+		return nil
+	}
+
+	s.pendingScheduledEvents = true
+	description := describeScheduledEvents(events)
+	ids := scheduledEventIDs(events)
+	if approved, ok := node.Annotations[infrav1exp.ApprovedScheduledEventsAnnotation]; ok && approved == ids {
+		// the node has already been drained and the events approved
+		return nil
+	}
+
+	log.Info("draining node ahead of scheduled events", "node", node.Name, "events", description)
+	conditions.MarkFalse(s.AzureMachinePoolMachine, infrav1.ScheduledEventsHandledCondition, infrav1.ScheduledEventDrainingReason, clusterv1.ConditionSeverityInfo, "Draining the node ahead of %s", description)
+	if _, excluded := s.AzureMachinePoolMachine.Annotations[clusterv1.ExcludeNodeDrainingAnnotation]; !excluded {
+		// the events are only approved once the node is actually drained
+		kubeClient, err := s.getWorkloadClientset(ctx)
+		if err != nil {
+			err = azure.WithTransientError(errors.Wrap(err, "failed to create a workload cluster client"), 20*time.Second)
+			conditions.MarkFalse(s.AzureMachinePoolMachine, infrav1.ScheduledEventsHandledCondition, infrav1.ScheduledEventDrainFailedReason, clusterv1.ConditionSeverityWarning, "Failed to drain the node ahead of %s: %v", description, err)
+			return err
+		}
+
+		if err := s.drainNode(ctx, kubeClient, node); err != nil {
+			conditions.MarkFalse(s.AzureMachinePoolMachine, infrav1.ScheduledEventsHandledCondition, infrav1.ScheduledEventDrainFailedReason, clusterv1.ConditionSeverityWarning, "Failed to drain the node ahead of %s: %v", description, err)
+			return err
+		}
+	}
+
+	// events reported by the node problem detector have no ID, the annotation still records that the node was drained
+	if err := s.approveScheduledEvents(ctx, node, ids); err != nil {
+		return azure.WithTransientError(errors.Wrap(err, "failed to approve scheduled events"), 20*time.Second)
+	}
+
+	conditions.MarkTrue(s.AzureMachinePoolMachine, infrav1.ScheduledEventsHandledCondition)
+	return nil
+}
+
+// HasPendingScheduledEvents returns true if the VM had Terminate, Preempt or Reboot scheduled events pending when they
+// were last handled.
+func (s *MachinePoolMachineScope) HasPendingScheduledEvents() bool {
+	return s.pendingScheduledEvents
+}
+
+// approveScheduledEvents records the scheduled events to approve on the node, for the relay running on the node to
+// approve them.
+func (s *MachinePoolMachineScope) approveScheduledEvents(ctx context.Context, node *corev1.Node, ids string) error {
+	kubeClient, err := s.getWorkloadClientset(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to create a workload cluster client")
+	}
+
+	patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, infrav1exp.ApprovedScheduledEventsAnnotation, ids)
+	_, err = kubeClient.CoreV1().Nodes().Patch(ctx, node.Name, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	return err
+}
+
+// uncordonAfterScheduledEvents uncordons a node which was drained ahead of scheduled events which are over, and
+// removes the approved scheduled events from the node.
+func (s *MachinePoolMachineScope) uncordonAfterScheduledEvents(ctx context.Context, node *corev1.Node) error {
+	kubeClient, err := s.getWorkloadClientset(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to create a workload cluster client")
+	}
+
+	drainer := &kubedrain.Helper{
+		Client: kubeClient,
+		Ctx:    ctx,
+		Out:    writer{klog.Info},
+		ErrOut: writer{klog.Error},
+	}
+	if err := kubedrain.RunCordonOrUncordon(drainer, node, false); err != nil {
+		return err
+	}
+
+	patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:null}}}`, infrav1exp.ApprovedScheduledEventsAnnotation)
+	_, err = kubeClient.CoreV1().Nodes().Patch(ctx, node.Name, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	return err
+}
+
+// pendingScheduledEvents returns the Terminate, Preempt and Reboot scheduled events of the VM of a node which have not
+// started yet. The events are reported by a relay on the node through the scheduled events annotation, or by the
+// node problem detector through node conditions, which cannot be approved.
+func pendingScheduledEvents(node *corev1.Node) []scheduledEvent {
+	var events []scheduledEvent
+	if raw, ok := node.Annotations[infrav1exp.ScheduledEventsAnnotation]; ok {
+		var reported []scheduledEvent
+		if err := json.Unmarshal([]byte(raw), &reported); err == nil {
+			events = append(events, reported...)
+		}
+	}
+
+	for _, condition := range node.Status.Conditions {
+		if condition.Status == corev1.ConditionTrue && strings.HasSuffix(string(condition.Type), "Scheduled") {
+			events = append(events, scheduledEvent{EventType: strings.TrimSuffix(string(condition.Type), "Scheduled")})
+		}
+	}
+
+	var pending []scheduledEvent
+	for _, event := range events {
+		switch event.EventType {
+		case "Terminate", "Preempt", "Reboot":
+		default:
+			continue
+		}
+
+		if event.EventStatus == "" || event.EventStatus == "Scheduled" {
+			pending = append(pending, event)
+		}
+	}
+
+	return pending
+}
+
+// scheduledEventIDs returns the sorted comma-separated IDs of scheduled events.
+func scheduledEventIDs(events []scheduledEvent) string {
+	var ids []string
+	for _, event := range events {
+		if event.EventID != "" {
+			ids = append(ids, event.EventID)
+		}
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
+
+// describeScheduledEvents returns a human readable description of scheduled events.
+func describeScheduledEvents(events []scheduledEvent) string {
+	descriptions := make([]string, len(events))
+	for i, event := range events {
+		descriptions[i] = fmt.Sprintf("%s event", event.EventType)
+		if event.EventID != "" {
+			descriptions[i] += " " + event.EventID
+		}
+		if event.NotBefore != "" {
+			descriptions[i] += fmt.Sprintf(" not before %s", event.NotBefore)
+		}
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+// applyNodeDrainOptions configures the drainer according to the node drain options of the AzureMachinePool.
+func (s *MachinePoolMachineScope) applyNodeDrainOptions(drainer *kubedrain.Helper, node *corev1.Node) {
+	if noderefutil.IsNodeUnreachable(node) {
+		// When the node is unreachable and some pods are not evicted for as long as this timeout, we ignore them.
+		drainer.SkipWaitForDeleteTimeoutSeconds = 60 * 5 // 5 minutes
+	}
+
+	if s.AzureMachinePool == nil || s.AzureMachinePool.Spec.NodeDrainOptions == nil {
+		return
+	}
+
+	options := s.AzureMachinePool.Spec.NodeDrainOptions
+	if options.SkipWaitForDeleteTimeout != nil {
+		drainer.SkipWaitForDeleteTimeoutSeconds = int(options.SkipWaitForDeleteTimeout.Seconds())
+	}
+
+	if options.DeleteEmptyDirData != nil {
+		drainer.DeleteEmptyDirData = *options.DeleteEmptyDirData
+	}
+
+	if options.IgnoreDaemonSets != nil {
+		drainer.IgnoreAllDaemonSets = *options.IgnoreDaemonSets
+	}
+
+	if options.GracePeriod != nil {
+		drainer.GracePeriodSeconds = int(options.GracePeriod.Seconds())
+	}
+
+	drainer.PodSelector = options.PodSelector
+}
+
+// recordEvictionBlocks records an event on the AzureMachinePoolMachine for each pod whose eviction is blocked by a
+// PodDisruptionBudget, whether the node is drained before deleting the machine or ahead of a scheduled event.
+func (s *MachinePoolMachineScope) recordEvictionBlocks(blocks []EvictionBlock) {
+	if s.recorder == nil {
+		return
+	}
+
+	for _, block := range blocks {
+		s.recorder.Eventf(s.AzureMachinePoolMachine, corev1.EventTypeWarning, "EvictionBlocked", "Eviction of pod %s is blocked by PodDisruptionBudget %s", block.Pod, block.PodDisruptionBudget)
+	}
+}
+
+// findEvictionBlocks finds the pods left to evict from a node whose eviction is blocked by a PodDisruptionBudget which
+// allows no disruption.
+func findEvictionBlocks(ctx context.Context, drainer *kubedrain.Helper, nodeName string) ([]EvictionBlock, error) {
+	podList, errs := drainer.GetPodsForDeletion(nodeName)
+	if len(errs) > 0 {
+		return nil, kerrors.NewAggregate(errs)
+	}
+
+	var (
+		blocks             []EvictionBlock
+		budgetsByNamespace = map[string][]policyv1.PodDisruptionBudget{}
+	)
+	for _, pod := range podList.Pods() {
+		if pod.DeletionTimestamp != nil {
+			// the pod has already been evicted
+			continue
+		}
+
+		budgets, ok := budgetsByNamespace[pod.Namespace]
+		if !ok {
+			list, err := drainer.Client.PolicyV1().PodDisruptionBudgets(pod.Namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return blocks, errors.Wrapf(err, "failed to list PodDisruptionBudgets in namespace %s", pod.Namespace)
+			}
+			budgets = list.Items
+			budgetsByNamespace[pod.Namespace] = budgets
+		}
+
+		for _, budget := range budgets {
+			if budget.Status.DisruptionsAllowed > 0 {
+				continue
+			}
+
+			selector, err := metav1.LabelSelectorAsSelector(budget.Spec.Selector)
+			if err != nil || !selector.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+
+			blocks = append(blocks, EvictionBlock{
+				Pod:                 fmt.Sprintf("%s/%s", pod.Namespace, pod.Name),
+				PodDisruptionBudget: fmt.Sprintf("%s/%s", budget.Namespace, budget.Name),
+			})
+		}
+	}
+
+	return blocks, nil
+}
+
 // isNodeDrainAllowed checks to see the node is excluded from draining or if the NodeDrainTimeout has expired.
 func (s *MachinePoolMachineScope) isNodeDrainAllowed() bool {
 	if _, exists := s.AzureMachinePoolMachine.ObjectMeta.Annotations[clusterv1.ExcludeNodeDrainingAnnotation]; exists {
@@ -501,10 +945,27 @@ func (s *MachinePoolMachineScope) hasLatestModelApplied(ctx context.Context) (bo
 		return false, errors.New("machinepoolscope image must not be nil")
 	}
 
+	// the VM created from older bootstrap data is rolled out like one running an older image
+	if isBootstrapDataOutdated(s.AzureMachinePool, s.AzureMachinePoolMachine) {
+		return false, nil
+	}
+
 	// if the images match, then the VM is of the same model
 	return reflect.DeepEqual(s.instance.Image, *image), nil
 }
 
+// scaleSetNameFromProviderID returns the name of the scale set of a scale set instance provider ID, or an empty string
+// if the provider ID is not the one of a scale set instance.
+func scaleSetNameFromProviderID(providerID string) string {
+	segments := strings.Split(providerID, "/")
+	for i := 0; i < len(segments)-1; i++ {
+		if strings.EqualFold(segments[i], "virtualMachineScaleSets") {
+			return segments[i+1]
+		}
+	}
+	return ""
+}
+
 func newWorkloadClusterProxy(c client.Client, cluster client.ObjectKey) *workloadClusterProxy {
 	return &workloadClusterProxy{
 		Client:  c,
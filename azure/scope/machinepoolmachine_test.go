@@ -19,14 +19,19 @@ package scope
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+	kubedrain "k8s.io/kubectl/pkg/drain"
 	"sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	mock_scope "sigs.k8s.io/cluster-api-provider-azure/azure/scope/mocks"
@@ -34,6 +39,7 @@ import (
 	gomock2 "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capiv1exp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -264,6 +270,36 @@ func TestMachineScope_UpdateStatus(t *testing.T) {
 				}))
 			},
 		},
+		{
+			Name: "instance protection populates the AMPM status",
+			Setup: func(mockNodeGetter *mock_scope.MocknodeGetter, ampm *infrav1.AzureMachinePoolMachine) (*azure.VMSSVM, *infrav1.AzureMachinePoolMachine) {
+				mockNodeGetter.EXPECT().GetNodeByProviderID(gomock2.AContext(), FakeProviderID).Return(nil, nil)
+				return &azure.VMSSVM{
+					State: v1beta1.Succeeded,
+					Image: v1beta1.Image{
+						Marketplace: &v1beta1.AzureMarketplaceImage{
+							Publisher: "cncf-upstream",
+							Offer:     "capi",
+							SKU:       "k8s-1dot19dot11-ubuntu-1804",
+							Version:   "latest",
+						},
+					},
+					ProtectionPolicy: azure.VMSSVMProtectionPolicy{
+						ProtectFromScaleIn: true,
+					},
+				}, ampm
+			},
+			Verify: func(g *WithT, scope *MachinePoolMachineScope) {
+				succeeded := v1beta1.Succeeded
+				g.Expect(scope.AzureMachinePoolMachine.Status).To(Equal(infrav1.AzureMachinePoolMachineStatus{
+					ProvisioningState:  &succeeded,
+					LatestModelApplied: true,
+					ProtectionPolicy: &infrav1.InstanceProtectionPolicy{
+						ProtectFromScaleIn: true,
+					},
+				}))
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -409,6 +445,353 @@ func TestMachinePoolMachineScope_CordonAndDrain(t *testing.T) {
 	}
 }
 
+func TestMachinePoolMachineScope_applyNodeDrainOptions(t *testing.T) {
+	unreachable := &corev1.Node{
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionUnknown}},
+		},
+	}
+
+	cases := []struct {
+		Name    string
+		Options *infrav1.NodeDrainOptions
+		Node    *corev1.Node
+		Want    kubedrain.Helper
+	}{
+		{
+			Name: "should keep the default drain settings without drain options",
+			Node: &corev1.Node{},
+			Want: kubedrain.Helper{IgnoreAllDaemonSets: true, DeleteEmptyDirData: true, GracePeriodSeconds: -1},
+		},
+		{
+			Name: "should skip waiting for pods of an unreachable node after 5 minutes",
+			Node: unreachable,
+			Want: kubedrain.Helper{IgnoreAllDaemonSets: true, DeleteEmptyDirData: true, GracePeriodSeconds: -1, SkipWaitForDeleteTimeoutSeconds: 300},
+		},
+		{
+			Name: "should apply the drain options",
+			Options: &infrav1.NodeDrainOptions{
+				SkipWaitForDeleteTimeout: &metav1.Duration{Duration: time.Minute},
+				DeleteEmptyDirData:       to.BoolPtr(false),
+				IgnoreDaemonSets:         to.BoolPtr(false),
+				PodSelector:              "app=stateful",
+				GracePeriod:              &metav1.Duration{Duration: 30 * time.Second},
+			},
+			Node: unreachable,
+			Want: kubedrain.Helper{GracePeriodSeconds: 30, SkipWaitForDeleteTimeoutSeconds: 60, PodSelector: "app=stateful"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			g := NewWithT(t)
+			s := &MachinePoolMachineScope{
+				AzureMachinePool: &infrav1.AzureMachinePool{
+					Spec: infrav1.AzureMachinePoolSpec{
+						NodeDrainOptions: c.Options,
+					},
+				},
+			}
+			drainer := &kubedrain.Helper{IgnoreAllDaemonSets: true, DeleteEmptyDirData: true, GracePeriodSeconds: -1}
+			s.applyNodeDrainOptions(drainer, c.Node)
+			g.Expect(*drainer).To(Equal(c.Want))
+		})
+	}
+}
+
+func Test_findEvictionBlocks(t *testing.T) {
+	g := NewWithT(t)
+	pod := func(name string, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       "default",
+				Labels:          labels,
+				OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: name, Controller: to.BoolPtr(true)}},
+			},
+			Spec: corev1.PodSpec{NodeName: "node1"},
+		}
+	}
+	budget := func(name string, disruptionsAllowed int32, matchLabels map[string]string) *policyv1.PodDisruptionBudget {
+		return &policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: matchLabels}},
+			Status:     policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: disruptionsAllowed},
+		}
+	}
+
+	client := kubefake.NewSimpleClientset(
+		pod("db-0", map[string]string{"app": "db"}),
+		pod("web-0", map[string]string{"app": "web"}),
+		pod("cache-0", map[string]string{"app": "cache"}),
+		budget("db", 0, map[string]string{"app": "db"}),
+		budget("web", 1, map[string]string{"app": "web"}),
+	)
+	drainer := &kubedrain.Helper{
+		Ctx:                 context.TODO(),
+		Client:              client,
+		IgnoreAllDaemonSets: true,
+		DeleteEmptyDirData:  true,
+	}
+
+	blocks, err := findEvictionBlocks(context.TODO(), drainer, "node1")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(blocks).To(Equal([]EvictionBlock{{Pod: "default/db-0", PodDisruptionBudget: "default/db"}}))
+
+	// an empty selector matches every pod of the namespace
+	drainer.Client = kubefake.NewSimpleClientset(
+		pod("web-0", map[string]string{"app": "web"}),
+		budget("all", 0, nil),
+	)
+	blocks, err = findEvictionBlocks(context.TODO(), drainer, "node1")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(blocks).To(Equal([]EvictionBlock{{Pod: "default/web-0", PodDisruptionBudget: "default/all"}}))
+}
+
+func TestMachinePoolMachineScope_recordEvictionBlocks(t *testing.T) {
+	g := NewWithT(t)
+	blocks := []EvictionBlock{{Pod: "default/db-0", PodDisruptionBudget: "default/db"}}
+
+	// events are not recorded without a recorder
+	s := &MachinePoolMachineScope{AzureMachinePoolMachine: &infrav1.AzureMachinePoolMachine{}}
+	s.recordEvictionBlocks(blocks)
+
+	recorder := record.NewFakeRecorder(2)
+	s.recorder = recorder
+	s.recordEvictionBlocks(blocks)
+	g.Expect(recorder.Events).To(Receive(Equal("Warning EvictionBlocked Eviction of pod default/db-0 is blocked by PodDisruptionBudget default/db")))
+	g.Expect(recorder.Events).NotTo(Receive())
+}
+
+func TestMachinePoolMachineScope_HandleScheduledEvents(t *testing.T) {
+	cases := []struct {
+		Name   string
+		Setup  func(node *corev1.Node, ampm *infrav1.AzureMachinePoolMachine)
+		Verify func(g *WithT, node *corev1.Node, ampm *infrav1.AzureMachinePoolMachine, pending bool)
+	}{
+		{
+			Name:  "should do nothing without scheduled events",
+			Setup: func(node *corev1.Node, ampm *infrav1.AzureMachinePoolMachine) {},
+			Verify: func(g *WithT, node *corev1.Node, ampm *infrav1.AzureMachinePoolMachine, pending bool) {
+				g.Expect(pending).To(BeFalse())
+				g.Expect(node.Spec.Unschedulable).To(BeFalse())
+				g.Expect(conditions.Has(ampm, v1beta1.ScheduledEventsHandledCondition)).To(BeFalse())
+			},
+		},
+		{
+			Name: "should ignore scheduled events which do not stop the VM",
+			Setup: func(node *corev1.Node, ampm *infrav1.AzureMachinePoolMachine) {
+				node.Annotations[infrav1.ScheduledEventsAnnotation] = `[{"EventId":"e1","EventType":"Freeze","EventStatus":"Scheduled"}]`
+			},
+			Verify: func(g *WithT, node *corev1.Node, ampm *infrav1.AzureMachinePoolMachine, pending bool) {
+				g.Expect(pending).To(BeFalse())
+				g.Expect(node.Spec.Unschedulable).To(BeFalse())
+			},
+		},
+		{
+			Name: "should drain the node and approve a pending Reboot event",
+			Setup: func(node *corev1.Node, ampm *infrav1.AzureMachinePoolMachine) {
+				node.Annotations[infrav1.ScheduledEventsAnnotation] = `[{"EventId":"e2","EventType":"Reboot","EventStatus":"Scheduled"},{"EventId":"e1","EventType":"Terminate","EventStatus":"Scheduled"}]`
+			},
+			Verify: func(g *WithT, node *corev1.Node, ampm *infrav1.AzureMachinePoolMachine, pending bool) {
+				g.Expect(pending).To(BeTrue())
+				g.Expect(node.Spec.Unschedulable).To(BeTrue())
+				g.Expect(node.Annotations).To(HaveKeyWithValue(infrav1.ApprovedScheduledEventsAnnotation, "e1,e2"))
+				g.Expect(conditions.IsTrue(ampm, v1beta1.ScheduledEventsHandledCondition)).To(BeTrue())
+			},
+		},
+		{
+			Name: "should drain the node for a Preempt event reported by the node problem detector",
+			Setup: func(node *corev1.Node, ampm *infrav1.AzureMachinePoolMachine) {
+				node.Status.Conditions = []corev1.NodeCondition{{Type: "PreemptScheduled", Status: corev1.ConditionTrue}}
+			},
+			Verify: func(g *WithT, node *corev1.Node, ampm *infrav1.AzureMachinePoolMachine, pending bool) {
+				g.Expect(pending).To(BeTrue())
+				g.Expect(node.Spec.Unschedulable).To(BeTrue())
+				g.Expect(node.Annotations).To(HaveKeyWithValue(infrav1.ApprovedScheduledEventsAnnotation, ""))
+			},
+		},
+		{
+			Name: "should not drain the node again once the events are approved",
+			Setup: func(node *corev1.Node, ampm *infrav1.AzureMachinePoolMachine) {
+				node.Annotations[infrav1.ScheduledEventsAnnotation] = `[{"EventId":"e1","EventType":"Reboot","EventStatus":"Scheduled"}]`
+				node.Annotations[infrav1.ApprovedScheduledEventsAnnotation] = "e1"
+			},
+			Verify: func(g *WithT, node *corev1.Node, ampm *infrav1.AzureMachinePoolMachine, pending bool) {
+				g.Expect(pending).To(BeTrue())
+				g.Expect(node.Spec.Unschedulable).To(BeFalse())
+			},
+		},
+		{
+			Name: "should uncordon the node once the approved events are over",
+			Setup: func(node *corev1.Node, ampm *infrav1.AzureMachinePoolMachine) {
+				node.Spec.Unschedulable = true
+				node.Annotations[infrav1.ScheduledEventsAnnotation] = `[{"EventId":"e1","EventType":"Reboot","EventStatus":"Started"}]`
+				node.Annotations[infrav1.ApprovedScheduledEventsAnnotation] = "e1"
+				conditions.MarkFalse(ampm, v1beta1.ScheduledEventsHandledCondition, v1beta1.ScheduledEventDrainingReason, clusterv1.ConditionSeverityInfo, "")
+			},
+			Verify: func(g *WithT, node *corev1.Node, ampm *infrav1.AzureMachinePoolMachine, pending bool) {
+				g.Expect(pending).To(BeFalse())
+				g.Expect(node.Spec.Unschedulable).To(BeFalse())
+				g.Expect(node.Annotations).NotTo(HaveKey(infrav1.ApprovedScheduledEventsAnnotation))
+				g.Expect(conditions.IsTrue(ampm, v1beta1.ScheduledEventsHandledCondition)).To(BeTrue())
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			var (
+				g          = NewWithT(t)
+				controller = gomock.NewController(t)
+				nodeGetter = mock_scope.NewMocknodeGetter(controller)
+				node       = &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "node1",
+						Annotations: map[string]string{},
+					},
+				}
+				ampm = &infrav1.AzureMachinePoolMachine{
+					Spec: infrav1.AzureMachinePoolMachineSpec{
+						ProviderID: FakeProviderID,
+					},
+				}
+			)
+			defer controller.Finish()
+
+			c.Setup(node, ampm)
+			clientset := kubefake.NewSimpleClientset(node)
+			nodeGetter.EXPECT().GetNodeByProviderID(gomock2.AContext(), FakeProviderID).Return(node, nil)
+			s := &MachinePoolMachineScope{
+				AzureMachinePoolMachine: ampm,
+				workloadNodeGetter:      nodeGetter,
+				workloadClientset:       clientset,
+			}
+
+			// the node fetched by UpdateStatus is used to handle the scheduled events
+			g.Expect(s.UpdateStatus(context.TODO())).To(Succeed())
+			g.Expect(s.HandleScheduledEvents(context.TODO())).To(Succeed())
+			updated, err := clientset.CoreV1().Nodes().Get(context.TODO(), node.Name, metav1.GetOptions{})
+			g.Expect(err).NotTo(HaveOccurred())
+			c.Verify(g, updated, ampm, s.HasPendingScheduledEvents())
+		})
+	}
+}
+
+func TestMachinePoolMachineScope_HandleScheduledEventsWithoutWorkloadClient(t *testing.T) {
+	var (
+		g          = NewWithT(t)
+		controller = gomock.NewController(t)
+		nodeGetter = mock_scope.NewMocknodeGetter(controller)
+		node       = &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "node1",
+				Annotations: map[string]string{
+					infrav1.ScheduledEventsAnnotation: `[{"EventId":"e1","EventType":"Reboot","EventStatus":"Scheduled"}]`,
+				},
+			},
+		}
+		ampm = &infrav1.AzureMachinePoolMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+			},
+			Spec: infrav1.AzureMachinePoolMachineSpec{
+				ProviderID: FakeProviderID,
+			},
+		}
+	)
+	defer controller.Finish()
+
+	// the kubeconfig of the workload cluster does not exist
+	nodeGetter.EXPECT().GetNodeByProviderID(gomock2.AContext(), FakeProviderID).Return(node, nil)
+	s := &MachinePoolMachineScope{
+		ClusterScoper: &ClusterScope{
+			Cluster: &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster1"}},
+		},
+		AzureMachinePoolMachine: ampm,
+		client:                  fake.NewClientBuilder().Build(),
+		workloadNodeGetter:      nodeGetter,
+	}
+
+	err := s.HandleScheduledEvents(context.TODO())
+	g.Expect(err).To(HaveOccurred())
+	var reconcileErr azure.ReconcileError
+	g.Expect(errors.As(err, &reconcileErr)).To(BeTrue())
+	g.Expect(reconcileErr.IsTransient()).To(BeTrue())
+	g.Expect(s.HasPendingScheduledEvents()).To(BeTrue())
+	g.Expect(conditions.IsFalse(ampm, v1beta1.ScheduledEventsHandledCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(ampm, v1beta1.ScheduledEventsHandledCondition)).To(Equal(v1beta1.ScheduledEventDrainFailedReason))
+}
+
+func TestMachinePoolMachineScope_NodeDiagnosticsSpec(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Setup    func(amp *infrav1.AzureMachinePool, ampm *infrav1.AzureMachinePoolMachine) *azure.VMSSVM
+		Expected *azure.NodeDiagnosticsSpec
+	}{
+		{
+			Name: "returns nil if node diagnostics are not enabled",
+			Setup: func(amp *infrav1.AzureMachinePool, ampm *infrav1.AzureMachinePoolMachine) *azure.VMSSVM {
+				amp.Spec.Template.NodeDiagnostics = nil
+				return &azure.VMSSVM{BootstrapState: v1beta1.Failed}
+			},
+		},
+		{
+			Name: "returns nil if the instance did not fail to bootstrap",
+			Setup: func(amp *infrav1.AzureMachinePool, ampm *infrav1.AzureMachinePoolMachine) *azure.VMSSVM {
+				return &azure.VMSSVM{BootstrapState: v1beta1.Succeeded}
+			},
+		},
+		{
+			Name: "returns nil if node diagnostics were already collected",
+			Setup: func(amp *infrav1.AzureMachinePool, ampm *infrav1.AzureMachinePoolMachine) *azure.VMSSVM {
+				conditions.MarkTrue(ampm, v1beta1.NodeDiagnosticsCollectedCondition)
+				return &azure.VMSSVM{BootstrapState: v1beta1.Failed}
+			},
+		},
+		{
+			Name: "returns the spec if the instance failed to bootstrap",
+			Setup: func(amp *infrav1.AzureMachinePool, ampm *infrav1.AzureMachinePoolMachine) *azure.VMSSVM {
+				return &azure.VMSSVM{BootstrapState: v1beta1.Failed}
+			},
+			Expected: &azure.NodeDiagnosticsSpec{
+				ScaleSetName: "amp",
+				InstanceID:   "2",
+				OSType:       "Windows",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			g := NewWithT(t)
+			amp := &infrav1.AzureMachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "amp"},
+				Spec: infrav1.AzureMachinePoolSpec{
+					Template: infrav1.AzureMachinePoolMachineTemplate{
+						OSDisk:          v1beta1.OSDisk{OSType: "Windows"},
+						NodeDiagnostics: &v1beta1.NodeDiagnostics{},
+					},
+				},
+			}
+			ampm := &infrav1.AzureMachinePoolMachine{
+				Spec: infrav1.AzureMachinePoolMachineSpec{InstanceID: "2"},
+			}
+			instance := c.Setup(amp, ampm)
+			s := &MachinePoolMachineScope{
+				AzureMachinePool:        amp,
+				AzureMachinePoolMachine: ampm,
+				MachinePoolScope: &MachinePoolScope{
+					AzureMachinePool: amp,
+				},
+			}
+			s.SetVMSSVM(instance)
+			g.Expect(s.NodeDiagnosticsSpec()).To(Equal(c.Expected))
+		})
+	}
+}
+
 func getReadyNode() *corev1.Node {
 	return &corev1.Node{
 		ObjectMeta: metav1.ObjectMeta{
@@ -22,10 +22,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"strconv"
 	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-08-01/containerservice"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"golang.org/x/mod/semver"
 	corev1 "k8s.io/api/core/v1"
@@ -118,6 +121,8 @@ type ManagedControlPlaneScope struct {
 	PatchTarget      conditions.Setter
 
 	AllNodePools []infrav1exp.AzureManagedMachinePool
+
+	vnetSpec *infrav1.VnetSpec
 }
 
 // ResourceGroup returns the managed control plane's resource group.
@@ -195,6 +200,9 @@ func (s *ManagedControlPlaneScope) PatchObject(ctx context.Context) error {
 			infrav1.SubnetsReadyCondition,
 			infrav1.ManagedClusterRunningCondition,
 			infrav1.AgentPoolsReadyCondition,
+			infrav1.ManagedClusterUpgradedCondition,
+			infrav1.AgentPoolUpgradedCondition,
+			infrav1.ManagedClusterAdoptedCondition,
 		}})
 }
 
@@ -207,12 +215,25 @@ func (s *ManagedControlPlaneScope) Close(ctx context.Context) error {
 }
 
 // Vnet returns the cluster Vnet.
+// The spec is kept for the lifetime of the scope so that the virtual network service can record the ID and tags
+// of the existing virtual network, which determine whether it is managed.
 func (s *ManagedControlPlaneScope) Vnet() *infrav1.VnetSpec {
-	return &infrav1.VnetSpec{
-		ResourceGroup: s.ControlPlane.Spec.ResourceGroupName,
-		Name:          s.ControlPlane.Spec.VirtualNetwork.Name,
-		CIDRBlocks:    []string{s.ControlPlane.Spec.VirtualNetwork.CIDRBlock},
+	if s.vnetSpec == nil {
+		s.vnetSpec = &infrav1.VnetSpec{
+			ResourceGroup: virtualNetworkResourceGroup(s.ControlPlane),
+			Name:          s.ControlPlane.Spec.VirtualNetwork.Name,
+			CIDRBlocks:    []string{s.ControlPlane.Spec.VirtualNetwork.CIDRBlock},
+		}
+	}
+	return s.vnetSpec
+}
+
+// virtualNetworkResourceGroup returns the resource group of the virtual network of a managed control plane.
+func virtualNetworkResourceGroup(managedControlPlane *infrav1exp.AzureManagedControlPlane) string {
+	if managedControlPlane.Spec.VirtualNetwork.ResourceGroup != "" {
+		return managedControlPlane.Spec.VirtualNetwork.ResourceGroup
 	}
+	return managedControlPlane.Spec.ResourceGroupName
 }
 
 // GroupSpec returns the resource group spec.
@@ -254,13 +275,16 @@ func (s *ManagedControlPlaneScope) NodeNatGateway() infrav1.NatGateway {
 
 // SubnetSpecs returns the subnets specs.
 func (s *ManagedControlPlaneScope) SubnetSpecs() []azure.SubnetSpec {
-	return []azure.SubnetSpec{
-		{
-			Name:     s.NodeSubnet().Name,
-			CIDRs:    s.NodeSubnet().CIDRBlocks,
+	subnets := s.NodeSubnets()
+	specs := make([]azure.SubnetSpec, 0, len(subnets))
+	for _, subnet := range subnets {
+		specs = append(specs, azure.SubnetSpec{
+			Name:     subnet.Name,
+			CIDRs:    subnet.CIDRBlocks,
 			VNetName: s.Vnet().Name,
-		},
+		})
 	}
+	return specs
 }
 
 // Subnets returns the subnets specs.
@@ -287,25 +311,33 @@ func (s *ManagedControlPlaneScope) ControlPlaneSubnet() infrav1.SubnetSpec {
 	return infrav1.SubnetSpec{}
 }
 
-// NodeSubnets returns the subnets with the node role.
+// NodeSubnets returns the subnets with the node role, which are the subnet of the virtual network followed by its
+// additional subnets.
 func (s *ManagedControlPlaneScope) NodeSubnets() []infrav1.SubnetSpec {
-	return []infrav1.SubnetSpec{
-		{
-			Name:       s.ControlPlane.Spec.VirtualNetwork.Subnet.Name,
-			CIDRBlocks: []string{s.ControlPlane.Spec.VirtualNetwork.Subnet.CIDRBlock},
-		},
+	virtualNetwork := s.ControlPlane.Spec.VirtualNetwork
+	subnets := make([]infrav1.SubnetSpec, 0, 1+len(virtualNetwork.Subnets))
+	subnets = append(subnets, infrav1.SubnetSpec{
+		Name:       virtualNetwork.Subnet.Name,
+		CIDRBlocks: []string{virtualNetwork.Subnet.CIDRBlock},
+	})
+	for _, subnet := range virtualNetwork.Subnets {
+		subnets = append(subnets, infrav1.SubnetSpec{
+			Name:       subnet.Name,
+			CIDRBlocks: []string{subnet.CIDRBlock},
+		})
 	}
+	return subnets
 }
 
 // Subnet returns the subnet with the provided name.
 func (s *ManagedControlPlaneScope) Subnet(name string) infrav1.SubnetSpec {
-	subnet := infrav1.SubnetSpec{}
-	if name == s.ControlPlane.Spec.VirtualNetwork.Subnet.Name {
-		subnet.Name = s.ControlPlane.Spec.VirtualNetwork.Subnet.Name
-		subnet.CIDRBlocks = []string{s.ControlPlane.Spec.VirtualNetwork.Subnet.CIDRBlock}
+	for _, subnet := range s.NodeSubnets() {
+		if subnet.Name == name {
+			return subnet
+		}
 	}
 
-	return subnet
+	return infrav1.SubnetSpec{}
 }
 
 // IsIPv6Enabled returns true if a cluster is ipv6 enabled.
@@ -316,7 +348,7 @@ func (s *ManagedControlPlaneScope) IsIPv6Enabled() bool {
 
 // IsVnetManaged returns true if the vnet is managed.
 func (s *ManagedControlPlaneScope) IsVnetManaged() bool {
-	return true
+	return s.Vnet().IsManaged(s.ClusterName())
 }
 
 // APIServerLBName returns the API Server LB spec.
@@ -368,24 +400,35 @@ func (s *ManagedControlPlaneScope) FailureDomains() []string {
 }
 
 // ManagedClusterSpec returns the managed cluster spec.
-func (s *ManagedControlPlaneScope) ManagedClusterSpec() (azure.ManagedClusterSpec, error) {
+func (s *ManagedControlPlaneScope) ManagedClusterSpec(ctx context.Context) (azure.ManagedClusterSpec, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scope.ManagedControlPlaneScope.ManagedClusterSpec")
+	defer done()
+
 	decodedSSHPublicKey, err := base64.StdEncoding.DecodeString(s.ControlPlane.Spec.SSHPublicKey)
 	if err != nil {
 		return azure.ManagedClusterSpec{}, errors.Wrap(err, "failed to decode SSHPublicKey")
 	}
 
+	// The AKS cluster is tagged as owned by the cluster, so that existing AKS clusters that were not created by CAPZ
+	// are only modified once their adoption is confirmed.
+	tags := infrav1.Build(infrav1.BuildParams{
+		ClusterName: s.ClusterName(),
+		Lifecycle:   infrav1.ResourceLifecycleOwned,
+		Additional:  s.AdditionalTags(),
+	})
+
 	managedClusterSpec := azure.ManagedClusterSpec{
 		Name:                  s.ControlPlane.Name,
 		ResourceGroupName:     s.ControlPlane.Spec.ResourceGroupName,
 		NodeResourceGroupName: s.ControlPlane.Spec.NodeResourceGroupName,
 		Location:              s.ControlPlane.Spec.Location,
-		Tags:                  s.ControlPlane.Spec.AdditionalTags,
+		Tags:                  tags,
 		Version:               strings.TrimPrefix(s.ControlPlane.Spec.Version, "v"),
 		SSHPublicKey:          string(decodedSSHPublicKey),
 		DNSServiceIP:          s.ControlPlane.Spec.DNSServiceIP,
 		VnetSubnetID: azure.SubnetID(
 			s.ControlPlane.Spec.SubscriptionID,
-			s.ControlPlane.Spec.ResourceGroupName,
+			virtualNetworkResourceGroup(s.ControlPlane),
 			s.ControlPlane.Spec.VirtualNetwork.Name,
 			s.ControlPlane.Spec.VirtualNetwork.Subnet.Name,
 		),
@@ -400,6 +443,9 @@ func (s *ManagedControlPlaneScope) ManagedClusterSpec() (azure.ManagedClusterSpe
 	if s.ControlPlane.Spec.LoadBalancerSKU != nil {
 		managedClusterSpec.LoadBalancerSKU = *s.ControlPlane.Spec.LoadBalancerSKU
 	}
+	if s.ControlPlane.Spec.OutboundType != nil {
+		managedClusterSpec.OutboundType = string(*s.ControlPlane.Spec.OutboundType)
+	}
 
 	if clusterNetwork := s.Cluster.Spec.ClusterNetwork; clusterNetwork != nil {
 		if clusterNetwork.Services != nil {
@@ -424,6 +470,14 @@ func (s *ManagedControlPlaneScope) ManagedClusterSpec() (azure.ManagedClusterSpe
 		}
 	}
 
+	// The CIDRs of the control plane take precedence over the ones of the cluster network.
+	if s.ControlPlane.Spec.PodCIDR != nil {
+		managedClusterSpec.PodCIDR = *s.ControlPlane.Spec.PodCIDR
+	}
+	if s.ControlPlane.Spec.ServiceCIDR != nil {
+		managedClusterSpec.ServiceCIDR = *s.ControlPlane.Spec.ServiceCIDR
+	}
+
 	if s.ControlPlane.Spec.DNSServiceIP != nil {
 		if managedClusterSpec.ServiceCIDR == "" {
 			return azure.ManagedClusterSpec{}, fmt.Errorf(s.Cluster.Name + " cluster serviceCIDR must be specified if specifying DNSServiceIP")
@@ -471,9 +525,196 @@ func (s *ManagedControlPlaneScope) ManagedClusterSpec() (azure.ManagedClusterSpe
 		}
 	}
 
+	if s.ControlPlane.Spec.WindowsProfile != nil {
+		adminPassword, err := s.windowsAdminPassword(ctx)
+		if err != nil {
+			return azure.ManagedClusterSpec{}, err
+		}
+		managedClusterSpec.WindowsProfile = &azure.WindowsProfile{
+			AdminUsername: s.ControlPlane.Spec.WindowsProfile.AdminUsername,
+			AdminPassword: adminPassword,
+		}
+	}
+
+	managedClusterSpec.AddonProfiles = s.addonProfiles()
+	managedClusterSpec.AutoScalerProfile = s.autoScalerProfile()
+
+	if s.ControlPlane.Spec.AutoUpgradeProfile != nil {
+		managedClusterSpec.AutoUpgradeChannel = to.StringPtr(string(s.ControlPlane.Spec.AutoUpgradeProfile.UpgradeChannel))
+	}
+
+	if identity := s.ControlPlane.Spec.Identity; identity != nil && identity.Type == infrav1exp.ManagedControlPlaneIdentityTypeUserAssigned {
+		managedClusterSpec.UserAssignedIdentity = identity.UserAssignedIdentityResourceID
+		managedClusterSpec.KubeletUserAssignedIdentity = identity.KubeletIdentityResourceID
+	}
+
 	return managedClusterSpec, nil
 }
 
+// RoleAssignmentSpecs returns the role assignments that the user-assigned identities of the control plane and of the
+// kubelets require: Network Contributor on the subnets of the cluster and Managed Identity Operator on the kubelet
+// identity for the control plane identity, and AcrPull on the container registries for the kubelet identity.
+// The roles required by system-assigned identities are assigned by AKS.
+func (s *ManagedControlPlaneScope) RoleAssignmentSpecs() []azure.RoleAssignmentSpec {
+	identity := s.ControlPlane.Spec.Identity
+	if identity == nil || identity.Type != infrav1exp.ManagedControlPlaneIdentityTypeUserAssigned {
+		return []azure.RoleAssignmentSpec{}
+	}
+
+	var specs []azure.RoleAssignmentSpec
+	assign := func(identityResourceID, scope, roleDefinitionID string) {
+		specs = append(specs, azure.RoleAssignmentSpec{
+			Name:               roleAssignmentName(identityResourceID, scope, roleDefinitionID),
+			ResourceType:       azure.UserAssignedIdentity,
+			IdentityResourceID: identityResourceID,
+			Scope:              scope,
+			RoleDefinitionID:   roleDefinitionID,
+		})
+	}
+
+	for _, subnet := range s.NodeSubnets() {
+		subnetID := azure.SubnetID(s.SubscriptionID(), s.Vnet().ResourceGroup, s.Vnet().Name, subnet.Name)
+		assign(identity.UserAssignedIdentityResourceID, subnetID, azure.NetworkContributorRoleID)
+	}
+
+	if identity.KubeletIdentityResourceID != "" {
+		assign(identity.UserAssignedIdentityResourceID, identity.KubeletIdentityResourceID, azure.ManagedIdentityOperatorRoleID)
+		for _, registryID := range identity.ContainerRegistryResourceIDs {
+			assign(identity.KubeletIdentityResourceID, registryID, azure.AcrPullRoleID)
+		}
+	}
+
+	return specs
+}
+
+// roleAssignmentName returns a role assignment name that is unique to the identity, scope and role, so that the role
+// assignment is created only once.
+func roleAssignmentName(identityResourceID, scope, roleDefinitionID string) string {
+	name := strings.ToLower(strings.Join([]string{identityResourceID, scope, roleDefinitionID}, "|"))
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(name)).String()
+}
+
+// autoScalerProfile returns the cluster autoscaler parameters configured on the control plane, formatted as AKS
+// expects them.
+func (s *ManagedControlPlaneScope) autoScalerProfile() *azure.AutoScalerProfile {
+	profile := s.ControlPlane.Spec.AutoScalerProfile
+	if profile == nil {
+		return nil
+	}
+
+	formatBool := func(b *bool) *string {
+		if b == nil {
+			return nil
+		}
+		return to.StringPtr(strconv.FormatBool(*b))
+	}
+	formatInt32 := func(i *int32) *string {
+		if i == nil {
+			return nil
+		}
+		return to.StringPtr(strconv.FormatInt(int64(*i), 10))
+	}
+
+	autoScalerProfile := &azure.AutoScalerProfile{
+		BalanceSimilarNodeGroups:      formatBool(profile.BalanceSimilarNodeGroups),
+		MaxEmptyBulkDelete:            formatInt32(profile.MaxEmptyBulkDelete),
+		MaxGracefulTerminationSec:     formatInt32(profile.MaxGracefulTerminationSec),
+		MaxNodeProvisionTime:          profile.MaxNodeProvisionTime,
+		MaxTotalUnreadyPercentage:     formatInt32(profile.MaxTotalUnreadyPercentage),
+		NewPodScaleUpDelay:            profile.NewPodScaleUpDelay,
+		OkTotalUnreadyCount:           formatInt32(profile.OkTotalUnreadyCount),
+		ScanInterval:                  profile.ScanInterval,
+		ScaleDownDelayAfterAdd:        profile.ScaleDownDelayAfterAdd,
+		ScaleDownDelayAfterDelete:     profile.ScaleDownDelayAfterDelete,
+		ScaleDownDelayAfterFailure:    profile.ScaleDownDelayAfterFailure,
+		ScaleDownUnneededTime:         profile.ScaleDownUnneededTime,
+		ScaleDownUnreadyTime:          profile.ScaleDownUnreadyTime,
+		ScaleDownUtilizationThreshold: profile.ScaleDownUtilizationThreshold,
+		SkipNodesWithLocalStorage:     formatBool(profile.SkipNodesWithLocalStorage),
+		SkipNodesWithSystemPods:       formatBool(profile.SkipNodesWithSystemPods),
+	}
+	if profile.Expander != nil {
+		autoScalerProfile.Expander = to.StringPtr(string(*profile.Expander))
+	}
+
+	return autoScalerProfile
+}
+
+// addonProfiles returns the profiles of the add-ons configured on the control plane, including the add-ons that
+// have a dedicated field.
+func (s *ManagedControlPlaneScope) addonProfiles() []azure.AddonProfile {
+	spec := s.ControlPlane.Spec
+	addonProfiles := make([]azure.AddonProfile, 0, len(spec.AddonProfiles))
+	for _, addon := range spec.AddonProfiles {
+		addonProfiles = append(addonProfiles, azure.AddonProfile{
+			Name:    addon.Name,
+			Enabled: addon.Enabled,
+			Config:  addon.Config,
+		})
+	}
+
+	if spec.EnableAzurePolicy != nil {
+		addonProfiles = append(addonProfiles, azure.AddonProfile{
+			Name:    infrav1exp.AzurePolicyAddonName,
+			Enabled: *spec.EnableAzurePolicy,
+		})
+	}
+
+	if spec.EnableHTTPApplicationRouting != nil {
+		addonProfiles = append(addonProfiles, azure.AddonProfile{
+			Name:    infrav1exp.HTTPApplicationRoutingAddonName,
+			Enabled: *spec.EnableHTTPApplicationRouting,
+		})
+	}
+
+	if kv := spec.KeyVaultSecretsProvider; kv != nil {
+		addonProfile := azure.AddonProfile{
+			Name:    infrav1exp.KeyVaultSecretsProviderAddonName,
+			Enabled: kv.Enabled,
+			Config:  map[string]string{},
+		}
+		if kv.EnableSecretRotation != nil {
+			addonProfile.Config["enableSecretRotation"] = strconv.FormatBool(*kv.EnableSecretRotation)
+		}
+		if kv.RotationPollInterval != nil {
+			addonProfile.Config["rotationPollInterval"] = *kv.RotationPollInterval
+		}
+		addonProfiles = append(addonProfiles, addonProfile)
+	}
+
+	if monitoring := spec.Monitoring; monitoring != nil {
+		addonProfile := azure.AddonProfile{
+			Name:    infrav1exp.MonitoringAddonName,
+			Enabled: monitoring.Enabled,
+			Config:  map[string]string{},
+		}
+		if monitoring.LogAnalyticsWorkspaceResourceID != nil {
+			addonProfile.Config["logAnalyticsWorkspaceResourceID"] = *monitoring.LogAnalyticsWorkspaceResourceID
+		}
+		addonProfiles = append(addonProfiles, addonProfile)
+	}
+
+	return addonProfiles
+}
+
+// windowsAdminPassword reads the Windows administrator password from the Secret referenced by the control plane.
+func (s *ManagedControlPlaneScope) windowsAdminPassword(ctx context.Context) (string, error) {
+	ref := s.ControlPlane.Spec.WindowsProfile.AdminPasswordSecretRef
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{
+		Namespace: s.ControlPlane.Namespace,
+		Name:      ref.Name,
+	}
+	if err := s.Client.Get(ctx, key, secret); err != nil {
+		return "", errors.Wrapf(err, "failed to get Windows admin password secret %s", key)
+	}
+	password, ok := secret.Data[ref.Key]
+	if !ok || len(password) == 0 {
+		return "", errors.Errorf("Windows admin password secret %s has no value for key %q", key, ref.Key)
+	}
+	return string(password), nil
+}
+
 // GetAllAgentPoolSpecs gets a slice of azure.AgentPoolSpec for the list of agent pools.
 func (s *ManagedControlPlaneScope) GetAllAgentPoolSpecs(ctx context.Context) ([]azure.AgentPoolSpec, error) {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "scope.ManagedControlPlaneScope.GetAllAgentPoolSpecs")
@@ -553,22 +794,26 @@ func buildAgentPoolSpec(managedControlPlane *infrav1exp.AzureManagedControlPlane
 	}
 
 	agentPoolSpec := azure.AgentPoolSpec{
-		Name:          to.String(managedMachinePool.Spec.Name),
-		ResourceGroup: managedControlPlane.Spec.ResourceGroupName,
-		Cluster:       managedControlPlane.Name,
-		SKU:           managedMachinePool.Spec.SKU,
-		Replicas:      replicas,
-		Version:       normalizedVersion,
-		VnetSubnetID: azure.SubnetID(
-			managedControlPlane.Spec.SubscriptionID,
-			managedControlPlane.Spec.ResourceGroupName,
-			managedControlPlane.Spec.VirtualNetwork.Name,
-			managedControlPlane.Spec.VirtualNetwork.Subnet.Name,
-		),
+		Name:              to.String(managedMachinePool.Spec.Name),
+		ResourceGroup:     managedControlPlane.Spec.ResourceGroupName,
+		Cluster:           managedControlPlane.Name,
+		SKU:               managedMachinePool.Spec.SKU,
+		Replicas:          replicas,
+		Version:           normalizedVersion,
+		VnetSubnetID:      agentPoolSubnetID(managedControlPlane, managedMachinePool.Spec.SubnetName),
 		Mode:              managedMachinePool.Spec.Mode,
 		MaxPods:           managedMachinePool.Spec.MaxPods,
 		AvailabilityZones: managedMachinePool.Spec.AvailabilityZones,
 		OsDiskType:        managedMachinePool.Spec.OsDiskType,
+		OSType:            managedMachinePool.Spec.OSType,
+	}
+
+	if managedMachinePool.Spec.PodSubnetName != nil {
+		agentPoolSpec.PodSubnetID = to.StringPtr(agentPoolSubnetID(managedControlPlane, managedMachinePool.Spec.PodSubnetName))
+	}
+
+	if managedMachinePool.Spec.UpgradeSettings != nil {
+		agentPoolSpec.MaxSurge = managedMachinePool.Spec.UpgradeSettings.MaxSurge
 	}
 
 	if managedMachinePool.Spec.OSDiskSizeGB != nil {
@@ -588,9 +833,82 @@ func buildAgentPoolSpec(managedControlPlane *infrav1exp.AzureManagedControlPlane
 		}
 	}
 
+	if taints := agentPoolTaints(managedMachinePool); len(taints) > 0 {
+		agentPoolSpec.NodeTaints = make([]string, 0, len(taints))
+		for _, taint := range taints {
+			agentPoolSpec.NodeTaints = append(agentPoolSpec.NodeTaints, fmt.Sprintf("%s=%s:%s", taint.Key, taint.Value, taint.Effect))
+		}
+	}
+
+	if spotVMOptions := managedMachinePool.Spec.SpotVMOptions; spotVMOptions != nil {
+		agentPoolSpec.ScaleSetPriority = to.StringPtr(string(containerservice.ScaleSetPrioritySpot))
+		agentPoolSpec.ScaleSetEvictionPolicy = to.StringPtr(string(containerservice.ScaleSetEvictionPolicyDelete))
+		if spotVMOptions.MaxPrice != nil {
+			agentPoolSpec.SpotMaxPrice = to.Float64Ptr(spotVMOptions.MaxPrice.AsApproximateFloat64())
+		}
+	}
+
+	if kubeletConfig := managedMachinePool.Spec.KubeletConfig; kubeletConfig != nil {
+		agentPoolSpec.KubeletConfig = &azure.KubeletConfig{
+			CPUManagerPolicy:      kubeletConfig.CPUManagerPolicy,
+			CPUCfsQuota:           kubeletConfig.CPUCfsQuota,
+			CPUCfsQuotaPeriod:     kubeletConfig.CPUCfsQuotaPeriod,
+			ImageGcHighThreshold:  kubeletConfig.ImageGcHighThreshold,
+			ImageGcLowThreshold:   kubeletConfig.ImageGcLowThreshold,
+			TopologyManagerPolicy: kubeletConfig.TopologyManagerPolicy,
+			AllowedUnsafeSysctls:  kubeletConfig.AllowedUnsafeSysctls,
+			FailSwapOn:            kubeletConfig.FailSwapOn,
+			ContainerLogMaxSizeMB: kubeletConfig.ContainerLogMaxSizeMB,
+			ContainerLogMaxFiles:  kubeletConfig.ContainerLogMaxFiles,
+			PodMaxPids:            kubeletConfig.PodMaxPids,
+		}
+	}
+
+	if linuxOSConfig := managedMachinePool.Spec.LinuxOSConfig; linuxOSConfig != nil {
+		agentPoolSpec.LinuxOSConfig = &azure.LinuxOSConfig{
+			SwapFileSizeMB:             linuxOSConfig.SwapFileSizeMB,
+			TransparentHugePageDefrag:  linuxOSConfig.TransparentHugePageDefrag,
+			TransparentHugePageEnabled: linuxOSConfig.TransparentHugePageEnabled,
+		}
+		if linuxOSConfig.Sysctls != nil {
+			// The API and azure sysctl types have identical fields.
+			sysctls := azure.SysctlConfig(*linuxOSConfig.Sysctls)
+			agentPoolSpec.LinuxOSConfig.Sysctls = &sysctls
+		}
+	}
+
 	return agentPoolSpec
 }
 
+// agentPoolSubnetID returns the ID of the subnet with the provided name in the virtual network of the managed control
+// plane, or of the subnet of the virtual network when no name is provided.
+func agentPoolSubnetID(managedControlPlane *infrav1exp.AzureManagedControlPlane, subnetName *string) string {
+	name := managedControlPlane.Spec.VirtualNetwork.Subnet.Name
+	if subnetName != nil {
+		name = *subnetName
+	}
+	return azure.SubnetID(
+		managedControlPlane.Spec.SubscriptionID,
+		virtualNetworkResourceGroup(managedControlPlane),
+		managedControlPlane.Spec.VirtualNetwork.Name,
+		name,
+	)
+}
+
+// agentPoolTaints returns the taints of the nodes of an agent pool, including the SpotTaint that AKS adds to Spot agent pools.
+func agentPoolTaints(managedMachinePool *infrav1exp.AzureManagedMachinePool) infrav1exp.Taints {
+	taints := managedMachinePool.Spec.Taints
+	if managedMachinePool.Spec.SpotVMOptions == nil {
+		return taints
+	}
+	for _, taint := range taints {
+		if taint == infrav1exp.SpotTaint {
+			return taints
+		}
+	}
+	return append(append(infrav1exp.Taints{}, taints...), infrav1exp.SpotTaint)
+}
+
 // SetAgentPoolProviderIDList sets a list of agent pool's Azure VM IDs.
 func (s *ManagedControlPlaneScope) SetAgentPoolProviderIDList(providerIDs []string) {
 	s.InfraMachinePool.Spec.ProviderIDList = providerIDs
@@ -606,6 +924,67 @@ func (s *ManagedControlPlaneScope) SetAgentPoolReady(ready bool) {
 	s.InfraMachinePool.Status.Ready = ready
 }
 
+// SetAgentPoolTaints sets the observed taints of the agent pool nodes.
+func (s *ManagedControlPlaneScope) SetAgentPoolTaints(taints infrav1exp.Taints) {
+	s.InfraMachinePool.Status.Taints = taints
+}
+
+// ControlPlaneVersion returns the Kubernetes version of the control plane as last reported by AKS.
+func (s *ManagedControlPlaneScope) ControlPlaneVersion() string {
+	return s.ControlPlane.Status.Version
+}
+
+// AutoUpgradesKubernetesVersion returns whether the auto-upgrade channel of the cluster upgrades the Kubernetes version
+// of the control plane and of the agent pools.
+func (s *ManagedControlPlaneScope) AutoUpgradesKubernetesVersion() bool {
+	return s.ControlPlane.Spec.AutoUpgradeProfile != nil && s.ControlPlane.Spec.AutoUpgradeProfile.UpgradeChannel.UpgradesKubernetesVersion()
+}
+
+// SetControlPlaneVersion sets the Kubernetes version of the control plane as reported by AKS.
+func (s *ManagedControlPlaneScope) SetControlPlaneVersion(version string) {
+	s.ControlPlane.Status.Version = version
+}
+
+// SetUpgradeCondition marks the provided upgrade condition on the patch target as true when reason is empty, or as
+// false with the reason, severity and message otherwise.
+func (s *ManagedControlPlaneScope) SetUpgradeCondition(condition clusterv1.ConditionType, reason string, severity clusterv1.ConditionSeverity, message string) {
+	if reason == "" {
+		conditions.MarkTrue(s.PatchTarget, condition)
+		return
+	}
+	conditions.MarkFalse(s.PatchTarget, condition, reason, severity, "%s", message)
+}
+
+// ManagedClusterAdoptionConfirmed returns true if CAPZ may take ownership of an existing AKS cluster that is not tagged
+// as owned by the cluster, either because the user confirmed its adoption with the adopt annotation, or because the
+// control plane was already initialized by an earlier release of CAPZ, which did not tag the AKS clusters it created.
+func (s *ManagedControlPlaneScope) ManagedClusterAdoptionConfirmed() bool {
+	return s.ControlPlane.Annotations[infrav1exp.AdoptAnnotation] == "true" || s.ControlPlane.Status.Initialized
+}
+
+// SetAdoptionCondition marks the ManagedClusterAdopted condition on the patch target as true when reason is empty, or
+// as false with the reason, severity and message otherwise.
+func (s *ManagedControlPlaneScope) SetAdoptionCondition(reason string, severity clusterv1.ConditionSeverity, message string) {
+	if reason == "" {
+		conditions.MarkTrue(s.PatchTarget, infrav1.ManagedClusterAdoptedCondition)
+		return
+	}
+	conditions.MarkFalse(s.PatchTarget, infrav1.ManagedClusterAdoptedCondition, reason, severity, "%s", message)
+}
+
+// WindowsAdminPasswordHash returns the hash of the Windows administrator password last applied to the managed cluster.
+func (s *ManagedControlPlaneScope) WindowsAdminPasswordHash() string {
+	return s.ControlPlane.Annotations[infrav1exp.WindowsAdminPasswordHashAnnotation]
+}
+
+// SetWindowsAdminPasswordHash records the hash of the Windows administrator password applied to the managed cluster.
+func (s *ManagedControlPlaneScope) SetWindowsAdminPasswordHash(hash string) {
+	if s.ControlPlane.Annotations == nil {
+		s.ControlPlane.Annotations = map[string]string{}
+	}
+	s.ControlPlane.Annotations[infrav1exp.WindowsAdminPasswordHashAnnotation] = hash
+}
+
 // SetControlPlaneEndpoint sets a control plane endpoint.
 func (s *ManagedControlPlaneScope) SetControlPlaneEndpoint(endpoint clusterv1.APIEndpoint) {
 	s.ControlPlane.Spec.ControlPlaneEndpoint = endpoint
@@ -20,16 +20,20 @@ import (
 	"context"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
+	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-08-01/containerservice"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/to"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	infrav1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capiv1exp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -562,6 +566,508 @@ func TestManagedControlPlaneScope_OSDiskType(t *testing.T) {
 	}
 }
 
+func TestManagedControlPlaneScope_TaintsAndNodeConfig(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = capiv1exp.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	managedPool := getAzureMachinePool("pool1", infrav1.NodePoolModeSystem)
+	managedPool.Spec.Taints = infrav1.Taints{
+		{
+			Key:    "CriticalAddonsOnly",
+			Value:  "true",
+			Effect: infrav1.TaintEffectNoSchedule,
+		},
+	}
+	managedPool.Spec.KubeletConfig = &infrav1.KubeletConfig{
+		CPUManagerPolicy:     to.StringPtr("static"),
+		AllowedUnsafeSysctls: []string{"net.core.*"},
+	}
+	managedPool.Spec.LinuxOSConfig = &infrav1.LinuxOSConfig{
+		TransparentHugePageEnabled: to.StringPtr("never"),
+		Sysctls: &infrav1.SysctlConfig{
+			NetCoreSomaxconn:         to.Int32Ptr(16384),
+			NetIpv4TCPKeepaliveIntvl: to.Int32Ptr(30),
+		},
+	}
+
+	input := ManagedControlPlaneScopeParams{
+		AzureClients: AzureClients{
+			Authorizer: autorest.NullAuthorizer{},
+		},
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster1",
+				Namespace: "default",
+			},
+		},
+		ControlPlane: &infrav1.AzureManagedControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster1",
+				Namespace: "default",
+			},
+			Spec: infrav1.AzureManagedControlPlaneSpec{
+				SubscriptionID: "00000000-0000-0000-0000-000000000000",
+			},
+		},
+		MachinePool:      getMachinePool("pool1"),
+		InfraMachinePool: managedPool,
+		PatchTarget:      managedPool,
+	}
+
+	g := NewWithT(t)
+	input.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(input.MachinePool, input.InfraMachinePool, input.ControlPlane).Build()
+	s, err := NewManagedControlPlaneScope(context.TODO(), input)
+	g.Expect(err).To(Succeed())
+	agentPool := s.AgentPoolSpec()
+	g.Expect(agentPool.NodeTaints).To(Equal([]string{"CriticalAddonsOnly=true:NoSchedule"}))
+	g.Expect(agentPool.KubeletConfig).To(Equal(&azure.KubeletConfig{
+		CPUManagerPolicy:     to.StringPtr("static"),
+		AllowedUnsafeSysctls: []string{"net.core.*"},
+	}))
+	g.Expect(agentPool.LinuxOSConfig).To(Equal(&azure.LinuxOSConfig{
+		TransparentHugePageEnabled: to.StringPtr("never"),
+		Sysctls: &azure.SysctlConfig{
+			NetCoreSomaxconn:         to.Int32Ptr(16384),
+			NetIpv4TCPKeepaliveIntvl: to.Int32Ptr(30),
+		},
+	}))
+}
+
+func TestManagedControlPlaneScope_SpotVMOptions(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = capiv1exp.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	maxPrice := resource.MustParse("0.25")
+	managedPool := getAzureMachinePool("pool1", infrav1.NodePoolModeUser)
+	managedPool.Spec.SpotVMOptions = &infrav1beta1.SpotVMOptions{
+		MaxPrice: &maxPrice,
+	}
+	managedPool.Spec.Taints = infrav1.Taints{
+		{
+			Key:    "dedicated",
+			Value:  "batch",
+			Effect: infrav1.TaintEffectNoExecute,
+		},
+	}
+
+	input := ManagedControlPlaneScopeParams{
+		AzureClients: AzureClients{
+			Authorizer: autorest.NullAuthorizer{},
+		},
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster1",
+				Namespace: "default",
+			},
+		},
+		ControlPlane: &infrav1.AzureManagedControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster1",
+				Namespace: "default",
+			},
+			Spec: infrav1.AzureManagedControlPlaneSpec{
+				SubscriptionID: "00000000-0000-0000-0000-000000000000",
+			},
+		},
+		MachinePool:      getMachinePool("pool1"),
+		InfraMachinePool: managedPool,
+		PatchTarget:      managedPool,
+	}
+
+	g := NewWithT(t)
+	input.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(input.MachinePool, input.InfraMachinePool, input.ControlPlane).Build()
+	s, err := NewManagedControlPlaneScope(context.TODO(), input)
+	g.Expect(err).To(Succeed())
+	agentPool := s.AgentPoolSpec()
+	g.Expect(agentPool.ScaleSetPriority).To(Equal(to.StringPtr("Spot")))
+	g.Expect(agentPool.ScaleSetEvictionPolicy).To(Equal(to.StringPtr("Delete")))
+	g.Expect(agentPool.SpotMaxPrice).To(Equal(to.Float64Ptr(0.25)))
+	g.Expect(agentPool.NodeTaints).To(Equal([]string{
+		"dedicated=batch:NoExecute",
+		"kubernetes.azure.com/scalesetpriority=spot:NoSchedule",
+	}))
+	g.Expect(managedPool.Spec.Taints).To(HaveLen(1))
+}
+
+func TestManagedControlPlaneScope_WindowsProfile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = capiv1exp.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	windowsProfile := &infrav1.ManagedControlPlaneWindowsProfile{
+		AdminUsername: "azureuser",
+		AdminPasswordSecretRef: corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "windows-password"},
+			Key:                  "password",
+		},
+	}
+
+	cases := []struct {
+		Name     string
+		Profile  *infrav1.ManagedControlPlaneWindowsProfile
+		Secret   *corev1.Secret
+		Expected *azure.WindowsProfile
+		Err      string
+	}{
+		{
+			Name: "Without WindowsProfile",
+		},
+		{
+			Name:    "With WindowsProfile",
+			Profile: windowsProfile,
+			Secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "windows-password",
+					Namespace: "default",
+				},
+				Data: map[string][]byte{
+					"password": []byte("P@ssw0rd1234!"),
+				},
+			},
+			Expected: &azure.WindowsProfile{
+				AdminUsername: "azureuser",
+				AdminPassword: "P@ssw0rd1234!",
+			},
+		},
+		{
+			Name:    "With WindowsProfile and missing Secret",
+			Profile: windowsProfile,
+			Err:     "failed to get Windows admin password secret default/windows-password",
+		},
+		{
+			Name:    "With WindowsProfile and missing Secret key",
+			Profile: windowsProfile,
+			Secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "windows-password",
+					Namespace: "default",
+				},
+				Data: map[string][]byte{
+					"other": []byte("P@ssw0rd1234!"),
+				},
+			},
+			Err: `Windows admin password secret default/windows-password has no value for key "password"`,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			g := NewWithT(t)
+			input := ManagedControlPlaneScopeParams{
+				AzureClients: AzureClients{
+					Authorizer: autorest.NullAuthorizer{},
+				},
+				Cluster: &clusterv1.Cluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+				},
+				ControlPlane: &infrav1.AzureManagedControlPlane{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "cluster1",
+						Namespace: "default",
+					},
+					Spec: infrav1.AzureManagedControlPlaneSpec{
+						SubscriptionID: "00000000-0000-0000-0000-000000000000",
+						WindowsProfile: c.Profile,
+					},
+				},
+				MachinePool:      getMachinePool("pool0"),
+				InfraMachinePool: getAzureMachinePool("pool0", infrav1.NodePoolModeSystem),
+				PatchTarget:      getAzureMachinePool("pool0", infrav1.NodePoolModeSystem),
+			}
+			objects := []client.Object{input.MachinePool, input.InfraMachinePool, input.ControlPlane}
+			if c.Secret != nil {
+				objects = append(objects, c.Secret)
+			}
+			input.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+			s, err := NewManagedControlPlaneScope(context.TODO(), input)
+			g.Expect(err).To(Succeed())
+			spec, err := s.ManagedClusterSpec(context.TODO())
+			if c.Err != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(c.Err))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(spec.WindowsProfile).To(Equal(c.Expected))
+		})
+	}
+}
+
+func TestManagedControlPlaneScope_AddonProfiles(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = capiv1exp.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	input := ManagedControlPlaneScopeParams{
+		AzureClients: AzureClients{
+			Authorizer: autorest.NullAuthorizer{},
+		},
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster1",
+				Namespace: "default",
+			},
+		},
+		ControlPlane: &infrav1.AzureManagedControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster1",
+				Namespace: "default",
+			},
+			Spec: infrav1.AzureManagedControlPlaneSpec{
+				SubscriptionID: "00000000-0000-0000-0000-000000000000",
+				AddonProfiles: []infrav1.AddonProfile{
+					{
+						Name:    "kubeDashboard",
+						Enabled: false,
+					},
+				},
+				EnableAzurePolicy:            to.BoolPtr(true),
+				EnableHTTPApplicationRouting: to.BoolPtr(false),
+				KeyVaultSecretsProvider: &infrav1.KeyVaultSecretsProviderProfile{
+					Enabled:              true,
+					EnableSecretRotation: to.BoolPtr(true),
+					RotationPollInterval: to.StringPtr("5m"),
+				},
+				Monitoring: &infrav1.MonitoringProfile{
+					Enabled:                         true,
+					LogAnalyticsWorkspaceResourceID: to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.OperationalInsights/workspaces/my-workspace"),
+				},
+			},
+		},
+		MachinePool:      getMachinePool("pool0"),
+		InfraMachinePool: getAzureMachinePool("pool0", infrav1.NodePoolModeSystem),
+		PatchTarget:      getAzureMachinePool("pool0", infrav1.NodePoolModeSystem),
+	}
+
+	g := NewWithT(t)
+	input.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(input.MachinePool, input.InfraMachinePool, input.ControlPlane).Build()
+	s, err := NewManagedControlPlaneScope(context.TODO(), input)
+	g.Expect(err).To(Succeed())
+	spec, err := s.ManagedClusterSpec(context.TODO())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(spec.AddonProfiles).To(Equal([]azure.AddonProfile{
+		{
+			Name:    "kubeDashboard",
+			Enabled: false,
+		},
+		{
+			Name:    "azurepolicy",
+			Enabled: true,
+		},
+		{
+			Name:    "httpApplicationRouting",
+			Enabled: false,
+		},
+		{
+			Name:    "azureKeyvaultSecretsProvider",
+			Enabled: true,
+			Config: map[string]string{
+				"enableSecretRotation": "true",
+				"rotationPollInterval": "5m",
+			},
+		},
+		{
+			Name:    "omsagent",
+			Enabled: true,
+			Config: map[string]string{
+				"logAnalyticsWorkspaceResourceID": "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.OperationalInsights/workspaces/my-workspace",
+			},
+		},
+	}))
+}
+
+func TestManagedControlPlaneScope_AutoScalerAndAutoUpgradeProfiles(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = capiv1exp.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	expander := infrav1.ExpanderMostPods
+	input := ManagedControlPlaneScopeParams{
+		AzureClients: AzureClients{
+			Authorizer: autorest.NullAuthorizer{},
+		},
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster1",
+				Namespace: "default",
+			},
+		},
+		ControlPlane: &infrav1.AzureManagedControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster1",
+				Namespace: "default",
+			},
+			Spec: infrav1.AzureManagedControlPlaneSpec{
+				SubscriptionID: "00000000-0000-0000-0000-000000000000",
+				AutoScalerProfile: &infrav1.AutoScalerProfile{
+					BalanceSimilarNodeGroups:      to.BoolPtr(true),
+					Expander:                      &expander,
+					MaxEmptyBulkDelete:            to.Int32Ptr(20),
+					ScaleDownUnneededTime:         to.StringPtr("5m"),
+					ScaleDownUtilizationThreshold: to.StringPtr("0.6"),
+					SkipNodesWithSystemPods:       to.BoolPtr(false),
+				},
+				AutoUpgradeProfile: &infrav1.AutoUpgradeProfile{
+					UpgradeChannel: infrav1.UpgradeChannelStable,
+				},
+			},
+		},
+		MachinePool:      getMachinePool("pool0"),
+		InfraMachinePool: getAzureMachinePool("pool0", infrav1.NodePoolModeSystem),
+		PatchTarget:      getAzureMachinePool("pool0", infrav1.NodePoolModeSystem),
+	}
+
+	g := NewWithT(t)
+	input.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(input.MachinePool, input.InfraMachinePool, input.ControlPlane).Build()
+	s, err := NewManagedControlPlaneScope(context.TODO(), input)
+	g.Expect(err).To(Succeed())
+	spec, err := s.ManagedClusterSpec(context.TODO())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(spec.AutoScalerProfile).To(Equal(&azure.AutoScalerProfile{
+		BalanceSimilarNodeGroups:      to.StringPtr("true"),
+		Expander:                      to.StringPtr("most-pods"),
+		MaxEmptyBulkDelete:            to.StringPtr("20"),
+		ScaleDownUnneededTime:         to.StringPtr("5m"),
+		ScaleDownUtilizationThreshold: to.StringPtr("0.6"),
+		SkipNodesWithSystemPods:       to.StringPtr("false"),
+	}))
+	g.Expect(spec.AutoUpgradeChannel).To(Equal(to.StringPtr("stable")))
+}
+
+func TestManagedControlPlaneScope_NetworkProfile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = capiv1exp.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	outboundType := infrav1.ManagedControlPlaneOutboundTypeUserDefinedRouting
+	input := ManagedControlPlaneScopeParams{
+		AzureClients: AzureClients{
+			Authorizer: autorest.NullAuthorizer{},
+		},
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster1",
+				Namespace: "default",
+			},
+			Spec: clusterv1.ClusterSpec{
+				ClusterNetwork: &clusterv1.ClusterNetwork{
+					Pods: &clusterv1.NetworkRanges{
+						CIDRBlocks: []string{"192.168.0.0/16"},
+					},
+					Services: &clusterv1.NetworkRanges{
+						CIDRBlocks: []string{"10.96.0.0/12"},
+					},
+				},
+			},
+		},
+		ControlPlane: &infrav1.AzureManagedControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster1",
+				Namespace: "default",
+			},
+			Spec: infrav1.AzureManagedControlPlaneSpec{
+				SubscriptionID: "00000000-0000-0000-0000-000000000000",
+				PodCIDR:        to.StringPtr("10.244.0.0/16"),
+				ServiceCIDR:    to.StringPtr("10.0.0.0/16"),
+				DNSServiceIP:   to.StringPtr("10.0.0.10"),
+				OutboundType:   &outboundType,
+			},
+		},
+		MachinePool:      getMachinePool("pool0"),
+		InfraMachinePool: getAzureMachinePool("pool0", infrav1.NodePoolModeSystem),
+		PatchTarget:      getAzureMachinePool("pool0", infrav1.NodePoolModeSystem),
+	}
+
+	g := NewWithT(t)
+	input.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(input.MachinePool, input.InfraMachinePool, input.ControlPlane).Build()
+	s, err := NewManagedControlPlaneScope(context.TODO(), input)
+	g.Expect(err).To(Succeed())
+	spec, err := s.ManagedClusterSpec(context.TODO())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(spec.PodCIDR).To(Equal("10.244.0.0/16"))
+	g.Expect(spec.ServiceCIDR).To(Equal("10.0.0.0/16"))
+	g.Expect(spec.DNSServiceIP).To(Equal(to.StringPtr("10.0.0.10")))
+	g.Expect(spec.OutboundType).To(Equal("userDefinedRouting"))
+}
+
+func TestManagedControlPlaneScope_VirtualNetwork(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = capiv1exp.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	input := ManagedControlPlaneScopeParams{
+		AzureClients: AzureClients{
+			Authorizer: autorest.NullAuthorizer{},
+		},
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster1",
+				Namespace: "default",
+			},
+		},
+		ControlPlane: &infrav1.AzureManagedControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster1",
+				Namespace: "default",
+			},
+			Spec: infrav1.AzureManagedControlPlaneSpec{
+				SubscriptionID:    "00000000-0000-0000-0000-000000000000",
+				ResourceGroupName: "cluster-rg",
+				VirtualNetwork: infrav1.ManagedControlPlaneVirtualNetwork{
+					Name:          "vnet",
+					CIDRBlock:     "10.0.0.0/8",
+					ResourceGroup: "vnet-rg",
+					Subnet: infrav1.ManagedControlPlaneSubnet{
+						Name:      "nodes",
+						CIDRBlock: "10.240.0.0/16",
+					},
+					Subnets: []infrav1.ManagedControlPlaneSubnet{
+						{Name: "nodes-2", CIDRBlock: "10.241.0.0/16"},
+						{Name: "pods", CIDRBlock: "10.242.0.0/16"},
+					},
+				},
+			},
+		},
+		MachinePool:      getMachinePool("pool0"),
+		InfraMachinePool: getAzureMachinePool("pool0", infrav1.NodePoolModeSystem),
+		PatchTarget:      getAzureMachinePool("pool0", infrav1.NodePoolModeSystem),
+	}
+	input.InfraMachinePool.Spec.SubnetName = to.StringPtr("nodes-2")
+	input.InfraMachinePool.Spec.PodSubnetName = to.StringPtr("pods")
+
+	g := NewWithT(t)
+	input.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(input.MachinePool, input.InfraMachinePool, input.ControlPlane).Build()
+	s, err := NewManagedControlPlaneScope(context.TODO(), input)
+	g.Expect(err).To(Succeed())
+
+	g.Expect(s.Vnet().ResourceGroup).To(Equal("vnet-rg"))
+	g.Expect(s.IsVnetManaged()).To(BeTrue())
+	s.Vnet().ID = "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/vnet-rg/providers/Microsoft.Network/virtualNetworks/vnet"
+	g.Expect(s.IsVnetManaged()).To(BeFalse())
+
+	g.Expect(s.SubnetSpecs()).To(Equal([]azure.SubnetSpec{
+		{Name: "nodes", CIDRs: []string{"10.240.0.0/16"}, VNetName: "vnet"},
+		{Name: "nodes-2", CIDRs: []string{"10.241.0.0/16"}, VNetName: "vnet"},
+		{Name: "pods", CIDRs: []string{"10.242.0.0/16"}, VNetName: "vnet"},
+	}))
+	g.Expect(s.Subnet("pods").CIDRBlocks).To(Equal([]string{"10.242.0.0/16"}))
+
+	spec, err := s.ManagedClusterSpec(context.TODO())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(spec.VnetSubnetID).To(Equal("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/vnet-rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/nodes"))
+
+	agentPool := s.AgentPoolSpec()
+	g.Expect(agentPool.VnetSubnetID).To(Equal("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/vnet-rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/nodes-2"))
+	g.Expect(agentPool.PodSubnetID).To(Equal(to.StringPtr("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/vnet-rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/pods")))
+}
+
 func getAzureMachinePool(name string, mode infrav1.NodePoolMode) *infrav1.AzureManagedMachinePool {
 	return &infrav1.AzureManagedMachinePool{
 		ObjectMeta: metav1.ObjectMeta{
@@ -633,3 +1139,187 @@ func getMachinePoolWithVersion(name, version string) *capiv1exp.MachinePool {
 	machine.Spec.Template.Spec.Version = to.StringPtr(version)
 	return machine
 }
+
+func TestManagedControlPlaneScope_Adoption(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = capiv1exp.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	input := ManagedControlPlaneScopeParams{
+		AzureClients: AzureClients{
+			Authorizer: autorest.NullAuthorizer{},
+		},
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster1",
+				Namespace: "default",
+			},
+		},
+		ControlPlane: &infrav1.AzureManagedControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster1",
+				Namespace: "default",
+			},
+			Spec: infrav1.AzureManagedControlPlaneSpec{
+				SubscriptionID: "00000000-0000-0000-0000-000000000000",
+				AdditionalTags: infrav1beta1.Tags{"team": "payments"},
+			},
+		},
+		MachinePool:      getMachinePool("pool0"),
+		InfraMachinePool: getAzureMachinePool("pool0", infrav1.NodePoolModeSystem),
+		PatchTarget:      getAzureMachinePool("pool0", infrav1.NodePoolModeSystem),
+	}
+
+	g := NewWithT(t)
+	input.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(input.MachinePool, input.InfraMachinePool, input.ControlPlane).Build()
+	s, err := NewManagedControlPlaneScope(context.TODO(), input)
+	g.Expect(err).To(Succeed())
+	spec, err := s.ManagedClusterSpec(context.TODO())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(spec.Tags).To(Equal(map[string]string{
+		"sigs.k8s.io_cluster-api-provider-azure_cluster_cluster1": "owned",
+		"team": "payments",
+	}))
+
+	g.Expect(s.ManagedClusterAdoptionConfirmed()).To(BeFalse())
+	input.ControlPlane.Annotations = map[string]string{infrav1.AdoptAnnotation: "true"}
+	g.Expect(s.ManagedClusterAdoptionConfirmed()).To(BeTrue())
+	input.ControlPlane.Annotations = nil
+	input.ControlPlane.Status.Initialized = true
+	g.Expect(s.ManagedClusterAdoptionConfirmed()).To(BeTrue())
+}
+
+func TestManagedControlPlaneScope_UpgradeSettings(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = capiv1exp.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	managedPool := getAzureMachinePool("pool1", infrav1.NodePoolModeUser)
+	managedPool.Spec.UpgradeSettings = &infrav1.ManagedMachinePoolUpgradeSettings{
+		MaxSurge: to.StringPtr("33%"),
+	}
+
+	input := ManagedControlPlaneScopeParams{
+		AzureClients: AzureClients{
+			Authorizer: autorest.NullAuthorizer{},
+		},
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster1",
+				Namespace: "default",
+			},
+		},
+		ControlPlane: &infrav1.AzureManagedControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster1",
+				Namespace: "default",
+			},
+			Spec: infrav1.AzureManagedControlPlaneSpec{
+				SubscriptionID: "00000000-0000-0000-0000-000000000000",
+			},
+			Status: infrav1.AzureManagedControlPlaneStatus{
+				Version: "1.22.4",
+			},
+		},
+		MachinePool:      getMachinePool("pool1"),
+		InfraMachinePool: managedPool,
+		PatchTarget:      managedPool,
+	}
+
+	g := NewWithT(t)
+	input.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(input.MachinePool, input.InfraMachinePool, input.ControlPlane).Build()
+	s, err := NewManagedControlPlaneScope(context.TODO(), input)
+	g.Expect(err).To(Succeed())
+	g.Expect(s.AgentPoolSpec().MaxSurge).To(Equal(to.StringPtr("33%")))
+	g.Expect(s.ControlPlaneVersion()).To(Equal("1.22.4"))
+
+	s.SetControlPlaneVersion("1.23.3")
+	g.Expect(input.ControlPlane.Status.Version).To(Equal("1.23.3"))
+}
+
+func TestManagedControlPlaneScope_Identity(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = capiv1exp.AddToScheme(scheme)
+	_ = infrav1.AddToScheme(scheme)
+
+	const (
+		controlPlaneIdentity = "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/identities/providers/Microsoft.ManagedIdentity/userAssignedIdentities/control-plane"
+		kubeletIdentity      = "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/identities/providers/Microsoft.ManagedIdentity/userAssignedIdentities/kubelet"
+		registry             = "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/registries/providers/Microsoft.ContainerRegistry/registries/myregistry"
+		subnet               = "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/network/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet"
+	)
+
+	input := ManagedControlPlaneScopeParams{
+		AzureClients: AzureClients{
+			Authorizer: autorest.NullAuthorizer{},
+		},
+		Cluster: &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster1",
+				Namespace: "default",
+			},
+		},
+		ControlPlane: &infrav1.AzureManagedControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster1",
+				Namespace: "default",
+			},
+			Spec: infrav1.AzureManagedControlPlaneSpec{
+				SubscriptionID:    "00000000-0000-0000-0000-000000000000",
+				ResourceGroupName: "cluster1",
+				VirtualNetwork: infrav1.ManagedControlPlaneVirtualNetwork{
+					Name:          "my-vnet",
+					ResourceGroup: "network",
+					Subnet: infrav1.ManagedControlPlaneSubnet{
+						Name: "my-subnet",
+					},
+				},
+			},
+		},
+		MachinePool:      getMachinePool("pool0"),
+		InfraMachinePool: getAzureMachinePool("pool0", infrav1.NodePoolModeSystem),
+		PatchTarget:      getAzureMachinePool("pool0", infrav1.NodePoolModeSystem),
+	}
+
+	g := NewWithT(t)
+	input.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(input.MachinePool, input.InfraMachinePool, input.ControlPlane).Build()
+	s, err := NewManagedControlPlaneScope(context.TODO(), input)
+	g.Expect(err).To(Succeed())
+
+	// AKS assigns the roles of system-assigned identities.
+	g.Expect(s.RoleAssignmentSpecs()).To(BeEmpty())
+	spec, err := s.ManagedClusterSpec(context.TODO())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(spec.UserAssignedIdentity).To(BeEmpty())
+	g.Expect(spec.KubeletUserAssignedIdentity).To(BeEmpty())
+
+	input.ControlPlane.Spec.Identity = &infrav1.ManagedControlPlaneIdentity{
+		Type:                           infrav1.ManagedControlPlaneIdentityTypeUserAssigned,
+		UserAssignedIdentityResourceID: controlPlaneIdentity,
+		KubeletIdentityResourceID:      kubeletIdentity,
+		ContainerRegistryResourceIDs:   []string{registry},
+	}
+	spec, err = s.ManagedClusterSpec(context.TODO())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(spec.UserAssignedIdentity).To(Equal(controlPlaneIdentity))
+	g.Expect(spec.KubeletUserAssignedIdentity).To(Equal(kubeletIdentity))
+
+	roleAssignments := s.RoleAssignmentSpecs()
+	g.Expect(roleAssignments).To(HaveLen(3))
+	g.Expect(roleAssignments[0]).To(Equal(azure.RoleAssignmentSpec{
+		Name:               roleAssignmentName(controlPlaneIdentity, subnet, azure.NetworkContributorRoleID),
+		ResourceType:       azure.UserAssignedIdentity,
+		IdentityResourceID: controlPlaneIdentity,
+		Scope:              subnet,
+		RoleDefinitionID:   azure.NetworkContributorRoleID,
+	}))
+	g.Expect(roleAssignments[1].IdentityResourceID).To(Equal(controlPlaneIdentity))
+	g.Expect(roleAssignments[1].Scope).To(Equal(kubeletIdentity))
+	g.Expect(roleAssignments[1].RoleDefinitionID).To(Equal(azure.ManagedIdentityOperatorRoleID))
+	g.Expect(roleAssignments[2].IdentityResourceID).To(Equal(kubeletIdentity))
+	g.Expect(roleAssignments[2].Scope).To(Equal(registry))
+	g.Expect(roleAssignments[2].RoleDefinitionID).To(Equal(azure.AcrPullRoleID))
+
+	// Role assignment names are stable across reconciles.
+	g.Expect(s.RoleAssignmentSpecs()[2].Name).To(Equal(roleAssignments[2].Name))
+}
@@ -27,6 +27,7 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
@@ -50,6 +51,13 @@ type (
 
 	rollingUpdateStrategy struct {
 		infrav1exp.MachineRollingUpdateDeployment
+		// azureManagedUpgrades is true if Azure rolls out model changes to the instances, in which case the model of a
+		// machine plays no part in selecting it for deletion.
+		azureManagedUpgrades bool
+	}
+
+	blueGreenStrategy struct {
+		infrav1exp.MachineBlueGreenDeployment
 	}
 )
 
@@ -66,6 +74,15 @@ func NewMachinePoolDeploymentStrategy(strategy infrav1exp.AzureMachinePoolDeploy
 		return &rollingUpdateStrategy{
 			MachineRollingUpdateDeployment: *rollingUpdate,
 		}
+	case infrav1exp.BlueGreenAzureMachinePoolDeploymentStrategyType:
+		blueGreen := strategy.BlueGreen
+		if blueGreen == nil {
+			blueGreen = &infrav1exp.MachineBlueGreenDeployment{}
+		}
+
+		return &blueGreenStrategy{
+			MachineBlueGreenDeployment: *blueGreen,
+		}
 	default:
 		// default to a rolling update strategy if unknown type
 		return &rollingUpdateStrategy{
@@ -74,6 +91,21 @@ func NewMachinePoolDeploymentStrategy(strategy infrav1exp.AzureMachinePoolDeploy
 	}
 }
 
+// NewAzureManagedUpgradeStrategy constructs a strategy for a scale set whose model changes are rolled out by a Rolling
+// or Automatic upgrade policy. It neither surges nor replaces machines running an old model, and only deletes failed,
+// deleting and surplus machines according to the delete policy.
+func NewAzureManagedUpgradeStrategy(deletePolicy infrav1exp.AzureMachinePoolDeletePolicyType) TypedDeleteSelector {
+	zero := intstr.FromInt(0)
+	return &rollingUpdateStrategy{
+		MachineRollingUpdateDeployment: infrav1exp.MachineRollingUpdateDeployment{
+			MaxSurge:       &zero,
+			MaxUnavailable: &zero,
+			DeletePolicy:   deletePolicy,
+		},
+		azureManagedUpgrades: true,
+	}
+}
+
 // Type is the AzureMachinePoolDeploymentStrategyType for the strategy.
 func (rollingUpdateStrategy *rollingUpdateStrategy) Type() infrav1exp.AzureMachinePoolDeploymentStrategyType {
 	return infrav1exp.RollingUpdateAzureMachinePoolDeploymentStrategyType
@@ -123,6 +155,8 @@ func (rollingUpdateStrategy rollingUpdateStrategy) SelectMachinesToDelete(ctx co
 				return orderByOldest
 			case infrav1exp.NewestDeletePolicyType:
 				return orderByNewest
+			case infrav1exp.AnnotatedDeletePolicyType:
+				return orderByDeleteMachineAnnotation
 			default:
 				return orderRandom
 			}
@@ -131,7 +165,8 @@ func (rollingUpdateStrategy rollingUpdateStrategy) SelectMachinesToDelete(ctx co
 		failedMachines             = order(getFailedMachines(machinesByProviderID))
 		deletingMachines           = order(getDeletingMachines(machinesByProviderID))
 		readyMachines              = order(getReadyMachines(machinesByProviderID))
-		machinesWithoutLatestModel = order(getMachinesWithoutLatestModel(machinesByProviderID))
+		machinesWithoutLatestModel []infrav1exp.AzureMachinePoolMachine
+		machinesByFailureDomain    = countMachinesByFailureDomain(machinesByProviderID)
 		overProvisionCount         = len(readyMachines) - int(desiredReplicaCount)
 		disruptionBudget           = func() int {
 			if maxUnavailable > int(desiredReplicaCount) {
@@ -142,6 +177,10 @@ func (rollingUpdateStrategy rollingUpdateStrategy) SelectMachinesToDelete(ctx co
 		}()
 	)
 
+	if !rollingUpdateStrategy.azureManagedUpgrades {
+		machinesWithoutLatestModel = order(getMachinesWithoutLatestModel(machinesByProviderID))
+	}
+
 	log.Info("selecting machines to delete",
 		"readyMachines", len(readyMachines),
 		"desiredReplicaCount", desiredReplicaCount,
@@ -149,6 +188,7 @@ func (rollingUpdateStrategy rollingUpdateStrategy) SelectMachinesToDelete(ctx co
 		"disruptionBudget", disruptionBudget,
 		"machinesWithoutTheLatestModel", len(machinesWithoutLatestModel),
 		"failedMachines", len(failedMachines),
+		"machinesByFailureDomain", machinesByFailureDomain,
 	)
 
 	// if we have failed or deleting machines, remove them
@@ -171,28 +211,47 @@ func (rollingUpdateStrategy rollingUpdateStrategy) SelectMachinesToDelete(ctx co
 
 	// we have too many machines, let's choose the oldest to remove
 	if overProvisionCount > 0 {
-		var toDelete []infrav1exp.AzureMachinePoolMachine
 		log.Info("over-provisioned", "desiredReplicaCount", desiredReplicaCount, "overProvisionCount", overProvisionCount, "machinesWithoutLatestModel", getProviderIDs(machinesWithoutLatestModel))
+		var (
+			toDelete             []infrav1exp.AzureMachinePoolMachine
+			annotatedMachines    []infrav1exp.AzureMachinePoolMachine
+			annotatedForDeletion = func(machine infrav1exp.AzureMachinePoolMachine) bool {
+				return rollingUpdateStrategy.DeletePolicy == infrav1exp.AnnotatedDeletePolicyType && isReady(machine) && hasDeleteMachineAnnotation(machine)
+			}
+		)
+		// annotated machines are removed first by the Annotated delete policy, even if they have the latest model
+		for _, v := range readyMachines {
+			if annotatedForDeletion(v) {
+				annotatedMachines = append(annotatedMachines, v)
+			}
+		}
+		toDelete = rollingUpdateStrategy.selectBalanced(annotatedMachines, overProvisionCount, machinesByFailureDomain)
+		if len(toDelete) >= overProvisionCount {
+			return toDelete, nil
+		}
+
 		// we are over-provisioned try to remove old models
+		var oldMachines []infrav1exp.AzureMachinePoolMachine
 		for _, v := range machinesWithoutLatestModel {
-			if len(toDelete) >= overProvisionCount {
-				return toDelete, nil
+			if !annotatedForDeletion(v) {
+				oldMachines = append(oldMachines, v)
 			}
-
-			toDelete = append(toDelete, v)
+		}
+		toDelete = append(toDelete, rollingUpdateStrategy.selectBalanced(oldMachines, overProvisionCount-len(toDelete), machinesByFailureDomain)...)
+		if len(toDelete) >= overProvisionCount {
+			return toDelete, nil
 		}
 
 		log.Info("over-provisioned ready", "desiredReplicaCount", desiredReplicaCount, "overProvisionCount", overProvisionCount, "readyMachines", getProviderIDs(readyMachines))
-		// remove ready machines
+		// remove ready machines which have not been selected already
+		var remainingReadyMachines []infrav1exp.AzureMachinePoolMachine
 		for _, v := range readyMachines {
-			if len(toDelete) >= overProvisionCount {
-				return toDelete, nil
+			if (v.Status.LatestModelApplied || rollingUpdateStrategy.azureManagedUpgrades) && !annotatedForDeletion(v) {
+				remainingReadyMachines = append(remainingReadyMachines, v)
 			}
-
-			toDelete = append(toDelete, v)
 		}
 
-		return toDelete, nil
+		return append(toDelete, rollingUpdateStrategy.selectBalanced(remainingReadyMachines, overProvisionCount-len(toDelete), machinesByFailureDomain)...), nil
 	}
 
 	if len(machinesWithoutLatestModel) <= 0 {
@@ -205,22 +264,79 @@ func (rollingUpdateStrategy rollingUpdateStrategy) SelectMachinesToDelete(ctx co
 		return []infrav1exp.AzureMachinePoolMachine{}, nil
 	}
 
-	var toDelete []infrav1exp.AzureMachinePoolMachine
 	log.Info("removing ready machines within disruption budget", "desiredReplicaCount", desiredReplicaCount, "maxUnavailable", maxUnavailable, "readyMachines", getProviderIDs(readyMachines), "readyMachinesCount", len(readyMachines))
+	var readyMachinesWithoutLatestModel []infrav1exp.AzureMachinePoolMachine
 	for _, v := range readyMachines {
-		if len(toDelete) >= disruptionBudget {
-			return toDelete, nil
-		}
-
 		if !v.Status.LatestModelApplied {
-			toDelete = append(toDelete, v)
+			readyMachinesWithoutLatestModel = append(readyMachinesWithoutLatestModel, v)
 		}
 	}
 
-	log.Info("completed without filling toDelete", "toDelete", getProviderIDs(toDelete), "numToDelete", len(toDelete))
+	toDelete := rollingUpdateStrategy.selectBalanced(readyMachinesWithoutLatestModel, disruptionBudget, machinesByFailureDomain)
+	if len(toDelete) < disruptionBudget {
+		log.Info("completed without filling toDelete", "toDelete", getProviderIDs(toDelete), "numToDelete", len(toDelete))
+	}
+
 	return toDelete, nil
 }
 
+// selectBalanced selects up to count machines from the ordered candidates. Each machine is taken from the failure
+// domain with the most remaining machines, so the pool stays evenly spread across its zones after the selected
+// machines are deleted. Ties are broken by the order of the candidates. Machines annotated for deletion are selected
+// first when the delete policy is Annotated. Machines protected from scale-in are never selected.
+func (rollingUpdateStrategy rollingUpdateStrategy) selectBalanced(candidates []infrav1exp.AzureMachinePoolMachine, count int, machinesByFailureDomain map[string]int) []infrav1exp.AzureMachinePoolMachine {
+	var (
+		selected  []infrav1exp.AzureMachinePoolMachine
+		remaining []infrav1exp.AzureMachinePoolMachine
+	)
+	for _, v := range candidates {
+		if !IsProtected(v) {
+			remaining = append(remaining, v)
+		}
+	}
+	for len(selected) < count && len(remaining) > 0 {
+		next := 0
+		// annotated machines are ordered first by the Annotated delete policy
+		if rollingUpdateStrategy.DeletePolicy != infrav1exp.AnnotatedDeletePolicyType || !hasDeleteMachineAnnotation(remaining[0]) {
+			for i, v := range remaining {
+				if machinesByFailureDomain[v.Status.FailureDomain] > machinesByFailureDomain[remaining[next].Status.FailureDomain] {
+					next = i
+				}
+			}
+		}
+
+		machinesByFailureDomain[remaining[next].Status.FailureDomain]--
+		selected = append(selected, remaining[next])
+		remaining = append(remaining[:next], remaining[next+1:]...)
+	}
+
+	return selected
+}
+
+// Type is the AzureMachinePoolDeploymentStrategyType for the strategy.
+func (blueGreenStrategy *blueGreenStrategy) Type() infrav1exp.AzureMachinePoolDeploymentStrategyType {
+	return infrav1exp.BlueGreenAzureMachinePoolDeploymentStrategyType
+}
+
+// SelectMachinesToDelete selects the failed, deleting and surplus machines of a scale set to delete. The machines of
+// a scale set are never replaced one by one, the whole scale set is replaced instead.
+func (blueGreenStrategy blueGreenStrategy) SelectMachinesToDelete(ctx context.Context, desiredReplicaCount int32, machinesByProviderID map[string]infrav1exp.AzureMachinePoolMachine) ([]infrav1exp.AzureMachinePoolMachine, error) {
+	ctx, _, done := tele.StartSpanWithLogger(
+		ctx,
+		"strategies.blueGreenStrategy.SelectMachinesToDelete",
+	)
+	defer done()
+
+	// without any disruption budget, a rolling update only deletes failed, deleting and surplus machines
+	rollingUpdate := rollingUpdateStrategy{
+		MachineRollingUpdateDeployment: infrav1exp.MachineRollingUpdateDeployment{
+			DeletePolicy: infrav1exp.OldestDeletePolicyType,
+		},
+	}
+
+	return rollingUpdate.SelectMachinesToDelete(ctx, desiredReplicaCount, machinesByProviderID)
+}
+
 func getFailedMachines(machinesByProviderID map[string]infrav1exp.AzureMachinePoolMachine) []infrav1exp.AzureMachinePoolMachine {
 	var machines []infrav1exp.AzureMachinePoolMachine
 	for _, v := range machinesByProviderID {
@@ -248,8 +364,7 @@ func getDeletingMachines(machinesByProviderID map[string]infrav1exp.AzureMachine
 func getReadyMachines(machinesByProviderID map[string]infrav1exp.AzureMachinePoolMachine) []infrav1exp.AzureMachinePoolMachine {
 	var readyMachines []infrav1exp.AzureMachinePoolMachine
 	for _, v := range machinesByProviderID {
-		// ready status, with provisioning state Succeeded, and not marked for delete
-		if v.Status.Ready && v.Status.ProvisioningState != nil && *v.Status.ProvisioningState == infrav1.Succeeded {
+		if isReady(v) {
 			readyMachines = append(readyMachines, v)
 		}
 	}
@@ -257,6 +372,11 @@ func getReadyMachines(machinesByProviderID map[string]infrav1exp.AzureMachinePoo
 	return readyMachines
 }
 
+// isReady returns true if the machine is ready, with provisioning state Succeeded, and not marked for delete.
+func isReady(machine infrav1exp.AzureMachinePoolMachine) bool {
+	return machine.Status.Ready && machine.Status.ProvisioningState != nil && *machine.Status.ProvisioningState == infrav1.Succeeded
+}
+
 func getMachinesWithoutLatestModel(machinesByProviderID map[string]infrav1exp.AzureMachinePoolMachine) []infrav1exp.AzureMachinePoolMachine {
 	var machinesWithLatestModel []infrav1exp.AzureMachinePoolMachine
 	for _, v := range machinesByProviderID {
@@ -268,6 +388,27 @@ func getMachinesWithoutLatestModel(machinesByProviderID map[string]infrav1exp.Az
 	return machinesWithLatestModel
 }
 
+func countMachinesByFailureDomain(machinesByProviderID map[string]infrav1exp.AzureMachinePoolMachine) map[string]int {
+	machinesByFailureDomain := make(map[string]int)
+	for _, v := range machinesByProviderID {
+		machinesByFailureDomain[v.Status.FailureDomain]++
+	}
+
+	return machinesByFailureDomain
+}
+
+// IsProtected returns true if the machine is protected from scale-in, in which case it must not be deleted when the
+// pool scales in or rolls out a new model.
+func IsProtected(machine infrav1exp.AzureMachinePoolMachine) bool {
+	policy := machine.Spec.ProtectionPolicy
+	return policy != nil && policy.ProtectFromScaleIn
+}
+
+func hasDeleteMachineAnnotation(machine infrav1exp.AzureMachinePoolMachine) bool {
+	_, ok := machine.Annotations[clusterv1.DeleteMachineAnnotation]
+	return ok
+}
+
 func orderByNewest(machines []infrav1exp.AzureMachinePoolMachine) []infrav1exp.AzureMachinePoolMachine {
 	sort.Slice(machines, func(i, j int) bool {
 		return machines[i].ObjectMeta.CreationTimestamp.After(machines[j].ObjectMeta.CreationTimestamp.Time)
@@ -284,6 +425,15 @@ func orderByOldest(machines []infrav1exp.AzureMachinePoolMachine) []infrav1exp.A
 	return machines
 }
 
+func orderByDeleteMachineAnnotation(machines []infrav1exp.AzureMachinePoolMachine) []infrav1exp.AzureMachinePoolMachine {
+	machines = orderByOldest(machines)
+	sort.SliceStable(machines, func(i, j int) bool {
+		return hasDeleteMachineAnnotation(machines[i]) && !hasDeleteMachineAnnotation(machines[j])
+	})
+
+	return machines
+}
+
 func orderRandom(machines []infrav1exp.AzureMachinePoolMachine) []infrav1exp.AzureMachinePoolMachine {
 	rand.Seed(time.Now().UnixNano())
 	rand.Shuffle(len(machines), func(i, j int) { machines[i], machines[j] = machines[j], machines[i] })
@@ -21,15 +21,22 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
+	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-08-01/containerservice"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
+	"golang.org/x/mod/semver"
 	infrav1alpha4 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
+// upgradingProvisioningState is the provisioning state of an agent pool while AKS upgrades its Kubernetes version.
+const upgradingProvisioningState = "Upgrading"
+
 // ManagedMachinePoolScope defines the scope interface for a managed machine pool.
 type ManagedMachinePoolScope interface {
 	azure.ClusterDescriber
@@ -39,6 +46,10 @@ type ManagedMachinePoolScope interface {
 	SetAgentPoolProviderIDList([]string)
 	SetAgentPoolReplicas(int32)
 	SetAgentPoolReady(bool)
+	SetAgentPoolTaints(infrav1exp.Taints)
+	ControlPlaneVersion() string
+	AutoUpgradesKubernetesVersion() bool
+	SetUpgradeCondition(clusterv1.ConditionType, string, clusterv1.ConditionSeverity, string)
 }
 
 // Service provides operations on Azure resources.
@@ -65,15 +76,21 @@ func (s *Service) Reconcile(ctx context.Context) error {
 
 	agentPoolSpec := s.scope.AgentPoolSpec()
 
+	osType := containerservice.OSTypeLinux
+	if agentPoolSpec.OSType != nil {
+		osType = containerservice.OSType(*agentPoolSpec.OSType)
+	}
+
 	profile := containerservice.AgentPool{
 		ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
 			VMSize:              &agentPoolSpec.SKU,
-			OsType:              containerservice.OSTypeLinux,
+			OsType:              osType,
 			OsDiskSizeGB:        &agentPoolSpec.OSDiskSizeGB,
 			Count:               &agentPoolSpec.Replicas,
 			Type:                containerservice.AgentPoolTypeVirtualMachineScaleSets,
 			OrchestratorVersion: agentPoolSpec.Version,
 			VnetSubnetID:        &agentPoolSpec.VnetSubnetID,
+			PodSubnetID:         agentPoolSpec.PodSubnetID,
 			Mode:                containerservice.AgentPoolMode(agentPoolSpec.Mode),
 			EnableAutoScaling:   agentPoolSpec.EnableAutoScaling,
 			MaxCount:            agentPoolSpec.MaxCount,
@@ -82,9 +99,35 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			MaxPods:             agentPoolSpec.MaxPods,
 			OsDiskType:          containerservice.OSDiskType(to.String(agentPoolSpec.OsDiskType)),
 			NodeLabels:          agentPoolSpec.NodeLabels,
+			NodeTaints:          converters.NodeTaintsToSDK(agentPoolSpec.NodeTaints),
+			KubeletConfig:       converters.KubeletConfigToSDK(agentPoolSpec.KubeletConfig),
+			LinuxOSConfig:       converters.LinuxOSConfigToSDK(agentPoolSpec.LinuxOSConfig),
+			SpotMaxPrice:        agentPoolSpec.SpotMaxPrice,
 		},
 	}
 
+	if agentPoolSpec.ScaleSetPriority != nil {
+		profile.ScaleSetPriority = containerservice.ScaleSetPriority(*agentPoolSpec.ScaleSetPriority)
+	}
+	if agentPoolSpec.ScaleSetEvictionPolicy != nil {
+		profile.ScaleSetEvictionPolicy = containerservice.ScaleSetEvictionPolicy(*agentPoolSpec.ScaleSetEvictionPolicy)
+	}
+	if agentPoolSpec.MaxSurge != nil {
+		profile.UpgradeSettings = &containerservice.AgentPoolUpgradeSettings{
+			MaxSurge: agentPoolSpec.MaxSurge,
+		}
+	}
+
+	// AKS only runs agent pools at the version of the control plane or lower, so the agent pool waits for the
+	// control plane to be upgraded first.
+	if controlPlaneVersion := s.scope.ControlPlaneVersion(); agentPoolSpec.Version != nil && controlPlaneVersion != "" &&
+		semver.Compare("v"+*agentPoolSpec.Version, "v"+controlPlaneVersion) > 0 {
+		msg := fmt.Sprintf("waiting for the control plane to be upgraded from Kubernetes version %s to %s", controlPlaneVersion, *agentPoolSpec.Version)
+		log.V(2).Info(msg)
+		s.scope.SetUpgradeCondition(infrav1alpha4.AgentPoolUpgradedCondition, infrav1alpha4.WaitingForControlPlaneUpgradeReason, clusterv1.ConditionSeverityInfo, msg)
+		return azure.WithTransientError(errors.New(msg), 20*time.Second)
+	}
+
 	existingPool, err := s.Client.Get(ctx, agentPoolSpec.ResourceGroup, agentPoolSpec.Cluster, agentPoolSpec.Name)
 	if err != nil && !azure.ResourceNotFound(err) {
 		return errors.Wrap(err, "failed to get existing agent pool")
@@ -102,14 +145,40 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		} else if err != nil {
 			return errors.Wrap(err, "failed to create or update agent pool")
 		}
+
+		// the taints of the nodes of a new agent pool are the ones it was created with
+		taints, err := converters.SDKToNodeTaints(profile.NodeTaints)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse the node taints of the agent pool")
+		}
+		s.scope.SetAgentPoolTaints(taints)
 	} else {
+		// the taints of the nodes are observed on the existing agent pool, including the taints added by AKS
+		taints, err := converters.SDKToNodeTaints(existingPool.NodeTaints)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse the node taints of the existing agent pool")
+		}
+		s.scope.SetAgentPoolTaints(taints)
+
 		ps := *existingPool.ManagedClusterAgentPoolProfileProperties.ProvisioningState
+		if ps == upgradingProvisioningState {
+			s.scope.SetUpgradeCondition(infrav1alpha4.AgentPoolUpgradedCondition, infrav1alpha4.AgentPoolUpgradingReason, clusterv1.ConditionSeverityInfo,
+				fmt.Sprintf("upgrading the agent pool to Kubernetes version %s", to.String(existingPool.OrchestratorVersion)))
+		}
 		if ps != string(infrav1alpha4.Canceled) && ps != string(infrav1alpha4.Failed) && ps != string(infrav1alpha4.Succeeded) {
 			msg := fmt.Sprintf("Unable to update existing agent pool in non terminal state. Agent pool must be in one of the following provisioning states: canceled, failed, or succeeded. Actual state: %s", ps)
 			log.V(2).Info(msg)
 			return azure.WithTransientError(errors.New(msg), 20*time.Second)
 		}
 
+		// AKS cannot downgrade an agent pool, so the version an auto-upgrade channel upgraded it to is kept.
+		if existingVersion := to.String(existingPool.OrchestratorVersion); s.scope.AutoUpgradesKubernetesVersion() &&
+			agentPoolSpec.Version != nil && existingVersion != "" && semver.Compare("v"+existingVersion, "v"+*agentPoolSpec.Version) > 0 {
+			log.V(2).Info(fmt.Sprintf("keeping Kubernetes version %s of the agent pool, which was automatically upgraded from %s", existingVersion, *agentPoolSpec.Version))
+			agentPoolSpec.Version = existingPool.OrchestratorVersion
+			profile.OrchestratorVersion = existingPool.OrchestratorVersion
+		}
+
 		// Normalize individual agent pools to diff in case we need to update
 		existingProfile := containerservice.AgentPool{
 			ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
@@ -119,8 +188,16 @@ func (s *Service) Reconcile(ctx context.Context) error {
 				EnableAutoScaling:   existingPool.EnableAutoScaling,
 				MinCount:            existingPool.MinCount,
 				MaxCount:            existingPool.MaxCount,
+				NodeLabels:          normalizedNodeLabels(existingPool.NodeLabels),
+				NodeTaints:          normalizedNodeTaints(existingPool.NodeTaints),
 			},
 		}
+		if profile.UpgradeSettings != nil {
+			existingProfile.UpgradeSettings = &containerservice.AgentPoolUpgradeSettings{}
+			if existingPool.UpgradeSettings != nil {
+				existingProfile.UpgradeSettings.MaxSurge = existingPool.UpgradeSettings.MaxSurge
+			}
+		}
 
 		normalizedProfile := containerservice.AgentPool{
 			ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
@@ -130,9 +207,19 @@ func (s *Service) Reconcile(ctx context.Context) error {
 				EnableAutoScaling:   profile.EnableAutoScaling,
 				MinCount:            profile.MinCount,
 				MaxCount:            profile.MaxCount,
+				NodeLabels:          normalizedNodeLabels(profile.NodeLabels),
+				NodeTaints:          normalizedNodeTaints(profile.NodeTaints),
+				UpgradeSettings:     profile.UpgradeSettings,
 			},
 		}
 
+		if agentPoolSpec.Version != nil && *agentPoolSpec.Version != to.String(existingPool.OrchestratorVersion) {
+			s.scope.SetUpgradeCondition(infrav1alpha4.AgentPoolUpgradedCondition, infrav1alpha4.AgentPoolUpgradingReason, clusterv1.ConditionSeverityInfo,
+				fmt.Sprintf("upgrading the agent pool from Kubernetes version %s to %s", to.String(existingPool.OrchestratorVersion), *agentPoolSpec.Version))
+		} else {
+			s.scope.SetUpgradeCondition(infrav1alpha4.AgentPoolUpgradedCondition, "", "", "")
+		}
+
 		// Diff and check if we require an update
 		diff := cmp.Diff(normalizedProfile, existingProfile)
 		if diff != "" {
@@ -172,3 +259,27 @@ func (s *Service) Delete(ctx context.Context) error {
 	log.V(2).Info(fmt.Sprintf("Successfully deleted agent pool %s ", agentPoolSpec.Name))
 	return nil
 }
+
+// normalizedNodeLabels returns nil for empty node labels so that unset and empty labels compare as equal.
+// The scale set priority label that AKS adds to Spot agent pools is ignored.
+func normalizedNodeLabels(labels map[string]*string) map[string]*string {
+	normalized := make(map[string]*string, len(labels))
+	for k, v := range labels {
+		if k == infrav1exp.SpotNodeLabelKey {
+			continue
+		}
+		normalized[k] = v
+	}
+	if len(normalized) == 0 {
+		return nil
+	}
+	return normalized
+}
+
+// normalizedNodeTaints returns nil for empty node taints so that unset and empty taints compare as equal.
+func normalizedNodeTaints(taints *[]string) *[]string {
+	if taints == nil || len(*taints) == 0 {
+		return nil
+	}
+	return taints
+}
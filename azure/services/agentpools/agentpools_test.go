@@ -19,14 +19,19 @@ package agentpools
 import (
 	"context"
 	"net/http"
+	"reflect"
+	"strings"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
+	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-08-01/containerservice"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/agentpools/mock_agentpools"
@@ -130,10 +135,17 @@ func TestReconcile(t *testing.T) {
 	}
 
 	testcases := []struct {
-		name           string
-		agentPoolsSpec azure.AgentPoolSpec
-		expectedError  string
-		expect         func(m *mock_agentpools.MockClientMockRecorder)
+		name                string
+		agentPoolsSpec      azure.AgentPoolSpec
+		taints              infraexpv1.Taints
+		spotVMOptions       *infrav1.SpotVMOptions
+		podSubnetName       *string
+		upgradeSettings     *infraexpv1.ManagedMachinePoolUpgradeSettings
+		controlPlaneVersion string
+		autoUpgradeProfile  *infraexpv1.AutoUpgradeProfile
+		expectedError       string
+		expectedTaints      infraexpv1.Taints
+		expect              func(m *mock_agentpools.MockClientMockRecorder)
 	}{
 		{
 			name: "no agentpool exists",
@@ -185,6 +197,102 @@ func TestReconcile(t *testing.T) {
 				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool", gomock.AssignableToTypeOf(containerservice.AgentPool{})).Return(nil)
 			},
 		},
+		{
+			name: "can create a Windows Agent Pool",
+			agentPoolsSpec: azure.AgentPoolSpec{
+				Name:          "win1",
+				ResourceGroup: "my-rg",
+				Cluster:       "my-cluster",
+				SKU:           "SKU123",
+				Version:       to.StringPtr("9.99.9999"),
+				Replicas:      2,
+				OSDiskSizeGB:  100,
+				MaxPods:       to.Int32Ptr(12),
+				OsDiskType:    to.StringPtr(string(containerservice.OSDiskTypeManaged)),
+				OSType:        to.StringPtr(azure.WindowsOS),
+			},
+			expectedError: "",
+			expect: func(m *mock_agentpools.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "win1").Return(containerservice.AgentPool{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", "win1", gomock.AssignableToTypeOf(containerservice.AgentPool{})).
+					DoAndReturn(func(_ context.Context, _, _, _ string, agentPool containerservice.AgentPool) error {
+						if agentPool.OsType != containerservice.OSTypeWindows {
+							return errors.Errorf("expected OsType %s, got %s", containerservice.OSTypeWindows, agentPool.OsType)
+						}
+						return nil
+					})
+			},
+		},
+		{
+			name: "can create a Spot Agent Pool",
+			agentPoolsSpec: azure.AgentPoolSpec{
+				Name:          "my-spot-pool",
+				ResourceGroup: "my-rg",
+				Cluster:       "my-cluster",
+				SKU:           "SKU123",
+				Version:       to.StringPtr("9.99.9999"),
+				Replicas:      2,
+				OSDiskSizeGB:  100,
+				MaxPods:       to.Int32Ptr(12),
+				OsDiskType:    to.StringPtr(string(containerservice.OSDiskTypeManaged)),
+			},
+			spotVMOptions: &infrav1.SpotVMOptions{
+				MaxPrice: resourceQuantityPtr("0.5"),
+			},
+			expectedError: "",
+			expectedTaints: infraexpv1.Taints{
+				{
+					Key:    "kubernetes.azure.com/scalesetpriority",
+					Value:  "spot",
+					Effect: infraexpv1.TaintEffectNoSchedule,
+				},
+			},
+			expect: func(m *mock_agentpools.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-spot-pool").Return(containerservice.AgentPool{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", "my-spot-pool", gomock.AssignableToTypeOf(containerservice.AgentPool{})).
+					DoAndReturn(func(_ context.Context, _, _, _ string, agentPool containerservice.AgentPool) error {
+						if agentPool.ScaleSetPriority != containerservice.ScaleSetPrioritySpot {
+							return errors.Errorf("expected ScaleSetPriority %s, got %s", containerservice.ScaleSetPrioritySpot, agentPool.ScaleSetPriority)
+						}
+						if agentPool.ScaleSetEvictionPolicy != containerservice.ScaleSetEvictionPolicyDelete {
+							return errors.Errorf("expected ScaleSetEvictionPolicy %s, got %s", containerservice.ScaleSetEvictionPolicyDelete, agentPool.ScaleSetEvictionPolicy)
+						}
+						if to.Float64(agentPool.SpotMaxPrice) != 0.5 {
+							return errors.Errorf("expected SpotMaxPrice 0.5, got %v", agentPool.SpotMaxPrice)
+						}
+						if !reflect.DeepEqual(agentPool.NodeTaints, &[]string{"kubernetes.azure.com/scalesetpriority=spot:NoSchedule"}) {
+							return errors.Errorf("unexpected node taints %v", agentPool.NodeTaints)
+						}
+						return nil
+					})
+			},
+		},
+		{
+			name: "can create an Agent Pool with a pod subnet",
+			agentPoolsSpec: azure.AgentPoolSpec{
+				Name:          "my-agent-pool",
+				ResourceGroup: "my-rg",
+				Cluster:       "my-cluster",
+				SKU:           "SKU123",
+				Version:       to.StringPtr("9.99.9999"),
+				Replicas:      2,
+				OSDiskSizeGB:  100,
+				MaxPods:       to.Int32Ptr(12),
+				OsDiskType:    to.StringPtr(string(containerservice.OSDiskTypeManaged)),
+			},
+			podSubnetName: to.StringPtr("pods"),
+			expectedError: "",
+			expect: func(m *mock_agentpools.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(containerservice.AgentPool{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool", gomock.AssignableToTypeOf(containerservice.AgentPool{})).
+					DoAndReturn(func(_ context.Context, _, _, _ string, agentPool containerservice.AgentPool) error {
+						if !strings.HasSuffix(to.String(agentPool.PodSubnetID), "/subnets/pods") {
+							return errors.Errorf("unexpected PodSubnetID %s", to.String(agentPool.PodSubnetID))
+						}
+						return nil
+					})
+			},
+		},
 		{
 			name: "fail to create an Agent Pool",
 			agentPoolsSpec: azure.AgentPoolSpec{
@@ -261,6 +369,225 @@ func TestReconcile(t *testing.T) {
 				}, nil)
 			},
 		},
+		{
+			name: "no update needed on an Agent Pool upgraded by the auto-upgrade channel",
+			agentPoolsSpec: azure.AgentPoolSpec{
+				Name:          "my-agent-pool",
+				ResourceGroup: "my-rg",
+				Cluster:       "my-cluster",
+				SKU:           "Standard_D2s_v3",
+				Version:       to.StringPtr("1.21.2"),
+				Replicas:      2,
+				OSDiskSizeGB:  100,
+				MaxPods:       to.Int32Ptr(12),
+				OsDiskType:    to.StringPtr(string(containerservice.OSDiskTypeEphemeral)),
+			},
+			autoUpgradeProfile: &infraexpv1.AutoUpgradeProfile{
+				UpgradeChannel: infraexpv1.UpgradeChannelStable,
+			},
+			expectedError: "",
+			expect: func(m *mock_agentpools.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(containerservice.AgentPool{
+					ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
+						Count:               to.Int32Ptr(2),
+						OsDiskSizeGB:        to.Int32Ptr(100),
+						VMSize:              to.StringPtr(string(containerservice.VMSizeTypesStandardD2sV3)),
+						OsType:              containerservice.OSTypeLinux,
+						OrchestratorVersion: to.StringPtr("1.22.4"),
+						ProvisioningState:   to.StringPtr("Succeeded"),
+						VnetSubnetID:        to.StringPtr(""),
+						MaxPods:             to.Int32Ptr(12),
+						OsDiskType:          containerservice.OSDiskTypeEphemeral,
+					},
+				}, nil)
+			},
+		},
+		{
+			name: "no update needed on a Spot Agent Pool",
+			agentPoolsSpec: azure.AgentPoolSpec{
+				Name:          "my-spot-pool",
+				ResourceGroup: "my-rg",
+				Cluster:       "my-cluster",
+				SKU:           "Standard_D2s_v3",
+				Version:       to.StringPtr("9.99.9999"),
+				Replicas:      2,
+				OSDiskSizeGB:  100,
+				MaxPods:       to.Int32Ptr(12),
+				OsDiskType:    to.StringPtr(string(containerservice.OSDiskTypeEphemeral)),
+			},
+			spotVMOptions: &infrav1.SpotVMOptions{},
+			expectedError: "",
+			expectedTaints: infraexpv1.Taints{
+				{
+					Key:    "kubernetes.azure.com/scalesetpriority",
+					Value:  "spot",
+					Effect: infraexpv1.TaintEffectNoSchedule,
+				},
+			},
+			expect: func(m *mock_agentpools.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-spot-pool").Return(containerservice.AgentPool{
+					ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
+						Count:                  to.Int32Ptr(2),
+						OsDiskSizeGB:           to.Int32Ptr(100),
+						VMSize:                 to.StringPtr(string(containerservice.VMSizeTypesStandardD2sV3)),
+						OsType:                 containerservice.OSTypeLinux,
+						OrchestratorVersion:    to.StringPtr("9.99.9999"),
+						ProvisioningState:      to.StringPtr("Succeeded"),
+						VnetSubnetID:           to.StringPtr(""),
+						MaxPods:                to.Int32Ptr(12),
+						OsDiskType:             containerservice.OSDiskTypeEphemeral,
+						ScaleSetPriority:       containerservice.ScaleSetPrioritySpot,
+						ScaleSetEvictionPolicy: containerservice.ScaleSetEvictionPolicyDelete,
+						SpotMaxPrice:           to.Float64Ptr(-1),
+						NodeLabels: map[string]*string{
+							"kubernetes.azure.com/scalesetpriority": to.StringPtr("spot"),
+						},
+						NodeTaints: &[]string{"kubernetes.azure.com/scalesetpriority=spot:NoSchedule"},
+					},
+				}, nil)
+			},
+		},
+		{
+			name: "update Agent Pool when taints are added",
+			agentPoolsSpec: azure.AgentPoolSpec{
+				Name:          "my-agent-pool",
+				ResourceGroup: "my-rg",
+				Cluster:       "my-cluster",
+				SKU:           "Standard_D2s_v3",
+				Version:       to.StringPtr("9.99.9999"),
+				Replicas:      2,
+				OSDiskSizeGB:  100,
+				MaxPods:       to.Int32Ptr(12),
+				OsDiskType:    to.StringPtr(string(containerservice.OSDiskTypeEphemeral)),
+			},
+			taints: infraexpv1.Taints{
+				{
+					Key:    "CriticalAddonsOnly",
+					Value:  "true",
+					Effect: infraexpv1.TaintEffectNoSchedule,
+				},
+			},
+			expectedError: "",
+			expect: func(m *mock_agentpools.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(containerservice.AgentPool{
+					ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
+						Count:               to.Int32Ptr(2),
+						OsDiskSizeGB:        to.Int32Ptr(100),
+						VMSize:              to.StringPtr(string(containerservice.VMSizeTypesStandardD2sV3)),
+						OsType:              containerservice.OSTypeLinux,
+						OrchestratorVersion: to.StringPtr("9.99.9999"),
+						ProvisioningState:   to.StringPtr("Succeeded"),
+						VnetSubnetID:        to.StringPtr(""),
+						MaxPods:             to.Int32Ptr(12),
+						OsDiskType:          containerservice.OSDiskTypeEphemeral,
+					},
+				}, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool", gomock.AssignableToTypeOf(containerservice.AgentPool{})).
+					DoAndReturn(func(_ context.Context, _, _, _ string, agentPool containerservice.AgentPool) error {
+						if !reflect.DeepEqual(agentPool.NodeTaints, &[]string{"CriticalAddonsOnly=true:NoSchedule"}) {
+							return errors.Errorf("unexpected node taints %v", agentPool.NodeTaints)
+						}
+						return nil
+					})
+			},
+		},
+		{
+			name: "update Agent Pool when taints are removed",
+			agentPoolsSpec: azure.AgentPoolSpec{
+				Name:          "my-agent-pool",
+				ResourceGroup: "my-rg",
+				Cluster:       "my-cluster",
+				SKU:           "Standard_D2s_v3",
+				Version:       to.StringPtr("9.99.9999"),
+				Replicas:      2,
+				OSDiskSizeGB:  100,
+				MaxPods:       to.Int32Ptr(12),
+				OsDiskType:    to.StringPtr(string(containerservice.OSDiskTypeEphemeral)),
+			},
+			expectedError: "",
+			expect: func(m *mock_agentpools.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(containerservice.AgentPool{
+					ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
+						Count:               to.Int32Ptr(2),
+						OsDiskSizeGB:        to.Int32Ptr(100),
+						VMSize:              to.StringPtr(string(containerservice.VMSizeTypesStandardD2sV3)),
+						OsType:              containerservice.OSTypeLinux,
+						OrchestratorVersion: to.StringPtr("9.99.9999"),
+						ProvisioningState:   to.StringPtr("Succeeded"),
+						VnetSubnetID:        to.StringPtr(""),
+						MaxPods:             to.Int32Ptr(12),
+						OsDiskType:          containerservice.OSDiskTypeEphemeral,
+						NodeTaints:          &[]string{"CriticalAddonsOnly=true:NoSchedule"},
+					},
+				}, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool", gomock.AssignableToTypeOf(containerservice.AgentPool{})).
+					DoAndReturn(func(_ context.Context, _, _, _ string, agentPool containerservice.AgentPool) error {
+						if agentPool.NodeTaints == nil || len(*agentPool.NodeTaints) != 0 {
+							return errors.Errorf("expected an empty list of node taints, got %v", agentPool.NodeTaints)
+						}
+						return nil
+					})
+			},
+		},
+		{
+			name: "wait for the control plane before upgrading an Agent Pool",
+			agentPoolsSpec: azure.AgentPoolSpec{
+				Name:          "my-agent-pool",
+				ResourceGroup: "my-rg",
+				Cluster:       "my-cluster",
+				SKU:           "Standard_D2s_v3",
+				Version:       to.StringPtr("1.23.3"),
+				Replicas:      2,
+				OSDiskSizeGB:  100,
+				MaxPods:       to.Int32Ptr(12),
+				OsDiskType:    to.StringPtr(string(containerservice.OSDiskTypeEphemeral)),
+			},
+			controlPlaneVersion: "1.22.4",
+			expectedError:       "waiting for the control plane to be upgraded from Kubernetes version 1.22.4 to 1.23.3. Object will be requeued after 20s",
+			expect:              func(m *mock_agentpools.MockClientMockRecorder) {},
+		},
+		{
+			name: "upgrade Agent Pool once the control plane is upgraded",
+			agentPoolsSpec: azure.AgentPoolSpec{
+				Name:          "my-agent-pool",
+				ResourceGroup: "my-rg",
+				Cluster:       "my-cluster",
+				SKU:           "Standard_D2s_v3",
+				Version:       to.StringPtr("1.23.3"),
+				Replicas:      2,
+				OSDiskSizeGB:  100,
+				MaxPods:       to.Int32Ptr(12),
+				OsDiskType:    to.StringPtr(string(containerservice.OSDiskTypeEphemeral)),
+			},
+			upgradeSettings:     &infraexpv1.ManagedMachinePoolUpgradeSettings{MaxSurge: to.StringPtr("33%")},
+			controlPlaneVersion: "1.23.3",
+			expectedError:       "",
+			expect: func(m *mock_agentpools.MockClientMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool").Return(containerservice.AgentPool{
+					ManagedClusterAgentPoolProfileProperties: &containerservice.ManagedClusterAgentPoolProfileProperties{
+						Count:               to.Int32Ptr(2),
+						OsDiskSizeGB:        to.Int32Ptr(100),
+						VMSize:              to.StringPtr(string(containerservice.VMSizeTypesStandardD2sV3)),
+						OsType:              containerservice.OSTypeLinux,
+						OrchestratorVersion: to.StringPtr("1.22.4"),
+						ProvisioningState:   to.StringPtr("Succeeded"),
+						VnetSubnetID:        to.StringPtr(""),
+						MaxPods:             to.Int32Ptr(12),
+						OsDiskType:          containerservice.OSDiskTypeEphemeral,
+					},
+				}, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-cluster", "my-agent-pool", gomock.AssignableToTypeOf(containerservice.AgentPool{})).
+					DoAndReturn(func(_ context.Context, _, _, _ string, agentPool containerservice.AgentPool) error {
+						if to.String(agentPool.OrchestratorVersion) != "1.23.3" {
+							return errors.Errorf("expected the orchestrator version to be 1.23.3, got %s", to.String(agentPool.OrchestratorVersion))
+						}
+						if agentPool.UpgradeSettings == nil || to.String(agentPool.UpgradeSettings.MaxSurge) != "33%" {
+							return errors.Errorf("expected a max surge of 33%%, got %v", agentPool.UpgradeSettings)
+						}
+						return nil
+					})
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -282,7 +609,11 @@ func TestReconcile(t *testing.T) {
 						Name: tc.agentPoolsSpec.Cluster,
 					},
 					Spec: infraexpv1.AzureManagedControlPlaneSpec{
-						ResourceGroupName: tc.agentPoolsSpec.ResourceGroup,
+						ResourceGroupName:  tc.agentPoolsSpec.ResourceGroup,
+						AutoUpgradeProfile: tc.autoUpgradeProfile,
+					},
+					Status: infraexpv1.AzureManagedControlPlaneStatus{
+						Version: tc.controlPlaneVersion,
 					},
 				},
 				MachinePool: &capiexp.MachinePool{
@@ -300,11 +631,16 @@ func TestReconcile(t *testing.T) {
 						Name: tc.agentPoolsSpec.Name,
 					},
 					Spec: infraexpv1.AzureManagedMachinePoolSpec{
-						Name:         &tc.agentPoolsSpec.Name,
-						SKU:          tc.agentPoolsSpec.SKU,
-						OSDiskSizeGB: &osDiskSizeGB,
-						MaxPods:      to.Int32Ptr(12),
-						OsDiskType:   to.StringPtr(string(containerservice.OSDiskTypeManaged)),
+						Name:            &tc.agentPoolsSpec.Name,
+						SKU:             tc.agentPoolsSpec.SKU,
+						OSDiskSizeGB:    &osDiskSizeGB,
+						MaxPods:         to.Int32Ptr(12),
+						OsDiskType:      to.StringPtr(string(containerservice.OSDiskTypeManaged)),
+						OSType:          tc.agentPoolsSpec.OSType,
+						Taints:          tc.taints,
+						SpotVMOptions:   tc.spotVMOptions,
+						PodSubnetName:   tc.podSubnetName,
+						UpgradeSettings: tc.upgradeSettings,
 					},
 				},
 			}
@@ -323,6 +659,9 @@ func TestReconcile(t *testing.T) {
 			} else {
 				g.Expect(err).NotTo(HaveOccurred())
 			}
+			if tc.expectedTaints != nil {
+				g.Expect(machinePoolScope.InfraMachinePool.Status.Taints).To(Equal(tc.expectedTaints))
+			}
 		})
 	}
 }
@@ -420,3 +759,8 @@ func TestDeleteAgentPools(t *testing.T) {
 		})
 	}
 }
+
+func resourceQuantityPtr(s string) *resource.Quantity {
+	q := resource.MustParse(s)
+	return &q
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identities
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-10-01/resources"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+// userAssignedIdentitiesAPIVersion is the API version of the Microsoft.ManagedIdentity/userAssignedIdentities
+// resource type.
+const userAssignedIdentitiesAPIVersion = "2018-11-30"
+
+// Client wraps go-sdk.
+type Client interface {
+	GetPrincipalID(ctx context.Context, resourceID string) (string, error)
+}
+
+// AzureClient contains the Azure go-sdk Client.
+type AzureClient struct {
+	resources resources.Client
+}
+
+var _ Client = (*AzureClient)(nil)
+
+// NewClient creates a new user-assigned identities client from subscription ID.
+func NewClient(auth azure.Authorizer) *AzureClient {
+	c := newResourcesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
+	return &AzureClient{c}
+}
+
+// newResourcesClient creates a new resources client from subscription ID.
+func newResourcesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) resources.Client {
+	resourcesClient := resources.NewClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&resourcesClient.Client, authorizer)
+	return resourcesClient
+}
+
+// GetPrincipalID returns the ID of the service principal of a user-assigned identity, which role assignments refer
+// to. The identity is read as a generic resource, so that it may belong to any subscription.
+func (ac *AzureClient) GetPrincipalID(ctx context.Context, resourceID string) (string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "identities.AzureClient.GetPrincipalID")
+	defer done()
+
+	identity, err := ac.resources.GetByID(ctx, resourceID, userAssignedIdentitiesAPIVersion)
+	if err != nil {
+		return "", err
+	}
+
+	properties, ok := identity.Properties.(map[string]interface{})
+	if !ok {
+		return "", errors.Errorf("user-assigned identity %s has no properties", resourceID)
+	}
+	principalID, ok := properties["principalId"].(string)
+	if !ok || principalID == "" {
+		return "", errors.Errorf("user-assigned identity %s has no principal ID", resourceID)
+	}
+	return principalID, nil
+}
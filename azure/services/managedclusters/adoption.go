@@ -0,0 +1,229 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managedclusters
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-08-01/containerservice"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// ReconcileAdoption returns an error until the adoption of an existing managed cluster that is not tagged as owned
+// by the cluster is confirmed, so that the resources the managed cluster depends on, such as its resource group,
+// virtual network and role assignments, are not modified before CAPZ may take ownership of the managed cluster.
+// It returns nil if the managed cluster does not exist yet or is owned by the cluster.
+func (s *Service) ReconcileAdoption(ctx context.Context) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.Service.ReconcileAdoption")
+	defer done()
+
+	managedClusterSpec, err := s.Scope.ManagedClusterSpec(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get managed cluster spec")
+	}
+
+	existingMC, err := s.Client.Get(ctx, managedClusterSpec.ResourceGroupName, managedClusterSpec.Name)
+	if azure.ResourceNotFound(err) {
+		return nil
+	} else if err != nil {
+		return azure.WithTransientError(errors.Wrap(err, "failed to fetch existing managed cluster"), 20*time.Second)
+	}
+
+	if converters.MapToTags(existingMC.Tags).HasOwned(s.Scope.ClusterName()) {
+		return nil
+	}
+	return s.reconcileAdoption(ctx, managedClusterSpec, existingMC)
+}
+
+// reconcileAdoption validates an existing managed cluster that was not created by CAPZ against the spec of the
+// managed cluster and its agent pools. It returns an error, and leaves the managed cluster untouched, until the spec
+// matches the managed cluster and its adoption is confirmed.
+func (s *Service) reconcileAdoption(ctx context.Context, managedClusterSpec azure.ManagedClusterSpec, existingMC containerservice.ManagedCluster) error {
+	agentPoolSpecs, err := s.Scope.GetAllAgentPoolSpecs(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get agent pool specs for managed cluster %s", s.Scope.ClusterName())
+	}
+
+	mismatches := managedClusterMismatches(managedClusterSpec, existingMC)
+	mismatches = append(mismatches, agentPoolMismatches(agentPoolSpecs, existingMC)...)
+	if len(mismatches) > 0 {
+		msg := fmt.Sprintf("existing managed cluster %s does not match its spec: %s", managedClusterSpec.Name, strings.Join(mismatches, "; "))
+		s.Scope.SetAdoptionCondition(infrav1.ManagedClusterAdoptionMismatchReason, clusterv1.ConditionSeverityWarning, msg)
+		return errors.New(msg)
+	}
+
+	if !s.Scope.ManagedClusterAdoptionConfirmed() {
+		msg := fmt.Sprintf("existing managed cluster %s matches its spec but was not created by CAPZ, set the %s annotation to \"true\" to adopt it",
+			managedClusterSpec.Name, infrav1exp.AdoptAnnotation)
+		klog.V(2).Infof(msg)
+		s.Scope.SetAdoptionCondition(infrav1.ManagedClusterAdoptionPendingReason, clusterv1.ConditionSeverityInfo, msg)
+		return azure.WithTransientError(errors.New(msg), 20*time.Second)
+	}
+
+	klog.V(2).Infof("Adopting existing managed cluster %s", managedClusterSpec.Name)
+	s.Scope.SetAdoptionCondition("", "", "")
+	return nil
+}
+
+// managedClusterMismatches returns the differences between the spec and an existing managed cluster that cannot be
+// reconciled, because AKS does not support updating the properties or CAPZ does not manage them.
+// Properties that are not set in the spec are not compared.
+func managedClusterMismatches(spec azure.ManagedClusterSpec, existingMC containerservice.ManagedCluster) []string {
+	var mismatches []string
+	mismatch := func(property, desired, existing string) {
+		mismatches = append(mismatches, fmt.Sprintf("%s is %q instead of %q", property, existing, desired))
+	}
+
+	if !equalLocations(spec.Location, to.String(existingMC.Location)) {
+		mismatch("location", spec.Location, to.String(existingMC.Location))
+	}
+
+	if existingMC.ManagedClusterProperties == nil {
+		return mismatches
+	}
+
+	if spec.NodeResourceGroupName != "" && !strings.EqualFold(spec.NodeResourceGroupName, to.String(existingMC.NodeResourceGroup)) {
+		mismatch("node resource group", spec.NodeResourceGroupName, to.String(existingMC.NodeResourceGroup))
+	}
+
+	if existingMC.LinuxProfile != nil && existingMC.LinuxProfile.SSH != nil && existingMC.LinuxProfile.SSH.PublicKeys != nil &&
+		len(*existingMC.LinuxProfile.SSH.PublicKeys) > 0 {
+		existingKey := to.String((*existingMC.LinuxProfile.SSH.PublicKeys)[0].KeyData)
+		if strings.TrimSpace(spec.SSHPublicKey) != strings.TrimSpace(existingKey) {
+			mismatches = append(mismatches, "SSH public key differs")
+		}
+	}
+
+	if existingMC.ServicePrincipalProfile != nil && to.String(existingMC.ServicePrincipalProfile.ClientID) != managedIdentity {
+		mismatches = append(mismatches, "cluster uses a service principal instead of a managed identity")
+	}
+
+	if spec.KubeletUserAssignedIdentity != "" {
+		var existingKubeletIdentity string
+		if identity, ok := existingMC.IdentityProfile[azure.KubeletIdentityKey]; ok && identity != nil {
+			existingKubeletIdentity = to.String(identity.ResourceID)
+		}
+		if !strings.EqualFold(spec.KubeletUserAssignedIdentity, existingKubeletIdentity) {
+			mismatch("kubelet identity", spec.KubeletUserAssignedIdentity, existingKubeletIdentity)
+		}
+	}
+
+	if np := existingMC.NetworkProfile; np != nil {
+		if spec.NetworkPlugin != "" && spec.NetworkPlugin != string(np.NetworkPlugin) {
+			mismatch("network plugin", spec.NetworkPlugin, string(np.NetworkPlugin))
+		}
+		if spec.NetworkPolicy != "" && spec.NetworkPolicy != string(np.NetworkPolicy) {
+			mismatch("network policy", spec.NetworkPolicy, string(np.NetworkPolicy))
+		}
+		if spec.LoadBalancerSKU != "" && !strings.EqualFold(spec.LoadBalancerSKU, string(np.LoadBalancerSku)) {
+			mismatch("load balancer SKU", spec.LoadBalancerSKU, string(np.LoadBalancerSku))
+		}
+		if spec.OutboundType != "" && spec.OutboundType != string(np.OutboundType) {
+			mismatch("outbound type", spec.OutboundType, string(np.OutboundType))
+		}
+		if spec.PodCIDR != "" && spec.PodCIDR != to.String(np.PodCidr) {
+			mismatch("pod CIDR", spec.PodCIDR, to.String(np.PodCidr))
+		}
+		if spec.ServiceCIDR != "" && spec.ServiceCIDR != to.String(np.ServiceCidr) {
+			mismatch("service CIDR", spec.ServiceCIDR, to.String(np.ServiceCidr))
+		}
+		if spec.DNSServiceIP != nil && *spec.DNSServiceIP != to.String(np.DNSServiceIP) {
+			mismatch("DNS service IP", *spec.DNSServiceIP, to.String(np.DNSServiceIP))
+		}
+	}
+
+	return mismatches
+}
+
+// agentPoolMismatches returns the differences between the agent pool specs and the existing agent pools of the same
+// name that AKS does not support updating. Agent pools that do not exist yet are created once the managed cluster is
+// adopted, and existing agent pools without a spec are left unmanaged.
+func agentPoolMismatches(agentPoolSpecs []azure.AgentPoolSpec, existingMC containerservice.ManagedCluster) []string {
+	if existingMC.ManagedClusterProperties == nil || existingMC.AgentPoolProfiles == nil {
+		return nil
+	}
+
+	existingPools := make(map[string]containerservice.ManagedClusterAgentPoolProfile, len(*existingMC.AgentPoolProfiles))
+	for _, pool := range *existingMC.AgentPoolProfiles {
+		existingPools[to.String(pool.Name)] = pool
+	}
+
+	var mismatches []string
+	for _, spec := range agentPoolSpecs {
+		pool, ok := existingPools[spec.Name]
+		if !ok {
+			continue
+		}
+		mismatch := func(property, desired, existing string) {
+			mismatches = append(mismatches, fmt.Sprintf("%s of agent pool %s is %q instead of %q", property, spec.Name, existing, desired))
+		}
+
+		if spec.SKU != "" && !strings.EqualFold(spec.SKU, to.String(pool.VMSize)) {
+			mismatch("SKU", spec.SKU, to.String(pool.VMSize))
+		}
+		if spec.OSDiskSizeGB != 0 && spec.OSDiskSizeGB != to.Int32(pool.OsDiskSizeGB) {
+			mismatch("OS disk size", fmt.Sprint(spec.OSDiskSizeGB), fmt.Sprint(to.Int32(pool.OsDiskSizeGB)))
+		}
+		if spec.OsDiskType != nil && *spec.OsDiskType != string(pool.OsDiskType) {
+			mismatch("OS disk type", *spec.OsDiskType, string(pool.OsDiskType))
+		}
+		if spec.OSType != nil && *spec.OSType != string(pool.OsType) {
+			mismatch("OS type", *spec.OSType, string(pool.OsType))
+		}
+		if spec.MaxPods != nil && *spec.MaxPods != to.Int32(pool.MaxPods) {
+			mismatch("max pods", fmt.Sprint(*spec.MaxPods), fmt.Sprint(to.Int32(pool.MaxPods)))
+		}
+		// Agent pools in a virtual network managed by AKS don't have a subnet.
+		if spec.VnetSubnetID != "" && !strings.EqualFold(spec.VnetSubnetID, to.String(pool.VnetSubnetID)) {
+			mismatch("subnet", spec.VnetSubnetID, to.String(pool.VnetSubnetID))
+		}
+	}
+
+	return mismatches
+}
+
+// equalLocations returns true if both Azure locations are the same, as AKS returns the normalized location name,
+// such as "eastus" for "East US".
+func equalLocations(a, b string) bool {
+	normalize := func(location string) string {
+		return strings.ToLower(strings.ReplaceAll(location, " ", ""))
+	}
+	return normalize(a) == normalize(b)
+}
+
+// mergeTags returns the desired tags together with the existing tags that are not part of the desired ones.
+func mergeTags(desired, existing map[string]*string) map[string]*string {
+	merged := make(map[string]*string, len(desired)+len(existing))
+	for k, v := range existing {
+		merged[k] = v
+	}
+	for k, v := range desired {
+		merged[k] = v
+	}
+	return merged
+}
@@ -19,7 +19,7 @@ package managedclusters
 import (
 	"context"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
+	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-08-01/containerservice"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/pkg/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
@@ -30,6 +30,7 @@ import (
 type Client interface {
 	Get(context.Context, string, string) (containerservice.ManagedCluster, error)
 	GetCredentials(context.Context, string, string) ([]byte, error)
+	GetUpgradeProfile(context.Context, string, string) (containerservice.ManagedClusterUpgradeProfile, error)
 	CreateOrUpdate(context.Context, string, string, containerservice.ManagedCluster) (containerservice.ManagedCluster, error)
 	Delete(context.Context, string, string) error
 }
@@ -77,6 +78,14 @@ func (ac *AzureClient) GetCredentials(ctx context.Context, resourceGroupName, na
 	return *(*credentialList.Kubeconfigs)[0].Value, nil
 }
 
+// GetUpgradeProfile gets the Kubernetes versions that a managed cluster can be upgraded to.
+func (ac *AzureClient) GetUpgradeProfile(ctx context.Context, resourceGroupName, name string) (containerservice.ManagedClusterUpgradeProfile, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.AzureClient.GetUpgradeProfile")
+	defer done()
+
+	return ac.managedclusters.GetUpgradeProfile(ctx, resourceGroupName, name)
+}
+
 // CreateOrUpdate creates or updates a managed cluster.
 func (ac *AzureClient) CreateOrUpdate(ctx context.Context, resourceGroupName, name string, cluster containerservice.ManagedCluster) (containerservice.ManagedCluster, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.AzureClient.CreateOrUpdate")
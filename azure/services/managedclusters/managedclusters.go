@@ -18,18 +18,24 @@ package managedclusters
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"net"
+	"strings"
 	"time"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
+	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-08-01/containerservice"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
+	"golang.org/x/mod/semver"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
 	infrav1alpha4 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
@@ -39,12 +45,22 @@ var (
 	managedIdentity = "msi"
 )
 
+// upgradingProvisioningState is the provisioning state of a managed cluster or agent pool while AKS upgrades its
+// Kubernetes version.
+const upgradingProvisioningState = "Upgrading"
+
 // ManagedClusterScope defines the scope interface for a managed cluster.
 type ManagedClusterScope interface {
 	azure.ClusterDescriber
-	ManagedClusterSpec() (azure.ManagedClusterSpec, error)
+	ManagedClusterSpec(ctx context.Context) (azure.ManagedClusterSpec, error)
 	GetAllAgentPoolSpecs(ctx context.Context) ([]azure.AgentPoolSpec, error)
 	SetControlPlaneEndpoint(clusterv1.APIEndpoint)
+	SetControlPlaneVersion(string)
+	SetUpgradeCondition(clusterv1.ConditionType, string, clusterv1.ConditionSeverity, string)
+	ManagedClusterAdoptionConfirmed() bool
+	SetAdoptionCondition(string, clusterv1.ConditionSeverity, string)
+	WindowsAdminPasswordHash() string
+	SetWindowsAdminPasswordHash(string)
 	MakeEmptyKubeConfigSecret() corev1.Secret
 	GetKubeConfigData() []byte
 	SetKubeConfigData([]byte)
@@ -117,6 +133,43 @@ func computeDiffOfNormalizedClusters(managedCluster containerservice.ManagedClus
 		}
 	}
 
+	// The admin password is never returned by AKS, so the hash of the desired password is compared with the hash of the
+	// password last applied, which the caller sets as the password of the existing cluster.
+	if managedCluster.WindowsProfile != nil {
+		propertiesNormalized.WindowsProfile = &containerservice.ManagedClusterWindowsProfile{
+			AdminUsername: managedCluster.WindowsProfile.AdminUsername,
+			AdminPassword: to.StringPtr(windowsAdminPasswordHash(managedCluster.WindowsProfile)),
+		}
+
+		if existingMC.WindowsProfile != nil {
+			existingMCPropertiesNormalized.WindowsProfile = &containerservice.ManagedClusterWindowsProfile{
+				AdminUsername: existingMC.WindowsProfile.AdminUsername,
+				AdminPassword: existingMC.WindowsProfile.AdminPassword,
+			}
+		}
+	}
+
+	if len(managedCluster.AddonProfiles) > 0 {
+		propertiesNormalized.AddonProfiles, existingMCPropertiesNormalized.AddonProfiles = normalizedAddonProfiles(managedCluster.AddonProfiles, existingMC.AddonProfiles)
+	}
+
+	if managedCluster.AutoScalerProfile != nil {
+		propertiesNormalized.AutoScalerProfile, existingMCPropertiesNormalized.AutoScalerProfile = normalizedAutoScalerProfile(managedCluster.AutoScalerProfile, existingMC.AutoScalerProfile)
+	}
+
+	// AKS does not return an auto-upgrade profile when auto-upgrades are disabled.
+	if managedCluster.AutoUpgradeProfile != nil {
+		propertiesNormalized.AutoUpgradeProfile = &containerservice.ManagedClusterAutoUpgradeProfile{
+			UpgradeChannel: managedCluster.AutoUpgradeProfile.UpgradeChannel,
+		}
+		existingMCPropertiesNormalized.AutoUpgradeProfile = &containerservice.ManagedClusterAutoUpgradeProfile{
+			UpgradeChannel: containerservice.UpgradeChannelNone,
+		}
+		if existingMC.AutoUpgradeProfile != nil && existingMC.AutoUpgradeProfile.UpgradeChannel != "" {
+			existingMCPropertiesNormalized.AutoUpgradeProfile.UpgradeChannel = existingMC.AutoUpgradeProfile.UpgradeChannel
+		}
+	}
+
 	clusterNormalized := &containerservice.ManagedCluster{
 		ManagedClusterProperties: propertiesNormalized,
 	}
@@ -124,6 +177,12 @@ func computeDiffOfNormalizedClusters(managedCluster containerservice.ManagedClus
 		ManagedClusterProperties: existingMCPropertiesNormalized,
 	}
 
+	// Clusters that use a service principal have no identity, and are not migrated to a managed identity.
+	if existingMC.Identity != nil {
+		clusterNormalized.Identity = normalizedIdentity(managedCluster.Identity)
+		existingMCClusterNormalized.Identity = normalizedIdentity(existingMC.Identity)
+	}
+
 	if managedCluster.Sku != nil {
 		clusterNormalized.Sku = managedCluster.Sku
 	}
@@ -135,6 +194,17 @@ func computeDiffOfNormalizedClusters(managedCluster containerservice.ManagedClus
 	return diff
 }
 
+// windowsAdminPasswordHash returns the SHA-256 hash of the admin password of a Windows profile, or an empty string if
+// the profile has no password.
+func windowsAdminPasswordHash(profile *containerservice.ManagedClusterWindowsProfile) string {
+	if profile == nil || to.String(profile.AdminPassword) == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(to.String(profile.AdminPassword)))
+	return hex.EncodeToString(sum[:])
+}
+
 // New creates a new service.
 func New(scope ManagedClusterScope) *Service {
 	return &Service{
@@ -148,7 +218,7 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.Service.Reconcile")
 	defer done()
 
-	managedClusterSpec, err := s.Scope.ManagedClusterSpec()
+	managedClusterSpec, err := s.Scope.ManagedClusterSpec(ctx)
 	if err != nil {
 		return errors.Wrap(err, "failed to get managed cluster spec")
 	}
@@ -173,10 +243,17 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		}
 	}
 
+	// An existing managed cluster that is not tagged as owned by the cluster was not created by CAPZ, and is only
+	// modified once its adoption is confirmed.
+	adopting := !isCreate && !converters.MapToTags(existingMC.Tags).HasOwned(s.Scope.ClusterName())
+	if adopting {
+		if err := s.reconcileAdoption(ctx, managedClusterSpec, existingMC); err != nil {
+			return err
+		}
+	}
+
 	managedCluster := containerservice.ManagedCluster{
-		Identity: &containerservice.ManagedClusterIdentity{
-			Type: containerservice.ResourceIdentityTypeSystemAssigned,
-		},
+		Identity: managedClusterIdentity(managedClusterSpec),
 		Location: &managedClusterSpec.Location,
 		Tags:     *to.StringMapPtr(managedClusterSpec.Tags),
 		ManagedClusterProperties: &containerservice.ManagedClusterProperties{
@@ -202,17 +279,26 @@ func (s *Service) Reconcile(ctx context.Context) error {
 				NetworkPlugin:   containerservice.NetworkPlugin(managedClusterSpec.NetworkPlugin),
 				LoadBalancerSku: containerservice.LoadBalancerSku(managedClusterSpec.LoadBalancerSKU),
 				NetworkPolicy:   containerservice.NetworkPolicy(managedClusterSpec.NetworkPolicy),
+				OutboundType:    containerservice.OutboundType(managedClusterSpec.OutboundType),
 			},
 		},
 	}
 
+	if managedClusterSpec.KubeletUserAssignedIdentity != "" {
+		managedCluster.IdentityProfile = map[string]*containerservice.UserAssignedIdentity{
+			azure.KubeletIdentityKey: {
+				ResourceID: to.StringPtr(managedClusterSpec.KubeletUserAssignedIdentity),
+			},
+		}
+	}
+
 	if managedClusterSpec.PodCIDR != "" {
 		managedCluster.NetworkProfile.PodCidr = &managedClusterSpec.PodCIDR
 	}
 
 	if managedClusterSpec.ServiceCIDR != "" {
+		managedCluster.NetworkProfile.ServiceCidr = &managedClusterSpec.ServiceCIDR
 		if managedClusterSpec.DNSServiceIP == nil {
-			managedCluster.NetworkProfile.ServiceCidr = &managedClusterSpec.ServiceCIDR
 			ip, _, err := net.ParseCIDR(managedClusterSpec.ServiceCIDR)
 			if err != nil {
 				return fmt.Errorf("failed to parse service cidr: %w", err)
@@ -237,12 +323,32 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			OsDiskSizeGB:        &pool.OSDiskSizeGB,
 			Count:               &pool.Replicas,
 			Type:                containerservice.AgentPoolTypeVirtualMachineScaleSets,
-			VnetSubnetID:        &managedClusterSpec.VnetSubnetID,
+			VnetSubnetID:        &pool.VnetSubnetID,
+			PodSubnetID:         pool.PodSubnetID,
 			Mode:                containerservice.AgentPoolMode(pool.Mode),
 			AvailabilityZones:   &pool.AvailabilityZones,
 			MaxPods:             pool.MaxPods,
 			OrchestratorVersion: pool.Version,
 			OsDiskType:          containerservice.OSDiskType(to.String(pool.OsDiskType)),
+			NodeLabels:          pool.NodeLabels,
+			NodeTaints:          converters.NodeTaintsToSDK(pool.NodeTaints),
+			KubeletConfig:       converters.KubeletConfigToSDK(pool.KubeletConfig),
+			LinuxOSConfig:       converters.LinuxOSConfigToSDK(pool.LinuxOSConfig),
+			SpotMaxPrice:        pool.SpotMaxPrice,
+		}
+		if pool.OSType != nil {
+			profile.OsType = containerservice.OSType(*pool.OSType)
+		}
+		if pool.ScaleSetPriority != nil {
+			profile.ScaleSetPriority = containerservice.ScaleSetPriority(*pool.ScaleSetPriority)
+		}
+		if pool.ScaleSetEvictionPolicy != nil {
+			profile.ScaleSetEvictionPolicy = containerservice.ScaleSetEvictionPolicy(*pool.ScaleSetEvictionPolicy)
+		}
+		if pool.MaxSurge != nil {
+			profile.UpgradeSettings = &containerservice.AgentPoolUpgradeSettings{
+				MaxSurge: pool.MaxSurge,
+			}
 		}
 		*managedCluster.AgentPoolProfiles = append(*managedCluster.AgentPoolProfiles, profile)
 	}
@@ -292,19 +398,73 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		}
 	}
 
+	if managedClusterSpec.WindowsProfile != nil {
+		managedCluster.WindowsProfile = &containerservice.ManagedClusterWindowsProfile{
+			AdminUsername: &managedClusterSpec.WindowsProfile.AdminUsername,
+			AdminPassword: &managedClusterSpec.WindowsProfile.AdminPassword,
+		}
+	}
+
+	if len(managedClusterSpec.AddonProfiles) > 0 {
+		managedCluster.AddonProfiles = make(map[string]*containerservice.ManagedClusterAddonProfile, len(managedClusterSpec.AddonProfiles))
+		for i := range managedClusterSpec.AddonProfiles {
+			addon := managedClusterSpec.AddonProfiles[i]
+			addonProfile := &containerservice.ManagedClusterAddonProfile{
+				Enabled: to.BoolPtr(addon.Enabled),
+			}
+			if len(addon.Config) > 0 {
+				addonProfile.Config = *to.StringMapPtr(addon.Config)
+			}
+			managedCluster.AddonProfiles[addon.Name] = addonProfile
+		}
+	}
+
+	managedCluster.AutoScalerProfile = converters.AutoScalerProfileToSDK(managedClusterSpec.AutoScalerProfile)
+	managedCluster.AutoUpgradeProfile = converters.AutoUpgradeProfileToSDK(managedClusterSpec.AutoUpgradeChannel)
+
 	if isCreate {
+		passwordHash := windowsAdminPasswordHash(managedCluster.WindowsProfile)
 		managedCluster, err = s.Client.CreateOrUpdate(ctx, managedClusterSpec.ResourceGroupName, managedClusterSpec.Name, managedCluster)
 		if err != nil {
 			return fmt.Errorf("failed to create managed cluster, %w", err)
 		}
+		if passwordHash != "" {
+			s.Scope.SetWindowsAdminPasswordHash(passwordHash)
+		}
 	} else {
 		ps := *existingMC.ManagedClusterProperties.ProvisioningState
+		existingVersion := to.String(existingMC.KubernetesVersion)
+		if ps == string(infrav1alpha4.Succeeded) {
+			s.Scope.SetControlPlaneVersion(existingVersion)
+		}
+		if ps == upgradingProvisioningState {
+			s.Scope.SetUpgradeCondition(infrav1alpha4.ManagedClusterUpgradedCondition, infrav1alpha4.ManagedClusterUpgradingReason, clusterv1.ConditionSeverityInfo,
+				fmt.Sprintf("upgrading the control plane to Kubernetes version %s", existingVersion))
+		}
 		if ps != string(infrav1alpha4.Canceled) && ps != string(infrav1alpha4.Failed) && ps != string(infrav1alpha4.Succeeded) {
 			msg := fmt.Sprintf("Unable to update existing managed cluster in non terminal state. Managed cluster must be in one of the following provisioning states: canceled, failed, or succeeded. Actual state: %s", ps)
 			klog.V(2).Infof(msg)
 			return azure.WithTransientError(errors.New(msg), 20*time.Second)
 		}
 
+		// AKS cannot downgrade the control plane, so the version an auto-upgrade channel upgraded it to is kept.
+		if infrav1exp.UpgradeChannel(to.String(managedClusterSpec.AutoUpgradeChannel)).UpgradesKubernetesVersion() &&
+			semver.Compare("v"+existingVersion, "v"+managedClusterSpec.Version) > 0 {
+			klog.V(2).Infof("keeping Kubernetes version %s of the control plane, which was automatically upgraded from %s", existingVersion, managedClusterSpec.Version)
+			managedClusterSpec.Version = existingVersion
+			managedCluster.KubernetesVersion = to.StringPtr(existingVersion)
+		}
+
+		if managedClusterSpec.Version != existingVersion {
+			if err := s.checkUpgradeAvailable(ctx, managedClusterSpec, existingVersion); err != nil {
+				return err
+			}
+			s.Scope.SetUpgradeCondition(infrav1alpha4.ManagedClusterUpgradedCondition, infrav1alpha4.ManagedClusterUpgradingReason, clusterv1.ConditionSeverityInfo,
+				fmt.Sprintf("upgrading the control plane from Kubernetes version %s to %s", existingVersion, managedClusterSpec.Version))
+		} else {
+			s.Scope.SetUpgradeCondition(infrav1alpha4.ManagedClusterUpgradedCondition, "", "", "")
+		}
+
 		// Normalize the LoadBalancerProfile so the diff below doesn't get thrown off by AKS added properties.
 		if managedCluster.NetworkProfile.LoadBalancerProfile == nil {
 			// If our LoadBalancerProfile generated by the spec is nil, then don't worry about what AKS has added.
@@ -319,13 +479,38 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		// AgentPool changes are managed through AMMP
 		managedCluster.AgentPoolProfiles = existingMC.AgentPoolProfiles
 
+		// Keep the add-ons that are not configured on the AMCP, such as add-ons enabled outside of CAPZ.
+		managedCluster.AddonProfiles = mergeAddonProfiles(managedCluster.AddonProfiles, existingMC.AddonProfiles)
+
+		// The kubelet identity cannot be changed, and is created by AKS when it is not set.
+		managedCluster.IdentityProfile = existingMC.IdentityProfile
+
+		// The DNS prefix cannot be changed, and adopted clusters may use another one than the cluster name.
+		if existingMC.DNSPrefix != nil {
+			managedCluster.DNSPrefix = existingMC.DNSPrefix
+		}
+
+		// Tag an adopted cluster as owned by the cluster, keeping the tags it already has.
+		if adopting {
+			managedCluster.Tags = mergeTags(managedCluster.Tags, existingMC.Tags)
+		}
+
+		// AKS never returns the Windows admin password, the one last applied is known by its hash.
+		if existingMC.WindowsProfile != nil {
+			existingMC.WindowsProfile.AdminPassword = to.StringPtr(s.Scope.WindowsAdminPasswordHash())
+		}
+
 		diff := computeDiffOfNormalizedClusters(managedCluster, existingMC)
-		if diff != "" {
+		if diff != "" || adopting {
 			klog.V(2).Infof("Update required (+new -old):\n%s", diff)
+			passwordHash := windowsAdminPasswordHash(managedCluster.WindowsProfile)
 			managedCluster, err = s.Client.CreateOrUpdate(ctx, managedClusterSpec.ResourceGroupName, managedClusterSpec.Name, managedCluster)
 			if err != nil {
 				return fmt.Errorf("failed to update managed cluster, %w", err)
 			}
+			if passwordHash != "" {
+				s.Scope.SetWindowsAdminPasswordHash(passwordHash)
+			}
 		}
 	}
 
@@ -349,14 +534,194 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	return nil
 }
 
+// checkUpgradeAvailable returns an error and marks the control plane upgrade as unavailable when AKS does not offer
+// the desired Kubernetes version as an upgrade of the existing version of the control plane.
+func (s *Service) checkUpgradeAvailable(ctx context.Context, managedClusterSpec azure.ManagedClusterSpec, existingVersion string) error {
+	upgradeProfile, err := s.Client.GetUpgradeProfile(ctx, managedClusterSpec.ResourceGroupName, managedClusterSpec.Name)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the available upgrades of the managed cluster")
+	}
+
+	var available []string
+	if upgradeProfile.ManagedClusterUpgradeProfileProperties != nil && upgradeProfile.ControlPlaneProfile != nil && upgradeProfile.ControlPlaneProfile.Upgrades != nil {
+		for _, upgrade := range *upgradeProfile.ControlPlaneProfile.Upgrades {
+			version := to.String(upgrade.KubernetesVersion)
+			if version == managedClusterSpec.Version {
+				return nil
+			}
+			available = append(available, version)
+		}
+	}
+
+	msg := fmt.Sprintf("Kubernetes version %s is not an available upgrade of the control plane version %s, available upgrades: [%s]",
+		managedClusterSpec.Version, existingVersion, strings.Join(available, ", "))
+	s.Scope.SetUpgradeCondition(infrav1alpha4.ManagedClusterUpgradedCondition, infrav1alpha4.ManagedClusterUpgradeUnavailableReason, clusterv1.ConditionSeverityWarning, msg)
+	return errors.New(msg)
+}
+
+// managedClusterIdentity returns the identity of the control plane, which is the user-assigned identity of the spec
+// when it is set, and a system-assigned identity otherwise.
+func managedClusterIdentity(managedClusterSpec azure.ManagedClusterSpec) *containerservice.ManagedClusterIdentity {
+	if managedClusterSpec.UserAssignedIdentity == "" {
+		return &containerservice.ManagedClusterIdentity{
+			Type: containerservice.ResourceIdentityTypeSystemAssigned,
+		}
+	}
+	return &containerservice.ManagedClusterIdentity{
+		Type: containerservice.ResourceIdentityTypeUserAssigned,
+		UserAssignedIdentities: map[string]*containerservice.ManagedClusterIdentityUserAssignedIdentitiesValue{
+			managedClusterSpec.UserAssignedIdentity: {},
+		},
+	}
+}
+
+// normalizedIdentity returns the type and the resource IDs of the user-assigned identities of a managed cluster
+// identity, in lower case as AKS may return resource IDs in another case than they were specified.
+func normalizedIdentity(identity *containerservice.ManagedClusterIdentity) *containerservice.ManagedClusterIdentity {
+	if identity == nil {
+		return nil
+	}
+	normalized := &containerservice.ManagedClusterIdentity{
+		Type: identity.Type,
+	}
+	if len(identity.UserAssignedIdentities) > 0 {
+		normalized.UserAssignedIdentities = make(map[string]*containerservice.ManagedClusterIdentityUserAssignedIdentitiesValue, len(identity.UserAssignedIdentities))
+		for resourceID := range identity.UserAssignedIdentities {
+			normalized.UserAssignedIdentities[strings.ToLower(resourceID)] = &containerservice.ManagedClusterIdentityUserAssignedIdentitiesValue{}
+		}
+	}
+	return normalized
+}
+
+// mergeAddonProfiles returns the desired add-on profiles together with the existing add-on profiles of the add-ons
+// that are not part of the desired ones.
+func mergeAddonProfiles(desired, existing map[string]*containerservice.ManagedClusterAddonProfile) map[string]*containerservice.ManagedClusterAddonProfile {
+	if len(existing) == 0 {
+		return desired
+	}
+
+	merged := make(map[string]*containerservice.ManagedClusterAddonProfile, len(desired)+len(existing))
+	for name, addon := range existing {
+		if _, ok := findAddonProfile(desired, name); !ok {
+			merged[name] = addon
+		}
+	}
+	for name, addon := range desired {
+		merged[name] = addon
+	}
+	return merged
+}
+
+// normalizedAddonProfiles returns the desired and existing add-on profiles normalized to the enabled flag and the
+// config keys of the desired add-on profiles, as AKS populates the config of enabled add-ons with default values.
+func normalizedAddonProfiles(desired, existing map[string]*containerservice.ManagedClusterAddonProfile) (map[string]*containerservice.ManagedClusterAddonProfile, map[string]*containerservice.ManagedClusterAddonProfile) {
+	desiredNormalized := make(map[string]*containerservice.ManagedClusterAddonProfile, len(desired))
+	existingNormalized := make(map[string]*containerservice.ManagedClusterAddonProfile, len(desired))
+	for name, addon := range desired {
+		enabled := to.Bool(addon.Enabled)
+		desiredNormalized[name] = &containerservice.ManagedClusterAddonProfile{Enabled: to.BoolPtr(enabled)}
+		existingNormalized[name] = &containerservice.ManagedClusterAddonProfile{Enabled: to.BoolPtr(false)}
+
+		existingAddon, ok := findAddonProfile(existing, name)
+		if ok {
+			existingNormalized[name].Enabled = to.BoolPtr(to.Bool(existingAddon.Enabled))
+		}
+
+		// The config of a disabled add-on is not returned by AKS.
+		if !enabled || len(addon.Config) == 0 {
+			continue
+		}
+		desiredNormalized[name].Config = addon.Config
+		existingConfig := make(map[string]*string, len(addon.Config))
+		for key := range addon.Config {
+			if ok {
+				existingConfig[key] = existingAddon.Config[key]
+			} else {
+				existingConfig[key] = nil
+			}
+		}
+		existingNormalized[name].Config = existingConfig
+	}
+	return desiredNormalized, existingNormalized
+}
+
+// normalizedAutoScalerProfile returns the desired autoscaler profile together with the existing autoscaler profile
+// normalized to the parameters set on the desired one, as AKS populates the parameters that are not set with
+// default values.
+func normalizedAutoScalerProfile(desired, existing *containerservice.ManagedClusterPropertiesAutoScalerProfile) (*containerservice.ManagedClusterPropertiesAutoScalerProfile, *containerservice.ManagedClusterPropertiesAutoScalerProfile) {
+	if existing == nil {
+		existing = &containerservice.ManagedClusterPropertiesAutoScalerProfile{}
+	}
+
+	// ifSet returns the existing value of a parameter only when the parameter is set on the desired profile.
+	ifSet := func(desiredValue, existingValue *string) *string {
+		if desiredValue == nil {
+			return nil
+		}
+		return existingValue
+	}
+
+	existingNormalized := &containerservice.ManagedClusterPropertiesAutoScalerProfile{
+		BalanceSimilarNodeGroups:      ifSet(desired.BalanceSimilarNodeGroups, existing.BalanceSimilarNodeGroups),
+		MaxEmptyBulkDelete:            ifSet(desired.MaxEmptyBulkDelete, existing.MaxEmptyBulkDelete),
+		MaxGracefulTerminationSec:     ifSet(desired.MaxGracefulTerminationSec, existing.MaxGracefulTerminationSec),
+		MaxNodeProvisionTime:          ifSet(desired.MaxNodeProvisionTime, existing.MaxNodeProvisionTime),
+		MaxTotalUnreadyPercentage:     ifSet(desired.MaxTotalUnreadyPercentage, existing.MaxTotalUnreadyPercentage),
+		NewPodScaleUpDelay:            ifSet(desired.NewPodScaleUpDelay, existing.NewPodScaleUpDelay),
+		OkTotalUnreadyCount:           ifSet(desired.OkTotalUnreadyCount, existing.OkTotalUnreadyCount),
+		ScanInterval:                  ifSet(desired.ScanInterval, existing.ScanInterval),
+		ScaleDownDelayAfterAdd:        ifSet(desired.ScaleDownDelayAfterAdd, existing.ScaleDownDelayAfterAdd),
+		ScaleDownDelayAfterDelete:     ifSet(desired.ScaleDownDelayAfterDelete, existing.ScaleDownDelayAfterDelete),
+		ScaleDownDelayAfterFailure:    ifSet(desired.ScaleDownDelayAfterFailure, existing.ScaleDownDelayAfterFailure),
+		ScaleDownUnneededTime:         ifSet(desired.ScaleDownUnneededTime, existing.ScaleDownUnneededTime),
+		ScaleDownUnreadyTime:          ifSet(desired.ScaleDownUnreadyTime, existing.ScaleDownUnreadyTime),
+		ScaleDownUtilizationThreshold: ifSet(desired.ScaleDownUtilizationThreshold, existing.ScaleDownUtilizationThreshold),
+		SkipNodesWithLocalStorage:     ifSet(desired.SkipNodesWithLocalStorage, existing.SkipNodesWithLocalStorage),
+		SkipNodesWithSystemPods:       ifSet(desired.SkipNodesWithSystemPods, existing.SkipNodesWithSystemPods),
+	}
+	if desired.Expander != "" {
+		existingNormalized.Expander = existing.Expander
+	}
+
+	return desired, existingNormalized
+}
+
+// findAddonProfile returns the add-on profile with the given name, ignoring case as AKS may return add-on names in a
+// different case than they were specified.
+func findAddonProfile(addonProfiles map[string]*containerservice.ManagedClusterAddonProfile, name string) (*containerservice.ManagedClusterAddonProfile, bool) {
+	if addon, ok := addonProfiles[name]; ok {
+		return addon, addon != nil
+	}
+	for key, addon := range addonProfiles {
+		if strings.EqualFold(key, name) {
+			return addon, addon != nil
+		}
+	}
+	return nil, false
+}
+
 // Delete deletes the managed cluster.
 func (s *Service) Delete(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "managedclusters.Service.Delete")
 	defer done()
 
-	klog.V(2).Infof("Deleting managed cluster  %s ", s.Scope.ClusterName())
-	err := s.Client.Delete(ctx, s.Scope.ResourceGroup(), s.Scope.ClusterName())
+	existingMC, err := s.Client.Get(ctx, s.Scope.ResourceGroup(), s.Scope.ClusterName())
 	if err != nil {
+		if azure.ResourceNotFound(err) {
+			// already deleted
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get managed cluster %s in resource group %s", s.Scope.ClusterName(), s.Scope.ResourceGroup())
+	}
+
+	// Never delete an existing managed cluster that CAPZ neither created nor adopted.
+	if !converters.MapToTags(existingMC.Tags).HasOwned(s.Scope.ClusterName()) && !s.Scope.ManagedClusterAdoptionConfirmed() {
+		klog.V(2).Infof("Skipping deletion of managed cluster %s, which is not managed by CAPZ", s.Scope.ClusterName())
+		return nil
+	}
+
+	klog.V(2).Infof("Deleting managed cluster  %s ", s.Scope.ClusterName())
+	if err := s.Client.Delete(ctx, s.Scope.ResourceGroup(), s.Scope.ClusterName()); err != nil {
 		if azure.ResourceNotFound(err) {
 			// already deleted
 			return nil
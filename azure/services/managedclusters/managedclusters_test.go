@@ -19,16 +19,21 @@ package managedclusters
 import (
 	"context"
 	"net/http"
+	"reflect"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
+	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-08-01/containerservice"
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	"k8s.io/utils/pointer"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managedclusters/mock_managedclusters"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
 func TestReconcile(t *testing.T) {
@@ -47,7 +52,7 @@ func TestReconcile(t *testing.T) {
 					Fqdn:              pointer.String("my-managedcluster-fqdn"),
 					ProvisioningState: &provisioningstate,
 				}}, nil)
-				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{Tags: ownedTags(), ManagedClusterProperties: &containerservice.ManagedClusterProperties{
 					Fqdn:              pointer.String("my-managedcluster-fqdn"),
 					ProvisioningState: &provisioningstate,
 					NetworkProfile:    &containerservice.NetworkProfile{},
@@ -55,7 +60,7 @@ func TestReconcile(t *testing.T) {
 				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
 				s.ClusterName().AnyTimes().Return("my-managedcluster")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
-				s.ManagedClusterSpec().AnyTimes().Return(azure.ManagedClusterSpec{
+				s.ManagedClusterSpec(gomockinternal.AContext()).AnyTimes().Return(azure.ManagedClusterSpec{
 					Name:              "my-managedcluster",
 					ResourceGroupName: "my-rg",
 				}, nil)
@@ -68,12 +73,12 @@ func TestReconcile(t *testing.T) {
 			provisioningStatesToTest: []string{"Deleting", "InProgress", "randomStringHere"},
 			expectedError:            "Unable to update existing managed cluster in non terminal state. Managed cluster must be in one of the following provisioning states: canceled, failed, or succeeded. Actual state",
 			expect: func(m *mock_managedclusters.MockClientMockRecorder, provisioningstate string, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
-				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{Tags: ownedTags(), ManagedClusterProperties: &containerservice.ManagedClusterProperties{
 					ProvisioningState: &provisioningstate,
 				}}, nil)
 				s.ClusterName().AnyTimes().Return("my-managedcluster")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
-				s.ManagedClusterSpec().AnyTimes().Return(azure.ManagedClusterSpec{
+				s.ManagedClusterSpec(gomockinternal.AContext()).AnyTimes().Return(azure.ManagedClusterSpec{
 					Name:              "my-managedcluster",
 					ResourceGroupName: "my-rg",
 				}, nil)
@@ -93,6 +98,8 @@ func TestReconcile(t *testing.T) {
 				clientMock := mock_managedclusters.NewMockClient(mockCtrl)
 
 				tc.expect(clientMock.EXPECT(), provisioningstate, scopeMock.EXPECT())
+				scopeMock.EXPECT().SetControlPlaneVersion(gomock.Any()).AnyTimes()
+				scopeMock.EXPECT().SetUpgradeCondition(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 
 				s := &Service{
 					Scope:  scopeMock,
@@ -125,7 +132,7 @@ func TestReconcile(t *testing.T) {
 				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
 				s.ClusterName().AnyTimes().Return("my-managedcluster")
 				s.ResourceGroup().AnyTimes().Return("my-rg")
-				s.ManagedClusterSpec().AnyTimes().Return(azure.ManagedClusterSpec{
+				s.ManagedClusterSpec(gomockinternal.AContext()).AnyTimes().Return(azure.ManagedClusterSpec{
 					Name:              "my-managedcluster",
 					ResourceGroupName: "my-rg",
 				}, nil)
@@ -140,6 +147,579 @@ func TestReconcile(t *testing.T) {
 				s.SetKubeConfigData(gomock.Any()).Times(1)
 			},
 		},
+		{
+			name:          "no managedcluster exists with a custom network profile",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-managedcluster", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _, _ string, mc containerservice.ManagedCluster) (containerservice.ManagedCluster, error) {
+						np := mc.NetworkProfile
+						if np.OutboundType != containerservice.OutboundTypeUserDefinedRouting {
+							return containerservice.ManagedCluster{}, errors.New("expected the userDefinedRouting outbound type")
+						}
+						if to.String(np.PodCidr) != "10.244.0.0/16" || to.String(np.ServiceCidr) != "10.0.0.0/16" || to.String(np.DNSServiceIP) != "10.0.0.53" {
+							return containerservice.ManagedCluster{}, errors.New("expected the pod CIDR, service CIDR and DNS service IP to be set")
+						}
+						return containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{}}, nil
+					})
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found"))
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec(gomockinternal.AContext()).AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:              "my-managedcluster",
+					ResourceGroupName: "my-rg",
+					OutboundType:      "userDefinedRouting",
+					PodCIDR:           "10.244.0.0/16",
+					ServiceCIDR:       "10.0.0.0/16",
+					DNSServiceIP:      to.StringPtr("10.0.0.53"),
+				}, nil)
+				s.GetAllAgentPoolSpecs(gomockinternal.AContext()).AnyTimes().Return([]azure.AgentPoolSpec{}, nil)
+				s.SetKubeConfigData(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name:          "no managedcluster exists with a Windows agent pool",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-managedcluster", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _, _ string, mc containerservice.ManagedCluster) (containerservice.ManagedCluster, error) {
+						if mc.WindowsProfile == nil || to.String(mc.WindowsProfile.AdminUsername) != "azureuser" || to.String(mc.WindowsProfile.AdminPassword) != "P@ssw0rd1234!" {
+							return containerservice.ManagedCluster{}, errors.New("expected Windows profile to be set")
+						}
+						if (*mc.AgentPoolProfiles)[1].OsType != containerservice.OSTypeWindows {
+							return containerservice.ManagedCluster{}, errors.New("expected Windows agent pool profile")
+						}
+						return containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{}}, nil
+					})
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found"))
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec(gomockinternal.AContext()).AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:              "my-managedcluster",
+					ResourceGroupName: "my-rg",
+					WindowsProfile: &azure.WindowsProfile{
+						AdminUsername: "azureuser",
+						AdminPassword: "P@ssw0rd1234!",
+					},
+				}, nil)
+				s.GetAllAgentPoolSpecs(gomockinternal.AContext()).AnyTimes().Return([]azure.AgentPoolSpec{
+					{
+						Name:     "my-agentpool",
+						SKU:      "Standard_D4s_v3",
+						Replicas: 1,
+						Mode:     "System",
+					},
+					{
+						Name:     "win1",
+						SKU:      "Standard_D4s_v3",
+						Replicas: 1,
+						Mode:     "User",
+						OSType:   to.StringPtr(azure.WindowsOS),
+					},
+				}, nil)
+				s.SetWindowsAdminPasswordHash("3c06e3ed892fd7aea4f45333e30174161009b247afc12978829dded01ea66ebe")
+				s.SetKubeConfigData(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name:          "no managedcluster exists with add-ons",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-managedcluster", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _, _ string, mc containerservice.ManagedCluster) (containerservice.ManagedCluster, error) {
+						expected := map[string]*containerservice.ManagedClusterAddonProfile{
+							"azurepolicy": {
+								Enabled: to.BoolPtr(true),
+							},
+							"omsagent": {
+								Enabled: to.BoolPtr(true),
+								Config: map[string]*string{
+									"logAnalyticsWorkspaceResourceID": to.StringPtr("/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.OperationalInsights/workspaces/my-workspace"),
+								},
+							},
+						}
+						if !reflect.DeepEqual(mc.AddonProfiles, expected) {
+							return containerservice.ManagedCluster{}, errors.Errorf("unexpected add-on profiles %v", mc.AddonProfiles)
+						}
+						return containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{}}, nil
+					})
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found"))
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec(gomockinternal.AContext()).AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:              "my-managedcluster",
+					ResourceGroupName: "my-rg",
+					AddonProfiles: []azure.AddonProfile{
+						{
+							Name:    "azurepolicy",
+							Enabled: true,
+							Config:  map[string]string{},
+						},
+						{
+							Name:    "omsagent",
+							Enabled: true,
+							Config: map[string]string{
+								"logAnalyticsWorkspaceResourceID": "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.OperationalInsights/workspaces/my-workspace",
+							},
+						},
+					},
+				}, nil)
+				s.GetAllAgentPoolSpecs(gomockinternal.AContext()).AnyTimes().Return([]azure.AgentPoolSpec{
+					{
+						Name:     "my-agentpool",
+						SKU:      "Standard_D4s_v3",
+						Replicas: 1,
+						Mode:     "System",
+					},
+				}, nil)
+				s.SetKubeConfigData(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name:          "no managedcluster exists with user-assigned control plane and kubelet identities",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-managedcluster", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _, _ string, mc containerservice.ManagedCluster) (containerservice.ManagedCluster, error) {
+						if mc.Identity == nil || mc.Identity.Type != containerservice.ResourceIdentityTypeUserAssigned {
+							return containerservice.ManagedCluster{}, errors.Errorf("unexpected identity %v", mc.Identity)
+						}
+						if _, ok := mc.Identity.UserAssignedIdentities["/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-control-plane"]; !ok {
+							return containerservice.ManagedCluster{}, errors.Errorf("unexpected user-assigned identities %v", mc.Identity.UserAssignedIdentities)
+						}
+						kubeletIdentity := mc.IdentityProfile[azure.KubeletIdentityKey]
+						if kubeletIdentity == nil || to.String(kubeletIdentity.ResourceID) != "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-kubelet" {
+							return containerservice.ManagedCluster{}, errors.Errorf("unexpected identity profile %v", mc.IdentityProfile)
+						}
+						return containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{}}, nil
+					})
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found"))
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec(gomockinternal.AContext()).AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:                        "my-managedcluster",
+					ResourceGroupName:           "my-rg",
+					UserAssignedIdentity:        "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-control-plane",
+					KubeletUserAssignedIdentity: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/my-kubelet",
+				}, nil)
+				s.GetAllAgentPoolSpecs(gomockinternal.AContext()).AnyTimes().Return([]azure.AgentPoolSpec{
+					{
+						Name:     "my-agentpool",
+						SKU:      "Standard_D4s_v3",
+						Replicas: 1,
+						Mode:     "System",
+					},
+				}, nil)
+				s.SetKubeConfigData(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name:          "no update needed when the add-ons match",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(existingManagedClusterWithAddons(), nil)
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec(gomockinternal.AContext()).AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:              "my-managedcluster",
+					ResourceGroupName: "my-rg",
+					Version:           "1.22.4",
+					AddonProfiles: []azure.AddonProfile{
+						{
+							Name:    "azureKeyvaultSecretsProvider",
+							Enabled: true,
+							Config: map[string]string{
+								"enableSecretRotation": "true",
+							},
+						},
+						{
+							Name:    "httpApplicationRouting",
+							Enabled: false,
+						},
+					},
+				}, nil)
+				s.GetAllAgentPoolSpecs(gomockinternal.AContext()).AnyTimes().Return([]azure.AgentPoolSpec{}, nil)
+				s.SetKubeConfigData(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name:          "update managedcluster when an add-on is enabled",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(existingManagedClusterWithAddons(), nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-managedcluster", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _, _ string, mc containerservice.ManagedCluster) (containerservice.ManagedCluster, error) {
+						if !to.Bool(mc.AddonProfiles["azurepolicy"].Enabled) {
+							return containerservice.ManagedCluster{}, errors.New("expected the azurepolicy add-on to be enabled")
+						}
+						// Add-ons that are not configured are kept as they are.
+						if mc.AddonProfiles["azureKeyvaultSecretsProvider"] == nil || !to.Bool(mc.AddonProfiles["azureKeyvaultSecretsProvider"].Enabled) {
+							return containerservice.ManagedCluster{}, errors.New("expected the azureKeyvaultSecretsProvider add-on to be kept")
+						}
+						return containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{}}, nil
+					})
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec(gomockinternal.AContext()).AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:              "my-managedcluster",
+					ResourceGroupName: "my-rg",
+					Version:           "1.22.4",
+					AddonProfiles: []azure.AddonProfile{
+						{
+							Name:    "azurepolicy",
+							Enabled: true,
+						},
+					},
+				}, nil)
+				s.GetAllAgentPoolSpecs(gomockinternal.AContext()).AnyTimes().Return([]azure.AgentPoolSpec{}, nil)
+				s.SetKubeConfigData(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name:          "update managedcluster when an add-on is disabled",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(existingManagedClusterWithAddons(), nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-managedcluster", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _, _ string, mc containerservice.ManagedCluster) (containerservice.ManagedCluster, error) {
+						if to.Bool(mc.AddonProfiles["azureKeyvaultSecretsProvider"].Enabled) {
+							return containerservice.ManagedCluster{}, errors.New("expected the azureKeyvaultSecretsProvider add-on to be disabled")
+						}
+						return containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{}}, nil
+					})
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec(gomockinternal.AContext()).AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:              "my-managedcluster",
+					ResourceGroupName: "my-rg",
+					Version:           "1.22.4",
+					AddonProfiles: []azure.AddonProfile{
+						{
+							Name:    "azureKeyvaultSecretsProvider",
+							Enabled: false,
+							Config: map[string]string{
+								"enableSecretRotation": "true",
+							},
+						},
+					},
+				}, nil)
+				s.GetAllAgentPoolSpecs(gomockinternal.AContext()).AnyTimes().Return([]azure.AgentPoolSpec{}, nil)
+				s.SetKubeConfigData(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name:          "no update needed when the autoscaler profile and auto-upgrade channel match",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(existingManagedClusterWithAutoScalerProfile(), nil)
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec(gomockinternal.AContext()).AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:              "my-managedcluster",
+					ResourceGroupName: "my-rg",
+					Version:           "1.22.4",
+					AutoScalerProfile: &azure.AutoScalerProfile{
+						Expander:     to.StringPtr("random"),
+						ScanInterval: to.StringPtr("10s"),
+					},
+					AutoUpgradeChannel: to.StringPtr("none"),
+				}, nil)
+				s.GetAllAgentPoolSpecs(gomockinternal.AContext()).AnyTimes().Return([]azure.AgentPoolSpec{}, nil)
+				s.SetKubeConfigData(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name:          "no update needed when the Windows admin password was applied",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				existing := existingManagedClusterWithAutoScalerProfile()
+				existing.WindowsProfile = &containerservice.ManagedClusterWindowsProfile{AdminUsername: to.StringPtr("azureuser")}
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(existing, nil)
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec(gomockinternal.AContext()).AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:              "my-managedcluster",
+					ResourceGroupName: "my-rg",
+					Version:           "1.22.4",
+					AutoScalerProfile: &azure.AutoScalerProfile{
+						Expander:     to.StringPtr("random"),
+						ScanInterval: to.StringPtr("10s"),
+					},
+					AutoUpgradeChannel: to.StringPtr("none"),
+					WindowsProfile: &azure.WindowsProfile{
+						AdminUsername: "azureuser",
+						AdminPassword: "P@ssw0rd1234!",
+					},
+				}, nil)
+				s.WindowsAdminPasswordHash().Return("3c06e3ed892fd7aea4f45333e30174161009b247afc12978829dded01ea66ebe")
+				s.GetAllAgentPoolSpecs(gomockinternal.AContext()).AnyTimes().Return([]azure.AgentPoolSpec{}, nil)
+				s.SetKubeConfigData(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name:          "update managedcluster when the Windows admin password is rotated",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				existing := existingManagedClusterWithAutoScalerProfile()
+				existing.WindowsProfile = &containerservice.ManagedClusterWindowsProfile{AdminUsername: to.StringPtr("azureuser")}
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(existing, nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-managedcluster", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _, _ string, mc containerservice.ManagedCluster) (containerservice.ManagedCluster, error) {
+						if mc.WindowsProfile == nil || to.String(mc.WindowsProfile.AdminPassword) != "N3wP@ssw0rd!" {
+							return containerservice.ManagedCluster{}, errors.New("expected the rotated Windows admin password")
+						}
+						return containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{}}, nil
+					})
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec(gomockinternal.AContext()).AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:              "my-managedcluster",
+					ResourceGroupName: "my-rg",
+					Version:           "1.22.4",
+					AutoScalerProfile: &azure.AutoScalerProfile{
+						Expander:     to.StringPtr("random"),
+						ScanInterval: to.StringPtr("10s"),
+					},
+					AutoUpgradeChannel: to.StringPtr("none"),
+					WindowsProfile: &azure.WindowsProfile{
+						AdminUsername: "azureuser",
+						AdminPassword: "N3wP@ssw0rd!",
+					},
+				}, nil)
+				s.WindowsAdminPasswordHash().Return("3c06e3ed892fd7aea4f45333e30174161009b247afc12978829dded01ea66ebe")
+				s.SetWindowsAdminPasswordHash("6105911c1fc106a6d9389fadcc7163bd196dad7d28a68b62ad6a074575bb58d4")
+				s.GetAllAgentPoolSpecs(gomockinternal.AContext()).AnyTimes().Return([]azure.AgentPoolSpec{}, nil)
+				s.SetKubeConfigData(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name:          "no update needed when the Windows profile of an existing managedcluster is not set",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				existing := existingManagedClusterWithAutoScalerProfile()
+				existing.WindowsProfile = &containerservice.ManagedClusterWindowsProfile{AdminUsername: to.StringPtr("azureuser")}
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(existing, nil)
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec(gomockinternal.AContext()).AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:              "my-managedcluster",
+					ResourceGroupName: "my-rg",
+					Version:           "1.22.4",
+					AutoScalerProfile: &azure.AutoScalerProfile{
+						Expander:     to.StringPtr("random"),
+						ScanInterval: to.StringPtr("10s"),
+					},
+					AutoUpgradeChannel: to.StringPtr("none"),
+				}, nil)
+				s.WindowsAdminPasswordHash().AnyTimes()
+				s.GetAllAgentPoolSpecs(gomockinternal.AContext()).AnyTimes().Return([]azure.AgentPoolSpec{}, nil)
+				s.SetKubeConfigData(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name:          "update managedcluster when an autoscaler parameter changes",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(existingManagedClusterWithAutoScalerProfile(), nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-managedcluster", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _, _ string, mc containerservice.ManagedCluster) (containerservice.ManagedCluster, error) {
+						if mc.AutoScalerProfile == nil || to.String(mc.AutoScalerProfile.ScanInterval) != "20s" {
+							return containerservice.ManagedCluster{}, errors.New("expected the autoscaler scan interval to be 20s")
+						}
+						return containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{}}, nil
+					})
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec(gomockinternal.AContext()).AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:              "my-managedcluster",
+					ResourceGroupName: "my-rg",
+					Version:           "1.22.4",
+					AutoScalerProfile: &azure.AutoScalerProfile{
+						ScanInterval: to.StringPtr("20s"),
+					},
+				}, nil)
+				s.GetAllAgentPoolSpecs(gomockinternal.AContext()).AnyTimes().Return([]azure.AgentPoolSpec{}, nil)
+				s.SetKubeConfigData(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name:          "update managedcluster when the auto-upgrade channel changes",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(existingManagedClusterWithAutoScalerProfile(), nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-managedcluster", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _, _ string, mc containerservice.ManagedCluster) (containerservice.ManagedCluster, error) {
+						if mc.AutoUpgradeProfile == nil || mc.AutoUpgradeProfile.UpgradeChannel != containerservice.UpgradeChannelPatch {
+							return containerservice.ManagedCluster{}, errors.New("expected the auto-upgrade channel to be patch")
+						}
+						return containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{}}, nil
+					})
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec(gomockinternal.AContext()).AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:               "my-managedcluster",
+					ResourceGroupName:  "my-rg",
+					Version:            "1.22.4",
+					AutoUpgradeChannel: to.StringPtr("patch"),
+				}, nil)
+				s.GetAllAgentPoolSpecs(gomockinternal.AContext()).AnyTimes().Return([]azure.AgentPoolSpec{}, nil)
+				s.SetKubeConfigData(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name:          "keep the version of a managedcluster upgraded by its auto-upgrade channel",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(existingManagedClusterWithAutoScalerProfile(), nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-managedcluster", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _, _ string, mc containerservice.ManagedCluster) (containerservice.ManagedCluster, error) {
+						if to.String(mc.KubernetesVersion) != "1.22.4" {
+							return containerservice.ManagedCluster{}, errors.New("expected the Kubernetes version to be kept at 1.22.4")
+						}
+						return containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{}}, nil
+					})
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec(gomockinternal.AContext()).AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:               "my-managedcluster",
+					ResourceGroupName:  "my-rg",
+					Version:            "1.21.2",
+					AutoUpgradeChannel: to.StringPtr("stable"),
+				}, nil)
+				s.GetAllAgentPoolSpecs(gomockinternal.AContext()).AnyTimes().Return([]azure.AgentPoolSpec{}, nil)
+				s.SetControlPlaneVersion("1.22.4")
+				s.SetUpgradeCondition(infrav1.ManagedClusterUpgradedCondition, "", clusterv1.ConditionSeverity(""), "")
+				s.SetKubeConfigData(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name:          "upgrade managedcluster when the version is an available upgrade",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(existingManagedClusterWithAutoScalerProfile(), nil)
+				m.GetUpgradeProfile(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(upgradeProfileWithUpgrades("1.22.6", "1.23.3"), nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-managedcluster", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _, _ string, mc containerservice.ManagedCluster) (containerservice.ManagedCluster, error) {
+						if to.String(mc.KubernetesVersion) != "1.23.3" {
+							return containerservice.ManagedCluster{}, errors.New("expected the Kubernetes version to be 1.23.3")
+						}
+						return containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{}}, nil
+					})
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec(gomockinternal.AContext()).AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:              "my-managedcluster",
+					ResourceGroupName: "my-rg",
+					Version:           "1.23.3",
+				}, nil)
+				s.GetAllAgentPoolSpecs(gomockinternal.AContext()).AnyTimes().Return([]azure.AgentPoolSpec{}, nil)
+				s.SetControlPlaneVersion("1.22.4")
+				s.SetUpgradeCondition(infrav1.ManagedClusterUpgradedCondition, infrav1.ManagedClusterUpgradingReason, clusterv1.ConditionSeverityInfo, gomock.Any())
+				s.SetKubeConfigData(gomock.Any()).Times(1)
+			},
+		},
+		{
+			name:          "fail to upgrade managedcluster when the version is not an available upgrade",
+			expectedError: "Kubernetes version 1.24.0 is not an available upgrade of the control plane version 1.22.4, available upgrades: [1.22.6, 1.23.3]",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(existingManagedClusterWithAutoScalerProfile(), nil)
+				m.GetUpgradeProfile(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(upgradeProfileWithUpgrades("1.22.6", "1.23.3"), nil)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec(gomockinternal.AContext()).AnyTimes().Return(azure.ManagedClusterSpec{
+					Name:              "my-managedcluster",
+					ResourceGroupName: "my-rg",
+					Version:           "1.24.0",
+				}, nil)
+				s.GetAllAgentPoolSpecs(gomockinternal.AContext()).AnyTimes().Return([]azure.AgentPoolSpec{}, nil)
+				s.SetControlPlaneVersion("1.22.4")
+				s.SetUpgradeCondition(infrav1.ManagedClusterUpgradedCondition, infrav1.ManagedClusterUpgradeUnavailableReason, clusterv1.ConditionSeverityWarning, gomock.Any())
+			},
+		},
+		{
+			name:          "wait for the adoption of an existing managedcluster to be confirmed",
+			expectedError: "existing managed cluster my-managedcluster matches its spec but was not created by CAPZ, set the azuremanagedcontrolplane.infrastructure.cluster.x-k8s.io/adopt annotation to \"true\" to adopt it. Object will be requeued after 20s",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(unmanagedManagedCluster(), nil)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec(gomockinternal.AContext()).AnyTimes().Return(adoptedManagedClusterSpec(), nil)
+				s.GetAllAgentPoolSpecs(gomockinternal.AContext()).AnyTimes().Return([]azure.AgentPoolSpec{{Name: "pool0", SKU: "Standard_D2s_v3", OSDiskSizeGB: 128}}, nil)
+				s.ManagedClusterAdoptionConfirmed().Return(false)
+				s.SetAdoptionCondition(infrav1.ManagedClusterAdoptionPendingReason, clusterv1.ConditionSeverityInfo, gomock.Any())
+			},
+		},
+		{
+			name:          "fail to adopt an existing managedcluster in a virtual network managed by AKS",
+			expectedError: `existing managed cluster my-managedcluster does not match its spec: subnet of agent pool pool0 is "" instead of "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet"`,
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(unmanagedManagedCluster(), nil)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec(gomockinternal.AContext()).AnyTimes().Return(adoptedManagedClusterSpec(), nil)
+				s.GetAllAgentPoolSpecs(gomockinternal.AContext()).AnyTimes().Return([]azure.AgentPoolSpec{{
+					Name:         "pool0",
+					SKU:          "Standard_D2s_v3",
+					OSDiskSizeGB: 128,
+					VnetSubnetID: "/subscriptions/123/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet",
+				}}, nil)
+				s.SetAdoptionCondition(infrav1.ManagedClusterAdoptionMismatchReason, clusterv1.ConditionSeverityWarning, gomock.Any())
+			},
+		},
+		{
+			name:          "fail to adopt an existing managedcluster that does not match its spec",
+			expectedError: `existing managed cluster my-managedcluster does not match its spec: network plugin is "kubenet" instead of "azure"; SKU of agent pool pool0 is "Standard_D2s_v3" instead of "Standard_D4s_v3"`,
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(unmanagedManagedCluster(), nil)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				spec := adoptedManagedClusterSpec()
+				spec.NetworkPlugin = "azure"
+				s.ManagedClusterSpec(gomockinternal.AContext()).AnyTimes().Return(spec, nil)
+				s.GetAllAgentPoolSpecs(gomockinternal.AContext()).AnyTimes().Return([]azure.AgentPoolSpec{{Name: "pool0", SKU: "Standard_D4s_v3", OSDiskSizeGB: 128}}, nil)
+				s.SetAdoptionCondition(infrav1.ManagedClusterAdoptionMismatchReason, clusterv1.ConditionSeverityWarning, gomock.Any())
+			},
+		},
+		{
+			name:          "adopt an existing managedcluster once confirmed",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(unmanagedManagedCluster(), nil)
+				m.CreateOrUpdate(gomockinternal.AContext(), "my-rg", "my-managedcluster", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _, _ string, mc containerservice.ManagedCluster) (containerservice.ManagedCluster, error) {
+						if to.String(mc.Tags["sigs.k8s.io_cluster-api-provider-azure_cluster_my-managedcluster"]) != "owned" || to.String(mc.Tags["team"]) != "payments" {
+							return containerservice.ManagedCluster{}, errors.Errorf("expected the ownership tag to be added to the existing tags, got %v", mc.Tags)
+						}
+						if to.String(mc.DNSPrefix) != "payments-dns" {
+							return containerservice.ManagedCluster{}, errors.New("expected the existing DNS prefix to be kept")
+						}
+						return containerservice.ManagedCluster{ManagedClusterProperties: &containerservice.ManagedClusterProperties{}}, nil
+					})
+				m.GetCredentials(gomockinternal.AContext(), "my-rg", "my-managedcluster").Times(1)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterSpec(gomockinternal.AContext()).AnyTimes().Return(adoptedManagedClusterSpec(), nil)
+				s.GetAllAgentPoolSpecs(gomockinternal.AContext()).AnyTimes().Return([]azure.AgentPoolSpec{{Name: "pool0", SKU: "Standard_D2s_v3", OSDiskSizeGB: 128}}, nil)
+				s.ManagedClusterAdoptionConfirmed().Return(true)
+				s.SetAdoptionCondition("", clusterv1.ConditionSeverity(""), "")
+				s.SetKubeConfigData(gomock.Any()).Times(1)
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -153,6 +733,8 @@ func TestReconcile(t *testing.T) {
 			clientMock := mock_managedclusters.NewMockClient(mockCtrl)
 
 			tc.expect(clientMock.EXPECT(), scopeMock.EXPECT())
+			scopeMock.EXPECT().SetControlPlaneVersion(gomock.Any()).AnyTimes()
+			scopeMock.EXPECT().SetUpgradeCondition(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
 
 			s := &Service{
 				Scope:  scopeMock,
@@ -169,3 +751,309 @@ func TestReconcile(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileAdoption(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder)
+	}{
+		{
+			name:          "no managedcluster exists",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found"))
+				s.ManagedClusterSpec(gomockinternal.AContext()).AnyTimes().Return(adoptedManagedClusterSpec(), nil)
+			},
+		},
+		{
+			name:          "managedcluster created by CAPZ",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(existingManagedClusterWithAddons(), nil)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ManagedClusterSpec(gomockinternal.AContext()).AnyTimes().Return(adoptedManagedClusterSpec(), nil)
+			},
+		},
+		{
+			name:          "wait for the adoption of an existing managedcluster to be confirmed",
+			expectedError: "existing managed cluster my-managedcluster matches its spec but was not created by CAPZ, set the azuremanagedcontrolplane.infrastructure.cluster.x-k8s.io/adopt annotation to \"true\" to adopt it. Object will be requeued after 20s",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(unmanagedManagedCluster(), nil)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ManagedClusterSpec(gomockinternal.AContext()).AnyTimes().Return(adoptedManagedClusterSpec(), nil)
+				s.GetAllAgentPoolSpecs(gomockinternal.AContext()).AnyTimes().Return([]azure.AgentPoolSpec{{Name: "pool0", SKU: "Standard_D2s_v3", OSDiskSizeGB: 128}}, nil)
+				s.ManagedClusterAdoptionConfirmed().Return(false)
+				s.SetAdoptionCondition(infrav1.ManagedClusterAdoptionPendingReason, clusterv1.ConditionSeverityInfo, gomock.Any())
+			},
+		},
+		{
+			name:          "adopt an existing managedcluster whose adoption was confirmed",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(unmanagedManagedCluster(), nil)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ManagedClusterSpec(gomockinternal.AContext()).AnyTimes().Return(adoptedManagedClusterSpec(), nil)
+				s.GetAllAgentPoolSpecs(gomockinternal.AContext()).AnyTimes().Return([]azure.AgentPoolSpec{{Name: "pool0", SKU: "Standard_D2s_v3", OSDiskSizeGB: 128}}, nil)
+				s.ManagedClusterAdoptionConfirmed().Return(true)
+				s.SetAdoptionCondition("", clusterv1.ConditionSeverity(""), "")
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_managedclusters.NewMockManagedClusterScope(mockCtrl)
+			clientMock := mock_managedclusters.NewMockClient(mockCtrl)
+
+			tc.expect(clientMock.EXPECT(), scopeMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.ReconcileAdoption(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	testcases := []struct {
+		name          string
+		expectedError string
+		expect        func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder)
+	}{
+		{
+			name:          "delete a managedcluster created by CAPZ",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(existingManagedClusterWithAddons(), nil)
+				m.Delete(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(nil)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+			},
+		},
+		{
+			name:          "managedcluster already deleted",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(containerservice.ManagedCluster{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found"))
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+			},
+		},
+		{
+			name:          "skip the deletion of an existing managedcluster whose adoption was not confirmed",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(unmanagedManagedCluster(), nil)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterAdoptionConfirmed().Return(false)
+			},
+		},
+		{
+			name:          "delete an existing managedcluster whose adoption was confirmed",
+			expectedError: "",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(unmanagedManagedCluster(), nil)
+				m.Delete(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(nil)
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.ManagedClusterAdoptionConfirmed().Return(true)
+			},
+		},
+		{
+			name:          "fail to delete a managedcluster",
+			expectedError: "failed to delete managed cluster my-managedcluster in resource group my-rg: #: Internal Server Error: StatusCode=500",
+			expect: func(m *mock_managedclusters.MockClientMockRecorder, s *mock_managedclusters.MockManagedClusterScopeMockRecorder) {
+				m.Get(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(existingManagedClusterWithAddons(), nil)
+				m.Delete(gomockinternal.AContext(), "my-rg", "my-managedcluster").Return(autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+				s.ClusterName().AnyTimes().Return("my-managedcluster")
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_managedclusters.NewMockManagedClusterScope(mockCtrl)
+			clientMock := mock_managedclusters.NewMockClient(mockCtrl)
+
+			tc.expect(clientMock.EXPECT(), scopeMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				Client: clientMock,
+			}
+
+			err := s.Delete(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+// ownedTags returns the tags of a managed cluster created or adopted by CAPZ.
+func ownedTags() map[string]*string {
+	return map[string]*string{
+		"sigs.k8s.io_cluster-api-provider-azure_cluster_my-managedcluster": to.StringPtr("owned"),
+	}
+}
+
+// unmanagedManagedCluster returns a managed cluster as returned by AKS, which was not created by CAPZ.
+func unmanagedManagedCluster() containerservice.ManagedCluster {
+	return containerservice.ManagedCluster{
+		Location: to.StringPtr("eastus"),
+		Tags: map[string]*string{
+			"team": to.StringPtr("payments"),
+		},
+		Identity: &containerservice.ManagedClusterIdentity{
+			Type: containerservice.ResourceIdentityTypeSystemAssigned,
+		},
+		ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+			ProvisioningState: to.StringPtr("Succeeded"),
+			KubernetesVersion: to.StringPtr("1.22.4"),
+			DNSPrefix:         to.StringPtr("payments-dns"),
+			NodeResourceGroup: to.StringPtr("MC_my-rg_my-managedcluster_eastus"),
+			LinuxProfile: &containerservice.LinuxProfile{
+				AdminUsername: to.StringPtr("azureuser"),
+				SSH: &containerservice.SSHConfiguration{
+					PublicKeys: &[]containerservice.SSHPublicKey{{KeyData: to.StringPtr("ssh-rsa AAAAB3NzaC1yc2E\n")}},
+				},
+			},
+			ServicePrincipalProfile: &containerservice.ManagedClusterServicePrincipalProfile{
+				ClientID: to.StringPtr("msi"),
+			},
+			NetworkProfile: &containerservice.NetworkProfile{
+				NetworkPlugin:   containerservice.NetworkPluginKubenet,
+				LoadBalancerSku: containerservice.LoadBalancerSkuStandard,
+				PodCidr:         to.StringPtr("10.244.0.0/16"),
+				ServiceCidr:     to.StringPtr("10.0.0.0/16"),
+				DNSServiceIP:    to.StringPtr("10.0.0.10"),
+			},
+			AgentPoolProfiles: &[]containerservice.ManagedClusterAgentPoolProfile{
+				{
+					Name:         to.StringPtr("pool0"),
+					VMSize:       to.StringPtr("Standard_D2s_v3"),
+					OsDiskSizeGB: to.Int32Ptr(128),
+					OsType:       containerservice.OSTypeLinux,
+				},
+			},
+		},
+	}
+}
+
+// adoptedManagedClusterSpec returns the spec of a managed cluster that matches unmanagedManagedCluster.
+func adoptedManagedClusterSpec() azure.ManagedClusterSpec {
+	return azure.ManagedClusterSpec{
+		Name:                  "my-managedcluster",
+		ResourceGroupName:     "my-rg",
+		NodeResourceGroupName: "MC_my-rg_my-managedcluster_eastus",
+		Location:              "East US",
+		Version:               "1.22.4",
+		NetworkPlugin:         "kubenet",
+		LoadBalancerSKU:       "Standard",
+		SSHPublicKey:          "ssh-rsa AAAAB3NzaC1yc2E",
+		Tags: map[string]string{
+			"sigs.k8s.io_cluster-api-provider-azure_cluster_my-managedcluster": "owned",
+		},
+	}
+}
+
+// existingManagedClusterWithAddons returns a managed cluster as returned by AKS, with the Key Vault secrets provider
+// add-on enabled and its default config populated, and the HTTP application routing add-on disabled.
+func existingManagedClusterWithAddons() containerservice.ManagedCluster {
+	return containerservice.ManagedCluster{
+		Tags: ownedTags(),
+		ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+			ProvisioningState: to.StringPtr("Succeeded"),
+			KubernetesVersion: to.StringPtr("1.22.4"),
+			NetworkProfile:    &containerservice.NetworkProfile{},
+			AddonProfiles: map[string]*containerservice.ManagedClusterAddonProfile{
+				"azureKeyvaultSecretsProvider": {
+					Enabled: to.BoolPtr(true),
+					Config: map[string]*string{
+						"enableSecretRotation": to.StringPtr("true"),
+						"rotationPollInterval": to.StringPtr("2m"),
+					},
+				},
+				"httpApplicationRouting": {
+					Enabled: to.BoolPtr(false),
+				},
+			},
+		},
+	}
+}
+
+// existingManagedClusterWithAutoScalerProfile returns a managed cluster as returned by AKS, with the default cluster
+// autoscaler parameters populated and auto-upgrades disabled.
+// upgradeProfileWithUpgrades returns an upgrade profile as returned by AKS, listing the given Kubernetes versions as
+// the available upgrades of the control plane.
+func upgradeProfileWithUpgrades(versions ...string) containerservice.ManagedClusterUpgradeProfile {
+	upgrades := make([]containerservice.ManagedClusterPoolUpgradeProfileUpgradesItem, 0, len(versions))
+	for _, version := range versions {
+		upgrades = append(upgrades, containerservice.ManagedClusterPoolUpgradeProfileUpgradesItem{
+			KubernetesVersion: to.StringPtr(version),
+		})
+	}
+	return containerservice.ManagedClusterUpgradeProfile{
+		ManagedClusterUpgradeProfileProperties: &containerservice.ManagedClusterUpgradeProfileProperties{
+			ControlPlaneProfile: &containerservice.ManagedClusterPoolUpgradeProfile{
+				KubernetesVersion: to.StringPtr("1.22.4"),
+				Upgrades:          &upgrades,
+			},
+		},
+	}
+}
+
+func existingManagedClusterWithAutoScalerProfile() containerservice.ManagedCluster {
+	return containerservice.ManagedCluster{
+		Tags: ownedTags(),
+		ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+			ProvisioningState: to.StringPtr("Succeeded"),
+			KubernetesVersion: to.StringPtr("1.22.4"),
+			NetworkProfile:    &containerservice.NetworkProfile{},
+			AutoScalerProfile: &containerservice.ManagedClusterPropertiesAutoScalerProfile{
+				BalanceSimilarNodeGroups:      to.StringPtr("false"),
+				Expander:                      containerservice.ExpanderRandom,
+				MaxEmptyBulkDelete:            to.StringPtr("10"),
+				MaxGracefulTerminationSec:     to.StringPtr("600"),
+				MaxNodeProvisionTime:          to.StringPtr("15m"),
+				MaxTotalUnreadyPercentage:     to.StringPtr("45"),
+				NewPodScaleUpDelay:            to.StringPtr("0s"),
+				OkTotalUnreadyCount:           to.StringPtr("3"),
+				ScanInterval:                  to.StringPtr("10s"),
+				ScaleDownDelayAfterAdd:        to.StringPtr("10m"),
+				ScaleDownDelayAfterDelete:     to.StringPtr("10s"),
+				ScaleDownDelayAfterFailure:    to.StringPtr("3m"),
+				ScaleDownUnneededTime:         to.StringPtr("10m"),
+				ScaleDownUnreadyTime:          to.StringPtr("20m"),
+				ScaleDownUtilizationThreshold: to.StringPtr("0.5"),
+				SkipNodesWithLocalStorage:     to.StringPtr("false"),
+				SkipNodesWithSystemPods:       to.StringPtr("true"),
+			},
+		},
+	}
+}
@@ -24,7 +24,7 @@ import (
 	context "context"
 	reflect "reflect"
 
-	containerservice "github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
+	containerservice "github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-08-01/containerservice"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -109,3 +109,18 @@ func (mr *MockClientMockRecorder) GetCredentials(arg0, arg1, arg2 interface{}) *
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCredentials", reflect.TypeOf((*MockClient)(nil).GetCredentials), arg0, arg1, arg2)
 }
+
+// GetUpgradeProfile mocks base method.
+func (m *MockClient) GetUpgradeProfile(arg0 context.Context, arg1, arg2 string) (containerservice.ManagedClusterUpgradeProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUpgradeProfile", arg0, arg1, arg2)
+	ret0, _ := ret[0].(containerservice.ManagedClusterUpgradeProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUpgradeProfile indicates an expected call of GetUpgradeProfile.
+func (mr *MockClientMockRecorder) GetUpgradeProfile(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUpgradeProfile", reflect.TypeOf((*MockClient)(nil).GetUpgradeProfile), arg0, arg1, arg2)
+}
@@ -266,19 +266,33 @@ func (mr *MockManagedClusterScopeMockRecorder) MakeEmptyKubeConfigSecret() *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MakeEmptyKubeConfigSecret", reflect.TypeOf((*MockManagedClusterScope)(nil).MakeEmptyKubeConfigSecret))
 }
 
+// ManagedClusterAdoptionConfirmed mocks base method.
+func (m *MockManagedClusterScope) ManagedClusterAdoptionConfirmed() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ManagedClusterAdoptionConfirmed")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ManagedClusterAdoptionConfirmed indicates an expected call of ManagedClusterAdoptionConfirmed.
+func (mr *MockManagedClusterScopeMockRecorder) ManagedClusterAdoptionConfirmed() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ManagedClusterAdoptionConfirmed", reflect.TypeOf((*MockManagedClusterScope)(nil).ManagedClusterAdoptionConfirmed))
+}
+
 // ManagedClusterSpec mocks base method.
-func (m *MockManagedClusterScope) ManagedClusterSpec() (azure.ManagedClusterSpec, error) {
+func (m *MockManagedClusterScope) ManagedClusterSpec(ctx context.Context) (azure.ManagedClusterSpec, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ManagedClusterSpec")
+	ret := m.ctrl.Call(m, "ManagedClusterSpec", ctx)
 	ret0, _ := ret[0].(azure.ManagedClusterSpec)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ManagedClusterSpec indicates an expected call of ManagedClusterSpec.
-func (mr *MockManagedClusterScopeMockRecorder) ManagedClusterSpec() *gomock.Call {
+func (mr *MockManagedClusterScopeMockRecorder) ManagedClusterSpec(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ManagedClusterSpec", reflect.TypeOf((*MockManagedClusterScope)(nil).ManagedClusterSpec))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ManagedClusterSpec", reflect.TypeOf((*MockManagedClusterScope)(nil).ManagedClusterSpec), ctx)
 }
 
 // ResourceGroup mocks base method.
@@ -295,6 +309,18 @@ func (mr *MockManagedClusterScopeMockRecorder) ResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockManagedClusterScope)(nil).ResourceGroup))
 }
 
+// SetAdoptionCondition mocks base method.
+func (m *MockManagedClusterScope) SetAdoptionCondition(arg0 string, arg1 v1beta10.ConditionSeverity, arg2 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetAdoptionCondition", arg0, arg1, arg2)
+}
+
+// SetAdoptionCondition indicates an expected call of SetAdoptionCondition.
+func (mr *MockManagedClusterScopeMockRecorder) SetAdoptionCondition(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAdoptionCondition", reflect.TypeOf((*MockManagedClusterScope)(nil).SetAdoptionCondition), arg0, arg1, arg2)
+}
+
 // SetControlPlaneEndpoint mocks base method.
 func (m *MockManagedClusterScope) SetControlPlaneEndpoint(arg0 v1beta10.APIEndpoint) {
 	m.ctrl.T.Helper()
@@ -307,6 +333,18 @@ func (mr *MockManagedClusterScopeMockRecorder) SetControlPlaneEndpoint(arg0 inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetControlPlaneEndpoint", reflect.TypeOf((*MockManagedClusterScope)(nil).SetControlPlaneEndpoint), arg0)
 }
 
+// SetControlPlaneVersion mocks base method.
+func (m *MockManagedClusterScope) SetControlPlaneVersion(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetControlPlaneVersion", arg0)
+}
+
+// SetControlPlaneVersion indicates an expected call of SetControlPlaneVersion.
+func (mr *MockManagedClusterScopeMockRecorder) SetControlPlaneVersion(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetControlPlaneVersion", reflect.TypeOf((*MockManagedClusterScope)(nil).SetControlPlaneVersion), arg0)
+}
+
 // SetKubeConfigData mocks base method.
 func (m *MockManagedClusterScope) SetKubeConfigData(arg0 []byte) {
 	m.ctrl.T.Helper()
@@ -319,6 +357,30 @@ func (mr *MockManagedClusterScopeMockRecorder) SetKubeConfigData(arg0 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetKubeConfigData", reflect.TypeOf((*MockManagedClusterScope)(nil).SetKubeConfigData), arg0)
 }
 
+// SetUpgradeCondition mocks base method.
+func (m *MockManagedClusterScope) SetUpgradeCondition(arg0 v1beta10.ConditionType, arg1 string, arg2 v1beta10.ConditionSeverity, arg3 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetUpgradeCondition", arg0, arg1, arg2, arg3)
+}
+
+// SetUpgradeCondition indicates an expected call of SetUpgradeCondition.
+func (mr *MockManagedClusterScopeMockRecorder) SetUpgradeCondition(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUpgradeCondition", reflect.TypeOf((*MockManagedClusterScope)(nil).SetUpgradeCondition), arg0, arg1, arg2, arg3)
+}
+
+// SetWindowsAdminPasswordHash mocks base method.
+func (m *MockManagedClusterScope) SetWindowsAdminPasswordHash(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetWindowsAdminPasswordHash", arg0)
+}
+
+// SetWindowsAdminPasswordHash indicates an expected call of SetWindowsAdminPasswordHash.
+func (mr *MockManagedClusterScopeMockRecorder) SetWindowsAdminPasswordHash(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWindowsAdminPasswordHash", reflect.TypeOf((*MockManagedClusterScope)(nil).SetWindowsAdminPasswordHash), arg0)
+}
+
 // SubscriptionID mocks base method.
 func (m *MockManagedClusterScope) SubscriptionID() string {
 	m.ctrl.T.Helper()
@@ -346,3 +408,17 @@ func (mr *MockManagedClusterScopeMockRecorder) TenantID() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockManagedClusterScope)(nil).TenantID))
 }
+
+// WindowsAdminPasswordHash mocks base method.
+func (m *MockManagedClusterScope) WindowsAdminPasswordHash() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WindowsAdminPasswordHash")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// WindowsAdminPasswordHash indicates an expected call of WindowsAdminPasswordHash.
+func (mr *MockManagedClusterScopeMockRecorder) WindowsAdminPasswordHash() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WindowsAdminPasswordHash", reflect.TypeOf((*MockManagedClusterScope)(nil).WindowsAdminPasswordHash))
+}
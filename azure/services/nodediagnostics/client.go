@@ -0,0 +1,255 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodediagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const (
+	// sasURIExpirationTimeInMinutes is the lifetime of the SAS URI used to download the serial console log.
+	sasURIExpirationTimeInMinutes = 5
+	// downloadTimeout is the maximum time allowed to download the serial console log.
+	downloadTimeout = 30 * time.Second
+)
+
+// client wraps go-sdk.
+type client interface {
+	RunCommandAsync(ctx context.Context, resourceGroupName, vmName string, parameters compute.RunCommandInput) (compute.RunCommandResult, azureautorest.FutureAPI, error)
+	RunCommandOnScaleSetVMAsync(ctx context.Context, resourceGroupName, vmssName, instanceID string, parameters compute.RunCommandInput) (compute.RunCommandResult, azureautorest.FutureAPI, error)
+	IsDone(ctx context.Context, future azureautorest.FutureAPI) (bool, error)
+	Result(ctx context.Context, future azureautorest.FutureAPI, futureType string) (interface{}, error)
+	ScaleSetVMResult(ctx context.Context, future azureautorest.FutureAPI, futureType string) (interface{}, error)
+	GetSerialConsoleLog(ctx context.Context, resourceGroupName, vmName string) (string, error)
+	GetScaleSetVMSerialConsoleLog(ctx context.Context, resourceGroupName, vmssName, instanceID string) (string, error)
+}
+
+// SerialConsoleLogGetter retrieves the boot diagnostics serial console log of virtual machines and scale set instances.
+type SerialConsoleLogGetter interface {
+	GetSerialConsoleLog(ctx context.Context, resourceGroupName, vmName string) (string, error)
+	GetScaleSetVMSerialConsoleLog(ctx context.Context, resourceGroupName, vmssName, instanceID string) (string, error)
+}
+
+// azureClient contains the Azure go-sdk Client.
+type azureClient struct {
+	virtualmachines compute.VirtualMachinesClient
+	scalesetvms     compute.VirtualMachineScaleSetVMsClient
+	http            *http.Client
+}
+
+var (
+	_ client                 = (*azureClient)(nil)
+	_ SerialConsoleLogGetter = (*azureClient)(nil)
+)
+
+// NewSerialConsoleLogGetter creates a new SerialConsoleLogGetter from subscription ID.
+func NewSerialConsoleLogGetter(auth azure.Authorizer) SerialConsoleLogGetter {
+	return newClient(auth)
+}
+
+// newClient creates a new node diagnostics client from subscription ID.
+func newClient(auth azure.Authorizer) *azureClient {
+	vmClient := compute.NewVirtualMachinesClientWithBaseURI(auth.BaseURI(), auth.SubscriptionID())
+	azure.SetAutoRestClientDefaults(&vmClient.Client, auth.Authorizer())
+	vmssVMClient := compute.NewVirtualMachineScaleSetVMsClientWithBaseURI(auth.BaseURI(), auth.SubscriptionID())
+	azure.SetAutoRestClientDefaults(&vmssVMClient.Client, auth.Authorizer())
+	return &azureClient{
+		virtualmachines: vmClient,
+		scalesetvms:     vmssVMClient,
+		http:            &http.Client{Timeout: downloadTimeout},
+	}
+}
+
+// RunCommandAsync runs a command on a virtual machine. If the command does not complete within the default Azure
+// call timeout, the func returns a Future which can be used to track the ongoing progress of the operation.
+func (ac *azureClient) RunCommandAsync(ctx context.Context, resourceGroupName, vmName string, parameters compute.RunCommandInput) (compute.RunCommandResult, azureautorest.FutureAPI, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "nodediagnostics.azureClient.RunCommandAsync")
+	defer done()
+
+	future, err := ac.virtualmachines.RunCommand(ctx, resourceGroupName, vmName, parameters)
+	if err != nil {
+		return compute.RunCommandResult{}, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	if err := future.WaitForCompletionRef(ctx, ac.virtualmachines.Client); err != nil {
+		// if an error occurs, return the future.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return compute.RunCommandResult{}, &future, err
+	}
+	result, err := future.Result(ac.virtualmachines)
+	// if the operation completed, return a nil future
+	return result, nil, err
+}
+
+// RunCommandOnScaleSetVMAsync runs a command on a virtual machine scale set instance. If the command does not complete
+// within the default Azure call timeout, the func returns a Future which can be used to track the ongoing progress of
+// the operation.
+func (ac *azureClient) RunCommandOnScaleSetVMAsync(ctx context.Context, resourceGroupName, vmssName, instanceID string, parameters compute.RunCommandInput) (compute.RunCommandResult, azureautorest.FutureAPI, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "nodediagnostics.azureClient.RunCommandOnScaleSetVMAsync")
+	defer done()
+
+	future, err := ac.scalesetvms.RunCommand(ctx, resourceGroupName, vmssName, instanceID, parameters)
+	if err != nil {
+		return compute.RunCommandResult{}, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	if err := future.WaitForCompletionRef(ctx, ac.scalesetvms.Client); err != nil {
+		// if an error occurs, return the future.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return compute.RunCommandResult{}, &future, err
+	}
+	result, err := future.Result(ac.scalesetvms)
+	// if the operation completed, return a nil future
+	return result, nil, err
+}
+
+// IsDone returns true if the long-running operation has completed.
+func (ac *azureClient) IsDone(ctx context.Context, future azureautorest.FutureAPI) (bool, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "nodediagnostics.azureClient.IsDone")
+	defer done()
+
+	isDone, err := future.DoneWithContext(ctx, ac.virtualmachines)
+	if err != nil {
+		return false, errors.Wrap(err, "failed checking if the operation was complete")
+	}
+
+	return isDone, nil
+}
+
+// Result fetches the result of a long-running operation future on a virtual machine.
+func (ac *azureClient) Result(ctx context.Context, future azureautorest.FutureAPI, futureType string) (interface{}, error) {
+	_, _, done := tele.StartSpanWithLogger(ctx, "nodediagnostics.azureClient.Result")
+	defer done()
+
+	if future == nil {
+		return nil, errors.Errorf("cannot get result from nil future")
+	}
+
+	switch futureType {
+	case infrav1.PostFuture:
+		// Marshal and Unmarshal the future to put it into the correct future type so we can access the Result function.
+		var runCommandFuture *compute.VirtualMachinesRunCommandFuture
+		jsonData, err := future.MarshalJSON()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal future")
+		}
+		if err := json.Unmarshal(jsonData, &runCommandFuture); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal future data")
+		}
+		return (*runCommandFuture).Result(ac.virtualmachines)
+
+	default:
+		return nil, errors.Errorf("unknown future type %q", futureType)
+	}
+}
+
+// ScaleSetVMResult fetches the result of a long-running operation future on a virtual machine scale set instance.
+func (ac *azureClient) ScaleSetVMResult(ctx context.Context, future azureautorest.FutureAPI, futureType string) (interface{}, error) {
+	_, _, done := tele.StartSpanWithLogger(ctx, "nodediagnostics.azureClient.ScaleSetVMResult")
+	defer done()
+
+	if future == nil {
+		return nil, errors.Errorf("cannot get result from nil future")
+	}
+
+	switch futureType {
+	case infrav1.PostFuture:
+		// Marshal and Unmarshal the future to put it into the correct future type so we can access the Result function.
+		var runCommandFuture *compute.VirtualMachineScaleSetVMsRunCommandFuture
+		jsonData, err := future.MarshalJSON()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal future")
+		}
+		if err := json.Unmarshal(jsonData, &runCommandFuture); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal future data")
+		}
+		return (*runCommandFuture).Result(ac.scalesetvms)
+
+	default:
+		return nil, errors.Errorf("unknown future type %q", futureType)
+	}
+}
+
+// GetSerialConsoleLog downloads the boot diagnostics serial console log of a virtual machine.
+func (ac *azureClient) GetSerialConsoleLog(ctx context.Context, resourceGroupName, vmName string) (string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "nodediagnostics.azureClient.GetSerialConsoleLog")
+	defer done()
+
+	result, err := ac.virtualmachines.RetrieveBootDiagnosticsData(ctx, resourceGroupName, vmName, to.Int32Ptr(sasURIExpirationTimeInMinutes))
+	if err != nil {
+		return "", err
+	}
+	return ac.download(ctx, to.String(result.SerialConsoleLogBlobURI))
+}
+
+// GetScaleSetVMSerialConsoleLog downloads the boot diagnostics serial console log of a virtual machine scale set instance.
+func (ac *azureClient) GetScaleSetVMSerialConsoleLog(ctx context.Context, resourceGroupName, vmssName, instanceID string) (string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "nodediagnostics.azureClient.GetScaleSetVMSerialConsoleLog")
+	defer done()
+
+	result, err := ac.scalesetvms.RetrieveBootDiagnosticsData(ctx, resourceGroupName, vmssName, instanceID, to.Int32Ptr(sasURIExpirationTimeInMinutes))
+	if err != nil {
+		return "", err
+	}
+	return ac.download(ctx, to.String(result.SerialConsoleLogBlobURI))
+}
+
+// download fetches the content of a blob from its SAS URI.
+func (ac *azureClient) download(ctx context.Context, uri string) (string, error) {
+	if uri == "" {
+		return "", errors.New("serial console log is not available, boot diagnostics may be disabled")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, http.NoBody)
+	if err != nil {
+		return "", err
+	}
+	resp, err := ac.http.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to download serial console log")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", autorest.NewErrorWithResponse("nodediagnostics", "download", resp, "failed to download serial console log")
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read serial console log")
+	}
+	return string(body), nil
+}
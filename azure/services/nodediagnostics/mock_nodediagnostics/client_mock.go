@@ -0,0 +1,213 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../client.go
+
+// Package mock_nodediagnostics is a generated GoMock package.
+package mock_nodediagnostics
+
+import (
+	context "context"
+	reflect "reflect"
+
+	compute "github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	azure "github.com/Azure/go-autorest/autorest/azure"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// Mockclient is a mock of client interface.
+type Mockclient struct {
+	ctrl     *gomock.Controller
+	recorder *MockclientMockRecorder
+}
+
+// MockclientMockRecorder is the mock recorder for Mockclient.
+type MockclientMockRecorder struct {
+	mock *Mockclient
+}
+
+// NewMockclient creates a new mock instance.
+func NewMockclient(ctrl *gomock.Controller) *Mockclient {
+	mock := &Mockclient{ctrl: ctrl}
+	mock.recorder = &MockclientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockclient) EXPECT() *MockclientMockRecorder {
+	return m.recorder
+}
+
+// GetScaleSetVMSerialConsoleLog mocks base method.
+func (m *Mockclient) GetScaleSetVMSerialConsoleLog(ctx context.Context, resourceGroupName, vmssName, instanceID string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScaleSetVMSerialConsoleLog", ctx, resourceGroupName, vmssName, instanceID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScaleSetVMSerialConsoleLog indicates an expected call of GetScaleSetVMSerialConsoleLog.
+func (mr *MockclientMockRecorder) GetScaleSetVMSerialConsoleLog(ctx, resourceGroupName, vmssName, instanceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScaleSetVMSerialConsoleLog", reflect.TypeOf((*Mockclient)(nil).GetScaleSetVMSerialConsoleLog), ctx, resourceGroupName, vmssName, instanceID)
+}
+
+// GetSerialConsoleLog mocks base method.
+func (m *Mockclient) GetSerialConsoleLog(ctx context.Context, resourceGroupName, vmName string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSerialConsoleLog", ctx, resourceGroupName, vmName)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSerialConsoleLog indicates an expected call of GetSerialConsoleLog.
+func (mr *MockclientMockRecorder) GetSerialConsoleLog(ctx, resourceGroupName, vmName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSerialConsoleLog", reflect.TypeOf((*Mockclient)(nil).GetSerialConsoleLog), ctx, resourceGroupName, vmName)
+}
+
+// IsDone mocks base method.
+func (m *Mockclient) IsDone(ctx context.Context, future azure.FutureAPI) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsDone", ctx, future)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsDone indicates an expected call of IsDone.
+func (mr *MockclientMockRecorder) IsDone(ctx, future interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsDone", reflect.TypeOf((*Mockclient)(nil).IsDone), ctx, future)
+}
+
+// Result mocks base method.
+func (m *Mockclient) Result(ctx context.Context, future azure.FutureAPI, futureType string) (interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Result", ctx, future, futureType)
+	ret0, _ := ret[0].(interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Result indicates an expected call of Result.
+func (mr *MockclientMockRecorder) Result(ctx, future, futureType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Result", reflect.TypeOf((*Mockclient)(nil).Result), ctx, future, futureType)
+}
+
+// RunCommandAsync mocks base method.
+func (m *Mockclient) RunCommandAsync(ctx context.Context, resourceGroupName, vmName string, parameters compute.RunCommandInput) (compute.RunCommandResult, azure.FutureAPI, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunCommandAsync", ctx, resourceGroupName, vmName, parameters)
+	ret0, _ := ret[0].(compute.RunCommandResult)
+	ret1, _ := ret[1].(azure.FutureAPI)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RunCommandAsync indicates an expected call of RunCommandAsync.
+func (mr *MockclientMockRecorder) RunCommandAsync(ctx, resourceGroupName, vmName, parameters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunCommandAsync", reflect.TypeOf((*Mockclient)(nil).RunCommandAsync), ctx, resourceGroupName, vmName, parameters)
+}
+
+// RunCommandOnScaleSetVMAsync mocks base method.
+func (m *Mockclient) RunCommandOnScaleSetVMAsync(ctx context.Context, resourceGroupName, vmssName, instanceID string, parameters compute.RunCommandInput) (compute.RunCommandResult, azure.FutureAPI, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunCommandOnScaleSetVMAsync", ctx, resourceGroupName, vmssName, instanceID, parameters)
+	ret0, _ := ret[0].(compute.RunCommandResult)
+	ret1, _ := ret[1].(azure.FutureAPI)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RunCommandOnScaleSetVMAsync indicates an expected call of RunCommandOnScaleSetVMAsync.
+func (mr *MockclientMockRecorder) RunCommandOnScaleSetVMAsync(ctx, resourceGroupName, vmssName, instanceID, parameters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunCommandOnScaleSetVMAsync", reflect.TypeOf((*Mockclient)(nil).RunCommandOnScaleSetVMAsync), ctx, resourceGroupName, vmssName, instanceID, parameters)
+}
+
+// ScaleSetVMResult mocks base method.
+func (m *Mockclient) ScaleSetVMResult(ctx context.Context, future azure.FutureAPI, futureType string) (interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScaleSetVMResult", ctx, future, futureType)
+	ret0, _ := ret[0].(interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScaleSetVMResult indicates an expected call of ScaleSetVMResult.
+func (mr *MockclientMockRecorder) ScaleSetVMResult(ctx, future, futureType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScaleSetVMResult", reflect.TypeOf((*Mockclient)(nil).ScaleSetVMResult), ctx, future, futureType)
+}
+
+// MockSerialConsoleLogGetter is a mock of SerialConsoleLogGetter interface.
+type MockSerialConsoleLogGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockSerialConsoleLogGetterMockRecorder
+}
+
+// MockSerialConsoleLogGetterMockRecorder is the mock recorder for MockSerialConsoleLogGetter.
+type MockSerialConsoleLogGetterMockRecorder struct {
+	mock *MockSerialConsoleLogGetter
+}
+
+// NewMockSerialConsoleLogGetter creates a new mock instance.
+func NewMockSerialConsoleLogGetter(ctrl *gomock.Controller) *MockSerialConsoleLogGetter {
+	mock := &MockSerialConsoleLogGetter{ctrl: ctrl}
+	mock.recorder = &MockSerialConsoleLogGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSerialConsoleLogGetter) EXPECT() *MockSerialConsoleLogGetterMockRecorder {
+	return m.recorder
+}
+
+// GetScaleSetVMSerialConsoleLog mocks base method.
+func (m *MockSerialConsoleLogGetter) GetScaleSetVMSerialConsoleLog(ctx context.Context, resourceGroupName, vmssName, instanceID string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScaleSetVMSerialConsoleLog", ctx, resourceGroupName, vmssName, instanceID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScaleSetVMSerialConsoleLog indicates an expected call of GetScaleSetVMSerialConsoleLog.
+func (mr *MockSerialConsoleLogGetterMockRecorder) GetScaleSetVMSerialConsoleLog(ctx, resourceGroupName, vmssName, instanceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScaleSetVMSerialConsoleLog", reflect.TypeOf((*MockSerialConsoleLogGetter)(nil).GetScaleSetVMSerialConsoleLog), ctx, resourceGroupName, vmssName, instanceID)
+}
+
+// GetSerialConsoleLog mocks base method.
+func (m *MockSerialConsoleLogGetter) GetSerialConsoleLog(ctx context.Context, resourceGroupName, vmName string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSerialConsoleLog", ctx, resourceGroupName, vmName)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSerialConsoleLog indicates an expected call of GetSerialConsoleLog.
+func (mr *MockSerialConsoleLogGetterMockRecorder) GetSerialConsoleLog(ctx, resourceGroupName, vmName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSerialConsoleLog", reflect.TypeOf((*MockSerialConsoleLogGetter)(nil).GetSerialConsoleLog), ctx, resourceGroupName, vmName)
+}
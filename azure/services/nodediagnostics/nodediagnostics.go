@@ -0,0 +1,234 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodediagnostics
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
+	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+)
+
+const serviceName = "nodediagnostics"
+
+const (
+	// MaxEventOutputLength is the maximum length of the node diagnostics excerpt recorded in an event.
+	MaxEventOutputLength = 1024
+	// MaxSecretOutputLength is the maximum length of the node diagnostics stored in a Secret.
+	MaxSecretOutputLength = 512 * 1024
+
+	truncatedMarker = "...(truncated)\n"
+)
+
+var (
+	linuxDiagnosticsScript = []string{
+		"echo '### cloud-init-output.log'",
+		"tail -n 100 /var/log/cloud-init-output.log",
+		"echo '### kubelet'",
+		"journalctl -u kubelet --no-pager -n 100",
+	}
+	windowsDiagnosticsScript = []string{
+		"Write-Output '### cloudbase-init.log'",
+		"Get-Content 'C:\\Program Files\\Cloudbase Solutions\\Cloudbase-Init\\log\\cloudbase-init.log' -Tail 100 -ErrorAction SilentlyContinue",
+		"Write-Output '### kubelet'",
+		"Get-ChildItem 'C:\\var\\log\\kubelet' -Filter '*.log' -ErrorAction SilentlyContinue | Get-Content -Tail 100",
+	}
+)
+
+// NodeDiagnosticsScope defines the scope interface for a node diagnostics service.
+type NodeDiagnosticsScope interface {
+	azure.Authorizer
+	azure.AsyncStatusUpdater
+	ResourceGroup() string
+	NodeDiagnosticsSpec() *azure.NodeDiagnosticsSpec
+}
+
+// Service provides operations on Azure resources.
+type Service struct {
+	Scope NodeDiagnosticsScope
+	client
+}
+
+// New creates a new node diagnostics service.
+func New(scope NodeDiagnosticsScope) *Service {
+	return &Service{
+		Scope:  scope,
+		client: newClient(scope),
+	}
+}
+
+// Collect runs a diagnostics command on the VM or scale set instance described by the scope and returns its output,
+// followed by the boot diagnostics serial console log. It returns an empty string if there is nothing to collect.
+// The command runs as a long-running operation: while it is in progress, Collect returns a transient error and
+// checks on the operation stored in the scope on the next call.
+func (s *Service) Collect(ctx context.Context) (string, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "nodediagnostics.Service.Collect")
+	defer done()
+
+	spec := s.Scope.NodeDiagnosticsSpec()
+	if spec == nil {
+		return "", nil
+	}
+
+	target := fmt.Sprintf("VM %s", spec.VMName)
+	resourceName := spec.VMName
+	if spec.ScaleSetName != "" {
+		target = fmt.Sprintf("instance %s of scale set %s", spec.InstanceID, spec.ScaleSetName)
+		resourceName = fmt.Sprintf("%s/%s", spec.ScaleSetName, spec.InstanceID)
+	}
+
+	result, err := s.runCommand(ctx, spec, resourceName)
+	if err != nil {
+		if azure.IsOperationNotDoneError(err) {
+			log.V(2).Info("node diagnostics command is still running", "target", target)
+			return "", err
+		}
+		return "", errors.Wrapf(err, "failed to run node diagnostics command on %s", target)
+	}
+
+	var serialLog string
+	var serialErr error
+	if spec.ScaleSetName != "" {
+		serialLog, serialErr = s.client.GetScaleSetVMSerialConsoleLog(ctx, s.Scope.ResourceGroup(), spec.ScaleSetName, spec.InstanceID)
+	} else {
+		serialLog, serialErr = s.client.GetSerialConsoleLog(ctx, s.Scope.ResourceGroup(), spec.VMName)
+	}
+
+	var sb strings.Builder
+	if result.Value != nil {
+		for _, status := range *result.Value {
+			sb.WriteString(fmt.Sprintf("=== %s\n%s\n", to.String(status.Code), to.String(status.Message)))
+		}
+	}
+	sb.WriteString("=== serial console log\n")
+	if serialErr != nil {
+		log.V(2).Info("unable to retrieve serial console log", "error", serialErr.Error())
+		sb.WriteString(fmt.Sprintf("unavailable: %s\n", serialErr.Error()))
+	} else {
+		sb.WriteString(serialLog)
+	}
+
+	return sb.String(), nil
+}
+
+// runCommand starts the diagnostics command, or checks on the command started by a previous call, and returns its
+// result once it has completed.
+func (s *Service) runCommand(ctx context.Context, spec *azure.NodeDiagnosticsSpec, resourceName string) (compute.RunCommandResult, error) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "nodediagnostics.Service.runCommand")
+	defer done()
+
+	rgName := s.Scope.ResourceGroup()
+
+	// Check if there is an ongoing long running operation.
+	if future := s.Scope.GetLongRunningOperationState(resourceName, serviceName); future != nil {
+		sdkFuture, err := converters.FutureToSDK(*future)
+		if err != nil {
+			// Reset the future data to avoid getting stuck in a bad loop.
+			s.Scope.DeleteLongRunningOperationState(resourceName, serviceName)
+			return compute.RunCommandResult{}, errors.Wrap(err, "could not decode future data, resetting long-running operation state")
+		}
+
+		isDone, err := s.client.IsDone(ctx, sdkFuture)
+		if err != nil {
+			return compute.RunCommandResult{}, errors.Wrap(err, "failed checking if the operation was complete")
+		}
+		if !isDone {
+			return compute.RunCommandResult{}, azure.WithTransientError(azure.NewOperationNotDoneError(future), retryAfter(sdkFuture))
+		}
+
+		log.V(2).Info("node diagnostics command has completed", "resource", resourceName)
+		var result interface{}
+		if spec.ScaleSetName != "" {
+			result, err = s.client.ScaleSetVMResult(ctx, sdkFuture, future.Type)
+		} else {
+			result, err = s.client.Result(ctx, sdkFuture, future.Type)
+		}
+		// The command is not run again if it failed, as diagnostics are collected at most once.
+		s.Scope.DeleteLongRunningOperationState(resourceName, serviceName)
+		if err != nil {
+			return compute.RunCommandResult{}, err
+		}
+		runCommandResult, ok := result.(compute.RunCommandResult)
+		if !ok {
+			return compute.RunCommandResult{}, errors.Errorf("%T is not a compute.RunCommandResult", result)
+		}
+		return runCommandResult, nil
+	}
+
+	input := compute.RunCommandInput{
+		CommandID: to.StringPtr("RunShellScript"),
+		Script:    &linuxDiagnosticsScript,
+	}
+	if spec.OSType == azure.WindowsOS {
+		input = compute.RunCommandInput{
+			CommandID: to.StringPtr("RunPowerShellScript"),
+			Script:    &windowsDiagnosticsScript,
+		}
+	}
+
+	var (
+		result    compute.RunCommandResult
+		sdkFuture azureautorest.FutureAPI
+		err       error
+	)
+	log.V(2).Info("collecting node diagnostics", "resource", resourceName)
+	if spec.ScaleSetName != "" {
+		result, sdkFuture, err = s.client.RunCommandOnScaleSetVMAsync(ctx, rgName, spec.ScaleSetName, spec.InstanceID, input)
+	} else {
+		result, sdkFuture, err = s.client.RunCommandAsync(ctx, rgName, spec.VMName, input)
+	}
+	if sdkFuture != nil {
+		future, err := converters.SDKToFuture(sdkFuture, infrav1.PostFuture, serviceName, resourceName, rgName)
+		if err != nil {
+			return compute.RunCommandResult{}, err
+		}
+		s.Scope.SetLongRunningOperationState(future)
+		return compute.RunCommandResult{}, azure.WithTransientError(azure.NewOperationNotDoneError(future), retryAfter(sdkFuture))
+	}
+	return result, err
+}
+
+// retryAfter returns the max between the `RETRY-AFTER` header and the default requeue time.
+func retryAfter(sdkFuture azureautorest.FutureAPI) time.Duration {
+	retryAfter, _ := sdkFuture.GetPollingDelay()
+	if retryAfter < reconciler.DefaultReconcilerRequeue {
+		retryAfter = reconciler.DefaultReconcilerRequeue
+	}
+	return retryAfter
+}
+
+// Truncate shortens the diagnostics output to at most maxLength bytes, keeping the end of the output where
+// bootstrap errors are usually reported.
+func Truncate(output string, maxLength int) string {
+	if len(output) <= maxLength {
+		return output
+	}
+	if maxLength <= len(truncatedMarker) {
+		return output[len(output)-maxLength:]
+	}
+	return truncatedMarker + output[len(output)-maxLength+len(truncatedMarker):]
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodediagnostics
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
+	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/nodediagnostics/mock_nodediagnostics"
+	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
+)
+
+var (
+	fakeRunCommandResult = compute.RunCommandResult{
+		Value: &[]compute.InstanceViewStatus{
+			{
+				Code:    to.StringPtr("ProvisioningState/succeeded"),
+				Message: to.StringPtr("kubelet failed to start"),
+			},
+		},
+	}
+	runCommandFuture = infrav1.Future{
+		Type:          infrav1.PostFuture,
+		ServiceName:   serviceName,
+		Name:          "my-vm",
+		ResourceGroup: "my-rg",
+		Data:          "eyJtZXRob2QiOiJQT1NUIiwicG9sbGluZ01ldGhvZCI6IkxvY2F0aW9uIiwibHJvU3RhdGUiOiJJblByb2dyZXNzIn0=",
+	}
+)
+
+func TestCollectNodeDiagnostics(t *testing.T) {
+	testcases := []struct {
+		name           string
+		expectedOutput string
+		expectedError  string
+		expect         func(s *mock_nodediagnostics.MockNodeDiagnosticsScopeMockRecorder, m *mock_nodediagnostics.MockclientMockRecorder)
+	}{
+		{
+			name:           "nothing to collect",
+			expectedOutput: "",
+			expect: func(s *mock_nodediagnostics.MockNodeDiagnosticsScopeMockRecorder, m *mock_nodediagnostics.MockclientMockRecorder) {
+				s.NodeDiagnosticsSpec().Return(nil)
+			},
+		},
+		{
+			name:           "collect diagnostics from a linux VM",
+			expectedOutput: "=== ProvisioningState/succeeded\nkubelet failed to start\n=== serial console log\nkernel panic",
+			expect: func(s *mock_nodediagnostics.MockNodeDiagnosticsScopeMockRecorder, m *mock_nodediagnostics.MockclientMockRecorder) {
+				s.NodeDiagnosticsSpec().Return(&azure.NodeDiagnosticsSpec{VMName: "my-vm", OSType: azure.LinuxOS})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.GetLongRunningOperationState("my-vm", serviceName).Return(nil)
+				m.RunCommandAsync(gomockinternal.AContext(), "my-rg", "my-vm", compute.RunCommandInput{
+					CommandID: to.StringPtr("RunShellScript"),
+					Script:    &linuxDiagnosticsScript,
+				}).Return(fakeRunCommandResult, nil, nil)
+				m.GetSerialConsoleLog(gomockinternal.AContext(), "my-rg", "my-vm").Return("kernel panic", nil)
+			},
+		},
+		{
+			name:           "collect diagnostics from a windows scale set instance without serial console log",
+			expectedOutput: "=== ProvisioningState/succeeded\nkubelet failed to start\n=== serial console log\nunavailable: boot diagnostics disabled\n",
+			expect: func(s *mock_nodediagnostics.MockNodeDiagnosticsScopeMockRecorder, m *mock_nodediagnostics.MockclientMockRecorder) {
+				s.NodeDiagnosticsSpec().Return(&azure.NodeDiagnosticsSpec{ScaleSetName: "my-vmss", InstanceID: "2", OSType: azure.WindowsOS})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.GetLongRunningOperationState("my-vmss/2", serviceName).Return(nil)
+				m.RunCommandOnScaleSetVMAsync(gomockinternal.AContext(), "my-rg", "my-vmss", "2", compute.RunCommandInput{
+					CommandID: to.StringPtr("RunPowerShellScript"),
+					Script:    &windowsDiagnosticsScript,
+				}).Return(fakeRunCommandResult, nil, nil)
+				m.GetScaleSetVMSerialConsoleLog(gomockinternal.AContext(), "my-rg", "my-vmss", "2").Return("", errors.New("boot diagnostics disabled"))
+			},
+		},
+		{
+			name:          "run command fails",
+			expectedError: "failed to run node diagnostics command on VM my-vm: #: Conflict: StatusCode=409",
+			expect: func(s *mock_nodediagnostics.MockNodeDiagnosticsScopeMockRecorder, m *mock_nodediagnostics.MockclientMockRecorder) {
+				s.NodeDiagnosticsSpec().Return(&azure.NodeDiagnosticsSpec{VMName: "my-vm", OSType: azure.LinuxOS})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.GetLongRunningOperationState("my-vm", serviceName).Return(nil)
+				m.RunCommandAsync(gomockinternal.AContext(), "my-rg", "my-vm", gomock.AssignableToTypeOf(compute.RunCommandInput{})).
+					Return(compute.RunCommandResult{}, nil, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 409}, "Conflict"))
+			},
+		},
+		{
+			name:          "run command does not complete before the timeout",
+			expectedError: "operation type POST on Azure resource my-rg/my-vm is not done. Object will be requeued after 15s",
+			expect: func(s *mock_nodediagnostics.MockNodeDiagnosticsScopeMockRecorder, m *mock_nodediagnostics.MockclientMockRecorder) {
+				s.NodeDiagnosticsSpec().Return(&azure.NodeDiagnosticsSpec{VMName: "my-vm", OSType: azure.LinuxOS})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.GetLongRunningOperationState("my-vm", serviceName).Return(nil)
+				m.RunCommandAsync(gomockinternal.AContext(), "my-rg", "my-vm", gomock.AssignableToTypeOf(compute.RunCommandInput{})).
+					Return(compute.RunCommandResult{}, &azureautorest.Future{}, errors.New("context deadline exceeded"))
+				s.SetLongRunningOperationState(gomock.AssignableToTypeOf(&infrav1.Future{}))
+			},
+		},
+		{
+			name:          "run command is still in progress",
+			expectedError: "operation type POST on Azure resource my-rg/my-vm is not done. Object will be requeued after 15s",
+			expect: func(s *mock_nodediagnostics.MockNodeDiagnosticsScopeMockRecorder, m *mock_nodediagnostics.MockclientMockRecorder) {
+				s.NodeDiagnosticsSpec().Return(&azure.NodeDiagnosticsSpec{VMName: "my-vm", OSType: azure.LinuxOS})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.GetLongRunningOperationState("my-vm", serviceName).Return(&runCommandFuture)
+				m.IsDone(gomockinternal.AContext(), gomock.AssignableToTypeOf(&azureautorest.Future{})).Return(false, nil)
+			},
+		},
+		{
+			name:           "collect diagnostics once the run command has completed",
+			expectedOutput: "=== ProvisioningState/succeeded\nkubelet failed to start\n=== serial console log\nkernel panic",
+			expect: func(s *mock_nodediagnostics.MockNodeDiagnosticsScopeMockRecorder, m *mock_nodediagnostics.MockclientMockRecorder) {
+				s.NodeDiagnosticsSpec().Return(&azure.NodeDiagnosticsSpec{VMName: "my-vm", OSType: azure.LinuxOS})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.GetLongRunningOperationState("my-vm", serviceName).Return(&runCommandFuture)
+				m.IsDone(gomockinternal.AContext(), gomock.AssignableToTypeOf(&azureautorest.Future{})).Return(true, nil)
+				m.Result(gomockinternal.AContext(), gomock.AssignableToTypeOf(&azureautorest.Future{}), infrav1.PostFuture).Return(fakeRunCommandResult, nil)
+				s.DeleteLongRunningOperationState("my-vm", serviceName)
+				m.GetSerialConsoleLog(gomockinternal.AContext(), "my-rg", "my-vm").Return("kernel panic", nil)
+			},
+		},
+		{
+			name:           "collect diagnostics once the run command on a scale set instance has completed",
+			expectedOutput: "=== ProvisioningState/succeeded\nkubelet failed to start\n=== serial console log\nkernel panic",
+			expect: func(s *mock_nodediagnostics.MockNodeDiagnosticsScopeMockRecorder, m *mock_nodediagnostics.MockclientMockRecorder) {
+				s.NodeDiagnosticsSpec().Return(&azure.NodeDiagnosticsSpec{ScaleSetName: "my-vmss", InstanceID: "0", OSType: azure.LinuxOS})
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.GetLongRunningOperationState("my-vmss/0", serviceName).Return(&runCommandFuture)
+				m.IsDone(gomockinternal.AContext(), gomock.AssignableToTypeOf(&azureautorest.Future{})).Return(true, nil)
+				m.ScaleSetVMResult(gomockinternal.AContext(), gomock.AssignableToTypeOf(&azureautorest.Future{}), infrav1.PostFuture).Return(fakeRunCommandResult, nil)
+				s.DeleteLongRunningOperationState("my-vmss/0", serviceName)
+				m.GetScaleSetVMSerialConsoleLog(gomockinternal.AContext(), "my-rg", "my-vmss", "0").Return("kernel panic", nil)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_nodediagnostics.NewMockNodeDiagnosticsScope(mockCtrl)
+			clientMock := mock_nodediagnostics.NewMockclient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			output, err := s.Collect(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(output).To(Equal(tc.expectedOutput))
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(Truncate("short output", 1024)).To(Equal("short output"))
+
+	long := strings.Repeat("a", 100) + "the error"
+	truncated := Truncate(long, 40)
+	g.Expect(truncated).To(HaveLen(40))
+	g.Expect(truncated).To(HavePrefix(truncatedMarker))
+	g.Expect(truncated).To(HaveSuffix("the error"))
+
+	g.Expect(Truncate(long, 5)).To(Equal("error"))
+}
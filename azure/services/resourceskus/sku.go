@@ -58,6 +58,10 @@ const (
 	VCPUs = "vCPUs"
 	// MemoryGB identifies the capability for memory Size.
 	MemoryGB = "MemoryGB"
+	// GPUs identifies the capability for the number of GPUs.
+	GPUs = "GPUs"
+	// CPUArchitectureType identifies the capability for the CPU architecture, either x64 or Arm64.
+	CPUArchitectureType = "CpuArchitectureType"
 	// MinimumVCPUS is the minimum vCPUS allowed.
 	MinimumVCPUS = 2
 	// MinimumMemory is the minimum memory allowed.
@@ -19,6 +19,7 @@ package roleassignments
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/profiles/2019-03-01/authorization/mgmt/authorization"
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
@@ -26,6 +27,7 @@ import (
 	"github.com/pkg/errors"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/identities"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesets"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachines"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
@@ -45,6 +47,7 @@ type Service struct {
 	client
 	virtualMachinesGetter        async.Getter
 	virtualMachineScaleSetClient scalesets.Client
+	identitiesClient             identities.Client
 }
 
 // New creates a new service.
@@ -54,23 +57,30 @@ func New(scope RoleAssignmentScope) *Service {
 		client:                       newClient(scope),
 		virtualMachinesGetter:        virtualmachines.NewClient(scope),
 		virtualMachineScaleSetClient: scalesets.NewClient(scope),
+		identitiesClient:             identities.NewClient(scope),
 	}
 }
 
-// Reconcile creates a role assignment.
+// Reconcile creates the role assignments.
 func (s *Service) Reconcile(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "roleassignments.Service.Reconcile")
 	defer done()
 
 	for _, roleSpec := range s.Scope.RoleAssignmentSpecs() {
+		var err error
 		switch roleSpec.ResourceType {
 		case azure.VirtualMachine:
-			return s.reconcileVM(ctx, roleSpec)
+			err = s.reconcileVM(ctx, roleSpec)
 		case azure.VirtualMachineScaleSet:
-			return s.reconcileVMSS(ctx, roleSpec)
+			err = s.reconcileVMSS(ctx, roleSpec)
+		case azure.UserAssignedIdentity:
+			err = s.reconcileUserAssignedIdentity(ctx, roleSpec)
 		default:
-			return errors.Errorf("unexpected resource type %q. Expected one of [%s, %s]", roleSpec.ResourceType,
-				azure.VirtualMachine, azure.VirtualMachineScaleSet)
+			err = errors.Errorf("unexpected resource type %q. Expected one of [%s, %s, %s]", roleSpec.ResourceType,
+				azure.VirtualMachine, azure.VirtualMachineScaleSet, azure.UserAssignedIdentity)
+		}
+		if err != nil {
+			return err
 		}
 	}
 	return nil
@@ -94,7 +104,7 @@ func (s *Service) reconcileVM(ctx context.Context, roleSpec azure.RoleAssignment
 		return errors.Errorf("%T is not a compute.VirtualMachine", resultVMIface)
 	}
 
-	err = s.assignRole(ctx, roleSpec.Name, resultVM.Identity.PrincipalID)
+	err = s.assignRole(ctx, roleSpec, resultVM.Identity.PrincipalID)
 	if err != nil {
 		return errors.Wrap(err, "cannot assign role to VM system assigned identity")
 	}
@@ -113,7 +123,7 @@ func (s *Service) reconcileVMSS(ctx context.Context, roleSpec azure.RoleAssignme
 		return errors.Wrap(err, "cannot get VMSS to assign role to system assigned identity")
 	}
 
-	err = s.assignRole(ctx, roleSpec.Name, resultVMSS.Identity.PrincipalID)
+	err = s.assignRole(ctx, roleSpec, resultVMSS.Identity.PrincipalID)
 	if err != nil {
 		return errors.Wrap(err, "cannot assign role to VMSS system assigned identity")
 	}
@@ -123,24 +133,65 @@ func (s *Service) reconcileVMSS(ctx context.Context, roleSpec azure.RoleAssignme
 	return nil
 }
 
-func (s *Service) assignRole(ctx context.Context, roleAssignmentName string, principalID *string) error {
+func (s *Service) reconcileUserAssignedIdentity(ctx context.Context, roleSpec azure.RoleAssignmentSpec) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "roleassignments.Service.reconcileUserAssignedIdentity")
+	defer done()
+
+	principalID, err := s.identitiesClient.GetPrincipalID(ctx, roleSpec.IdentityResourceID)
+	if err != nil {
+		return errors.Wrapf(err, "cannot get user-assigned identity %s to assign role to", roleSpec.IdentityResourceID)
+	}
+
+	err = s.assignRole(ctx, roleSpec, to.StringPtr(principalID))
+	// The role may already be assigned to the identity by a role assignment of another name, for example one
+	// created by the user before the cluster.
+	if err != nil && !azure.ResourceConflict(err) {
+		return errors.Wrapf(err, "cannot assign role to user-assigned identity %s on %s", roleSpec.IdentityResourceID, roleSpec.Scope)
+	}
+
+	log.V(2).Info("successfully created role assignment for user-assigned identity", "identity", roleSpec.IdentityResourceID, "scope", roleSpec.Scope)
+
+	return nil
+}
+
+func (s *Service) assignRole(ctx context.Context, roleSpec azure.RoleAssignmentSpec, principalID *string) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "roleassignments.Service.assignRole")
 	defer done()
 
-	scope := fmt.Sprintf("/subscriptions/%s/", s.Scope.SubscriptionID())
+	scope := roleSpec.Scope
+	subscriptionID := subscriptionIDFromScope(scope)
+	if scope == "" {
+		scope = fmt.Sprintf("/subscriptions/%s/", s.Scope.SubscriptionID())
+		subscriptionID = s.Scope.SubscriptionID()
+	}
+	roleID := roleSpec.RoleDefinitionID
+	if roleID == "" {
+		roleID = azureBuiltInContributorID
+	}
 	// Azure built-in roles https://docs.microsoft.com/en-us/azure/role-based-access-control/built-in-roles
-	contributorRoleDefinitionID := fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Authorization/roleDefinitions/%s", s.Scope.SubscriptionID(), azureBuiltInContributorID)
+	roleDefinitionID := fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Authorization/roleDefinitions/%s", subscriptionID, roleID)
 	params := authorization.RoleAssignmentCreateParameters{
 		Properties: &authorization.RoleAssignmentProperties{
-			RoleDefinitionID: to.StringPtr(contributorRoleDefinitionID),
+			RoleDefinitionID: to.StringPtr(roleDefinitionID),
 			PrincipalID:      principalID,
 		},
 	}
-	_, err := s.client.Create(ctx, scope, roleAssignmentName, params)
+	_, err := s.client.Create(ctx, scope, roleSpec.Name, params)
 	return err
 }
 
-// Delete is a no-op as the role assignments get deleted as part of VM deletion.
+// subscriptionIDFromScope returns the subscription ID of the scope of a role assignment, as the role definitions
+// of a role assignment must belong to the subscription of its scope.
+func subscriptionIDFromScope(scope string) string {
+	parts := strings.Split(strings.Trim(scope, "/"), "/")
+	if len(parts) < 2 || !strings.EqualFold(parts[0], "subscriptions") {
+		return ""
+	}
+	return parts[1]
+}
+
+// Delete is a no-op as the role assignments get deleted as part of VM deletion, and the role assignments of
+// user-assigned identities are kept with the identities that the user manages.
 func (s *Service) Delete(ctx context.Context) error {
 	_, _, done := tele.StartSpanWithLogger(ctx, "roleassignments.Service.Delete")
 	defer done()
@@ -29,6 +29,7 @@ import (
 	. "github.com/onsi/gomega"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/async/mock_async"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/identities/mock_identities"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments/mock_roleassignments"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/scalesets/mock_scalesets"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualmachines"
@@ -73,6 +74,55 @@ func TestReconcileRoleAssignmentsVM(t *testing.T) {
 				}))
 			},
 		},
+		{
+			name:          "create the role assignments of all the specs",
+			expectedError: "",
+			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, m *mock_roleassignments.MockclientMockRecorder, v *mock_async.MockGetterMockRecorder) {
+				s.SubscriptionID().AnyTimes().Return("12345")
+				s.ResourceGroup().Times(2).Return("my-rg")
+				s.RoleAssignmentSpecs().Return([]azure.RoleAssignmentSpec{
+					{
+						MachineName:  "test-vm",
+						Name:         "role-assignment-1",
+						ResourceType: azure.VirtualMachine,
+					},
+					{
+						MachineName:  "test-vm",
+						Name:         "role-assignment-2",
+						ResourceType: azure.VirtualMachine,
+						Scope:        "/subscriptions/12345/resourceGroups/my-rg",
+					},
+				})
+				v.Get(gomockinternal.AContext(), &fakeVMSpec).Times(2).Return(compute.VirtualMachine{
+					Identity: &compute.VirtualMachineIdentity{
+						PrincipalID: to.StringPtr("000"),
+					},
+				}, nil)
+				m.Create(gomockinternal.AContext(), "/subscriptions/12345/", "role-assignment-1", gomock.AssignableToTypeOf(authorization.RoleAssignmentCreateParameters{}))
+				m.Create(gomockinternal.AContext(), "/subscriptions/12345/resourceGroups/my-rg", "role-assignment-2", gomock.AssignableToTypeOf(authorization.RoleAssignmentCreateParameters{}))
+			},
+		},
+		{
+			name:          "stop at the first spec that fails",
+			expectedError: "cannot get VM to assign role to system assigned identity: #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, m *mock_roleassignments.MockclientMockRecorder, v *mock_async.MockGetterMockRecorder) {
+				s.SubscriptionID().AnyTimes().Return("12345")
+				s.ResourceGroup().Return("my-rg")
+				s.RoleAssignmentSpecs().Return([]azure.RoleAssignmentSpec{
+					{
+						MachineName:  "test-vm",
+						Name:         "role-assignment-1",
+						ResourceType: azure.VirtualMachine,
+					},
+					{
+						MachineName:  "other-vm",
+						Name:         "role-assignment-2",
+						ResourceType: azure.VirtualMachine,
+					},
+				})
+				v.Get(gomockinternal.AContext(), &fakeVMSpec).Return(compute.VirtualMachine{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
 		{
 			name:          "error getting VM",
 			expectedError: "cannot get VM to assign role to system assigned identity: #: Internal Server Error: StatusCode=500",
@@ -236,3 +286,113 @@ func TestReconcileRoleAssignmentsVMSS(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileRoleAssignmentsUserAssignedIdentity(t *testing.T) {
+	var (
+		controlPlaneIdentity = "/subscriptions/12345/resourceGroups/identities-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/control-plane"
+		kubeletIdentity      = "/subscriptions/12345/resourceGroups/identities-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/kubelet"
+		subnet               = "/subscriptions/12345/resourceGroups/my-rg/providers/Microsoft.Network/virtualNetworks/my-vnet/subnets/my-subnet"
+		registry             = "/subscriptions/67890/resourceGroups/registries-rg/providers/Microsoft.ContainerRegistry/registries/myregistry"
+		roleAssignmentSpecs  = []azure.RoleAssignmentSpec{
+			{
+				Name:               "3a8c5a0e-0000-0000-0000-000000000000",
+				ResourceType:       azure.UserAssignedIdentity,
+				IdentityResourceID: controlPlaneIdentity,
+				Scope:              subnet,
+				RoleDefinitionID:   azure.NetworkContributorRoleID,
+			},
+			{
+				Name:               "7d1c9b4f-0000-0000-0000-000000000000",
+				ResourceType:       azure.UserAssignedIdentity,
+				IdentityResourceID: kubeletIdentity,
+				Scope:              registry,
+				RoleDefinitionID:   azure.AcrPullRoleID,
+			},
+		}
+	)
+
+	testcases := []struct {
+		name          string
+		expect        func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, m *mock_roleassignments.MockclientMockRecorder, i *mock_identities.MockClientMockRecorder)
+		expectedError string
+	}{
+		{
+			name:          "create role assignments in the subscription of their scope",
+			expectedError: "",
+			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, m *mock_roleassignments.MockclientMockRecorder, i *mock_identities.MockClientMockRecorder) {
+				s.RoleAssignmentSpecs().Return(roleAssignmentSpecs)
+				i.GetPrincipalID(gomockinternal.AContext(), controlPlaneIdentity).Return("111", nil)
+				i.GetPrincipalID(gomockinternal.AContext(), kubeletIdentity).Return("222", nil)
+				m.Create(gomockinternal.AContext(), subnet, "3a8c5a0e-0000-0000-0000-000000000000", authorization.RoleAssignmentCreateParameters{
+					Properties: &authorization.RoleAssignmentProperties{
+						RoleDefinitionID: to.StringPtr("/subscriptions/12345/providers/Microsoft.Authorization/roleDefinitions/4d97b98b-1d4f-4787-a291-c67834d212e7"),
+						PrincipalID:      to.StringPtr("111"),
+					},
+				})
+				m.Create(gomockinternal.AContext(), registry, "7d1c9b4f-0000-0000-0000-000000000000", authorization.RoleAssignmentCreateParameters{
+					Properties: &authorization.RoleAssignmentProperties{
+						RoleDefinitionID: to.StringPtr("/subscriptions/67890/providers/Microsoft.Authorization/roleDefinitions/7f951dda-4ed3-4680-a7ca-43fe172d538d"),
+						PrincipalID:      to.StringPtr("222"),
+					},
+				})
+			},
+		},
+		{
+			name:          "ignore roles that are already assigned",
+			expectedError: "",
+			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, m *mock_roleassignments.MockclientMockRecorder, i *mock_identities.MockClientMockRecorder) {
+				s.RoleAssignmentSpecs().Return(roleAssignmentSpecs)
+				i.GetPrincipalID(gomockinternal.AContext(), controlPlaneIdentity).Return("111", nil)
+				i.GetPrincipalID(gomockinternal.AContext(), kubeletIdentity).Return("222", nil)
+				m.Create(gomockinternal.AContext(), subnet, gomock.Any(), gomock.Any()).Return(authorization.RoleAssignment{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 409}, "Conflict"))
+				m.Create(gomockinternal.AContext(), registry, gomock.Any(), gomock.Any())
+			},
+		},
+		{
+			name:          "error getting the user-assigned identity",
+			expectedError: "cannot get user-assigned identity " + controlPlaneIdentity + " to assign role to: #: Not Found: StatusCode=404",
+			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, m *mock_roleassignments.MockclientMockRecorder, i *mock_identities.MockClientMockRecorder) {
+				s.RoleAssignmentSpecs().Return(roleAssignmentSpecs)
+				i.GetPrincipalID(gomockinternal.AContext(), controlPlaneIdentity).Return("", autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not Found"))
+			},
+		},
+		{
+			name:          "return error when creating a role assignment",
+			expectedError: "cannot assign role to user-assigned identity " + controlPlaneIdentity + " on " + subnet + ": #: Internal Server Error: StatusCode=500",
+			expect: func(s *mock_roleassignments.MockRoleAssignmentScopeMockRecorder, m *mock_roleassignments.MockclientMockRecorder, i *mock_identities.MockClientMockRecorder) {
+				s.RoleAssignmentSpecs().Return(roleAssignmentSpecs)
+				i.GetPrincipalID(gomockinternal.AContext(), controlPlaneIdentity).Return("111", nil)
+				m.Create(gomockinternal.AContext(), subnet, gomock.Any(), gomock.Any()).Return(authorization.RoleAssignment{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 500}, "Internal Server Error"))
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			scopeMock := mock_roleassignments.NewMockRoleAssignmentScope(mockCtrl)
+			clientMock := mock_roleassignments.NewMockclient(mockCtrl)
+			identitiesMock := mock_identities.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT(), identitiesMock.EXPECT())
+
+			s := &Service{
+				Scope:            scopeMock,
+				client:           clientMock,
+				identitiesClient: identitiesMock,
+			}
+
+			err := s.Reconcile(context.TODO())
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
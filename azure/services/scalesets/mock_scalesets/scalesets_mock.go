@@ -250,6 +250,21 @@ func (mr *MockScaleSetScopeMockRecorder) GetVMImage(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVMImage", reflect.TypeOf((*MockScaleSetScope)(nil).GetVMImage), arg0)
 }
 
+// HasBootstrapDataChanges mocks base method.
+func (m *MockScaleSetScope) HasBootstrapDataChanges(arg0 context.Context) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasBootstrapDataChanges", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasBootstrapDataChanges indicates an expected call of HasBootstrapDataChanges.
+func (mr *MockScaleSetScopeMockRecorder) HasBootstrapDataChanges(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasBootstrapDataChanges", reflect.TypeOf((*MockScaleSetScope)(nil).HasBootstrapDataChanges), arg0)
+}
+
 // HashKey mocks base method.
 func (m *MockScaleSetScope) HashKey() string {
 	m.ctrl.T.Helper()
@@ -307,6 +322,20 @@ func (mr *MockScaleSetScopeMockRecorder) ResourceGroup() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockScaleSetScope)(nil).ResourceGroup))
 }
 
+// SaveBootstrapDataHash mocks base method.
+func (m *MockScaleSetScope) SaveBootstrapDataHash(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveBootstrapDataHash", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveBootstrapDataHash indicates an expected call of SaveBootstrapDataHash.
+func (mr *MockScaleSetScopeMockRecorder) SaveBootstrapDataHash(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBootstrapDataHash", reflect.TypeOf((*MockScaleSetScope)(nil).SaveBootstrapDataHash), arg0)
+}
+
 // SaveVMImageToStatus mocks base method.
 func (m *MockScaleSetScope) SaveVMImageToStatus(arg0 *v1beta1.Image) {
 	m.ctrl.T.Helper()
@@ -446,15 +475,1008 @@ func (mr *MockScaleSetScopeMockRecorder) UpdatePutStatus(arg0, arg1, arg2 interf
 }
 
 // VMSSExtensionSpecs mocks base method.
-func (m *MockScaleSetScope) VMSSExtensionSpecs() []azure.ExtensionSpec {
+func (m *MockScaleSetScope) VMSSExtensionSpecs(arg0 context.Context) ([]azure.ExtensionSpec, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "VMSSExtensionSpecs")
+	ret := m.ctrl.Call(m, "VMSSExtensionSpecs", arg0)
 	ret0, _ := ret[0].([]azure.ExtensionSpec)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VMSSExtensionSpecs indicates an expected call of VMSSExtensionSpecs.
+func (mr *MockScaleSetScopeMockRecorder) VMSSExtensionSpecs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VMSSExtensionSpecs", reflect.TypeOf((*MockScaleSetScope)(nil).VMSSExtensionSpecs), arg0)
+}
+
+// MockMixedInstancesScope is a mock of MixedInstancesScope interface.
+type MockMixedInstancesScope struct {
+	ctrl     *gomock.Controller
+	recorder *MockMixedInstancesScopeMockRecorder
+}
+
+// MockMixedInstancesScopeMockRecorder is the mock recorder for MockMixedInstancesScope.
+type MockMixedInstancesScopeMockRecorder struct {
+	mock *MockMixedInstancesScope
+}
+
+// NewMockMixedInstancesScope creates a new mock instance.
+func NewMockMixedInstancesScope(ctrl *gomock.Controller) *MockMixedInstancesScope {
+	mock := &MockMixedInstancesScope{ctrl: ctrl}
+	mock.recorder = &MockMixedInstancesScopeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMixedInstancesScope) EXPECT() *MockMixedInstancesScopeMockRecorder {
+	return m.recorder
+}
+
+// AdditionalTags mocks base method.
+func (m *MockMixedInstancesScope) AdditionalTags() v1beta1.Tags {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdditionalTags")
+	ret0, _ := ret[0].(v1beta1.Tags)
+	return ret0
+}
+
+// AdditionalTags indicates an expected call of AdditionalTags.
+func (mr *MockMixedInstancesScopeMockRecorder) AdditionalTags() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdditionalTags", reflect.TypeOf((*MockMixedInstancesScope)(nil).AdditionalTags))
+}
+
+// Authorizer mocks base method.
+func (m *MockMixedInstancesScope) Authorizer() autorest.Authorizer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorizer")
+	ret0, _ := ret[0].(autorest.Authorizer)
+	return ret0
+}
+
+// Authorizer indicates an expected call of Authorizer.
+func (mr *MockMixedInstancesScopeMockRecorder) Authorizer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorizer", reflect.TypeOf((*MockMixedInstancesScope)(nil).Authorizer))
+}
+
+// AvailabilitySetEnabled mocks base method.
+func (m *MockMixedInstancesScope) AvailabilitySetEnabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AvailabilitySetEnabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// AvailabilitySetEnabled indicates an expected call of AvailabilitySetEnabled.
+func (mr *MockMixedInstancesScopeMockRecorder) AvailabilitySetEnabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AvailabilitySetEnabled", reflect.TypeOf((*MockMixedInstancesScope)(nil).AvailabilitySetEnabled))
+}
+
+// BaseURI mocks base method.
+func (m *MockMixedInstancesScope) BaseURI() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BaseURI")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BaseURI indicates an expected call of BaseURI.
+func (mr *MockMixedInstancesScopeMockRecorder) BaseURI() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockMixedInstancesScope)(nil).BaseURI))
+}
+
+// ClientID mocks base method.
+func (m *MockMixedInstancesScope) ClientID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientID indicates an expected call of ClientID.
+func (mr *MockMixedInstancesScopeMockRecorder) ClientID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientID", reflect.TypeOf((*MockMixedInstancesScope)(nil).ClientID))
+}
+
+// ClientSecret mocks base method.
+func (m *MockMixedInstancesScope) ClientSecret() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientSecret")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientSecret indicates an expected call of ClientSecret.
+func (mr *MockMixedInstancesScopeMockRecorder) ClientSecret() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientSecret", reflect.TypeOf((*MockMixedInstancesScope)(nil).ClientSecret))
+}
+
+// CloudEnvironment mocks base method.
+func (m *MockMixedInstancesScope) CloudEnvironment() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloudEnvironment")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CloudEnvironment indicates an expected call of CloudEnvironment.
+func (mr *MockMixedInstancesScopeMockRecorder) CloudEnvironment() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloudEnvironment", reflect.TypeOf((*MockMixedInstancesScope)(nil).CloudEnvironment))
+}
+
+// CloudProviderConfigOverrides mocks base method.
+func (m *MockMixedInstancesScope) CloudProviderConfigOverrides() *v1beta1.CloudProviderConfigOverrides {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloudProviderConfigOverrides")
+	ret0, _ := ret[0].(*v1beta1.CloudProviderConfigOverrides)
+	return ret0
+}
+
+// CloudProviderConfigOverrides indicates an expected call of CloudProviderConfigOverrides.
+func (mr *MockMixedInstancesScopeMockRecorder) CloudProviderConfigOverrides() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloudProviderConfigOverrides", reflect.TypeOf((*MockMixedInstancesScope)(nil).CloudProviderConfigOverrides))
+}
+
+// ClusterName mocks base method.
+func (m *MockMixedInstancesScope) ClusterName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClusterName")
+	ret0, _ := ret[0].(string)
 	return ret0
 }
 
+// ClusterName indicates an expected call of ClusterName.
+func (mr *MockMixedInstancesScopeMockRecorder) ClusterName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClusterName", reflect.TypeOf((*MockMixedInstancesScope)(nil).ClusterName))
+}
+
+// DeleteLongRunningOperationState mocks base method.
+func (m *MockMixedInstancesScope) DeleteLongRunningOperationState(arg0, arg1 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteLongRunningOperationState", arg0, arg1)
+}
+
+// DeleteLongRunningOperationState indicates an expected call of DeleteLongRunningOperationState.
+func (mr *MockMixedInstancesScopeMockRecorder) DeleteLongRunningOperationState(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLongRunningOperationState", reflect.TypeOf((*MockMixedInstancesScope)(nil).DeleteLongRunningOperationState), arg0, arg1)
+}
+
+// FailureDomains mocks base method.
+func (m *MockMixedInstancesScope) FailureDomains() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailureDomains")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// FailureDomains indicates an expected call of FailureDomains.
+func (mr *MockMixedInstancesScopeMockRecorder) FailureDomains() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailureDomains", reflect.TypeOf((*MockMixedInstancesScope)(nil).FailureDomains))
+}
+
+// GetBootstrapData mocks base method.
+func (m *MockMixedInstancesScope) GetBootstrapData(arg0 context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBootstrapData", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBootstrapData indicates an expected call of GetBootstrapData.
+func (mr *MockMixedInstancesScopeMockRecorder) GetBootstrapData(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBootstrapData", reflect.TypeOf((*MockMixedInstancesScope)(nil).GetBootstrapData), arg0)
+}
+
+// GetLongRunningOperationState mocks base method.
+func (m *MockMixedInstancesScope) GetLongRunningOperationState(arg0, arg1 string) *v1beta1.Future {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLongRunningOperationState", arg0, arg1)
+	ret0, _ := ret[0].(*v1beta1.Future)
+	return ret0
+}
+
+// GetLongRunningOperationState indicates an expected call of GetLongRunningOperationState.
+func (mr *MockMixedInstancesScopeMockRecorder) GetLongRunningOperationState(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLongRunningOperationState", reflect.TypeOf((*MockMixedInstancesScope)(nil).GetLongRunningOperationState), arg0, arg1)
+}
+
+// GetVMImage mocks base method.
+func (m *MockMixedInstancesScope) GetVMImage(arg0 context.Context) (*v1beta1.Image, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVMImage", arg0)
+	ret0, _ := ret[0].(*v1beta1.Image)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVMImage indicates an expected call of GetVMImage.
+func (mr *MockMixedInstancesScopeMockRecorder) GetVMImage(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVMImage", reflect.TypeOf((*MockMixedInstancesScope)(nil).GetVMImage), arg0)
+}
+
+// HasBootstrapDataChanges mocks base method.
+func (m *MockMixedInstancesScope) HasBootstrapDataChanges(arg0 context.Context) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasBootstrapDataChanges", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasBootstrapDataChanges indicates an expected call of HasBootstrapDataChanges.
+func (mr *MockMixedInstancesScopeMockRecorder) HasBootstrapDataChanges(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasBootstrapDataChanges", reflect.TypeOf((*MockMixedInstancesScope)(nil).HasBootstrapDataChanges), arg0)
+}
+
+// HashKey mocks base method.
+func (m *MockMixedInstancesScope) HashKey() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HashKey")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// HashKey indicates an expected call of HashKey.
+func (mr *MockMixedInstancesScopeMockRecorder) HashKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashKey", reflect.TypeOf((*MockMixedInstancesScope)(nil).HashKey))
+}
+
+// Location mocks base method.
+func (m *MockMixedInstancesScope) Location() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Location")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Location indicates an expected call of Location.
+func (mr *MockMixedInstancesScopeMockRecorder) Location() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockMixedInstancesScope)(nil).Location))
+}
+
+// MaxSurge mocks base method.
+func (m *MockMixedInstancesScope) MaxSurge() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MaxSurge")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MaxSurge indicates an expected call of MaxSurge.
+func (mr *MockMixedInstancesScopeMockRecorder) MaxSurge() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaxSurge", reflect.TypeOf((*MockMixedInstancesScope)(nil).MaxSurge))
+}
+
+// ResourceGroup mocks base method.
+func (m *MockMixedInstancesScope) ResourceGroup() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResourceGroup")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ResourceGroup indicates an expected call of ResourceGroup.
+func (mr *MockMixedInstancesScopeMockRecorder) ResourceGroup() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockMixedInstancesScope)(nil).ResourceGroup))
+}
+
+// SaveBootstrapDataHash mocks base method.
+func (m *MockMixedInstancesScope) SaveBootstrapDataHash(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveBootstrapDataHash", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveBootstrapDataHash indicates an expected call of SaveBootstrapDataHash.
+func (mr *MockMixedInstancesScopeMockRecorder) SaveBootstrapDataHash(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBootstrapDataHash", reflect.TypeOf((*MockMixedInstancesScope)(nil).SaveBootstrapDataHash), arg0)
+}
+
+// SaveVMImageToStatus mocks base method.
+func (m *MockMixedInstancesScope) SaveVMImageToStatus(arg0 *v1beta1.Image) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SaveVMImageToStatus", arg0)
+}
+
+// SaveVMImageToStatus indicates an expected call of SaveVMImageToStatus.
+func (mr *MockMixedInstancesScopeMockRecorder) SaveVMImageToStatus(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveVMImageToStatus", reflect.TypeOf((*MockMixedInstancesScope)(nil).SaveVMImageToStatus), arg0)
+}
+
+// ScaleSetSpec mocks base method.
+func (m *MockMixedInstancesScope) ScaleSetSpec() azure.ScaleSetSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScaleSetSpec")
+	ret0, _ := ret[0].(azure.ScaleSetSpec)
+	return ret0
+}
+
+// ScaleSetSpec indicates an expected call of ScaleSetSpec.
+func (mr *MockMixedInstancesScopeMockRecorder) ScaleSetSpec() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScaleSetSpec", reflect.TypeOf((*MockMixedInstancesScope)(nil).ScaleSetSpec))
+}
+
+// ScaleSetSpecs mocks base method.
+func (m *MockMixedInstancesScope) ScaleSetSpecs() []azure.ScaleSetSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScaleSetSpecs")
+	ret0, _ := ret[0].([]azure.ScaleSetSpec)
+	return ret0
+}
+
+// ScaleSetSpecs indicates an expected call of ScaleSetSpecs.
+func (mr *MockMixedInstancesScopeMockRecorder) ScaleSetSpecs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScaleSetSpecs", reflect.TypeOf((*MockMixedInstancesScope)(nil).ScaleSetSpecs))
+}
+
+// SetAnnotation mocks base method.
+func (m *MockMixedInstancesScope) SetAnnotation(arg0, arg1 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetAnnotation", arg0, arg1)
+}
+
+// SetAnnotation indicates an expected call of SetAnnotation.
+func (mr *MockMixedInstancesScopeMockRecorder) SetAnnotation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAnnotation", reflect.TypeOf((*MockMixedInstancesScope)(nil).SetAnnotation), arg0, arg1)
+}
+
+// SetLongRunningOperationState mocks base method.
+func (m *MockMixedInstancesScope) SetLongRunningOperationState(arg0 *v1beta1.Future) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetLongRunningOperationState", arg0)
+}
+
+// SetLongRunningOperationState indicates an expected call of SetLongRunningOperationState.
+func (mr *MockMixedInstancesScopeMockRecorder) SetLongRunningOperationState(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLongRunningOperationState", reflect.TypeOf((*MockMixedInstancesScope)(nil).SetLongRunningOperationState), arg0)
+}
+
+// SetProviderID mocks base method.
+func (m *MockMixedInstancesScope) SetProviderID(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetProviderID", arg0)
+}
+
+// SetProviderID indicates an expected call of SetProviderID.
+func (mr *MockMixedInstancesScopeMockRecorder) SetProviderID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProviderID", reflect.TypeOf((*MockMixedInstancesScope)(nil).SetProviderID), arg0)
+}
+
+// SetScaleSetStates mocks base method.
+func (m *MockMixedInstancesScope) SetScaleSetStates(arg0 []*azure.VMSS) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetScaleSetStates", arg0)
+}
+
+// SetScaleSetStates indicates an expected call of SetScaleSetStates.
+func (mr *MockMixedInstancesScopeMockRecorder) SetScaleSetStates(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetScaleSetStates", reflect.TypeOf((*MockMixedInstancesScope)(nil).SetScaleSetStates), arg0)
+}
+
+// SetScaleSetUnavailable mocks base method.
+func (m *MockMixedInstancesScope) SetScaleSetUnavailable(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetScaleSetUnavailable", arg0)
+}
+
+// SetScaleSetUnavailable indicates an expected call of SetScaleSetUnavailable.
+func (mr *MockMixedInstancesScopeMockRecorder) SetScaleSetUnavailable(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetScaleSetUnavailable", reflect.TypeOf((*MockMixedInstancesScope)(nil).SetScaleSetUnavailable), arg0)
+}
+
+// SetVMSSState mocks base method.
+func (m *MockMixedInstancesScope) SetVMSSState(arg0 *azure.VMSS) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetVMSSState", arg0)
+}
+
+// SetVMSSState indicates an expected call of SetVMSSState.
+func (mr *MockMixedInstancesScopeMockRecorder) SetVMSSState(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVMSSState", reflect.TypeOf((*MockMixedInstancesScope)(nil).SetVMSSState), arg0)
+}
+
+// SubscriptionID mocks base method.
+func (m *MockMixedInstancesScope) SubscriptionID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscriptionID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SubscriptionID indicates an expected call of SubscriptionID.
+func (mr *MockMixedInstancesScopeMockRecorder) SubscriptionID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscriptionID", reflect.TypeOf((*MockMixedInstancesScope)(nil).SubscriptionID))
+}
+
+// TenantID mocks base method.
+func (m *MockMixedInstancesScope) TenantID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TenantID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// TenantID indicates an expected call of TenantID.
+func (mr *MockMixedInstancesScopeMockRecorder) TenantID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockMixedInstancesScope)(nil).TenantID))
+}
+
+// UpdateDeleteStatus mocks base method.
+func (m *MockMixedInstancesScope) UpdateDeleteStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateDeleteStatus", arg0, arg1, arg2)
+}
+
+// UpdateDeleteStatus indicates an expected call of UpdateDeleteStatus.
+func (mr *MockMixedInstancesScopeMockRecorder) UpdateDeleteStatus(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDeleteStatus", reflect.TypeOf((*MockMixedInstancesScope)(nil).UpdateDeleteStatus), arg0, arg1, arg2)
+}
+
+// UpdatePatchStatus mocks base method.
+func (m *MockMixedInstancesScope) UpdatePatchStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdatePatchStatus", arg0, arg1, arg2)
+}
+
+// UpdatePatchStatus indicates an expected call of UpdatePatchStatus.
+func (mr *MockMixedInstancesScopeMockRecorder) UpdatePatchStatus(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePatchStatus", reflect.TypeOf((*MockMixedInstancesScope)(nil).UpdatePatchStatus), arg0, arg1, arg2)
+}
+
+// UpdatePutStatus mocks base method.
+func (m *MockMixedInstancesScope) UpdatePutStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdatePutStatus", arg0, arg1, arg2)
+}
+
+// UpdatePutStatus indicates an expected call of UpdatePutStatus.
+func (mr *MockMixedInstancesScopeMockRecorder) UpdatePutStatus(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePutStatus", reflect.TypeOf((*MockMixedInstancesScope)(nil).UpdatePutStatus), arg0, arg1, arg2)
+}
+
+// VMSSExtensionSpecs mocks base method.
+func (m *MockMixedInstancesScope) VMSSExtensionSpecs(arg0 context.Context) ([]azure.ExtensionSpec, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VMSSExtensionSpecs", arg0)
+	ret0, _ := ret[0].([]azure.ExtensionSpec)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VMSSExtensionSpecs indicates an expected call of VMSSExtensionSpecs.
+func (mr *MockMixedInstancesScopeMockRecorder) VMSSExtensionSpecs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VMSSExtensionSpecs", reflect.TypeOf((*MockMixedInstancesScope)(nil).VMSSExtensionSpecs), arg0)
+}
+
+// MockBlueGreenScope is a mock of BlueGreenScope interface.
+type MockBlueGreenScope struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlueGreenScopeMockRecorder
+}
+
+// MockBlueGreenScopeMockRecorder is the mock recorder for MockBlueGreenScope.
+type MockBlueGreenScopeMockRecorder struct {
+	mock *MockBlueGreenScope
+}
+
+// NewMockBlueGreenScope creates a new mock instance.
+func NewMockBlueGreenScope(ctrl *gomock.Controller) *MockBlueGreenScope {
+	mock := &MockBlueGreenScope{ctrl: ctrl}
+	mock.recorder = &MockBlueGreenScopeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlueGreenScope) EXPECT() *MockBlueGreenScopeMockRecorder {
+	return m.recorder
+}
+
+// AdditionalTags mocks base method.
+func (m *MockBlueGreenScope) AdditionalTags() v1beta1.Tags {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdditionalTags")
+	ret0, _ := ret[0].(v1beta1.Tags)
+	return ret0
+}
+
+// AdditionalTags indicates an expected call of AdditionalTags.
+func (mr *MockBlueGreenScopeMockRecorder) AdditionalTags() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdditionalTags", reflect.TypeOf((*MockBlueGreenScope)(nil).AdditionalTags))
+}
+
+// Authorizer mocks base method.
+func (m *MockBlueGreenScope) Authorizer() autorest.Authorizer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorizer")
+	ret0, _ := ret[0].(autorest.Authorizer)
+	return ret0
+}
+
+// Authorizer indicates an expected call of Authorizer.
+func (mr *MockBlueGreenScopeMockRecorder) Authorizer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorizer", reflect.TypeOf((*MockBlueGreenScope)(nil).Authorizer))
+}
+
+// AvailabilitySetEnabled mocks base method.
+func (m *MockBlueGreenScope) AvailabilitySetEnabled() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AvailabilitySetEnabled")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// AvailabilitySetEnabled indicates an expected call of AvailabilitySetEnabled.
+func (mr *MockBlueGreenScopeMockRecorder) AvailabilitySetEnabled() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AvailabilitySetEnabled", reflect.TypeOf((*MockBlueGreenScope)(nil).AvailabilitySetEnabled))
+}
+
+// BaseURI mocks base method.
+func (m *MockBlueGreenScope) BaseURI() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BaseURI")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BaseURI indicates an expected call of BaseURI.
+func (mr *MockBlueGreenScopeMockRecorder) BaseURI() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BaseURI", reflect.TypeOf((*MockBlueGreenScope)(nil).BaseURI))
+}
+
+// BlueGreenScaleSetSpecs mocks base method.
+func (m *MockBlueGreenScope) BlueGreenScaleSetSpecs() []azure.ScaleSetSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlueGreenScaleSetSpecs")
+	ret0, _ := ret[0].([]azure.ScaleSetSpec)
+	return ret0
+}
+
+// BlueGreenScaleSetSpecs indicates an expected call of BlueGreenScaleSetSpecs.
+func (mr *MockBlueGreenScopeMockRecorder) BlueGreenScaleSetSpecs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlueGreenScaleSetSpecs", reflect.TypeOf((*MockBlueGreenScope)(nil).BlueGreenScaleSetSpecs))
+}
+
+// ClientID mocks base method.
+func (m *MockBlueGreenScope) ClientID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientID indicates an expected call of ClientID.
+func (mr *MockBlueGreenScopeMockRecorder) ClientID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientID", reflect.TypeOf((*MockBlueGreenScope)(nil).ClientID))
+}
+
+// ClientSecret mocks base method.
+func (m *MockBlueGreenScope) ClientSecret() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientSecret")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientSecret indicates an expected call of ClientSecret.
+func (mr *MockBlueGreenScopeMockRecorder) ClientSecret() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientSecret", reflect.TypeOf((*MockBlueGreenScope)(nil).ClientSecret))
+}
+
+// CloudEnvironment mocks base method.
+func (m *MockBlueGreenScope) CloudEnvironment() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloudEnvironment")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CloudEnvironment indicates an expected call of CloudEnvironment.
+func (mr *MockBlueGreenScopeMockRecorder) CloudEnvironment() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloudEnvironment", reflect.TypeOf((*MockBlueGreenScope)(nil).CloudEnvironment))
+}
+
+// CloudProviderConfigOverrides mocks base method.
+func (m *MockBlueGreenScope) CloudProviderConfigOverrides() *v1beta1.CloudProviderConfigOverrides {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloudProviderConfigOverrides")
+	ret0, _ := ret[0].(*v1beta1.CloudProviderConfigOverrides)
+	return ret0
+}
+
+// CloudProviderConfigOverrides indicates an expected call of CloudProviderConfigOverrides.
+func (mr *MockBlueGreenScopeMockRecorder) CloudProviderConfigOverrides() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloudProviderConfigOverrides", reflect.TypeOf((*MockBlueGreenScope)(nil).CloudProviderConfigOverrides))
+}
+
+// ClusterName mocks base method.
+func (m *MockBlueGreenScope) ClusterName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClusterName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClusterName indicates an expected call of ClusterName.
+func (mr *MockBlueGreenScopeMockRecorder) ClusterName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClusterName", reflect.TypeOf((*MockBlueGreenScope)(nil).ClusterName))
+}
+
+// DeleteLongRunningOperationState mocks base method.
+func (m *MockBlueGreenScope) DeleteLongRunningOperationState(arg0, arg1 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteLongRunningOperationState", arg0, arg1)
+}
+
+// DeleteLongRunningOperationState indicates an expected call of DeleteLongRunningOperationState.
+func (mr *MockBlueGreenScopeMockRecorder) DeleteLongRunningOperationState(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLongRunningOperationState", reflect.TypeOf((*MockBlueGreenScope)(nil).DeleteLongRunningOperationState), arg0, arg1)
+}
+
+// FailureDomains mocks base method.
+func (m *MockBlueGreenScope) FailureDomains() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailureDomains")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// FailureDomains indicates an expected call of FailureDomains.
+func (mr *MockBlueGreenScopeMockRecorder) FailureDomains() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailureDomains", reflect.TypeOf((*MockBlueGreenScope)(nil).FailureDomains))
+}
+
+// GetBootstrapData mocks base method.
+func (m *MockBlueGreenScope) GetBootstrapData(arg0 context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBootstrapData", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBootstrapData indicates an expected call of GetBootstrapData.
+func (mr *MockBlueGreenScopeMockRecorder) GetBootstrapData(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBootstrapData", reflect.TypeOf((*MockBlueGreenScope)(nil).GetBootstrapData), arg0)
+}
+
+// GetLongRunningOperationState mocks base method.
+func (m *MockBlueGreenScope) GetLongRunningOperationState(arg0, arg1 string) *v1beta1.Future {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLongRunningOperationState", arg0, arg1)
+	ret0, _ := ret[0].(*v1beta1.Future)
+	return ret0
+}
+
+// GetLongRunningOperationState indicates an expected call of GetLongRunningOperationState.
+func (mr *MockBlueGreenScopeMockRecorder) GetLongRunningOperationState(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLongRunningOperationState", reflect.TypeOf((*MockBlueGreenScope)(nil).GetLongRunningOperationState), arg0, arg1)
+}
+
+// GetVMImage mocks base method.
+func (m *MockBlueGreenScope) GetVMImage(arg0 context.Context) (*v1beta1.Image, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVMImage", arg0)
+	ret0, _ := ret[0].(*v1beta1.Image)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVMImage indicates an expected call of GetVMImage.
+func (mr *MockBlueGreenScopeMockRecorder) GetVMImage(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVMImage", reflect.TypeOf((*MockBlueGreenScope)(nil).GetVMImage), arg0)
+}
+
+// HasBootstrapDataChanges mocks base method.
+func (m *MockBlueGreenScope) HasBootstrapDataChanges(arg0 context.Context) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasBootstrapDataChanges", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasBootstrapDataChanges indicates an expected call of HasBootstrapDataChanges.
+func (mr *MockBlueGreenScopeMockRecorder) HasBootstrapDataChanges(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasBootstrapDataChanges", reflect.TypeOf((*MockBlueGreenScope)(nil).HasBootstrapDataChanges), arg0)
+}
+
+// HashKey mocks base method.
+func (m *MockBlueGreenScope) HashKey() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HashKey")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// HashKey indicates an expected call of HashKey.
+func (mr *MockBlueGreenScopeMockRecorder) HashKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashKey", reflect.TypeOf((*MockBlueGreenScope)(nil).HashKey))
+}
+
+// Location mocks base method.
+func (m *MockBlueGreenScope) Location() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Location")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Location indicates an expected call of Location.
+func (mr *MockBlueGreenScopeMockRecorder) Location() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockBlueGreenScope)(nil).Location))
+}
+
+// MaxSurge mocks base method.
+func (m *MockBlueGreenScope) MaxSurge() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MaxSurge")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MaxSurge indicates an expected call of MaxSurge.
+func (mr *MockBlueGreenScopeMockRecorder) MaxSurge() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MaxSurge", reflect.TypeOf((*MockBlueGreenScope)(nil).MaxSurge))
+}
+
+// ResourceGroup mocks base method.
+func (m *MockBlueGreenScope) ResourceGroup() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResourceGroup")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ResourceGroup indicates an expected call of ResourceGroup.
+func (mr *MockBlueGreenScopeMockRecorder) ResourceGroup() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResourceGroup", reflect.TypeOf((*MockBlueGreenScope)(nil).ResourceGroup))
+}
+
+// SaveBootstrapDataHash mocks base method.
+func (m *MockBlueGreenScope) SaveBootstrapDataHash(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveBootstrapDataHash", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveBootstrapDataHash indicates an expected call of SaveBootstrapDataHash.
+func (mr *MockBlueGreenScopeMockRecorder) SaveBootstrapDataHash(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBootstrapDataHash", reflect.TypeOf((*MockBlueGreenScope)(nil).SaveBootstrapDataHash), arg0)
+}
+
+// SaveVMImageToStatus mocks base method.
+func (m *MockBlueGreenScope) SaveVMImageToStatus(arg0 *v1beta1.Image) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SaveVMImageToStatus", arg0)
+}
+
+// SaveVMImageToStatus indicates an expected call of SaveVMImageToStatus.
+func (mr *MockBlueGreenScopeMockRecorder) SaveVMImageToStatus(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveVMImageToStatus", reflect.TypeOf((*MockBlueGreenScope)(nil).SaveVMImageToStatus), arg0)
+}
+
+// ScaleSetSpec mocks base method.
+func (m *MockBlueGreenScope) ScaleSetSpec() azure.ScaleSetSpec {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScaleSetSpec")
+	ret0, _ := ret[0].(azure.ScaleSetSpec)
+	return ret0
+}
+
+// ScaleSetSpec indicates an expected call of ScaleSetSpec.
+func (mr *MockBlueGreenScopeMockRecorder) ScaleSetSpec() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScaleSetSpec", reflect.TypeOf((*MockBlueGreenScope)(nil).ScaleSetSpec))
+}
+
+// SetAnnotation mocks base method.
+func (m *MockBlueGreenScope) SetAnnotation(arg0, arg1 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetAnnotation", arg0, arg1)
+}
+
+// SetAnnotation indicates an expected call of SetAnnotation.
+func (mr *MockBlueGreenScopeMockRecorder) SetAnnotation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAnnotation", reflect.TypeOf((*MockBlueGreenScope)(nil).SetAnnotation), arg0, arg1)
+}
+
+// SetLongRunningOperationState mocks base method.
+func (m *MockBlueGreenScope) SetLongRunningOperationState(arg0 *v1beta1.Future) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetLongRunningOperationState", arg0)
+}
+
+// SetLongRunningOperationState indicates an expected call of SetLongRunningOperationState.
+func (mr *MockBlueGreenScopeMockRecorder) SetLongRunningOperationState(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLongRunningOperationState", reflect.TypeOf((*MockBlueGreenScope)(nil).SetLongRunningOperationState), arg0)
+}
+
+// SetProviderID mocks base method.
+func (m *MockBlueGreenScope) SetProviderID(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetProviderID", arg0)
+}
+
+// SetProviderID indicates an expected call of SetProviderID.
+func (mr *MockBlueGreenScopeMockRecorder) SetProviderID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProviderID", reflect.TypeOf((*MockBlueGreenScope)(nil).SetProviderID), arg0)
+}
+
+// SetScaleSetDeleted mocks base method.
+func (m *MockBlueGreenScope) SetScaleSetDeleted(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetScaleSetDeleted", arg0)
+}
+
+// SetScaleSetDeleted indicates an expected call of SetScaleSetDeleted.
+func (mr *MockBlueGreenScopeMockRecorder) SetScaleSetDeleted(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetScaleSetDeleted", reflect.TypeOf((*MockBlueGreenScope)(nil).SetScaleSetDeleted), arg0)
+}
+
+// SetScaleSetStates mocks base method.
+func (m *MockBlueGreenScope) SetScaleSetStates(arg0 []*azure.VMSS) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetScaleSetStates", arg0)
+}
+
+// SetScaleSetStates indicates an expected call of SetScaleSetStates.
+func (mr *MockBlueGreenScopeMockRecorder) SetScaleSetStates(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetScaleSetStates", reflect.TypeOf((*MockBlueGreenScope)(nil).SetScaleSetStates), arg0)
+}
+
+// SetVMSSState mocks base method.
+func (m *MockBlueGreenScope) SetVMSSState(arg0 *azure.VMSS) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetVMSSState", arg0)
+}
+
+// SetVMSSState indicates an expected call of SetVMSSState.
+func (mr *MockBlueGreenScopeMockRecorder) SetVMSSState(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVMSSState", reflect.TypeOf((*MockBlueGreenScope)(nil).SetVMSSState), arg0)
+}
+
+// SubscriptionID mocks base method.
+func (m *MockBlueGreenScope) SubscriptionID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscriptionID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SubscriptionID indicates an expected call of SubscriptionID.
+func (mr *MockBlueGreenScopeMockRecorder) SubscriptionID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscriptionID", reflect.TypeOf((*MockBlueGreenScope)(nil).SubscriptionID))
+}
+
+// TenantID mocks base method.
+func (m *MockBlueGreenScope) TenantID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TenantID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// TenantID indicates an expected call of TenantID.
+func (mr *MockBlueGreenScopeMockRecorder) TenantID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockBlueGreenScope)(nil).TenantID))
+}
+
+// UpdateDeleteStatus mocks base method.
+func (m *MockBlueGreenScope) UpdateDeleteStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateDeleteStatus", arg0, arg1, arg2)
+}
+
+// UpdateDeleteStatus indicates an expected call of UpdateDeleteStatus.
+func (mr *MockBlueGreenScopeMockRecorder) UpdateDeleteStatus(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDeleteStatus", reflect.TypeOf((*MockBlueGreenScope)(nil).UpdateDeleteStatus), arg0, arg1, arg2)
+}
+
+// UpdatePatchStatus mocks base method.
+func (m *MockBlueGreenScope) UpdatePatchStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdatePatchStatus", arg0, arg1, arg2)
+}
+
+// UpdatePatchStatus indicates an expected call of UpdatePatchStatus.
+func (mr *MockBlueGreenScopeMockRecorder) UpdatePatchStatus(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePatchStatus", reflect.TypeOf((*MockBlueGreenScope)(nil).UpdatePatchStatus), arg0, arg1, arg2)
+}
+
+// UpdatePutStatus mocks base method.
+func (m *MockBlueGreenScope) UpdatePutStatus(arg0 v1beta10.ConditionType, arg1 string, arg2 error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdatePutStatus", arg0, arg1, arg2)
+}
+
+// UpdatePutStatus indicates an expected call of UpdatePutStatus.
+func (mr *MockBlueGreenScopeMockRecorder) UpdatePutStatus(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePutStatus", reflect.TypeOf((*MockBlueGreenScope)(nil).UpdatePutStatus), arg0, arg1, arg2)
+}
+
+// VMSSExtensionSpecs mocks base method.
+func (m *MockBlueGreenScope) VMSSExtensionSpecs(arg0 context.Context) ([]azure.ExtensionSpec, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VMSSExtensionSpecs", arg0)
+	ret0, _ := ret[0].([]azure.ExtensionSpec)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // VMSSExtensionSpecs indicates an expected call of VMSSExtensionSpecs.
-func (mr *MockScaleSetScopeMockRecorder) VMSSExtensionSpecs() *gomock.Call {
+func (mr *MockBlueGreenScopeMockRecorder) VMSSExtensionSpecs(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VMSSExtensionSpecs", reflect.TypeOf((*MockScaleSetScope)(nil).VMSSExtensionSpecs))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VMSSExtensionSpecs", reflect.TypeOf((*MockBlueGreenScope)(nil).VMSSExtensionSpecs), arg0)
 }
@@ -41,16 +41,36 @@ type (
 		azure.ClusterDescriber
 		azure.AsyncStatusUpdater
 		GetBootstrapData(context.Context) (string, error)
+		HasBootstrapDataChanges(context.Context) (bool, error)
+		SaveBootstrapDataHash(context.Context) error
 		GetVMImage(context.Context) (*infrav1.Image, error)
 		SaveVMImageToStatus(*infrav1.Image)
 		MaxSurge() (int, error)
 		ScaleSetSpec() azure.ScaleSetSpec
-		VMSSExtensionSpecs() []azure.ExtensionSpec
+		VMSSExtensionSpecs(context.Context) ([]azure.ExtensionSpec, error)
 		SetAnnotation(string, string)
 		SetProviderID(string)
 		SetVMSSState(*azure.VMSS)
 	}
 
+	// MixedInstancesScope defines the scope interface for a scale sets service reconciling a machine pool backed by
+	// several scale sets, one for each VM size and priority of its mixed instances policy.
+	MixedInstancesScope interface {
+		ScaleSetScope
+		ScaleSetSpecs() []azure.ScaleSetSpec
+		SetScaleSetUnavailable(string)
+		SetScaleSetStates([]*azure.VMSS)
+	}
+
+	// BlueGreenScope defines the scope interface for a scale sets service reconciling a machine pool backed by several
+	// scale sets, one for each model of a blue/green deployment.
+	BlueGreenScope interface {
+		ScaleSetScope
+		BlueGreenScaleSetSpecs() []azure.ScaleSetSpec
+		SetScaleSetDeleted(string)
+		SetScaleSetStates([]*azure.VMSS)
+	}
+
 	// Service provides operations on Azure resources.
 	Service struct {
 		Scope ScaleSetScope
@@ -73,6 +93,18 @@ func (s *Service) Reconcile(ctx context.Context) (retErr error) {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "scalesets.Service.Reconcile")
 	defer done()
 
+	if blueGreen, ok := s.Scope.(BlueGreenScope); ok {
+		if specs := blueGreen.BlueGreenScaleSetSpecs(); len(specs) > 0 {
+			return s.reconcileBlueGreen(ctx, blueGreen, specs)
+		}
+	}
+
+	if mixed, ok := s.Scope.(MixedInstancesScope); ok {
+		if specs := mixed.ScaleSetSpecs(); len(specs) > 0 {
+			return s.reconcileMixedInstances(ctx, mixed, specs)
+		}
+	}
+
 	if err := s.validateSpec(ctx); err != nil {
 		// do as much early validation as possible to limit calls to Azure
 		return err
@@ -149,6 +181,22 @@ func (s *Service) Delete(ctx context.Context) error {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "scalesets.Service.Delete")
 	defer done()
 
+	if blueGreen, ok := s.Scope.(BlueGreenScope); ok {
+		if specs := blueGreen.BlueGreenScaleSetSpecs(); len(specs) > 0 {
+			states, err := s.deleteScaleSets(ctx, blueGreen, specs)
+			blueGreen.SetScaleSetStates(states)
+			return err
+		}
+	}
+
+	if mixed, ok := s.Scope.(MixedInstancesScope); ok {
+		if specs := mixed.ScaleSetSpecs(); len(specs) > 0 {
+			states, err := s.deleteScaleSets(ctx, mixed, specs)
+			mixed.SetScaleSetStates(states)
+			return err
+		}
+	}
+
 	var err error
 
 	vmssSpec := s.Scope.ScaleSetSpec()
@@ -203,6 +251,247 @@ func (s *Service) Delete(ctx context.Context) error {
 	return nil
 }
 
+// reconcileMixedInstances reconciles each of the scale sets backing a machine pool with a mixed instances policy. A
+// scale set whose VM size is restricted or out of capacity is reported to the scope, so that the replicas it could not
+// provision are moved to the next VM size on the following reconciliation.
+func (s *Service) reconcileMixedInstances(ctx context.Context, mixed MixedInstancesScope, specs []azure.ScaleSetSpec) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scalesets.Service.reconcileMixedInstances")
+	defer done()
+
+	bootstrapDataChanged, err := mixed.HasBootstrapDataChanges(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to compare the bootstrap data with the scale set model")
+	}
+
+	var (
+		states   []*azure.VMSS
+		firstErr error
+	)
+	for _, spec := range specs {
+		if spec.Capacity == 0 && !s.scaleSetExists(ctx, spec.Name) {
+			// do not create a scale set for a VM size that has no replicas
+			continue
+		}
+
+		vmss, err := s.reconcileScaleSet(ctx, mixed, spec, bootstrapDataChanged)
+		if vmss != nil {
+			states = append(states, vmss)
+		}
+
+		if err != nil && azure.CapacityUnavailable(err) {
+			log.Info("VM size is unavailable, failing over to the next VM size", "scale set", spec.Name, "size", spec.Size, "error", err.Error())
+			mixed.SetScaleSetUnavailable(spec.Name)
+			err = azure.WithTransientError(err, 30*time.Second)
+		}
+
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	mixed.SetScaleSetStates(states)
+	if len(states) > 0 {
+		mixed.SetProviderID(azure.ProviderIDPrefix + states[0].ID)
+	}
+
+	if firstErr != nil {
+		return firstErr
+	}
+
+	// the bootstrap data is only recorded once it is applied to all the scale sets of the pool
+	if bootstrapDataChanged {
+		return mixed.SaveBootstrapDataHash(ctx)
+	}
+
+	return nil
+}
+
+// reconcileBlueGreen reconciles each of the scale sets backing a machine pool with a blue/green deployment. A retired
+// scale set which no longer exists is reported to the scope, so that it is no longer tracked.
+func (s *Service) reconcileBlueGreen(ctx context.Context, blueGreen BlueGreenScope, specs []azure.ScaleSetSpec) error {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.Service.reconcileBlueGreen")
+	defer done()
+
+	bootstrapDataChanged, err := blueGreen.HasBootstrapDataChanges(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to compare the bootstrap data with the scale set model")
+	}
+
+	var (
+		states   []*azure.VMSS
+		firstErr error
+	)
+	for _, spec := range specs {
+		if spec.Capacity == 0 && !s.scaleSetExists(ctx, spec.Name) {
+			blueGreen.SetScaleSetDeleted(spec.Name)
+			continue
+		}
+
+		vmss, err := s.reconcileScaleSet(ctx, blueGreen, spec, bootstrapDataChanged)
+		if vmss != nil {
+			states = append(states, vmss)
+		}
+
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	blueGreen.SetScaleSetStates(states)
+	if len(states) > 0 {
+		blueGreen.SetProviderID(azure.ProviderIDPrefix + states[0].ID)
+	}
+
+	if firstErr != nil {
+		return firstErr
+	}
+
+	if bootstrapDataChanged {
+		return blueGreen.SaveBootstrapDataHash(ctx)
+	}
+
+	return nil
+}
+
+// scaleSetExists returns false if the scale set is known not to exist and has no ongoing long running operation.
+func (s *Service) scaleSetExists(ctx context.Context, name string) bool {
+	if s.Scope.GetLongRunningOperationState(name, scope.ScalesetsServiceName) != nil {
+		return true
+	}
+
+	_, err := s.getVirtualMachineScaleSet(ctx, name)
+	return !azure.ResourceNotFound(err)
+}
+
+// reconcileScaleSet reconciles one of the scale sets backing a machine pool, or only observes it if its model must not
+// change, and returns its state.
+func (s *Service) reconcileScaleSet(ctx context.Context, scaleSetScope ScaleSetScope, spec azure.ScaleSetSpec, bootstrapDataChanged bool) (*azure.VMSS, error) {
+	var (
+		specScope = &scaleSetSpecScope{ScaleSetScope: scaleSetScope, spec: spec, bootstrapDataChanged: bootstrapDataChanged}
+		err       error
+	)
+	if spec.ObserveOnly {
+		err = s.forScaleSet(specScope).observe(ctx)
+	} else {
+		err = s.forScaleSet(specScope).Reconcile(ctx)
+	}
+
+	return specScope.vmss, err
+}
+
+// observe gets the state of a scale set whose model must not change, without creating or updating it. The scale set is
+// deleted once it has no instances left if it has no capacity.
+func (s *Service) observe(ctx context.Context) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scalesets.Service.observe")
+	defer done()
+
+	var (
+		spec   = s.Scope.ScaleSetSpec()
+		future = s.Scope.GetLongRunningOperationState(spec.Name, scope.ScalesetsServiceName)
+		vmss   *azure.VMSS
+		err    error
+	)
+	switch {
+	case future != nil && future.Type == infrav1.DeleteFuture:
+		return s.Delete(ctx)
+	case future != nil:
+		vmss, err = s.getVirtualMachineScaleSetIfDone(ctx, future)
+	default:
+		vmss, err = s.getVirtualMachineScaleSet(ctx, spec.Name)
+	}
+
+	if err != nil {
+		if azure.ResourceNotFound(err) {
+			s.Scope.DeleteLongRunningOperationState(spec.Name, scope.ScalesetsServiceName)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get VMSS %s", spec.Name)
+	}
+
+	s.Scope.DeleteLongRunningOperationState(spec.Name, scope.ScalesetsServiceName)
+	s.Scope.SetVMSSState(vmss)
+
+	if spec.Capacity == 0 && len(vmss.Instances) == 0 {
+		log.V(2).Info("deleting VMSS with no instances left", "scale set", spec.Name)
+		return s.Delete(ctx)
+	}
+
+	return nil
+}
+
+// deleteScaleSets deletes each of the scale sets backing a machine pool with a mixed instances policy or a blue/green
+// deployment, and returns the states of the scale sets which still exist.
+func (s *Service) deleteScaleSets(ctx context.Context, scaleSetScope ScaleSetScope, specs []azure.ScaleSetSpec) ([]*azure.VMSS, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.Service.deleteScaleSets")
+	defer done()
+
+	var (
+		states   []*azure.VMSS
+		firstErr error
+	)
+	for _, spec := range specs {
+		specScope := &scaleSetSpecScope{ScaleSetScope: scaleSetScope, spec: spec}
+		if err := s.forScaleSet(specScope).Delete(ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+
+		if specScope.vmss != nil {
+			states = append(states, specScope.vmss)
+		}
+	}
+
+	return states, firstErr
+}
+
+// forScaleSet returns a service reconciling the single scale set of the scope.
+func (s *Service) forScaleSet(scaleSetScope ScaleSetScope) *Service {
+	return &Service{
+		Scope:            scaleSetScope,
+		Client:           s.Client,
+		resourceSKUCache: s.resourceSKUCache,
+	}
+}
+
+// scaleSetSpecScope scopes a MixedInstancesScope or a BlueGreenScope down to one of the scale sets backing the machine
+// pool. The state of the scale set is kept for the parent scope, which aggregates the states of all the scale sets of
+// the pool.
+type scaleSetSpecScope struct {
+	ScaleSetScope
+	spec                 azure.ScaleSetSpec
+	vmss                 *azure.VMSS
+	bootstrapDataChanged bool
+}
+
+// ScaleSetSpec returns the spec of the scale set.
+func (s *scaleSetSpecScope) ScaleSetSpec() azure.ScaleSetSpec {
+	return s.spec
+}
+
+// MaxSurge returns 0 as the replicas of a pool with a mixed instances policy are rolled out without surging each of its
+// scale sets.
+func (s *scaleSetSpecScope) MaxSurge() (int, error) {
+	return 0, nil
+}
+
+// SetProviderID is a no-op, the provider ID of the pool is set once all of its scale sets are reconciled.
+func (s *scaleSetSpecScope) SetProviderID(string) {}
+
+// HasBootstrapDataChanges returns whether the bootstrap data of the pool changed, as observed before any of its scale
+// sets was reconciled.
+func (s *scaleSetSpecScope) HasBootstrapDataChanges(context.Context) (bool, error) {
+	return s.bootstrapDataChanged, nil
+}
+
+// SaveBootstrapDataHash is a no-op, the bootstrap data of the pool is recorded once all of its scale sets are reconciled.
+func (s *scaleSetSpecScope) SaveBootstrapDataHash(context.Context) error {
+	return nil
+}
+
+// SetVMSSState keeps the state of the scale set.
+func (s *scaleSetSpecScope) SetVMSSState(vmss *azure.VMSS) {
+	s.vmss = vmss
+}
+
 func (s *Service) createVMSS(ctx context.Context) (*infrav1.Future, error) {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "scalesets.Service.createVMSS")
 	defer done()
@@ -221,7 +510,11 @@ func (s *Service) createVMSS(ctx context.Context) (*infrav1.Future, error) {
 
 	log.V(2).Info("starting to create VMSS", "scale set", spec.Name)
 	s.Scope.SetLongRunningOperationState(future)
-	return future, err
+	if err := s.Scope.SaveBootstrapDataHash(ctx); err != nil {
+		return future, errors.Wrap(err, "failed to record the bootstrap data of the VMSS")
+	}
+
+	return future, nil
 }
 
 func (s *Service) patchVMSSIfNeeded(ctx context.Context, infraVMSS *azure.VMSS) (*infrav1.Future, error) {
@@ -245,7 +538,13 @@ func (s *Service) patchVMSSIfNeeded(ctx context.Context, infraVMSS *azure.VMSS)
 		return nil, errors.Wrap(err, "failed to calculate maxSurge")
 	}
 
-	hasModelChanges := hasModelModifyingDifferences(infraVMSS, vmss)
+	bootstrapDataChanged, err := s.Scope.HasBootstrapDataChanges(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to compare the bootstrap data with the scale set model")
+	}
+
+	// a change of the bootstrap data only changes the custom data of the model, which Azure does not return
+	hasModelChanges := hasModelModifyingDifferences(infraVMSS, vmss) || bootstrapDataChanged
 	if maxSurge > 0 && (hasModelChanges || !infraVMSS.HasEnoughLatestModelOrNotMixedModel()) {
 		// surge capacity with the intention of lowering during instance reconciliation
 		surge := spec.Capacity + int64(maxSurge)
@@ -271,7 +570,13 @@ func (s *Service) patchVMSSIfNeeded(ctx context.Context, infraVMSS *azure.VMSS)
 
 	s.Scope.SetLongRunningOperationState(future)
 	log.V(2).Info("successfully started to update vmss", "scale set", spec.Name)
-	return future, err
+	if bootstrapDataChanged {
+		if err := s.Scope.SaveBootstrapDataHash(ctx); err != nil {
+			return future, errors.Wrap(err, "failed to record the bootstrap data of the VMSS")
+		}
+	}
+
+	return future, nil
 }
 
 func hasModelModifyingDifferences(infraVMSS *azure.VMSS, vmss compute.VirtualMachineScaleSet) bool {
@@ -364,7 +669,14 @@ func (s *Service) buildVMSSFromSpec(ctx context.Context, vmssSpec azure.ScaleSet
 		vmssSpec.AcceleratedNetworking = &accelNet
 	}
 
-	extensions := s.generateExtensions()
+	extensions, err := s.generateExtensions(ctx)
+	if err != nil {
+		return compute.VirtualMachineScaleSet{}, err
+	}
+
+	if vmssSpec.ApplicationHealth != nil {
+		extensions = append(extensions, getApplicationHealthExtension(vmssSpec.OSDisk.OSType, *vmssSpec.ApplicationHealth))
+	}
 
 	storageProfile, err := s.generateStorageProfile(ctx, vmssSpec, sku)
 	if err != nil {
@@ -407,20 +719,15 @@ func (s *Service) buildVMSSFromSpec(ctx context.Context, vmssSpec azure.ScaleSet
 		Zones: to.StringSlicePtr(vmssSpec.FailureDomains),
 		Plan:  s.generateImagePlan(ctx),
 		VirtualMachineScaleSetProperties: &compute.VirtualMachineScaleSetProperties{
-			SinglePlacementGroup: to.BoolPtr(false),
-			UpgradePolicy: &compute.UpgradePolicy{
-				Mode: compute.UpgradeModeManual,
-			},
-			Overprovision: to.BoolPtr(false),
+			SinglePlacementGroup:   to.BoolPtr(false),
+			UpgradePolicy:          getUpgradePolicy(vmssSpec.UpgradePolicy),
+			AutomaticRepairsPolicy: getAutomaticRepairsPolicy(vmssSpec.AutomaticRepairsPolicy),
+			Overprovision:          to.BoolPtr(false),
 			VirtualMachineProfile: &compute.VirtualMachineScaleSetVMProfile{
-				OsProfile:       osProfile,
-				StorageProfile:  storageProfile,
-				SecurityProfile: securityProfile,
-				DiagnosticsProfile: &compute.DiagnosticsProfile{
-					BootDiagnostics: &compute.BootDiagnostics{
-						Enabled: to.BoolPtr(true),
-					},
-				},
+				OsProfile:          osProfile,
+				StorageProfile:     storageProfile,
+				SecurityProfile:    securityProfile,
+				DiagnosticsProfile: converters.GetDiagnosticsProfile(vmssSpec.DiagnosticsProfile),
 				NetworkProfile: &compute.VirtualMachineScaleSetNetworkProfile{
 					NetworkInterfaceConfigurations: &[]compute.VirtualMachineScaleSetNetworkConfiguration{
 						{
@@ -472,6 +779,23 @@ func (s *Service) buildVMSSFromSpec(ctx context.Context, vmssSpec azure.ScaleSet
 		}
 	}
 
+	if vmssSpec.PlatformFaultDomainCount != nil {
+		vmss.VirtualMachineScaleSetProperties.PlatformFaultDomainCount = vmssSpec.PlatformFaultDomainCount
+	}
+
+	if vmssSpec.OrchestrationMode == infrav1.FlexibleOrchestrationMode {
+		// Flexible scale sets do not support upgrade policies, overprovisioning or placement groups, and require the
+		// network API version used to create the instance NICs and a platform fault domain count.
+		vmss.OrchestrationMode = compute.OrchestrationModeFlexible
+		vmss.UpgradePolicy = nil
+		vmss.Overprovision = nil
+		vmss.SinglePlacementGroup = nil
+		vmss.VirtualMachineProfile.NetworkProfile.NetworkAPIVersion = compute.NetworkAPIVersionTwoZeroTwoZeroHyphenMinusOneOneHyphenMinusZeroOne
+		if vmss.PlatformFaultDomainCount == nil {
+			vmss.PlatformFaultDomainCount = to.Int32Ptr(1)
+		}
+	}
+
 	for _, dataDisk := range vmssSpec.DataDisks {
 		if dataDisk.ManagedDisk != nil && dataDisk.ManagedDisk.StorageAccountType == string(compute.StorageAccountTypesUltraSSDLRS) {
 			vmss.VirtualMachineScaleSetProperties.AdditionalCapabilities = &compute.AdditionalCapabilities{
@@ -511,12 +835,7 @@ func (s *Service) getVirtualMachineScaleSet(ctx context.Context, vmssName string
 		return nil, errors.Wrap(err, "failed to get existing vmss")
 	}
 
-	vmssInstances, err := s.Client.ListInstances(ctx, s.Scope.ResourceGroup(), vmssName)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to list instances")
-	}
-
-	return converters.SDKToVMSS(vmss, vmssInstances), nil
+	return s.withInstances(ctx, s.Scope.ResourceGroup(), vmssName, vmss)
 }
 
 // getVirtualMachineScaleSetIfDone gets a Virtual Machine Scale Set and its instances from Azure if the future is completed.
@@ -529,7 +848,29 @@ func (s *Service) getVirtualMachineScaleSetIfDone(ctx context.Context, future *i
 		return nil, errors.Wrap(err, "failed to get result from future")
 	}
 
-	vmssInstances, err := s.Client.ListInstances(ctx, future.ResourceGroup, future.Name)
+	return s.withInstances(ctx, future.ResourceGroup, future.Name, vmss)
+}
+
+// withInstances converts a Virtual Machine Scale Set and its instances to an azure.VMSS. Instances of a scale set in
+// Flexible orchestration mode are not available through the VMSS VM API, so they are listed through the VM API instead.
+func (s *Service) withInstances(ctx context.Context, resourceGroup, vmssName string, vmss compute.VirtualMachineScaleSet) (*azure.VMSS, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesets.Service.withInstances")
+	defer done()
+
+	if vmss.VirtualMachineScaleSetProperties != nil && vmss.OrchestrationMode == compute.OrchestrationModeFlexible {
+		vms, err := s.Client.ListVMs(ctx, resourceGroup, to.String(vmss.ID))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list instances")
+		}
+
+		result := converters.SDKToVMSS(vmss, nil)
+		for _, vm := range vms {
+			result.Instances = append(result.Instances, *converters.SDKVMToVMSSVM(vm))
+		}
+		return result, nil
+	}
+
+	vmssInstances, err := s.Client.ListInstances(ctx, resourceGroup, vmssName)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to list instances")
 	}
@@ -537,22 +878,119 @@ func (s *Service) getVirtualMachineScaleSetIfDone(ctx context.Context, future *i
 	return converters.SDKToVMSS(vmss, vmssInstances), nil
 }
 
-func (s *Service) generateExtensions() []compute.VirtualMachineScaleSetExtension {
-	extensions := make([]compute.VirtualMachineScaleSetExtension, len(s.Scope.VMSSExtensionSpecs()))
-	for i, extensionSpec := range s.Scope.VMSSExtensionSpecs() {
+func (s *Service) generateExtensions(ctx context.Context) ([]compute.VirtualMachineScaleSetExtension, error) {
+	extensionSpecs, err := s.Scope.VMSSExtensionSpecs(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get vmss extension specs")
+	}
+
+	// the scope returns the extensions of each of the scale sets backing the machine pool, which share the same model
+	var (
+		extensions = make([]compute.VirtualMachineScaleSetExtension, 0, len(extensionSpecs))
+		seen       = make(map[string]bool, len(extensionSpecs))
+	)
+	for _, extensionSpec := range extensionSpecs {
 		extensionSpec := extensionSpec
-		extensions[i] = compute.VirtualMachineScaleSetExtension{
+		if seen[extensionSpec.Name] {
+			continue
+		}
+		seen[extensionSpec.Name] = true
+
+		var settings interface{}
+		if len(extensionSpec.Settings) > 0 {
+			settings = extensionSpec.Settings
+		}
+		extensions = append(extensions, compute.VirtualMachineScaleSetExtension{
 			Name: &extensionSpec.Name,
 			VirtualMachineScaleSetExtensionProperties: &compute.VirtualMachineScaleSetExtensionProperties{
 				Publisher:          to.StringPtr(extensionSpec.Publisher),
-				Type:               to.StringPtr(extensionSpec.Name),
+				Type:               to.StringPtr(extensionSpec.Type),
 				TypeHandlerVersion: to.StringPtr(extensionSpec.Version),
-				Settings:           nil,
+				Settings:           settings,
 				ProtectedSettings:  extensionSpec.ProtectedSettings,
 			},
+		})
+	}
+	return extensions, nil
+}
+
+// getUpgradePolicy converts the upgrade policy of the scale set spec to its SDK representation. Scale sets default to
+// the Manual upgrade mode, which lets CAPZ orchestrate the rollout of model changes.
+func getUpgradePolicy(policy *infrav1.UpgradePolicy) *compute.UpgradePolicy {
+	if policy == nil || policy.Mode == "" {
+		return &compute.UpgradePolicy{
+			Mode: compute.UpgradeModeManual,
 		}
 	}
-	return extensions
+
+	upgradePolicy := &compute.UpgradePolicy{
+		Mode: compute.UpgradeMode(policy.Mode),
+	}
+
+	if rolling := policy.RollingUpgradePolicy; rolling != nil {
+		upgradePolicy.RollingUpgradePolicy = &compute.RollingUpgradePolicy{
+			MaxBatchInstancePercent:             rolling.MaxBatchInstancePercent,
+			MaxUnhealthyInstancePercent:         rolling.MaxUnhealthyInstancePercent,
+			MaxUnhealthyUpgradedInstancePercent: rolling.MaxUnhealthyUpgradedInstancePercent,
+			PrioritizeUnhealthyInstances:        rolling.PrioritizeUnhealthyInstances,
+		}
+		if rolling.PauseTimeBetweenBatches != nil {
+			upgradePolicy.RollingUpgradePolicy.PauseTimeBetweenBatches = to.StringPtr(fmt.Sprintf("PT%dS", int64(rolling.PauseTimeBetweenBatches.Seconds())))
+		}
+	}
+
+	if automatic := policy.AutomaticOSUpgradePolicy; automatic != nil {
+		upgradePolicy.AutomaticOSUpgradePolicy = &compute.AutomaticOSUpgradePolicy{
+			EnableAutomaticOSUpgrade: to.BoolPtr(automatic.EnableAutomaticOSUpgrade),
+			DisableAutomaticRollback: automatic.DisableAutomaticRollback,
+		}
+	}
+
+	return upgradePolicy
+}
+
+// getAutomaticRepairsPolicy converts the automatic repairs policy of the scale set spec to its SDK representation.
+func getAutomaticRepairsPolicy(policy *infrav1.AutomaticRepairsPolicy) *compute.AutomaticRepairsPolicy {
+	if policy == nil {
+		return nil
+	}
+
+	repairsPolicy := &compute.AutomaticRepairsPolicy{
+		Enabled: to.BoolPtr(policy.Enabled),
+	}
+	if policy.GracePeriod != nil {
+		repairsPolicy.GracePeriod = to.StringPtr(fmt.Sprintf("PT%dM", int64(policy.GracePeriod.Minutes())))
+	}
+
+	return repairsPolicy
+}
+
+// getApplicationHealthExtension returns the application health extension reporting the health of the scale set
+// instances, which backs rolling upgrades, automatic OS upgrades and automatic repairs.
+func getApplicationHealthExtension(osType string, probe infrav1.ApplicationHealthProbe) compute.VirtualMachineScaleSetExtension {
+	extensionType := azure.ApplicationHealthExtensionLinux
+	if osType == azure.WindowsOS {
+		extensionType = azure.ApplicationHealthExtensionWindows
+	}
+
+	settings := map[string]interface{}{
+		"protocol": string(probe.Protocol),
+		"port":     probe.Port,
+	}
+	if probe.RequestPath != "" {
+		settings["requestPath"] = probe.RequestPath
+	}
+
+	return compute.VirtualMachineScaleSetExtension{
+		Name: to.StringPtr(azure.ApplicationHealthExtensionName),
+		VirtualMachineScaleSetExtensionProperties: &compute.VirtualMachineScaleSetExtensionProperties{
+			Publisher:               to.StringPtr(azure.ApplicationHealthExtensionPublisher),
+			Type:                    to.StringPtr(extensionType),
+			TypeHandlerVersion:      to.StringPtr("1.0"),
+			AutoUpgradeMinorVersion: to.BoolPtr(true),
+			Settings:                settings,
+		},
+	}
 }
 
 // generateStorageProfile generates a pointer to a compute.VirtualMachineScaleSetStorageProfile which can utilized for VM creation.
@@ -18,11 +18,14 @@ package scalesets
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
 	"github.com/Azure/go-autorest/autorest"
+	autorestazure "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
@@ -164,6 +167,54 @@ func TestGetExistingVMSS(t *testing.T) {
 				}, nil)
 			},
 		},
+		{
+			name:     "get existing vmss in flexible orchestration mode",
+			vmssName: "my-vmss",
+			result: &azure.VMSS{
+				ID:                "my-id",
+				Name:              "my-vmss",
+				State:             "Succeeded",
+				Sku:               "Standard_D2",
+				Capacity:          int64(1),
+				OrchestrationMode: infrav1.FlexibleOrchestrationMode,
+				Instances: []azure.VMSSVM{
+					{
+						ID:         "my-vm-id",
+						InstanceID: "my-vmss_1a2b3c4d",
+						Name:       "my-vmss1a2b3c",
+						State:      "Succeeded",
+					},
+				},
+			},
+			expectedError: "",
+			expect: func(s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vmss").Return(compute.VirtualMachineScaleSet{
+					ID:   to.StringPtr("my-id"),
+					Name: to.StringPtr("my-vmss"),
+					Sku: &compute.Sku{
+						Capacity: to.Int64Ptr(1),
+						Name:     to.StringPtr("Standard_D2"),
+					},
+					VirtualMachineScaleSetProperties: &compute.VirtualMachineScaleSetProperties{
+						OrchestrationMode: compute.OrchestrationModeFlexible,
+						ProvisioningState: to.StringPtr("Succeeded"),
+					},
+				}, nil)
+				m.ListVMs(gomockinternal.AContext(), "my-rg", "my-id").Return([]compute.VirtualMachine{
+					{
+						ID:   to.StringPtr("my-vm-id"),
+						Name: to.StringPtr("my-vmss_1a2b3c4d"),
+						VirtualMachineProperties: &compute.VirtualMachineProperties{
+							ProvisioningState: to.StringPtr("Succeeded"),
+							OsProfile: &compute.OSProfile{
+								ComputerName: to.StringPtr("my-vmss1a2b3c"),
+							},
+						},
+					},
+				}, nil)
+			},
+		},
 		{
 			name:          "list instances fails",
 			vmssName:      "my-vmss",
@@ -298,6 +349,92 @@ func TestReconcileVMSS(t *testing.T) {
 				setupCreatingSucceededExpectations(s, m, newDefaultExistingVMSS("VM_SIZE_AN"), putFuture)
 			},
 		},
+		{
+			name:          "should start creating a vmss in flexible orchestration mode",
+			expectedError: "failed to get VMSS my-vmss after create or update: failed to get result from future: operation type PUT on Azure resource my-rg/my-vmss is not done",
+			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				spec := newDefaultVMSSSpec()
+				spec.OrchestrationMode = infrav1.FlexibleOrchestrationMode
+				spec.DataDisks = append(spec.DataDisks, infrav1.DataDisk{
+					NameSuffix: "my_disk_with_ultra_disks",
+					DiskSizeGB: 128,
+					Lun:        to.Int32Ptr(3),
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						StorageAccountType: "UltraSSD_LRS",
+					},
+				})
+				s.ScaleSetSpec().Return(spec).AnyTimes()
+				setupDefaultVMSSStartCreatingExpectations(s, m)
+				vmss := newDefaultVMSS("VM_SIZE")
+				vmss.VirtualMachineScaleSetProperties.AdditionalCapabilities = &compute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
+				vmss.OrchestrationMode = compute.OrchestrationModeFlexible
+				vmss.PlatformFaultDomainCount = to.Int32Ptr(1)
+				vmss.UpgradePolicy = nil
+				vmss.Overprovision = nil
+				vmss.SinglePlacementGroup = nil
+				vmss.VirtualMachineProfile.NetworkProfile.NetworkAPIVersion = compute.NetworkAPIVersionTwoZeroTwoZeroHyphenMinusOneOneHyphenMinusZeroOne
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomockinternal.DiffEq(vmss)).
+					Return(putFuture, nil)
+				setupCreatingSucceededExpectations(s, m, newDefaultExistingVMSS("VM_SIZE"), putFuture)
+			},
+		},
+		{
+			name:          "should start creating a vmss with a rolling upgrade policy and automatic repairs",
+			expectedError: "failed to get VMSS my-vmss after create or update: failed to get result from future: operation type PUT on Azure resource my-rg/my-vmss is not done",
+			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				spec := newDefaultVMSSSpec()
+				// the default data disks of this size do not include an ultra disk
+				spec.Size = "VM_SIZE_EAH"
+				spec.UpgradePolicy = &infrav1.UpgradePolicy{
+					Mode: infrav1.UpgradeModeRolling,
+					RollingUpgradePolicy: &infrav1.RollingUpgradePolicy{
+						MaxBatchInstancePercent: to.Int32Ptr(20),
+						PauseTimeBetweenBatches: &metav1.Duration{Duration: 90 * time.Second},
+					},
+				}
+				spec.AutomaticRepairsPolicy = &infrav1.AutomaticRepairsPolicy{
+					Enabled:     true,
+					GracePeriod: &metav1.Duration{Duration: 45 * time.Minute},
+				}
+				spec.ApplicationHealth = &infrav1.ApplicationHealthProbe{
+					Protocol:    infrav1.ApplicationHealthProtocolHTTP,
+					Port:        10248,
+					RequestPath: "/healthz",
+				}
+				s.ScaleSetSpec().Return(spec).AnyTimes()
+				setupDefaultVMSSStartCreatingExpectations(s, m)
+				vmss := newDefaultVMSS("VM_SIZE_EAH")
+				vmss.UpgradePolicy = &compute.UpgradePolicy{
+					Mode: compute.UpgradeModeRolling,
+					RollingUpgradePolicy: &compute.RollingUpgradePolicy{
+						MaxBatchInstancePercent: to.Int32Ptr(20),
+						PauseTimeBetweenBatches: to.StringPtr("PT90S"),
+					},
+				}
+				vmss.AutomaticRepairsPolicy = &compute.AutomaticRepairsPolicy{
+					Enabled:     to.BoolPtr(true),
+					GracePeriod: to.StringPtr("PT45M"),
+				}
+				extensions := append(*vmss.VirtualMachineProfile.ExtensionProfile.Extensions, compute.VirtualMachineScaleSetExtension{
+					Name: to.StringPtr("HealthExtension"),
+					VirtualMachineScaleSetExtensionProperties: &compute.VirtualMachineScaleSetExtensionProperties{
+						Publisher:               to.StringPtr("Microsoft.ManagedServices"),
+						Type:                    to.StringPtr("ApplicationHealthLinux"),
+						TypeHandlerVersion:      to.StringPtr("1.0"),
+						AutoUpgradeMinorVersion: to.BoolPtr(true),
+						Settings: map[string]interface{}{
+							"protocol":    "http",
+							"port":        int32(10248),
+							"requestPath": "/healthz",
+						},
+					},
+				})
+				vmss.VirtualMachineProfile.ExtensionProfile.Extensions = &extensions
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomockinternal.DiffEq(vmss)).
+					Return(putFuture, nil)
+				setupCreatingSucceededExpectations(s, m, newDefaultExistingVMSS("VM_SIZE_EAH"), putFuture)
+			},
+		},
 		{
 			name:          "should start creating a vmss with spot vm",
 			expectedError: "failed to get VMSS my-vmss after create or update: failed to get result from future: operation type PUT on Azure resource my-rg/my-vmss is not done",
@@ -493,6 +630,51 @@ func TestReconcileVMSS(t *testing.T) {
 				m.ListInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(instances, nil)
 			},
 		},
+		{
+			name:          "should start updating when only the bootstrap data of the scale set changed",
+			expectedError: "failed to get VMSS my-vmss after create or update: failed to get result from future: operation type PATCH on Azure resource my-rg/my-vmss is not done",
+			expect: func(g *WithT, s *mock_scalesets.MockScaleSetScopeMockRecorder, m *mock_scalesets.MockClientMockRecorder) {
+				spec := newDefaultVMSSSpec()
+				spec.Capacity = 2
+				spec.DataDisks = append(spec.DataDisks, infrav1.DataDisk{
+					NameSuffix: "my_disk_with_ultra_disks",
+					DiskSizeGB: 128,
+					Lun:        to.Int32Ptr(3),
+					ManagedDisk: &infrav1.ManagedDiskParameters{
+						StorageAccountType: "UltraSSD_LRS",
+					},
+				})
+				s.ScaleSetSpec().Return(spec).AnyTimes()
+				s.HasBootstrapDataChanges(gomockinternal.AContext()).Return(true, nil)
+				s.SaveBootstrapDataHash(gomockinternal.AContext()).Return(nil)
+
+				setupDefaultVMSSExpectations(s)
+				s.SetProviderID(azure.ProviderIDPrefix + "vmss-id")
+				s.GetLongRunningOperationState(defaultVMSSName, scope.ScalesetsServiceName).Return(nil)
+				s.MaxSurge().Return(1, nil)
+				s.SetVMSSState(gomock.Any())
+				existingVMSS := newDefaultExistingVMSS("VM_SIZE")
+				existingVMSS.VirtualMachineScaleSetProperties.AdditionalCapabilities = &compute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
+				existingVMSS.Sku.Capacity = to.Int64Ptr(2)
+				instances := newDefaultInstances()
+				m.Get(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(existingVMSS, nil)
+				m.ListInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(instances, nil)
+
+				clone := newDefaultExistingVMSS("VM_SIZE")
+				clone.Sku.Capacity = to.Int64Ptr(3)
+				clone.VirtualMachineScaleSetProperties.AdditionalCapabilities = &compute.AdditionalCapabilities{UltraSSDEnabled: pointer.Bool(true)}
+
+				patchVMSS, err := getVMSSUpdateFromVMSS(clone)
+				g.Expect(err).NotTo(HaveOccurred())
+				patchVMSS.VirtualMachineProfile.NetworkProfile = nil
+				m.UpdateAsync(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName, gomockinternal.DiffEq(patchVMSS)).
+					Return(patchFuture, nil)
+				s.SetLongRunningOperationState(patchFuture)
+				m.GetResultIfDone(gomockinternal.AContext(), patchFuture).Return(compute.VirtualMachineScaleSet{}, azure.NewOperationNotDoneError(patchFuture))
+				m.Get(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(clone, nil)
+				m.ListInstances(gomockinternal.AContext(), defaultResourceGroup, defaultVMSSName).Return(instances, nil)
+			},
+		},
 		{
 			name:          "less than 2 vCPUs",
 			expectedError: "reconcile error that cannot be recovered occurred: vm size should be bigger or equal to at least 2 vCPUs. Object will not be requeued",
@@ -689,6 +871,158 @@ func TestDeleteVMSS(t *testing.T) {
 	}
 }
 
+func TestReconcileMixedInstancesVMSS(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	scopeMock := mock_scalesets.NewMockMixedInstancesScope(mockCtrl)
+	clientMock := mock_scalesets.NewMockClient(mockCtrl)
+	s, m := scopeMock.EXPECT(), clientMock.EXPECT()
+
+	preferred := newDefaultVMSSSpec()
+	fallback := newDefaultVMSSSpec()
+	fallback.Name = "my-vmss-fallback"
+	fallback.Capacity = 0
+	s.ScaleSetSpecs().Return([]azure.ScaleSetSpec{preferred, fallback})
+	// the bootstrap data is not recorded as the preferred scale set fails to be created
+	s.HasBootstrapDataChanges(gomockinternal.AContext()).Return(true, nil)
+
+	s.SubscriptionID().AnyTimes().Return(defaultSubscriptionID)
+	s.ResourceGroup().AnyTimes().Return(defaultResourceGroup)
+	s.Location().AnyTimes().Return("test-location")
+	s.AdditionalTags().AnyTimes()
+	s.ClusterName().AnyTimes().Return("my-cluster")
+	s.GetBootstrapData(gomockinternal.AContext()).Return("fake-bootstrap-data", nil)
+	s.VMSSExtensionSpecs(gomockinternal.AContext()).Return(nil, nil)
+	image := &infrav1.Image{ID: to.StringPtr("image-id")}
+	s.GetVMImage(gomockinternal.AContext()).Return(image, nil).AnyTimes()
+	s.SaveVMImageToStatus(image)
+
+	// the preferred VM size is out of capacity
+	s.GetLongRunningOperationState(preferred.Name, scope.ScalesetsServiceName).Return(nil)
+	m.Get(gomockinternal.AContext(), defaultResourceGroup, preferred.Name).
+		Return(compute.VirtualMachineScaleSet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found")).Times(2)
+	m.CreateOrUpdateAsync(gomockinternal.AContext(), defaultResourceGroup, preferred.Name, gomock.Any()).
+		Return(nil, autorest.NewErrorWithError(&autorestazure.RequestError{
+			ServiceError: &autorestazure.ServiceError{Code: "SkuNotAvailable"},
+		}, "compute.VirtualMachineScaleSetsClient", "CreateOrUpdate", nil, "Failure sending request"))
+	s.SetScaleSetUnavailable(preferred.Name)
+
+	// the fallback VM size has no replicas yet and its scale set is not created
+	s.GetLongRunningOperationState(fallback.Name, scope.ScalesetsServiceName).Return(nil)
+	m.Get(gomockinternal.AContext(), defaultResourceGroup, fallback.Name).
+		Return(compute.VirtualMachineScaleSet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+
+	s.SetScaleSetStates(gomock.Len(0))
+
+	service := &Service{
+		Scope:            scopeMock,
+		Client:           clientMock,
+		resourceSKUCache: resourceskus.NewStaticCache(getFakeSkus(), "test-location"),
+	}
+
+	err := service.Reconcile(context.TODO())
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("SkuNotAvailable"))
+	var reconcileErr azure.ReconcileError
+	g.Expect(errors.As(err, &reconcileErr)).To(BeTrue())
+	g.Expect(reconcileErr.IsTransient()).To(BeTrue())
+}
+
+func TestReconcileBlueGreenVMSS(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	scopeMock := mock_scalesets.NewMockBlueGreenScope(mockCtrl)
+	clientMock := mock_scalesets.NewMockClient(mockCtrl)
+	s, m := scopeMock.EXPECT(), clientMock.EXPECT()
+
+	active := newDefaultVMSSSpec()
+	active.ObserveOnly = true
+	retired := newDefaultVMSSSpec()
+	retired.Name = "my-vmss-retired"
+	retired.Capacity = 0
+	retired.ObserveOnly = true
+	deleted := newDefaultVMSSSpec()
+	deleted.Name = "my-vmss-deleted"
+	deleted.Capacity = 0
+	deleted.ObserveOnly = true
+	s.BlueGreenScaleSetSpecs().Return([]azure.ScaleSetSpec{active, retired, deleted})
+	s.HasBootstrapDataChanges(gomockinternal.AContext()).Return(true, nil)
+	s.ResourceGroup().AnyTimes().Return(defaultResourceGroup)
+
+	// the scale set of the previous model is observed, but its model is not updated
+	s.GetLongRunningOperationState(active.Name, scope.ScalesetsServiceName).Return(nil)
+	m.Get(gomockinternal.AContext(), defaultResourceGroup, active.Name).Return(newDefaultExistingVMSS("VM_SIZE"), nil)
+	m.ListInstances(gomockinternal.AContext(), defaultResourceGroup, active.Name).Return([]compute.VirtualMachineScaleSetVM{
+		{
+			InstanceID: to.StringPtr("0"),
+			VirtualMachineScaleSetVMProperties: &compute.VirtualMachineScaleSetVMProperties{
+				ProvisioningState: to.StringPtr("Succeeded"),
+			},
+		},
+	}, nil)
+	s.DeleteLongRunningOperationState(active.Name, scope.ScalesetsServiceName)
+
+	// the retired scale set has no instances left, so it is deleted
+	s.GetLongRunningOperationState(retired.Name, scope.ScalesetsServiceName).Return(nil).Times(3)
+	m.Get(gomockinternal.AContext(), defaultResourceGroup, retired.Name).Return(newDefaultExistingVMSS("VM_SIZE"), nil).Times(2)
+	m.ListInstances(gomockinternal.AContext(), defaultResourceGroup, retired.Name).Return(nil, nil).Times(2)
+	s.DeleteLongRunningOperationState(retired.Name, scope.ScalesetsServiceName).Times(2)
+	m.DeleteAsync(gomockinternal.AContext(), defaultResourceGroup, retired.Name).Return(nil, nil)
+	s.SetLongRunningOperationState(nil)
+	m.Get(gomockinternal.AContext(), defaultResourceGroup, retired.Name).
+		Return(compute.VirtualMachineScaleSet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+
+	// the scale set retired before is no longer tracked once deleted
+	s.GetLongRunningOperationState(deleted.Name, scope.ScalesetsServiceName).Return(nil)
+	m.Get(gomockinternal.AContext(), defaultResourceGroup, deleted.Name).
+		Return(compute.VirtualMachineScaleSet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+	s.SetScaleSetDeleted(deleted.Name)
+
+	s.SetScaleSetStates(gomock.Len(2))
+	s.SetProviderID(azure.ProviderIDPrefix + "vmss-id")
+	s.SaveBootstrapDataHash(gomockinternal.AContext())
+
+	service := &Service{
+		Scope:  scopeMock,
+		Client: clientMock,
+	}
+
+	g.Expect(service.Reconcile(context.TODO())).To(Succeed())
+}
+
+func TestDeleteMixedInstancesVMSS(t *testing.T) {
+	g := NewWithT(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	scopeMock := mock_scalesets.NewMockMixedInstancesScope(mockCtrl)
+	clientMock := mock_scalesets.NewMockClient(mockCtrl)
+	s, m := scopeMock.EXPECT(), clientMock.EXPECT()
+
+	s.ScaleSetSpecs().Return([]azure.ScaleSetSpec{{Name: "my-vmss-a"}, {Name: "my-vmss-b"}})
+	s.ResourceGroup().AnyTimes().Return(defaultResourceGroup)
+	for _, name := range []string{"my-vmss-a", "my-vmss-b"} {
+		s.GetLongRunningOperationState(name, scope.ScalesetsServiceName).Return(nil)
+		m.DeleteAsync(gomockinternal.AContext(), defaultResourceGroup, name).Return(nil, nil)
+		s.SetLongRunningOperationState(nil)
+		s.DeleteLongRunningOperationState(name, scope.ScalesetsServiceName)
+		m.Get(gomockinternal.AContext(), defaultResourceGroup, name).
+			Return(compute.VirtualMachineScaleSet{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+	}
+	s.SetScaleSetStates(gomock.Len(0))
+
+	service := &Service{
+		Scope:  scopeMock,
+		Client: clientMock,
+	}
+
+	g.Expect(service.Delete(context.TODO())).To(Succeed())
+}
+
 func getFakeSkus() []compute.ResourceSku {
 	return []compute.ResourceSku{
 		{
@@ -1263,17 +1597,20 @@ func setupVMSSExpectationsWithoutVMImage(s *mock_scalesets.MockScaleSetScopeMock
 	s.Location().AnyTimes().Return("test-location")
 	s.ClusterName().Return("my-cluster")
 	s.GetBootstrapData(gomockinternal.AContext()).Return("fake-bootstrap-data", nil)
-	s.VMSSExtensionSpecs().Return([]azure.ExtensionSpec{
+	s.HasBootstrapDataChanges(gomockinternal.AContext()).Return(false, nil).AnyTimes()
+	s.SaveBootstrapDataHash(gomockinternal.AContext()).Return(nil).AnyTimes()
+	s.VMSSExtensionSpecs(gomockinternal.AContext()).Return([]azure.ExtensionSpec{
 		{
 			Name:      "someExtension",
 			VMName:    "my-vmss",
 			Publisher: "somePublisher",
+			Type:      "someExtension",
 			Version:   "someVersion",
 			ProtectedSettings: map[string]string{
 				"commandToExecute": "echo hello",
 			},
 		},
-	}).AnyTimes()
+	}, nil).AnyTimes()
 }
 
 func setupDefaultVMSSUpdateExpectations(s *mock_scalesets.MockScaleSetScopeMockRecorder) {
@@ -24,6 +24,7 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
 	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
@@ -37,12 +38,17 @@ type client interface {
 	Get(context.Context, string, string, string) (compute.VirtualMachineScaleSetVM, error)
 	GetResultIfDone(ctx context.Context, future *infrav1.Future) (compute.VirtualMachineScaleSetVM, error)
 	DeleteAsync(context.Context, string, string, string) (*infrav1.Future, error)
+	UpdateProtectionPolicyAsync(context.Context, string, string, string, azure.VMSSVMProtectionPolicy) (*infrav1.Future, error)
+	GetVM(context.Context, string, string) (compute.VirtualMachine, error)
+	GetVMResultIfDone(ctx context.Context, future *infrav1.Future) (compute.VirtualMachine, error)
+	DeleteVMAsync(context.Context, string, string) (*infrav1.Future, error)
 }
 
 type (
 	// azureClient contains the Azure go-sdk Client.
 	azureClient struct {
-		scalesetvms compute.VirtualMachineScaleSetVMsClient
+		scalesetvms     compute.VirtualMachineScaleSetVMsClient
+		virtualmachines compute.VirtualMachinesClient
 	}
 
 	genericScaleSetVMFuture interface {
@@ -50,9 +56,23 @@ type (
 		Result(client compute.VirtualMachineScaleSetVMsClient) (vmss compute.VirtualMachineScaleSetVM, err error)
 	}
 
+	genericScaleSetVMFutureImpl struct {
+		azureautorest.FutureAPI
+		result func(client compute.VirtualMachineScaleSetVMsClient) (vm compute.VirtualMachineScaleSetVM, err error)
+	}
+
 	deleteFutureAdapter struct {
 		compute.VirtualMachineScaleSetVMsDeleteFuture
 	}
+
+	genericVMFuture interface {
+		DoneWithContext(ctx context.Context, sender autorest.Sender) (done bool, err error)
+		Result(client compute.VirtualMachinesClient) (vm compute.VirtualMachine, err error)
+	}
+
+	vmDeleteFutureAdapter struct {
+		compute.VirtualMachinesDeleteFuture
+	}
 )
 
 var _ client = &azureClient{}
@@ -60,7 +80,8 @@ var _ client = &azureClient{}
 // newClient creates a new VMSS client from subscription ID.
 func newClient(auth azure.Authorizer) *azureClient {
 	return &azureClient{
-		scalesetvms: newVirtualMachineScaleSetVMsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+		scalesetvms:     newVirtualMachineScaleSetVMsClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
+		virtualmachines: newVirtualMachinesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer()),
 	}
 }
 
@@ -73,12 +94,19 @@ func newVirtualMachineScaleSetVMsClient(subscriptionID string, baseURI string, a
 	return c
 }
 
+// newVirtualMachinesClient creates a new VM client from subscription ID.
+func newVirtualMachinesClient(subscriptionID string, baseURI string, authorizer autorest.Authorizer) compute.VirtualMachinesClient {
+	c := compute.NewVirtualMachinesClientWithBaseURI(baseURI, subscriptionID)
+	azure.SetAutoRestClientDefaults(&c.Client, authorizer)
+	return c
+}
+
 // Get retrieves the Virtual Machine Scale Set Virtual Machine.
 func (ac *azureClient) Get(ctx context.Context, resourceGroupName, vmssName, instanceID string) (compute.VirtualMachineScaleSetVM, error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesetvms.azureClient.Get")
 	defer done()
 
-	return ac.scalesetvms.Get(ctx, resourceGroupName, vmssName, instanceID, "")
+	return ac.scalesetvms.Get(ctx, resourceGroupName, vmssName, instanceID, compute.InstanceViewTypesInstanceView)
 }
 
 // GetResultIfDone fetches the result of a long-running operation future if it is done.
@@ -102,6 +130,16 @@ func (ac *azureClient) GetResultIfDone(ctx context.Context, future *infrav1.Futu
 		genericFuture = &deleteFutureAdapter{
 			VirtualMachineScaleSetVMsDeleteFuture: future,
 		}
+	case infrav1.PutFuture:
+		var future compute.VirtualMachineScaleSetVMsUpdateFuture
+		if err := json.Unmarshal(futureData, &future); err != nil {
+			return compute.VirtualMachineScaleSetVM{}, errors.Wrap(err, "failed to unmarshal future data")
+		}
+
+		genericFuture = &genericScaleSetVMFutureImpl{
+			FutureAPI: &future,
+			result:    future.Result,
+		}
 	default:
 		return compute.VirtualMachineScaleSetVM{}, errors.Errorf("unknown furture type %q", future.Type)
 	}
@@ -143,9 +181,118 @@ func (ac *azureClient) DeleteAsync(ctx context.Context, resourceGroupName, vmssN
 	return converters.SDKToFuture(&future, infrav1.DeleteFuture, serviceName, instanceID, resourceGroupName)
 }
 
+// UpdateProtectionPolicyAsync is the operation to update the instance protection of a virtual machine scale set
+// instance asynchronously. Only the protection policy of the instance is sent to Azure, so that the rest of the
+// instance model is left untouched, and if the PUT request is accepted without error, the func will return a Future
+// which can be used to track the ongoing progress of the operation.
+//
+// Parameters:
+//   resourceGroupName - the name of the resource group.
+//   vmssName - the name of the VM scale set.
+//   instanceID - the ID of the VM scale set VM.
+//   policy - the instance protection to apply to the VM scale set VM.
+func (ac *azureClient) UpdateProtectionPolicyAsync(ctx context.Context, resourceGroupName, vmssName, instanceID string, policy azure.VMSSVMProtectionPolicy) (*infrav1.Future, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesetvms.azureClient.UpdateProtectionPolicyAsync")
+	defer done()
+
+	instance := compute.VirtualMachineScaleSetVM{
+		VirtualMachineScaleSetVMProperties: &compute.VirtualMachineScaleSetVMProperties{
+			ProtectionPolicy: &compute.VirtualMachineScaleSetVMProtectionPolicy{
+				ProtectFromScaleIn:         to.BoolPtr(policy.ProtectFromScaleIn),
+				ProtectFromScaleSetActions: to.BoolPtr(policy.ProtectFromScaleSetActions),
+			},
+		},
+	}
+
+	future, err := ac.scalesetvms.Update(ctx, resourceGroupName, vmssName, instanceID, instance)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed updating instance %s of vmss named %q", instanceID, vmssName)
+	}
+
+	return converters.SDKToFuture(&future, infrav1.PutFuture, serviceName, instanceID, resourceGroupName)
+}
+
+// Result returns the Result so that we can treat it generically.
+func (g *genericScaleSetVMFutureImpl) Result(client compute.VirtualMachineScaleSetVMsClient) (compute.VirtualMachineScaleSetVM, error) {
+	return g.result(client)
+}
+
 // Result wraps the delete result so that we can treat it generically. The only thing we care about is if the delete
 // was successful. If it wasn't, an error will be returned.
 func (da *deleteFutureAdapter) Result(client compute.VirtualMachineScaleSetVMsClient) (compute.VirtualMachineScaleSetVM, error) {
 	_, err := da.VirtualMachineScaleSetVMsDeleteFuture.Result(client)
 	return compute.VirtualMachineScaleSetVM{}, err
 }
+
+// GetVM retrieves a Virtual Machine of a Virtual Machine Scale Set in Flexible orchestration mode.
+func (ac *azureClient) GetVM(ctx context.Context, resourceGroupName, vmName string) (compute.VirtualMachine, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesetvms.azureClient.GetVM")
+	defer done()
+
+	return ac.virtualmachines.Get(ctx, resourceGroupName, vmName, compute.InstanceViewTypesInstanceView)
+}
+
+// GetVMResultIfDone fetches the result of a long-running operation future on a Virtual Machine of a Virtual Machine
+// Scale Set in Flexible orchestration mode if it is done.
+func (ac *azureClient) GetVMResultIfDone(ctx context.Context, future *infrav1.Future) (compute.VirtualMachine, error) {
+	ctx, _, spanDone := tele.StartSpanWithLogger(ctx, "scalesetvms.azureClient.GetVMResultIfDone")
+	defer spanDone()
+
+	var genericFuture genericVMFuture
+	futureData, err := base64.URLEncoding.DecodeString(future.Data)
+	if err != nil {
+		return compute.VirtualMachine{}, errors.Wrapf(err, "failed to base64 decode future data")
+	}
+
+	switch future.Type {
+	case infrav1.DeleteFuture:
+		var future compute.VirtualMachinesDeleteFuture
+		if err := json.Unmarshal(futureData, &future); err != nil {
+			return compute.VirtualMachine{}, errors.Wrap(err, "failed to unmarshal future data")
+		}
+
+		genericFuture = &vmDeleteFutureAdapter{
+			VirtualMachinesDeleteFuture: future,
+		}
+	default:
+		return compute.VirtualMachine{}, errors.Errorf("unknown furture type %q", future.Type)
+	}
+
+	done, err := genericFuture.DoneWithContext(ctx, ac.virtualmachines)
+	if err != nil {
+		return compute.VirtualMachine{}, errors.Wrapf(err, "failed checking if the operation was complete")
+	}
+
+	if !done {
+		return compute.VirtualMachine{}, azure.WithTransientError(azure.NewOperationNotDoneError(future), 15*time.Second)
+	}
+
+	vm, err := genericFuture.Result(ac.virtualmachines)
+	if err != nil {
+		return vm, errors.Wrapf(err, "failed fetching the result of operation for vm")
+	}
+
+	return vm, nil
+}
+
+// DeleteVMAsync is the operation to delete a Virtual Machine of a Virtual Machine Scale Set in Flexible orchestration
+// mode asynchronously. DeleteVMAsync sends a DELETE request to Azure and if accepted without error, the func will
+// return a Future which can be used to track the ongoing progress of the operation.
+func (ac *azureClient) DeleteVMAsync(ctx context.Context, resourceGroupName, vmName string) (*infrav1.Future, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesetvms.azureClient.DeleteVMAsync")
+	defer done()
+
+	future, err := ac.virtualmachines.Delete(ctx, resourceGroupName, vmName, to.BoolPtr(false))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed deleting vm named %q", vmName)
+	}
+
+	return converters.SDKToFuture(&future, infrav1.DeleteFuture, serviceName, vmName, resourceGroupName)
+}
+
+// Result wraps the delete result so that we can treat it generically. The only thing we care about is if the delete
+// was successful. If it wasn't, an error will be returned.
+func (da *vmDeleteFutureAdapter) Result(client compute.VirtualMachinesClient) (compute.VirtualMachine, error) {
+	_, err := da.VirtualMachinesDeleteFuture.Result(client)
+	return compute.VirtualMachine{}, err
+}
@@ -28,6 +28,7 @@ import (
 	autorest "github.com/Azure/go-autorest/autorest"
 	gomock "github.com/golang/mock/gomock"
 	v1beta1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	azure "sigs.k8s.io/cluster-api-provider-azure/azure"
 )
 
 // Mockclient is a mock of client interface.
@@ -68,6 +69,21 @@ func (mr *MockclientMockRecorder) DeleteAsync(arg0, arg1, arg2, arg3 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAsync", reflect.TypeOf((*Mockclient)(nil).DeleteAsync), arg0, arg1, arg2, arg3)
 }
 
+// DeleteVMAsync mocks base method.
+func (m *Mockclient) DeleteVMAsync(arg0 context.Context, arg1, arg2 string) (*v1beta1.Future, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVMAsync", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*v1beta1.Future)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteVMAsync indicates an expected call of DeleteVMAsync.
+func (mr *MockclientMockRecorder) DeleteVMAsync(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVMAsync", reflect.TypeOf((*Mockclient)(nil).DeleteVMAsync), arg0, arg1, arg2)
+}
+
 // Get mocks base method.
 func (m *Mockclient) Get(arg0 context.Context, arg1, arg2, arg3 string) (compute.VirtualMachineScaleSetVM, error) {
 	m.ctrl.T.Helper()
@@ -98,6 +114,51 @@ func (mr *MockclientMockRecorder) GetResultIfDone(ctx, future interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResultIfDone", reflect.TypeOf((*Mockclient)(nil).GetResultIfDone), ctx, future)
 }
 
+// GetVM mocks base method.
+func (m *Mockclient) GetVM(arg0 context.Context, arg1, arg2 string) (compute.VirtualMachine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVM", arg0, arg1, arg2)
+	ret0, _ := ret[0].(compute.VirtualMachine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVM indicates an expected call of GetVM.
+func (mr *MockclientMockRecorder) GetVM(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVM", reflect.TypeOf((*Mockclient)(nil).GetVM), arg0, arg1, arg2)
+}
+
+// GetVMResultIfDone mocks base method.
+func (m *Mockclient) GetVMResultIfDone(ctx context.Context, future *v1beta1.Future) (compute.VirtualMachine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVMResultIfDone", ctx, future)
+	ret0, _ := ret[0].(compute.VirtualMachine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVMResultIfDone indicates an expected call of GetVMResultIfDone.
+func (mr *MockclientMockRecorder) GetVMResultIfDone(ctx, future interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVMResultIfDone", reflect.TypeOf((*Mockclient)(nil).GetVMResultIfDone), ctx, future)
+}
+
+// UpdateProtectionPolicyAsync mocks base method.
+func (m *Mockclient) UpdateProtectionPolicyAsync(arg0 context.Context, arg1, arg2, arg3 string, arg4 azure.VMSSVMProtectionPolicy) (*v1beta1.Future, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProtectionPolicyAsync", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*v1beta1.Future)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProtectionPolicyAsync indicates an expected call of UpdateProtectionPolicyAsync.
+func (mr *MockclientMockRecorder) UpdateProtectionPolicyAsync(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProtectionPolicyAsync", reflect.TypeOf((*Mockclient)(nil).UpdateProtectionPolicyAsync), arg0, arg1, arg2, arg3, arg4)
+}
+
 // MockgenericScaleSetVMFuture is a mock of genericScaleSetVMFuture interface.
 type MockgenericScaleSetVMFuture struct {
 	ctrl     *gomock.Controller
@@ -150,3 +211,56 @@ func (mr *MockgenericScaleSetVMFutureMockRecorder) Result(client interface{}) *g
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Result", reflect.TypeOf((*MockgenericScaleSetVMFuture)(nil).Result), client)
 }
+
+// MockgenericVMFuture is a mock of genericVMFuture interface.
+type MockgenericVMFuture struct {
+	ctrl     *gomock.Controller
+	recorder *MockgenericVMFutureMockRecorder
+}
+
+// MockgenericVMFutureMockRecorder is the mock recorder for MockgenericVMFuture.
+type MockgenericVMFutureMockRecorder struct {
+	mock *MockgenericVMFuture
+}
+
+// NewMockgenericVMFuture creates a new mock instance.
+func NewMockgenericVMFuture(ctrl *gomock.Controller) *MockgenericVMFuture {
+	mock := &MockgenericVMFuture{ctrl: ctrl}
+	mock.recorder = &MockgenericVMFutureMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockgenericVMFuture) EXPECT() *MockgenericVMFutureMockRecorder {
+	return m.recorder
+}
+
+// DoneWithContext mocks base method.
+func (m *MockgenericVMFuture) DoneWithContext(ctx context.Context, sender autorest.Sender) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DoneWithContext", ctx, sender)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DoneWithContext indicates an expected call of DoneWithContext.
+func (mr *MockgenericVMFutureMockRecorder) DoneWithContext(ctx, sender interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DoneWithContext", reflect.TypeOf((*MockgenericVMFuture)(nil).DoneWithContext), ctx, sender)
+}
+
+// Result mocks base method.
+func (m *MockgenericVMFuture) Result(client compute.VirtualMachinesClient) (compute.VirtualMachine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Result", client)
+	ret0, _ := ret[0].(compute.VirtualMachine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Result indicates an expected call of Result.
+func (mr *MockgenericVMFutureMockRecorder) Result(client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Result", reflect.TypeOf((*MockgenericVMFuture)(nil).Result), client)
+}
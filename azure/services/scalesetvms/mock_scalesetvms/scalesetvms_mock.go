@@ -261,6 +261,34 @@ func (mr *MockScaleSetVMScopeMockRecorder) Location() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockScaleSetVMScope)(nil).Location))
 }
 
+// OrchestrationMode mocks base method.
+func (m *MockScaleSetVMScope) OrchestrationMode() v1beta1.OrchestrationModeType {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OrchestrationMode")
+	ret0, _ := ret[0].(v1beta1.OrchestrationModeType)
+	return ret0
+}
+
+// OrchestrationMode indicates an expected call of OrchestrationMode.
+func (mr *MockScaleSetVMScopeMockRecorder) OrchestrationMode() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrchestrationMode", reflect.TypeOf((*MockScaleSetVMScope)(nil).OrchestrationMode))
+}
+
+// ProtectionPolicy mocks base method.
+func (m *MockScaleSetVMScope) ProtectionPolicy() *azure.VMSSVMProtectionPolicy {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProtectionPolicy")
+	ret0, _ := ret[0].(*azure.VMSSVMProtectionPolicy)
+	return ret0
+}
+
+// ProtectionPolicy indicates an expected call of ProtectionPolicy.
+func (mr *MockScaleSetVMScopeMockRecorder) ProtectionPolicy() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProtectionPolicy", reflect.TypeOf((*MockScaleSetVMScope)(nil).ProtectionPolicy))
+}
+
 // ResourceGroup mocks base method.
 func (m *MockScaleSetVMScope) ResourceGroup() string {
 	m.ctrl.T.Helper()
@@ -36,6 +36,8 @@ type (
 		azure.AsyncStatusUpdater
 		InstanceID() string
 		ScaleSetName() string
+		OrchestrationMode() infrav1.OrchestrationModeType
+		ProtectionPolicy() *azure.VMSSVMProtectionPolicy
 		SetVMSSVM(vmssvm *azure.VMSSVM)
 	}
 
@@ -54,9 +56,9 @@ func NewService(scope ScaleSetVMScope) *Service {
 	}
 }
 
-// Reconcile idempotently gets, creates, and updates a scale set.
+// Reconcile idempotently gets a scale set instance and updates its instance protection.
 func (s *Service) Reconcile(ctx context.Context) error {
-	ctx, _, done := tele.StartSpanWithLogger(ctx, "scalesetvms.Service.Reconcile")
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "scalesetvms.Service.Reconcile")
 	defer done()
 
 	var (
@@ -66,7 +68,7 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	)
 
 	// fetch the latest data about the instance -- model mutations are handled by the AzureMachinePoolReconciler
-	instance, err := s.Client.Get(ctx, resourceGroup, vmssName, instanceID)
+	instance, err := s.getInstance(ctx, resourceGroup, vmssName, instanceID)
 	if err != nil {
 		if azure.ResourceNotFound(err) {
 			return azure.WithTransientError(errors.New("instance does not exist yet"), 30*time.Second)
@@ -74,7 +76,44 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		return errors.Wrap(err, "failed getting instance")
 	}
 
-	s.Scope.SetVMSSVM(converters.SDKToVMSSVM(instance))
+	s.Scope.SetVMSSVM(instance)
+
+	future := s.Scope.GetLongRunningOperationState(instanceID, serviceName)
+	if future != nil {
+		if future.Type != infrav1.PutFuture {
+			return azure.WithTransientError(errors.New("attempting to update, non-update operation in progress"), 30*time.Second)
+		}
+
+		log.V(4).Info("checking if the instance protection is done updating")
+		if err := s.getResultIfDone(ctx, future); err != nil {
+			return errors.Wrap(err, "failed to get result of long running operation")
+		}
+
+		// the updated instance protection will be observed on the next reconciliation
+		s.Scope.DeleteLongRunningOperationState(instanceID, serviceName)
+		return nil
+	}
+
+	policy := s.Scope.ProtectionPolicy()
+	if s.isFlexible() || policy == nil || *policy == instance.ProtectionPolicy || instance.State != infrav1.Succeeded {
+		return nil
+	}
+
+	// instance protection is only available to scale sets in Uniform orchestration mode, and can only be changed once
+	// the instance is done provisioning
+	log.V(4).Info("updating the instance protection", "protectFromScaleIn", policy.ProtectFromScaleIn, "protectFromScaleSetActions", policy.ProtectFromScaleSetActions)
+	future, err = s.Client.UpdateProtectionPolicyAsync(ctx, resourceGroup, vmssName, instanceID, *policy)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update the instance protection of instance %s/%s", vmssName, instanceID)
+	}
+
+	s.Scope.SetLongRunningOperationState(future)
+
+	if err := s.getResultIfDone(ctx, future); err != nil {
+		return errors.Wrap(err, "failed to get result of long running operation")
+	}
+
+	s.Scope.DeleteLongRunningOperationState(instanceID, serviceName)
 	return nil
 }
 
@@ -96,21 +135,27 @@ func (s *Service) Delete(ctx context.Context) error {
 	defer done()
 
 	defer func() {
-		if instance, err := s.Client.Get(ctx, resourceGroup, vmssName, instanceID); err == nil && instance.VirtualMachineScaleSetVMProperties != nil {
-			log.V(4).Info("updating vmss vm state", "state", instance.ProvisioningState)
-			s.Scope.SetVMSSVM(converters.SDKToVMSSVM(instance))
+		if instance, err := s.getInstance(ctx, resourceGroup, vmssName, instanceID); err == nil && instance.State != "" {
+			log.V(4).Info("updating vmss vm state", "state", instance.State)
+			s.Scope.SetVMSSVM(instance)
 		}
 	}()
 
 	log.V(4).Info("entering delete")
 	future := s.Scope.GetLongRunningOperationState(instanceID, serviceName)
+	if future != nil && future.Type == infrav1.PutFuture {
+		// an instance protection update does not need to complete before the instance is deleted
+		log.V(4).Info("dropping in-progress instance protection update")
+		s.Scope.DeleteLongRunningOperationState(instanceID, serviceName)
+		future = nil
+	}
 	if future != nil {
 		if future.Type != infrav1.DeleteFuture {
 			return azure.WithTransientError(errors.New("attempting to delete, non-delete operation in progress"), 30*time.Second)
 		}
 
 		log.V(4).Info("checking if the instance is done deleting")
-		if _, err := s.Client.GetResultIfDone(ctx, future); err != nil {
+		if err := s.getResultIfDone(ctx, future); err != nil {
 			// fetch instance to update status
 			return errors.Wrap(err, "failed to get result of long running operation")
 		}
@@ -122,7 +167,7 @@ func (s *Service) Delete(ctx context.Context) error {
 	}
 
 	// since the future was nil, there is no ongoing activity; start deleting the instance
-	future, err := s.Client.DeleteAsync(ctx, resourceGroup, vmssName, instanceID)
+	future, err := s.deleteInstanceAsync(ctx, resourceGroup, vmssName, instanceID)
 	if err != nil {
 		if azure.ResourceNotFound(err) {
 			// already deleted
@@ -134,7 +179,7 @@ func (s *Service) Delete(ctx context.Context) error {
 	s.Scope.SetLongRunningOperationState(future)
 
 	log.V(4).Info("checking if the instance is done deleting")
-	if _, err := s.Client.GetResultIfDone(ctx, future); err != nil {
+	if err := s.getResultIfDone(ctx, future); err != nil {
 		// fetch instance to update status
 		return errors.Wrap(err, "failed to get result of long running operation")
 	}
@@ -142,3 +187,44 @@ func (s *Service) Delete(ctx context.Context) error {
 	s.Scope.DeleteLongRunningOperationState(instanceID, serviceName)
 	return nil
 }
+
+// isFlexible returns true if the instance belongs to a scale set in Flexible orchestration mode, in which case it is a
+// regular VM managed through the VM API, and the instance ID is the VM name.
+func (s *Service) isFlexible() bool {
+	return s.Scope.OrchestrationMode() == infrav1.FlexibleOrchestrationMode
+}
+
+// getInstance fetches the instance through the API matching the orchestration mode of the scale set.
+func (s *Service) getInstance(ctx context.Context, resourceGroup, vmssName, instanceID string) (*azure.VMSSVM, error) {
+	if s.isFlexible() {
+		vm, err := s.Client.GetVM(ctx, resourceGroup, instanceID)
+		if err != nil {
+			return nil, err
+		}
+		return converters.SDKVMToVMSSVM(vm), nil
+	}
+
+	instance, err := s.Client.Get(ctx, resourceGroup, vmssName, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	return converters.SDKToVMSSVM(instance), nil
+}
+
+// deleteInstanceAsync starts deleting the instance through the API matching the orchestration mode of the scale set.
+func (s *Service) deleteInstanceAsync(ctx context.Context, resourceGroup, vmssName, instanceID string) (*infrav1.Future, error) {
+	if s.isFlexible() {
+		return s.Client.DeleteVMAsync(ctx, resourceGroup, instanceID)
+	}
+	return s.Client.DeleteAsync(ctx, resourceGroup, vmssName, instanceID)
+}
+
+// getResultIfDone checks the long-running operation through the API matching the orchestration mode of the scale set.
+func (s *Service) getResultIfDone(ctx context.Context, future *infrav1.Future) error {
+	if s.isFlexible() {
+		_, err := s.Client.GetVMResultIfDone(ctx, future)
+		return err
+	}
+	_, err := s.Client.GetResultIfDone(ctx, future)
+	return err
+}
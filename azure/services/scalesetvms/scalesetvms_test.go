@@ -101,11 +101,105 @@ func TestService_Reconcile(t *testing.T) {
 				s.ResourceGroup().Return("rg")
 				s.InstanceID().Return("0")
 				s.ScaleSetName().Return("scaleset")
+				s.OrchestrationMode().Return(infrav1.UniformOrchestrationMode).AnyTimes()
 				vm := compute.VirtualMachineScaleSetVM{
 					InstanceID: to.StringPtr("0"),
 				}
 				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(vm, nil)
 				s.SetVMSSVM(converters.SDKToVMSSVM(vm))
+				s.GetLongRunningOperationState("0", serviceName).Return(nil)
+				s.ProtectionPolicy().Return(nil)
+			},
+		},
+		{
+			Name: "should start updating the instance protection if it differs from the desired one",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.ResourceGroup().Return("rg")
+				s.InstanceID().Return("0")
+				s.ScaleSetName().Return("scaleset")
+				s.OrchestrationMode().Return(infrav1.UniformOrchestrationMode).AnyTimes()
+				vm := compute.VirtualMachineScaleSetVM{
+					InstanceID: to.StringPtr("0"),
+					VirtualMachineScaleSetVMProperties: &compute.VirtualMachineScaleSetVMProperties{
+						ProvisioningState: to.StringPtr("Succeeded"),
+					},
+				}
+				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(vm, nil)
+				s.SetVMSSVM(converters.SDKToVMSSVM(vm))
+				s.GetLongRunningOperationState("0", serviceName).Return(nil)
+				policy := azure.VMSSVMProtectionPolicy{ProtectFromScaleIn: true}
+				s.ProtectionPolicy().Return(&policy)
+				future := &infrav1.Future{
+					Type: infrav1.PutFuture,
+				}
+				m.UpdateProtectionPolicyAsync(gomock2.AContext(), "rg", "scaleset", "0", policy).Return(future, nil)
+				s.SetLongRunningOperationState(future)
+				m.GetResultIfDone(gomock2.AContext(), future).Return(compute.VirtualMachineScaleSetVM{}, azure.WithTransientError(azure.NewOperationNotDoneError(future), 15*time.Second))
+			},
+			CheckIsErr: true,
+			Err: errors.Wrap(azure.WithTransientError(azure.NewOperationNotDoneError(&infrav1.Future{
+				Type: infrav1.PutFuture,
+			}), 15*time.Second), "failed to get result of long running operation"),
+		},
+		{
+			Name: "should not update the instance protection if it is already applied",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.ResourceGroup().Return("rg")
+				s.InstanceID().Return("0")
+				s.ScaleSetName().Return("scaleset")
+				s.OrchestrationMode().Return(infrav1.UniformOrchestrationMode).AnyTimes()
+				vm := compute.VirtualMachineScaleSetVM{
+					InstanceID: to.StringPtr("0"),
+					VirtualMachineScaleSetVMProperties: &compute.VirtualMachineScaleSetVMProperties{
+						ProvisioningState: to.StringPtr("Succeeded"),
+						ProtectionPolicy: &compute.VirtualMachineScaleSetVMProtectionPolicy{
+							ProtectFromScaleIn: to.BoolPtr(true),
+						},
+					},
+				}
+				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(vm, nil)
+				s.SetVMSSVM(converters.SDKToVMSSVM(vm))
+				s.GetLongRunningOperationState("0", serviceName).Return(nil)
+				s.ProtectionPolicy().Return(&azure.VMSSVMProtectionPolicy{ProtectFromScaleIn: true})
+			},
+		},
+		{
+			Name: "should not update the instance protection until the instance is done provisioning",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.ResourceGroup().Return("rg")
+				s.InstanceID().Return("0")
+				s.ScaleSetName().Return("scaleset")
+				s.OrchestrationMode().Return(infrav1.UniformOrchestrationMode).AnyTimes()
+				vm := compute.VirtualMachineScaleSetVM{
+					InstanceID: to.StringPtr("0"),
+					VirtualMachineScaleSetVMProperties: &compute.VirtualMachineScaleSetVMProperties{
+						ProvisioningState: to.StringPtr("Updating"),
+					},
+				}
+				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(vm, nil)
+				s.SetVMSSVM(converters.SDKToVMSSVM(vm))
+				s.GetLongRunningOperationState("0", serviceName).Return(nil)
+				s.ProtectionPolicy().Return(&azure.VMSSVMProtectionPolicy{ProtectFromScaleIn: true})
+			},
+		},
+		{
+			Name: "should finish updating the instance protection when the long running operation has completed",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.ResourceGroup().Return("rg")
+				s.InstanceID().Return("0")
+				s.ScaleSetName().Return("scaleset")
+				s.OrchestrationMode().Return(infrav1.UniformOrchestrationMode).AnyTimes()
+				vm := compute.VirtualMachineScaleSetVM{
+					InstanceID: to.StringPtr("0"),
+				}
+				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(vm, nil)
+				s.SetVMSSVM(converters.SDKToVMSSVM(vm))
+				future := &infrav1.Future{
+					Type: infrav1.PutFuture,
+				}
+				s.GetLongRunningOperationState("0", serviceName).Return(future)
+				m.GetResultIfDone(gomock2.AContext(), future).Return(compute.VirtualMachineScaleSetVM{}, nil)
+				s.DeleteLongRunningOperationState("0", serviceName)
 			},
 		},
 		{
@@ -114,6 +208,7 @@ func TestService_Reconcile(t *testing.T) {
 				s.ResourceGroup().Return("rg")
 				s.InstanceID().Return("0")
 				s.ScaleSetName().Return("scaleset")
+				s.OrchestrationMode().Return(infrav1.UniformOrchestrationMode).AnyTimes()
 				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(compute.VirtualMachineScaleSetVM{}, autorest404)
 			},
 			Err:        azure.WithTransientError(errors.New("instance does not exist yet"), 30*time.Second),
@@ -125,10 +220,27 @@ func TestService_Reconcile(t *testing.T) {
 				s.ResourceGroup().Return("rg")
 				s.InstanceID().Return("0")
 				s.ScaleSetName().Return("scaleset")
+				s.OrchestrationMode().Return(infrav1.UniformOrchestrationMode).AnyTimes()
 				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(compute.VirtualMachineScaleSetVM{}, errors.New("boom"))
 			},
 			Err: errors.Wrap(errors.New("boom"), "failed getting instance"),
 		},
+		{
+			Name: "should reconcile an instance of a flexible scale set through the VM API",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.ResourceGroup().Return("rg")
+				s.InstanceID().Return("scaleset_1a2b3c4d")
+				s.ScaleSetName().Return("scaleset")
+				s.OrchestrationMode().Return(infrav1.FlexibleOrchestrationMode).AnyTimes()
+				vm := compute.VirtualMachine{
+					Name: to.StringPtr("scaleset_1a2b3c4d"),
+				}
+				m.GetVM(gomock2.AContext(), "rg", "scaleset_1a2b3c4d").Return(vm, nil)
+				s.SetVMSSVM(converters.SDKVMToVMSSVM(vm))
+				s.GetLongRunningOperationState("scaleset_1a2b3c4d", serviceName).Return(nil)
+				s.ProtectionPolicy().Return(&azure.VMSSVMProtectionPolicy{ProtectFromScaleIn: true})
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -141,9 +253,9 @@ func TestService_Reconcile(t *testing.T) {
 			)
 			defer mockCtrl.Finish()
 
-			scopeMock.EXPECT().SubscriptionID().Return("subID")
-			scopeMock.EXPECT().BaseURI().Return("https://localhost/")
-			scopeMock.EXPECT().Authorizer().Return(nil)
+			scopeMock.EXPECT().SubscriptionID().Return("subID").AnyTimes()
+			scopeMock.EXPECT().BaseURI().Return("https://localhost/").AnyTimes()
+			scopeMock.EXPECT().Authorizer().Return(nil).AnyTimes()
 
 			service := NewService(scopeMock)
 			service.Client = clientMock
@@ -175,6 +287,7 @@ func TestService_Delete(t *testing.T) {
 				s.ResourceGroup().Return("rg")
 				s.InstanceID().Return("0")
 				s.ScaleSetName().Return("scaleset")
+				s.OrchestrationMode().Return(infrav1.UniformOrchestrationMode).AnyTimes()
 				s.GetLongRunningOperationState("0", serviceName).Return(nil)
 				future := &infrav1.Future{
 					Type: infrav1.DeleteFuture,
@@ -195,6 +308,7 @@ func TestService_Delete(t *testing.T) {
 				s.ResourceGroup().Return("rg")
 				s.InstanceID().Return("0")
 				s.ScaleSetName().Return("scaleset")
+				s.OrchestrationMode().Return(infrav1.UniformOrchestrationMode).AnyTimes()
 				future := &infrav1.Future{
 					Type: infrav1.DeleteFuture,
 				}
@@ -204,12 +318,34 @@ func TestService_Delete(t *testing.T) {
 				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(compute.VirtualMachineScaleSetVM{}, nil)
 			},
 		},
+		{
+			Name: "should drop an in-progress instance protection update and start deleting",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.ResourceGroup().Return("rg")
+				s.InstanceID().Return("0")
+				s.ScaleSetName().Return("scaleset")
+				s.OrchestrationMode().Return(infrav1.UniformOrchestrationMode).AnyTimes()
+				s.GetLongRunningOperationState("0", serviceName).Return(&infrav1.Future{
+					Type: infrav1.PutFuture,
+				})
+				s.DeleteLongRunningOperationState("0", serviceName)
+				future := &infrav1.Future{
+					Type: infrav1.DeleteFuture,
+				}
+				m.DeleteAsync(gomock2.AContext(), "rg", "scaleset", "0").Return(future, nil)
+				s.SetLongRunningOperationState(future)
+				m.GetResultIfDone(gomock2.AContext(), future).Return(compute.VirtualMachineScaleSetVM{}, nil)
+				s.DeleteLongRunningOperationState("0", serviceName)
+				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(compute.VirtualMachineScaleSetVM{}, nil)
+			},
+		},
 		{
 			Name: "should not error when deleting, but resource is 404",
 			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
 				s.ResourceGroup().Return("rg")
 				s.InstanceID().Return("0")
 				s.ScaleSetName().Return("scaleset")
+				s.OrchestrationMode().Return(infrav1.UniformOrchestrationMode).AnyTimes()
 				s.GetLongRunningOperationState("0", serviceName).Return(nil)
 				m.DeleteAsync(gomock2.AContext(), "rg", "scaleset", "0").Return(nil, autorest404)
 				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(compute.VirtualMachineScaleSetVM{}, nil)
@@ -221,6 +357,7 @@ func TestService_Delete(t *testing.T) {
 				s.ResourceGroup().Return("rg")
 				s.InstanceID().Return("0")
 				s.ScaleSetName().Return("scaleset")
+				s.OrchestrationMode().Return(infrav1.UniformOrchestrationMode).AnyTimes()
 				s.GetLongRunningOperationState("0", serviceName).Return(nil)
 				m.DeleteAsync(gomock2.AContext(), "rg", "scaleset", "0").Return(nil, errors.New("boom"))
 				m.Get(gomock2.AContext(), "rg", "scaleset", "0").Return(compute.VirtualMachineScaleSetVM{}, nil)
@@ -233,6 +370,7 @@ func TestService_Delete(t *testing.T) {
 				s.ResourceGroup().Return("rg")
 				s.InstanceID().Return("0")
 				s.ScaleSetName().Return("scaleset")
+				s.OrchestrationMode().Return(infrav1.UniformOrchestrationMode).AnyTimes()
 				future := &infrav1.Future{
 					Type: infrav1.DeleteFuture,
 				}
@@ -242,6 +380,24 @@ func TestService_Delete(t *testing.T) {
 			},
 			Err: errors.Wrap(errors.New("boom"), "failed to get result of long running operation"),
 		},
+		{
+			Name: "should start deleting an instance of a flexible scale set through the VM API",
+			Setup: func(s *mock_scalesetvms.MockScaleSetVMScopeMockRecorder, m *mock_scalesetvms.MockclientMockRecorder) {
+				s.ResourceGroup().Return("rg")
+				s.InstanceID().Return("scaleset_1a2b3c4d")
+				s.ScaleSetName().Return("scaleset")
+				s.OrchestrationMode().Return(infrav1.FlexibleOrchestrationMode).AnyTimes()
+				s.GetLongRunningOperationState("scaleset_1a2b3c4d", serviceName).Return(nil)
+				future := &infrav1.Future{
+					Type: infrav1.DeleteFuture,
+				}
+				m.DeleteVMAsync(gomock2.AContext(), "rg", "scaleset_1a2b3c4d").Return(future, nil)
+				s.SetLongRunningOperationState(future)
+				m.GetVMResultIfDone(gomock2.AContext(), future).Return(compute.VirtualMachine{}, nil)
+				s.DeleteLongRunningOperationState("scaleset_1a2b3c4d", serviceName)
+				m.GetVM(gomock2.AContext(), "rg", "scaleset_1a2b3c4d").Return(compute.VirtualMachine{}, autorest404)
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -254,9 +410,9 @@ func TestService_Delete(t *testing.T) {
 			)
 			defer mockCtrl.Finish()
 
-			scopeMock.EXPECT().SubscriptionID().Return("subID")
-			scopeMock.EXPECT().BaseURI().Return("https://localhost/")
-			scopeMock.EXPECT().Authorizer().Return(nil)
+			scopeMock.EXPECT().SubscriptionID().Return("subID").AnyTimes()
+			scopeMock.EXPECT().BaseURI().Return("https://localhost/").AnyTimes()
+			scopeMock.EXPECT().Authorizer().Return(nil).AnyTimes()
 
 			service := NewService(scopeMock)
 			service.Client = clientMock
@@ -19,6 +19,7 @@ package virtualmachines
 import (
 	"context"
 	"encoding/json"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
 	"github.com/Azure/go-autorest/autorest"
@@ -31,11 +32,23 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
+// Client wraps go-sdk.
+type Client interface {
+	Get(context.Context, azure.ResourceSpecGetter) (interface{}, error)
+	ListAvailableSizes(context.Context, string, string) ([]string, error)
+	GetPowerState(context.Context, string, string) (string, error)
+	Deallocate(context.Context, string, string) (azureautorest.FutureAPI, error)
+	Start(context.Context, string, string) (azureautorest.FutureAPI, error)
+	IsDone(context.Context, azureautorest.FutureAPI) (bool, error)
+}
+
 // AzureClient contains the Azure go-sdk Client.
 type AzureClient struct {
 	virtualmachines compute.VirtualMachinesClient
 }
 
+var _ Client = &AzureClient{}
+
 // NewClient creates a new VM client from subscription ID.
 func NewClient(auth azure.Authorizer) *AzureClient {
 	c := newVirtualMachinesClient(auth.SubscriptionID(), auth.BaseURI(), auth.Authorizer())
@@ -57,13 +70,82 @@ func (ac *AzureClient) Get(ctx context.Context, spec azure.ResourceSpecGetter) (
 	return ac.virtualmachines.Get(ctx, spec.ResourceGroupName(), spec.ResourceName(), "")
 }
 
+// ListAvailableSizes lists the names of the sizes to which a virtual machine can be resized without being deallocated.
+func (ac *AzureClient) ListAvailableSizes(ctx context.Context, resourceGroupName, vmName string) ([]string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.ListAvailableSizes")
+	defer done()
+
+	result, err := ac.virtualmachines.ListAvailableSizes(ctx, resourceGroupName, vmName)
+	if err != nil {
+		return nil, err
+	}
+	var sizes []string
+	if result.Value != nil {
+		for _, size := range *result.Value {
+			sizes = append(sizes, to.String(size.Name))
+		}
+	}
+	return sizes, nil
+}
+
+// GetPowerState returns the power state of a virtual machine, e.g. "PowerState/running", or an empty string if it is
+// unknown.
+func (ac *AzureClient) GetPowerState(ctx context.Context, resourceGroupName, vmName string) (string, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.GetPowerState")
+	defer done()
+
+	instanceView, err := ac.virtualmachines.InstanceView(ctx, resourceGroupName, vmName)
+	if err != nil {
+		return "", err
+	}
+	if instanceView.Statuses != nil {
+		for _, status := range *instanceView.Statuses {
+			if strings.HasPrefix(to.String(status.Code), "PowerState/") {
+				return to.String(status.Code), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// Deallocate requests a virtual machine to be deallocated without waiting for the operation to complete, and returns
+// a Future which can be used to track the progress of the operation.
+func (ac *AzureClient) Deallocate(ctx context.Context, resourceGroupName, vmName string) (azureautorest.FutureAPI, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.Deallocate")
+	defer done()
+
+	future, err := ac.virtualmachines.Deallocate(ctx, resourceGroupName, vmName)
+	if err != nil {
+		return nil, err
+	}
+	return &future, nil
+}
+
+// Start requests a virtual machine to be started without waiting for the operation to complete, and returns a Future
+// which can be used to track the progress of the operation.
+func (ac *AzureClient) Start(ctx context.Context, resourceGroupName, vmName string) (azureautorest.FutureAPI, error) {
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.Start")
+	defer done()
+
+	future, err := ac.virtualmachines.Start(ctx, resourceGroupName, vmName)
+	if err != nil {
+		return nil, err
+	}
+	return &future, nil
+}
+
 // CreateOrUpdateAsync creates or updates a virtual machine asynchronously.
-// It sends a PUT request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
+// It sends a PUT request to Azure, or a PATCH request when updating the mutable properties of an existing virtual
+// machine in place, and if accepted without error, the func will return a Future which can be used to track the ongoing
 // progress of the operation.
 func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.ResourceSpecGetter, parameters interface{}) (result interface{}, future azureautorest.FutureAPI, err error) {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.AzureClient.CreateOrUpdate")
 	defer done()
 
+	if update, ok := parameters.(compute.VirtualMachineUpdate); ok {
+		return ac.updateAsync(ctx, spec, update)
+	}
+
 	vm, ok := parameters.(compute.VirtualMachine)
 	if !ok {
 		return nil, nil, errors.Errorf("%T is not a compute.VirtualMachine", parameters)
@@ -88,6 +170,29 @@ func (ac *AzureClient) CreateOrUpdateAsync(ctx context.Context, spec azure.Resou
 	return result, nil, err
 }
 
+// updateAsync updates the mutable properties of a virtual machine asynchronously with a PATCH request.
+// The async reconciler tracks the returned Future as a PUT future. Both futures resolve to the virtual machine read from
+// the final response of the operation, so the result of either future type is the updated virtual machine.
+func (ac *AzureClient) updateAsync(ctx context.Context, spec azure.ResourceSpecGetter, update compute.VirtualMachineUpdate) (result interface{}, future azureautorest.FutureAPI, err error) {
+	updateFuture, err := ac.virtualmachines.Update(ctx, spec.ResourceGroupName(), spec.ResourceName(), update)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultAzureCallTimeout)
+	defer cancel()
+
+	err = updateFuture.WaitForCompletionRef(ctx, ac.virtualmachines.Client)
+	if err != nil {
+		// if an error occurs, return the future.
+		// this means the long-running operation didn't finish in the specified timeout.
+		return nil, &updateFuture, err
+	}
+	result, err = updateFuture.Result(ac.virtualmachines)
+	// if the operation completed, return a nil future
+	return result, nil, err
+}
+
 // DeleteAsync deletes a virtual machine asynchronously. DeleteAsync sends a DELETE
 // request to Azure and if accepted without error, the func will return a Future which can be used to track the ongoing
 // progress of the operation.
@@ -171,6 +276,10 @@ func (ac *AzureClient) Result(ctx context.Context, future azureautorest.FutureAP
 		// Delete does not return a result VM.
 		return nil, nil
 
+	case infrav1.PostFuture:
+		// Deallocate and Start do not return a result VM.
+		return nil, nil
+
 	default:
 		return nil, errors.Errorf("unknown future type %q", futureType)
 	}
@@ -188,6 +188,18 @@ func (mr *MockVMScopeMockRecorder) SetAnnotation(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAnnotation", reflect.TypeOf((*MockVMScope)(nil).SetAnnotation), arg0, arg1)
 }
 
+// SetDeallocatedForResize mocks base method.
+func (m *MockVMScope) SetDeallocatedForResize(arg0 bool) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetDeallocatedForResize", arg0)
+}
+
+// SetDeallocatedForResize indicates an expected call of SetDeallocatedForResize.
+func (mr *MockVMScopeMockRecorder) SetDeallocatedForResize(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDeallocatedForResize", reflect.TypeOf((*MockVMScope)(nil).SetDeallocatedForResize), arg0)
+}
+
 // SetLongRunningOperationState mocks base method.
 func (m *MockVMScope) SetLongRunningOperationState(arg0 *v1beta1.Future) {
 	m.ctrl.T.Helper()
@@ -19,6 +19,8 @@ package virtualmachines
 import (
 	"encoding/base64"
 	"fmt"
+	"reflect"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
 	"github.com/Azure/go-autorest/autorest/to"
@@ -48,11 +50,14 @@ type VMSpec struct {
 	UserAssignedIdentities []infrav1.UserAssignedIdentity
 	SpotVMOptions          *infrav1.SpotVMOptions
 	SecurityProfile        *infrav1.SecurityProfile
+	DiagnosticsProfile     *infrav1.Diagnostics
 	AdditionalTags         infrav1.Tags
 	SKU                    resourceskus.SKU
 	Image                  *infrav1.Image
 	BootstrapData          string
 	ProviderID             string
+	InPlaceUpdate          bool
+	DeallocatedForResize   bool
 }
 
 // ResourceName returns the name of the virtual machine.
@@ -73,9 +78,13 @@ func (s *VMSpec) OwnerResourceName() string {
 // Parameters returns the parameters for the virtual machine.
 func (s *VMSpec) Parameters(existing interface{}) (params interface{}, err error) {
 	if existing != nil {
-		if _, ok := existing.(compute.VirtualMachine); !ok {
+		existingVM, ok := existing.(compute.VirtualMachine)
+		if !ok {
 			return nil, errors.Errorf("%T is not a compute.VirtualMachine", existing)
 		}
+		if s.InPlaceUpdate {
+			return s.updateParameters(existingVM)
+		}
 		// vm already exists
 		return nil, nil
 	}
@@ -132,20 +141,119 @@ func (s *VMSpec) Parameters(existing interface{}) (params interface{}, err error
 			NetworkProfile: &compute.NetworkProfile{
 				NetworkInterfaces: s.generateNICRefs(),
 			},
-			Priority:       priority,
-			EvictionPolicy: evictionPolicy,
-			BillingProfile: billingProfile,
-			DiagnosticsProfile: &compute.DiagnosticsProfile{
-				BootDiagnostics: &compute.BootDiagnostics{
-					Enabled: to.BoolPtr(true),
-				},
-			},
+			Priority:           priority,
+			EvictionPolicy:     evictionPolicy,
+			BillingProfile:     billingProfile,
+			DiagnosticsProfile: converters.GetDiagnosticsProfile(s.DiagnosticsProfile),
 		},
 		Identity: identity,
 		Zones:    s.getZones(),
 	}, nil
 }
 
+// updateParameters returns the parameters to update the mutable properties of an existing virtual machine in place,
+// or nil if the virtual machine is up to date. Only the VM size, new data disks and identities are updated.
+func (s *VMSpec) updateParameters(existing compute.VirtualMachine) (interface{}, error) {
+	if existing.VirtualMachineProperties == nil {
+		return nil, nil
+	}
+
+	// Errors are not terminal here so that an invalid update does not mark a running machine as failed.
+	storageProfile, err := s.generateStorageProfile()
+	if err != nil {
+		return nil, errors.Errorf("failed to update VM %s: %s", s.Name, err.Error())
+	}
+	identity, err := converters.VMIdentityToVMSDK(s.Identity, s.UserAssignedIdentities)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate VM identity")
+	}
+
+	// Only the changed properties are sent so that the update does not overwrite other properties of the VM.
+	update := compute.VirtualMachineUpdate{
+		VirtualMachineProperties: &compute.VirtualMachineProperties{},
+	}
+	changed := false
+
+	if existing.HardwareProfile == nil || !strings.EqualFold(string(existing.HardwareProfile.VMSize), s.Size) {
+		update.HardwareProfile = &compute.HardwareProfile{
+			VMSize: compute.VirtualMachineSizeTypes(s.Size),
+		}
+		changed = true
+	}
+
+	if dataDisks, ok := addedDataDisks(existing.StorageProfile, storageProfile.DataDisks); ok {
+		update.StorageProfile = &compute.StorageProfile{
+			DataDisks: &dataDisks,
+		}
+		changed = true
+	}
+
+	if !identitiesEqual(existing.Identity, identity) {
+		update.Identity = identity
+		if identity == nil {
+			update.Identity = &compute.VirtualMachineIdentity{
+				Type: compute.ResourceIdentityTypeNone,
+			}
+		}
+		changed = true
+	}
+
+	if !changed {
+		return nil, nil
+	}
+	return update, nil
+}
+
+// addedDataDisks returns the existing data disks followed by the desired data disks attached to a LUN that is not in
+// use yet, and whether any data disk was added.
+func addedDataDisks(existing *compute.StorageProfile, desired *[]compute.DataDisk) ([]compute.DataDisk, bool) {
+	var dataDisks []compute.DataDisk
+	if existing != nil && existing.DataDisks != nil {
+		dataDisks = append(dataDisks, *existing.DataDisks...)
+	}
+	if desired == nil {
+		return dataDisks, false
+	}
+
+	usedLUNs := make(map[int32]struct{}, len(dataDisks))
+	for _, disk := range dataDisks {
+		usedLUNs[to.Int32(disk.Lun)] = struct{}{}
+	}
+	added := false
+	for _, disk := range *desired {
+		if _, ok := usedLUNs[to.Int32(disk.Lun)]; ok {
+			continue
+		}
+		dataDisks = append(dataDisks, disk)
+		added = true
+	}
+	return dataDisks, added
+}
+
+// identitiesEqual returns true if both identities have the same type and user assigned identities.
+func identitiesEqual(existing, desired *compute.VirtualMachineIdentity) bool {
+	identityType := func(identity *compute.VirtualMachineIdentity) compute.ResourceIdentityType {
+		if identity == nil || identity.Type == "" {
+			return compute.ResourceIdentityTypeNone
+		}
+		return identity.Type
+	}
+	if !strings.EqualFold(string(identityType(existing)), string(identityType(desired))) {
+		return false
+	}
+
+	userAssignedIdentities := func(identity *compute.VirtualMachineIdentity) map[string]struct{} {
+		ids := make(map[string]struct{})
+		if identity != nil {
+			for id := range identity.UserAssignedIdentities {
+				ids[strings.ToLower(id)] = struct{}{}
+			}
+		}
+		return ids
+	}
+	return reflect.DeepEqual(userAssignedIdentities(existing), userAssignedIdentities(desired))
+}
+
 // generateStorageProfile generates a pointer to a compute.StorageProfile which can utilized for VM creation.
 func (s *VMSpec) generateStorageProfile() (*compute.StorageProfile, error) {
 	storageProfile := &compute.StorageProfile{
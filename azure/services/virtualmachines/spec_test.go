@@ -191,6 +191,99 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "returns nil if in-place update is enabled and vm is up to date",
+			spec: &VMSpec{
+				Name:          "my-vm",
+				Size:          "Standard_D2v3",
+				SKU:           validSKU,
+				Image:         &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				InPlaceUpdate: true,
+			},
+			existing: compute.VirtualMachine{
+				VirtualMachineProperties: &compute.VirtualMachineProperties{
+					HardwareProfile: &compute.HardwareProfile{VMSize: "Standard_D2v3"},
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeNil())
+			},
+			expectedError: "",
+		},
+		{
+			name: "can update vm size, data disks and identity in place",
+			spec: &VMSpec{
+				Name:     "my-vm",
+				Size:     "Standard_D4v3",
+				SKU:      validSKU,
+				Image:    &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				Identity: infrav1.VMIdentitySystemAssigned,
+				DataDisks: []infrav1.DataDisk{
+					{
+						NameSuffix: "etcddisk",
+						DiskSizeGB: 256,
+						Lun:        to.Int32Ptr(0),
+					},
+					{
+						NameSuffix: "mydisk",
+						DiskSizeGB: 64,
+						Lun:        to.Int32Ptr(1),
+					},
+				},
+				InPlaceUpdate: true,
+			},
+			existing: compute.VirtualMachine{
+				Location: to.StringPtr("test-location"),
+				VirtualMachineProperties: &compute.VirtualMachineProperties{
+					HardwareProfile: &compute.HardwareProfile{VMSize: "Standard_D2v3"},
+					StorageProfile: &compute.StorageProfile{
+						DataDisks: &[]compute.DataDisk{
+							{
+								Name: to.StringPtr("my-vm_etcddisk"),
+								Lun:  to.Int32Ptr(0),
+							},
+						},
+					},
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachineUpdate{}))
+				vm := result.(compute.VirtualMachineUpdate)
+				g.Expect(vm.OsProfile).To(BeNil())
+				g.Expect(vm.HardwareProfile.VMSize).To(Equal(compute.VirtualMachineSizeTypes("Standard_D4v3")))
+				g.Expect(*vm.StorageProfile.DataDisks).To(HaveLen(2))
+				g.Expect((*vm.StorageProfile.DataDisks)[0].Name).To(Equal(to.StringPtr("my-vm_etcddisk")))
+				g.Expect((*vm.StorageProfile.DataDisks)[1].Name).To(Equal(to.StringPtr("my-vm_mydisk")))
+				g.Expect((*vm.StorageProfile.DataDisks)[1].CreateOption).To(Equal(compute.DiskCreateOptionTypesEmpty))
+				g.Expect(vm.Identity.Type).To(Equal(compute.ResourceIdentityTypeSystemAssigned))
+			},
+			expectedError: "",
+		},
+		{
+			name: "removes identities in place",
+			spec: &VMSpec{
+				Name:          "my-vm",
+				Size:          "Standard_D2v3",
+				SKU:           validSKU,
+				Image:         &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				InPlaceUpdate: true,
+			},
+			existing: compute.VirtualMachine{
+				Identity: &compute.VirtualMachineIdentity{
+					Type: compute.ResourceIdentityTypeSystemAssigned,
+				},
+				VirtualMachineProperties: &compute.VirtualMachineProperties{
+					HardwareProfile: &compute.HardwareProfile{VMSize: "Standard_D2v3"},
+				},
+			},
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachineUpdate{}))
+				vm := result.(compute.VirtualMachineUpdate)
+				g.Expect(vm.HardwareProfile).To(BeNil())
+				g.Expect(vm.Identity.Type).To(Equal(compute.ResourceIdentityTypeNone))
+			},
+			expectedError: "",
+		},
 		{
 			name: "fails if vm deleted out of band, should not recreate",
 			spec: &VMSpec{
@@ -364,6 +457,56 @@ func TestParameters(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "can create a vm with user managed boot diagnostics",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				Image:      &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				SKU:        validSKU,
+				DiagnosticsProfile: &infrav1.Diagnostics{
+					Boot: &infrav1.BootDiagnostics{
+						StorageAccountType: infrav1.UserManagedDiagnosticsStorage,
+						UserManaged: &infrav1.UserManagedBootDiagnostics{
+							StorageAccountURI: "https://fake.blob.core.windows.net/",
+						},
+					},
+				},
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
+				g.Expect(result.(compute.VirtualMachine).DiagnosticsProfile.BootDiagnostics.Enabled).To(Equal(to.BoolPtr(true)))
+				g.Expect(result.(compute.VirtualMachine).DiagnosticsProfile.BootDiagnostics.StorageURI).To(Equal(to.StringPtr("https://fake.blob.core.windows.net/")))
+			},
+			expectedError: "",
+		},
+		{
+			name: "can create a vm with boot diagnostics disabled",
+			spec: &VMSpec{
+				Name:       "my-vm",
+				Role:       infrav1.Node,
+				NICIDs:     []string{"my-nic"},
+				SSHKeyData: "fakesshpublickey",
+				Size:       "Standard_D2v3",
+				Image:      &infrav1.Image{ID: to.StringPtr("fake-image-id")},
+				SKU:        validSKU,
+				DiagnosticsProfile: &infrav1.Diagnostics{
+					Boot: &infrav1.BootDiagnostics{
+						StorageAccountType: infrav1.DisabledDiagnosticsStorage,
+					},
+				},
+			},
+			existing: nil,
+			expect: func(g *WithT, result interface{}) {
+				g.Expect(result).To(BeAssignableToTypeOf(compute.VirtualMachine{}))
+				g.Expect(result.(compute.VirtualMachine).DiagnosticsProfile.BootDiagnostics.Enabled).To(Equal(to.BoolPtr(false)))
+			},
+			expectedError: "",
+		},
 		{
 			name: "can create a vm with EphemeralOSDisk",
 			spec: &VMSpec{
@@ -19,9 +19,11 @@ package virtualmachines
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -35,7 +37,14 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
 
-const serviceName = "virtualmachine"
+const (
+	serviceName = "virtualmachine"
+
+	// powerStateDeallocated is the power state of a deallocated virtual machine.
+	powerStateDeallocated = "PowerState/deallocated"
+	// resizeRequeueAfter is the time to wait before checking again whether a VM being resized is deallocated.
+	resizeRequeueAfter = 15 * time.Second
+)
 
 // VMScope defines the scope interface for a virtual machines service.
 type VMScope interface {
@@ -46,12 +55,14 @@ type VMScope interface {
 	SetProviderID(string)
 	SetAddresses([]corev1.NodeAddress)
 	SetVMState(infrav1.ProvisioningState)
+	SetDeallocatedForResize(bool)
 }
 
 // Service provides operations on Azure resources.
 type Service struct {
 	Scope VMScope
 	async.Reconciler
+	client           Client
 	interfacesGetter async.Getter
 	publicIPsClient  publicips.Client
 }
@@ -61,6 +72,7 @@ func New(scope VMScope) *Service {
 	Client := NewClient(scope)
 	return &Service{
 		Scope:            scope,
+		client:           Client,
 		interfacesGetter: networkinterfaces.NewClient(scope),
 		publicIPsClient:  publicips.NewClient(scope),
 		Reconciler:       async.New(scope, Client, Client),
@@ -77,6 +89,12 @@ func (s *Service) Reconcile(ctx context.Context) error {
 
 	vmSpec := s.Scope.VMSpec()
 
+	if spec, ok := vmSpec.(*VMSpec); ok && spec.InPlaceUpdate {
+		if err := s.reconcileResize(ctx, spec); err != nil {
+			return err
+		}
+	}
+
 	result, err := s.CreateResource(ctx, vmSpec, serviceName)
 	s.Scope.UpdatePutStatus(infrav1.VMRunningCondition, serviceName, err)
 	// Set the DiskReady condition here since the disk gets created with the VM.
@@ -104,6 +122,124 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	return err
 }
 
+// reconcileResize deallocates the virtual machine before it is updated in place when its new size is not available on
+// the current hardware cluster, and starts it again once it has been resized.
+func (s *Service) reconcileResize(ctx context.Context, spec *VMSpec) error {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "virtualmachines.Service.reconcileResize")
+	defer done()
+
+	if future := s.Scope.GetLongRunningOperationState(spec.ResourceName(), serviceName); future != nil {
+		if future.Type != infrav1.PostFuture {
+			// Let an ongoing update of the VM complete first.
+			return nil
+		}
+		// Check on an ongoing deallocation or start of the VM.
+		if err := s.processPowerOperation(ctx, future); err != nil {
+			return err
+		}
+	}
+
+	existing, err := s.client.Get(ctx, spec)
+	if azure.ResourceNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Wrapf(err, "failed to get VM %s", spec.ResourceName())
+	}
+	vm, ok := existing.(compute.VirtualMachine)
+	if !ok {
+		return errors.Errorf("%T is not a compute.VirtualMachine", existing)
+	}
+	if vm.VirtualMachineProperties == nil || vm.HardwareProfile == nil {
+		return nil
+	}
+
+	if strings.EqualFold(string(vm.HardwareProfile.VMSize), spec.Size) {
+		if spec.DeallocatedForResize && to.String(vm.ProvisioningState) == string(infrav1.Succeeded) {
+			log.V(2).Info("starting VM after resize", "vm", spec.ResourceName(), "size", spec.Size)
+			sdkFuture, err := s.client.Start(ctx, spec.ResourceGroupName(), spec.ResourceName())
+			if err != nil {
+				return errors.Wrapf(err, "failed to start VM %s after resize", spec.ResourceName())
+			}
+			s.Scope.SetDeallocatedForResize(false)
+			if err := s.setPowerOperationState(spec, sdkFuture); err != nil {
+				return err
+			}
+			return azure.WithTransientError(errors.Errorf("waiting for VM %s to be started after resizing it", spec.ResourceName()), resizeRequeueAfter)
+		}
+		return nil
+	}
+
+	if spec.DeallocatedForResize {
+		powerState, err := s.client.GetPowerState(ctx, spec.ResourceGroupName(), spec.ResourceName())
+		if err != nil {
+			return errors.Wrapf(err, "failed to get power state of VM %s", spec.ResourceName())
+		}
+		if powerState != powerStateDeallocated {
+			s.Scope.SetVMState(infrav1.Updating)
+			return azure.WithTransientError(errors.Errorf("waiting for VM %s to be deallocated before resizing it", spec.ResourceName()), resizeRequeueAfter)
+		}
+		return nil
+	}
+
+	sizes, err := s.client.ListAvailableSizes(ctx, spec.ResourceGroupName(), spec.ResourceName())
+	if err != nil {
+		return errors.Wrapf(err, "failed to list available sizes for VM %s", spec.ResourceName())
+	}
+	for _, size := range sizes {
+		if strings.EqualFold(size, spec.Size) {
+			// The VM can be resized without being deallocated.
+			return nil
+		}
+	}
+
+	log.V(2).Info("deallocating VM to resize it", "vm", spec.ResourceName(), "size", spec.Size)
+	sdkFuture, err := s.client.Deallocate(ctx, spec.ResourceGroupName(), spec.ResourceName())
+	if err != nil {
+		return errors.Wrapf(err, "failed to deallocate VM %s", spec.ResourceName())
+	}
+	s.Scope.SetDeallocatedForResize(true)
+	s.Scope.SetVMState(infrav1.Updating)
+	if err := s.setPowerOperationState(spec, sdkFuture); err != nil {
+		return err
+	}
+	return azure.WithTransientError(errors.Errorf("waiting for VM %s to be deallocated before resizing it", spec.ResourceName()), resizeRequeueAfter)
+}
+
+// setPowerOperationState stores the future of a deallocation or start of the virtual machine so that the operation is
+// tracked across reconciliations.
+func (s *Service) setPowerOperationState(spec *VMSpec, sdkFuture azureautorest.FutureAPI) error {
+	if sdkFuture == nil {
+		return nil
+	}
+	future, err := converters.SDKToFuture(sdkFuture, infrav1.PostFuture, serviceName, spec.ResourceName(), spec.ResourceGroupName())
+	if err != nil {
+		return errors.Wrapf(err, "failed to store the operation state of VM %s", spec.ResourceName())
+	}
+	s.Scope.SetLongRunningOperationState(future)
+	return nil
+}
+
+// processPowerOperation checks on an ongoing deallocation or start of the virtual machine, and returns a transient
+// error while it is in progress.
+func (s *Service) processPowerOperation(ctx context.Context, future *infrav1.Future) error {
+	sdkFuture, err := converters.FutureToSDK(*future)
+	if err != nil {
+		// Reset the future data to avoid getting stuck trying to parse it.
+		s.Scope.DeleteLongRunningOperationState(future.Name, serviceName)
+		return errors.Wrap(err, "could not decode future data, resetting long-running operation state")
+	}
+
+	isDone, err := s.client.IsDone(ctx, sdkFuture)
+	if err != nil {
+		return errors.Wrap(err, "failed checking if the operation was complete")
+	}
+	if !isDone {
+		return azure.WithTransientError(azure.NewOperationNotDoneError(future), resizeRequeueAfter)
+	}
+	s.Scope.DeleteLongRunningOperationState(future.Name, serviceName)
+	return nil
+}
+
 // Delete deletes the virtual machine with the provided name.
 func (s *Service) Delete(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "virtualmachines.Service.Delete")
@@ -24,6 +24,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
 	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
@@ -195,6 +196,152 @@ func TestReconcileVM(t *testing.T) {
 	}
 }
 
+func TestReconcileVMResize(t *testing.T) {
+	resizeSpec := fakeVMSpec
+	resizeSpec.Size = "Standard_D4v3"
+	resizeSpec.InPlaceUpdate = true
+	deallocatedSpec := resizeSpec
+	deallocatedSpec.DeallocatedForResize = true
+	powerOperationFuture := infrav1.Future{
+		Type:          infrav1.PostFuture,
+		ServiceName:   serviceName,
+		Name:          "test-vm",
+		ResourceGroup: "test-group",
+		Data:          "eyJtZXRob2QiOiJQT1NUIiwicG9sbGluZ01ldGhvZCI6IkxvY2F0aW9uIiwibHJvU3RhdGUiOiJJblByb2dyZXNzIn0=",
+	}
+	existingVM := func(size string) compute.VirtualMachine {
+		return compute.VirtualMachine{
+			VirtualMachineProperties: &compute.VirtualMachineProperties{
+				ProvisioningState: to.StringPtr("Succeeded"),
+				HardwareProfile:   &compute.HardwareProfile{VMSize: compute.VirtualMachineSizeTypes(size)},
+			},
+		}
+	}
+
+	testcases := []struct {
+		name          string
+		spec          VMSpec
+		expectedError string
+		expect        func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder)
+	}{
+		{
+			name:          "does not deallocate the vm if the new size is available",
+			spec:          resizeSpec,
+			expectedError: "",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				s.GetLongRunningOperationState("test-vm", serviceName).Return(nil)
+				m.Get(gomockinternal.AContext(), gomock.Any()).Return(existingVM("Standard_D2v3"), nil)
+				m.ListAvailableSizes(gomockinternal.AContext(), "test-group", "test-vm").Return([]string{"Standard_D2v3", "Standard_D4v3"}, nil)
+			},
+		},
+		{
+			name:          "deallocates the vm if the new size is not available",
+			spec:          resizeSpec,
+			expectedError: "waiting for VM test-vm to be deallocated before resizing it. Object will be requeued after 15s",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				s.GetLongRunningOperationState("test-vm", serviceName).Return(nil)
+				m.Get(gomockinternal.AContext(), gomock.Any()).Return(existingVM("Standard_D2v3"), nil)
+				m.ListAvailableSizes(gomockinternal.AContext(), "test-group", "test-vm").Return([]string{"Standard_D2v3"}, nil)
+				m.Deallocate(gomockinternal.AContext(), "test-group", "test-vm").Return(&azureautorest.Future{}, nil)
+				s.SetDeallocatedForResize(true)
+				s.SetVMState(infrav1.Updating)
+				s.SetLongRunningOperationState(gomock.AssignableToTypeOf(&infrav1.Future{}))
+			},
+		},
+		{
+			name:          "waits for the vm to be deallocated",
+			spec:          deallocatedSpec,
+			expectedError: "waiting for VM test-vm to be deallocated before resizing it. Object will be requeued after 15s",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				s.GetLongRunningOperationState("test-vm", serviceName).Return(nil)
+				m.Get(gomockinternal.AContext(), gomock.Any()).Return(existingVM("Standard_D2v3"), nil)
+				m.GetPowerState(gomockinternal.AContext(), "test-group", "test-vm").Return("PowerState/deallocating", nil)
+				s.SetVMState(infrav1.Updating)
+			},
+		},
+		{
+			name:          "resizes the vm once it is deallocated",
+			spec:          deallocatedSpec,
+			expectedError: "",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				s.GetLongRunningOperationState("test-vm", serviceName).Return(nil)
+				m.Get(gomockinternal.AContext(), gomock.Any()).Return(existingVM("Standard_D2v3"), nil)
+				m.GetPowerState(gomockinternal.AContext(), "test-group", "test-vm").Return("PowerState/deallocated", nil)
+			},
+		},
+		{
+			name:          "starts the vm after it was resized",
+			spec:          deallocatedSpec,
+			expectedError: "waiting for VM test-vm to be started after resizing it. Object will be requeued after 15s",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				s.GetLongRunningOperationState("test-vm", serviceName).Return(nil)
+				m.Get(gomockinternal.AContext(), gomock.Any()).Return(existingVM("Standard_D4v3"), nil)
+				m.Start(gomockinternal.AContext(), "test-group", "test-vm").Return(&azureautorest.Future{}, nil)
+				s.SetDeallocatedForResize(false)
+				s.SetLongRunningOperationState(gomock.AssignableToTypeOf(&infrav1.Future{}))
+			},
+		},
+		{
+			name:          "waits for an ongoing update to complete",
+			spec:          deallocatedSpec,
+			expectedError: "",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				s.GetLongRunningOperationState("test-vm", serviceName).Return(&infrav1.Future{Type: infrav1.PutFuture})
+			},
+		},
+		{
+			name:          "waits for an ongoing deallocation to complete",
+			spec:          deallocatedSpec,
+			expectedError: "operation type POST on Azure resource test-group/test-vm is not done. Object will be requeued after 15s",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				s.GetLongRunningOperationState("test-vm", serviceName).Return(&powerOperationFuture)
+				m.IsDone(gomockinternal.AContext(), gomock.AssignableToTypeOf(&azureautorest.Future{})).Return(false, nil)
+			},
+		},
+		{
+			name:          "resizes the vm once the deallocation has completed",
+			spec:          deallocatedSpec,
+			expectedError: "",
+			expect: func(s *mock_virtualmachines.MockVMScopeMockRecorder, m *mock_virtualmachines.MockClientMockRecorder) {
+				s.GetLongRunningOperationState("test-vm", serviceName).Return(&powerOperationFuture)
+				m.IsDone(gomockinternal.AContext(), gomock.AssignableToTypeOf(&azureautorest.Future{})).Return(true, nil)
+				s.DeleteLongRunningOperationState("test-vm", serviceName)
+				m.Get(gomockinternal.AContext(), gomock.Any()).Return(existingVM("Standard_D2v3"), nil)
+				m.GetPowerState(gomockinternal.AContext(), "test-group", "test-vm").Return("PowerState/deallocated", nil)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Parallel()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			scopeMock := mock_virtualmachines.NewMockVMScope(mockCtrl)
+			clientMock := mock_virtualmachines.NewMockClient(mockCtrl)
+
+			tc.expect(scopeMock.EXPECT(), clientMock.EXPECT())
+
+			s := &Service{
+				Scope:  scopeMock,
+				client: clientMock,
+			}
+
+			spec := tc.spec
+			err := s.reconcileResize(context.TODO(), &spec)
+			if tc.expectedError != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err).To(MatchError(tc.expectedError))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
 func TestDeleteVM(t *testing.T) {
 	testcases := []struct {
 		name          string
@@ -249,6 +249,18 @@ func (mr *MockVMExtensionScopeMockRecorder) SetBootstrapConditions(arg0, arg1, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBootstrapConditions", reflect.TypeOf((*MockVMExtensionScope)(nil).SetBootstrapConditions), arg0, arg1, arg2)
 }
 
+// SetVMExtensionStatus mocks base method.
+func (m *MockVMExtensionScope) SetVMExtensionStatus(arg0 string, arg1 v1beta1.ProvisioningState) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetVMExtensionStatus", arg0, arg1)
+}
+
+// SetVMExtensionStatus indicates an expected call of SetVMExtensionStatus.
+func (mr *MockVMExtensionScopeMockRecorder) SetVMExtensionStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVMExtensionStatus", reflect.TypeOf((*MockVMExtensionScope)(nil).SetVMExtensionStatus), arg0, arg1)
+}
+
 // SubscriptionID mocks base method.
 func (m *MockVMExtensionScope) SubscriptionID() string {
 	m.ctrl.T.Helper()
@@ -278,15 +290,16 @@ func (mr *MockVMExtensionScopeMockRecorder) TenantID() *gomock.Call {
 }
 
 // VMExtensionSpecs mocks base method.
-func (m *MockVMExtensionScope) VMExtensionSpecs() []azure.ExtensionSpec {
+func (m *MockVMExtensionScope) VMExtensionSpecs(arg0 context.Context) ([]azure.ExtensionSpec, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "VMExtensionSpecs")
+	ret := m.ctrl.Call(m, "VMExtensionSpecs", arg0)
 	ret0, _ := ret[0].([]azure.ExtensionSpec)
-	return ret0
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // VMExtensionSpecs indicates an expected call of VMExtensionSpecs.
-func (mr *MockVMExtensionScopeMockRecorder) VMExtensionSpecs() *gomock.Call {
+func (mr *MockVMExtensionScopeMockRecorder) VMExtensionSpecs(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VMExtensionSpecs", reflect.TypeOf((*MockVMExtensionScope)(nil).VMExtensionSpecs))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VMExtensionSpecs", reflect.TypeOf((*MockVMExtensionScope)(nil).VMExtensionSpecs), arg0)
 }
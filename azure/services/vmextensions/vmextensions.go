@@ -22,6 +22,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2021-04-01/compute"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
@@ -29,8 +30,9 @@ import (
 // VMExtensionScope defines the scope interface for a vm extension service.
 type VMExtensionScope interface {
 	azure.ClusterDescriber
-	VMExtensionSpecs() []azure.ExtensionSpec
+	VMExtensionSpecs(context.Context) ([]azure.ExtensionSpec, error)
 	SetBootstrapConditions(context.Context, string, string) error
+	SetVMExtensionStatus(string, infrav1.ProvisioningState)
 }
 
 // Service provides operations on Azure resources.
@@ -52,8 +54,14 @@ func (s *Service) Reconcile(ctx context.Context) error {
 	ctx, log, done := tele.StartSpanWithLogger(ctx, "vmextensions.Service.Reconcile")
 	defer done()
 
-	for _, extensionSpec := range s.Scope.VMExtensionSpecs() {
+	extensionSpecs, err := s.Scope.VMExtensionSpecs(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get vm extension specs")
+	}
+
+	for _, extensionSpec := range extensionSpecs {
 		if existing, err := s.client.Get(ctx, s.Scope.ResourceGroup(), extensionSpec.VMName, extensionSpec.Name); err == nil {
+			s.Scope.SetVMExtensionStatus(extensionSpec.Name, infrav1.ProvisioningState(to.String(existing.ProvisioningState)))
 			// check the extension status and set the associated conditions.
 			if retErr := s.Scope.SetBootstrapConditions(ctx, to.String(existing.ProvisioningState), extensionSpec.Name); retErr != nil {
 				return retErr
@@ -64,6 +72,11 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			return errors.Wrapf(err, "failed to get vm extension %s on vm %s", extensionSpec.Name, extensionSpec.VMName)
 		}
 
+		var settings interface{}
+		if len(extensionSpec.Settings) > 0 {
+			settings = extensionSpec.Settings
+		}
+
 		log.V(2).Info("creating VM extension", "vm extension", extensionSpec.Name)
 		err := s.client.CreateOrUpdateAsync(
 			ctx,
@@ -73,9 +86,9 @@ func (s *Service) Reconcile(ctx context.Context) error {
 			compute.VirtualMachineExtension{
 				VirtualMachineExtensionProperties: &compute.VirtualMachineExtensionProperties{
 					Publisher:          to.StringPtr(extensionSpec.Publisher),
-					Type:               to.StringPtr(extensionSpec.Name),
+					Type:               to.StringPtr(extensionSpec.Type),
 					TypeHandlerVersion: to.StringPtr(extensionSpec.Version),
-					Settings:           nil,
+					Settings:           settings,
 					ProtectedSettings:  extensionSpec.ProtectedSettings,
 				},
 				Location: to.StringPtr(s.Scope.Location()),
@@ -84,6 +97,7 @@ func (s *Service) Reconcile(ctx context.Context) error {
 		if err != nil {
 			return errors.Wrapf(err, "failed to create VM extension %s on VM %s in resource group %s", extensionSpec.Name, extensionSpec.VMName, s.Scope.ResourceGroup())
 		}
+		s.Scope.SetVMExtensionStatus(extensionSpec.Name, infrav1.Creating)
 		log.V(2).Info("successfully created VM extension", "vm extension", extensionSpec.Name)
 	}
 	return nil
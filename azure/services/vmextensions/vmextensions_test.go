@@ -26,6 +26,8 @@ import (
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vmextensions/mock_vmextensions"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
@@ -41,14 +43,14 @@ func TestReconcileVMExtension(t *testing.T) {
 			name:          "extension is in succeeded state",
 			expectedError: "",
 			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, m *mock_vmextensions.MockclientMockRecorder) {
-				s.VMExtensionSpecs().Return([]azure.ExtensionSpec{
+				s.VMExtensionSpecs(gomockinternal.AContext()).Return([]azure.ExtensionSpec{
 					{
 						Name:      "my-extension-1",
 						VMName:    "my-vm",
 						Publisher: "some-publisher",
 						Version:   "1.0",
 					},
-				})
+				}, nil)
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("test-location")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-vm", "my-extension-1").Return(compute.VirtualMachineExtension{
@@ -60,6 +62,7 @@ func TestReconcileVMExtension(t *testing.T) {
 					ID:   to.StringPtr("fake/id"),
 					Name: to.StringPtr("my-extension-1"),
 				}, nil)
+				s.SetVMExtensionStatus("my-extension-1", infrav1.ProvisioningState(compute.ProvisioningStateSucceeded))
 				s.SetBootstrapConditions(gomockinternal.AContext(), string(compute.ProvisioningStateSucceeded), "my-extension-1")
 			},
 		},
@@ -67,14 +70,14 @@ func TestReconcileVMExtension(t *testing.T) {
 			name:          "extension is in failed state",
 			expectedError: "",
 			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, m *mock_vmextensions.MockclientMockRecorder) {
-				s.VMExtensionSpecs().Return([]azure.ExtensionSpec{
+				s.VMExtensionSpecs(gomockinternal.AContext()).Return([]azure.ExtensionSpec{
 					{
 						Name:      "my-extension-1",
 						VMName:    "my-vm",
 						Publisher: "some-publisher",
 						Version:   "1.0",
 					},
-				})
+				}, nil)
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("test-location")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-vm", "my-extension-1").Return(compute.VirtualMachineExtension{
@@ -86,6 +89,7 @@ func TestReconcileVMExtension(t *testing.T) {
 					ID:   to.StringPtr("fake/id"),
 					Name: to.StringPtr("my-extension-1"),
 				}, nil)
+				s.SetVMExtensionStatus("my-extension-1", infrav1.ProvisioningState(compute.ProvisioningStateFailed))
 				s.SetBootstrapConditions(gomockinternal.AContext(), string(compute.ProvisioningStateFailed), "my-extension-1")
 			},
 		},
@@ -93,14 +97,14 @@ func TestReconcileVMExtension(t *testing.T) {
 			name:          "extension is still creating",
 			expectedError: "",
 			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, m *mock_vmextensions.MockclientMockRecorder) {
-				s.VMExtensionSpecs().Return([]azure.ExtensionSpec{
+				s.VMExtensionSpecs(gomockinternal.AContext()).Return([]azure.ExtensionSpec{
 					{
 						Name:      "my-extension-1",
 						VMName:    "my-vm",
 						Publisher: "some-publisher",
 						Version:   "1.0",
 					},
-				})
+				}, nil)
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("test-location")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-vm", "my-extension-1").Return(compute.VirtualMachineExtension{
@@ -112,6 +116,7 @@ func TestReconcileVMExtension(t *testing.T) {
 					ID:   to.StringPtr("fake/id"),
 					Name: to.StringPtr("my-extension-1"),
 				}, nil)
+				s.SetVMExtensionStatus("my-extension-1", infrav1.ProvisioningState(compute.ProvisioningStateCreating))
 				s.SetBootstrapConditions(gomockinternal.AContext(), string(compute.ProvisioningStateCreating), "my-extension-1")
 			},
 		},
@@ -119,7 +124,7 @@ func TestReconcileVMExtension(t *testing.T) {
 			name:          "reconcile multiple extensions",
 			expectedError: "",
 			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, m *mock_vmextensions.MockclientMockRecorder) {
-				s.VMExtensionSpecs().Return([]azure.ExtensionSpec{
+				s.VMExtensionSpecs(gomockinternal.AContext()).Return([]azure.ExtensionSpec{
 					{
 						Name:      "my-extension-1",
 						VMName:    "my-vm",
@@ -132,22 +137,71 @@ func TestReconcileVMExtension(t *testing.T) {
 						Publisher: "other-publisher",
 						Version:   "2.0",
 					},
-				})
+				}, nil)
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("test-location")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-vm", "my-extension-1").
 					Return(compute.VirtualMachineExtension{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-vm", "my-extension-1", gomock.AssignableToTypeOf(compute.VirtualMachineExtension{}))
+				s.SetVMExtensionStatus("my-extension-1", infrav1.Creating)
 				m.Get(gomockinternal.AContext(), "my-rg", "my-vm", "other-extension").
 					Return(compute.VirtualMachineExtension{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
 				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-vm", "other-extension", gomock.AssignableToTypeOf(compute.VirtualMachineExtension{}))
+				s.SetVMExtensionStatus("other-extension", infrav1.Creating)
+			},
+		},
+		{
+			name:          "create a user-defined extension with settings",
+			expectedError: "",
+			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, m *mock_vmextensions.MockclientMockRecorder) {
+				s.VMExtensionSpecs(gomockinternal.AContext()).Return([]azure.ExtensionSpec{
+					{
+						Name:      "my-script",
+						VMName:    "my-vm",
+						Publisher: "Microsoft.Azure.Extensions",
+						Type:      "CustomScript",
+						Version:   "2.1",
+						Settings: map[string]string{
+							"commandToExecute": "echo hello",
+						},
+						ProtectedSettings: map[string]string{
+							"storageAccountKey": "secret",
+						},
+					},
+				}, nil)
+				s.ResourceGroup().AnyTimes().Return("my-rg")
+				s.Location().AnyTimes().Return("test-location")
+				m.Get(gomockinternal.AContext(), "my-rg", "my-vm", "my-script").
+					Return(compute.VirtualMachineExtension{}, autorest.NewErrorWithResponse("", "", &http.Response{StatusCode: 404}, "Not found"))
+				m.CreateOrUpdateAsync(gomockinternal.AContext(), "my-rg", "my-vm", "my-script", compute.VirtualMachineExtension{
+					VirtualMachineExtensionProperties: &compute.VirtualMachineExtensionProperties{
+						Publisher:          to.StringPtr("Microsoft.Azure.Extensions"),
+						Type:               to.StringPtr("CustomScript"),
+						TypeHandlerVersion: to.StringPtr("2.1"),
+						Settings: map[string]string{
+							"commandToExecute": "echo hello",
+						},
+						ProtectedSettings: map[string]string{
+							"storageAccountKey": "secret",
+						},
+					},
+					Location: to.StringPtr("test-location"),
+				})
+				s.SetVMExtensionStatus("my-script", infrav1.Creating)
+			},
+		},
+		{
+			name:          "error getting the extension specs",
+			expectedError: "failed to get vm extension specs: secret not found",
+			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, m *mock_vmextensions.MockclientMockRecorder) {
+				s.VMExtensionSpecs(gomockinternal.AContext()).Return(nil, errors.New("secret not found"))
 			},
 		},
 		{
 			name:          "error getting the extension",
 			expectedError: "failed to get vm extension my-extension-1 on vm my-vm: #: Internal Server Error: StatusCode=500",
 			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, m *mock_vmextensions.MockclientMockRecorder) {
-				s.VMExtensionSpecs().Return([]azure.ExtensionSpec{
+				s.VMExtensionSpecs(gomockinternal.AContext()).Return([]azure.ExtensionSpec{
 					{
 						Name:      "my-extension-1",
 						VMName:    "my-vm",
@@ -160,7 +214,7 @@ func TestReconcileVMExtension(t *testing.T) {
 						Publisher: "other-publisher",
 						Version:   "2.0",
 					},
-				})
+				}, nil)
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("test-location")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-vm", "my-extension-1").
@@ -171,7 +225,7 @@ func TestReconcileVMExtension(t *testing.T) {
 			name:          "error creating the extension",
 			expectedError: "failed to create VM extension my-extension-1 on VM my-vm in resource group my-rg: #: Internal Server Error: StatusCode=500",
 			expect: func(s *mock_vmextensions.MockVMExtensionScopeMockRecorder, m *mock_vmextensions.MockclientMockRecorder) {
-				s.VMExtensionSpecs().Return([]azure.ExtensionSpec{
+				s.VMExtensionSpecs(gomockinternal.AContext()).Return([]azure.ExtensionSpec{
 					{
 						Name:      "my-extension-1",
 						VMName:    "my-vm",
@@ -184,7 +238,7 @@ func TestReconcileVMExtension(t *testing.T) {
 						Publisher: "other-publisher",
 						Version:   "2.0",
 					},
-				})
+				}, nil)
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("test-location")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-vm", "my-extension-1").
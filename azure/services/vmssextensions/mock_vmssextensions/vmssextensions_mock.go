@@ -249,6 +249,18 @@ func (mr *MockVMSSExtensionScopeMockRecorder) SetBootstrapConditions(arg0, arg1,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBootstrapConditions", reflect.TypeOf((*MockVMSSExtensionScope)(nil).SetBootstrapConditions), arg0, arg1, arg2)
 }
 
+// SetVMExtensionStatus mocks base method.
+func (m *MockVMSSExtensionScope) SetVMExtensionStatus(arg0 string, arg1 v1beta1.ProvisioningState) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetVMExtensionStatus", arg0, arg1)
+}
+
+// SetVMExtensionStatus indicates an expected call of SetVMExtensionStatus.
+func (mr *MockVMSSExtensionScopeMockRecorder) SetVMExtensionStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVMExtensionStatus", reflect.TypeOf((*MockVMSSExtensionScope)(nil).SetVMExtensionStatus), arg0, arg1)
+}
+
 // SubscriptionID mocks base method.
 func (m *MockVMSSExtensionScope) SubscriptionID() string {
 	m.ctrl.T.Helper()
@@ -278,15 +290,16 @@ func (mr *MockVMSSExtensionScopeMockRecorder) TenantID() *gomock.Call {
 }
 
 // VMSSExtensionSpecs mocks base method.
-func (m *MockVMSSExtensionScope) VMSSExtensionSpecs() []azure.ExtensionSpec {
+func (m *MockVMSSExtensionScope) VMSSExtensionSpecs(arg0 context.Context) ([]azure.ExtensionSpec, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "VMSSExtensionSpecs")
+	ret := m.ctrl.Call(m, "VMSSExtensionSpecs", arg0)
 	ret0, _ := ret[0].([]azure.ExtensionSpec)
-	return ret0
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // VMSSExtensionSpecs indicates an expected call of VMSSExtensionSpecs.
-func (mr *MockVMSSExtensionScopeMockRecorder) VMSSExtensionSpecs() *gomock.Call {
+func (mr *MockVMSSExtensionScopeMockRecorder) VMSSExtensionSpecs(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VMSSExtensionSpecs", reflect.TypeOf((*MockVMSSExtensionScope)(nil).VMSSExtensionSpecs))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VMSSExtensionSpecs", reflect.TypeOf((*MockVMSSExtensionScope)(nil).VMSSExtensionSpecs), arg0)
 }
@@ -21,6 +21,7 @@ import (
 
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
 )
@@ -28,8 +29,9 @@ import (
 // VMSSExtensionScope defines the scope interface for a vmss extension service.
 type VMSSExtensionScope interface {
 	azure.ClusterDescriber
-	VMSSExtensionSpecs() []azure.ExtensionSpec
+	VMSSExtensionSpecs(context.Context) ([]azure.ExtensionSpec, error)
 	SetBootstrapConditions(context.Context, string, string) error
+	SetVMExtensionStatus(string, infrav1.ProvisioningState)
 }
 
 // Service provides operations on Azure resources.
@@ -48,11 +50,17 @@ func New(scope VMSSExtensionScope) *Service {
 
 // Reconcile creates or updates the VMSS extension.
 func (s *Service) Reconcile(ctx context.Context) error {
-	_, _, done := tele.StartSpanWithLogger(ctx, "vmssextensions.Service.Reconcile")
+	ctx, _, done := tele.StartSpanWithLogger(ctx, "vmssextensions.Service.Reconcile")
 	defer done()
 
-	for _, extensionSpec := range s.Scope.VMSSExtensionSpecs() {
+	extensionSpecs, err := s.Scope.VMSSExtensionSpecs(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get vmss extension specs")
+	}
+
+	for _, extensionSpec := range extensionSpecs {
 		if existing, err := s.client.Get(ctx, s.Scope.ResourceGroup(), extensionSpec.VMName, extensionSpec.Name); err == nil {
+			s.Scope.SetVMExtensionStatus(extensionSpec.Name, infrav1.ProvisioningState(to.String(existing.ProvisioningState)))
 			// check the extension status and set the associated conditions.
 			if retErr := s.Scope.SetBootstrapConditions(ctx, to.String(existing.ProvisioningState), extensionSpec.Name); retErr != nil {
 				return retErr
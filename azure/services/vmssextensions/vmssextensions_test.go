@@ -26,6 +26,7 @@ import (
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/vmssextensions/mock_vmssextensions"
 	gomockinternal "sigs.k8s.io/cluster-api-provider-azure/internal/test/matchers/gomock"
@@ -41,14 +42,14 @@ func TestReconcileVMSSExtension(t *testing.T) {
 			name:          "extension already exists",
 			expectedError: "",
 			expect: func(s *mock_vmssextensions.MockVMSSExtensionScopeMockRecorder, m *mock_vmssextensions.MockclientMockRecorder) {
-				s.VMSSExtensionSpecs().Return([]azure.ExtensionSpec{
+				s.VMSSExtensionSpecs(gomockinternal.AContext()).Return([]azure.ExtensionSpec{
 					{
 						Name:      "my-extension-1",
 						VMName:    "my-vmss",
 						Publisher: "some-publisher",
 						Version:   "1.0",
 					},
-				})
+				}, nil)
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("test-location")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-vmss", "my-extension-1").Return(compute.VirtualMachineScaleSetExtension{
@@ -60,6 +61,7 @@ func TestReconcileVMSSExtension(t *testing.T) {
 					},
 					ID: to.StringPtr("some/fake/id"),
 				}, nil)
+				s.SetVMExtensionStatus("my-extension-1", infrav1.ProvisioningState(compute.ProvisioningStateSucceeded))
 				s.SetBootstrapConditions(gomockinternal.AContext(), string(compute.ProvisioningStateSucceeded), "my-extension-1")
 			},
 		},
@@ -67,7 +69,7 @@ func TestReconcileVMSSExtension(t *testing.T) {
 			name:          "extension does not exist",
 			expectedError: "",
 			expect: func(s *mock_vmssextensions.MockVMSSExtensionScopeMockRecorder, m *mock_vmssextensions.MockclientMockRecorder) {
-				s.VMSSExtensionSpecs().Return([]azure.ExtensionSpec{
+				s.VMSSExtensionSpecs(gomockinternal.AContext()).Return([]azure.ExtensionSpec{
 					{
 						Name:      "my-extension-1",
 						VMName:    "my-vmss",
@@ -80,7 +82,7 @@ func TestReconcileVMSSExtension(t *testing.T) {
 						Publisher: "other-publisher",
 						Version:   "2.0",
 					},
-				})
+				}, nil)
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("test-location")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-vmss", "my-extension-1").
@@ -93,7 +95,7 @@ func TestReconcileVMSSExtension(t *testing.T) {
 			name:          "error getting the extension",
 			expectedError: "failed to get vm extension my-extension-1 on scale set my-vmss: #: Internal Server Error: StatusCode=500",
 			expect: func(s *mock_vmssextensions.MockVMSSExtensionScopeMockRecorder, m *mock_vmssextensions.MockclientMockRecorder) {
-				s.VMSSExtensionSpecs().Return([]azure.ExtensionSpec{
+				s.VMSSExtensionSpecs(gomockinternal.AContext()).Return([]azure.ExtensionSpec{
 					{
 						Name:      "my-extension-1",
 						VMName:    "my-vmss",
@@ -106,7 +108,7 @@ func TestReconcileVMSSExtension(t *testing.T) {
 						Publisher: "other-publisher",
 						Version:   "2.0",
 					},
-				})
+				}, nil)
 				s.ResourceGroup().AnyTimes().Return("my-rg")
 				s.Location().AnyTimes().Return("test-location")
 				m.Get(gomockinternal.AContext(), "my-rg", "my-vmss", "my-extension-1").
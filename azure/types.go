@@ -18,6 +18,7 @@ package azure
 
 import (
 	"reflect"
+	"strings"
 
 	"github.com/google/go-cmp/cmp"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
@@ -46,6 +47,16 @@ type RoleAssignmentSpec struct {
 	MachineName  string
 	Name         string
 	ResourceType string
+
+	// IdentityResourceID is the resource ID of the user-assigned identity the role is assigned to when ResourceType
+	// is UserAssignedIdentity.
+	IdentityResourceID string
+
+	// Scope is the resource ID the role assignment applies to. Defaults to the subscription.
+	Scope string
+
+	// RoleDefinitionID is the ID of the built-in role to assign. Defaults to Contributor.
+	RoleDefinitionID string
 }
 
 // ResourceType defines the type azure resource being reconciled.
@@ -59,6 +70,22 @@ const (
 
 	// VirtualMachineScaleSet ...
 	VirtualMachineScaleSet = "VirtualMachineScaleSet"
+
+	// UserAssignedIdentity ...
+	UserAssignedIdentity = "UserAssignedIdentity"
+)
+
+// IDs of the Azure built-in roles, see https://docs.microsoft.com/en-us/azure/role-based-access-control/built-in-roles.
+const (
+	// AcrPullRoleID is the ID of the AcrPull role, which allows pulling images from a container registry.
+	AcrPullRoleID = "7f951dda-4ed3-4680-a7ca-43fe172d538d"
+
+	// ManagedIdentityOperatorRoleID is the ID of the Managed Identity Operator role, which allows assigning a
+	// user-assigned identity.
+	ManagedIdentityOperatorRoleID = "f1a07417-d97a-45cb-824c-7a7467783830"
+
+	// NetworkContributorRoleID is the ID of the Network Contributor role, which allows managing networks.
+	NetworkContributorRoleID = "4d97b98b-1d4f-4787-a291-c67834d212e7"
 )
 
 // NSGSpec defines the specification for a Security Group.
@@ -86,7 +113,17 @@ type ScaleSetSpec struct {
 	UserAssignedIdentities       []infrav1.UserAssignedIdentity
 	SecurityProfile              *infrav1.SecurityProfile
 	SpotVMOptions                *infrav1.SpotVMOptions
+	DiagnosticsProfile           *infrav1.Diagnostics
 	FailureDomains               []string
+	OrchestrationMode            infrav1.OrchestrationModeType
+	PlatformFaultDomainCount     *int32
+	UpgradePolicy                *infrav1.UpgradePolicy
+	AutomaticRepairsPolicy       *infrav1.AutomaticRepairsPolicy
+	ApplicationHealth            *infrav1.ApplicationHealthProbe
+	// ObserveOnly is true for a scale set being replaced by another scale set of the machine pool, whose model must not
+	// change. The scale set is neither created nor updated, and it is deleted once it has no instances left if its
+	// capacity is 0.
+	ObserveOnly bool
 }
 
 // TagsSpec defines the specification for a set of tags.
@@ -118,10 +155,20 @@ type ExtensionSpec struct {
 	Name              string
 	VMName            string
 	Publisher         string
+	Type              string
 	Version           string
+	Settings          map[string]string
 	ProtectedSettings map[string]string
 }
 
+// NodeDiagnosticsSpec defines the specification for collecting node diagnostics from a VM or a VM scale set instance.
+type NodeDiagnosticsSpec struct {
+	VMName       string
+	ScaleSetName string
+	InstanceID   string
+	OSType       string
+}
+
 type (
 	// VMSSVM defines a VM in a virtual machine scale set.
 	VMSSVM struct {
@@ -131,20 +178,36 @@ type (
 		Name             string                    `json:"name,omitempty"`
 		AvailabilityZone string                    `json:"availabilityZone,omitempty"`
 		State            infrav1.ProvisioningState `json:"vmState,omitempty"`
+		BootstrapState   infrav1.ProvisioningState `json:"bootstrapState,omitempty"`
+		ProtectionPolicy VMSSVMProtectionPolicy    `json:"protectionPolicy,omitempty"`
+		VMSize           string                    `json:"vmSize,omitempty"`
+		Tags             infrav1.Tags              `json:"tags,omitempty"`
+		Extensions       map[string]string         `json:"extensions,omitempty"`
+		// ModelOutdated is true when Azure reports the instance of a scale set in Uniform orchestration mode is not
+		// running the latest model.
+		ModelOutdated bool `json:"modelOutdated,omitempty"`
+	}
+
+	// VMSSVMProtectionPolicy defines the instance protection of a virtual machine scale set VM.
+	VMSSVMProtectionPolicy struct {
+		ProtectFromScaleIn         bool `json:"protectFromScaleIn,omitempty"`
+		ProtectFromScaleSetActions bool `json:"protectFromScaleSetActions,omitempty"`
 	}
 
 	// VMSS defines a virtual machine scale set.
 	VMSS struct {
-		ID        string                    `json:"id,omitempty"`
-		Name      string                    `json:"name,omitempty"`
-		Sku       string                    `json:"sku,omitempty"`
-		Capacity  int64                     `json:"capacity,omitempty"`
-		Zones     []string                  `json:"zones,omitempty"`
-		Image     infrav1.Image             `json:"image,omitempty"`
-		State     infrav1.ProvisioningState `json:"vmState,omitempty"`
-		Identity  infrav1.VMIdentity        `json:"identity,omitempty"`
-		Tags      infrav1.Tags              `json:"tags,omitempty"`
-		Instances []VMSSVM                  `json:"instances,omitempty"`
+		ID                string                        `json:"id,omitempty"`
+		Name              string                        `json:"name,omitempty"`
+		Sku               string                        `json:"sku,omitempty"`
+		Capacity          int64                         `json:"capacity,omitempty"`
+		Zones             []string                      `json:"zones,omitempty"`
+		Image             infrav1.Image                 `json:"image,omitempty"`
+		State             infrav1.ProvisioningState     `json:"vmState,omitempty"`
+		Identity          infrav1.VMIdentity            `json:"identity,omitempty"`
+		Tags              infrav1.Tags                  `json:"tags,omitempty"`
+		OrchestrationMode infrav1.OrchestrationModeType `json:"orchestrationMode,omitempty"`
+		Extensions        map[string]string             `json:"extensions,omitempty"`
+		Instances         []VMSSVM                      `json:"instances,omitempty"`
 	}
 )
 
@@ -206,6 +269,39 @@ func (vmss VMSS) HasLatestModelApplied(vm VMSSVM) bool {
 	return reflect.DeepEqual(vm.Image, vmss.Image)
 }
 
+// ModelDifferences returns the parts of the VMSS model the VMSS instance differs in. The image and the VM size are always
+// compared. The extensions and the tags are only compared once Azure reports the instance is not running the latest
+// model, and the custom data is reported when Azure does while none of the other parts differ.
+func (vmss VMSS) ModelDifferences(vm VMSSVM) []infrav1.ModelDifference {
+	var differences []infrav1.ModelDifference
+	if !vmss.HasLatestModelApplied(vm) {
+		differences = append(differences, infrav1.ModelDifferenceImage)
+	}
+
+	if vm.VMSize != "" && vmss.Sku != "" && !strings.EqualFold(vm.VMSize, vmss.Sku) {
+		differences = append(differences, infrav1.ModelDifferenceVMSize)
+	}
+
+	if !vm.ModelOutdated {
+		return differences
+	}
+
+	// extensions and tags are not returned for every instance, they are only compared when they are
+	if vm.Extensions != nil && !reflect.DeepEqual(vm.Extensions, vmss.Extensions) {
+		differences = append(differences, infrav1.ModelDifferenceExtensions)
+	}
+
+	if vm.Tags != nil && !vm.Tags.Equals(vmss.Tags) {
+		differences = append(differences, infrav1.ModelDifferenceTags)
+	}
+
+	if len(differences) == 0 {
+		differences = append(differences, infrav1.ModelDifferenceUnknown)
+	}
+
+	return differences
+}
+
 // ManagedClusterSpec contains properties to create a managed cluster.
 type ManagedClusterSpec struct {
 	// Name is the name of this AKS Cluster.
@@ -232,6 +328,10 @@ type ManagedClusterSpec struct {
 	// LoadBalancerSKU for the managed cluster. Possible values include: 'Standard', 'Basic'. Defaults to Standard.
 	LoadBalancerSKU string
 
+	// OutboundType is the outbound (egress) routing method. Possible values include: 'loadBalancer', 'managedNATGateway',
+	// 'userAssignedNATGateway', 'userDefinedRouting'. Defaults to loadBalancer.
+	OutboundType string
+
 	// NetworkPlugin used for building Kubernetes network. Possible values include: 'azure', 'kubenet'. Defaults to azure.
 	NetworkPlugin string
 
@@ -264,6 +364,59 @@ type ManagedClusterSpec struct {
 
 	// APIServerAccessProfile is the access profile for AKS API server.
 	APIServerAccessProfile *APIServerAccessProfile
+
+	// WindowsProfile is the profile for Windows VMs in the cluster.
+	WindowsProfile *WindowsProfile
+
+	// AddonProfiles are the profiles of the managed cluster add-ons.
+	AddonProfiles []AddonProfile
+
+	// AutoScalerProfile is the parameters to be applied to the cluster autoscaler.
+	AutoScalerProfile *AutoScalerProfile
+
+	// AutoUpgradeChannel is the channel used to automatically upgrade the cluster.
+	AutoUpgradeChannel *string
+
+	// UserAssignedIdentity is the resource ID of the user-assigned identity of the control plane. The control plane
+	// uses a system-assigned identity when it is empty.
+	UserAssignedIdentity string
+
+	// KubeletUserAssignedIdentity is the resource ID of the user-assigned identity of the kubelets. AKS creates the
+	// kubelet identity when it is empty.
+	KubeletUserAssignedIdentity string
+}
+
+// AutoScalerProfile - parameters of the cluster autoscaler, in the string representation expected by AKS.
+type AutoScalerProfile struct {
+	BalanceSimilarNodeGroups      *string
+	Expander                      *string
+	MaxEmptyBulkDelete            *string
+	MaxGracefulTerminationSec     *string
+	MaxNodeProvisionTime          *string
+	MaxTotalUnreadyPercentage     *string
+	NewPodScaleUpDelay            *string
+	OkTotalUnreadyCount           *string
+	ScanInterval                  *string
+	ScaleDownDelayAfterAdd        *string
+	ScaleDownDelayAfterDelete     *string
+	ScaleDownDelayAfterFailure    *string
+	ScaleDownUnneededTime         *string
+	ScaleDownUnreadyTime          *string
+	ScaleDownUtilizationThreshold *string
+	SkipNodesWithLocalStorage     *string
+	SkipNodesWithSystemPods       *string
+}
+
+// AddonProfile is the profile of a managed cluster add-on.
+type AddonProfile struct {
+	// Name - The name of the managed cluster add-on.
+	Name string
+
+	// Enabled - Whether the add-on is enabled or not.
+	Enabled bool
+
+	// Config - Key-value pairs for configuring the add-on.
+	Config map[string]string
 }
 
 // AADProfile is Azure Active Directory configuration to integrate with AKS, for aad authentication.
@@ -319,6 +472,14 @@ type APIServerAccessProfile struct {
 	EnablePrivateClusterPublicFQDN *bool
 }
 
+// WindowsProfile is the profile for Windows VMs in an AKS cluster.
+type WindowsProfile struct {
+	// AdminUsername - Administrator username for Windows VMs.
+	AdminUsername string
+	// AdminPassword - Administrator password for Windows VMs.
+	AdminPassword string
+}
+
 // AgentPoolSpec contains agent pool specification details.
 type AgentPoolSpec struct {
 	// Name is the name of agent pool.
@@ -345,6 +506,9 @@ type AgentPoolSpec struct {
 	// VnetSubnetID is the Azure Resource ID for the subnet which should contain nodes.
 	VnetSubnetID string
 
+	// PodSubnetID is the Azure Resource ID for the subnet from which pods get their IP addresses.
+	PodSubnetID *string `json:"podSubnetID,omitempty"`
+
 	// Mode represents mode of an agent pool. Possible values include: 'System', 'User'.
 	Mode string
 
@@ -368,4 +532,126 @@ type AgentPoolSpec struct {
 
 	// OsDiskType specifies the OS disk type for each node in the pool. Allowed values are 'Ephemeral' and 'Managed'.
 	OsDiskType *string `json:"osDiskType,omitempty"`
+
+	// OSType specifies the operating system for each node in the pool. Allowed values are 'Linux' and 'Windows'.
+	OSType *string `json:"osType,omitempty"`
+
+	// NodeTaints specifies the taints for nodes in the pool, in the form key=value:Effect.
+	NodeTaints []string `json:"nodeTaints,omitempty"`
+
+	// KubeletConfig specifies the kubelet configuration for nodes in the pool.
+	KubeletConfig *KubeletConfig `json:"kubeletConfig,omitempty"`
+
+	// LinuxOSConfig specifies the OS configuration for Linux nodes in the pool.
+	LinuxOSConfig *LinuxOSConfig `json:"linuxOSConfig,omitempty"`
+
+	// ScaleSetPriority specifies the virtual machine scale set priority. Allowed values are 'Regular' and 'Spot'.
+	ScaleSetPriority *string `json:"scaleSetPriority,omitempty"`
+
+	// ScaleSetEvictionPolicy specifies the eviction policy for a Spot virtual machine scale set.
+	ScaleSetEvictionPolicy *string `json:"scaleSetEvictionPolicy,omitempty"`
+
+	// SpotMaxPrice specifies the maximum price to pay for Spot instances. -1 means the current on-demand price.
+	SpotMaxPrice *float64 `json:"spotMaxPrice,omitempty"`
+
+	// MaxSurge specifies the maximum number or percentage of nodes that are surged during an upgrade.
+	MaxSurge *string `json:"maxSurge,omitempty"`
+}
+
+// KubeletConfig is the kubelet configuration for nodes in an agent pool.
+type KubeletConfig struct {
+	// CPUManagerPolicy - CPU Manager policy to use.
+	CPUManagerPolicy *string
+	// CPUCfsQuota - Enable CPU CFS quota enforcement for containers that specify CPU limits.
+	CPUCfsQuota *bool
+	// CPUCfsQuotaPeriod - Sets CPU CFS quota period value.
+	CPUCfsQuotaPeriod *string
+	// ImageGcHighThreshold - The percent of disk usage after which image garbage collection is always run.
+	ImageGcHighThreshold *int32
+	// ImageGcLowThreshold - The percent of disk usage before which image garbage collection is never run.
+	ImageGcLowThreshold *int32
+	// TopologyManagerPolicy - Topology Manager policy to use.
+	TopologyManagerPolicy *string
+	// AllowedUnsafeSysctls - Allowlist of unsafe sysctls or unsafe sysctl patterns (ending in `*`).
+	AllowedUnsafeSysctls []string
+	// FailSwapOn - If set to true it will make the Kubelet fail to start if swap is enabled on the node.
+	FailSwapOn *bool
+	// ContainerLogMaxSizeMB - The maximum size in MB of container log file before it is rotated.
+	ContainerLogMaxSizeMB *int32
+	// ContainerLogMaxFiles - The maximum number of container log files that can be present for a container.
+	ContainerLogMaxFiles *int32
+	// PodMaxPids - The maximum number of processes per pod.
+	PodMaxPids *int32
+}
+
+// LinuxOSConfig is the OS configuration for Linux nodes in an agent pool.
+type LinuxOSConfig struct {
+	// SwapFileSizeMB - Size in MB of a swap file that will be created on each node.
+	SwapFileSizeMB *int32
+	// Sysctls - Sysctl settings for Linux nodes.
+	Sysctls *SysctlConfig
+	// TransparentHugePageDefrag - Transparent Huge Page defrag configuration.
+	TransparentHugePageDefrag *string
+	// TransparentHugePageEnabled - Transparent Huge Page enabled configuration.
+	TransparentHugePageEnabled *string
+}
+
+// SysctlConfig is the sysctl settings for Linux nodes in an agent pool.
+type SysctlConfig struct {
+	// NetCoreSomaxconn - Sysctl setting net.core.somaxconn.
+	NetCoreSomaxconn *int32
+	// NetCoreNetdevMaxBacklog - Sysctl setting net.core.netdev_max_backlog.
+	NetCoreNetdevMaxBacklog *int32
+	// NetCoreRmemDefault - Sysctl setting net.core.rmem_default.
+	NetCoreRmemDefault *int32
+	// NetCoreRmemMax - Sysctl setting net.core.rmem_max.
+	NetCoreRmemMax *int32
+	// NetCoreWmemDefault - Sysctl setting net.core.wmem_default.
+	NetCoreWmemDefault *int32
+	// NetCoreWmemMax - Sysctl setting net.core.wmem_max.
+	NetCoreWmemMax *int32
+	// NetCoreOptmemMax - Sysctl setting net.core.optmem_max.
+	NetCoreOptmemMax *int32
+	// NetIpv4TCPMaxSynBacklog - Sysctl setting net.ipv4.tcp_max_syn_backlog.
+	NetIpv4TCPMaxSynBacklog *int32
+	// NetIpv4TCPMaxTwBuckets - Sysctl setting net.ipv4.tcp_max_tw_buckets.
+	NetIpv4TCPMaxTwBuckets *int32
+	// NetIpv4TCPFinTimeout - Sysctl setting net.ipv4.tcp_fin_timeout.
+	NetIpv4TCPFinTimeout *int32
+	// NetIpv4TCPKeepaliveTime - Sysctl setting net.ipv4.tcp_keepalive_time.
+	NetIpv4TCPKeepaliveTime *int32
+	// NetIpv4TCPKeepaliveProbes - Sysctl setting net.ipv4.tcp_keepalive_probes.
+	NetIpv4TCPKeepaliveProbes *int32
+	// NetIpv4TCPKeepaliveIntvl - Sysctl setting net.ipv4.tcp_keepalive_intvl.
+	NetIpv4TCPKeepaliveIntvl *int32
+	// NetIpv4TCPTwReuse - Sysctl setting net.ipv4.tcp_tw_reuse.
+	NetIpv4TCPTwReuse *bool
+	// NetIpv4IPLocalPortRange - Sysctl setting net.ipv4.ip_local_port_range.
+	NetIpv4IPLocalPortRange *string
+	// NetIpv4NeighDefaultGcThresh1 - Sysctl setting net.ipv4.neigh.default.gc_thresh1.
+	NetIpv4NeighDefaultGcThresh1 *int32
+	// NetIpv4NeighDefaultGcThresh2 - Sysctl setting net.ipv4.neigh.default.gc_thresh2.
+	NetIpv4NeighDefaultGcThresh2 *int32
+	// NetIpv4NeighDefaultGcThresh3 - Sysctl setting net.ipv4.neigh.default.gc_thresh3.
+	NetIpv4NeighDefaultGcThresh3 *int32
+	// NetNetfilterNfConntrackMax - Sysctl setting net.netfilter.nf_conntrack_max.
+	NetNetfilterNfConntrackMax *int32
+	// NetNetfilterNfConntrackBuckets - Sysctl setting net.netfilter.nf_conntrack_buckets.
+	NetNetfilterNfConntrackBuckets *int32
+	// FsInotifyMaxUserWatches - Sysctl setting fs.inotify.max_user_watches.
+	FsInotifyMaxUserWatches *int32
+	// FsFileMax - Sysctl setting fs.file-max.
+	FsFileMax *int32
+	// FsAioMaxNr - Sysctl setting fs.aio-max-nr.
+	FsAioMaxNr *int32
+	// FsNrOpen - Sysctl setting fs.nr_open.
+	FsNrOpen *int32
+	// KernelThreadsMax - Sysctl setting kernel.threads-max.
+	KernelThreadsMax *int32
+	// VMMaxMapCount - Sysctl setting vm.max_map_count.
+	VMMaxMapCount *int32
+	// VMSwappiness - Sysctl setting vm.swappiness.
+	VMSwappiness *int32
+	// VMVfsCachePressure - Sysctl setting vm.vfs_cache_pressure.
+	VMVfsCachePressure *int32
 }
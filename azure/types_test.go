@@ -148,6 +148,81 @@ func TestVMSS_HasModelChanges(t *testing.T) {
 	}
 }
 
+func TestVMSS_ModelDifferences(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Factory  func(VMSS) VMSSVM
+		Expected []infrav1.ModelDifference
+	}{
+		{
+			Name: "instance running the latest model",
+			Factory: func(vmss VMSS) VMSSVM {
+				return VMSSVM{Image: vmss.Image, VMSize: vmss.Sku}
+			},
+		},
+		{
+			Name: "instance with a different image and VM size",
+			Factory: func(vmss VMSS) VMSSVM {
+				return VMSSVM{
+					Image: infrav1.Image{
+						Marketplace: &infrav1.AzureMarketplaceImage{
+							Version: "bar",
+						},
+					},
+					VMSize: "smallVM",
+				}
+			},
+			Expected: []infrav1.ModelDifference{infrav1.ModelDifferenceImage, infrav1.ModelDifferenceVMSize},
+		},
+		{
+			Name: "extensions and tags are ignored unless Azure reports the model is outdated",
+			Factory: func(vmss VMSS) VMSSVM {
+				return VMSSVM{
+					Image:      vmss.Image,
+					VMSize:     vmss.Sku,
+					Extensions: map[string]string{"CAPZ.Linux.Bootstrapping": "1.0"},
+					Tags:       infrav1.Tags{"foo": "bar"},
+				}
+			},
+		},
+		{
+			Name: "outdated instance with different extensions and tags",
+			Factory: func(vmss VMSS) VMSSVM {
+				return VMSSVM{
+					Image:         vmss.Image,
+					VMSize:        vmss.Sku,
+					Extensions:    map[string]string{"CAPZ.Linux.Bootstrapping": "1.0"},
+					Tags:          infrav1.Tags{"foo": "bar"},
+					ModelOutdated: true,
+				}
+			},
+			Expected: []infrav1.ModelDifference{infrav1.ModelDifferenceExtensions, infrav1.ModelDifferenceTags},
+		},
+		{
+			Name: "outdated instance without other differences has an unknown difference",
+			Factory: func(vmss VMSS) VMSSVM {
+				return VMSSVM{
+					Image:         vmss.Image,
+					VMSize:        vmss.Sku,
+					Extensions:    vmss.Extensions,
+					ModelOutdated: true,
+				}
+			},
+			Expected: []infrav1.ModelDifference{infrav1.ModelDifferenceUnknown},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			g := NewWithT(t)
+			vmss := getDefaultVMSSForModelTesting()
+			vmss.Extensions = map[string]string{"CAPZ.Linux.Bootstrapping": "1.1"}
+			g.Expect(vmss.ModelDifferences(c.Factory(vmss))).To(Equal(c.Expected))
+		})
+	}
+}
+
 func getDefaultVMSSForModelTesting() VMSS {
 	return VMSS{
 		Zones: []string{"0", "1"},
@@ -130,7 +130,7 @@ func (amr *AzureMachineReconciler) SetupWithManager(ctx context.Context, mgr ctr
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azuremachines/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines;machines/status,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
-// +kubebuilder:rbac:groups="",resources=secrets;,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets;,verbs=get;list;watch;create;update;patch
 
 // Reconcile idempotently gets, creates, and updates a machine.
 func (amr *AzureMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
@@ -244,7 +244,8 @@ func (amr *AzureMachineReconciler) reconcileNormal(ctx context.Context, machineS
 	// If the AzureMachine is in an error state, return early.
 	if machineScope.AzureMachine.Status.FailureReason != nil || machineScope.AzureMachine.Status.FailureMessage != nil {
 		log.Info("Error state detected, skipping reconciliation")
-		return reconcile.Result{}, nil
+		// keep checking on the node diagnostics of a VM that failed to bootstrap until they are collected
+		return CollectNodeDiagnostics(ctx, amr.Recorder, machineScope.AzureMachine, machineScope), nil
 	}
 
 	// If the AzureMachine doesn't have our finalizer, add it.
@@ -310,7 +311,11 @@ func (amr *AzureMachineReconciler) reconcileNormal(ctx context.Context, machineS
 				machineScope.SetFailureMessage(err)
 				machineScope.SetNotReady()
 				machineScope.SetVMState(infrav1.Failed)
-				return reconcile.Result{}, nil
+				if machineScope.AzureMachine.Spec.NodeDiagnostics == nil {
+					// node diagnostics already include the serial console log when enabled
+					RecordSerialConsoleLog(ctx, amr.Recorder, machineScope.AzureMachine, machineScope)
+				}
+				return CollectNodeDiagnostics(ctx, amr.Recorder, machineScope.AzureMachine, machineScope), nil
 			}
 
 			if reconcileError.IsTransient() {
@@ -326,6 +331,11 @@ func (amr *AzureMachineReconciler) reconcileNormal(ctx context.Context, machineS
 		return reconcile.Result{}, errors.Wrap(err, "failed to reconcile AzureMachine")
 	}
 
+	if machineScope.VMState() != infrav1.Failed {
+		// record the serial console log again if the VM fails again after recovering
+		conditions.Delete(machineScope.AzureMachine, infrav1.SerialConsoleLogRecordedCondition)
+	}
+
 	machineScope.SetReady()
 
 	return reconcile.Result{}, nil
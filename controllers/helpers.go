@@ -31,10 +31,12 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/groups"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/nodediagnostics"
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/pkg/coalescing"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
@@ -42,11 +44,13 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capiv1exp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 const (
@@ -64,6 +68,20 @@ type (
 		controller.Options
 		Cache *coalescing.ReconcileCache
 	}
+
+	// NodeDiagnosticsScope is the scope used to collect and store node diagnostics from a machine that failed to bootstrap.
+	NodeDiagnosticsScope interface {
+		nodediagnostics.NodeDiagnosticsScope
+		NodeDiagnosticsOutput() infrav1.NodeDiagnosticsOutput
+		SetNodeDiagnostics(ctx context.Context, output string) error
+		SetNodeDiagnosticsFailed(err error)
+	}
+
+	// SerialConsoleLogScope is the scope used to retrieve the boot diagnostics serial console log of a failed machine.
+	SerialConsoleLogScope interface {
+		BootDiagnosticsEnabled() bool
+		GetSerialConsoleLog(ctx context.Context) (string, error)
+	}
 )
 
 // AzureClusterToAzureMachinesMapper creates a mapping handler to transform AzureClusters into AzureMachines. The transform
@@ -597,3 +615,65 @@ func GetClusterIdentityFromRef(ctx context.Context, c client.Client, azureCluste
 	}
 	return nil, nil
 }
+
+// CollectNodeDiagnostics collects node diagnostics from a machine that failed to bootstrap, when enabled, and records
+// them in an event on the given object or in a Secret referenced from its status. Diagnostics are collected at most once.
+// While the diagnostics command is running, it returns a result that requeues the object to check on the command.
+func CollectNodeDiagnostics(ctx context.Context, recorder record.EventRecorder, obj runtime.Object, s NodeDiagnosticsScope) reconcile.Result {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.CollectNodeDiagnostics")
+	defer done()
+
+	if s.NodeDiagnosticsSpec() == nil {
+		return reconcile.Result{}
+	}
+
+	output, err := nodediagnostics.New(s).Collect(ctx)
+	var reconcileError azure.ReconcileError
+	if errors.As(err, &reconcileError) && reconcileError.IsTransient() {
+		log.V(2).Info("node diagnostics are still being collected", "reason", err.Error())
+		return reconcile.Result{RequeueAfter: reconcileError.RequeueAfter()}
+	}
+	if err != nil {
+		log.Error(err, "failed to collect node diagnostics")
+		recorder.Eventf(obj, corev1.EventTypeWarning, "NodeDiagnosticsFailed", "failed to collect node diagnostics: %s", err.Error())
+		s.SetNodeDiagnosticsFailed(err)
+		return reconcile.Result{}
+	}
+
+	if err := s.SetNodeDiagnostics(ctx, output); err != nil {
+		log.Error(err, "failed to store node diagnostics")
+		recorder.Eventf(obj, corev1.EventTypeWarning, "NodeDiagnosticsFailed", "failed to store node diagnostics: %s", err.Error())
+		s.SetNodeDiagnosticsFailed(err)
+		return reconcile.Result{}
+	}
+
+	if s.NodeDiagnosticsOutput() == infrav1.NodeDiagnosticsOutputEvent {
+		recorder.Event(obj, corev1.EventTypeWarning, "NodeDiagnostics", nodediagnostics.Truncate(output, nodediagnostics.MaxEventOutputLength))
+		return reconcile.Result{}
+	}
+	recorder.Event(obj, corev1.EventTypeWarning, "NodeDiagnostics", "node diagnostics were collected, see the Secret referenced by status.nodeDiagnosticsRef")
+	return reconcile.Result{}
+}
+
+// RecordSerialConsoleLog records an excerpt of the boot diagnostics serial console log of a failed machine in an event
+// on the given object. Nothing is recorded if boot diagnostics are disabled or the log cannot be retrieved. The log is
+// recorded at most once per failure: the SerialConsoleLogRecorded condition of the object must be removed once the
+// machine recovers for the log of a later failure to be recorded.
+func RecordSerialConsoleLog(ctx context.Context, recorder record.EventRecorder, obj conditions.Setter, s SerialConsoleLogScope) {
+	ctx, log, done := tele.StartSpanWithLogger(ctx, "controllers.RecordSerialConsoleLog")
+	defer done()
+
+	if !s.BootDiagnosticsEnabled() || conditions.Has(obj, infrav1.SerialConsoleLogRecordedCondition) {
+		return
+	}
+
+	serialLog, err := s.GetSerialConsoleLog(ctx)
+	if err != nil {
+		log.V(2).Info("unable to retrieve serial console log", "error", err.Error())
+		conditions.MarkFalse(obj, infrav1.SerialConsoleLogRecordedCondition, infrav1.SerialConsoleLogUnavailableReason, clusterv1.ConditionSeverityInfo, err.Error())
+		return
+	}
+
+	recorder.Event(obj, corev1.EventTypeWarning, "SerialConsoleLog", nodediagnostics.Truncate(serialLog, nodediagnostics.MaxEventOutputLength))
+	conditions.MarkTrue(obj, infrav1.SerialConsoleLogRecordedCondition)
+}
@@ -28,16 +28,19 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/internal/test/mock_log"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -671,3 +674,71 @@ const (
     "cloudProviderBackoffJitter": 1.2000000000000002
 }`
 )
+
+type fakeSerialConsoleLogScope struct {
+	enabled   bool
+	serialLog string
+	err       error
+}
+
+func (f *fakeSerialConsoleLogScope) BootDiagnosticsEnabled() bool {
+	return f.enabled
+}
+
+func (f *fakeSerialConsoleLogScope) GetSerialConsoleLog(_ context.Context) (string, error) {
+	return f.serialLog, f.err
+}
+
+func TestRecordSerialConsoleLog(t *testing.T) {
+	tests := []struct {
+		name          string
+		scope         *fakeSerialConsoleLogScope
+		recorded      bool
+		wantEvent     string
+		wantCondition *clusterv1.Condition
+	}{
+		{
+			name:          "records an event with the serial console log",
+			scope:         &fakeSerialConsoleLogScope{enabled: true, serialLog: "kernel panic"},
+			wantEvent:     "Warning SerialConsoleLog kernel panic",
+			wantCondition: conditions.TrueCondition(infrav1.SerialConsoleLogRecordedCondition),
+		},
+		{
+			name:  "does not record an event if boot diagnostics are disabled",
+			scope: &fakeSerialConsoleLogScope{enabled: false, serialLog: "kernel panic"},
+		},
+		{
+			name:          "does not record an event if the serial console log cannot be retrieved",
+			scope:         &fakeSerialConsoleLogScope{enabled: true, err: errors.New("not found")},
+			wantCondition: conditions.FalseCondition(infrav1.SerialConsoleLogRecordedCondition, infrav1.SerialConsoleLogUnavailableReason, clusterv1.ConditionSeverityInfo, "not found"),
+		},
+		{
+			name:          "does not record an event again for the same failure",
+			scope:         &fakeSerialConsoleLogScope{enabled: true, serialLog: "kernel panic"},
+			recorded:      true,
+			wantCondition: conditions.TrueCondition(infrav1.SerialConsoleLogRecordedCondition),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			recorder := record.NewFakeRecorder(1)
+			azureMachine := &infrav1.AzureMachine{}
+			if tt.recorded {
+				conditions.MarkTrue(azureMachine, infrav1.SerialConsoleLogRecordedCondition)
+			}
+			RecordSerialConsoleLog(context.Background(), recorder, azureMachine, tt.scope)
+			if tt.wantCondition == nil {
+				g.Expect(conditions.Has(azureMachine, infrav1.SerialConsoleLogRecordedCondition)).To(BeFalse())
+			} else {
+				g.Expect(conditions.Get(azureMachine, infrav1.SerialConsoleLogRecordedCondition)).To(conditions.HaveSameStateOf(tt.wantCondition))
+			}
+			if tt.wantEvent == "" {
+				g.Expect(recorder.Events).To(BeEmpty())
+				return
+			}
+			g.Expect(recorder.Events).To(Receive(Equal(tt.wantEvent)))
+		})
+	}
+}
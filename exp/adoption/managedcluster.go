@@ -0,0 +1,449 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package adoption generates the Cluster API objects that bring existing Azure resources under CAPZ management.
+package adoption
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-08-01/containerservice"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/converters"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiexp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ManagedClusterOptions are the options of the objects generated for an existing AKS cluster.
+type ManagedClusterOptions struct {
+	// Namespace is the namespace of the generated objects.
+	Namespace string
+
+	// IdentityRef is the reference to the AzureClusterIdentity of the AzureManagedControlPlane.
+	IdentityRef *corev1.ObjectReference
+
+	// ConfirmAdoption sets the adopt annotation on the AzureManagedControlPlane, so that CAPZ takes ownership of the
+	// AKS cluster without any further confirmation.
+	ConfirmAdoption bool
+
+	// SubnetCIDRBlocks are the address prefixes of the subnets of the agent pools, keyed by subnet ID. The managed
+	// cluster does not report them, but the AzureManagedControlPlane lists the subnets with their CIDR blocks.
+	SubnetCIDRBlocks map[string]string
+}
+
+// ManagedClusterObjects returns the Cluster, AzureManagedCluster, AzureManagedControlPlane, MachinePools and
+// AzureManagedMachinePools that match an existing AKS cluster. The objects are named after the AKS cluster, and the
+// AzureManagedMachinePools after the cluster and their agent pool.
+func ManagedClusterObjects(mc containerservice.ManagedCluster, options ManagedClusterOptions) ([]client.Object, error) {
+	if mc.ID == nil || mc.Name == nil || mc.ManagedClusterProperties == nil {
+		return nil, errors.New("managed cluster is missing its ID, name or properties")
+	}
+
+	resourceID, err := azureautorest.ParseResourceID(*mc.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse managed cluster ID %s", *mc.ID)
+	}
+
+	name := *mc.Name
+	controlPlane, err := azureManagedControlPlane(mc, resourceID, options)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []client.Object{
+		cluster(mc, options.Namespace),
+		&infrav1exp.AzureManagedCluster{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: infrav1exp.GroupVersion.String(),
+				Kind:       "AzureManagedCluster",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: options.Namespace,
+			},
+		},
+		controlPlane,
+	}
+
+	if mc.AgentPoolProfiles != nil {
+		for _, pool := range *mc.AgentPoolProfiles {
+			machinePool, managedMachinePool, err := agentPoolObjects(name, pool, controlPlane.Spec.VirtualNetwork.Subnet.Name, options.Namespace)
+			if err != nil {
+				return nil, err
+			}
+			objects = append(objects, machinePool, managedMachinePool)
+		}
+	}
+
+	return objects, nil
+}
+
+// cluster returns the Cluster of an existing AKS cluster.
+func cluster(mc containerservice.ManagedCluster, namespace string) *clusterv1.Cluster {
+	name := to.String(mc.Name)
+	cluster := &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: clusterv1.ClusterSpec{
+			ControlPlaneRef: &corev1.ObjectReference{
+				APIVersion: infrav1exp.GroupVersion.String(),
+				Kind:       "AzureManagedControlPlane",
+				Name:       name,
+			},
+			InfrastructureRef: &corev1.ObjectReference{
+				APIVersion: infrav1exp.GroupVersion.String(),
+				Kind:       "AzureManagedCluster",
+				Name:       name,
+			},
+		},
+	}
+
+	if np := mc.NetworkProfile; np != nil && (np.PodCidr != nil || np.ServiceCidr != nil) {
+		cluster.Spec.ClusterNetwork = &clusterv1.ClusterNetwork{}
+		if np.PodCidr != nil {
+			cluster.Spec.ClusterNetwork.Pods = &clusterv1.NetworkRanges{CIDRBlocks: []string{*np.PodCidr}}
+		}
+		if np.ServiceCidr != nil {
+			cluster.Spec.ClusterNetwork.Services = &clusterv1.NetworkRanges{CIDRBlocks: []string{*np.ServiceCidr}}
+		}
+	}
+
+	return cluster
+}
+
+// azureManagedControlPlane returns the AzureManagedControlPlane of an existing AKS cluster.
+func azureManagedControlPlane(mc containerservice.ManagedCluster, resourceID azureautorest.Resource, options ManagedClusterOptions) (*infrav1exp.AzureManagedControlPlane, error) {
+	controlPlane := &infrav1exp.AzureManagedControlPlane{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: infrav1exp.GroupVersion.String(),
+			Kind:       "AzureManagedControlPlane",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      to.String(mc.Name),
+			Namespace: options.Namespace,
+		},
+		Spec: infrav1exp.AzureManagedControlPlaneSpec{
+			Version:               "v" + to.String(mc.KubernetesVersion),
+			ResourceGroupName:     resourceID.ResourceGroup,
+			NodeResourceGroupName: to.String(mc.NodeResourceGroup),
+			SubscriptionID:        resourceID.SubscriptionID,
+			Location:              to.String(mc.Location),
+			IdentityRef:           options.IdentityRef,
+		},
+	}
+
+	if options.ConfirmAdoption {
+		controlPlane.Annotations = map[string]string{
+			infrav1exp.AdoptAnnotation: "true",
+		}
+	}
+
+	if lp := mc.LinuxProfile; lp != nil && lp.SSH != nil && lp.SSH.PublicKeys != nil && len(*lp.SSH.PublicKeys) > 0 {
+		controlPlane.Spec.SSHPublicKey = base64.StdEncoding.EncodeToString([]byte(to.String((*lp.SSH.PublicKeys)[0].KeyData)))
+	}
+
+	if np := mc.NetworkProfile; np != nil {
+		if np.NetworkPlugin != "" {
+			controlPlane.Spec.NetworkPlugin = to.StringPtr(string(np.NetworkPlugin))
+		}
+		if np.NetworkPolicy != "" {
+			controlPlane.Spec.NetworkPolicy = to.StringPtr(string(np.NetworkPolicy))
+		}
+		if np.LoadBalancerSku != "" {
+			// AKS returns the SKU in lower case, such as "standard" for "Standard".
+			sku := string(np.LoadBalancerSku)
+			controlPlane.Spec.LoadBalancerSKU = to.StringPtr(strings.ToUpper(sku[:1]) + sku[1:])
+		}
+		if np.OutboundType != "" {
+			outboundType := infrav1exp.ManagedControlPlaneOutboundType(np.OutboundType)
+			controlPlane.Spec.OutboundType = &outboundType
+		}
+		controlPlane.Spec.PodCIDR = np.PodCidr
+		controlPlane.Spec.ServiceCIDR = np.ServiceCidr
+		controlPlane.Spec.DNSServiceIP = np.DNSServiceIP
+	}
+
+	if mc.AadProfile != nil && to.Bool(mc.AadProfile.Managed) {
+		controlPlane.Spec.AADProfile = &infrav1exp.AADProfile{
+			Managed:             true,
+			AdminGroupObjectIDs: to.StringSlice(mc.AadProfile.AdminGroupObjectIDs),
+		}
+	}
+
+	if mc.Sku != nil && mc.Sku.Tier != "" {
+		controlPlane.Spec.SKU = &infrav1exp.SKU{
+			Tier: infrav1exp.AzureManagedControlPlaneSkuTier(mc.Sku.Tier),
+		}
+	}
+
+	if mc.AutoUpgradeProfile != nil && mc.AutoUpgradeProfile.UpgradeChannel != "" {
+		controlPlane.Spec.AutoUpgradeProfile = &infrav1exp.AutoUpgradeProfile{
+			UpgradeChannel: infrav1exp.UpgradeChannel(mc.AutoUpgradeProfile.UpgradeChannel),
+		}
+	}
+
+	// A kubelet identity that AKS created in the node resource group along with a system-assigned control plane
+	// identity is left to AKS.
+	if mc.Identity != nil && mc.Identity.Type == containerservice.ResourceIdentityTypeUserAssigned {
+		controlPlane.Spec.Identity = &infrav1exp.ManagedControlPlaneIdentity{
+			Type: infrav1exp.ManagedControlPlaneIdentityTypeUserAssigned,
+		}
+		for identityID := range mc.Identity.UserAssignedIdentities {
+			controlPlane.Spec.Identity.UserAssignedIdentityResourceID = identityID
+		}
+		if kubeletIdentity := mc.IdentityProfile[azure.KubeletIdentityKey]; kubeletIdentity != nil && kubeletIdentity.ResourceID != nil {
+			kubeletIdentityID, err := azureautorest.ParseResourceID(*kubeletIdentity.ResourceID)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse kubelet identity ID %s", *kubeletIdentity.ResourceID)
+			}
+			if !strings.EqualFold(kubeletIdentityID.ResourceGroup, to.String(mc.NodeResourceGroup)) {
+				controlPlane.Spec.Identity.KubeletIdentityResourceID = *kubeletIdentity.ResourceID
+			}
+		}
+	}
+
+	// The virtual network is the one of the subnet of the first agent pool, and is left unmanaged when it belongs to
+	// another resource group than the cluster. The subnets of the other agent pools are additional subnets of the
+	// same virtual network.
+	if mc.AgentPoolProfiles != nil {
+		for i, pool := range *mc.AgentPoolProfiles {
+			// Agent pools in a virtual network managed by AKS don't have a subnet, and CAPZ would create another
+			// virtual network for the cluster.
+			subnetID := to.String(pool.VnetSubnetID)
+			if subnetID == "" {
+				return nil, errors.Errorf("agent pool %s uses a virtual network managed by AKS, which cannot be adopted", to.String(pool.Name))
+			}
+			vnetResourceGroup, vnetName, subnetName, err := parseSubnetID(subnetID)
+			if err != nil {
+				return nil, err
+			}
+			cidrBlock, ok := subnetCIDRBlock(options.SubnetCIDRBlocks, subnetID)
+			if !ok {
+				return nil, errors.Errorf("the CIDR block of subnet %s is unknown", subnetID)
+			}
+			subnet := infrav1exp.ManagedControlPlaneSubnet{
+				Name:      subnetName,
+				CIDRBlock: cidrBlock,
+			}
+
+			vnet := &controlPlane.Spec.VirtualNetwork
+			if i == 0 {
+				vnet.Name = vnetName
+				vnet.Subnet = subnet
+				if !strings.EqualFold(vnetResourceGroup, resourceID.ResourceGroup) {
+					vnet.ResourceGroup = vnetResourceGroup
+				}
+				continue
+			}
+
+			if vnetName != vnet.Name || !strings.EqualFold(vnetResourceGroup, vnetResourceGroupOrDefault(vnet.ResourceGroup, resourceID.ResourceGroup)) {
+				return nil, errors.Errorf("agent pool %s uses virtual network %s instead of %s", to.String(pool.Name), vnetName, vnet.Name)
+			}
+			if !hasSubnet(*vnet, subnetName) {
+				vnet.Subnets = append(vnet.Subnets, subnet)
+			}
+		}
+	}
+
+	return controlPlane, nil
+}
+
+// agentPoolObjects returns the MachinePool and AzureManagedMachinePool of an existing agent pool.
+func agentPoolObjects(clusterName string, pool containerservice.ManagedClusterAgentPoolProfile, defaultSubnetName, namespace string) (*capiexp.MachinePool, *infrav1exp.AzureManagedMachinePool, error) {
+	poolName := to.String(pool.Name)
+	name := fmt.Sprintf("%s-%s", clusterName, poolName)
+
+	managedMachinePool := &infrav1exp.AzureManagedMachinePool{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: infrav1exp.GroupVersion.String(),
+			Kind:       "AzureManagedMachinePool",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			// CAPZ lists the AzureManagedMachinePools of the cluster by label to validate them against the agent pools.
+			Labels: map[string]string{
+				clusterv1.ClusterLabelName: clusterName,
+			},
+		},
+		Spec: infrav1exp.AzureManagedMachinePoolSpec{
+			Name:              to.StringPtr(poolName),
+			Mode:              string(pool.Mode),
+			SKU:               to.String(pool.VMSize),
+			OSDiskSizeGB:      pool.OsDiskSizeGB,
+			AvailabilityZones: to.StringSlice(pool.AvailabilityZones),
+			MaxPods:           pool.MaxPods,
+		},
+	}
+
+	if pool.OsDiskType != "" {
+		managedMachinePool.Spec.OsDiskType = to.StringPtr(string(pool.OsDiskType))
+	}
+	if pool.OsType != "" {
+		managedMachinePool.Spec.OSType = to.StringPtr(string(pool.OsType))
+	}
+
+	if to.Bool(pool.EnableAutoScaling) {
+		managedMachinePool.Spec.Scaling = &infrav1exp.ManagedMachinePoolScaling{
+			MinSize: pool.MinCount,
+			MaxSize: pool.MaxCount,
+		}
+	}
+
+	// AKS adds the spot label and taint to the nodes of spot node pools, and so does CAPZ.
+	for key, value := range pool.NodeLabels {
+		if key == infrav1exp.SpotNodeLabelKey {
+			continue
+		}
+		if managedMachinePool.Spec.NodeLabels == nil {
+			managedMachinePool.Spec.NodeLabels = map[string]string{}
+		}
+		managedMachinePool.Spec.NodeLabels[key] = to.String(value)
+	}
+
+	for _, taint := range to.StringSlice(pool.NodeTaints) {
+		parsed, err := converters.SDKToNodeTaint(taint)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to parse a taint of agent pool %s", poolName)
+		}
+		if parsed == infrav1exp.SpotTaint {
+			continue
+		}
+		managedMachinePool.Spec.Taints = append(managedMachinePool.Spec.Taints, parsed)
+	}
+
+	if pool.ScaleSetPriority == containerservice.ScaleSetPrioritySpot {
+		managedMachinePool.Spec.SpotVMOptions = &infrav1.SpotVMOptions{}
+		// A max price of -1 means the on-demand price, which is also the default of CAPZ.
+		if pool.SpotMaxPrice != nil && *pool.SpotMaxPrice != -1 {
+			maxPrice, err := resource.ParseQuantity(strconv.FormatFloat(*pool.SpotMaxPrice, 'f', -1, 64))
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "failed to parse the spot max price of agent pool %s", poolName)
+			}
+			managedMachinePool.Spec.SpotVMOptions.MaxPrice = &maxPrice
+		}
+	}
+
+	if subnetID := to.String(pool.VnetSubnetID); subnetID != "" {
+		_, _, subnetName, err := parseSubnetID(subnetID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if subnetName != defaultSubnetName {
+			managedMachinePool.Spec.SubnetName = to.StringPtr(subnetName)
+		}
+	}
+
+	if pool.UpgradeSettings != nil && pool.UpgradeSettings.MaxSurge != nil {
+		managedMachinePool.Spec.UpgradeSettings = &infrav1exp.ManagedMachinePoolUpgradeSettings{
+			MaxSurge: pool.UpgradeSettings.MaxSurge,
+		}
+	}
+
+	machinePool := &capiexp.MachinePool{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: capiexp.GroupVersion.String(),
+			Kind:       "MachinePool",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: capiexp.MachinePoolSpec{
+			ClusterName: clusterName,
+			Replicas:    pool.Count,
+			Template: clusterv1.MachineTemplateSpec{
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{
+						DataSecretName: to.StringPtr(""),
+					},
+					ClusterName: clusterName,
+					InfrastructureRef: corev1.ObjectReference{
+						APIVersion: infrav1exp.GroupVersion.String(),
+						Kind:       "AzureManagedMachinePool",
+						Name:       name,
+					},
+				},
+			},
+		},
+	}
+	if pool.OrchestratorVersion != nil {
+		machinePool.Spec.Template.Spec.Version = to.StringPtr("v" + *pool.OrchestratorVersion)
+	}
+
+	return machinePool, managedMachinePool, nil
+}
+
+// subnetCIDRBlock returns the CIDR block of a subnet, comparing subnet IDs case-insensitively as Azure does.
+func subnetCIDRBlock(cidrBlocks map[string]string, subnetID string) (string, bool) {
+	for id, cidrBlock := range cidrBlocks {
+		if strings.EqualFold(id, subnetID) {
+			return cidrBlock, true
+		}
+	}
+	return "", false
+}
+
+// vnetResourceGroupOrDefault returns the resource group of the virtual network, which defaults to the resource group
+// of the cluster.
+func vnetResourceGroupOrDefault(vnetResourceGroup, clusterResourceGroup string) string {
+	if vnetResourceGroup == "" {
+		return clusterResourceGroup
+	}
+	return vnetResourceGroup
+}
+
+// hasSubnet returns true if the virtual network lists a subnet of the name.
+func hasSubnet(vnet infrav1exp.ManagedControlPlaneVirtualNetwork, name string) bool {
+	if vnet.Subnet.Name == name {
+		return true
+	}
+	for _, subnet := range vnet.Subnets {
+		if subnet.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSubnetID returns the resource group, virtual network name and subnet name of a subnet ID.
+func parseSubnetID(subnetID string) (resourceGroup, vnetName, subnetName string, err error) {
+	parts := strings.Split(strings.Trim(subnetID, "/"), "/")
+	// subscriptions/<id>/resourceGroups/<rg>/providers/Microsoft.Network/virtualNetworks/<vnet>/subnets/<subnet>
+	if len(parts) != 10 || !strings.EqualFold(parts[2], "resourceGroups") || !strings.EqualFold(parts[6], "virtualNetworks") ||
+		!strings.EqualFold(parts[8], "subnets") {
+		return "", "", "", errors.Errorf("invalid subnet ID %s", subnetID)
+	}
+	return parts[3], parts[7], parts[9], nil
+}
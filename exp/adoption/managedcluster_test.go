@@ -0,0 +1,191 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adoption
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-08-01/containerservice"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
+	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiexp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+)
+
+func TestManagedClusterObjects(t *testing.T) {
+	g := NewWithT(t)
+
+	mc := containerservice.ManagedCluster{
+		ID:       to.StringPtr("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/payments-rg/providers/Microsoft.ContainerService/managedClusters/payments"),
+		Name:     to.StringPtr("payments"),
+		Location: to.StringPtr("eastus"),
+		Identity: &containerservice.ManagedClusterIdentity{
+			Type: containerservice.ResourceIdentityTypeUserAssigned,
+			UserAssignedIdentities: map[string]*containerservice.ManagedClusterIdentityUserAssignedIdentitiesValue{
+				"/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/identity-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/payments": {},
+			},
+		},
+		ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+			KubernetesVersion: to.StringPtr("1.22.4"),
+			NodeResourceGroup: to.StringPtr("MC_payments-rg_payments_eastus"),
+			IdentityProfile: map[string]*containerservice.UserAssignedIdentity{
+				"kubeletidentity": {
+					ResourceID: to.StringPtr("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/identity-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/payments-kubelet"),
+				},
+			},
+			LinuxProfile: &containerservice.LinuxProfile{
+				SSH: &containerservice.SSHConfiguration{
+					PublicKeys: &[]containerservice.SSHPublicKey{{KeyData: to.StringPtr("ssh-rsa AAAAB3NzaC1yc2E")}},
+				},
+			},
+			NetworkProfile: &containerservice.NetworkProfile{
+				NetworkPlugin:   containerservice.NetworkPluginAzure,
+				LoadBalancerSku: containerservice.LoadBalancerSkuStandard,
+				ServiceCidr:     to.StringPtr("10.0.0.0/16"),
+				DNSServiceIP:    to.StringPtr("10.0.0.10"),
+			},
+			AgentPoolProfiles: &[]containerservice.ManagedClusterAgentPoolProfile{
+				{
+					Name:                to.StringPtr("system"),
+					Mode:                containerservice.AgentPoolModeSystem,
+					Count:               to.Int32Ptr(3),
+					VMSize:              to.StringPtr("Standard_D2s_v3"),
+					OsDiskSizeGB:        to.Int32Ptr(128),
+					OsType:              containerservice.OSTypeLinux,
+					OrchestratorVersion: to.StringPtr("1.22.4"),
+					VnetSubnetID:        to.StringPtr("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/network-rg/providers/Microsoft.Network/virtualNetworks/hub/subnets/nodes"),
+				},
+				{
+					Name:                to.StringPtr("spot"),
+					Mode:                containerservice.AgentPoolModeUser,
+					Count:               to.Int32Ptr(1),
+					VMSize:              to.StringPtr("Standard_D4s_v3"),
+					OsType:              containerservice.OSTypeLinux,
+					OrchestratorVersion: to.StringPtr("1.22.4"),
+					VnetSubnetID:        to.StringPtr("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/network-rg/providers/Microsoft.Network/virtualNetworks/hub/subnets/batch"),
+					EnableAutoScaling:   to.BoolPtr(true),
+					MinCount:            to.Int32Ptr(1),
+					MaxCount:            to.Int32Ptr(5),
+					ScaleSetPriority:    containerservice.ScaleSetPrioritySpot,
+					SpotMaxPrice:        to.Float64Ptr(0.25),
+					NodeLabels: map[string]*string{
+						"kubernetes.azure.com/scalesetpriority": to.StringPtr("spot"),
+						"workload":                              to.StringPtr("batch"),
+					},
+					NodeTaints: &[]string{
+						"kubernetes.azure.com/scalesetpriority=spot:NoSchedule",
+						"dedicated=batch:NoExecute",
+					},
+				},
+			},
+		},
+	}
+
+	objects, err := ManagedClusterObjects(mc, ManagedClusterOptions{
+		Namespace:       "aks",
+		ConfirmAdoption: true,
+		SubnetCIDRBlocks: map[string]string{
+			"/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/network-rg/providers/Microsoft.Network/virtualNetworks/hub/subnets/nodes": "10.240.0.0/16",
+			"/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/network-rg/providers/Microsoft.Network/virtualNetworks/hub/subnets/batch": "10.241.0.0/16",
+		},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(objects).To(HaveLen(7))
+
+	cluster := objects[0].(*clusterv1.Cluster)
+	g.Expect(cluster.Name).To(Equal("payments"))
+	g.Expect(cluster.Namespace).To(Equal("aks"))
+	g.Expect(cluster.Spec.ControlPlaneRef.Name).To(Equal("payments"))
+	g.Expect(cluster.Spec.ClusterNetwork.Services.CIDRBlocks).To(Equal([]string{"10.0.0.0/16"}))
+	g.Expect(objects[1].(*infrav1exp.AzureManagedCluster).Name).To(Equal("payments"))
+
+	controlPlane := objects[2].(*infrav1exp.AzureManagedControlPlane)
+	g.Expect(controlPlane.Annotations).To(HaveKeyWithValue(infrav1exp.AdoptAnnotation, "true"))
+	g.Expect(controlPlane.Spec.Version).To(Equal("v1.22.4"))
+	g.Expect(controlPlane.Spec.ResourceGroupName).To(Equal("payments-rg"))
+	g.Expect(controlPlane.Spec.NodeResourceGroupName).To(Equal("MC_payments-rg_payments_eastus"))
+	g.Expect(controlPlane.Spec.SubscriptionID).To(Equal("00000000-0000-0000-0000-000000000000"))
+	g.Expect(controlPlane.Spec.SSHPublicKey).To(Equal(base64.StdEncoding.EncodeToString([]byte("ssh-rsa AAAAB3NzaC1yc2E"))))
+	g.Expect(controlPlane.Spec.NetworkPlugin).To(Equal(to.StringPtr("azure")))
+	g.Expect(controlPlane.Spec.LoadBalancerSKU).To(Equal(to.StringPtr("Standard")))
+	g.Expect(controlPlane.Spec.DNSServiceIP).To(Equal(to.StringPtr("10.0.0.10")))
+	g.Expect(controlPlane.Spec.VirtualNetwork).To(Equal(infrav1exp.ManagedControlPlaneVirtualNetwork{
+		Name:          "hub",
+		ResourceGroup: "network-rg",
+		Subnet:        infrav1exp.ManagedControlPlaneSubnet{Name: "nodes", CIDRBlock: "10.240.0.0/16"},
+		Subnets:       []infrav1exp.ManagedControlPlaneSubnet{{Name: "batch", CIDRBlock: "10.241.0.0/16"}},
+	}))
+	g.Expect(controlPlane.Spec.Identity).To(Equal(&infrav1exp.ManagedControlPlaneIdentity{
+		Type:                           infrav1exp.ManagedControlPlaneIdentityTypeUserAssigned,
+		UserAssignedIdentityResourceID: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/identity-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/payments",
+		KubeletIdentityResourceID:      "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/identity-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/payments-kubelet",
+	}))
+
+	systemMachinePool := objects[3].(*capiexp.MachinePool)
+	g.Expect(systemMachinePool.Name).To(Equal("payments-system"))
+	g.Expect(systemMachinePool.Spec.Replicas).To(Equal(to.Int32Ptr(3)))
+	g.Expect(systemMachinePool.Spec.Template.Spec.Version).To(Equal(to.StringPtr("v1.22.4")))
+	g.Expect(systemMachinePool.Spec.Template.Spec.InfrastructureRef.Name).To(Equal("payments-system"))
+
+	systemPool := objects[4].(*infrav1exp.AzureManagedMachinePool)
+	g.Expect(systemPool.Labels).To(HaveKeyWithValue(clusterv1.ClusterLabelName, "payments"))
+	g.Expect(systemPool.Spec.Name).To(Equal(to.StringPtr("system")))
+	g.Expect(systemPool.Spec.Mode).To(Equal("System"))
+	g.Expect(systemPool.Spec.SKU).To(Equal("Standard_D2s_v3"))
+	g.Expect(systemPool.Spec.OSDiskSizeGB).To(Equal(to.Int32Ptr(128)))
+	g.Expect(systemPool.Spec.SubnetName).To(BeNil())
+
+	spotPool := objects[6].(*infrav1exp.AzureManagedMachinePool)
+	maxPrice := resource.MustParse("0.25")
+	g.Expect(spotPool.Spec.Scaling).To(Equal(&infrav1exp.ManagedMachinePoolScaling{MinSize: to.Int32Ptr(1), MaxSize: to.Int32Ptr(5)}))
+	g.Expect(spotPool.Spec.SpotVMOptions.MaxPrice.Cmp(maxPrice)).To(Equal(0))
+	g.Expect(spotPool.Spec.NodeLabels).To(Equal(map[string]string{"workload": "batch"}))
+	g.Expect(spotPool.Spec.Taints).To(Equal(infrav1exp.Taints{{Key: "dedicated", Value: "batch", Effect: infrav1exp.TaintEffectNoExecute}}))
+	g.Expect(spotPool.Spec.SubnetName).To(Equal(to.StringPtr("batch")))
+}
+
+func TestManagedClusterObjectsWithoutID(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := ManagedClusterObjects(containerservice.ManagedCluster{Name: to.StringPtr("payments")}, ManagedClusterOptions{})
+	g.Expect(err).To(MatchError("managed cluster is missing its ID, name or properties"))
+}
+
+func TestManagedClusterObjectsWithAKSManagedVirtualNetwork(t *testing.T) {
+	g := NewWithT(t)
+
+	mc := containerservice.ManagedCluster{
+		ID:   to.StringPtr("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/payments-rg/providers/Microsoft.ContainerService/managedClusters/payments"),
+		Name: to.StringPtr("payments"),
+		ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+			KubernetesVersion: to.StringPtr("1.22.4"),
+			AgentPoolProfiles: &[]containerservice.ManagedClusterAgentPoolProfile{
+				{
+					Name:   to.StringPtr("system"),
+					Mode:   containerservice.AgentPoolModeSystem,
+					VMSize: to.StringPtr("Standard_D2s_v3"),
+				},
+			},
+		},
+	}
+
+	_, err := ManagedClusterObjects(mc, ManagedClusterOptions{})
+	g.Expect(err).To(MatchError("agent pool system uses a virtual network managed by AKS, which cannot be adopted"))
+}
@@ -48,6 +48,9 @@ func (src *AzureMachinePool) ConvertTo(dstRaw conversion.Hub) error { // nolint
 	}
 
 	dst.Spec.Template.SubnetName = restored.Spec.Template.SubnetName
+	dst.Spec.Template.VMExtensions = restored.Spec.Template.VMExtensions
+	dst.Spec.Template.NodeDiagnostics = restored.Spec.Template.NodeDiagnostics
+	dst.Spec.Template.Diagnostics = restored.Spec.Template.Diagnostics
 
 	dst.Spec.Strategy.Type = restored.Spec.Strategy.Type
 	if restored.Spec.Strategy.RollingUpdate != nil {
@@ -58,15 +61,29 @@ func (src *AzureMachinePool) ConvertTo(dstRaw conversion.Hub) error { // nolint
 
 		dst.Spec.Strategy.RollingUpdate.DeletePolicy = restored.Spec.Strategy.RollingUpdate.DeletePolicy
 	}
+	dst.Spec.Strategy.BlueGreen = restored.Spec.Strategy.BlueGreen
 
 	if restored.Spec.NodeDrainTimeout != nil {
 		dst.Spec.NodeDrainTimeout = restored.Spec.NodeDrainTimeout
 	}
 
+	dst.Spec.NodeDrainOptions = restored.Spec.NodeDrainOptions
+	dst.Spec.OrchestrationMode = restored.Spec.OrchestrationMode
+	dst.Spec.PlatformFaultDomainCount = restored.Spec.PlatformFaultDomainCount
+	dst.Spec.UpgradePolicy = restored.Spec.UpgradePolicy
+	dst.Spec.AutomaticRepairsPolicy = restored.Spec.AutomaticRepairsPolicy
+	dst.Spec.MixedInstancesPolicy = restored.Spec.MixedInstancesPolicy
+	dst.Spec.Scaling = restored.Spec.Scaling
+	dst.Spec.Template.ApplicationHealth = restored.Spec.Template.ApplicationHealth
+
 	if restored.Status.Image != nil {
 		dst.Status.Image = restored.Status.Image
 	}
 
+	dst.Status.VMExtensions = restored.Status.VMExtensions
+	dst.Status.ScaleSets = restored.Status.ScaleSets
+	dst.Status.BlueGreen = restored.Status.BlueGreen
+
 	if restored.Spec.Template.Image != nil && restored.Spec.Template.Image.SharedGallery != nil {
 		dst.Spec.Template.Image.SharedGallery.Offer = restored.Spec.Template.Image.SharedGallery.Offer
 		dst.Spec.Template.Image.SharedGallery.Publisher = restored.Spec.Template.Image.SharedGallery.Publisher
@@ -41,9 +41,24 @@ func (src *AzureManagedControlPlane) ConvertTo(dstRaw conversion.Hub) error { //
 	dst.Spec.SKU = restored.Spec.SKU
 	dst.Spec.LoadBalancerProfile = restored.Spec.LoadBalancerProfile
 	dst.Spec.APIServerAccessProfile = restored.Spec.APIServerAccessProfile
+	dst.Spec.WindowsProfile = restored.Spec.WindowsProfile
+	dst.Spec.AddonProfiles = restored.Spec.AddonProfiles
+	dst.Spec.EnableAzurePolicy = restored.Spec.EnableAzurePolicy
+	dst.Spec.EnableHTTPApplicationRouting = restored.Spec.EnableHTTPApplicationRouting
+	dst.Spec.KeyVaultSecretsProvider = restored.Spec.KeyVaultSecretsProvider
+	dst.Spec.Monitoring = restored.Spec.Monitoring
+	dst.Spec.AutoScalerProfile = restored.Spec.AutoScalerProfile
+	dst.Spec.AutoUpgradeProfile = restored.Spec.AutoUpgradeProfile
+	dst.Spec.PodCIDR = restored.Spec.PodCIDR
+	dst.Spec.ServiceCIDR = restored.Spec.ServiceCIDR
+	dst.Spec.OutboundType = restored.Spec.OutboundType
+	dst.Spec.Identity = restored.Spec.Identity
+	dst.Spec.VirtualNetwork.ResourceGroup = restored.Spec.VirtualNetwork.ResourceGroup
+	dst.Spec.VirtualNetwork.Subnets = restored.Spec.VirtualNetwork.Subnets
 
 	dst.Status.LongRunningOperationStates = restored.Status.LongRunningOperationStates
 	dst.Status.Conditions = restored.Status.Conditions
+	dst.Status.Version = restored.Status.Version
 
 	return nil
 }
@@ -73,3 +88,8 @@ func Convert_v1beta1_AzureManagedControlPlaneSpec_To_v1alpha3_AzureManagedContro
 func Convert_v1beta1_AzureManagedControlPlaneStatus_To_v1alpha3_AzureManagedControlPlaneStatus(in *expv1beta1.AzureManagedControlPlaneStatus, out *AzureManagedControlPlaneStatus, s apiconversion.Scope) error {
 	return autoConvert_v1beta1_AzureManagedControlPlaneStatus_To_v1alpha3_AzureManagedControlPlaneStatus(in, out, s)
 }
+
+// Convert_v1beta1_ManagedControlPlaneVirtualNetwork_To_v1alpha3_ManagedControlPlaneVirtualNetwork is an autogenerated conversion function.
+func Convert_v1beta1_ManagedControlPlaneVirtualNetwork_To_v1alpha3_ManagedControlPlaneVirtualNetwork(in *expv1beta1.ManagedControlPlaneVirtualNetwork, out *ManagedControlPlaneVirtualNetwork, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_ManagedControlPlaneVirtualNetwork_To_v1alpha3_ManagedControlPlaneVirtualNetwork(in, out, s)
+}
@@ -42,10 +42,19 @@ func (src *AzureManagedMachinePool) ConvertTo(dstRaw conversion.Hub) error {
 	dst.Spec.AvailabilityZones = restored.Spec.AvailabilityZones
 	dst.Spec.MaxPods = restored.Spec.MaxPods
 	dst.Spec.OsDiskType = restored.Spec.OsDiskType
+	dst.Spec.OSType = restored.Spec.OSType
+	dst.Spec.Taints = restored.Spec.Taints
+	dst.Spec.KubeletConfig = restored.Spec.KubeletConfig
+	dst.Spec.LinuxOSConfig = restored.Spec.LinuxOSConfig
+	dst.Spec.SpotVMOptions = restored.Spec.SpotVMOptions
+	dst.Spec.SubnetName = restored.Spec.SubnetName
+	dst.Spec.PodSubnetName = restored.Spec.PodSubnetName
+	dst.Spec.UpgradeSettings = restored.Spec.UpgradeSettings
 	dst.Spec.NodeLabels = restored.Spec.NodeLabels
 
 	dst.Status.LongRunningOperationStates = restored.Status.LongRunningOperationStates
 	dst.Status.Conditions = restored.Status.Conditions
+	dst.Status.Taints = restored.Status.Taints
 
 	return nil
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
@@ -28,17 +29,36 @@ const (
 	// MachinePoolNameLabel indicates the AzureMachinePool name the AzureMachinePoolMachine belongs.
 	MachinePoolNameLabel = "azuremachinepool.infrastructure.cluster.x-k8s.io/machine-pool"
 
+	// BootstrapDataHashAnnotation is the hash of the bootstrap data of a scale set model. It is set on an
+	// AzureMachinePool once the bootstrap data is applied to the model of its scale sets, and on each
+	// AzureMachinePoolMachine when it is created, so that the machines created from older bootstrap data are rolled out.
+	BootstrapDataHashAnnotation = "azuremachinepool.infrastructure.cluster.x-k8s.io/bootstrap-data-hash"
+
 	// RollingUpdateAzureMachinePoolDeploymentStrategyType replaces AzureMachinePoolMachines with older models with
 	// AzureMachinePoolMachines based on the latest model.
 	// i.e. gradually scale down the old AzureMachinePoolMachines and scale up the new ones.
 	RollingUpdateAzureMachinePoolDeploymentStrategyType AzureMachinePoolDeploymentStrategyType = "RollingUpdate"
 
+	// BlueGreenAzureMachinePoolDeploymentStrategyType replaces the scale set of an AzureMachinePool with a new scale set
+	// based on the latest model.
+	// i.e. bring up all the new AzureMachinePoolMachines, then drain and delete all the old ones once the new ones are
+	// ready.
+	BlueGreenAzureMachinePoolDeploymentStrategyType AzureMachinePoolDeploymentStrategyType = "BlueGreen"
+
 	// OldestDeletePolicyType will delete machines with the oldest creation date first.
 	OldestDeletePolicyType AzureMachinePoolDeletePolicyType = "Oldest"
 	// NewestDeletePolicyType will delete machines with the newest creation date first.
 	NewestDeletePolicyType AzureMachinePoolDeletePolicyType = "Newest"
 	// RandomDeletePolicyType will delete machines in random order.
 	RandomDeletePolicyType AzureMachinePoolDeletePolicyType = "Random"
+	// AnnotatedDeletePolicyType will delete machines annotated with cluster.x-k8s.io/delete-machine first, followed by
+	// the machines with the oldest creation date.
+	AnnotatedDeletePolicyType AzureMachinePoolDeletePolicyType = "Annotated"
+
+	// RegularScaleSetPriority is the priority of a scale set of regular (on-demand) VMs.
+	RegularScaleSetPriority ScaleSetPriority = "Regular"
+	// SpotScaleSetPriority is the priority of a scale set of Spot VMs.
+	SpotScaleSetPriority ScaleSetPriority = "Spot"
 )
 
 type (
@@ -87,6 +107,25 @@ type (
 		// SubnetName selects the Subnet where the VMSS will be placed
 		// +optional
 		SubnetName string `json:"subnetName,omitempty"`
+
+		// VMExtensions specifies a list of extensions to be added to the scale set model, in addition to the
+		// bootstrapping extension managed by CAPZ.
+		// +optional
+		VMExtensions []infrav1.VMExtension `json:"vmExtensions,omitempty"`
+
+		// NodeDiagnostics enables the collection of node diagnostics from instances that fail to bootstrap.
+		// +optional
+		NodeDiagnostics *infrav1.NodeDiagnostics `json:"nodeDiagnostics,omitempty"`
+
+		// Diagnostics specifies the diagnostics settings for a virtual machine scale set.
+		// If not specified then Boot diagnostics (Managed) will be enabled.
+		// +optional
+		Diagnostics *infrav1.Diagnostics `json:"diagnostics,omitempty"`
+
+		// ApplicationHealth adds the application health extension to the scale set model to report the health of the
+		// instances. It is required by the Rolling and Automatic upgrade modes, automatic OS upgrades and automatic repairs.
+		// +optional
+		ApplicationHealth *infrav1.ApplicationHealthProbe `json:"applicationHealth,omitempty"`
 	}
 
 	// AzureMachinePoolSpec defines the desired state of AzureMachinePool.
@@ -143,6 +182,149 @@ type (
 		// NOTE: NodeDrainTimeout is different from `kubectl drain --timeout`
 		// +optional
 		NodeDrainTimeout *metav1.Duration `json:"nodeDrainTimeout,omitempty"`
+
+		// NodeDrainOptions configures how the node of an AzureMachinePoolMachine is drained before the machine is
+		// deleted. If not specified, DaemonSet-managed pods are ignored and pods using emptyDir volumes are evicted.
+		// +optional
+		NodeDrainOptions *NodeDrainOptions `json:"nodeDrainOptions,omitempty"`
+
+		// OrchestrationMode specifies the orchestration mode for the Virtual Machine Scale Set. In Flexible mode, the
+		// instances are managed through the standard Virtual Machine APIs. The orchestration mode cannot be changed once
+		// the scale set has been created.
+		// +kubebuilder:default=Uniform
+		// +optional
+		OrchestrationMode infrav1.OrchestrationModeType `json:"orchestrationMode,omitempty"`
+
+		// PlatformFaultDomainCount is the number of fault domains the instances of the scale set are spread across.
+		// For Flexible orchestration mode it defaults to 1, which spreads the instances across as many fault domains as
+		// possible. It cannot be changed once the scale set has been created.
+		// +kubebuilder:validation:Minimum=1
+		// +kubebuilder:validation:Maximum=5
+		// +optional
+		PlatformFaultDomainCount *int32 `json:"platformFaultDomainCount,omitempty"`
+
+		// UpgradePolicy describes how Azure applies changes of the scale set model to the instances. If not specified,
+		// CAPZ rolls out changes using the deployment strategy. It cannot be changed once the scale set has been created.
+		// +optional
+		UpgradePolicy *infrav1.UpgradePolicy `json:"upgradePolicy,omitempty"`
+
+		// AutomaticRepairsPolicy enables Azure to replace instances reported unhealthy by the application health
+		// extension. It cannot be changed once the scale set has been created.
+		// +optional
+		AutomaticRepairsPolicy *infrav1.AutomaticRepairsPolicy `json:"automaticRepairsPolicy,omitempty"`
+
+		// MixedInstancesPolicy spreads the replicas of the pool across several scale sets, one for each VM size and
+		// priority, to fail over to other VM sizes when a size is out of capacity and to run part of the replicas as
+		// Spot VMs. It cannot be added to or removed from an existing pool.
+		// +optional
+		MixedInstancesPolicy *MixedInstancesPolicy `json:"mixedInstancesPolicy,omitempty"`
+
+		// Scaling sets the minimum and maximum size of the pool for the Cluster API cluster autoscaler provider, along
+		// with the capacity of the nodes of the pool, which lets the cluster autoscaler scale the pool from zero. They
+		// are set as annotations of the MachinePool. If not specified, the annotations of the MachinePool are left
+		// unchanged.
+		// +optional
+		Scaling *MachinePoolScaling `json:"scaling,omitempty"`
+	}
+
+	// MachinePoolScaling describes the node group of an AzureMachinePool for the Cluster API cluster autoscaler
+	// provider.
+	MachinePoolScaling struct {
+		// MinSize is the minimum number of replicas the cluster autoscaler scales the pool down to.
+		// +kubebuilder:validation:Minimum=0
+		MinSize int32 `json:"minSize"`
+
+		// MaxSize is the maximum number of replicas the cluster autoscaler scales the pool up to.
+		// +kubebuilder:validation:Minimum=0
+		MaxSize int32 `json:"maxSize"`
+
+		// NodeLabels are the labels the nodes of the pool register with, e.g. through the node-labels kubelet argument.
+		// They are advertised to the cluster autoscaler along with the well-known labels of the VM size, so that pods
+		// selecting them can scale the pool up from zero.
+		// +optional
+		NodeLabels map[string]string `json:"nodeLabels,omitempty"`
+
+		// NodeTaints are the taints the nodes of the pool register with, e.g. through the register-with-taints kubelet
+		// argument. They are advertised to the cluster autoscaler, so that only pods tolerating them scale the pool up
+		// from zero.
+		// +optional
+		NodeTaints []corev1.Taint `json:"nodeTaints,omitempty"`
+	}
+
+	// MixedInstancesPolicy describes the VM sizes and the split between regular and Spot VMs of a pool backed by
+	// several scale sets.
+	MixedInstancesPolicy struct {
+		// FallbackVMSizes is the list of VM sizes, in order of preference, used when the VM size of the template cannot
+		// be provisioned because it is restricted or out of capacity.
+		// +optional
+		FallbackVMSizes []string `json:"fallbackVMSizes,omitempty"`
+
+		// OnDemandBaseCapacity is the number of replicas provisioned as regular VMs before any Spot VM is provisioned.
+		// +kubebuilder:validation:Minimum=0
+		// +optional
+		OnDemandBaseCapacity *int32 `json:"onDemandBaseCapacity,omitempty"`
+
+		// SpotPercentageAboveBaseCapacity is the percentage of the replicas above the on-demand base capacity that are
+		// provisioned as Spot VMs. Spot VMs use the SpotVMOptions of the template, if any. Defaults to 0.
+		// +kubebuilder:validation:Minimum=0
+		// +kubebuilder:validation:Maximum=100
+		// +optional
+		SpotPercentageAboveBaseCapacity *int32 `json:"spotPercentageAboveBaseCapacity,omitempty"`
+	}
+
+	// NodeDrainOptions describes how the node of an AzureMachinePoolMachine is drained before the machine is deleted.
+	// Pod eviction always honors PodDisruptionBudgets.
+	NodeDrainOptions struct {
+		// SkipWaitForDeleteTimeout ignores the pods whose deletion timestamp is older than this duration, e.g. the
+		// pods of an unreachable node which will never be removed. Defaults to 5 minutes for an unreachable node, and
+		// to waiting for all the pods otherwise.
+		// +optional
+		SkipWaitForDeleteTimeout *metav1.Duration `json:"skipWaitForDeleteTimeout,omitempty"`
+
+		// DeleteEmptyDirData evicts the pods using emptyDir volumes, whose data is lost when the node is drained. If
+		// false, draining fails while such pods run on the node. Defaults to true.
+		// +optional
+		DeleteEmptyDirData *bool `json:"deleteEmptyDirData,omitempty"`
+
+		// IgnoreDaemonSets ignores the pods managed by a DaemonSet. If false, draining fails while such pods run on
+		// the node. Defaults to true.
+		// +optional
+		IgnoreDaemonSets *bool `json:"ignoreDaemonSets,omitempty"`
+
+		// PodSelector is a label selector restricting the pods evicted from the node. The other pods keep running
+		// until the machine is deleted.
+		// +optional
+		PodSelector string `json:"podSelector,omitempty"`
+
+		// GracePeriod overrides the termination grace period of the evicted pods. Defaults to the termination grace
+		// period of each pod.
+		// +optional
+		GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+	}
+
+	// ScaleSetPriority is the priority of the VMs of a scale set backing an AzureMachinePool.
+	ScaleSetPriority string
+
+	// AzureMachinePoolScaleSetStatus provides status information for each scale set backing an AzureMachinePool with a
+	// mixed instances policy.
+	AzureMachinePoolScaleSetStatus struct {
+		// Name is the name of the scale set.
+		Name string `json:"name"`
+
+		// VMSize is the size of the VMs of the scale set.
+		VMSize string `json:"vmSize"`
+
+		// Priority is the priority of the VMs of the scale set.
+		Priority ScaleSetPriority `json:"priority"`
+
+		// Replicas is the number of instances of the scale set.
+		// +optional
+		Replicas int32 `json:"replicas"`
+
+		// UnavailableSince is the time at which the VM size was last found restricted or out of capacity. Scale ups
+		// fail over to the next VM size until the VM size is retried.
+		// +optional
+		UnavailableSince *metav1.Time `json:"unavailableSince,omitempty"`
 	}
 
 	// AzureMachinePoolDeploymentStrategyType is the type of deployment strategy employed to rollout a new version of
@@ -151,9 +333,9 @@ type (
 
 	// AzureMachinePoolDeploymentStrategy describes how to replace existing machines with new ones.
 	AzureMachinePoolDeploymentStrategy struct {
-		// Type of deployment. Currently the only supported strategy is RollingUpdate
+		// Type of deployment. Valid values are RollingUpdate and BlueGreen.
 		// +optional
-		// +kubebuilder:validation:Enum=RollingUpdate
+		// +kubebuilder:validation:Enum=RollingUpdate;BlueGreen
 		// +optional
 		// +kubebuilder:default=RollingUpdate
 		Type AzureMachinePoolDeploymentStrategyType `json:"type,omitempty"`
@@ -162,6 +344,11 @@ type (
 		// MachineDeploymentStrategyType = RollingUpdate.
 		// +optional
 		RollingUpdate *MachineRollingUpdateDeployment `json:"rollingUpdate,omitempty"`
+
+		// Blue/green deployment config params. Present only if
+		// MachineDeploymentStrategyType = BlueGreen.
+		// +optional
+		BlueGreen *MachineBlueGreenDeployment `json:"blueGreen,omitempty"`
 	}
 
 	// AzureMachinePoolDeletePolicyType is the type of DeletePolicy employed to select machines to be deleted during an
@@ -204,14 +391,40 @@ type (
 		MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
 
 		// DeletePolicy defines the policy used by the MachineDeployment to identify nodes to delete when downscaling.
-		// Valid values are "Random, "Newest", "Oldest"
+		// Valid values are "Random, "Newest", "Oldest", "Annotated"
 		// When no value is supplied, the default is Oldest
+		// Regardless of the policy, machines are selected so that the remaining machines stay evenly spread across the
+		// failure domains of the pool.
 		// +optional
-		// +kubebuilder:validation:Enum=Random;Newest;Oldest
+		// +kubebuilder:validation:Enum=Random;Newest;Oldest;Annotated
 		// +kubebuilder:default:=Oldest
 		DeletePolicy AzureMachinePoolDeletePolicyType `json:"deletePolicy,omitempty"`
 	}
 
+	// MachineBlueGreenDeployment is used to control the desired behavior of blue/green deployment.
+	MachineBlueGreenDeployment struct {
+		// SoakDuration is the time all the machines of the replacement scale set must stay ready before the machines
+		// of the scale set it replaces are drained and deleted.
+		// Defaults to 0.
+		// +optional
+		SoakDuration *metav1.Duration `json:"soakDuration,omitempty"`
+
+		// ReadinessTimeout is the time the replacement scale set has to get all of its machines ready. Past this
+		// timeout, the replacement scale set is rolled back as soon as one of its machines is not ready: its machines
+		// are drained and deleted, and its model is not rolled out again until the AzureMachinePool changes.
+		// Defaults to 30m.
+		// +optional
+		ReadinessTimeout *metav1.Duration `json:"readinessTimeout,omitempty"`
+
+		// MaxUnavailable is the maximum number of machines of the replaced scale sets that are drained and deleted at
+		// once after the replacement scale set is promoted.
+		// Value can be an absolute number (ex: 5) or a percentage of desired machines (ex: 10%).
+		// Absolute number is calculated from percentage by rounding up.
+		// Defaults to 25%.
+		// +optional
+		MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+	}
+
 	// AzureMachinePoolStatus defines the observed state of AzureMachinePool.
 	AzureMachinePoolStatus struct {
 		// Ready is true when the provider resource is ready.
@@ -281,10 +494,58 @@ type (
 		// +optional
 		Conditions clusterv1.Conditions `json:"conditions,omitempty"`
 
+		// VMExtensions reports the provisioning state of each extension in the scale set model.
+		// +optional
+		VMExtensions []infrav1.VMExtensionStatus `json:"vmExtensions,omitempty"`
+
 		// LongRunningOperationStates saves the state for Azure long-running operations so they can be continued on the
 		// next reconciliation loop.
 		// +optional
 		LongRunningOperationStates infrav1.Futures `json:"longRunningOperationStates,omitempty"`
+
+		// ScaleSets reports the scale sets backing an AzureMachinePool with a mixed instances policy and the number of
+		// replicas of each VM size and priority.
+		// +optional
+		ScaleSets []AzureMachinePoolScaleSetStatus `json:"scaleSets,omitempty"`
+
+		// BlueGreen reports the scale sets backing an AzureMachinePool with the BlueGreen deployment strategy.
+		// +optional
+		BlueGreen *AzureMachinePoolBlueGreenStatus `json:"blueGreen,omitempty"`
+	}
+
+	// AzureMachinePoolBlueGreenStatus provides status information about the scale sets of a blue/green deployment.
+	AzureMachinePoolBlueGreenStatus struct {
+		// ActiveScaleSet is the name of the scale set serving the replicas of the machine pool.
+		ActiveScaleSet string `json:"activeScaleSet"`
+
+		// ActiveRevision is the revision of the model of the active scale set.
+		ActiveRevision string `json:"activeRevision"`
+
+		// ReplacementScaleSet is the name of the scale set being rolled out to replace the active scale set.
+		// +optional
+		ReplacementScaleSet string `json:"replacementScaleSet,omitempty"`
+
+		// ReplacementRevision is the revision of the model of the replacement scale set.
+		// +optional
+		ReplacementRevision string `json:"replacementRevision,omitempty"`
+
+		// ReplacementStartTime is the time at which the rollout of the replacement scale set started.
+		// +optional
+		ReplacementStartTime *metav1.Time `json:"replacementStartTime,omitempty"`
+
+		// ReplacementReadyTime is the time since which all the machines of the replacement scale set are ready.
+		// +optional
+		ReplacementReadyTime *metav1.Time `json:"replacementReadyTime,omitempty"`
+
+		// RetiredScaleSets are the names of the replaced or rolled back scale sets whose machines are being drained
+		// and deleted.
+		// +optional
+		RetiredScaleSets []string `json:"retiredScaleSets,omitempty"`
+
+		// FailedRevision is the revision of the last model rolled back because its scale set did not get ready in
+		// time.
+		// +optional
+		FailedRevision string `json:"failedRevision,omitempty"`
 	}
 
 	// AzureMachinePoolInstanceStatus provides status information for each instance in the VMSS.
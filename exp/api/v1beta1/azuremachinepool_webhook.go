@@ -20,12 +20,18 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	"sigs.k8s.io/cluster-api-provider-azure/util/slice"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
@@ -73,10 +79,21 @@ func (amp *AzureMachinePool) Validate(old runtime.Object) error {
 	validators := []func() error{
 		amp.ValidateImage,
 		amp.ValidateTerminateNotificationTimeout,
+		amp.ValidateNodeDrainOptions,
 		amp.ValidateSSHKey,
 		amp.ValidateUserAssignedIdentity,
 		amp.ValidateStrategy(),
+		amp.ValidateStrategyUpdate(old),
 		amp.ValidateSystemAssignedIdentity(old),
+		amp.ValidateVMExtensions,
+		amp.ValidateDiagnostics,
+		amp.ValidateOrchestrationMode(old),
+		amp.ValidateUpgradePolicy,
+		amp.ValidateAutomaticRepairsPolicy,
+		amp.ValidateUpgradePolicyUpdate(old),
+		amp.ValidateMixedInstancesPolicy,
+		amp.ValidateMixedInstancesPolicyUpdate(old),
+		amp.ValidateScaling,
 	}
 
 	var errs []error
@@ -118,6 +135,34 @@ func (amp *AzureMachinePool) ValidateTerminateNotificationTimeout() error {
 	return nil
 }
 
+// ValidateNodeDrainOptions validates the node drain options.
+func (amp *AzureMachinePool) ValidateNodeDrainOptions() error {
+	options := amp.Spec.NodeDrainOptions
+	if options == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("Spec", "NodeDrainOptions")
+	if timeout := options.SkipWaitForDeleteTimeout; timeout != nil && timeout.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("SkipWaitForDeleteTimeout"), timeout.Duration.String(), "must not be negative"))
+	}
+
+	if grace := options.GracePeriod; grace != nil && grace.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("GracePeriod"), grace.Duration.String(), "must not be negative"))
+	}
+
+	if _, err := labels.Parse(options.PodSelector); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("PodSelector"), options.PodSelector, err.Error()))
+	}
+
+	if len(allErrs) > 0 {
+		return kerrors.NewAggregate(allErrs.ToAggregate().Errors())
+	}
+
+	return nil
+}
+
 // ValidateSSHKey validates an SSHKey.
 func (amp *AzureMachinePool) ValidateSSHKey() error {
 	if amp.Spec.Template.SSHPublicKey != "" {
@@ -154,6 +199,83 @@ func (amp *AzureMachinePool) ValidateStrategy() func() error {
 			}
 		}
 
+		if amp.Spec.Strategy.Type == BlueGreenAzureMachinePoolDeploymentStrategyType {
+			return amp.validateBlueGreenStrategy()
+		}
+
+		return nil
+	}
+}
+
+// validateBlueGreenStrategy validates that a machine pool using the blue/green strategy does not rely on features
+// which are bound to a single scale set, and that its durations are valid.
+func (amp *AzureMachinePool) validateBlueGreenStrategy() error {
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("Spec", "Strategy")
+
+	if amp.Spec.MixedInstancesPolicy != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "the blue/green strategy is not supported with a mixed instances policy"))
+	}
+
+	if amp.Spec.Template.OSDisk.OSType == azure.WindowsOS {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "the blue/green strategy is not supported for Windows machine pools"))
+	}
+
+	if amp.Spec.OrchestrationMode == infrav1.FlexibleOrchestrationMode {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "the blue/green strategy is not supported with the Flexible orchestration mode"))
+	}
+
+	if amp.Spec.Identity == infrav1.VMIdentitySystemAssigned {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "the blue/green strategy is not supported with a system-assigned identity, use user-assigned identities instead"))
+	}
+
+	if policy := amp.Spec.UpgradePolicy; policy != nil && (policy.Mode == infrav1.UpgradeModeRolling || policy.Mode == infrav1.UpgradeModeAutomatic) {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "the blue/green strategy is not supported with the Rolling and Automatic upgrade modes"))
+	}
+
+	if blueGreen := amp.Spec.Strategy.BlueGreen; blueGreen != nil {
+		if soak := blueGreen.SoakDuration; soak != nil && soak.Duration < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("BlueGreen", "SoakDuration"), soak.Duration.String(), "must not be negative"))
+		}
+
+		if timeout := blueGreen.ReadinessTimeout; timeout != nil && timeout.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("BlueGreen", "ReadinessTimeout"), timeout.Duration.String(), "must be positive"))
+		}
+
+		if maxUnavailable := blueGreen.MaxUnavailable; maxUnavailable != nil {
+			if val, err := intstr.GetScaledValueFromIntOrPercent(maxUnavailable, 100, true); err != nil || val < 1 {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("BlueGreen", "MaxUnavailable"), maxUnavailable.String(), "must be a positive number or percentage"))
+			}
+		}
+	}
+
+	if len(allErrs) > 0 {
+		return kerrors.NewAggregate(allErrs.ToAggregate().Errors())
+	}
+
+	return nil
+}
+
+// ValidateStrategyUpdate validates that a machine pool which has been deployed with the blue/green strategy does not
+// switch to another strategy, as its machines may no longer live in the scale set named after the pool.
+func (amp *AzureMachinePool) ValidateStrategyUpdate(old runtime.Object) func() error {
+	return func() error {
+		if old == nil {
+			return nil
+		}
+
+		oldMachinePool, ok := old.(*AzureMachinePool)
+		if !ok {
+			return fmt.Errorf("unexpected type for old azure machine pool object. Expected: %q, Got: %q",
+				"AzureMachinePool", reflect.TypeOf(old))
+		}
+
+		if oldMachinePool.Spec.Strategy.Type == BlueGreenAzureMachinePoolDeploymentStrategyType &&
+			amp.Spec.Strategy.Type != BlueGreenAzureMachinePoolDeploymentStrategyType &&
+			oldMachinePool.Status.BlueGreen != nil {
+			return field.Invalid(field.NewPath("Spec", "Strategy", "Type"), amp.Spec.Strategy.Type, "cannot switch away from the blue/green strategy once it has been deployed")
+		}
+
 		return nil
 	}
 }
@@ -179,3 +301,275 @@ func (amp *AzureMachinePool) ValidateSystemAssignedIdentity(old runtime.Object)
 		return nil
 	}
 }
+
+// ValidateVMExtensions validates the VM extensions of the scale set model.
+func (amp *AzureMachinePool) ValidateVMExtensions() error {
+	if errs := infrav1.ValidateVMExtensions(amp.Spec.Template.VMExtensions, field.NewPath("template", "vmExtensions")); len(errs) > 0 {
+		return kerrors.NewAggregate(errs.ToAggregate().Errors())
+	}
+
+	return nil
+}
+
+// ValidateDiagnostics validates the diagnostics settings of the scale set model.
+func (amp *AzureMachinePool) ValidateDiagnostics() error {
+	if errs := infrav1.ValidateDiagnostics(amp.Spec.Template.Diagnostics, field.NewPath("template", "diagnostics")); len(errs) > 0 {
+		return kerrors.NewAggregate(errs.ToAggregate().Errors())
+	}
+
+	return nil
+}
+
+// ValidateOrchestrationMode validates that the orchestration mode and the platform fault domain count of the scale set
+// are not changed once set.
+func (amp *AzureMachinePool) ValidateOrchestrationMode(old runtime.Object) func() error {
+	return func() error {
+		if old == nil {
+			return nil
+		}
+
+		oldMachinePool, ok := old.(*AzureMachinePool)
+		if !ok {
+			return fmt.Errorf("unexpected type for old azure machine pool object. Expected: %q, Got: %q",
+				"AzureMachinePool", reflect.TypeOf(old))
+		}
+
+		var allErrs field.ErrorList
+		if orchestrationModeOrDefault(oldMachinePool.Spec.OrchestrationMode) != orchestrationModeOrDefault(amp.Spec.OrchestrationMode) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "OrchestrationMode"), amp.Spec.OrchestrationMode, "field is immutable"))
+		}
+
+		if !reflect.DeepEqual(oldMachinePool.Spec.PlatformFaultDomainCount, amp.Spec.PlatformFaultDomainCount) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "PlatformFaultDomainCount"), amp.Spec.PlatformFaultDomainCount, "field is immutable"))
+		}
+
+		if len(allErrs) > 0 {
+			return kerrors.NewAggregate(allErrs.ToAggregate().Errors())
+		}
+
+		return nil
+	}
+}
+
+// orchestrationModeOrDefault returns the orchestration mode, treating an unset mode as Uniform.
+func orchestrationModeOrDefault(mode infrav1.OrchestrationModeType) infrav1.OrchestrationModeType {
+	if mode == "" {
+		return infrav1.UniformOrchestrationMode
+	}
+	return mode
+}
+
+// ValidateUpgradePolicy validates the upgrade policy of the scale set.
+func (amp *AzureMachinePool) ValidateUpgradePolicy() error {
+	policy := amp.Spec.UpgradePolicy
+	if policy == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("Spec", "UpgradePolicy")
+	managedByAzure := policy.Mode == infrav1.UpgradeModeRolling || policy.Mode == infrav1.UpgradeModeAutomatic
+	automaticOSUpgrade := policy.AutomaticOSUpgradePolicy != nil && policy.AutomaticOSUpgradePolicy.EnableAutomaticOSUpgrade
+
+	if (managedByAzure || automaticOSUpgrade) && amp.Spec.OrchestrationMode == infrav1.FlexibleOrchestrationMode {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "upgrade policies are not supported with the Flexible orchestration mode"))
+	}
+
+	if (managedByAzure || automaticOSUpgrade) && amp.Spec.Template.ApplicationHealth == nil {
+		allErrs = append(allErrs, field.Required(field.NewPath("Spec", "Template", "ApplicationHealth"), "the application health extension is required by the Rolling and Automatic upgrade modes and by automatic OS upgrades"))
+	}
+
+	if policy.RollingUpgradePolicy != nil {
+		if policy.Mode != infrav1.UpgradeModeRolling && !automaticOSUpgrade {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("RollingUpgradePolicy"), "the rolling upgrade policy can only be set with the Rolling upgrade mode or automatic OS upgrades"))
+		}
+
+		if pause := policy.RollingUpgradePolicy.PauseTimeBetweenBatches; pause != nil && (pause.Duration < 0 || pause.Duration%time.Second != 0) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("RollingUpgradePolicy", "PauseTimeBetweenBatches"), pause.Duration.String(), "must be a positive whole number of seconds"))
+		}
+	}
+
+	if len(allErrs) > 0 {
+		return kerrors.NewAggregate(allErrs.ToAggregate().Errors())
+	}
+
+	return nil
+}
+
+// ValidateAutomaticRepairsPolicy validates the automatic repairs policy and the application health extension backing
+// it.
+func (amp *AzureMachinePool) ValidateAutomaticRepairsPolicy() error {
+	var allErrs field.ErrorList
+
+	if probe := amp.Spec.Template.ApplicationHealth; probe != nil && probe.Protocol == infrav1.ApplicationHealthProtocolTCP && probe.RequestPath != "" {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("Spec", "Template", "ApplicationHealth", "RequestPath"), "the request path cannot be set with the tcp protocol"))
+	}
+
+	policy := amp.Spec.AutomaticRepairsPolicy
+	if policy != nil && policy.Enabled {
+		fldPath := field.NewPath("Spec", "AutomaticRepairsPolicy")
+		if amp.Spec.Template.ApplicationHealth == nil {
+			allErrs = append(allErrs, field.Required(field.NewPath("Spec", "Template", "ApplicationHealth"), "the application health extension is required by automatic repairs"))
+		}
+
+		if grace := policy.GracePeriod; grace != nil && (grace.Duration < 30*time.Minute || grace.Duration > 90*time.Minute || grace.Duration%time.Minute != 0) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("GracePeriod"), grace.Duration.String(), "must be a whole number of minutes between 30m and 90m"))
+		}
+	}
+
+	if len(allErrs) > 0 {
+		return kerrors.NewAggregate(allErrs.ToAggregate().Errors())
+	}
+
+	return nil
+}
+
+// ValidateUpgradePolicyUpdate validates that the upgrade and automatic repairs policies of the scale set are not
+// changed once set.
+func (amp *AzureMachinePool) ValidateUpgradePolicyUpdate(old runtime.Object) func() error {
+	return func() error {
+		if old == nil {
+			return nil
+		}
+
+		oldMachinePool, ok := old.(*AzureMachinePool)
+		if !ok {
+			return fmt.Errorf("unexpected type for old azure machine pool object. Expected: %q, Got: %q",
+				"AzureMachinePool", reflect.TypeOf(old))
+		}
+
+		var allErrs field.ErrorList
+		if !reflect.DeepEqual(oldMachinePool.Spec.UpgradePolicy, amp.Spec.UpgradePolicy) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "UpgradePolicy"), amp.Spec.UpgradePolicy, "field is immutable"))
+		}
+
+		if !reflect.DeepEqual(oldMachinePool.Spec.AutomaticRepairsPolicy, amp.Spec.AutomaticRepairsPolicy) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "AutomaticRepairsPolicy"), amp.Spec.AutomaticRepairsPolicy, "field is immutable"))
+		}
+
+		if len(allErrs) > 0 {
+			return kerrors.NewAggregate(allErrs.ToAggregate().Errors())
+		}
+
+		return nil
+	}
+}
+
+// ValidateMixedInstancesPolicy validates the VM sizes of a machine pool with a mixed instances policy.
+func (amp *AzureMachinePool) ValidateMixedInstancesPolicy() error {
+	policy := amp.Spec.MixedInstancesPolicy
+	if policy == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("Spec", "MixedInstancesPolicy")
+	if amp.Spec.Template.OSDisk.OSType == azure.WindowsOS {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "mixed instances policies are not supported for Windows machine pools"))
+	}
+
+	if amp.Spec.Identity == infrav1.VMIdentitySystemAssigned {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "mixed instances policies are not supported with a system-assigned identity, use user-assigned identities instead"))
+	}
+
+	if amp.Spec.OrchestrationMode == infrav1.FlexibleOrchestrationMode {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "OrchestrationMode"), amp.Spec.OrchestrationMode, "mixed instances policies are only supported in Uniform orchestration mode"))
+	}
+
+	if upgradePolicy := amp.Spec.UpgradePolicy; upgradePolicy != nil && (upgradePolicy.Mode == infrav1.UpgradeModeRolling || upgradePolicy.Mode == infrav1.UpgradeModeAutomatic) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "UpgradePolicy", "Mode"), upgradePolicy.Mode, "mixed instances policies require the Manual upgrade mode, their scale sets are upgraded by the deployment strategy"))
+	}
+
+	vmSizes := map[string]struct{}{amp.Spec.Template.VMSize: {}}
+	for i, vmSize := range policy.FallbackVMSizes {
+		if _, ok := vmSizes[vmSize]; ok {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("FallbackVMSizes").Index(i), vmSize))
+		}
+		vmSizes[vmSize] = struct{}{}
+	}
+
+	if len(allErrs) > 0 {
+		return kerrors.NewAggregate(allErrs.ToAggregate().Errors())
+	}
+
+	return nil
+}
+
+// ValidateMixedInstancesPolicyUpdate validates that the mixed instances policy is not added to or removed from an
+// existing machine pool, and that no VM size backing one of its scale sets is removed.
+func (amp *AzureMachinePool) ValidateMixedInstancesPolicyUpdate(old runtime.Object) func() error {
+	return func() error {
+		if old == nil {
+			return nil
+		}
+
+		oldMachinePool, ok := old.(*AzureMachinePool)
+		if !ok {
+			return fmt.Errorf("unexpected type for old azure machine pool object. Expected: %q, Got: %q",
+				"AzureMachinePool", reflect.TypeOf(old))
+		}
+
+		oldPolicy, policy := oldMachinePool.Spec.MixedInstancesPolicy, amp.Spec.MixedInstancesPolicy
+		if (oldPolicy == nil) != (policy == nil) {
+			return field.Invalid(field.NewPath("Spec", "MixedInstancesPolicy"), policy, "cannot be added to or removed from an existing machine pool")
+		}
+
+		if policy == nil {
+			return nil
+		}
+
+		var allErrs field.ErrorList
+		if oldMachinePool.Spec.Template.VMSize != amp.Spec.Template.VMSize {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "Template", "VMSize"), amp.Spec.Template.VMSize, "field is immutable for a machine pool with a mixed instances policy, add a fallback VM size instead"))
+		}
+
+		for _, vmSize := range oldPolicy.FallbackVMSizes {
+			if !slice.Contains(policy.FallbackVMSizes, vmSize) {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "MixedInstancesPolicy", "FallbackVMSizes"), policy.FallbackVMSizes, fmt.Sprintf("VM size %s cannot be removed", vmSize)))
+			}
+		}
+
+		if len(allErrs) > 0 {
+			return kerrors.NewAggregate(allErrs.ToAggregate().Errors())
+		}
+
+		return nil
+	}
+}
+
+// ValidateScaling validates the node group size, labels and taints advertised to the cluster autoscaler.
+func (amp *AzureMachinePool) ValidateScaling() error {
+	scaling := amp.Spec.Scaling
+	if scaling == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("Spec", "Scaling")
+	if scaling.MinSize < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("MinSize"), scaling.MinSize, "must not be negative"))
+	}
+
+	if scaling.MaxSize < scaling.MinSize {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("MaxSize"), scaling.MaxSize, "must be greater than or equal to MinSize"))
+	}
+
+	allErrs = append(allErrs, metav1validation.ValidateLabels(scaling.NodeLabels, fldPath.Child("NodeLabels"))...)
+
+	validEffects := []string{string(corev1.TaintEffectNoSchedule), string(corev1.TaintEffectPreferNoSchedule), string(corev1.TaintEffectNoExecute)}
+	for i, taint := range scaling.NodeTaints {
+		if taint.Key == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("NodeTaints").Index(i).Child("Key"), "taint key is required"))
+		}
+
+		if !slice.Contains(validEffects, string(taint.Effect)) {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("NodeTaints").Index(i).Child("Effect"), taint.Effect, validEffects))
+		}
+	}
+
+	if len(allErrs) > 0 {
+		return kerrors.NewAggregate(allErrs.ToAggregate().Errors())
+	}
+
+	return nil
+}
@@ -21,11 +21,14 @@ import (
 	"crypto/rsa"
 	"encoding/base64"
 	"testing"
+	"time"
 
 	"github.com/Azure/go-autorest/autorest/to"
 	guuid "github.com/google/uuid"
 	. "github.com/onsi/gomega"
 	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
@@ -39,8 +42,9 @@ func TestAzureMachinePool_ValidateCreate(t *testing.T) {
 	g := NewWithT(t)
 
 	var (
-		zero = intstr.FromInt(0)
-		one  = intstr.FromInt(1)
+		zero       = intstr.FromInt(0)
+		one        = intstr.FromInt(1)
+		tenPercent = intstr.FromString("10%")
 	)
 
 	tests := []struct {
@@ -135,6 +139,453 @@ func TestAzureMachinePool_ValidateCreate(t *testing.T) {
 			}),
 			wantErr: false,
 		},
+		{
+			name: "azuremachinepool with valid VM extensions",
+			amp: createMachinePoolWithVMExtensions([]infrav1.VMExtension{
+				{Name: "monitoring-agent", Publisher: "Microsoft.Azure.Monitor", Type: "AzureMonitorLinuxAgent", Version: "1.0"},
+			}),
+			wantErr: false,
+		},
+		{
+			name: "azuremachinepool with duplicate VM extensions",
+			amp: createMachinePoolWithVMExtensions([]infrav1.VMExtension{
+				{Name: "monitoring-agent", Publisher: "Microsoft.Azure.Monitor", Type: "AzureMonitorLinuxAgent", Version: "1.0"},
+				{Name: "monitoring-agent", Publisher: "Microsoft.Azure.Monitor", Type: "AzureMonitorLinuxAgent", Version: "1.0"},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with user managed boot diagnostics",
+			amp: createMachinePoolWithDiagnostics(&infrav1.Diagnostics{
+				Boot: &infrav1.BootDiagnostics{
+					StorageAccountType: infrav1.UserManagedDiagnosticsStorage,
+					UserManaged:        &infrav1.UserManagedBootDiagnostics{StorageAccountURI: "https://fake.blob.core.windows.net/"},
+				},
+			}),
+			wantErr: false,
+		},
+		{
+			name: "azuremachinepool with user managed boot diagnostics missing the storage account",
+			amp: createMachinePoolWithDiagnostics(&infrav1.Diagnostics{
+				Boot: &infrav1.BootDiagnostics{StorageAccountType: infrav1.UserManagedDiagnosticsStorage},
+			}),
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with rolling upgrade policy and application health",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					UpgradePolicy: rollingUpgradePolicy(),
+					Template: AzureMachinePoolMachineTemplate{
+						ApplicationHealth: httpHealthProbe(),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "azuremachinepool with rolling upgrade policy without application health",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					UpgradePolicy: rollingUpgradePolicy(),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with rolling upgrade policy in Manual mode",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					UpgradePolicy: &infrav1.UpgradePolicy{
+						Mode:                 infrav1.UpgradeModeManual,
+						RollingUpgradePolicy: &infrav1.RollingUpgradePolicy{MaxBatchInstancePercent: to.Int32Ptr(20)},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with rolling upgrade policy and Flexible orchestration mode",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					OrchestrationMode: infrav1.FlexibleOrchestrationMode,
+					UpgradePolicy:     rollingUpgradePolicy(),
+					Template: AzureMachinePoolMachineTemplate{
+						ApplicationHealth: httpHealthProbe(),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with sub-second pause time between batches",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					UpgradePolicy: &infrav1.UpgradePolicy{
+						Mode: infrav1.UpgradeModeRolling,
+						RollingUpgradePolicy: &infrav1.RollingUpgradePolicy{
+							PauseTimeBetweenBatches: &metav1.Duration{Duration: 1500 * time.Millisecond},
+						},
+					},
+					Template: AzureMachinePoolMachineTemplate{
+						ApplicationHealth: httpHealthProbe(),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with automatic OS upgrades without application health",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					UpgradePolicy: &infrav1.UpgradePolicy{
+						Mode:                     infrav1.UpgradeModeManual,
+						AutomaticOSUpgradePolicy: &infrav1.AutomaticOSUpgradePolicy{EnableAutomaticOSUpgrade: true},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with automatic repairs and application health",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					AutomaticRepairsPolicy: &infrav1.AutomaticRepairsPolicy{
+						Enabled:     true,
+						GracePeriod: &metav1.Duration{Duration: 30 * time.Minute},
+					},
+					Template: AzureMachinePoolMachineTemplate{
+						ApplicationHealth: httpHealthProbe(),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "azuremachinepool with automatic repairs without application health",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					AutomaticRepairsPolicy: &infrav1.AutomaticRepairsPolicy{Enabled: true},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with automatic repairs grace period out of range",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					AutomaticRepairsPolicy: &infrav1.AutomaticRepairsPolicy{
+						Enabled:     true,
+						GracePeriod: &metav1.Duration{Duration: 10 * time.Minute},
+					},
+					Template: AzureMachinePoolMachineTemplate{
+						ApplicationHealth: httpHealthProbe(),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with tcp application health and request path",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					Template: AzureMachinePoolMachineTemplate{
+						ApplicationHealth: &infrav1.ApplicationHealthProbe{
+							Protocol:    infrav1.ApplicationHealthProtocolTCP,
+							Port:        10250,
+							RequestPath: "/healthz",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with mixed instances policy",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					Template: AzureMachinePoolMachineTemplate{
+						VMSize: "Standard_D2s_v3",
+					},
+					MixedInstancesPolicy: &MixedInstancesPolicy{
+						FallbackVMSizes: []string{"Standard_D2as_v4"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "azuremachinepool with mixed instances policy and duplicate VM sizes",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					Template: AzureMachinePoolMachineTemplate{
+						VMSize: "Standard_D2s_v3",
+					},
+					MixedInstancesPolicy: &MixedInstancesPolicy{
+						FallbackVMSizes: []string{"Standard_D2as_v4", "Standard_D2s_v3"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with mixed instances policy and Windows",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					Template: AzureMachinePoolMachineTemplate{
+						VMSize: "Standard_D2s_v3",
+						OSDisk: infrav1.OSDisk{OSType: "Windows"},
+					},
+					MixedInstancesPolicy: &MixedInstancesPolicy{},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with mixed instances policy and system-assigned identity",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					Identity: infrav1.VMIdentitySystemAssigned,
+					Template: AzureMachinePoolMachineTemplate{
+						VMSize: "Standard_D2s_v3",
+					},
+					MixedInstancesPolicy: &MixedInstancesPolicy{},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with mixed instances policy and Flexible orchestration mode",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					OrchestrationMode: infrav1.FlexibleOrchestrationMode,
+					Template: AzureMachinePoolMachineTemplate{
+						VMSize: "Standard_D2s_v3",
+					},
+					MixedInstancesPolicy: &MixedInstancesPolicy{},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with mixed instances policy and Rolling upgrade mode",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					UpgradePolicy: rollingUpgradePolicy(),
+					Template: AzureMachinePoolMachineTemplate{
+						VMSize:            "Standard_D2s_v3",
+						ApplicationHealth: httpHealthProbe(),
+					},
+					MixedInstancesPolicy: &MixedInstancesPolicy{},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with mixed instances policy and Automatic upgrade mode",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					UpgradePolicy: &infrav1.UpgradePolicy{Mode: infrav1.UpgradeModeAutomatic},
+					Template: AzureMachinePoolMachineTemplate{
+						VMSize: "Standard_D2s_v3",
+					},
+					MixedInstancesPolicy: &MixedInstancesPolicy{},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with mixed instances policy and Manual upgrade mode",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					UpgradePolicy: &infrav1.UpgradePolicy{Mode: infrav1.UpgradeModeManual},
+					Template: AzureMachinePoolMachineTemplate{
+						VMSize: "Standard_D2s_v3",
+					},
+					MixedInstancesPolicy: &MixedInstancesPolicy{},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "azuremachinepool with node drain options",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					NodeDrainOptions: &NodeDrainOptions{
+						SkipWaitForDeleteTimeout: &metav1.Duration{Duration: time.Minute},
+						DeleteEmptyDirData:       to.BoolPtr(false),
+						IgnoreDaemonSets:         to.BoolPtr(false),
+						PodSelector:              "app=stateful,tier!=cache",
+						GracePeriod:              &metav1.Duration{Duration: 30 * time.Second},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "azuremachinepool with negative node drain grace period",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					NodeDrainOptions: &NodeDrainOptions{GracePeriod: &metav1.Duration{Duration: -time.Second}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with negative node drain skip wait for delete timeout",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					NodeDrainOptions: &NodeDrainOptions{SkipWaitForDeleteTimeout: &metav1.Duration{Duration: -time.Second}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with invalid node drain pod selector",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					NodeDrainOptions: &NodeDrainOptions{PodSelector: "app in (stateful"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with scaling",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					Scaling: &MachinePoolScaling{
+						MinSize:    0,
+						MaxSize:    10,
+						NodeLabels: map[string]string{"nvidia.com/gpu.present": "true"},
+						NodeTaints: []corev1.Taint{{Key: "nvidia.com/gpu", Value: "present", Effect: corev1.TaintEffectNoSchedule}},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "azuremachinepool with scaling max size lower than min size",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					Scaling: &MachinePoolScaling{MinSize: 3, MaxSize: 2},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with scaling invalid node label",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					Scaling: &MachinePoolScaling{MaxSize: 2, NodeLabels: map[string]string{"gpu": "not valid"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with scaling invalid node taint effect",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					Scaling: &MachinePoolScaling{MaxSize: 2, NodeTaints: []corev1.Taint{{Key: "gpu", Effect: "NoWay"}}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with blue/green strategy",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					Strategy: AzureMachinePoolDeploymentStrategy{
+						Type:      BlueGreenAzureMachinePoolDeploymentStrategyType,
+						BlueGreen: &MachineBlueGreenDeployment{SoakDuration: &metav1.Duration{Duration: 10 * time.Minute}},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "azuremachinepool with blue/green strategy and negative soak duration",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					Strategy: AzureMachinePoolDeploymentStrategy{
+						Type:      BlueGreenAzureMachinePoolDeploymentStrategyType,
+						BlueGreen: &MachineBlueGreenDeployment{SoakDuration: &metav1.Duration{Duration: -time.Minute}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with blue/green strategy and zero readiness timeout",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					Strategy: AzureMachinePoolDeploymentStrategy{
+						Type:      BlueGreenAzureMachinePoolDeploymentStrategyType,
+						BlueGreen: &MachineBlueGreenDeployment{ReadinessTimeout: &metav1.Duration{}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with blue/green strategy and max unavailable percentage",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					Strategy: AzureMachinePoolDeploymentStrategy{
+						Type:      BlueGreenAzureMachinePoolDeploymentStrategyType,
+						BlueGreen: &MachineBlueGreenDeployment{MaxUnavailable: &tenPercent},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "azuremachinepool with blue/green strategy and zero max unavailable",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					Strategy: AzureMachinePoolDeploymentStrategy{
+						Type:      BlueGreenAzureMachinePoolDeploymentStrategyType,
+						BlueGreen: &MachineBlueGreenDeployment{MaxUnavailable: &zero},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with blue/green strategy and mixed instances policy",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					Strategy: AzureMachinePoolDeploymentStrategy{Type: BlueGreenAzureMachinePoolDeploymentStrategyType},
+					Template: AzureMachinePoolMachineTemplate{
+						VMSize: "Standard_D2s_v3",
+					},
+					MixedInstancesPolicy: &MixedInstancesPolicy{},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with blue/green strategy and Flexible orchestration mode",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					Strategy:          AzureMachinePoolDeploymentStrategy{Type: BlueGreenAzureMachinePoolDeploymentStrategyType},
+					OrchestrationMode: infrav1.FlexibleOrchestrationMode,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with blue/green strategy and Rolling upgrade mode",
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					Strategy:      AzureMachinePoolDeploymentStrategy{Type: BlueGreenAzureMachinePoolDeploymentStrategyType},
+					UpgradePolicy: rollingUpgradePolicy(),
+					Template: AzureMachinePoolMachineTemplate{
+						ApplicationHealth: httpHealthProbe(),
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -210,6 +661,189 @@ func TestAzureMachinePool_ValidateUpdate(t *testing.T) {
 			}),
 			wantErr: false,
 		},
+		{
+			name:    "azuremachinepool with unset orchestration mode defaulted to Uniform",
+			oldAMP:  createMachinePoolWithOrchestrationMode("", nil),
+			amp:     createMachinePoolWithOrchestrationMode(infrav1.UniformOrchestrationMode, nil),
+			wantErr: false,
+		},
+		{
+			name:    "azuremachinepool with orchestration mode changed",
+			oldAMP:  createMachinePoolWithOrchestrationMode(infrav1.UniformOrchestrationMode, nil),
+			amp:     createMachinePoolWithOrchestrationMode(infrav1.FlexibleOrchestrationMode, nil),
+			wantErr: true,
+		},
+		{
+			name:    "azuremachinepool with platform fault domain count changed",
+			oldAMP:  createMachinePoolWithOrchestrationMode(infrav1.FlexibleOrchestrationMode, nil),
+			amp:     createMachinePoolWithOrchestrationMode(infrav1.FlexibleOrchestrationMode, to.Int32Ptr(2)),
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with upgrade policy unchanged",
+			oldAMP: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					UpgradePolicy: rollingUpgradePolicy(),
+					Template: AzureMachinePoolMachineTemplate{
+						ApplicationHealth: httpHealthProbe(),
+					},
+				},
+			},
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					UpgradePolicy: rollingUpgradePolicy(),
+					Template: AzureMachinePoolMachineTemplate{
+						ApplicationHealth: httpHealthProbe(),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "azuremachinepool with upgrade policy changed",
+			oldAMP: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					Template: AzureMachinePoolMachineTemplate{
+						ApplicationHealth: httpHealthProbe(),
+					},
+				},
+			},
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					UpgradePolicy: rollingUpgradePolicy(),
+					Template: AzureMachinePoolMachineTemplate{
+						ApplicationHealth: httpHealthProbe(),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with automatic repairs policy changed",
+			oldAMP: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					Template: AzureMachinePoolMachineTemplate{
+						ApplicationHealth: httpHealthProbe(),
+					},
+				},
+			},
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					AutomaticRepairsPolicy: &infrav1.AutomaticRepairsPolicy{Enabled: true},
+					Template: AzureMachinePoolMachineTemplate{
+						ApplicationHealth: httpHealthProbe(),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with fallback VM size added",
+			oldAMP: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					Template: AzureMachinePoolMachineTemplate{
+						VMSize: "Standard_D2s_v3",
+					},
+					MixedInstancesPolicy: &MixedInstancesPolicy{},
+				},
+			},
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					Template: AzureMachinePoolMachineTemplate{
+						VMSize: "Standard_D2s_v3",
+					},
+					MixedInstancesPolicy: &MixedInstancesPolicy{
+						FallbackVMSizes: []string{"Standard_D2as_v4"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "azuremachinepool with fallback VM size removed",
+			oldAMP: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					Template: AzureMachinePoolMachineTemplate{
+						VMSize: "Standard_D2s_v3",
+					},
+					MixedInstancesPolicy: &MixedInstancesPolicy{
+						FallbackVMSizes: []string{"Standard_D2as_v4"},
+					},
+				},
+			},
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					Template: AzureMachinePoolMachineTemplate{
+						VMSize: "Standard_D2s_v3",
+					},
+					MixedInstancesPolicy: &MixedInstancesPolicy{},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepool with mixed instances policy and VM size changed",
+			oldAMP: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					Template: AzureMachinePoolMachineTemplate{
+						VMSize: "Standard_D2s_v3",
+					},
+					MixedInstancesPolicy: &MixedInstancesPolicy{},
+				},
+			},
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					Template: AzureMachinePoolMachineTemplate{
+						VMSize: "Standard_D4s_v3",
+					},
+					MixedInstancesPolicy: &MixedInstancesPolicy{},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:   "azuremachinepool with mixed instances policy added",
+			oldAMP: createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{}),
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					MixedInstancesPolicy: &MixedInstancesPolicy{},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:   "azuremachinepool switched to the blue/green strategy",
+			oldAMP: createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{Type: RollingUpdateAzureMachinePoolDeploymentStrategyType}),
+			amp: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					Strategy: AzureMachinePoolDeploymentStrategy{Type: BlueGreenAzureMachinePoolDeploymentStrategyType},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "azuremachinepool switched away from the blue/green strategy before it was deployed",
+			oldAMP: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					Strategy: AzureMachinePoolDeploymentStrategy{Type: BlueGreenAzureMachinePoolDeploymentStrategyType},
+				},
+			},
+			amp:     createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{Type: RollingUpdateAzureMachinePoolDeploymentStrategyType}),
+			wantErr: false,
+		},
+		{
+			name: "azuremachinepool switched away from the blue/green strategy once deployed",
+			oldAMP: &AzureMachinePool{
+				Spec: AzureMachinePoolSpec{
+					Strategy: AzureMachinePoolDeploymentStrategy{Type: BlueGreenAzureMachinePoolDeploymentStrategyType},
+				},
+				Status: AzureMachinePoolStatus{
+					BlueGreen: &AzureMachinePoolBlueGreenStatus{ActiveScaleSet: "pool-0a1b2c3d", ActiveRevision: "0a1b2c3d"},
+				},
+			},
+			amp:     createMachinePoolWithStrategy(AzureMachinePoolDeploymentStrategy{Type: RollingUpdateAzureMachinePoolDeploymentStrategyType}),
+			wantErr: true,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -361,3 +995,50 @@ func createMachinePoolWithStrategy(strategy AzureMachinePoolDeploymentStrategy)
 		},
 	}
 }
+
+func createMachinePoolWithVMExtensions(extensions []infrav1.VMExtension) *AzureMachinePool {
+	return &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			Template: AzureMachinePoolMachineTemplate{
+				VMExtensions: extensions,
+			},
+		},
+	}
+}
+
+func createMachinePoolWithDiagnostics(diagnostics *infrav1.Diagnostics) *AzureMachinePool {
+	return &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			Template: AzureMachinePoolMachineTemplate{
+				Diagnostics: diagnostics,
+			},
+		},
+	}
+}
+
+func createMachinePoolWithOrchestrationMode(mode infrav1.OrchestrationModeType, platformFaultDomainCount *int32) *AzureMachinePool {
+	return &AzureMachinePool{
+		Spec: AzureMachinePoolSpec{
+			OrchestrationMode:        mode,
+			PlatformFaultDomainCount: platformFaultDomainCount,
+		},
+	}
+}
+
+func rollingUpgradePolicy() *infrav1.UpgradePolicy {
+	return &infrav1.UpgradePolicy{
+		Mode: infrav1.UpgradeModeRolling,
+		RollingUpgradePolicy: &infrav1.RollingUpgradePolicy{
+			MaxBatchInstancePercent: to.Int32Ptr(20),
+			PauseTimeBetweenBatches: &metav1.Duration{Duration: 30 * time.Second},
+		},
+	}
+}
+
+func httpHealthProbe() *infrav1.ApplicationHealthProbe {
+	return &infrav1.ApplicationHealthProbe{
+		Protocol:    infrav1.ApplicationHealthProtocolHTTP,
+		Port:        10248,
+		RequestPath: "/healthz",
+	}
+}
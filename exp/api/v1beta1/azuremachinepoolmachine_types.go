@@ -27,6 +27,16 @@ import (
 const (
 	// AzureMachinePoolMachineFinalizer is used to ensure deletion of dependencies (nodes, infra).
 	AzureMachinePoolMachineFinalizer = "azuremachinepoolmachine.infrastructure.cluster.x-k8s.io"
+
+	// ScheduledEventsAnnotation is set on the Node of an AzureMachinePoolMachine by a relay running on the node. It
+	// holds the JSON list of the scheduled events of the VM, as returned in the Events field of the Azure Instance
+	// Metadata Service scheduled events endpoint.
+	ScheduledEventsAnnotation = "azuremachinepoolmachine.infrastructure.cluster.x-k8s.io/scheduled-events"
+
+	// ApprovedScheduledEventsAnnotation is set on the Node of an AzureMachinePoolMachine once the node is drained
+	// ahead of its scheduled events. It holds the comma-separated IDs of the events the relay running on the node
+	// should approve through the Azure Instance Metadata Service.
+	ApprovedScheduledEventsAnnotation = "azuremachinepoolmachine.infrastructure.cluster.x-k8s.io/approved-scheduled-events"
 )
 
 type (
@@ -38,6 +48,24 @@ type (
 
 		// InstanceID is the identification of the Machine Instance within the VMSS
 		InstanceID string `json:"instanceID"`
+
+		// ProtectionPolicy specifies the Azure instance protection of the Machine Instance within the VMSS. An instance
+		// protected from scale-in is never selected for deletion when the AzureMachinePool scales in or rolls out a new
+		// model. The protection is only supported for scale sets in Uniform orchestration mode.
+		// +optional
+		ProtectionPolicy *InstanceProtectionPolicy `json:"protectionPolicy,omitempty"`
+	}
+
+	// InstanceProtectionPolicy defines the Azure instance protection of a Machine Instance within a VMSS.
+	InstanceProtectionPolicy struct {
+		// ProtectFromScaleIn indicates the instance must not be deleted when the scale set scales in.
+		// +optional
+		ProtectFromScaleIn bool `json:"protectFromScaleIn,omitempty"`
+
+		// ProtectFromScaleSetActions indicates that updates or actions applied to the scale set, such as a model upgrade
+		// or a reimage, must not be applied to the instance. It requires ProtectFromScaleIn to be set.
+		// +optional
+		ProtectFromScaleSetActions bool `json:"protectFromScaleSetActions,omitempty"`
 	}
 
 	// AzureMachinePoolMachineStatus defines the observed state of AzureMachinePoolMachine.
@@ -58,6 +86,10 @@ type (
 		// +optional
 		InstanceName string `json:"instanceName"`
 
+		// FailureDomain is the availability zone the Machine Instance is running in.
+		// +optional
+		FailureDomain string `json:"failureDomain,omitempty"`
+
 		// FailureReason will be set in the event that there is a terminal problem
 		// reconciling the MachinePool machine and will contain a succinct value suitable
 		// for machine interpretation.
@@ -82,16 +114,31 @@ type (
 		// +optional
 		Conditions clusterv1.Conditions `json:"conditions,omitempty"`
 
+		// NodeDiagnosticsRef is a reference to the Secret containing the node diagnostics collected after a bootstrap
+		// failure. It is only set when the node diagnostics output is Secret.
+		// +optional
+		NodeDiagnosticsRef *corev1.LocalObjectReference `json:"nodeDiagnosticsRef,omitempty"`
+
 		// LongRunningOperationStates saves the state for Azure long running operations so they can be continued on the
 		// next reconciliation loop.
 		// +optional
 		LongRunningOperationStates infrav1.Futures `json:"longRunningOperationStates,omitempty"`
 
+		// ProtectionPolicy is the Azure instance protection currently applied to the Machine Instance within the VMSS.
+		// +optional
+		ProtectionPolicy *InstanceProtectionPolicy `json:"protectionPolicy,omitempty"`
+
 		// LatestModelApplied indicates the instance is running the most up-to-date VMSS model. A VMSS model describes
 		// the image version the VM is running. If the instance is not running the latest model, it means the instance
 		// may not be running the version of Kubernetes the Machine Pool has specified and needs to be updated.
 		LatestModelApplied bool `json:"latestModelApplied"`
 
+		// ModelDifferences are the parts of the scale set model the Machine Instance within the VMSS differs in. They
+		// are observed by the AzureMachinePool controller, which compares the model of the VMSS with the model of its
+		// instances.
+		// +optional
+		ModelDifferences []infrav1.ModelDifference `json:"modelDifferences,omitempty"`
+
 		// Ready is true when the provider resource is ready.
 		// +optional
 		Ready bool `json:"ready"`
@@ -103,6 +150,7 @@ type (
 	// +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.version",description="Kubernetes version"
 	// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.ready",description="Flag indicating infrastructure is successfully provisioned"
 	// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.provisioningState",description="Azure VMSS VM provisioning state"
+	// +kubebuilder:printcolumn:name="Protected",type="boolean",priority=1,JSONPath=".status.protectionPolicy.protectFromScaleIn",description="Flag indicating the Azure VMSS VM is protected from scale-in"
 	// +kubebuilder:printcolumn:name="Cluster",type="string",priority=1,JSONPath=".metadata.labels.cluster\\.x-k8s\\.io/cluster-name",description="Cluster to which this AzureMachinePoolMachine belongs"
 	// +kubebuilder:printcolumn:name="VMSS VM ID",type="string",priority=1,JSONPath=".spec.providerID",description="Azure VMSS VM ID"
 	// +kubebuilder:storageversion
@@ -17,30 +17,24 @@ limitations under the License.
 package v1beta1
 
 import (
+	"context"
+
 	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
-	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-// SetupWebhookWithManager sets up and registers the webhook with the manager.
-func (ampm *AzureMachinePoolMachine) SetupWebhookWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewWebhookManagedBy(mgr).
-		For(ampm).
-		Complete()
-}
-
 // +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-azuremachinepoolmachine,mutating=false,failurePolicy=fail,groups=infrastructure.cluster.x-k8s.io,resources=azuremachinepoolmachines,versions=v1beta1,name=azuremachinepoolmachine.kb.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
 
-var _ webhook.Validator = &AzureMachinePoolMachine{}
-
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
-func (ampm *AzureMachinePoolMachine) ValidateCreate() error {
-	return nil
+func (ampm *AzureMachinePoolMachine) ValidateCreate(client client.Client) error {
+	return ampm.ValidateProtectionPolicy(client)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
-func (ampm *AzureMachinePoolMachine) ValidateUpdate(old runtime.Object) error {
+func (ampm *AzureMachinePoolMachine) ValidateUpdate(old runtime.Object, client client.Client) error {
 	oldMachine, ok := old.(*AzureMachinePoolMachine)
 	if !ok {
 		return errors.New("expected and AzureMachinePoolMachine")
@@ -50,10 +44,61 @@ func (ampm *AzureMachinePoolMachine) ValidateUpdate(old runtime.Object) error {
 		return errors.New("providerID is immutable")
 	}
 
+	return ampm.ValidateProtectionPolicy(client)
+}
+
+// ValidateProtectionPolicy validates the instance protection of the AzureMachinePoolMachine. Azure only allows to
+// protect an instance from scale set actions if it is also protected from scale-in, and only for the instances of a
+// scale set in Uniform orchestration mode.
+func (ampm *AzureMachinePoolMachine) ValidateProtectionPolicy(client client.Client) error {
+	policy := ampm.Spec.ProtectionPolicy
+	if policy == nil {
+		return nil
+	}
+
+	if policy.ProtectFromScaleSetActions && !policy.ProtectFromScaleIn {
+		return errors.New("protectionPolicy.protectFromScaleSetActions requires protectionPolicy.protectFromScaleIn")
+	}
+
+	machinePool, err := ampm.ownerMachinePool(client)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the AzureMachinePool of the machine")
+	}
+
+	if machinePool != nil && machinePool.Spec.OrchestrationMode == infrav1.FlexibleOrchestrationMode {
+		return errors.New("protectionPolicy is not supported for the machines of an AzureMachinePool in Flexible orchestration mode")
+	}
+
 	return nil
 }
 
+// ownerMachinePool returns the AzureMachinePool the machine belongs to. It returns nil if the machine has no owner
+// AzureMachinePool, or if the AzureMachinePool no longer exists.
+func (ampm *AzureMachinePoolMachine) ownerMachinePool(cli client.Client) (*AzureMachinePool, error) {
+	for _, ref := range ampm.OwnerReferences {
+		if ref.Kind != "AzureMachinePool" {
+			continue
+		}
+
+		machinePool := &AzureMachinePool{}
+		key := client.ObjectKey{
+			Namespace: ampm.Namespace,
+			Name:      ref.Name,
+		}
+		if err := cli.Get(context.Background(), key, machinePool); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+
+		return machinePool, nil
+	}
+
+	return nil, nil
+}
+
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
-func (ampm *AzureMachinePoolMachine) ValidateDelete() error {
+func (ampm *AzureMachinePoolMachine) ValidateDelete(client client.Client) error {
 	return nil
 }
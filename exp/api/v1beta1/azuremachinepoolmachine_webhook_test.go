@@ -0,0 +1,221 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAzureMachinePoolMachine_ValidateCreate(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(AddToScheme(scheme)).To(Succeed())
+
+	ownerReferences := func(poolName string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{APIVersion: GroupVersion.String(), Kind: "AzureMachinePool", Name: poolName}}
+	}
+
+	tests := []struct {
+		name    string
+		ampm    *AzureMachinePoolMachine
+		wantErr bool
+	}{
+		{
+			name: "azuremachinepoolmachine without protection policy",
+			ampm: &AzureMachinePoolMachine{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", OwnerReferences: ownerReferences("flexible")},
+				Spec: AzureMachinePoolMachineSpec{
+					ProviderID: "azure:///subscriptions/123/resourceGroups/rg/providers/Microsoft.Compute/virtualMachineScaleSets/vmss/virtualMachines/0",
+					InstanceID: "0",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "azuremachinepoolmachine protected from scale-in",
+			ampm: &AzureMachinePoolMachine{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", OwnerReferences: ownerReferences("uniform")},
+				Spec: AzureMachinePoolMachineSpec{
+					ProviderID: "azure:///subscriptions/123/resourceGroups/rg/providers/Microsoft.Compute/virtualMachineScaleSets/vmss/virtualMachines/0",
+					InstanceID: "0",
+					ProtectionPolicy: &InstanceProtectionPolicy{
+						ProtectFromScaleIn: true,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "azuremachinepoolmachine protected from scale-in and scale set actions",
+			ampm: &AzureMachinePoolMachine{
+				Spec: AzureMachinePoolMachineSpec{
+					ProviderID: "azure:///subscriptions/123/resourceGroups/rg/providers/Microsoft.Compute/virtualMachineScaleSets/vmss/virtualMachines/0",
+					InstanceID: "0",
+					ProtectionPolicy: &InstanceProtectionPolicy{
+						ProtectFromScaleIn:         true,
+						ProtectFromScaleSetActions: true,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "azuremachinepoolmachine protected from scale set actions but not from scale-in",
+			ampm: &AzureMachinePoolMachine{
+				Spec: AzureMachinePoolMachineSpec{
+					ProviderID: "azure:///subscriptions/123/resourceGroups/rg/providers/Microsoft.Compute/virtualMachineScaleSets/vmss/virtualMachines/0",
+					InstanceID: "0",
+					ProtectionPolicy: &InstanceProtectionPolicy{
+						ProtectFromScaleSetActions: true,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepoolmachine of a Flexible machine pool protected from scale-in",
+			ampm: &AzureMachinePoolMachine{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", OwnerReferences: ownerReferences("flexible")},
+				Spec: AzureMachinePoolMachineSpec{
+					ProviderID: "azure:///subscriptions/123/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm-0",
+					InstanceID: "vm-0",
+					ProtectionPolicy: &InstanceProtectionPolicy{
+						ProtectFromScaleIn: true,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepoolmachine of a deleted machine pool protected from scale-in",
+			ampm: &AzureMachinePoolMachine{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", OwnerReferences: ownerReferences("deleted")},
+				Spec: AzureMachinePoolMachineSpec{
+					ProviderID: "azure:///subscriptions/123/resourceGroups/rg/providers/Microsoft.Compute/virtualMachineScaleSets/vmss/virtualMachines/0",
+					InstanceID: "0",
+					ProtectionPolicy: &InstanceProtectionPolicy{
+						ProtectFromScaleIn: true,
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&AzureMachinePool{
+					ObjectMeta: metav1.ObjectMeta{Name: "uniform", Namespace: "default"},
+					Spec:       AzureMachinePoolSpec{OrchestrationMode: infrav1.UniformOrchestrationMode},
+				},
+				&AzureMachinePool{
+					ObjectMeta: metav1.ObjectMeta{Name: "flexible", Namespace: "default"},
+					Spec:       AzureMachinePoolSpec{OrchestrationMode: infrav1.FlexibleOrchestrationMode},
+				},
+			).Build()
+
+			err := tc.ampm.ValidateCreate(client)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAzureMachinePoolMachine_ValidateUpdate(t *testing.T) {
+	tests := []struct {
+		name    string
+		oldAMPM *AzureMachinePoolMachine
+		ampm    *AzureMachinePoolMachine
+		wantErr bool
+	}{
+		{
+			name: "azuremachinepoolmachine protection policy is added",
+			oldAMPM: &AzureMachinePoolMachine{
+				Spec: AzureMachinePoolMachineSpec{
+					ProviderID: "azure:///subscriptions/123/resourceGroups/rg/providers/Microsoft.Compute/virtualMachineScaleSets/vmss/virtualMachines/0",
+					InstanceID: "0",
+				},
+			},
+			ampm: &AzureMachinePoolMachine{
+				Spec: AzureMachinePoolMachineSpec{
+					ProviderID: "azure:///subscriptions/123/resourceGroups/rg/providers/Microsoft.Compute/virtualMachineScaleSets/vmss/virtualMachines/0",
+					InstanceID: "0",
+					ProtectionPolicy: &InstanceProtectionPolicy{
+						ProtectFromScaleIn: true,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "azuremachinepoolmachine invalid protection policy is added",
+			oldAMPM: &AzureMachinePoolMachine{
+				Spec: AzureMachinePoolMachineSpec{
+					ProviderID: "azure:///subscriptions/123/resourceGroups/rg/providers/Microsoft.Compute/virtualMachineScaleSets/vmss/virtualMachines/0",
+					InstanceID: "0",
+				},
+			},
+			ampm: &AzureMachinePoolMachine{
+				Spec: AzureMachinePoolMachineSpec{
+					ProviderID: "azure:///subscriptions/123/resourceGroups/rg/providers/Microsoft.Compute/virtualMachineScaleSets/vmss/virtualMachines/0",
+					InstanceID: "0",
+					ProtectionPolicy: &InstanceProtectionPolicy{
+						ProtectFromScaleSetActions: true,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "azuremachinepoolmachine providerID is changed",
+			oldAMPM: &AzureMachinePoolMachine{
+				Spec: AzureMachinePoolMachineSpec{
+					ProviderID: "azure:///subscriptions/123/resourceGroups/rg/providers/Microsoft.Compute/virtualMachineScaleSets/vmss/virtualMachines/0",
+					InstanceID: "0",
+				},
+			},
+			ampm: &AzureMachinePoolMachine{
+				Spec: AzureMachinePoolMachineSpec{
+					ProviderID: "azure:///subscriptions/123/resourceGroups/rg/providers/Microsoft.Compute/virtualMachineScaleSets/vmss/virtualMachines/1",
+					InstanceID: "0",
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			err := tc.ampm.ValidateUpdate(tc.oldAMPM, nil)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -60,6 +60,9 @@ func (r *AzureManagedControlPlane) setDefaultVirtualNetwork() {
 	if r.Spec.VirtualNetwork.CIDRBlock == "" {
 		r.Spec.VirtualNetwork.CIDRBlock = defaultAKSVnetCIDR
 	}
+	if r.Spec.VirtualNetwork.ResourceGroup == "" {
+		r.Spec.VirtualNetwork.ResourceGroup = r.Spec.ResourceGroupName
+	}
 }
 
 // setDefaultSubnet sets the default Subnet for an AzureManagedControlPlane.
@@ -79,3 +82,10 @@ func (r *AzureManagedControlPlane) setDefaultSku() {
 		}
 	}
 }
+
+// setDefaultIdentity sets the default type of the identity of an AzureManagedControlPlane.
+func (r *AzureManagedControlPlane) setDefaultIdentity() {
+	if r.Spec.Identity != nil && r.Spec.Identity.Type == "" {
+		r.Spec.Identity.Type = ManagedControlPlaneIdentityTypeSystemAssigned
+	}
+}
@@ -31,6 +31,59 @@ const (
 	PrivateDNSZoneModeNone string = "None"
 )
 
+const (
+	// AdoptAnnotation is the annotation of an AzureManagedControlPlane which confirms that CAPZ may take ownership of
+	// an existing AKS cluster that it did not create. The annotation value must be "true".
+	AdoptAnnotation = "azuremanagedcontrolplane.infrastructure.cluster.x-k8s.io/adopt"
+
+	// WindowsAdminPasswordHashAnnotation is the hash of the Windows administrator password last applied to the AKS
+	// cluster of an AzureManagedControlPlane. AKS never returns the password, so a rotation of the password is detected
+	// by comparing its hash with this annotation.
+	WindowsAdminPasswordHashAnnotation = "azuremanagedcontrolplane.infrastructure.cluster.x-k8s.io/windows-admin-password-hash"
+)
+
+const (
+	// AzurePolicyAddonName is the name of the Azure Policy add-on.
+	AzurePolicyAddonName = "azurepolicy"
+
+	// HTTPApplicationRoutingAddonName is the name of the HTTP application routing add-on.
+	HTTPApplicationRoutingAddonName = "httpApplicationRouting"
+
+	// KeyVaultSecretsProviderAddonName is the name of the Azure Key Vault Provider for Secrets Store CSI Driver add-on.
+	KeyVaultSecretsProviderAddonName = "azureKeyvaultSecretsProvider"
+
+	// MonitoringAddonName is the name of the Azure Monitor Container insights add-on.
+	MonitoringAddonName = "omsagent"
+)
+
+// ManagedControlPlaneOutboundType is the outbound (egress) routing method of an AKS cluster.
+// +kubebuilder:validation:Enum=loadBalancer;managedNATGateway;userAssignedNATGateway;userDefinedRouting
+type ManagedControlPlaneOutboundType string
+
+const (
+	// ManagedControlPlaneOutboundTypeLoadBalancer routes egress traffic through the cluster load balancer.
+	ManagedControlPlaneOutboundTypeLoadBalancer ManagedControlPlaneOutboundType = "loadBalancer"
+	// ManagedControlPlaneOutboundTypeManagedNATGateway routes egress traffic through a NAT gateway managed by AKS.
+	ManagedControlPlaneOutboundTypeManagedNATGateway ManagedControlPlaneOutboundType = "managedNATGateway"
+	// ManagedControlPlaneOutboundTypeUserAssignedNATGateway routes egress traffic through a NAT gateway associated
+	// with the cluster subnet by the user.
+	ManagedControlPlaneOutboundTypeUserAssignedNATGateway ManagedControlPlaneOutboundType = "userAssignedNATGateway"
+	// ManagedControlPlaneOutboundTypeUserDefinedRouting routes egress traffic through the routes of the route table
+	// associated with the cluster subnet, for example to a firewall.
+	ManagedControlPlaneOutboundTypeUserDefinedRouting ManagedControlPlaneOutboundType = "userDefinedRouting"
+)
+
+// ManagedControlPlaneIdentityType is the type of the identity of an AKS cluster control plane.
+// +kubebuilder:validation:Enum=SystemAssigned;UserAssigned
+type ManagedControlPlaneIdentityType string
+
+const (
+	// ManagedControlPlaneIdentityTypeSystemAssigned uses an identity created and managed by AKS for the control plane.
+	ManagedControlPlaneIdentityTypeSystemAssigned ManagedControlPlaneIdentityType = "SystemAssigned"
+	// ManagedControlPlaneIdentityTypeUserAssigned uses a user-assigned identity for the control plane.
+	ManagedControlPlaneIdentityTypeUserAssigned ManagedControlPlaneIdentityType = "UserAssigned"
+)
+
 // AzureManagedControlPlaneSpec defines the desired state of AzureManagedControlPlane.
 type AzureManagedControlPlaneSpec struct {
 	// Version defines the desired Kubernetes version.
@@ -79,6 +132,16 @@ type AzureManagedControlPlaneSpec struct {
 	// SSHPublicKey is a string literal containing an ssh public key base64 encoded.
 	SSHPublicKey string `json:"sshPublicKey"`
 
+	// PodCIDR is the CIDR block for IP addresses distributed to pods when the kubenet network plugin is used.
+	// It takes precedence over the pod CIDR blocks of the Cluster's clusterNetwork.
+	// +optional
+	PodCIDR *string `json:"podCIDR,omitempty"`
+
+	// ServiceCIDR is the CIDR block for IP addresses distributed to services. It must not overlap with any subnet
+	// IP ranges. It takes precedence over the service CIDR blocks of the Cluster's clusterNetwork.
+	// +optional
+	ServiceCIDR *string `json:"serviceCIDR,omitempty"`
+
 	// DNSServiceIP is an IP address assigned to the Kubernetes DNS service.
 	// It must be within the Kubernetes service address range specified in serviceCidr.
 	// +optional
@@ -89,10 +152,19 @@ type AzureManagedControlPlaneSpec struct {
 	// +optional
 	LoadBalancerSKU *string `json:"loadBalancerSKU,omitempty"`
 
+	// OutboundType is the outbound (egress) routing method of the cluster. Defaults to loadBalancer.
+	// +optional
+	OutboundType *ManagedControlPlaneOutboundType `json:"outboundType,omitempty"`
+
 	// IdentityRef is a reference to a AzureClusterIdentity to be used when reconciling this cluster
 	// +optional
 	IdentityRef *corev1.ObjectReference `json:"identityRef,omitempty"`
 
+	// Identity is the identity of the AKS cluster control plane and of its kubelets. Defaults to a system-assigned
+	// control plane identity.
+	// +optional
+	Identity *ManagedControlPlaneIdentity `json:"identity,omitempty"`
+
 	// AadProfile is Azure Active Directory configuration to integrate with AKS for aad authentication.
 	// +optional
 	AADProfile *AADProfile `json:"aadProfile,omitempty"`
@@ -108,6 +180,236 @@ type AzureManagedControlPlaneSpec struct {
 	// APIServerAccessProfile is the access profile for AKS API server.
 	// +optional
 	APIServerAccessProfile *APIServerAccessProfile `json:"apiServerAccessProfile,omitempty"`
+
+	// WindowsProfile is the profile of the Windows nodes of the AKS cluster. It is required to add Windows node pools
+	// and cannot be removed once set.
+	// +optional
+	WindowsProfile *ManagedControlPlaneWindowsProfile `json:"windowsProfile,omitempty"`
+
+	// AddonProfiles are the profiles of managed cluster add-ons. Add-ons that have a dedicated field, such as
+	// EnableAzurePolicy, must be configured with that field instead. Add-ons that are not listed are left unchanged.
+	// +optional
+	AddonProfiles []AddonProfile `json:"addonProfiles,omitempty"`
+
+	// EnableAzurePolicy enables or disables the Azure Policy add-on.
+	// +optional
+	EnableAzurePolicy *bool `json:"enableAzurePolicy,omitempty"`
+
+	// EnableHTTPApplicationRouting enables or disables the HTTP application routing add-on.
+	// +optional
+	EnableHTTPApplicationRouting *bool `json:"enableHTTPApplicationRouting,omitempty"`
+
+	// KeyVaultSecretsProvider is the profile of the Azure Key Vault Provider for Secrets Store CSI Driver add-on.
+	// +optional
+	KeyVaultSecretsProvider *KeyVaultSecretsProviderProfile `json:"keyVaultSecretsProvider,omitempty"`
+
+	// Monitoring is the profile of the Azure Monitor Container insights add-on.
+	// +optional
+	Monitoring *MonitoringProfile `json:"monitoring,omitempty"`
+
+	// AutoScalerProfile is the parameters to be applied to the cluster autoscaler of the node pools that have
+	// autoscaling enabled.
+	// +optional
+	AutoScalerProfile *AutoScalerProfile `json:"autoScalerProfile,omitempty"`
+
+	// AutoUpgradeProfile is the auto-upgrade configuration of the AKS cluster.
+	// +optional
+	AutoUpgradeProfile *AutoUpgradeProfile `json:"autoUpgradeProfile,omitempty"`
+}
+
+// ManagedControlPlaneIdentity - identities of an AKS cluster control plane and of its kubelets.
+type ManagedControlPlaneIdentity struct {
+	// Type - The type of the identity of the control plane. Defaults to SystemAssigned.
+	// +optional
+	Type ManagedControlPlaneIdentityType `json:"type,omitempty"`
+
+	// UserAssignedIdentityResourceID - The resource ID of the user-assigned identity of the control plane. Required
+	// when Type is UserAssigned. The identity is granted the Network Contributor role on the subnets of the cluster.
+	// +optional
+	UserAssignedIdentityResourceID string `json:"userAssignedIdentityResourceID,omitempty"`
+
+	// KubeletIdentityResourceID - The resource ID of the user-assigned identity of the kubelets, which they use to pull
+	// images and to access Azure resources. Requires a user-assigned control plane identity, which is granted the
+	// Managed Identity Operator role on it. Defaults to an identity created by AKS in the node resource group.
+	// Immutable.
+	// +optional
+	KubeletIdentityResourceID string `json:"kubeletIdentityResourceID,omitempty"`
+
+	// ContainerRegistryResourceIDs - The resource IDs of the container registries on which the kubelet identity is
+	// granted the AcrPull role. Requires a kubelet identity.
+	// +optional
+	ContainerRegistryResourceIDs []string `json:"containerRegistryResourceIDs,omitempty"`
+}
+
+// Expander is the cluster autoscaler expander to use when scaling up.
+// +kubebuilder:validation:Enum=least-waste;most-pods;priority;random
+type Expander string
+
+const (
+	// ExpanderLeastWaste selects the node group that will have the least idle CPU and memory after scaling up.
+	ExpanderLeastWaste Expander = "least-waste"
+	// ExpanderMostPods selects the node group that would be able to schedule the most pods when scaling up.
+	ExpanderMostPods Expander = "most-pods"
+	// ExpanderPriority selects the node group that has the highest priority assigned by the user.
+	ExpanderPriority Expander = "priority"
+	// ExpanderRandom selects a node group at random.
+	ExpanderRandom Expander = "random"
+)
+
+// AutoScalerProfile - parameters of the cluster autoscaler. Durations are expressed as a number followed by a unit,
+// for example 10m. See https://docs.microsoft.com/en-us/azure/aks/cluster-autoscaler#using-the-autoscaler-profile
+// for the default values.
+type AutoScalerProfile struct {
+	// BalanceSimilarNodeGroups - Whether to balance the number of nodes between similar node pools.
+	// +optional
+	BalanceSimilarNodeGroups *bool `json:"balanceSimilarNodeGroups,omitempty"`
+
+	// Expander - The expander to use when scaling up.
+	// +optional
+	Expander *Expander `json:"expander,omitempty"`
+
+	// MaxEmptyBulkDelete - The maximum number of empty nodes that can be deleted at the same time.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxEmptyBulkDelete *int32 `json:"maxEmptyBulkDelete,omitempty"`
+
+	// MaxGracefulTerminationSec - The maximum number of seconds the autoscaler waits for pod termination when
+	// scaling down a node.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxGracefulTerminationSec *int32 `json:"maxGracefulTerminationSec,omitempty"`
+
+	// MaxNodeProvisionTime - The maximum time the autoscaler waits for a node to be provisioned.
+	// +optional
+	MaxNodeProvisionTime *string `json:"maxNodeProvisionTime,omitempty"`
+
+	// MaxTotalUnreadyPercentage - The maximum percentage of unready nodes in the cluster, after which the autoscaler
+	// halts operations.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxTotalUnreadyPercentage *int32 `json:"maxTotalUnreadyPercentage,omitempty"`
+
+	// NewPodScaleUpDelay - The time to ignore unscheduled pods for after they are created.
+	// +optional
+	NewPodScaleUpDelay *string `json:"newPodScaleUpDelay,omitempty"`
+
+	// OkTotalUnreadyCount - The number of allowed unready nodes, irrespective of MaxTotalUnreadyPercentage.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	OkTotalUnreadyCount *int32 `json:"okTotalUnreadyCount,omitempty"`
+
+	// ScanInterval - How often the cluster is reevaluated for scale up or down.
+	// +optional
+	ScanInterval *string `json:"scanInterval,omitempty"`
+
+	// ScaleDownDelayAfterAdd - How long after scale up that scale down evaluation resumes.
+	// +optional
+	ScaleDownDelayAfterAdd *string `json:"scaleDownDelayAfterAdd,omitempty"`
+
+	// ScaleDownDelayAfterDelete - How long after node deletion that scale down evaluation resumes.
+	// +optional
+	ScaleDownDelayAfterDelete *string `json:"scaleDownDelayAfterDelete,omitempty"`
+
+	// ScaleDownDelayAfterFailure - How long after scale down failure that scale down evaluation resumes.
+	// +optional
+	ScaleDownDelayAfterFailure *string `json:"scaleDownDelayAfterFailure,omitempty"`
+
+	// ScaleDownUnneededTime - How long a node should be unneeded before it is eligible for scale down.
+	// +optional
+	ScaleDownUnneededTime *string `json:"scaleDownUnneededTime,omitempty"`
+
+	// ScaleDownUnreadyTime - How long an unready node should be unneeded before it is eligible for scale down.
+	// +optional
+	ScaleDownUnreadyTime *string `json:"scaleDownUnreadyTime,omitempty"`
+
+	// ScaleDownUtilizationThreshold - The ratio of requested resources to node capacity below which a node is
+	// considered for scale down, for example 0.5.
+	// +optional
+	ScaleDownUtilizationThreshold *string `json:"scaleDownUtilizationThreshold,omitempty"`
+
+	// SkipNodesWithLocalStorage - Whether the autoscaler skips deleting nodes with pods using local storage.
+	// +optional
+	SkipNodesWithLocalStorage *bool `json:"skipNodesWithLocalStorage,omitempty"`
+
+	// SkipNodesWithSystemPods - Whether the autoscaler skips deleting nodes with pods from kube-system, other than
+	// DaemonSet pods.
+	// +optional
+	SkipNodesWithSystemPods *bool `json:"skipNodesWithSystemPods,omitempty"`
+}
+
+// UpgradeChannel is the channel used to automatically upgrade an AKS cluster.
+// +kubebuilder:validation:Enum=rapid;stable;patch;node-image;none
+type UpgradeChannel string
+
+const (
+	// UpgradeChannelRapid upgrades the cluster to the latest supported patch release of the latest supported minor version.
+	UpgradeChannelRapid UpgradeChannel = "rapid"
+	// UpgradeChannelStable upgrades the cluster to the latest supported patch release of the minor version N-1, where N is the latest supported minor version.
+	UpgradeChannelStable UpgradeChannel = "stable"
+	// UpgradeChannelPatch upgrades the cluster to the latest supported patch release of its current minor version.
+	UpgradeChannelPatch UpgradeChannel = "patch"
+	// UpgradeChannelNodeImage upgrades the node image of the cluster to the latest version available.
+	UpgradeChannelNodeImage UpgradeChannel = "node-image"
+	// UpgradeChannelNone disables auto-upgrades.
+	UpgradeChannelNone UpgradeChannel = "none"
+)
+
+// UpgradesKubernetesVersion returns whether the channel upgrades the Kubernetes version of the cluster, and not only
+// the node image of its nodes.
+func (c UpgradeChannel) UpgradesKubernetesVersion() bool {
+	switch c {
+	case UpgradeChannelRapid, UpgradeChannelStable, UpgradeChannelPatch:
+		return true
+	default:
+		return false
+	}
+}
+
+// AutoUpgradeProfile - auto-upgrade configuration of an AKS cluster.
+type AutoUpgradeProfile struct {
+	// UpgradeChannel - The channel used to automatically upgrade the cluster.
+	UpgradeChannel UpgradeChannel `json:"upgradeChannel"`
+}
+
+// AddonProfile - profile of a managed cluster add-on.
+type AddonProfile struct {
+	// Name - The name of the managed cluster add-on.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Enabled - Whether the add-on is enabled or not.
+	Enabled bool `json:"enabled"`
+
+	// Config - Key-value pairs for configuring the add-on.
+	// +optional
+	Config map[string]string `json:"config,omitempty"`
+}
+
+// KeyVaultSecretsProviderProfile - profile of the Azure Key Vault Provider for Secrets Store CSI Driver add-on.
+type KeyVaultSecretsProviderProfile struct {
+	// Enabled - Whether the add-on is enabled or not.
+	Enabled bool `json:"enabled"`
+
+	// EnableSecretRotation - Whether to periodically update the secrets mounted from Azure Key Vault.
+	// +optional
+	EnableSecretRotation *bool `json:"enableSecretRotation,omitempty"`
+
+	// RotationPollInterval - The interval at which the mounted secrets are updated when EnableSecretRotation is true,
+	// for example 2m.
+	// +optional
+	RotationPollInterval *string `json:"rotationPollInterval,omitempty"`
+}
+
+// MonitoringProfile - profile of the Azure Monitor Container insights add-on.
+type MonitoringProfile struct {
+	// Enabled - Whether the add-on is enabled or not.
+	Enabled bool `json:"enabled"`
+
+	// LogAnalyticsWorkspaceResourceID - The resource ID of the Log Analytics workspace to send monitoring data to.
+	// AKS creates a default workspace if it is not set.
+	// +optional
+	LogAnalyticsWorkspaceResourceID *string `json:"logAnalyticsWorkspaceResourceID,omitempty"`
 }
 
 // AADProfile - AAD integration managed by AKS.
@@ -183,12 +485,35 @@ type APIServerAccessProfile struct {
 	EnablePrivateClusterPublicFQDN *bool `json:"enablePrivateClusterPublicFQDN,omitempty"`
 }
 
+// ManagedControlPlaneWindowsProfile - profile of the Windows nodes of an AKS cluster.
+type ManagedControlPlaneWindowsProfile struct {
+	// AdminUsername is the name of the administrator account of the Windows nodes.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=20
+	AdminUsername string `json:"adminUsername"`
+
+	// AdminPasswordSecretRef is a reference to the key of a Secret, in the namespace of the AzureManagedControlPlane,
+	// holding the password of the administrator account of the Windows nodes.
+	AdminPasswordSecretRef corev1.SecretKeySelector `json:"adminPasswordSecretRef"`
+}
+
 // ManagedControlPlaneVirtualNetwork describes a virtual network required to provision AKS clusters.
 type ManagedControlPlaneVirtualNetwork struct {
 	Name      string `json:"name"`
 	CIDRBlock string `json:"cidrBlock"`
+
+	// ResourceGroup is the name of the resource group of the virtual network. Defaults to the resource group of the
+	// AKS cluster. A virtual network that already exists and is not owned by the cluster is left unmanaged. Its
+	// subnets must then already exist, and it is not deleted with the cluster.
+	// +optional
+	ResourceGroup string `json:"resourceGroup,omitempty"`
+
 	// +optional
 	Subnet ManagedControlPlaneSubnet `json:"subnet,omitempty"`
+
+	// Subnets are additional subnets of the virtual network, which agent pools can use for their nodes or pods.
+	// +optional
+	Subnets []ManagedControlPlaneSubnet `json:"subnets,omitempty"`
 }
 
 // ManagedControlPlaneSubnet describes a subnet for an AKS cluster.
@@ -209,6 +534,11 @@ type AzureManagedControlPlaneStatus struct {
 	// +optional
 	Initialized bool `json:"initialized,omitempty"`
 
+	// Version is the Kubernetes version of the control plane as reported by AKS once its last operation succeeded.
+	// Agent pools are only upgraded to this version or lower.
+	// +optional
+	Version string `json:"version,omitempty"`
+
 	// Conditions defines current service state of the AzureManagedControlPlane.
 	// +optional
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
@@ -21,8 +21,12 @@ import (
 	"net"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/Azure/go-autorest/autorest/azure"
+	"golang.org/x/mod/semver"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -32,8 +36,19 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
+const (
+	// userAssignedIdentityResourceType is the resource type of user-assigned identities.
+	userAssignedIdentityResourceType = "Microsoft.ManagedIdentity/userAssignedIdentities"
+	// containerRegistryResourceType is the resource type of container registries.
+	containerRegistryResourceType = "Microsoft.ContainerRegistry/registries"
+)
+
 var kubeSemver = regexp.MustCompile(`^v(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)([-0-9a-zA-Z_\.+]*)?$`)
 
+// autoScalerDuration matches the durations AKS accepts in the autoscaler profile, which are a whole number of
+// seconds or minutes.
+var autoScalerDuration = regexp.MustCompile(`^[0-9]+[sm]$`)
+
 // SetupWebhookWithManager sets up and registers the webhook with the manager.
 func (r *AzureManagedControlPlane) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
@@ -73,6 +88,7 @@ func (r *AzureManagedControlPlane) Default() {
 	r.setDefaultVirtualNetwork()
 	r.setDefaultSubnet()
 	r.setDefaultSku()
+	r.setDefaultIdentity()
 }
 
 // +kubebuilder:webhook:verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-azuremanagedcontrolplane,mutating=false,failurePolicy=fail,groups=infrastructure.cluster.x-k8s.io,resources=azuremanagedcontrolplanes,versions=v1beta1,name=validation.azuremanagedcontrolplanes.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
@@ -237,6 +253,30 @@ func (r *AzureManagedControlPlane) ValidateUpdate(oldRaw runtime.Object) error {
 		allErrs = append(allErrs, errs...)
 	}
 
+	if errs := r.validateWindowsProfileUpdate(old); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := r.validateNetworkProfileUpdate(old); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := r.validateVersionUpdate(old); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if errs := r.validateIdentityUpdate(old); len(errs) > 0 {
+		allErrs = append(allErrs, errs...)
+	}
+
+	if old.Spec.VirtualNetwork.ResourceGroup != "" && r.Spec.VirtualNetwork.ResourceGroup != old.Spec.VirtualNetwork.ResourceGroup {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "VirtualNetwork", "ResourceGroup"),
+				r.Spec.VirtualNetwork.ResourceGroup,
+				"field is immutable"))
+	}
+
 	if len(allErrs) == 0 {
 		return r.Validate()
 	}
@@ -254,9 +294,14 @@ func (r *AzureManagedControlPlane) Validate() error {
 	validators := []func() error{
 		r.validateVersion,
 		r.validateDNSServiceIP,
+		r.validateNetworkProfile,
+		r.validateVirtualNetwork,
 		r.validateSSHKey,
 		r.validateLoadBalancerProfile,
 		r.validateAPIServerAccessProfile,
+		r.validateAddonProfiles,
+		r.validateAutoScalerProfile,
+		r.validateIdentity,
 	}
 
 	var errs []error
@@ -280,6 +325,71 @@ func (r *AzureManagedControlPlane) validateDNSServiceIP() error {
 	return nil
 }
 
+// validateNetworkProfile validates the pod and service CIDRs and the outbound type.
+func (r *AzureManagedControlPlane) validateNetworkProfile() error {
+	var allErrs field.ErrorList
+
+	if r.Spec.PodCIDR != nil {
+		if _, _, err := net.ParseCIDR(*r.Spec.PodCIDR); err != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "PodCIDR"), *r.Spec.PodCIDR, "invalid CIDR format"))
+		}
+		// pods get their IP addresses from the subnets of the virtual network with the azure network plugin
+		if r.Spec.NetworkPlugin != nil && *r.Spec.NetworkPlugin == "azure" {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("Spec", "PodCIDR"), "can only be set with the kubenet network plugin"))
+		}
+	}
+
+	if r.Spec.ServiceCIDR != nil {
+		_, serviceCIDR, err := net.ParseCIDR(*r.Spec.ServiceCIDR)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "ServiceCIDR"), *r.Spec.ServiceCIDR, "invalid CIDR format"))
+		} else if r.Spec.DNSServiceIP != nil {
+			if ip := net.ParseIP(*r.Spec.DNSServiceIP); ip != nil && !serviceCIDR.Contains(ip) {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "DNSServiceIP"), *r.Spec.DNSServiceIP, "must reside within ServiceCIDR"))
+			}
+		}
+	}
+
+	if r.Spec.OutboundType != nil && *r.Spec.OutboundType != ManagedControlPlaneOutboundTypeLoadBalancer {
+		if r.Spec.LoadBalancerProfile != nil {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("Spec", "LoadBalancerProfile"), "can only be set when OutboundType is loadBalancer"))
+		}
+		if r.Spec.LoadBalancerSKU != nil && *r.Spec.LoadBalancerSKU != "Standard" {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "LoadBalancerSKU"), *r.Spec.LoadBalancerSKU, "must be Standard when OutboundType is "+string(*r.Spec.OutboundType)))
+		}
+	}
+
+	if len(allErrs) > 0 {
+		return kerrors.NewAggregate(allErrs.ToAggregate().Errors())
+	}
+	return nil
+}
+
+// validateVirtualNetwork validates that the subnets of the virtual network have unique names and valid CIDR blocks.
+func (r *AzureManagedControlPlane) validateVirtualNetwork() error {
+	var allErrs field.ErrorList
+
+	names := map[string]bool{r.Spec.VirtualNetwork.Subnet.Name: true}
+	for i, subnet := range r.Spec.VirtualNetwork.Subnets {
+		path := field.NewPath("Spec", "VirtualNetwork", "Subnets").Index(i)
+		if subnet.Name == "" {
+			allErrs = append(allErrs, field.Required(path.Child("Name"), "subnet name is required"))
+		} else if names[subnet.Name] {
+			allErrs = append(allErrs, field.Duplicate(path.Child("Name"), subnet.Name))
+		}
+		names[subnet.Name] = true
+
+		if _, _, err := net.ParseCIDR(subnet.CIDRBlock); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("CIDRBlock"), subnet.CIDRBlock, "invalid CIDR format"))
+		}
+	}
+
+	if len(allErrs) > 0 {
+		return kerrors.NewAggregate(allErrs.ToAggregate().Errors())
+	}
+	return nil
+}
+
 func (r *AzureManagedControlPlane) validateVersion() error {
 	if !kubeSemver.MatchString(r.Spec.Version) {
 		return errors.New("must be a valid semantic version")
@@ -365,6 +475,139 @@ func (r *AzureManagedControlPlane) validateAPIServerAccessProfile() error {
 	return nil
 }
 
+// validateAddonProfiles validates the add-on profiles, including the add-ons that have a dedicated field.
+func (r *AzureManagedControlPlane) validateAddonProfiles() error {
+	var allErrs field.ErrorList
+
+	dedicatedAddons := map[string]string{
+		strings.ToLower(AzurePolicyAddonName):             "EnableAzurePolicy",
+		strings.ToLower(HTTPApplicationRoutingAddonName):  "EnableHTTPApplicationRouting",
+		strings.ToLower(KeyVaultSecretsProviderAddonName): "KeyVaultSecretsProvider",
+		strings.ToLower(MonitoringAddonName):              "Monitoring",
+	}
+	names := make(map[string]bool, len(r.Spec.AddonProfiles))
+	for i, addon := range r.Spec.AddonProfiles {
+		namePath := field.NewPath("Spec", "AddonProfiles").Index(i).Child("Name")
+		name := strings.ToLower(addon.Name)
+		if fieldName, ok := dedicatedAddons[name]; ok {
+			allErrs = append(allErrs, field.Invalid(namePath, addon.Name, "add-on must be configured with the "+fieldName+" field"))
+		}
+		if names[name] {
+			allErrs = append(allErrs, field.Duplicate(namePath, addon.Name))
+		}
+		names[name] = true
+	}
+
+	if kv := r.Spec.KeyVaultSecretsProvider; kv != nil && kv.RotationPollInterval != nil {
+		if _, err := time.ParseDuration(*kv.RotationPollInterval); err != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "KeyVaultSecretsProvider", "RotationPollInterval"), *kv.RotationPollInterval, "must be a valid duration, for example 2m"))
+		}
+	}
+
+	if monitoring := r.Spec.Monitoring; monitoring != nil && monitoring.LogAnalyticsWorkspaceResourceID != nil {
+		if _, err := azure.ParseResourceID(*monitoring.LogAnalyticsWorkspaceResourceID); err != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "Monitoring", "LogAnalyticsWorkspaceResourceID"), *monitoring.LogAnalyticsWorkspaceResourceID, "must be a valid Azure resource ID"))
+		}
+	}
+
+	if len(allErrs) > 0 {
+		return kerrors.NewAggregate(allErrs.ToAggregate().Errors())
+	}
+	return nil
+}
+
+// validateAutoScalerProfile validates the durations and the scale down utilization threshold of the cluster
+// autoscaler profile.
+func (r *AzureManagedControlPlane) validateAutoScalerProfile() error {
+	profile := r.Spec.AutoScalerProfile
+	if profile == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	durations := []struct {
+		name  string
+		value *string
+	}{
+		{"MaxNodeProvisionTime", profile.MaxNodeProvisionTime},
+		{"NewPodScaleUpDelay", profile.NewPodScaleUpDelay},
+		{"ScanInterval", profile.ScanInterval},
+		{"ScaleDownDelayAfterAdd", profile.ScaleDownDelayAfterAdd},
+		{"ScaleDownDelayAfterDelete", profile.ScaleDownDelayAfterDelete},
+		{"ScaleDownDelayAfterFailure", profile.ScaleDownDelayAfterFailure},
+		{"ScaleDownUnneededTime", profile.ScaleDownUnneededTime},
+		{"ScaleDownUnreadyTime", profile.ScaleDownUnreadyTime},
+	}
+	for _, duration := range durations {
+		if duration.value == nil {
+			continue
+		}
+		if !autoScalerDuration.MatchString(*duration.value) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "AutoScalerProfile", duration.name), *duration.value, "must be a whole number of seconds or minutes, for example 10s or 10m"))
+		}
+	}
+
+	if profile.ScaleDownUtilizationThreshold != nil {
+		if threshold, err := strconv.ParseFloat(*profile.ScaleDownUtilizationThreshold, 64); err != nil || threshold <= 0 || threshold > 1 {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("Spec", "AutoScalerProfile", "ScaleDownUtilizationThreshold"), *profile.ScaleDownUtilizationThreshold, "must be a number greater than 0 and less than or equal to 1"))
+		}
+	}
+
+	if len(allErrs) > 0 {
+		return kerrors.NewAggregate(allErrs.ToAggregate().Errors())
+	}
+	return nil
+}
+
+// validateIdentity validates that the user-assigned identities and the container registries are valid resource IDs,
+// and that a kubelet identity is only set together with a user-assigned control plane identity.
+func (r *AzureManagedControlPlane) validateIdentity() error {
+	identity := r.Spec.Identity
+	if identity == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	path := field.NewPath("Spec", "Identity")
+
+	validateResourceID := func(path *field.Path, resourceID, resourceType string) {
+		parsed, err := azure.ParseResourceID(resourceID)
+		if err != nil || !strings.EqualFold(parsed.Provider+"/"+parsed.ResourceType, resourceType) {
+			allErrs = append(allErrs, field.Invalid(path, resourceID, "must be a valid "+resourceType+" resource ID"))
+		}
+	}
+
+	if identity.Type == ManagedControlPlaneIdentityTypeUserAssigned {
+		if identity.UserAssignedIdentityResourceID == "" {
+			allErrs = append(allErrs, field.Required(path.Child("UserAssignedIdentityResourceID"), "must be set when Type is UserAssigned"))
+		} else {
+			validateResourceID(path.Child("UserAssignedIdentityResourceID"), identity.UserAssignedIdentityResourceID, userAssignedIdentityResourceType)
+		}
+	} else {
+		if identity.UserAssignedIdentityResourceID != "" {
+			allErrs = append(allErrs, field.Forbidden(path.Child("UserAssignedIdentityResourceID"), "can only be set when Type is UserAssigned"))
+		}
+		if identity.KubeletIdentityResourceID != "" {
+			allErrs = append(allErrs, field.Forbidden(path.Child("KubeletIdentityResourceID"), "can only be set when Type is UserAssigned"))
+		}
+	}
+
+	if identity.KubeletIdentityResourceID != "" {
+		validateResourceID(path.Child("KubeletIdentityResourceID"), identity.KubeletIdentityResourceID, userAssignedIdentityResourceType)
+	} else if len(identity.ContainerRegistryResourceIDs) > 0 {
+		allErrs = append(allErrs, field.Forbidden(path.Child("ContainerRegistryResourceIDs"), "can only be set together with KubeletIdentityResourceID"))
+	}
+
+	for i, registryID := range identity.ContainerRegistryResourceIDs {
+		validateResourceID(path.Child("ContainerRegistryResourceIDs").Index(i), registryID, containerRegistryResourceType)
+	}
+
+	if len(allErrs) > 0 {
+		return kerrors.NewAggregate(allErrs.ToAggregate().Errors())
+	}
+	return nil
+}
+
 // validateAPIServerAccessProfileUpdate validates update to APIServerAccessProfile.
 func (r *AzureManagedControlPlane) validateAPIServerAccessProfileUpdate(old *AzureManagedControlPlane) field.ErrorList {
 	var allErrs field.ErrorList
@@ -395,3 +638,156 @@ func (r *AzureManagedControlPlane) validateAPIServerAccessProfileUpdate(old *Azu
 
 	return allErrs
 }
+
+// validateWindowsProfileUpdate validates update to WindowsProfile.
+func (r *AzureManagedControlPlane) validateWindowsProfileUpdate(old *AzureManagedControlPlane) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if old.Spec.WindowsProfile == nil {
+		return allErrs
+	}
+
+	if r.Spec.WindowsProfile == nil {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "WindowsProfile"),
+				r.Spec.WindowsProfile,
+				"field cannot be nil, cannot remove WindowsProfile"))
+	} else if r.Spec.WindowsProfile.AdminUsername != old.Spec.WindowsProfile.AdminUsername {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "WindowsProfile", "AdminUsername"),
+				r.Spec.WindowsProfile.AdminUsername,
+				"field is immutable"))
+	}
+
+	return allErrs
+}
+
+// validateVersionUpdate validates that the Kubernetes version is neither downgraded nor upgraded by more than one
+// minor version at a time, as AKS supports neither.
+func (r *AzureManagedControlPlane) validateVersionUpdate(old *AzureManagedControlPlane) field.ErrorList {
+	var allErrs field.ErrorList
+
+	oldVersion, newVersion := normalizeVersion(old.Spec.Version), normalizeVersion(r.Spec.Version)
+	if !semver.IsValid(oldVersion) || !semver.IsValid(newVersion) {
+		// Invalid versions are reported by validateVersion.
+		return allErrs
+	}
+
+	if semver.Compare(newVersion, oldVersion) < 0 {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "Version"),
+				r.Spec.Version,
+				"field cannot be downgraded"))
+	} else if minorVersion(newVersion) > minorVersion(oldVersion)+1 && semver.Major(newVersion) == semver.Major(oldVersion) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "Version"),
+				r.Spec.Version,
+				"field cannot be upgraded by more than one minor version at a time"))
+	}
+
+	return allErrs
+}
+
+// normalizeVersion returns the version prefixed with "v", as expected by the semver package.
+func normalizeVersion(version string) string {
+	if version != "" && !strings.HasPrefix(version, "v") {
+		return "v" + version
+	}
+	return version
+}
+
+// minorVersion returns the minor version of a valid semantic version.
+func minorVersion(version string) int {
+	minor, _ := strconv.Atoi(strings.TrimPrefix(semver.MajorMinor(version), semver.Major(version)+"."))
+	return minor
+}
+
+// validateNetworkProfileUpdate validates that the pod and service CIDRs and the outbound type are neither changed, set
+// nor unset after the creation of the control plane, as AKS does not support updating them.
+func (r *AzureManagedControlPlane) validateNetworkProfileUpdate(old *AzureManagedControlPlane) field.ErrorList {
+	var allErrs field.ErrorList
+
+	// AKS defaults the fields left unset at creation, so setting them afterwards would change them as well
+	if !reflect.DeepEqual(r.Spec.PodCIDR, old.Spec.PodCIDR) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "PodCIDR"),
+				r.Spec.PodCIDR,
+				"field is immutable"))
+	}
+
+	if !reflect.DeepEqual(r.Spec.ServiceCIDR, old.Spec.ServiceCIDR) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "ServiceCIDR"),
+				r.Spec.ServiceCIDR,
+				"field is immutable"))
+	}
+
+	if !reflect.DeepEqual(r.Spec.OutboundType, old.Spec.OutboundType) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "OutboundType"),
+				r.Spec.OutboundType,
+				"field is immutable"))
+	}
+
+	return allErrs
+}
+
+// validateIdentityUpdate validates that the identities are not changed, as AKS does not support updating them, except
+// for the migration of the control plane from a system-assigned to a user-assigned identity.
+func (r *AzureManagedControlPlane) validateIdentityUpdate(old *AzureManagedControlPlane) field.ErrorList {
+	var allErrs field.ErrorList
+
+	identityType := func(controlPlane *AzureManagedControlPlane) ManagedControlPlaneIdentityType {
+		if controlPlane.Spec.Identity == nil || controlPlane.Spec.Identity.Type == "" {
+			return ManagedControlPlaneIdentityTypeSystemAssigned
+		}
+		return controlPlane.Spec.Identity.Type
+	}
+	userAssignedIdentity := func(controlPlane *AzureManagedControlPlane) string {
+		if controlPlane.Spec.Identity == nil {
+			return ""
+		}
+		return controlPlane.Spec.Identity.UserAssignedIdentityResourceID
+	}
+
+	switch oldType, newType := identityType(old), identityType(r); {
+	case oldType == ManagedControlPlaneIdentityTypeSystemAssigned && newType == ManagedControlPlaneIdentityTypeUserAssigned:
+		// AKS migrates the control plane to the user-assigned identity.
+	case oldType != newType:
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "Identity", "Type"),
+				newType,
+				"field is immutable, except from SystemAssigned to UserAssigned"))
+	case !strings.EqualFold(userAssignedIdentity(r), userAssignedIdentity(old)):
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "Identity", "UserAssignedIdentityResourceID"),
+				userAssignedIdentity(r),
+				"field is immutable"))
+	}
+
+	kubeletIdentity := func(controlPlane *AzureManagedControlPlane) string {
+		if controlPlane.Spec.Identity == nil {
+			return ""
+		}
+		return controlPlane.Spec.Identity.KubeletIdentityResourceID
+	}
+
+	if !strings.EqualFold(kubeletIdentity(r), kubeletIdentity(old)) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "Identity", "KubeletIdentityResourceID"),
+				kubeletIdentity(r),
+				"field is immutable"))
+	}
+
+	return allErrs
+}
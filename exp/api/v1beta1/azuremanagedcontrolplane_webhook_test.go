@@ -21,6 +21,7 @@ import (
 
 	"github.com/Azure/go-autorest/autorest/to"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 )
@@ -47,6 +48,7 @@ func TestDefaultingWebhook(t *testing.T) {
 	g.Expect(amcp.Spec.SSHPublicKey).NotTo(BeEmpty())
 	g.Expect(amcp.Spec.NodeResourceGroupName).To(Equal("MC_fooRg_fooName_fooLocation"))
 	g.Expect(amcp.Spec.VirtualNetwork.Name).To(Equal("fooName"))
+	g.Expect(amcp.Spec.VirtualNetwork.ResourceGroup).To(Equal("fooRg"))
 	g.Expect(amcp.Spec.VirtualNetwork.Subnet.Name).To(Equal("fooName"))
 	g.Expect(amcp.Spec.SKU.Tier).To(Equal(FreeManagedControlPlaneTier))
 
@@ -61,6 +63,7 @@ func TestDefaultingWebhook(t *testing.T) {
 	amcp.Spec.SSHPublicKey = ""
 	amcp.Spec.NodeResourceGroupName = "fooNodeRg"
 	amcp.Spec.VirtualNetwork.Name = "fooVnetName"
+	amcp.Spec.VirtualNetwork.ResourceGroup = "fooVnetRg"
 	amcp.Spec.VirtualNetwork.Subnet.Name = "fooSubnetName"
 	amcp.Spec.SKU.Tier = PaidManagedControlPlaneTier
 	amcp.Default()
@@ -71,6 +74,7 @@ func TestDefaultingWebhook(t *testing.T) {
 	g.Expect(amcp.Spec.SSHPublicKey).NotTo(BeEmpty())
 	g.Expect(amcp.Spec.NodeResourceGroupName).To(Equal("fooNodeRg"))
 	g.Expect(amcp.Spec.VirtualNetwork.Name).To(Equal("fooVnetName"))
+	g.Expect(amcp.Spec.VirtualNetwork.ResourceGroup).To(Equal("fooVnetRg"))
 	g.Expect(amcp.Spec.VirtualNetwork.Subnet.Name).To(Equal("fooSubnetName"))
 	g.Expect(amcp.Spec.SKU.Tier).To(Equal(PaidManagedControlPlaneTier))
 }
@@ -233,6 +237,348 @@ func TestValidatingWebhook(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		{
+			name: "Valid add-ons",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					AddonProfiles: []AddonProfile{
+						{
+							Name:    "kubeDashboard",
+							Enabled: false,
+						},
+					},
+					EnableAzurePolicy:            to.BoolPtr(true),
+					EnableHTTPApplicationRouting: to.BoolPtr(false),
+					KeyVaultSecretsProvider: &KeyVaultSecretsProviderProfile{
+						Enabled:              true,
+						EnableSecretRotation: to.BoolPtr(true),
+						RotationPollInterval: to.StringPtr("2m"),
+					},
+					Monitoring: &MonitoringProfile{
+						Enabled:                         true,
+						LogAnalyticsWorkspaceResourceID: to.StringPtr("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/my-rg/providers/Microsoft.OperationalInsights/workspaces/my-workspace"),
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "Duplicate AddonProfiles",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					AddonProfiles: []AddonProfile{
+						{
+							Name:    "kubeDashboard",
+							Enabled: false,
+						},
+						{
+							Name:    "kubedashboard",
+							Enabled: true,
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "AddonProfiles with an add-on that has a dedicated field",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					AddonProfiles: []AddonProfile{
+						{
+							Name:    AzurePolicyAddonName,
+							Enabled: true,
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Invalid KeyVaultSecretsProvider.RotationPollInterval",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					KeyVaultSecretsProvider: &KeyVaultSecretsProviderProfile{
+						Enabled:              true,
+						EnableSecretRotation: to.BoolPtr(true),
+						RotationPollInterval: to.StringPtr("two minutes"),
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Invalid Monitoring.LogAnalyticsWorkspaceResourceID",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					Monitoring: &MonitoringProfile{
+						Enabled:                         true,
+						LogAnalyticsWorkspaceResourceID: to.StringPtr("my-workspace"),
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Valid network profile",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:         "v1.21.2",
+					NetworkPlugin:   to.StringPtr("kubenet"),
+					PodCIDR:         to.StringPtr("10.244.0.0/16"),
+					ServiceCIDR:     to.StringPtr("10.0.0.0/16"),
+					DNSServiceIP:    to.StringPtr("10.0.0.10"),
+					LoadBalancerSKU: to.StringPtr("Standard"),
+					OutboundType:    (*ManagedControlPlaneOutboundType)(to.StringPtr(string(ManagedControlPlaneOutboundTypeUserDefinedRouting))),
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "Invalid PodCIDR",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					PodCIDR: to.StringPtr("10.244.0.0"),
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "PodCIDR with the azure network plugin",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:       "v1.21.2",
+					NetworkPlugin: to.StringPtr("azure"),
+					PodCIDR:       to.StringPtr("10.244.0.0/16"),
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "DNSServiceIP outside of ServiceCIDR",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:      "v1.21.2",
+					ServiceCIDR:  to.StringPtr("10.0.0.0/16"),
+					DNSServiceIP: to.StringPtr("10.1.0.10"),
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "LoadBalancerProfile with the userDefinedRouting OutboundType",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:      "v1.21.2",
+					OutboundType: (*ManagedControlPlaneOutboundType)(to.StringPtr(string(ManagedControlPlaneOutboundTypeUserDefinedRouting))),
+					LoadBalancerProfile: &LoadBalancerProfile{
+						ManagedOutboundIPs: to.Int32Ptr(2),
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Basic LoadBalancerSKU with the userDefinedRouting OutboundType",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version:         "v1.21.2",
+					LoadBalancerSKU: to.StringPtr("Basic"),
+					OutboundType:    (*ManagedControlPlaneOutboundType)(to.StringPtr(string(ManagedControlPlaneOutboundTypeUserDefinedRouting))),
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Valid additional subnets",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					VirtualNetwork: ManagedControlPlaneVirtualNetwork{
+						Subnet: ManagedControlPlaneSubnet{Name: "nodes", CIDRBlock: "10.240.0.0/16"},
+						Subnets: []ManagedControlPlaneSubnet{
+							{Name: "nodes-2", CIDRBlock: "10.241.0.0/16"},
+							{Name: "pods", CIDRBlock: "10.242.0.0/16"},
+						},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "Additional subnet with the name of the subnet",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					VirtualNetwork: ManagedControlPlaneVirtualNetwork{
+						Subnet: ManagedControlPlaneSubnet{Name: "nodes", CIDRBlock: "10.240.0.0/16"},
+						Subnets: []ManagedControlPlaneSubnet{
+							{Name: "nodes", CIDRBlock: "10.241.0.0/16"},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Additional subnet with an invalid CIDRBlock",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					VirtualNetwork: ManagedControlPlaneVirtualNetwork{
+						Subnets: []ManagedControlPlaneSubnet{
+							{Name: "pods", CIDRBlock: "10.242.0.0"},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Valid AutoScalerProfile and AutoUpgradeProfile",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					AutoScalerProfile: &AutoScalerProfile{
+						BalanceSimilarNodeGroups:      to.BoolPtr(true),
+						Expander:                      (*Expander)(to.StringPtr(string(ExpanderLeastWaste))),
+						MaxEmptyBulkDelete:            to.Int32Ptr(10),
+						ScanInterval:                  to.StringPtr("20s"),
+						ScaleDownUnneededTime:         to.StringPtr("5m"),
+						ScaleDownUtilizationThreshold: to.StringPtr("0.6"),
+					},
+					AutoUpgradeProfile: &AutoUpgradeProfile{
+						UpgradeChannel: UpgradeChannelStable,
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "Invalid AutoScalerProfile.ScaleDownDelayAfterAdd",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					AutoScalerProfile: &AutoScalerProfile{
+						ScaleDownDelayAfterAdd: to.StringPtr("ten minutes"),
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Invalid AutoScalerProfile.ScaleDownUnneededTime with a compound duration",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					AutoScalerProfile: &AutoScalerProfile{
+						ScaleDownUnneededTime: to.StringPtr("1h30m"),
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Invalid AutoScalerProfile.ScaleDownUnneededTime with a fractional duration",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					AutoScalerProfile: &AutoScalerProfile{
+						ScaleDownUnneededTime: to.StringPtr("1.5m"),
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Invalid AutoScalerProfile.ScaleDownUtilizationThreshold",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					AutoScalerProfile: &AutoScalerProfile{
+						ScaleDownUtilizationThreshold: to.StringPtr("1.5"),
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Valid user-assigned control plane and kubelet identities",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					Identity: &ManagedControlPlaneIdentity{
+						Type:                           ManagedControlPlaneIdentityTypeUserAssigned,
+						UserAssignedIdentityResourceID: "/subscriptions/123/resourceGroups/identities-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/control-plane",
+						KubeletIdentityResourceID:      "/subscriptions/123/resourceGroups/identities-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/kubelet",
+						ContainerRegistryResourceIDs: []string{
+							"/subscriptions/123/resourceGroups/registries-rg/providers/Microsoft.ContainerRegistry/registries/myregistry",
+						},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "Invalid user-assigned control plane identity without resource ID",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					Identity: &ManagedControlPlaneIdentity{
+						Type: ManagedControlPlaneIdentityTypeUserAssigned,
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Invalid user-assigned control plane identity resource type",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					Identity: &ManagedControlPlaneIdentity{
+						Type:                           ManagedControlPlaneIdentityTypeUserAssigned,
+						UserAssignedIdentityResourceID: "/subscriptions/123/resourceGroups/identities-rg/providers/Microsoft.Compute/virtualMachines/control-plane",
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Invalid kubelet identity with a system-assigned control plane identity",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					Identity: &ManagedControlPlaneIdentity{
+						Type:                      ManagedControlPlaneIdentityTypeSystemAssigned,
+						KubeletIdentityResourceID: "/subscriptions/123/resourceGroups/identities-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/kubelet",
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Invalid container registries without kubelet identity",
+			amcp: AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.21.2",
+					Identity: &ManagedControlPlaneIdentity{
+						Type:                           ManagedControlPlaneIdentityTypeUserAssigned,
+						UserAssignedIdentityResourceID: "/subscriptions/123/resourceGroups/identities-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/control-plane",
+						ContainerRegistryResourceIDs: []string{
+							"/subscriptions/123/resourceGroups/registries-rg/providers/Microsoft.ContainerRegistry/registries/myregistry",
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -347,6 +693,24 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 			amcp:    createAzureManagedControlPlane("192.168.0.0", "1.999.9", generateSSHPublicKey(true)),
 			wantErr: true,
 		},
+		{
+			name:    "AzureManagedControlPlane with version upgraded by one minor version",
+			oldAMCP: createAzureManagedControlPlane("192.168.0.0", "v1.18.0", ""),
+			amcp:    createAzureManagedControlPlane("192.168.0.0", "v1.19.3", ""),
+			wantErr: false,
+		},
+		{
+			name:    "AzureManagedControlPlane with version upgraded by more than one minor version",
+			oldAMCP: createAzureManagedControlPlane("192.168.0.0", "v1.18.0", ""),
+			amcp:    createAzureManagedControlPlane("192.168.0.0", "v1.20.0", ""),
+			wantErr: true,
+		},
+		{
+			name:    "AzureManagedControlPlane with version downgraded",
+			oldAMCP: createAzureManagedControlPlane("192.168.0.0", "v1.19.3", ""),
+			amcp:    createAzureManagedControlPlane("192.168.0.0", "v1.19.1", ""),
+			wantErr: true,
+		},
 		{
 			name: "AzureManagedControlPlane SubscriptionID is immutable",
 			oldAMCP: &AzureManagedControlPlane{
@@ -468,6 +832,130 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "AzureManagedControlPlane PodCIDR is immutable",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					PodCIDR: to.StringPtr("10.244.0.0/16"),
+					Version: "v1.18.0",
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					PodCIDR: to.StringPtr("10.245.0.0/16"),
+					Version: "v1.18.0",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane ServiceCIDR is immutable, unsetting is not allowed",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					ServiceCIDR: to.StringPtr("10.0.0.0/16"),
+					Version:     "v1.18.0",
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.18.0",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane PodCIDR cannot be set after creation",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.18.0",
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					PodCIDR: to.StringPtr("10.244.0.0/16"),
+					Version: "v1.18.0",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane ServiceCIDR cannot be set after creation",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.18.0",
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					ServiceCIDR: to.StringPtr("10.0.0.0/16"),
+					Version:     "v1.18.0",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane OutboundType cannot be set after creation",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.18.0",
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					OutboundType: (*ManagedControlPlaneOutboundType)(to.StringPtr(string(ManagedControlPlaneOutboundTypeLoadBalancer))),
+					Version:      "v1.18.0",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane OutboundType is immutable",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					OutboundType: (*ManagedControlPlaneOutboundType)(to.StringPtr(string(ManagedControlPlaneOutboundTypeLoadBalancer))),
+					Version:      "v1.18.0",
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					OutboundType: (*ManagedControlPlaneOutboundType)(to.StringPtr(string(ManagedControlPlaneOutboundTypeUserDefinedRouting))),
+					Version:      "v1.18.0",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane OutboundType can be set to its current value",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					OutboundType: (*ManagedControlPlaneOutboundType)(to.StringPtr(string(ManagedControlPlaneOutboundTypeUserDefinedRouting))),
+					Version:      "v1.18.0",
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					OutboundType: (*ManagedControlPlaneOutboundType)(to.StringPtr(string(ManagedControlPlaneOutboundTypeUserDefinedRouting))),
+					Version:      "v1.18.0",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "AzureManagedControlPlane VirtualNetwork.ResourceGroup is immutable",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					VirtualNetwork: ManagedControlPlaneVirtualNetwork{ResourceGroup: "vnet-rg"},
+					Version:        "v1.18.0",
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					VirtualNetwork: ManagedControlPlaneVirtualNetwork{ResourceGroup: "other-vnet-rg"},
+					Version:        "v1.18.0",
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "AzureManagedControlPlane NetworkPlugin is immutable",
 			oldAMCP: &AzureManagedControlPlane{
@@ -723,6 +1211,236 @@ func TestAzureManagedControlPlane_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "AzureManagedControlPlane WindowsProfile can be added",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					Version:      "v1.18.0",
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					Version:      "v1.18.0",
+					WindowsProfile: &ManagedControlPlaneWindowsProfile{
+						AdminUsername: "azureuser",
+						AdminPasswordSecretRef: corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "windows-secret"},
+							Key:                  "password",
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "AzureManagedControlPlane WindowsProfile cannot be removed",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					Version:      "v1.18.0",
+					WindowsProfile: &ManagedControlPlaneWindowsProfile{
+						AdminUsername: "azureuser",
+						AdminPasswordSecretRef: corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "windows-secret"},
+							Key:                  "password",
+						},
+					},
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					Version:      "v1.18.0",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane WindowsProfile.AdminUsername is immutable",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					Version:      "v1.18.0",
+					WindowsProfile: &ManagedControlPlaneWindowsProfile{
+						AdminUsername: "azureuser",
+						AdminPasswordSecretRef: corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "windows-secret"},
+							Key:                  "password",
+						},
+					},
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					Version:      "v1.18.0",
+					WindowsProfile: &ManagedControlPlaneWindowsProfile{
+						AdminUsername: "winadmin",
+						AdminPasswordSecretRef: corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "windows-secret"},
+							Key:                  "password",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane WindowsProfile.AdminPasswordSecretRef is mutable",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					Version:      "v1.18.0",
+					WindowsProfile: &ManagedControlPlaneWindowsProfile{
+						AdminUsername: "azureuser",
+						AdminPasswordSecretRef: corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "windows-secret"},
+							Key:                  "password",
+						},
+					},
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					DNSServiceIP: to.StringPtr("192.168.0.0"),
+					Version:      "v1.18.0",
+					WindowsProfile: &ManagedControlPlaneWindowsProfile{
+						AdminUsername: "azureuser",
+						AdminPasswordSecretRef: corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "rotated-secret"},
+							Key:                  "password",
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "AzureManagedControlPlane Identity.KubeletIdentityResourceID is immutable",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.18.0",
+					Identity: &ManagedControlPlaneIdentity{
+						Type:                           ManagedControlPlaneIdentityTypeUserAssigned,
+						UserAssignedIdentityResourceID: "/subscriptions/123/resourceGroups/identities-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/control-plane",
+					},
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.18.0",
+					Identity: &ManagedControlPlaneIdentity{
+						Type:                           ManagedControlPlaneIdentityTypeUserAssigned,
+						UserAssignedIdentityResourceID: "/subscriptions/123/resourceGroups/identities-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/control-plane",
+						KubeletIdentityResourceID:      "/subscriptions/123/resourceGroups/identities-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/kubelet",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane Identity.Type can change from SystemAssigned to UserAssigned",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.18.0",
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.18.0",
+					Identity: &ManagedControlPlaneIdentity{
+						Type:                           ManagedControlPlaneIdentityTypeUserAssigned,
+						UserAssignedIdentityResourceID: "/subscriptions/123/resourceGroups/identities-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/control-plane",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "AzureManagedControlPlane Identity.Type cannot change from UserAssigned to SystemAssigned",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.18.0",
+					Identity: &ManagedControlPlaneIdentity{
+						Type:                           ManagedControlPlaneIdentityTypeUserAssigned,
+						UserAssignedIdentityResourceID: "/subscriptions/123/resourceGroups/identities-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/control-plane",
+					},
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.18.0",
+					Identity: &ManagedControlPlaneIdentity{
+						Type: ManagedControlPlaneIdentityTypeSystemAssigned,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane Identity.Type cannot be unset from UserAssigned",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.18.0",
+					Identity: &ManagedControlPlaneIdentity{
+						Type:                           ManagedControlPlaneIdentityTypeUserAssigned,
+						UserAssignedIdentityResourceID: "/subscriptions/123/resourceGroups/identities-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/control-plane",
+					},
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.18.0",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane Identity.UserAssignedIdentityResourceID is immutable",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.18.0",
+					Identity: &ManagedControlPlaneIdentity{
+						Type:                           ManagedControlPlaneIdentityTypeUserAssigned,
+						UserAssignedIdentityResourceID: "/subscriptions/123/resourceGroups/identities-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/control-plane",
+					},
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.18.0",
+					Identity: &ManagedControlPlaneIdentity{
+						Type:                           ManagedControlPlaneIdentityTypeUserAssigned,
+						UserAssignedIdentityResourceID: "/subscriptions/123/resourceGroups/identities-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/other-control-plane",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "AzureManagedControlPlane Identity.UserAssignedIdentityResourceID is case insensitive",
+			oldAMCP: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.18.0",
+					Identity: &ManagedControlPlaneIdentity{
+						Type:                           ManagedControlPlaneIdentityTypeUserAssigned,
+						UserAssignedIdentityResourceID: "/subscriptions/123/resourceGroups/identities-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/control-plane",
+					},
+				},
+			},
+			amcp: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.18.0",
+					Identity: &ManagedControlPlaneIdentity{
+						Type:                           ManagedControlPlaneIdentityTypeUserAssigned,
+						UserAssignedIdentityResourceID: "/subscriptions/123/resourcegroups/identities-rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/control-plane",
+					},
+				},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -32,8 +32,18 @@ const (
 
 	// NodePoolModeUser represents mode user for azuremachinepool.
 	NodePoolModeUser NodePoolMode = "User"
+
+	// SpotNodeLabelKey is the key of the label and taint AKS adds to the nodes of spot node pools.
+	SpotNodeLabelKey = "kubernetes.azure.com/scalesetpriority"
 )
 
+// SpotTaint is the taint AKS adds to the nodes of spot node pools.
+var SpotTaint = Taint{
+	Key:    SpotNodeLabelKey,
+	Value:  "spot",
+	Effect: TaintEffectNoSchedule,
+}
+
 // NodePoolMode enumerates the values for agent pool mode.
 type NodePoolMode string
 
@@ -64,6 +74,11 @@ type AzureManagedMachinePoolSpec struct {
 	// +optional
 	NodeLabels map[string]string `json:"nodeLabels,omitempty"`
 
+	// Taints specifies the taints for nodes present in this agent pool.
+	// Node labels and taints can be updated in place.
+	// +optional
+	Taints Taints `json:"taints,omitempty"`
+
 	// ProviderIDList is the unique identifier as specified by the cloud provider.
 	// +optional
 	ProviderIDList []string `json:"providerIDList,omitempty"`
@@ -81,6 +96,41 @@ type AzureManagedMachinePoolSpec struct {
 	// +kubebuilder:default=Managed
 	// +optional
 	OsDiskType *string `json:"osDiskType,omitempty"`
+
+	// OSType specifies the operating system of the nodes in the pool. Windows node pools must be User node pools,
+	// with a name of at most 6 characters, in a cluster with a Windows profile and the azure network plugin.
+	// +kubebuilder:validation:Enum=Linux;Windows
+	// +kubebuilder:default=Linux
+	// +optional
+	OSType *string `json:"osType,omitempty"`
+
+	// KubeletConfig specifies the kubelet configuration for nodes in this agent pool. Immutable.
+	// +optional
+	KubeletConfig *KubeletConfig `json:"kubeletConfig,omitempty"`
+
+	// LinuxOSConfig specifies the OS configuration for Linux nodes in this agent pool. Immutable.
+	// +optional
+	LinuxOSConfig *LinuxOSConfig `json:"linuxOSConfig,omitempty"`
+
+	// SpotVMOptions allows the ability to specify the agent pool should use Spot VMs, which are deleted when evicted.
+	// Spot node pools must be User node pools, and AKS adds the SpotTaint to their nodes. Immutable.
+	// +optional
+	SpotVMOptions *infrav1.SpotVMOptions `json:"spotVMOptions,omitempty"`
+
+	// SubnetName is the name of the subnet of the control plane's virtual network for the nodes of this agent pool.
+	// Defaults to the subnet of the control plane's virtual network. Immutable.
+	// +optional
+	SubnetName *string `json:"subnetName,omitempty"`
+
+	// PodSubnetName is the name of the subnet of the control plane's virtual network from which the pods of this
+	// agent pool get their IP addresses, with dynamic IP allocation of the azure network plugin. Pods get IP
+	// addresses from the node subnet when it is not set. Immutable.
+	// +optional
+	PodSubnetName *string `json:"podSubnetName,omitempty"`
+
+	// UpgradeSettings specifies how the nodes of this agent pool are rolled when its Kubernetes version is upgraded.
+	// +optional
+	UpgradeSettings *ManagedMachinePoolUpgradeSettings `json:"upgradeSettings,omitempty"`
 }
 
 // ManagedMachinePoolScaling specifies scaling options.
@@ -89,6 +139,242 @@ type ManagedMachinePoolScaling struct {
 	MaxSize *int32 `json:"maxSize,omitempty"`
 }
 
+// ManagedMachinePoolUpgradeSettings specifies the upgrade settings of an agent pool.
+type ManagedMachinePoolUpgradeSettings struct {
+	// MaxSurge is the maximum number or percentage of nodes that are surged during an upgrade, for example 5 or 33%.
+	// AKS surges one node when it is not set. See
+	// https://docs.microsoft.com/en-us/azure/aks/upgrade-cluster#customize-node-surge-upgrade
+	// +kubebuilder:validation:Pattern=`^[1-9][0-9]*%?$`
+	// +optional
+	MaxSurge *string `json:"maxSurge,omitempty"`
+}
+
+// TaintEffect is the effect for a Kubernetes taint.
+type TaintEffect string
+
+const (
+	// TaintEffectNoSchedule means pods that don't tolerate the taint are not scheduled on the node.
+	TaintEffectNoSchedule TaintEffect = "NoSchedule"
+
+	// TaintEffectPreferNoSchedule means the scheduler tries to avoid placing pods that don't tolerate the taint on the node.
+	TaintEffectPreferNoSchedule TaintEffect = "PreferNoSchedule"
+
+	// TaintEffectNoExecute means pods that don't tolerate the taint are evicted from the node.
+	TaintEffectNoExecute TaintEffect = "NoExecute"
+)
+
+// Taint represents a Kubernetes taint.
+type Taint struct {
+	// Effect specifies the effect for the taint.
+	// +kubebuilder:validation:Enum=NoSchedule;PreferNoSchedule;NoExecute
+	Effect TaintEffect `json:"effect"`
+
+	// Key is the key of the taint.
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+
+	// Value is the value of the taint.
+	Value string `json:"value"`
+}
+
+// Taints is an array of Taints.
+type Taints []Taint
+
+// KubeletConfig defines the set of kubelet configurations for nodes in an agent pool.
+// See https://docs.microsoft.com/en-us/azure/aks/custom-node-configuration for details.
+type KubeletConfig struct {
+	// CPUManagerPolicy specifies the CPU Manager policy to use.
+	// +kubebuilder:validation:Enum=none;static
+	// +optional
+	CPUManagerPolicy *string `json:"cpuManagerPolicy,omitempty"`
+
+	// CPUCfsQuota enables CPU CFS quota enforcement for containers that specify CPU limits.
+	// +optional
+	CPUCfsQuota *bool `json:"cpuCfsQuota,omitempty"`
+
+	// CPUCfsQuotaPeriod specifies the CPU CFS quota period value, e.g. '100ms'.
+	// +optional
+	CPUCfsQuotaPeriod *string `json:"cpuCfsQuotaPeriod,omitempty"`
+
+	// ImageGcHighThreshold specifies the percent of disk usage after which image garbage collection is always run.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	ImageGcHighThreshold *int32 `json:"imageGcHighThreshold,omitempty"`
+
+	// ImageGcLowThreshold specifies the percent of disk usage before which image garbage collection is never run.
+	// Must not be greater than ImageGcHighThreshold.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	ImageGcLowThreshold *int32 `json:"imageGcLowThreshold,omitempty"`
+
+	// TopologyManagerPolicy specifies the Topology Manager policy to use.
+	// +kubebuilder:validation:Enum=none;best-effort;restricted;single-numa-node
+	// +optional
+	TopologyManagerPolicy *string `json:"topologyManagerPolicy,omitempty"`
+
+	// AllowedUnsafeSysctls specifies an allowlist of unsafe sysctls or unsafe sysctl patterns (ending in `*`).
+	// +optional
+	AllowedUnsafeSysctls []string `json:"allowedUnsafeSysctls,omitempty"`
+
+	// FailSwapOn makes the kubelet fail to start if swap is enabled on the node.
+	// +optional
+	FailSwapOn *bool `json:"failSwapOn,omitempty"`
+
+	// ContainerLogMaxSizeMB specifies the maximum size in MB of a container log file before it is rotated.
+	// +optional
+	ContainerLogMaxSizeMB *int32 `json:"containerLogMaxSizeMB,omitempty"`
+
+	// ContainerLogMaxFiles specifies the maximum number of container log files that can be present for a container.
+	// +kubebuilder:validation:Minimum=2
+	// +optional
+	ContainerLogMaxFiles *int32 `json:"containerLogMaxFiles,omitempty"`
+
+	// PodMaxPids specifies the maximum number of processes per pod.
+	// +optional
+	PodMaxPids *int32 `json:"podMaxPids,omitempty"`
+}
+
+// LinuxOSConfig defines the OS configuration for Linux nodes in an agent pool.
+// See https://docs.microsoft.com/en-us/azure/aks/custom-node-configuration for details.
+type LinuxOSConfig struct {
+	// SwapFileSizeMB specifies the size in MB of a swap file that will be created on each node.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	SwapFileSizeMB *int32 `json:"swapFileSizeMB,omitempty"`
+
+	// Sysctls specifies the sysctl settings for Linux nodes.
+	// +optional
+	Sysctls *SysctlConfig `json:"sysctls,omitempty"`
+
+	// TransparentHugePageDefrag specifies whether the kernel should make aggressive use of memory compaction
+	// to make more hugepages available.
+	// +kubebuilder:validation:Enum=always;defer;defer+madvise;madvise;never
+	// +optional
+	TransparentHugePageDefrag *string `json:"transparentHugePageDefrag,omitempty"`
+
+	// TransparentHugePageEnabled specifies whether transparent hugepages are enabled.
+	// +kubebuilder:validation:Enum=always;madvise;never
+	// +optional
+	TransparentHugePageEnabled *string `json:"transparentHugePageEnabled,omitempty"`
+}
+
+// SysctlConfig specifies the sysctl settings for Linux nodes in an agent pool.
+type SysctlConfig struct {
+	// NetCoreSomaxconn specifies the value of net.core.somaxconn.
+	// +optional
+	NetCoreSomaxconn *int32 `json:"netCoreSomaxconn,omitempty"`
+
+	// NetCoreNetdevMaxBacklog specifies the value of net.core.netdev_max_backlog.
+	// +optional
+	NetCoreNetdevMaxBacklog *int32 `json:"netCoreNetdevMaxBacklog,omitempty"`
+
+	// NetCoreRmemDefault specifies the value of net.core.rmem_default.
+	// +optional
+	NetCoreRmemDefault *int32 `json:"netCoreRmemDefault,omitempty"`
+
+	// NetCoreRmemMax specifies the value of net.core.rmem_max.
+	// +optional
+	NetCoreRmemMax *int32 `json:"netCoreRmemMax,omitempty"`
+
+	// NetCoreWmemDefault specifies the value of net.core.wmem_default.
+	// +optional
+	NetCoreWmemDefault *int32 `json:"netCoreWmemDefault,omitempty"`
+
+	// NetCoreWmemMax specifies the value of net.core.wmem_max.
+	// +optional
+	NetCoreWmemMax *int32 `json:"netCoreWmemMax,omitempty"`
+
+	// NetCoreOptmemMax specifies the value of net.core.optmem_max.
+	// +optional
+	NetCoreOptmemMax *int32 `json:"netCoreOptmemMax,omitempty"`
+
+	// NetIpv4TCPMaxSynBacklog specifies the value of net.ipv4.tcp_max_syn_backlog.
+	// +optional
+	NetIpv4TCPMaxSynBacklog *int32 `json:"netIpv4TcpMaxSynBacklog,omitempty"`
+
+	// NetIpv4TCPMaxTwBuckets specifies the value of net.ipv4.tcp_max_tw_buckets.
+	// +optional
+	NetIpv4TCPMaxTwBuckets *int32 `json:"netIpv4TcpMaxTwBuckets,omitempty"`
+
+	// NetIpv4TCPFinTimeout specifies the value of net.ipv4.tcp_fin_timeout.
+	// +optional
+	NetIpv4TCPFinTimeout *int32 `json:"netIpv4TcpFinTimeout,omitempty"`
+
+	// NetIpv4TCPKeepaliveTime specifies the value of net.ipv4.tcp_keepalive_time.
+	// +optional
+	NetIpv4TCPKeepaliveTime *int32 `json:"netIpv4TcpKeepaliveTime,omitempty"`
+
+	// NetIpv4TCPKeepaliveProbes specifies the value of net.ipv4.tcp_keepalive_probes.
+	// +optional
+	NetIpv4TCPKeepaliveProbes *int32 `json:"netIpv4TcpKeepaliveProbes,omitempty"`
+
+	// NetIpv4TCPKeepaliveIntvl specifies the value of net.ipv4.tcp_keepalive_intvl.
+	// +optional
+	NetIpv4TCPKeepaliveIntvl *int32 `json:"netIpv4TcpkeepaliveIntvl,omitempty"`
+
+	// NetIpv4TCPTwReuse specifies the value of net.ipv4.tcp_tw_reuse.
+	// +optional
+	NetIpv4TCPTwReuse *bool `json:"netIpv4TcpTwReuse,omitempty"`
+
+	// NetIpv4IPLocalPortRange specifies the value of net.ipv4.ip_local_port_range.
+	// +optional
+	NetIpv4IPLocalPortRange *string `json:"netIpv4IpLocalPortRange,omitempty"`
+
+	// NetIpv4NeighDefaultGcThresh1 specifies the value of net.ipv4.neigh.default.gc_thresh1.
+	// +optional
+	NetIpv4NeighDefaultGcThresh1 *int32 `json:"netIpv4NeighDefaultGcThresh1,omitempty"`
+
+	// NetIpv4NeighDefaultGcThresh2 specifies the value of net.ipv4.neigh.default.gc_thresh2.
+	// +optional
+	NetIpv4NeighDefaultGcThresh2 *int32 `json:"netIpv4NeighDefaultGcThresh2,omitempty"`
+
+	// NetIpv4NeighDefaultGcThresh3 specifies the value of net.ipv4.neigh.default.gc_thresh3.
+	// +optional
+	NetIpv4NeighDefaultGcThresh3 *int32 `json:"netIpv4NeighDefaultGcThresh3,omitempty"`
+
+	// NetNetfilterNfConntrackMax specifies the value of net.netfilter.nf_conntrack_max.
+	// +optional
+	NetNetfilterNfConntrackMax *int32 `json:"netNetfilterNfConntrackMax,omitempty"`
+
+	// NetNetfilterNfConntrackBuckets specifies the value of net.netfilter.nf_conntrack_buckets.
+	// +optional
+	NetNetfilterNfConntrackBuckets *int32 `json:"netNetfilterNfConntrackBuckets,omitempty"`
+
+	// FsInotifyMaxUserWatches specifies the value of fs.inotify.max_user_watches.
+	// +optional
+	FsInotifyMaxUserWatches *int32 `json:"fsInotifyMaxUserWatches,omitempty"`
+
+	// FsFileMax specifies the value of fs.file-max.
+	// +optional
+	FsFileMax *int32 `json:"fsFileMax,omitempty"`
+
+	// FsAioMaxNr specifies the value of fs.aio-max-nr.
+	// +optional
+	FsAioMaxNr *int32 `json:"fsAioMaxNr,omitempty"`
+
+	// FsNrOpen specifies the value of fs.nr_open.
+	// +optional
+	FsNrOpen *int32 `json:"fsNrOpen,omitempty"`
+
+	// KernelThreadsMax specifies the value of kernel.threads-max.
+	// +optional
+	KernelThreadsMax *int32 `json:"kernelThreadsMax,omitempty"`
+
+	// VMMaxMapCount specifies the value of vm.max_map_count.
+	// +optional
+	VMMaxMapCount *int32 `json:"vmMaxMapCount,omitempty"`
+
+	// VMSwappiness specifies the value of vm.swappiness.
+	// +optional
+	VMSwappiness *int32 `json:"vmSwappiness,omitempty"`
+
+	// VMVfsCachePressure specifies the value of vm.vfs_cache_pressure.
+	// +optional
+	VMVfsCachePressure *int32 `json:"vmVfsCachePressure,omitempty"`
+}
+
 // AzureManagedMachinePoolStatus defines the observed state of AzureManagedMachinePool.
 type AzureManagedMachinePoolStatus struct {
 	// Ready is true when the provider resource is ready.
@@ -99,6 +385,11 @@ type AzureManagedMachinePoolStatus struct {
 	// +optional
 	Replicas int32 `json:"replicas"`
 
+	// Taints are the taints on the nodes of the agent pool, including taints that AKS adds automatically,
+	// such as the SpotTaint.
+	// +optional
+	Taints Taints `json:"taints,omitempty"`
+
 	// Any transient errors that occur during the reconciliation of Machines
 	// can be added as events to the Machine object and/or logged in the
 	// controller's output.
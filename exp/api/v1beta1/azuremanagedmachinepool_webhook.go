@@ -18,15 +18,20 @@ package v1beta1
 
 import (
 	"context"
+	"reflect"
 
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
+	"golang.org/x/mod/semver"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/cluster-api-provider-azure/azure"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterv1exp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -42,14 +47,31 @@ func (r *AzureManagedMachinePool) Default(client client.Client) {
 	if r.Spec.Name == nil || *r.Spec.Name == "" {
 		r.Spec.Name = &r.Name
 	}
+
+	// Spot agent pools can be scaled down to zero nodes when all of their nodes are evicted.
+	if r.Spec.SpotVMOptions != nil && r.Spec.Scaling != nil && r.Spec.Scaling.MinSize == nil {
+		r.Spec.Scaling.MinSize = to.Int32Ptr(0)
+	}
 }
 
-//+kubebuilder:webhook:verbs=update;delete,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-azuremanagedmachinepool,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azuremanagedmachinepools,versions=v1beta1,name=validation.azuremanagedmachinepools.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+//+kubebuilder:webhook:verbs=create;update;delete,path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-azuremanagedmachinepool,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=infrastructure.cluster.x-k8s.io,resources=azuremanagedmachinepools,versions=v1beta1,name=validation.azuremanagedmachinepools.infrastructure.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
 func (r *AzureManagedMachinePool) ValidateCreate(client client.Client) error {
+	controlPlane, err := r.ownerControlPlane(client)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the AzureManagedControlPlane of the cluster")
+	}
+
 	validators := []func() error{
 		r.validateMaxPods,
+		func() error { return r.validateOSType(controlPlane) },
+		r.validateKubeletConfig,
+		r.validateLinuxOSConfig,
+		r.validateSpotVMOptions,
+		r.validateScaling,
+		func() error { return r.validateSubnets(controlPlane) },
+		func() error { return r.validateVersion(client, controlPlane) },
 	}
 
 	var errs []error
@@ -150,11 +172,69 @@ func (r *AzureManagedMachinePool) ValidateUpdate(oldRaw runtime.Object, client c
 		}
 	}
 
+	// OSType defaults to Linux, so an unset value is equivalent to Linux.
+	if osTypeOrDefault(r.Spec.OSType) != osTypeOrDefault(old.Spec.OSType) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "OSType"),
+				r.Spec.OSType,
+				"field is immutable"))
+	}
+
+	if !reflect.DeepEqual(r.Spec.KubeletConfig, old.Spec.KubeletConfig) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "KubeletConfig"),
+				r.Spec.KubeletConfig,
+				"field is immutable"))
+	}
+
+	if !reflect.DeepEqual(r.Spec.LinuxOSConfig, old.Spec.LinuxOSConfig) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "LinuxOSConfig"),
+				r.Spec.LinuxOSConfig,
+				"field is immutable"))
+	}
+
+	if !reflect.DeepEqual(r.Spec.SpotVMOptions, old.Spec.SpotVMOptions) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "SpotVMOptions"),
+				r.Spec.SpotVMOptions,
+				"field is immutable"))
+	}
+
+	if !reflect.DeepEqual(r.Spec.SubnetName, old.Spec.SubnetName) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "SubnetName"),
+				r.Spec.SubnetName,
+				"field is immutable"))
+	}
+
+	if !reflect.DeepEqual(r.Spec.PodSubnetName, old.Spec.PodSubnetName) {
+		allErrs = append(allErrs,
+			field.Invalid(
+				field.NewPath("Spec", "PodSubnetName"),
+				r.Spec.PodSubnetName,
+				"field is immutable"))
+	}
+
 	if len(allErrs) != 0 {
 		return apierrors.NewInvalid(GroupVersion.WithKind("AzureManagedMachinePool").GroupKind(), r.Name, allErrs)
 	}
 
-	return nil
+	controlPlane, err := r.ownerControlPlane(client)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the AzureManagedControlPlane of the cluster")
+	}
+
+	if err := r.validateVersion(client, controlPlane); err != nil {
+		return err
+	}
+
+	return r.validateScaling()
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
@@ -211,6 +291,115 @@ func (r *AzureManagedMachinePool) validateLastSystemNodePool(cli client.Client)
 	return nil
 }
 
+// ownerControlPlane returns the AzureManagedControlPlane of the cluster the agent pool belongs to.
+// It returns nil if the agent pool is not labeled with its cluster yet, or if the cluster or its control plane
+// do not exist yet, as all of them may be created at the same time.
+func (r *AzureManagedMachinePool) ownerControlPlane(cli client.Client) (*AzureManagedControlPlane, error) {
+	ctx := context.Background()
+
+	clusterName, ok := r.Labels[clusterv1.ClusterLabelName]
+	if !ok {
+		return nil, nil
+	}
+
+	ownerCluster := &clusterv1.Cluster{}
+	key := client.ObjectKey{
+		Namespace: r.Namespace,
+		Name:      clusterName,
+	}
+
+	if err := cli.Get(ctx, key, ownerCluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	ref := ownerCluster.Spec.ControlPlaneRef
+	if ref == nil || ref.Kind != "AzureManagedControlPlane" {
+		return nil, nil
+	}
+
+	controlPlane := &AzureManagedControlPlane{}
+	key = client.ObjectKey{
+		Namespace: ref.Namespace,
+		Name:      ref.Name,
+	}
+	if key.Namespace == "" {
+		key.Namespace = r.Namespace
+	}
+
+	if err := cli.Get(ctx, key, controlPlane); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return controlPlane, nil
+}
+
+// ownerMachinePool returns the MachinePool the agent pool belongs to. It returns nil if the agent pool has no owner
+// MachinePool yet, or if the MachinePool no longer exists.
+func (r *AzureManagedMachinePool) ownerMachinePool(cli client.Client) (*clusterv1exp.MachinePool, error) {
+	for _, ref := range r.OwnerReferences {
+		if ref.Kind != "MachinePool" {
+			continue
+		}
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil {
+			return nil, err
+		}
+		if gv.Group != clusterv1exp.GroupVersion.Group {
+			continue
+		}
+
+		machinePool := &clusterv1exp.MachinePool{}
+		key := client.ObjectKey{
+			Namespace: r.Namespace,
+			Name:      ref.Name,
+		}
+		if err := cli.Get(context.Background(), key, machinePool); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+
+		return machinePool, nil
+	}
+
+	return nil, nil
+}
+
+// validateVersion validates that the Kubernetes version of the owner MachinePool is not ahead of the version of the
+// control plane, as AKS only upgrades agent pools after their control plane.
+func (r *AzureManagedMachinePool) validateVersion(cli client.Client, controlPlane *AzureManagedControlPlane) error {
+	if controlPlane == nil {
+		return nil
+	}
+
+	machinePool, err := r.ownerMachinePool(cli)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the MachinePool of the agent pool")
+	}
+	if machinePool == nil || machinePool.Spec.Template.Spec.Version == nil {
+		return nil
+	}
+
+	version, controlPlaneVersion := normalizeVersion(*machinePool.Spec.Template.Spec.Version), normalizeVersion(controlPlane.Spec.Version)
+	if !semver.IsValid(version) || !semver.IsValid(controlPlaneVersion) {
+		return nil
+	}
+
+	if semver.Compare(version, controlPlaneVersion) > 0 {
+		return errors.Errorf("the Kubernetes version %s of the MachinePool cannot be greater than the version %s of the AzureManagedControlPlane",
+			*machinePool.Spec.Template.Spec.Version, controlPlane.Spec.Version)
+	}
+
+	return nil
+}
+
 func (r *AzureManagedMachinePool) validateMaxPods() error {
 	if r.Spec.MaxPods != nil {
 		if to.Int32(r.Spec.MaxPods) < 10 || to.Int32(r.Spec.MaxPods) > 250 {
@@ -224,6 +413,205 @@ func (r *AzureManagedMachinePool) validateMaxPods() error {
 	return nil
 }
 
+// validateOSType validates the restrictions AKS places on Windows node pools.
+// The control plane is nil if it could not be found, in which case the cluster level restrictions are not validated.
+func (r *AzureManagedMachinePool) validateOSType(controlPlane *AzureManagedControlPlane) error {
+	if to.String(r.Spec.OSType) != azure.WindowsOS {
+		return nil
+	}
+
+	if r.Spec.Mode == string(NodePoolModeSystem) {
+		return field.Invalid(
+			field.NewPath("Spec", "OSType"),
+			r.Spec.OSType,
+			"System node pools must use the Linux OS type")
+	}
+
+	name := r.Name
+	if r.Spec.Name != nil && *r.Spec.Name != "" {
+		name = *r.Spec.Name
+	}
+	if len(name) > 6 {
+		return field.Invalid(
+			field.NewPath("Spec", "Name"),
+			name,
+			"Windows node pool names must be at most 6 characters long")
+	}
+
+	if controlPlane == nil {
+		return nil
+	}
+
+	if controlPlane.Spec.WindowsProfile == nil {
+		return field.Invalid(
+			field.NewPath("Spec", "OSType"),
+			r.Spec.OSType,
+			"Windows node pools require the AzureManagedControlPlane to have a WindowsProfile")
+	}
+
+	// The network plugin defaults to azure.
+	if controlPlane.Spec.NetworkPlugin != nil && *controlPlane.Spec.NetworkPlugin != "azure" {
+		return field.Invalid(
+			field.NewPath("Spec", "OSType"),
+			r.Spec.OSType,
+			"Windows node pools require the AzureManagedControlPlane to use the azure network plugin")
+	}
+
+	return nil
+}
+
+// validateKubeletConfig validates the kubelet configuration of the agent pool.
+func (r *AzureManagedMachinePool) validateKubeletConfig() error {
+	kubeletConfig := r.Spec.KubeletConfig
+	if kubeletConfig == nil || kubeletConfig.ImageGcHighThreshold == nil || kubeletConfig.ImageGcLowThreshold == nil {
+		return nil
+	}
+
+	if *kubeletConfig.ImageGcLowThreshold > *kubeletConfig.ImageGcHighThreshold {
+		return field.Invalid(
+			field.NewPath("Spec", "KubeletConfig", "ImageGcLowThreshold"),
+			*kubeletConfig.ImageGcLowThreshold,
+			"ImageGcLowThreshold must not be greater than ImageGcHighThreshold")
+	}
+
+	return nil
+}
+
+// validateLinuxOSConfig validates the Linux OS configuration of the agent pool.
+func (r *AzureManagedMachinePool) validateLinuxOSConfig() error {
+	if r.Spec.LinuxOSConfig == nil {
+		return nil
+	}
+
+	if to.String(r.Spec.OSType) == azure.WindowsOS {
+		return field.Invalid(
+			field.NewPath("Spec", "LinuxOSConfig"),
+			r.Spec.LinuxOSConfig,
+			"LinuxOSConfig cannot be set on Windows node pools")
+	}
+
+	// AKS only creates a swap file when the kubelet is allowed to start with swap enabled.
+	if r.Spec.LinuxOSConfig.SwapFileSizeMB != nil {
+		if r.Spec.KubeletConfig == nil || r.Spec.KubeletConfig.FailSwapOn == nil || *r.Spec.KubeletConfig.FailSwapOn {
+			return field.Invalid(
+				field.NewPath("Spec", "LinuxOSConfig", "SwapFileSizeMB"),
+				*r.Spec.LinuxOSConfig.SwapFileSizeMB,
+				"KubeletConfig.FailSwapOn must be set to false to enable a swap file")
+		}
+	}
+
+	return nil
+}
+
+// validateSpotVMOptions validates the restrictions AKS places on Spot node pools.
+func (r *AzureManagedMachinePool) validateSpotVMOptions() error {
+	if r.Spec.SpotVMOptions == nil {
+		return nil
+	}
+
+	if r.Spec.Mode == string(NodePoolModeSystem) {
+		return field.Invalid(
+			field.NewPath("Spec", "Mode"),
+			r.Spec.Mode,
+			"Spot node pools must be User node pools")
+	}
+
+	// A max price of -1 caps the price at the on-demand price.
+	if maxPrice := r.Spec.SpotVMOptions.MaxPrice; maxPrice != nil && maxPrice.Sign() <= 0 && maxPrice.Cmp(resource.MustParse("-1")) != 0 {
+		return field.Invalid(
+			field.NewPath("Spec", "SpotVMOptions", "MaxPrice"),
+			maxPrice.String(),
+			"MaxPrice must be -1 or greater than zero")
+	}
+
+	return nil
+}
+
+// validateScaling validates the autoscaler bounds of the agent pool.
+// System node pools must keep at least one node, while User node pools such as Spot node pools may scale down to zero.
+func (r *AzureManagedMachinePool) validateScaling() error {
+	if r.Spec.Scaling == nil {
+		return nil
+	}
+
+	minSize, maxSize := r.Spec.Scaling.MinSize, r.Spec.Scaling.MaxSize
+	if minSize != nil && *minSize < 0 {
+		return field.Invalid(
+			field.NewPath("Spec", "Scaling", "MinSize"),
+			*minSize,
+			"MinSize must not be negative")
+	}
+
+	if minSize != nil && *minSize == 0 && r.Spec.Mode == string(NodePoolModeSystem) {
+		return field.Invalid(
+			field.NewPath("Spec", "Scaling", "MinSize"),
+			*minSize,
+			"System node pools must have at least one node")
+	}
+
+	if minSize != nil && maxSize != nil && *minSize > *maxSize {
+		return field.Invalid(
+			field.NewPath("Spec", "Scaling", "MinSize"),
+			*minSize,
+			"MinSize must not be greater than MaxSize")
+	}
+
+	return nil
+}
+
+// validateSubnets validates that the node and pod subnets of the agent pool are subnets of the control plane's virtual
+// network, and that the control plane uses the azure network plugin when the agent pool has a pod subnet.
+// The control plane is nil if it could not be found, in which case the subnets are not validated.
+func (r *AzureManagedMachinePool) validateSubnets(controlPlane *AzureManagedControlPlane) error {
+	if controlPlane == nil {
+		return nil
+	}
+
+	vnet := controlPlane.Spec.VirtualNetwork
+	subnetNames := make(map[string]bool, len(vnet.Subnets)+1)
+	if vnet.Subnet.Name != "" {
+		subnetNames[vnet.Subnet.Name] = true
+	}
+	for _, subnet := range vnet.Subnets {
+		subnetNames[subnet.Name] = true
+	}
+
+	if r.Spec.SubnetName != nil && !subnetNames[*r.Spec.SubnetName] {
+		return field.Invalid(
+			field.NewPath("Spec", "SubnetName"),
+			*r.Spec.SubnetName,
+			"SubnetName must be the name of a subnet of the virtual network of the AzureManagedControlPlane")
+	}
+
+	if r.Spec.PodSubnetName == nil {
+		return nil
+	}
+
+	if !subnetNames[*r.Spec.PodSubnetName] {
+		return field.Invalid(
+			field.NewPath("Spec", "PodSubnetName"),
+			*r.Spec.PodSubnetName,
+			"PodSubnetName must be the name of a subnet of the virtual network of the AzureManagedControlPlane")
+	}
+
+	// The network plugin defaults to azure.
+	if controlPlane.Spec.NetworkPlugin != nil && *controlPlane.Spec.NetworkPlugin != "azure" {
+		return field.Invalid(
+			field.NewPath("Spec", "PodSubnetName"),
+			*r.Spec.PodSubnetName,
+			"PodSubnetName requires the AzureManagedControlPlane to use the azure network plugin")
+	}
+
+	return nil
+}
+
+func osTypeOrDefault(osType *string) string {
+	if osType == nil || *osType == "" {
+		return azure.LinuxOS
+	}
+	return *osType
+}
+
 func ensureStringSlicesAreEqual(a []string, b []string) bool {
 	if len(a) != len(b) {
 		return false
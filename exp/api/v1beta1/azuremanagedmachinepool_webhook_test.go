@@ -19,11 +19,19 @@ package v1beta1
 import (
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-05-01/containerservice"
+	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-08-01/containerservice"
 	"github.com/Azure/go-autorest/autorest/to"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/azure"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterv1exp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestAzureManagedMachinePoolDefaultingWebhook(t *testing.T) {
@@ -65,6 +73,28 @@ func TestAzureManagedMachinePoolDefaultingWebhook(t *testing.T) {
 	ammp.Spec.OsDiskType = &normalOsDiskType
 	ammp.Default(client)
 	g.Expect(*ammp.Spec.OsDiskType).To(Equal("Ephemeral"))
+
+	t.Logf("Testing ammp defaulting webhook with a Spot node pool without a MinSize")
+	spotPool := &AzureManagedMachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "spotpool",
+		},
+		Spec: AzureManagedMachinePoolSpec{
+			Mode:          "User",
+			SKU:           "StandardD2S_V3",
+			SpotVMOptions: &infrav1.SpotVMOptions{},
+			Scaling: &ManagedMachinePoolScaling{
+				MaxSize: to.Int32Ptr(5),
+			},
+		},
+	}
+	spotPool.Default(client)
+	g.Expect(spotPool.Spec.Scaling.MinSize).To(Equal(to.Int32Ptr(0)))
+
+	t.Logf("Testing ammp defaulting webhook with a Spot node pool with a MinSize")
+	spotPool.Spec.Scaling.MinSize = to.Int32Ptr(2)
+	spotPool.Default(client)
+	g.Expect(spotPool.Spec.Scaling.MinSize).To(Equal(to.Int32Ptr(2)))
 }
 
 func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
@@ -276,6 +306,250 @@ func TestAzureManagedMachinePoolUpdatingWebhook(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Cannot change OSType of the agentpool",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:   "User",
+					SKU:    "StandardD2S_V3",
+					OSType: to.StringPtr(azure.WindowsOS),
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:   "User",
+					SKU:    "StandardD2S_V3",
+					OSType: to.StringPtr(azure.LinuxOS),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Cannot change KubeletConfig of the agentpool",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode: "User",
+					SKU:  "StandardD2S_V3",
+					KubeletConfig: &KubeletConfig{
+						CPUManagerPolicy: to.StringPtr("static"),
+					},
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode: "User",
+					SKU:  "StandardD2S_V3",
+					KubeletConfig: &KubeletConfig{
+						CPUManagerPolicy: to.StringPtr("none"),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Cannot add LinuxOSConfig to the agentpool",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode: "User",
+					SKU:  "StandardD2S_V3",
+					LinuxOSConfig: &LinuxOSConfig{
+						TransparentHugePageEnabled: to.StringPtr("never"),
+					},
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode: "User",
+					SKU:  "StandardD2S_V3",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Can change Taints and NodeLabels of the agentpool",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode: "System",
+					SKU:  "StandardD2S_V3",
+					NodeLabels: map[string]string{
+						"role": "system",
+					},
+					Taints: Taints{
+						{
+							Key:    "CriticalAddonsOnly",
+							Value:  "true",
+							Effect: TaintEffectNoSchedule,
+						},
+					},
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode: "System",
+					SKU:  "StandardD2S_V3",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Setting OSType to the default Linux value should not result in an error",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:   "User",
+					SKU:    "StandardD2S_V3",
+					OSType: to.StringPtr(azure.LinuxOS),
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode: "User",
+					SKU:  "StandardD2S_V3",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Cannot add SpotVMOptions to the agentpool",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:          "User",
+					SKU:           "StandardD2S_V3",
+					SpotVMOptions: &infrav1.SpotVMOptions{},
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode: "User",
+					SKU:  "StandardD2S_V3",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Cannot change the Spot max price of the agentpool",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode: "User",
+					SKU:  "StandardD2S_V3",
+					SpotVMOptions: &infrav1.SpotVMOptions{
+						MaxPrice: resourceQuantityPtr("0.5"),
+					},
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:          "User",
+					SKU:           "StandardD2S_V3",
+					SpotVMOptions: &infrav1.SpotVMOptions{},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Can change the scaling bounds of a Spot agentpool",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:          "User",
+					SKU:           "StandardD2S_V3",
+					SpotVMOptions: &infrav1.SpotVMOptions{},
+					Scaling: &ManagedMachinePoolScaling{
+						MinSize: to.Int32Ptr(0),
+						MaxSize: to.Int32Ptr(10),
+					},
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:          "User",
+					SKU:           "StandardD2S_V3",
+					SpotVMOptions: &infrav1.SpotVMOptions{},
+					Scaling: &ManagedMachinePoolScaling{
+						MinSize: to.Int32Ptr(1),
+						MaxSize: to.Int32Ptr(5),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Cannot change the SubnetName of the agentpool",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:       "User",
+					SKU:        "StandardD2S_V3",
+					SubnetName: to.StringPtr("subnet-2"),
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:       "User",
+					SKU:        "StandardD2S_V3",
+					SubnetName: to.StringPtr("subnet-1"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Cannot add a PodSubnetName to the agentpool",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:          "User",
+					SKU:           "StandardD2S_V3",
+					PodSubnetName: to.StringPtr("pods"),
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode: "User",
+					SKU:  "StandardD2S_V3",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Can keep the SubnetName and PodSubnetName of the agentpool",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:          "User",
+					SKU:           "StandardD2S_V3",
+					SubnetName:    to.StringPtr("nodes"),
+					PodSubnetName: to.StringPtr("pods"),
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:          "User",
+					SKU:           "StandardD2S_V3",
+					SubnetName:    to.StringPtr("nodes"),
+					PodSubnetName: to.StringPtr("pods"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Cannot set a MinSize greater than the MaxSize of the agentpool",
+			new: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode: "User",
+					SKU:  "StandardD2S_V3",
+					Scaling: &ManagedMachinePoolScaling{
+						MinSize: to.Int32Ptr(6),
+						MaxSize: to.Int32Ptr(5),
+					},
+				},
+			},
+			old: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode: "User",
+					SKU:  "StandardD2S_V3",
+					Scaling: &ManagedMachinePoolScaling{
+						MinSize: to.Int32Ptr(1),
+						MaxSize: to.Int32Ptr(5),
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	var client client.Client
 	for _, tc := range tests {
@@ -346,6 +620,177 @@ func TestAzureManagedMachinePool_ValidateCreate(t *testing.T) {
 			wantErr:  true,
 			errorLen: 1,
 		},
+		{
+			name: "valid kubelet and Linux OS configuration",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					KubeletConfig: &KubeletConfig{
+						ImageGcHighThreshold: to.Int32Ptr(85),
+						ImageGcLowThreshold:  to.Int32Ptr(80),
+						FailSwapOn:           to.BoolPtr(false),
+					},
+					LinuxOSConfig: &LinuxOSConfig{
+						SwapFileSizeMB: to.Int32Ptr(1500),
+						Sysctls: &SysctlConfig{
+							NetCoreSomaxconn: to.Int32Ptr(16384),
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ImageGcLowThreshold greater than ImageGcHighThreshold",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					KubeletConfig: &KubeletConfig{
+						ImageGcHighThreshold: to.Int32Ptr(70),
+						ImageGcLowThreshold:  to.Int32Ptr(80),
+					},
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
+		{
+			name: "swap file without disabling FailSwapOn",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					LinuxOSConfig: &LinuxOSConfig{
+						SwapFileSizeMB: to.Int32Ptr(1500),
+					},
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
+		{
+			name: "LinuxOSConfig on a Windows node pool",
+			ammp: &AzureManagedMachinePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "pool1",
+				},
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:   "User",
+					OSType: to.StringPtr(azure.WindowsOS),
+					LinuxOSConfig: &LinuxOSConfig{
+						TransparentHugePageEnabled: to.StringPtr("never"),
+					},
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
+		{
+			name: "valid Windows node pool",
+			ammp: &AzureManagedMachinePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "pool1",
+				},
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:   "User",
+					OSType: to.StringPtr(azure.WindowsOS),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Windows node pool name too long",
+			ammp: &AzureManagedMachinePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "pool1",
+				},
+				Spec: AzureManagedMachinePoolSpec{
+					Name:   to.StringPtr("windowspool"),
+					Mode:   "User",
+					OSType: to.StringPtr(azure.WindowsOS),
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
+		{
+			name: "Windows system node pool",
+			ammp: &AzureManagedMachinePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "pool1",
+				},
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:   "System",
+					OSType: to.StringPtr(azure.WindowsOS),
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
+		{
+			name: "valid Spot node pool",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode: "User",
+					SpotVMOptions: &infrav1.SpotVMOptions{
+						MaxPrice: resourceQuantityPtr("-1"),
+					},
+					Scaling: &ManagedMachinePoolScaling{
+						MinSize: to.Int32Ptr(0),
+						MaxSize: to.Int32Ptr(3),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Spot system node pool",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode:          "System",
+					SpotVMOptions: &infrav1.SpotVMOptions{},
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
+		{
+			name: "Spot node pool with an invalid max price",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode: "User",
+					SpotVMOptions: &infrav1.SpotVMOptions{
+						MaxPrice: resourceQuantityPtr("-2"),
+					},
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
+		{
+			name: "system node pool scaling down to zero nodes",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode: "System",
+					Scaling: &ManagedMachinePoolScaling{
+						MinSize: to.Int32Ptr(0),
+						MaxSize: to.Int32Ptr(3),
+					},
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
+		{
+			name: "MinSize greater than MaxSize",
+			ammp: &AzureManagedMachinePool{
+				Spec: AzureManagedMachinePoolSpec{
+					Mode: "User",
+					Scaling: &ManagedMachinePoolScaling{
+						MinSize: to.Int32Ptr(4),
+						MaxSize: to.Int32Ptr(3),
+					},
+				},
+			},
+			wantErr:  true,
+			errorLen: 1,
+		},
 	}
 	var client client.Client
 	for _, tc := range tests {
@@ -360,3 +805,297 @@ func TestAzureManagedMachinePool_ValidateCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestAzureManagedMachinePool_ValidateCreateWithControlPlane(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(AddToScheme(scheme)).To(Succeed())
+	g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+
+	windowsPool := func() *AzureManagedMachinePool {
+		return &AzureManagedMachinePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pool1",
+				Namespace: "default",
+				Labels: map[string]string{
+					clusterv1.ClusterLabelName: "my-cluster",
+				},
+			},
+			Spec: AzureManagedMachinePoolSpec{
+				Mode:   "User",
+				OSType: to.StringPtr(azure.WindowsOS),
+			},
+		}
+	}
+
+	userPool := func(subnetName, podSubnetName *string) *AzureManagedMachinePool {
+		return &AzureManagedMachinePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pool1",
+				Namespace: "default",
+				Labels: map[string]string{
+					clusterv1.ClusterLabelName: "my-cluster",
+				},
+			},
+			Spec: AzureManagedMachinePoolSpec{
+				Mode:          "User",
+				SubnetName:    subnetName,
+				PodSubnetName: podSubnetName,
+			},
+		}
+	}
+
+	controlPlaneWithSubnets := func(networkPlugin string) *AzureManagedControlPlane {
+		return &AzureManagedControlPlane{
+			Spec: AzureManagedControlPlaneSpec{
+				NetworkPlugin: to.StringPtr(networkPlugin),
+				VirtualNetwork: ManagedControlPlaneVirtualNetwork{
+					Name:      "my-vnet",
+					CIDRBlock: "10.0.0.0/8",
+					Subnet: ManagedControlPlaneSubnet{
+						Name:      "my-subnet",
+						CIDRBlock: "10.240.0.0/16",
+					},
+					Subnets: []ManagedControlPlaneSubnet{
+						{
+							Name:      "my-pod-subnet",
+							CIDRBlock: "10.241.0.0/16",
+						},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		ammp         *AzureManagedMachinePool
+		controlPlane *AzureManagedControlPlane
+		wantErr      bool
+	}{
+		{
+			name: "Windows node pool on a control plane with a WindowsProfile and the azure network plugin",
+			ammp: windowsPool(),
+			controlPlane: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					NetworkPlugin: to.StringPtr("azure"),
+					WindowsProfile: &ManagedControlPlaneWindowsProfile{
+						AdminUsername: "azureuser",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Windows node pool on a control plane without a WindowsProfile",
+			ammp: windowsPool(),
+			controlPlane: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					NetworkPlugin: to.StringPtr("azure"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Windows node pool on a control plane with the kubenet network plugin",
+			ammp: windowsPool(),
+			controlPlane: &AzureManagedControlPlane{
+				Spec: AzureManagedControlPlaneSpec{
+					NetworkPlugin: to.StringPtr("kubenet"),
+					WindowsProfile: &ManagedControlPlaneWindowsProfile{
+						AdminUsername: "azureuser",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:         "node and pod subnets of the control plane's virtual network",
+			ammp:         userPool(to.StringPtr("my-subnet"), to.StringPtr("my-pod-subnet")),
+			controlPlane: controlPlaneWithSubnets("azure"),
+			wantErr:      false,
+		},
+		{
+			name:         "node subnet that is not a subnet of the control plane's virtual network",
+			ammp:         userPool(to.StringPtr("other-subnet"), nil),
+			controlPlane: controlPlaneWithSubnets("azure"),
+			wantErr:      true,
+		},
+		{
+			name:         "pod subnet that is not a subnet of the control plane's virtual network",
+			ammp:         userPool(nil, to.StringPtr("other-subnet")),
+			controlPlane: controlPlaneWithSubnets("azure"),
+			wantErr:      true,
+		},
+		{
+			name:         "pod subnet on a control plane with the kubenet network plugin",
+			ammp:         userPool(nil, to.StringPtr("my-pod-subnet")),
+			controlPlane: controlPlaneWithSubnets("kubenet"),
+			wantErr:      true,
+		},
+		{
+			name:    "pod subnet whose control plane does not exist yet",
+			ammp:    userPool(nil, to.StringPtr("other-subnet")),
+			wantErr: false,
+		},
+		{
+			name:    "Windows node pool whose control plane does not exist yet",
+			ammp:    windowsPool(),
+			wantErr: false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-cluster",
+					Namespace: "default",
+				},
+				Spec: clusterv1.ClusterSpec{
+					ControlPlaneRef: &corev1.ObjectReference{
+						APIVersion: GroupVersion.String(),
+						Kind:       "AzureManagedControlPlane",
+						Name:       "my-control-plane",
+					},
+				},
+			}
+			initObjects := []client.Object{cluster}
+			if tc.controlPlane != nil {
+				tc.controlPlane.Name = "my-control-plane"
+				tc.controlPlane.Namespace = "default"
+				initObjects = append(initObjects, tc.controlPlane)
+			}
+			client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjects...).Build()
+
+			err := tc.ammp.ValidateCreate(client)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestAzureManagedMachinePool_ValidateVersion(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(AddToScheme(scheme)).To(Succeed())
+	g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(clusterv1exp.AddToScheme(scheme)).To(Succeed())
+
+	tests := []struct {
+		name               string
+		machinePoolVersion *string
+		wantErr            bool
+	}{
+		{
+			name:               "MachinePool version equal to the control plane version",
+			machinePoolVersion: to.StringPtr("v1.22.4"),
+			wantErr:            false,
+		},
+		{
+			name:               "MachinePool version lower than the control plane version",
+			machinePoolVersion: to.StringPtr("v1.21.7"),
+			wantErr:            false,
+		},
+		{
+			name:               "MachinePool version greater than the control plane version",
+			machinePoolVersion: to.StringPtr("v1.23.3"),
+			wantErr:            true,
+		},
+		{
+			name:               "MachinePool version without the v prefix greater than the control plane version",
+			machinePoolVersion: to.StringPtr("1.22.6"),
+			wantErr:            true,
+		},
+		{
+			name:               "MachinePool without a version",
+			machinePoolVersion: nil,
+			wantErr:            false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-cluster",
+					Namespace: "default",
+				},
+				Spec: clusterv1.ClusterSpec{
+					ControlPlaneRef: &corev1.ObjectReference{
+						APIVersion: GroupVersion.String(),
+						Kind:       "AzureManagedControlPlane",
+						Name:       "my-control-plane",
+					},
+				},
+			}
+			controlPlane := &AzureManagedControlPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-control-plane",
+					Namespace: "default",
+				},
+				Spec: AzureManagedControlPlaneSpec{
+					Version: "v1.22.4",
+				},
+			}
+			machinePool := &clusterv1exp.MachinePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-machine-pool",
+					Namespace: "default",
+				},
+				Spec: clusterv1exp.MachinePoolSpec{
+					ClusterName: "my-cluster",
+					Template: clusterv1.MachineTemplateSpec{
+						Spec: clusterv1.MachineSpec{
+							ClusterName: "my-cluster",
+							Version:     tc.machinePoolVersion,
+						},
+					},
+				},
+			}
+			ammp := &AzureManagedMachinePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pool1",
+					Namespace: "default",
+					Labels: map[string]string{
+						clusterv1.ClusterLabelName: "my-cluster",
+					},
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							APIVersion: clusterv1exp.GroupVersion.String(),
+							Kind:       "MachinePool",
+							Name:       "my-machine-pool",
+						},
+					},
+				},
+				Spec: AzureManagedMachinePoolSpec{
+					Mode: "User",
+					SKU:  "StandardD2S_V3",
+				},
+			}
+			client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, controlPlane, machinePool).Build()
+
+			createErr := ammp.ValidateCreate(client)
+			updateErr := ammp.ValidateUpdate(ammp.DeepCopy(), client)
+			if tc.wantErr {
+				g.Expect(createErr).To(HaveOccurred())
+				g.Expect(updateErr).To(HaveOccurred())
+			} else {
+				g.Expect(createErr).NotTo(HaveOccurred())
+				g.Expect(updateErr).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func resourceQuantityPtr(s string) *resource.Quantity {
+	q := resource.MustParse(s)
+	return &q
+}
@@ -86,6 +86,143 @@ func (in *APIServerAccessProfile) DeepCopy() *APIServerAccessProfile {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddonProfile) DeepCopyInto(out *AddonProfile) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonProfile.
+func (in *AddonProfile) DeepCopy() *AddonProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(AddonProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoScalerProfile) DeepCopyInto(out *AutoScalerProfile) {
+	*out = *in
+	if in.BalanceSimilarNodeGroups != nil {
+		in, out := &in.BalanceSimilarNodeGroups, &out.BalanceSimilarNodeGroups
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Expander != nil {
+		in, out := &in.Expander, &out.Expander
+		*out = new(Expander)
+		**out = **in
+	}
+	if in.MaxEmptyBulkDelete != nil {
+		in, out := &in.MaxEmptyBulkDelete, &out.MaxEmptyBulkDelete
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxGracefulTerminationSec != nil {
+		in, out := &in.MaxGracefulTerminationSec, &out.MaxGracefulTerminationSec
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxNodeProvisionTime != nil {
+		in, out := &in.MaxNodeProvisionTime, &out.MaxNodeProvisionTime
+		*out = new(string)
+		**out = **in
+	}
+	if in.MaxTotalUnreadyPercentage != nil {
+		in, out := &in.MaxTotalUnreadyPercentage, &out.MaxTotalUnreadyPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NewPodScaleUpDelay != nil {
+		in, out := &in.NewPodScaleUpDelay, &out.NewPodScaleUpDelay
+		*out = new(string)
+		**out = **in
+	}
+	if in.OkTotalUnreadyCount != nil {
+		in, out := &in.OkTotalUnreadyCount, &out.OkTotalUnreadyCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ScanInterval != nil {
+		in, out := &in.ScanInterval, &out.ScanInterval
+		*out = new(string)
+		**out = **in
+	}
+	if in.ScaleDownDelayAfterAdd != nil {
+		in, out := &in.ScaleDownDelayAfterAdd, &out.ScaleDownDelayAfterAdd
+		*out = new(string)
+		**out = **in
+	}
+	if in.ScaleDownDelayAfterDelete != nil {
+		in, out := &in.ScaleDownDelayAfterDelete, &out.ScaleDownDelayAfterDelete
+		*out = new(string)
+		**out = **in
+	}
+	if in.ScaleDownDelayAfterFailure != nil {
+		in, out := &in.ScaleDownDelayAfterFailure, &out.ScaleDownDelayAfterFailure
+		*out = new(string)
+		**out = **in
+	}
+	if in.ScaleDownUnneededTime != nil {
+		in, out := &in.ScaleDownUnneededTime, &out.ScaleDownUnneededTime
+		*out = new(string)
+		**out = **in
+	}
+	if in.ScaleDownUnreadyTime != nil {
+		in, out := &in.ScaleDownUnreadyTime, &out.ScaleDownUnreadyTime
+		*out = new(string)
+		**out = **in
+	}
+	if in.ScaleDownUtilizationThreshold != nil {
+		in, out := &in.ScaleDownUtilizationThreshold, &out.ScaleDownUtilizationThreshold
+		*out = new(string)
+		**out = **in
+	}
+	if in.SkipNodesWithLocalStorage != nil {
+		in, out := &in.SkipNodesWithLocalStorage, &out.SkipNodesWithLocalStorage
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SkipNodesWithSystemPods != nil {
+		in, out := &in.SkipNodesWithSystemPods, &out.SkipNodesWithSystemPods
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoScalerProfile.
+func (in *AutoScalerProfile) DeepCopy() *AutoScalerProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoScalerProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoUpgradeProfile) DeepCopyInto(out *AutoUpgradeProfile) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoUpgradeProfile.
+func (in *AutoUpgradeProfile) DeepCopy() *AutoUpgradeProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoUpgradeProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureMachinePool) DeepCopyInto(out *AzureMachinePool) {
 	*out = *in
@@ -113,6 +250,34 @@ func (in *AzureMachinePool) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMachinePoolBlueGreenStatus) DeepCopyInto(out *AzureMachinePoolBlueGreenStatus) {
+	*out = *in
+	if in.ReplacementStartTime != nil {
+		in, out := &in.ReplacementStartTime, &out.ReplacementStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ReplacementReadyTime != nil {
+		in, out := &in.ReplacementReadyTime, &out.ReplacementReadyTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RetiredScaleSets != nil {
+		in, out := &in.RetiredScaleSets, &out.RetiredScaleSets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolBlueGreenStatus.
+func (in *AzureMachinePoolBlueGreenStatus) DeepCopy() *AzureMachinePoolBlueGreenStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMachinePoolBlueGreenStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureMachinePoolDeploymentStrategy) DeepCopyInto(out *AzureMachinePoolDeploymentStrategy) {
 	*out = *in
@@ -121,6 +286,11 @@ func (in *AzureMachinePoolDeploymentStrategy) DeepCopyInto(out *AzureMachinePool
 		*out = new(MachineRollingUpdateDeployment)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.BlueGreen != nil {
+		in, out := &in.BlueGreen, &out.BlueGreen
+		*out = new(MachineBlueGreenDeployment)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolDeploymentStrategy.
@@ -190,7 +360,7 @@ func (in *AzureMachinePoolMachine) DeepCopyInto(out *AzureMachinePoolMachine) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -247,6 +417,11 @@ func (in *AzureMachinePoolMachineList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureMachinePoolMachineSpec) DeepCopyInto(out *AzureMachinePoolMachineSpec) {
 	*out = *in
+	if in.ProtectionPolicy != nil {
+		in, out := &in.ProtectionPolicy, &out.ProtectionPolicy
+		*out = new(InstanceProtectionPolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolMachineSpec.
@@ -289,11 +464,26 @@ func (in *AzureMachinePoolMachineStatus) DeepCopyInto(out *AzureMachinePoolMachi
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.NodeDiagnosticsRef != nil {
+		in, out := &in.NodeDiagnosticsRef, &out.NodeDiagnosticsRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
 	if in.LongRunningOperationStates != nil {
 		in, out := &in.LongRunningOperationStates, &out.LongRunningOperationStates
 		*out = make(apiv1beta1.Futures, len(*in))
 		copy(*out, *in)
 	}
+	if in.ProtectionPolicy != nil {
+		in, out := &in.ProtectionPolicy, &out.ProtectionPolicy
+		*out = new(InstanceProtectionPolicy)
+		**out = **in
+	}
+	if in.ModelDifferences != nil {
+		in, out := &in.ModelDifferences, &out.ModelDifferences
+		*out = make([]apiv1beta1.ModelDifference, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolMachineStatus.
@@ -342,6 +532,28 @@ func (in *AzureMachinePoolMachineTemplate) DeepCopyInto(out *AzureMachinePoolMac
 		*out = new(apiv1beta1.SpotVMOptions)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.VMExtensions != nil {
+		in, out := &in.VMExtensions, &out.VMExtensions
+		*out = make([]apiv1beta1.VMExtension, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeDiagnostics != nil {
+		in, out := &in.NodeDiagnostics, &out.NodeDiagnostics
+		*out = new(apiv1beta1.NodeDiagnostics)
+		**out = **in
+	}
+	if in.Diagnostics != nil {
+		in, out := &in.Diagnostics, &out.Diagnostics
+		*out = new(apiv1beta1.Diagnostics)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ApplicationHealth != nil {
+		in, out := &in.ApplicationHealth, &out.ApplicationHealth
+		*out = new(apiv1beta1.ApplicationHealthProbe)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolMachineTemplate.
@@ -354,6 +566,25 @@ func (in *AzureMachinePoolMachineTemplate) DeepCopy() *AzureMachinePoolMachineTe
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureMachinePoolScaleSetStatus) DeepCopyInto(out *AzureMachinePoolScaleSetStatus) {
+	*out = *in
+	if in.UnavailableSince != nil {
+		in, out := &in.UnavailableSince, &out.UnavailableSince
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolScaleSetStatus.
+func (in *AzureMachinePoolScaleSetStatus) DeepCopy() *AzureMachinePoolScaleSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureMachinePoolScaleSetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureMachinePoolSpec) DeepCopyInto(out *AzureMachinePoolSpec) {
 	*out = *in
@@ -381,6 +612,36 @@ func (in *AzureMachinePoolSpec) DeepCopyInto(out *AzureMachinePoolSpec) {
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.NodeDrainOptions != nil {
+		in, out := &in.NodeDrainOptions, &out.NodeDrainOptions
+		*out = new(NodeDrainOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PlatformFaultDomainCount != nil {
+		in, out := &in.PlatformFaultDomainCount, &out.PlatformFaultDomainCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.UpgradePolicy != nil {
+		in, out := &in.UpgradePolicy, &out.UpgradePolicy
+		*out = new(apiv1beta1.UpgradePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AutomaticRepairsPolicy != nil {
+		in, out := &in.AutomaticRepairsPolicy, &out.AutomaticRepairsPolicy
+		*out = new(apiv1beta1.AutomaticRepairsPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MixedInstancesPolicy != nil {
+		in, out := &in.MixedInstancesPolicy, &out.MixedInstancesPolicy
+		*out = new(MixedInstancesPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scaling != nil {
+		in, out := &in.Scaling, &out.Scaling
+		*out = new(MachinePoolScaling)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolSpec.
@@ -434,11 +695,28 @@ func (in *AzureMachinePoolStatus) DeepCopyInto(out *AzureMachinePoolStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.VMExtensions != nil {
+		in, out := &in.VMExtensions, &out.VMExtensions
+		*out = make([]apiv1beta1.VMExtensionStatus, len(*in))
+		copy(*out, *in)
+	}
 	if in.LongRunningOperationStates != nil {
 		in, out := &in.LongRunningOperationStates, &out.LongRunningOperationStates
 		*out = make(apiv1beta1.Futures, len(*in))
 		copy(*out, *in)
 	}
+	if in.ScaleSets != nil {
+		in, out := &in.ScaleSets, &out.ScaleSets
+		*out = make([]AzureMachinePoolScaleSetStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BlueGreen != nil {
+		in, out := &in.BlueGreen, &out.BlueGreen
+		*out = new(AzureMachinePoolBlueGreenStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureMachinePoolStatus.
@@ -603,7 +881,7 @@ func (in *AzureManagedControlPlaneList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureManagedControlPlaneSpec) DeepCopyInto(out *AzureManagedControlPlaneSpec) {
 	*out = *in
-	out.VirtualNetwork = in.VirtualNetwork
+	in.VirtualNetwork.DeepCopyInto(&out.VirtualNetwork)
 	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
 	if in.AdditionalTags != nil {
 		in, out := &in.AdditionalTags, &out.AdditionalTags
@@ -622,6 +900,16 @@ func (in *AzureManagedControlPlaneSpec) DeepCopyInto(out *AzureManagedControlPla
 		*out = new(string)
 		**out = **in
 	}
+	if in.PodCIDR != nil {
+		in, out := &in.PodCIDR, &out.PodCIDR
+		*out = new(string)
+		**out = **in
+	}
+	if in.ServiceCIDR != nil {
+		in, out := &in.ServiceCIDR, &out.ServiceCIDR
+		*out = new(string)
+		**out = **in
+	}
 	if in.DNSServiceIP != nil {
 		in, out := &in.DNSServiceIP, &out.DNSServiceIP
 		*out = new(string)
@@ -632,11 +920,21 @@ func (in *AzureManagedControlPlaneSpec) DeepCopyInto(out *AzureManagedControlPla
 		*out = new(string)
 		**out = **in
 	}
+	if in.OutboundType != nil {
+		in, out := &in.OutboundType, &out.OutboundType
+		*out = new(ManagedControlPlaneOutboundType)
+		**out = **in
+	}
 	if in.IdentityRef != nil {
 		in, out := &in.IdentityRef, &out.IdentityRef
 		*out = new(corev1.ObjectReference)
 		**out = **in
 	}
+	if in.Identity != nil {
+		in, out := &in.Identity, &out.Identity
+		*out = new(ManagedControlPlaneIdentity)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.AADProfile != nil {
 		in, out := &in.AADProfile, &out.AADProfile
 		*out = new(AADProfile)
@@ -657,6 +955,48 @@ func (in *AzureManagedControlPlaneSpec) DeepCopyInto(out *AzureManagedControlPla
 		*out = new(APIServerAccessProfile)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.WindowsProfile != nil {
+		in, out := &in.WindowsProfile, &out.WindowsProfile
+		*out = new(ManagedControlPlaneWindowsProfile)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AddonProfiles != nil {
+		in, out := &in.AddonProfiles, &out.AddonProfiles
+		*out = make([]AddonProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EnableAzurePolicy != nil {
+		in, out := &in.EnableAzurePolicy, &out.EnableAzurePolicy
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableHTTPApplicationRouting != nil {
+		in, out := &in.EnableHTTPApplicationRouting, &out.EnableHTTPApplicationRouting
+		*out = new(bool)
+		**out = **in
+	}
+	if in.KeyVaultSecretsProvider != nil {
+		in, out := &in.KeyVaultSecretsProvider, &out.KeyVaultSecretsProvider
+		*out = new(KeyVaultSecretsProviderProfile)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(MonitoringProfile)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AutoScalerProfile != nil {
+		in, out := &in.AutoScalerProfile, &out.AutoScalerProfile
+		*out = new(AutoScalerProfile)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AutoUpgradeProfile != nil {
+		in, out := &in.AutoUpgradeProfile, &out.AutoUpgradeProfile
+		*out = new(AutoUpgradeProfile)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureManagedControlPlaneSpec.
@@ -780,6 +1120,11 @@ func (in *AzureManagedMachinePoolSpec) DeepCopyInto(out *AzureManagedMachinePool
 			(*out)[key] = val
 		}
 	}
+	if in.Taints != nil {
+		in, out := &in.Taints, &out.Taints
+		*out = make(Taints, len(*in))
+		copy(*out, *in)
+	}
 	if in.ProviderIDList != nil {
 		in, out := &in.ProviderIDList, &out.ProviderIDList
 		*out = make([]string, len(*in))
@@ -800,6 +1145,41 @@ func (in *AzureManagedMachinePoolSpec) DeepCopyInto(out *AzureManagedMachinePool
 		*out = new(string)
 		**out = **in
 	}
+	if in.OSType != nil {
+		in, out := &in.OSType, &out.OSType
+		*out = new(string)
+		**out = **in
+	}
+	if in.KubeletConfig != nil {
+		in, out := &in.KubeletConfig, &out.KubeletConfig
+		*out = new(KubeletConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LinuxOSConfig != nil {
+		in, out := &in.LinuxOSConfig, &out.LinuxOSConfig
+		*out = new(LinuxOSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SpotVMOptions != nil {
+		in, out := &in.SpotVMOptions, &out.SpotVMOptions
+		*out = new(apiv1beta1.SpotVMOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SubnetName != nil {
+		in, out := &in.SubnetName, &out.SubnetName
+		*out = new(string)
+		**out = **in
+	}
+	if in.PodSubnetName != nil {
+		in, out := &in.PodSubnetName, &out.PodSubnetName
+		*out = new(string)
+		**out = **in
+	}
+	if in.UpgradeSettings != nil {
+		in, out := &in.UpgradeSettings, &out.UpgradeSettings
+		*out = new(ManagedMachinePoolUpgradeSettings)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureManagedMachinePoolSpec.
@@ -815,6 +1195,11 @@ func (in *AzureManagedMachinePoolSpec) DeepCopy() *AzureManagedMachinePoolSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureManagedMachinePoolStatus) DeepCopyInto(out *AzureManagedMachinePoolStatus) {
 	*out = *in
+	if in.Taints != nil {
+		in, out := &in.Taints, &out.Taints
+		*out = make(Taints, len(*in))
+		copy(*out, *in)
+	}
 	if in.ErrorReason != nil {
 		in, out := &in.ErrorReason, &out.ErrorReason
 		*out = new(errors.MachineStatusError)
@@ -850,66 +1235,290 @@ func (in *AzureManagedMachinePoolStatus) DeepCopy() *AzureManagedMachinePoolStat
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LoadBalancerProfile) DeepCopyInto(out *LoadBalancerProfile) {
+func (in *InstanceProtectionPolicy) DeepCopyInto(out *InstanceProtectionPolicy) {
 	*out = *in
-	if in.ManagedOutboundIPs != nil {
-		in, out := &in.ManagedOutboundIPs, &out.ManagedOutboundIPs
-		*out = new(int32)
-		**out = **in
-	}
-	if in.OutboundIPPrefixes != nil {
-		in, out := &in.OutboundIPPrefixes, &out.OutboundIPPrefixes
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.OutboundIPs != nil {
-		in, out := &in.OutboundIPs, &out.OutboundIPs
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.AllocatedOutboundPorts != nil {
-		in, out := &in.AllocatedOutboundPorts, &out.AllocatedOutboundPorts
-		*out = new(int32)
-		**out = **in
-	}
-	if in.IdleTimeoutInMinutes != nil {
-		in, out := &in.IdleTimeoutInMinutes, &out.IdleTimeoutInMinutes
-		*out = new(int32)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerProfile.
-func (in *LoadBalancerProfile) DeepCopy() *LoadBalancerProfile {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceProtectionPolicy.
+func (in *InstanceProtectionPolicy) DeepCopy() *InstanceProtectionPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(LoadBalancerProfile)
+	out := new(InstanceProtectionPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MachineRollingUpdateDeployment) DeepCopyInto(out *MachineRollingUpdateDeployment) {
+func (in *KeyVaultSecretsProviderProfile) DeepCopyInto(out *KeyVaultSecretsProviderProfile) {
 	*out = *in
-	if in.MaxUnavailable != nil {
-		in, out := &in.MaxUnavailable, &out.MaxUnavailable
-		*out = new(intstr.IntOrString)
+	if in.EnableSecretRotation != nil {
+		in, out := &in.EnableSecretRotation, &out.EnableSecretRotation
+		*out = new(bool)
 		**out = **in
 	}
-	if in.MaxSurge != nil {
-		in, out := &in.MaxSurge, &out.MaxSurge
-		*out = new(intstr.IntOrString)
+	if in.RotationPollInterval != nil {
+		in, out := &in.RotationPollInterval, &out.RotationPollInterval
+		*out = new(string)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineRollingUpdateDeployment.
-func (in *MachineRollingUpdateDeployment) DeepCopy() *MachineRollingUpdateDeployment {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyVaultSecretsProviderProfile.
+func (in *KeyVaultSecretsProviderProfile) DeepCopy() *KeyVaultSecretsProviderProfile {
 	if in == nil {
 		return nil
 	}
-	out := new(MachineRollingUpdateDeployment)
+	out := new(KeyVaultSecretsProviderProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeletConfig) DeepCopyInto(out *KubeletConfig) {
+	*out = *in
+	if in.CPUManagerPolicy != nil {
+		in, out := &in.CPUManagerPolicy, &out.CPUManagerPolicy
+		*out = new(string)
+		**out = **in
+	}
+	if in.CPUCfsQuota != nil {
+		in, out := &in.CPUCfsQuota, &out.CPUCfsQuota
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CPUCfsQuotaPeriod != nil {
+		in, out := &in.CPUCfsQuotaPeriod, &out.CPUCfsQuotaPeriod
+		*out = new(string)
+		**out = **in
+	}
+	if in.ImageGcHighThreshold != nil {
+		in, out := &in.ImageGcHighThreshold, &out.ImageGcHighThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ImageGcLowThreshold != nil {
+		in, out := &in.ImageGcLowThreshold, &out.ImageGcLowThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TopologyManagerPolicy != nil {
+		in, out := &in.TopologyManagerPolicy, &out.TopologyManagerPolicy
+		*out = new(string)
+		**out = **in
+	}
+	if in.AllowedUnsafeSysctls != nil {
+		in, out := &in.AllowedUnsafeSysctls, &out.AllowedUnsafeSysctls
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FailSwapOn != nil {
+		in, out := &in.FailSwapOn, &out.FailSwapOn
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ContainerLogMaxSizeMB != nil {
+		in, out := &in.ContainerLogMaxSizeMB, &out.ContainerLogMaxSizeMB
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ContainerLogMaxFiles != nil {
+		in, out := &in.ContainerLogMaxFiles, &out.ContainerLogMaxFiles
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PodMaxPids != nil {
+		in, out := &in.PodMaxPids, &out.PodMaxPids
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeletConfig.
+func (in *KubeletConfig) DeepCopy() *KubeletConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeletConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LinuxOSConfig) DeepCopyInto(out *LinuxOSConfig) {
+	*out = *in
+	if in.SwapFileSizeMB != nil {
+		in, out := &in.SwapFileSizeMB, &out.SwapFileSizeMB
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Sysctls != nil {
+		in, out := &in.Sysctls, &out.Sysctls
+		*out = new(SysctlConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TransparentHugePageDefrag != nil {
+		in, out := &in.TransparentHugePageDefrag, &out.TransparentHugePageDefrag
+		*out = new(string)
+		**out = **in
+	}
+	if in.TransparentHugePageEnabled != nil {
+		in, out := &in.TransparentHugePageEnabled, &out.TransparentHugePageEnabled
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LinuxOSConfig.
+func (in *LinuxOSConfig) DeepCopy() *LinuxOSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LinuxOSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadBalancerProfile) DeepCopyInto(out *LoadBalancerProfile) {
+	*out = *in
+	if in.ManagedOutboundIPs != nil {
+		in, out := &in.ManagedOutboundIPs, &out.ManagedOutboundIPs
+		*out = new(int32)
+		**out = **in
+	}
+	if in.OutboundIPPrefixes != nil {
+		in, out := &in.OutboundIPPrefixes, &out.OutboundIPPrefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OutboundIPs != nil {
+		in, out := &in.OutboundIPs, &out.OutboundIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllocatedOutboundPorts != nil {
+		in, out := &in.AllocatedOutboundPorts, &out.AllocatedOutboundPorts
+		*out = new(int32)
+		**out = **in
+	}
+	if in.IdleTimeoutInMinutes != nil {
+		in, out := &in.IdleTimeoutInMinutes, &out.IdleTimeoutInMinutes
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadBalancerProfile.
+func (in *LoadBalancerProfile) DeepCopy() *LoadBalancerProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadBalancerProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineBlueGreenDeployment) DeepCopyInto(out *MachineBlueGreenDeployment) {
+	*out = *in
+	if in.SoakDuration != nil {
+		in, out := &in.SoakDuration, &out.SoakDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ReadinessTimeout != nil {
+		in, out := &in.ReadinessTimeout, &out.ReadinessTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineBlueGreenDeployment.
+func (in *MachineBlueGreenDeployment) DeepCopy() *MachineBlueGreenDeployment {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineBlueGreenDeployment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachinePoolScaling) DeepCopyInto(out *MachinePoolScaling) {
+	*out = *in
+	if in.NodeLabels != nil {
+		in, out := &in.NodeLabels, &out.NodeLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodeTaints != nil {
+		in, out := &in.NodeTaints, &out.NodeTaints
+		*out = make([]corev1.Taint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachinePoolScaling.
+func (in *MachinePoolScaling) DeepCopy() *MachinePoolScaling {
+	if in == nil {
+		return nil
+	}
+	out := new(MachinePoolScaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineRollingUpdateDeployment) DeepCopyInto(out *MachineRollingUpdateDeployment) {
+	*out = *in
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxSurge != nil {
+		in, out := &in.MaxSurge, &out.MaxSurge
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineRollingUpdateDeployment.
+func (in *MachineRollingUpdateDeployment) DeepCopy() *MachineRollingUpdateDeployment {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineRollingUpdateDeployment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedControlPlaneIdentity) DeepCopyInto(out *ManagedControlPlaneIdentity) {
+	*out = *in
+	if in.ContainerRegistryResourceIDs != nil {
+		in, out := &in.ContainerRegistryResourceIDs, &out.ContainerRegistryResourceIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedControlPlaneIdentity.
+func (in *ManagedControlPlaneIdentity) DeepCopy() *ManagedControlPlaneIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedControlPlaneIdentity)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -933,6 +1542,11 @@ func (in *ManagedControlPlaneSubnet) DeepCopy() *ManagedControlPlaneSubnet {
 func (in *ManagedControlPlaneVirtualNetwork) DeepCopyInto(out *ManagedControlPlaneVirtualNetwork) {
 	*out = *in
 	out.Subnet = in.Subnet
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]ManagedControlPlaneSubnet, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedControlPlaneVirtualNetwork.
@@ -945,6 +1559,22 @@ func (in *ManagedControlPlaneVirtualNetwork) DeepCopy() *ManagedControlPlaneVirt
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedControlPlaneWindowsProfile) DeepCopyInto(out *ManagedControlPlaneWindowsProfile) {
+	*out = *in
+	in.AdminPasswordSecretRef.DeepCopyInto(&out.AdminPasswordSecretRef)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedControlPlaneWindowsProfile.
+func (in *ManagedControlPlaneWindowsProfile) DeepCopy() *ManagedControlPlaneWindowsProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedControlPlaneWindowsProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagedMachinePoolScaling) DeepCopyInto(out *ManagedMachinePoolScaling) {
 	*out = *in
@@ -970,6 +1600,111 @@ func (in *ManagedMachinePoolScaling) DeepCopy() *ManagedMachinePoolScaling {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedMachinePoolUpgradeSettings) DeepCopyInto(out *ManagedMachinePoolUpgradeSettings) {
+	*out = *in
+	if in.MaxSurge != nil {
+		in, out := &in.MaxSurge, &out.MaxSurge
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedMachinePoolUpgradeSettings.
+func (in *ManagedMachinePoolUpgradeSettings) DeepCopy() *ManagedMachinePoolUpgradeSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedMachinePoolUpgradeSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MixedInstancesPolicy) DeepCopyInto(out *MixedInstancesPolicy) {
+	*out = *in
+	if in.FallbackVMSizes != nil {
+		in, out := &in.FallbackVMSizes, &out.FallbackVMSizes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OnDemandBaseCapacity != nil {
+		in, out := &in.OnDemandBaseCapacity, &out.OnDemandBaseCapacity
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SpotPercentageAboveBaseCapacity != nil {
+		in, out := &in.SpotPercentageAboveBaseCapacity, &out.SpotPercentageAboveBaseCapacity
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MixedInstancesPolicy.
+func (in *MixedInstancesPolicy) DeepCopy() *MixedInstancesPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(MixedInstancesPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringProfile) DeepCopyInto(out *MonitoringProfile) {
+	*out = *in
+	if in.LogAnalyticsWorkspaceResourceID != nil {
+		in, out := &in.LogAnalyticsWorkspaceResourceID, &out.LogAnalyticsWorkspaceResourceID
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringProfile.
+func (in *MonitoringProfile) DeepCopy() *MonitoringProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeDrainOptions) DeepCopyInto(out *NodeDrainOptions) {
+	*out = *in
+	if in.SkipWaitForDeleteTimeout != nil {
+		in, out := &in.SkipWaitForDeleteTimeout, &out.SkipWaitForDeleteTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.DeleteEmptyDirData != nil {
+		in, out := &in.DeleteEmptyDirData, &out.DeleteEmptyDirData
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IgnoreDaemonSets != nil {
+		in, out := &in.IgnoreDaemonSets, &out.IgnoreDaemonSets
+		*out = new(bool)
+		**out = **in
+	}
+	if in.GracePeriod != nil {
+		in, out := &in.GracePeriod, &out.GracePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeDrainOptions.
+func (in *NodeDrainOptions) DeepCopy() *NodeDrainOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeDrainOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SKU) DeepCopyInto(out *SKU) {
 	*out = *in
@@ -984,3 +1719,192 @@ func (in *SKU) DeepCopy() *SKU {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SysctlConfig) DeepCopyInto(out *SysctlConfig) {
+	*out = *in
+	if in.NetCoreSomaxconn != nil {
+		in, out := &in.NetCoreSomaxconn, &out.NetCoreSomaxconn
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NetCoreNetdevMaxBacklog != nil {
+		in, out := &in.NetCoreNetdevMaxBacklog, &out.NetCoreNetdevMaxBacklog
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NetCoreRmemDefault != nil {
+		in, out := &in.NetCoreRmemDefault, &out.NetCoreRmemDefault
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NetCoreRmemMax != nil {
+		in, out := &in.NetCoreRmemMax, &out.NetCoreRmemMax
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NetCoreWmemDefault != nil {
+		in, out := &in.NetCoreWmemDefault, &out.NetCoreWmemDefault
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NetCoreWmemMax != nil {
+		in, out := &in.NetCoreWmemMax, &out.NetCoreWmemMax
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NetCoreOptmemMax != nil {
+		in, out := &in.NetCoreOptmemMax, &out.NetCoreOptmemMax
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NetIpv4TCPMaxSynBacklog != nil {
+		in, out := &in.NetIpv4TCPMaxSynBacklog, &out.NetIpv4TCPMaxSynBacklog
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NetIpv4TCPMaxTwBuckets != nil {
+		in, out := &in.NetIpv4TCPMaxTwBuckets, &out.NetIpv4TCPMaxTwBuckets
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NetIpv4TCPFinTimeout != nil {
+		in, out := &in.NetIpv4TCPFinTimeout, &out.NetIpv4TCPFinTimeout
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NetIpv4TCPKeepaliveTime != nil {
+		in, out := &in.NetIpv4TCPKeepaliveTime, &out.NetIpv4TCPKeepaliveTime
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NetIpv4TCPKeepaliveProbes != nil {
+		in, out := &in.NetIpv4TCPKeepaliveProbes, &out.NetIpv4TCPKeepaliveProbes
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NetIpv4TCPKeepaliveIntvl != nil {
+		in, out := &in.NetIpv4TCPKeepaliveIntvl, &out.NetIpv4TCPKeepaliveIntvl
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NetIpv4TCPTwReuse != nil {
+		in, out := &in.NetIpv4TCPTwReuse, &out.NetIpv4TCPTwReuse
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NetIpv4IPLocalPortRange != nil {
+		in, out := &in.NetIpv4IPLocalPortRange, &out.NetIpv4IPLocalPortRange
+		*out = new(string)
+		**out = **in
+	}
+	if in.NetIpv4NeighDefaultGcThresh1 != nil {
+		in, out := &in.NetIpv4NeighDefaultGcThresh1, &out.NetIpv4NeighDefaultGcThresh1
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NetIpv4NeighDefaultGcThresh2 != nil {
+		in, out := &in.NetIpv4NeighDefaultGcThresh2, &out.NetIpv4NeighDefaultGcThresh2
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NetIpv4NeighDefaultGcThresh3 != nil {
+		in, out := &in.NetIpv4NeighDefaultGcThresh3, &out.NetIpv4NeighDefaultGcThresh3
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NetNetfilterNfConntrackMax != nil {
+		in, out := &in.NetNetfilterNfConntrackMax, &out.NetNetfilterNfConntrackMax
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NetNetfilterNfConntrackBuckets != nil {
+		in, out := &in.NetNetfilterNfConntrackBuckets, &out.NetNetfilterNfConntrackBuckets
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FsInotifyMaxUserWatches != nil {
+		in, out := &in.FsInotifyMaxUserWatches, &out.FsInotifyMaxUserWatches
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FsFileMax != nil {
+		in, out := &in.FsFileMax, &out.FsFileMax
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FsAioMaxNr != nil {
+		in, out := &in.FsAioMaxNr, &out.FsAioMaxNr
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FsNrOpen != nil {
+		in, out := &in.FsNrOpen, &out.FsNrOpen
+		*out = new(int32)
+		**out = **in
+	}
+	if in.KernelThreadsMax != nil {
+		in, out := &in.KernelThreadsMax, &out.KernelThreadsMax
+		*out = new(int32)
+		**out = **in
+	}
+	if in.VMMaxMapCount != nil {
+		in, out := &in.VMMaxMapCount, &out.VMMaxMapCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.VMSwappiness != nil {
+		in, out := &in.VMSwappiness, &out.VMSwappiness
+		*out = new(int32)
+		**out = **in
+	}
+	if in.VMVfsCachePressure != nil {
+		in, out := &in.VMVfsCachePressure, &out.VMVfsCachePressure
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SysctlConfig.
+func (in *SysctlConfig) DeepCopy() *SysctlConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SysctlConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Taint) DeepCopyInto(out *Taint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Taint.
+func (in *Taint) DeepCopy() *Taint {
+	if in == nil {
+		return nil
+	}
+	out := new(Taint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in Taints) DeepCopyInto(out *Taints) {
+	{
+		in := &in
+		*out = make(Taints, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Taints.
+func (in Taints) DeepCopy() Taints {
+	if in == nil {
+		return nil
+	}
+	out := new(Taints)
+	in.DeepCopyInto(out)
+	return *out
+}
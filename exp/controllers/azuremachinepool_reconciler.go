@@ -69,6 +69,10 @@ func (s *azureMachinePoolService) Reconcile(ctx context.Context) error {
 		return errors.Wrap(err, "failed to create scale set")
 	}
 
+	if err := s.scope.ReconcileAutoscalerAnnotations(ctx, s.skuCache); err != nil {
+		return errors.Wrap(err, "failed to reconcile cluster autoscaler annotations")
+	}
+
 	if err := s.roleAssignmentsSvc.Reconcile(ctx); err != nil {
 		return errors.Wrap(err, "unable to create role assignment")
 	}
@@ -35,14 +35,19 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/pkg/coalescing"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	"sigs.k8s.io/cluster-api-provider-azure/util/tele"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/remote"
 	capierrors "sigs.k8s.io/cluster-api/errors"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
@@ -53,11 +58,15 @@ type (
 	// AzureMachinePoolMachineController handles Kubernetes change events for AzureMachinePoolMachine resources.
 	AzureMachinePoolMachineController struct {
 		client.Client
-		Scheme            *runtime.Scheme
-		Recorder          record.EventRecorder
-		ReconcileTimeout  time.Duration
-		WatchFilterValue  string
+		Scheme           *runtime.Scheme
+		Recorder         record.EventRecorder
+		ReconcileTimeout time.Duration
+		WatchFilterValue string
+		// Tracker watches the Nodes of the workload clusters, so that scheduled events reported on a Node are handled
+		// without waiting for the next resync. Nodes are not watched if it is nil.
+		Tracker           *remote.ClusterCacheTracker
 		reconcilerFactory azureMachinePoolMachineReconcilerFactory
+		controller        controller.Controller
 	}
 
 	azureMachinePoolMachineReconciler struct {
@@ -67,12 +76,13 @@ type (
 )
 
 // NewAzureMachinePoolMachineController creates a new AzureMachinePoolMachineController to handle updates to Azure Machine Pool Machines.
-func NewAzureMachinePoolMachineController(c client.Client, recorder record.EventRecorder, reconcileTimeout time.Duration, watchFilterValue string) *AzureMachinePoolMachineController {
+func NewAzureMachinePoolMachineController(c client.Client, recorder record.EventRecorder, reconcileTimeout time.Duration, watchFilterValue string, tracker *remote.ClusterCacheTracker) *AzureMachinePoolMachineController {
 	return &AzureMachinePoolMachineController{
 		Client:            c,
 		Recorder:          recorder,
 		ReconcileTimeout:  reconcileTimeout,
 		WatchFilterValue:  watchFilterValue,
+		Tracker:           tracker,
 		reconcilerFactory: newAzureMachinePoolMachineReconciler,
 	}
 }
@@ -109,16 +119,34 @@ func (ampmr *AzureMachinePoolMachineController) SetupWithManager(ctx context.Con
 		return errors.Wrapf(err, "failed adding a watch for AzureMachinePool model changes")
 	}
 
+	ampmr.controller = c
 	return nil
 }
 
+// watchClusterNodes watches the Nodes of the workload cluster for changes to their scheduled events.
+func (ampmr *AzureMachinePoolMachineController) watchClusterNodes(ctx context.Context, cluster *clusterv1.Cluster) error {
+	if ampmr.Tracker == nil || ampmr.controller == nil {
+		return nil
+	}
+
+	log := ctrl.LoggerFrom(ctx)
+	return ampmr.Tracker.Watch(ctx, remote.WatchInput{
+		Name:         "azuremachinepoolmachine-watchNodes",
+		Cluster:      util.ObjectKey(cluster),
+		Watcher:      ampmr.controller,
+		Kind:         &corev1.Node{},
+		EventHandler: handler.EnqueueRequestsFromMapFunc(NodeToAzureMachinePoolMachines(ctx, ampmr.Client, util.ObjectKey(cluster), log)),
+		Predicates:   []predicate.Predicate{NodeScheduledEventsHaveChanged(log)},
+	})
+}
+
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azuremachinepools,verbs=get;list;watch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azuremachinepools/status,verbs=get
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azuremachinepoolmachines,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=azuremachinepoolmachines/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinepools;machinepools/status,verbs=get
 // +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
-// +kubebuilder:rbac:groups="",resources=secrets;,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets;,verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
 
 // Reconcile idempotently gets, creates, and updates a machine pool.
@@ -215,6 +243,7 @@ func (ampmr *AzureMachinePoolMachineController) Reconcile(ctx context.Context, r
 		AzureMachinePool:        azureMachinePool,
 		AzureMachinePoolMachine: machine,
 		ClusterScope:            clusterScope,
+		Recorder:                ampmr.Recorder,
 	})
 	if err != nil {
 		return reconcile.Result{}, errors.Errorf("failed to create scope: %+v", err)
@@ -237,6 +266,12 @@ func (ampmr *AzureMachinePoolMachineController) Reconcile(ctx context.Context, r
 		return reconcile.Result{}, nil
 	}
 
+	if conditions.IsTrue(cluster, clusterv1.ControlPlaneInitializedCondition) {
+		if err := ampmr.watchClusterNodes(ctx, cluster); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to watch the nodes of the workload cluster")
+		}
+	}
+
 	// Handle non-deleted machine pools
 	return ampmr.reconcileNormal(ctx, machineScope)
 }
@@ -273,10 +308,16 @@ func (ampmr *AzureMachinePoolMachineController) reconcileNormal(ctx context.Cont
 		return reconcile.Result{}, err
 	}
 
+	diagnosticsResult := infracontroller.CollectNodeDiagnostics(ctx, ampmr.Recorder, machineScope.AzureMachinePoolMachine, machineScope)
+
 	state := machineScope.ProvisioningState()
 	switch state {
 	case infrav1.Failed:
 		ampmr.Recorder.Eventf(machineScope.AzureMachinePoolMachine, corev1.EventTypeWarning, "FailedVMState", "Azure scale set VM is in failed state")
+		if machineScope.AzureMachinePool.Spec.Template.NodeDiagnostics == nil {
+			// node diagnostics already include the serial console log when enabled
+			infracontroller.RecordSerialConsoleLog(ctx, ampmr.Recorder, machineScope.AzureMachinePoolMachine, machineScope)
+		}
 		machineScope.SetFailureReason(capierrors.UpdateMachineError)
 		machineScope.SetFailureMessage(errors.Errorf("Azure VM state is %s", state))
 	case infrav1.Deleting:
@@ -284,9 +325,21 @@ func (ampmr *AzureMachinePoolMachineController) reconcileNormal(ctx context.Cont
 			return reconcile.Result{}, errors.Wrap(err, "machine pool machine failed to be deleted when deleting")
 		}
 	}
+	if state != infrav1.Failed {
+		// record the serial console log again if the VM fails again after recovering
+		conditions.Delete(machineScope.AzureMachinePoolMachine, infrav1.SerialConsoleLogRecordedCondition)
+	}
 
 	log.V(2).Info(fmt.Sprintf("Scale Set VM is %s", state), "id", machineScope.ProviderID())
 
+	if machineScope.HasPendingScheduledEvents() {
+		log.V(2).Info("Requeuing until the scheduled events are over", "id", machineScope.ProviderID())
+		// a Preempt event only gives 30 seconds of notice, check on the events often
+		return reconcile.Result{
+			RequeueAfter: 10 * time.Second,
+		}, nil
+	}
+
 	if !infrav1.IsTerminalProvisioningState(state) || !machineScope.IsReady() {
 		log.V(2).Info("Requeuing", "state", state, "ready", machineScope.IsReady())
 		// we are in a non-terminal state, retry in a bit
@@ -295,7 +348,8 @@ func (ampmr *AzureMachinePoolMachineController) reconcileNormal(ctx context.Cont
 		}, nil
 	}
 
-	return reconcile.Result{}, nil
+	// check on the node diagnostics command until it completes
+	return diagnosticsResult, nil
 }
 
 func (ampmr *AzureMachinePoolMachineController) reconcileDelete(ctx context.Context, machineScope *scope.MachinePoolMachineScope) (_ reconcile.Result, reterr error) {
@@ -359,6 +413,10 @@ func (r *azureMachinePoolMachineReconciler) Reconcile(ctx context.Context) error
 		return errors.Wrap(err, "failed to update vmss vm status")
 	}
 
+	if err := r.Scope.HandleScheduledEvents(ctx); err != nil {
+		return errors.Wrap(err, "failed to handle scheduled events")
+	}
+
 	return nil
 }
 
@@ -103,7 +103,7 @@ func TestAzureMachinePoolMachineReconciler_Reconcile(t *testing.T) {
 			defer mockCtrl.Finish()
 
 			c.Setup(cb, reconciler.EXPECT())
-			controller := NewAzureMachinePoolMachineController(cb.Build(), nil, 30*time.Second, "foo")
+			controller := NewAzureMachinePoolMachineController(cb.Build(), nil, 30*time.Second, "foo", nil)
 			controller.reconcilerFactory = func(_ *scope.MachinePoolMachineScope) azure.Reconciler {
 				return reconciler
 			}
@@ -24,6 +24,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/azure/scope"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/groups"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/managedclusters"
+	"sigs.k8s.io/cluster-api-provider-azure/azure/services/roleassignments"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/subnets"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/tags"
 	"sigs.k8s.io/cluster-api-provider-azure/azure/services/virtualnetworks"
@@ -37,10 +38,11 @@ import (
 type azureManagedControlPlaneService struct {
 	kubeclient         client.Client
 	scope              managedclusters.ManagedClusterScope
-	managedClustersSvc azure.Reconciler
+	managedClustersSvc *managedclusters.Service
 	groupsSvc          azure.Reconciler
 	vnetSvc            azure.Reconciler
 	subnetsSvc         azure.Reconciler
+	roleAssignmentsSvc azure.Reconciler
 	tagsSvc            azure.Reconciler
 }
 
@@ -53,6 +55,7 @@ func newAzureManagedControlPlaneReconciler(scope *scope.ManagedControlPlaneScope
 		groupsSvc:          groups.New(scope),
 		vnetSvc:            virtualnetworks.New(scope),
 		subnetsSvc:         subnets.New(scope),
+		roleAssignmentsSvc: roleassignments.New(scope),
 		tagsSvc:            tags.New(scope),
 	}
 }
@@ -62,6 +65,12 @@ func (r *azureManagedControlPlaneService) Reconcile(ctx context.Context) error {
 	ctx, _, done := tele.StartSpanWithLogger(ctx, "controllers.azureManagedControlPlaneService.Reconcile")
 	defer done()
 
+	// An existing managed cluster that was not created by CAPZ is only modified once its adoption is confirmed, and
+	// so are the resources it depends on.
+	if err := r.managedClustersSvc.ReconcileAdoption(ctx); err != nil {
+		return errors.Wrap(err, "failed to reconcile the adoption of the managed cluster")
+	}
+
 	if err := r.groupsSvc.Reconcile(ctx); err != nil {
 		return errors.Wrap(err, "failed to reconcile managed cluster resource group")
 	}
@@ -74,6 +83,11 @@ func (r *azureManagedControlPlaneService) Reconcile(ctx context.Context) error {
 		return errors.Wrap(err, "failed to reconcile subnet")
 	}
 
+	// The roles of user-assigned identities must be assigned before the managed cluster is created with them.
+	if err := r.roleAssignmentsSvc.Reconcile(ctx); err != nil {
+		return errors.Wrap(err, "failed to reconcile role assignments")
+	}
+
 	// Send to Azure for create/update.
 	if err := r.managedClustersSvc.Reconcile(ctx); err != nil {
 		return errors.Wrapf(err, "failed to reconcile managed cluster")
@@ -19,10 +19,12 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -32,6 +34,7 @@ import (
 	infrav1exp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api-provider-azure/util/reconciler"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/noderefutil"
 	clusterv1exp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -622,3 +625,85 @@ func MachinePoolMachineHasStateOrVersionChange(logger logr.Logger) predicate.Fun
 		GenericFunc: func(e event.GenericEvent) bool { return false },
 	}
 }
+
+// NodeToAzureMachinePoolMachines creates a mapping handler to transform the Nodes of a workload cluster into the
+// AzureMachinePoolMachines of the cluster running on them.
+func NodeToAzureMachinePoolMachines(ctx context.Context, c client.Client, cluster client.ObjectKey, log logr.Logger) handler.MapFunc {
+	return func(o client.Object) []reconcile.Request {
+		ctx, cancel := context.WithTimeout(ctx, reconciler.DefaultMappingTimeout)
+		defer cancel()
+
+		node, ok := o.(*corev1.Node)
+		if !ok {
+			log.Error(errors.Errorf("expected a Node but got a %T", o), "failed to get Node")
+			return nil
+		}
+		logWithValues := log.WithValues("Node", node.Name, "Cluster", cluster.Name, "Namespace", cluster.Namespace)
+
+		ampml := &infrav1exp.AzureMachinePoolMachineList{}
+		if err := c.List(ctx, ampml, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterLabelName: cluster.Name}); err != nil {
+			logWithValues.Error(err, "failed to list AzureMachinePoolMachines")
+			return nil
+		}
+
+		for _, m := range ampml.Items {
+			if (m.Status.NodeRef != nil && m.Status.NodeRef.Name == node.Name) ||
+				(node.Spec.ProviderID != "" && strings.EqualFold(m.Spec.ProviderID, node.Spec.ProviderID)) {
+				return []reconcile.Request{
+					{
+						NamespacedName: client.ObjectKey{
+							Namespace: m.Namespace,
+							Name:      m.Name,
+						},
+					},
+				}
+			}
+		}
+
+		return nil
+	}
+}
+
+// NodeScheduledEventsHaveChanged predicates any events based on changes to the scheduled events reported on a Node, or
+// to its readiness.
+func NodeScheduledEventsHaveChanged(logger logr.Logger) predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			log := logger.WithValues("predicate", "NodeScheduledEventsHaveChanged", "eventType", "update")
+
+			oldNode, ok := e.ObjectOld.(*corev1.Node)
+			if !ok {
+				log.V(4).Info("Expected Node", "type", e.ObjectOld.GetObjectKind().GroupVersionKind().String())
+				return false
+			}
+			log = log.WithValues("node", oldNode.Name)
+
+			newNode := e.ObjectNew.(*corev1.Node)
+
+			// if any of these are not equal, run the update
+			shouldUpdate := oldNode.Annotations[infrav1exp.ScheduledEventsAnnotation] != newNode.Annotations[infrav1exp.ScheduledEventsAnnotation] ||
+				!cmp.Equal(scheduledEventConditions(oldNode), scheduledEventConditions(newNode)) ||
+				noderefutil.IsNodeReady(oldNode) != noderefutil.IsNodeReady(newNode)
+
+			if shouldUpdate {
+				log.V(4).Info("node predicate", "shouldUpdate", shouldUpdate)
+			}
+			return shouldUpdate
+		},
+		CreateFunc:  func(e event.CreateEvent) bool { return true },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+	}
+}
+
+// scheduledEventConditions returns the status of the conditions reporting scheduled events on a Node, such as the
+// conditions set by the node problem detector.
+func scheduledEventConditions(node *corev1.Node) map[corev1.NodeConditionType]corev1.ConditionStatus {
+	statuses := make(map[corev1.NodeConditionType]corev1.ConditionStatus)
+	for _, condition := range node.Status.Conditions {
+		if strings.HasSuffix(string(condition.Type), "Scheduled") {
+			statuses[condition.Type] = condition.Status
+		}
+	}
+	return statuses
+}
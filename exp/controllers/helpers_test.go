@@ -136,6 +136,47 @@ func TestAzureManagedClusterToAzureManagedMachinePoolsMapper(t *testing.T) {
 	}))
 }
 
+func TestNodeToAzureMachinePoolMachines(t *testing.T) {
+	g := NewWithT(t)
+	scheme := newScheme(g)
+	ampm := func(name, providerID string) *infrav1exp.AzureMachinePoolMachine {
+		return &infrav1exp.AzureMachinePoolMachine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					clusterv1.ClusterLabelName: clusterName,
+				},
+			},
+			Spec: infrav1exp.AzureMachinePoolMachineSpec{
+				ProviderID: providerID,
+			},
+		}
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(
+		ampm("ampm-0", "azure:///subscriptions/123/resourceGroups/rg/providers/Microsoft.Compute/virtualMachineScaleSets/pool/virtualMachines/0"),
+		ampm("ampm-1", "azure:///subscriptions/123/resourceGroups/rg/providers/Microsoft.Compute/virtualMachineScaleSets/pool/virtualMachines/1"),
+	).Build()
+
+	mapper := NodeToAzureMachinePoolMachines(context.Background(), fakeClient, client.ObjectKey{Namespace: "default", Name: clusterName}, logr.Discard())
+	requests := mapper(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "pool000001",
+		},
+		Spec: corev1.NodeSpec{
+			ProviderID: "azure:///subscriptions/123/resourceGroups/RG/providers/Microsoft.Compute/virtualMachineScaleSets/pool/virtualMachines/1",
+		},
+	})
+	g.Expect(requests).To(Equal([]reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Name:      "ampm-1",
+				Namespace: "default",
+			},
+		},
+	}))
+}
+
 func TestAzureManagedControlPlaneToAzureManagedMachinePoolsMapper(t *testing.T) {
 	g := NewWithT(t)
 	scheme := newScheme(g)
@@ -0,0 +1,152 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// aks-adopt prints the Cluster API objects that bring an existing AKS cluster under CAPZ management.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-08-01/containerservice"
+	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2021-02-01/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-azure/exp/adoption"
+	"sigs.k8s.io/yaml"
+)
+
+func main() {
+	var (
+		subscriptionID    string
+		resourceGroup     string
+		name              string
+		options           adoption.ManagedClusterOptions
+		identityName      string
+		identityNamespace string
+	)
+
+	pflag.StringVar(&subscriptionID, "subscription-id", os.Getenv("AZURE_SUBSCRIPTION_ID"), "Subscription of the AKS cluster, defaults to $AZURE_SUBSCRIPTION_ID")
+	pflag.StringVar(&resourceGroup, "resource-group", "", "Resource group of the AKS cluster")
+	pflag.StringVar(&name, "name", "", "Name of the AKS cluster")
+	pflag.StringVar(&options.Namespace, "namespace", "default", "Namespace of the generated objects")
+	pflag.StringVar(&identityName, "identity-name", "", "Name of the AzureClusterIdentity of the AzureManagedControlPlane")
+	pflag.StringVar(&identityNamespace, "identity-namespace", "", "Namespace of the AzureClusterIdentity of the AzureManagedControlPlane")
+	pflag.BoolVar(&options.ConfirmAdoption, "confirm-adoption", false, "Let CAPZ take ownership of the AKS cluster as soon as it matches the generated objects")
+	pflag.Parse()
+
+	if subscriptionID == "" || resourceGroup == "" || name == "" {
+		fmt.Fprintln(os.Stderr, "--subscription-id, --resource-group and --name are required")
+		pflag.Usage()
+		os.Exit(1)
+	}
+
+	if identityName != "" {
+		options.IdentityRef = &corev1.ObjectReference{
+			APIVersion: infrav1.GroupVersion.String(),
+			Kind:       "AzureClusterIdentity",
+			Name:       identityName,
+			Namespace:  identityNamespace,
+		}
+	}
+
+	if err := run(context.Background(), subscriptionID, resourceGroup, name, options); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, subscriptionID, resourceGroup, name string, options adoption.ManagedClusterOptions) error {
+	authorizer, err := newAuthorizer()
+	if err != nil {
+		return errors.Wrap(err, "failed to create an Azure authorizer")
+	}
+
+	managedClusters := containerservice.NewManagedClustersClient(subscriptionID)
+	managedClusters.Authorizer = authorizer
+	managedCluster, err := managedClusters.Get(ctx, resourceGroup, name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get managed cluster %s in resource group %s", name, resourceGroup)
+	}
+
+	options.SubnetCIDRBlocks, err = subnetCIDRBlocks(ctx, authorizer, managedCluster)
+	if err != nil {
+		return err
+	}
+
+	objects, err := adoption.ManagedClusterObjects(managedCluster, options)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate the objects of the managed cluster")
+	}
+
+	for _, object := range objects {
+		out, err := yaml.Marshal(object)
+		if err != nil {
+			return errors.Wrapf(err, "failed to marshal %s", object.GetName())
+		}
+		fmt.Printf("---\n%s", out)
+	}
+	return nil
+}
+
+// subnetCIDRBlocks returns the address prefixes of the subnets of the agent pools of the managed cluster, keyed by
+// subnet ID.
+func subnetCIDRBlocks(ctx context.Context, authorizer autorest.Authorizer, mc containerservice.ManagedCluster) (map[string]string, error) {
+	cidrBlocks := map[string]string{}
+	if mc.ManagedClusterProperties == nil || mc.AgentPoolProfiles == nil {
+		return cidrBlocks, nil
+	}
+
+	for _, pool := range *mc.AgentPoolProfiles {
+		subnetID := to.String(pool.VnetSubnetID)
+		if _, ok := cidrBlocks[subnetID]; ok || subnetID == "" {
+			continue
+		}
+
+		// subscriptions/<id>/resourceGroups/<rg>/providers/Microsoft.Network/virtualNetworks/<vnet>/subnets/<subnet>
+		parts := strings.Split(strings.Trim(subnetID, "/"), "/")
+		if len(parts) != 10 {
+			return nil, errors.Errorf("invalid subnet ID %s", subnetID)
+		}
+		subnets := network.NewSubnetsClient(parts[1])
+		subnets.Authorizer = authorizer
+		subnet, err := subnets.Get(ctx, parts[3], parts[7], parts[9], "")
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get subnet %s", subnetID)
+		}
+		if subnet.SubnetPropertiesFormat == nil || subnet.AddressPrefix == nil {
+			return nil, errors.Errorf("subnet %s has no address prefix", subnetID)
+		}
+		cidrBlocks[subnetID] = *subnet.AddressPrefix
+	}
+	return cidrBlocks, nil
+}
+
+// newAuthorizer authenticates with the service principal of the AZURE_CLIENT_ID, AZURE_CLIENT_SECRET and
+// AZURE_TENANT_ID environment variables when they are set, and with the Azure CLI otherwise.
+func newAuthorizer() (autorest.Authorizer, error) {
+	if os.Getenv("AZURE_CLIENT_ID") != "" {
+		return auth.NewAuthorizerFromEnvironment()
+	}
+	return auth.NewAuthorizerFromCLI()
+}
@@ -52,6 +52,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-azure/version"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha4"
 	clusterv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/remote"
 	clusterv1exp "sigs.k8s.io/cluster-api/exp/api/v1alpha4"
 	clusterv1beta1exp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	capifeature "sigs.k8s.io/cluster-api/feature"
@@ -402,11 +403,30 @@ func registerControllers(ctx context.Context, mgr manager.Manager) {
 			setupLog.Error(err, "failed to build mpmCache ReconcileCache")
 		}
 
+		// the tracker watches the Nodes of the workload clusters for the AzureMachinePoolMachine controller
+		trackerLog := ctrl.Log.WithName("remote").WithName("ClusterCacheTracker")
+		tracker, err := remote.NewClusterCacheTracker(mgr, remote.ClusterCacheTrackerOptions{Log: &trackerLog})
+		if err != nil {
+			setupLog.Error(err, "unable to create cluster cache tracker")
+			os.Exit(1)
+		}
+
+		if err := (&remote.ClusterCacheReconciler{
+			Client:           mgr.GetClient(),
+			Log:              ctrl.Log.WithName("remote").WithName("ClusterCacheReconciler"),
+			Tracker:          tracker,
+			WatchFilterValue: watchFilterValue,
+		}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: azureClusterConcurrency}); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ClusterCacheReconciler")
+			os.Exit(1)
+		}
+
 		if err := infrav1controllersexp.NewAzureMachinePoolMachineController(
 			mgr.GetClient(),
 			mgr.GetEventRecorderFor("azuremachinepoolmachine-reconciler"),
 			reconcileTimeout,
 			watchFilterValue,
+			tracker,
 		).SetupWithManager(ctx, mgr, controllers.Options{Options: controller.Options{MaxConcurrentReconciles: azureMachinePoolMachineConcurrency}, Cache: mpmCache}); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "AzureMachinePoolMachine")
 			os.Exit(1)
@@ -498,10 +518,9 @@ func registerWebhooks(mgr manager.Manager) {
 			os.Exit(1)
 		}
 
-		if err := (&infrav1beta1exp.AzureMachinePoolMachine{}).SetupWebhookWithManager(mgr); err != nil {
-			setupLog.Error(err, "unable to create webhook", "webhook", "AzureMachinePoolMachine")
-			os.Exit(1)
-		}
+		mgr.GetWebhookServer().Register("/validate-infrastructure-cluster-x-k8s-io-v1beta1-azuremachinepoolmachine", webhook.NewValidatingWebhook(
+			&infrav1beta1exp.AzureMachinePoolMachine{}, mgr.GetClient(),
+		))
 	}
 
 	if feature.Gates.Enabled(feature.AKS) {